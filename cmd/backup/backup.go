@@ -0,0 +1,141 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/backup/targets"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates the backup parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manage BirdNET-Go backups",
+	}
+
+	backupCmd.AddCommand(RestoreCommand(settings))
+
+	return backupCmd
+}
+
+// RestoreCommand creates the backup restore subcommand
+func RestoreCommand(settings *conf.Settings) *cobra.Command {
+	opts := &backup.RestoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "restore [backup-id]",
+		Short: "Restore the database and configuration from a backup",
+		Long: `Restore the database and configuration from a backup archive.
+
+The archive is fetched from the configured backup targets, decrypted with the
+installed encryption key if needed, and verified before anything is written.
+Replaced files are kept next to the originals with a .pre-restore-<timestamp> suffix.
+Stop BirdNET-Go before restoring into the live locations.
+
+Examples:
+  # Check that a backup can be restored without writing anything
+  birdnet backup restore birdnet-20250102-030000 --dry-run
+
+  # Restore from a specific target
+  birdnet backup restore birdnet-20250102-030000 --target local
+
+  # Restore an archive copied by hand into a separate directory
+  birdnet backup restore --file ./birdnet-20250102-030000.tar.enc --output-dir ./restored`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.BackupID = args[0]
+			}
+			if opts.BackupID == "" && opts.ArchivePath == "" {
+				return fmt.Errorf("a backup ID or --file is required")
+			}
+
+			stateManager, err := backup.NewStateManager(nil)
+			if err != nil {
+				return fmt.Errorf("failed to initialize backup state: %w", err)
+			}
+			manager, err := backup.NewManager(settings, nil, stateManager, settings.Version)
+			if err != nil {
+				return fmt.Errorf("failed to initialize backup manager: %w", err)
+			}
+
+			// Targets are only needed when the archive is fetched by ID
+			if opts.ArchivePath == "" {
+				for _, err := range targets.RegisterConfiguredTargets(manager, &settings.Backup, nil) {
+					if _, werr := fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err); werr != nil {
+						return fmt.Errorf("failed to write output: %w", werr)
+					}
+				}
+			}
+
+			result, err := manager.Restore(context.Background(), opts)
+			if err != nil {
+				return fmt.Errorf("restore failed: %w", err)
+			}
+
+			return printResult(cmd.OutOrStdout(), result)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Target, "target", "", "Only fetch the backup from this target (e.g. local, sftp)")
+	cmd.Flags().StringVar(&opts.ArchivePath, "file", "", "Restore from a local archive file instead of a backup target")
+	cmd.Flags().StringVar(&opts.OutputDir, "output-dir", "", "Restore into this directory instead of the configured locations")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Verify the backup without writing anything")
+	cmd.Flags().BoolVar(&opts.SkipDatabase, "skip-database", false, "Do not restore the database")
+	cmd.Flags().BoolVar(&opts.SkipConfig, "skip-config", false, "Do not restore the configuration file")
+
+	return cmd
+}
+
+// printResult writes a human readable restore summary
+func printResult(w io.Writer, result *backup.RestoreResult) error {
+	lines := []string{
+		fmt.Sprintf("Backup:       %s (created %s, app version %s)", result.Metadata.ID, result.Metadata.Timestamp.Local().Format("2006-01-02 15:04:05"), result.Metadata.AppVersion),
+	}
+	if result.Target != "" {
+		lines = append(lines, fmt.Sprintf("Target:       %s", result.Target))
+	}
+	if result.ChecksumVerified {
+		lines = append(lines, "Checksum:     verified")
+	} else {
+		lines = append(lines, "Checksum:     not verified")
+	}
+
+	verb := "Restored"
+	if result.DryRun {
+		verb = "Would restore"
+	}
+	if result.DatabasePath != "" {
+		lines = append(lines, fmt.Sprintf("%s database to %s", verb, result.DatabasePath))
+	}
+	if result.PreviousDatabase != "" {
+		lines = append(lines, fmt.Sprintf("Previous database moved to %s", result.PreviousDatabase))
+	}
+	if result.ConfigPath != "" {
+		lines = append(lines, fmt.Sprintf("%s config to %s", verb, result.ConfigPath))
+	}
+	if result.PreviousConfig != "" {
+		lines = append(lines, fmt.Sprintf("Previous config moved to %s", result.PreviousConfig))
+	}
+	if result.ClipsPath != "" {
+		lines = append(lines, fmt.Sprintf("%s %d clips to %s (%d already present)", verb, result.ClipsRestored, result.ClipsPath, result.ClipsSkipped))
+	}
+	if result.ClipsManifest != "" {
+		lines = append(lines, fmt.Sprintf("Clips manifest written to %s", result.ClipsManifest))
+	}
+	for _, warning := range result.Warnings {
+		lines = append(lines, fmt.Sprintf("Warning: %s", warning))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	return nil
+}
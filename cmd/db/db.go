@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"gorm.io/gorm"
+)
+
+// Database types accepted by --from and --to
+const (
+	dbTypeSQLite = "sqlite"
+	dbTypeMySQL  = "mysql"
+)
+
+// Command creates the db parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the BirdNET-Go database",
+	}
+
+	dbCmd.AddCommand(MigrateCommand(settings))
+
+	return dbCmd
+}
+
+// MigrateCommand creates the db migrate subcommand
+func MigrateCommand(settings *conf.Settings) *cobra.Command {
+	var (
+		from       string
+		to         string
+		sqlitePath string
+		batchSize  int
+		resume     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy all data between the SQLite and MySQL databases",
+		Long: `Copy all data from one database backend to the other.
+
+Both databases are taken from the output settings of the configuration file,
+output.sqlite.path for SQLite and output.mysql for MySQL. The schema of the
+destination is created when missing. Every table is copied in batches with its
+primary keys, so detections keep their results, reviews, comments and locks.
+Row counts are verified when the copy is done.
+
+An interrupted migration can be continued with --resume, rows that were already
+copied are skipped. Stop BirdNET-Go before migrating and switch the enabled
+database in the configuration afterwards.
+
+Examples:
+  # Move an install from SQLite to MySQL
+  birdnet db migrate --from sqlite --to mysql
+
+  # Continue an interrupted migration
+  birdnet db migrate --from sqlite --to mysql --resume
+
+  # Move back to a new SQLite file
+  birdnet db migrate --from mysql --to sqlite --sqlite-path ./birdnet-new.db`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == to {
+				return fmt.Errorf("--from and --to must be different databases")
+			}
+			if sqlitePath != "" {
+				settings.Output.SQLite.Path = sqlitePath
+			}
+
+			src, srcDB, err := openStore(settings, from)
+			if err != nil {
+				return fmt.Errorf("failed to open source database: %w", err)
+			}
+			defer closeStore(cmd.ErrOrStderr(), src)
+
+			dst, dstDB, err := openStore(settings, to)
+			if err != nil {
+				return fmt.Errorf("failed to open destination database: %w", err)
+			}
+			defer closeStore(cmd.ErrOrStderr(), dst)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			result, err := datastore.MigrateDatabase(ctx, srcDB, dstDB, datastore.MigrationOptions{
+				BatchSize: batchSize,
+				Resume:    resume,
+				Progress: func(table string, copied, total int64) {
+					_, _ = fmt.Fprintf(out, "\r%-24s %d/%d", table, copied, total)
+				},
+			})
+			if result != nil && len(result.Tables) > 0 {
+				if _, werr := fmt.Fprintln(out); werr != nil {
+					return fmt.Errorf("failed to write output: %w", werr)
+				}
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("migration interrupted, run again with --resume to continue: %w", err)
+				}
+				return fmt.Errorf("migration failed: %w", err)
+			}
+
+			if err := printResult(out, result); err != nil {
+				return err
+			}
+			if !result.Verified() {
+				return fmt.Errorf("row counts do not match, run again with --resume to copy missing rows")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", dbTypeSQLite, "Source database (sqlite or mysql)")
+	cmd.Flags().StringVar(&to, "to", dbTypeMySQL, "Destination database (sqlite or mysql)")
+	cmd.Flags().StringVar(&sqlitePath, "sqlite-path", "", "Use this SQLite file instead of output.sqlite.path")
+	cmd.Flags().IntVar(&batchSize, "batch-size", datastore.DefaultMigrationBatchSize, "Number of rows copied per batch")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Continue an earlier migration into a non-empty destination")
+
+	return cmd
+}
+
+// openStore opens the database of the given type with the configured connection
+// settings. Opening the store creates any missing tables.
+func openStore(settings *conf.Settings, dbType string) (datastore.Interface, *gorm.DB, error) {
+	switch dbType {
+	case dbTypeSQLite:
+		if settings.Output.SQLite.Path == "" {
+			return nil, nil, fmt.Errorf("output.sqlite.path is not configured")
+		}
+		store := &datastore.SQLiteStore{Settings: settings}
+		if err := store.Open(); err != nil {
+			return nil, nil, err
+		}
+		return store, store.DB, nil
+	case dbTypeMySQL:
+		if settings.Output.MySQL.Host == "" || settings.Output.MySQL.Database == "" {
+			return nil, nil, fmt.Errorf("output.mysql host and database are not configured")
+		}
+		store := &datastore.MySQLStore{Settings: settings}
+		if err := store.Open(); err != nil {
+			return nil, nil, err
+		}
+		return store, store.DB, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported database type %q, use %s or %s", dbType, dbTypeSQLite, dbTypeMySQL)
+	}
+}
+
+// closeStore closes a database and reports failures as warnings
+func closeStore(w io.Writer, store datastore.Interface) {
+	if err := store.Close(); err != nil {
+		_, _ = fmt.Fprintf(w, "Warning: failed to close database: %v\n", err)
+	}
+}
+
+// printResult writes a per table summary of the migration
+func printResult(w io.Writer, result *datastore.MigrationResult) error {
+	if _, err := fmt.Fprintf(w, "%-24s %10s %10s %10s %8s\n", "Table", "Source", "Target", "Copied", "Status"); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	for i := range result.Tables {
+		t := &result.Tables[i]
+		status := "ok"
+		if !t.Verified() {
+			status = "MISMATCH"
+		}
+		if _, err := fmt.Fprintf(w, "%-24s %10d %10d %10d %8s\n", t.Table, t.SourceRows, t.TargetRows, t.Copied, status); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		if t.SkippedRows > 0 {
+			if _, err := fmt.Fprintf(w, "  skipped %d orphaned rows referencing deleted detections\n", t.SkippedRows); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package importdata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/importer"
+)
+
+// importFlags holds the flags shared by the import subcommands
+type importFlags struct {
+	sourceNode string
+	clipMode   string
+	dryRun     bool
+}
+
+// Command creates the import parent command
+func Command(settings *conf.Settings) *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import historic detections from other BirdNET applications",
+		Long: `Import historic detections into the BirdNET-Go database.
+
+Detections that are already stored for the same date, time and species are
+skipped, so an import can be repeated safely. Imported detections are tagged
+with a source node (default "import") and become part of the species tracking
+and analytics history after BirdNET-Go is restarted.`,
+	}
+
+	flags := &importFlags{}
+	importCmd.PersistentFlags().StringVar(&flags.sourceNode, "source-node", importer.DefaultSourceNode, "Source node tag stored with imported detections")
+	importCmd.PersistentFlags().StringVar(&flags.clipMode, "clips", string(importer.ClipCopy), "How existing clips are attached: copy, link or none")
+	importCmd.PersistentFlags().BoolVar(&flags.dryRun, "dry-run", false, "Count the detections that would be imported without writing anything")
+
+	importCmd.AddCommand(birdNETPiCommand(settings, flags), analyzerCommand(settings, flags))
+
+	return importCmd
+}
+
+// birdNETPiCommand creates the import birdnetpi subcommand
+func birdNETPiCommand(settings *conf.Settings, flags *importFlags) *cobra.Command {
+	var clipsDir string
+
+	cmd := &cobra.Command{
+		Use:   "birdnetpi <birds.db>",
+		Short: "Import detections from a BirdNET-Pi birds.db database",
+		Long: `Import detections from a BirdNET-Pi birds.db database.
+
+Extracted clips are attached when --extracted points to the BirdNET-Pi Extracted
+directory, which contains the By_Date folder.
+
+Examples:
+  # Import detections and clips copied from a BirdNET-Pi installation
+  birdnet import birdnetpi ./birds.db --extracted ./BirdSongs/Extracted
+
+  # Check what would be imported
+  birdnet import birdnetpi ./birds.db --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reader := &importer.BirdNETPiReader{DBPath: args[0], ClipsDir: clipsDir}
+			return runImport(cmd, settings, flags, reader)
+		},
+	}
+
+	cmd.Flags().StringVar(&clipsDir, "extracted", "", "BirdNET-Pi Extracted directory containing the detection clips")
+
+	return cmd
+}
+
+// analyzerCommand creates the import analyzer subcommand
+func analyzerCommand(settings *conf.Settings, flags *importFlags) *cobra.Command {
+	var start string
+
+	cmd := &cobra.Command{
+		Use:   "analyzer <file or directory>...",
+		Short: "Import BirdNET-Analyzer CSV results and Raven selection tables",
+		Long: `Import detections from BirdNET-Analyzer output files.
+
+CSV result files and Raven selection tables are supported, directories are
+searched recursively. The recording start time is read from the audio file
+name, e.g. 20240501_060000.wav, or set with --start for recordings without a
+timestamp in their name.
+
+Examples:
+  # Import all results of an analysis run
+  birdnet import analyzer ./results
+
+  # Import a selection table of a recording started at a known time
+  birdnet import analyzer ./garden.BirdNET.selection.table.txt --start "2024-05-01 06:00:00"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reader := &importer.AnalyzerReader{Paths: args}
+			if start != "" {
+				t, err := time.ParseInLocation(time.DateTime, start, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid --start, use YYYY-MM-DD HH:MM:SS: %w", err)
+				}
+				reader.Start = t
+			}
+			return runImport(cmd, settings, flags, reader)
+		},
+	}
+
+	cmd.Flags().StringVar(&start, "start", "", "Recording start time (YYYY-MM-DD HH:MM:SS) for files without a timestamp in their name")
+
+	return cmd
+}
+
+// runImport opens the configured database and imports the detections of reader
+func runImport(cmd *cobra.Command, settings *conf.Settings, flags *importFlags, reader importer.Reader) error {
+	clipMode, err := importer.ParseClipMode(flags.clipMode)
+	if err != nil {
+		return err
+	}
+
+	taxonomy, _, err := birdnet.LoadTaxonomyData("")
+	if err != nil {
+		return fmt.Errorf("failed to load species taxonomy: %w", err)
+	}
+
+	db := datastore.New(settings)
+	if db == nil {
+		return fmt.Errorf("no database is enabled in the output settings")
+	}
+	store, ok := db.(importer.Store)
+	if !ok {
+		return fmt.Errorf("the configured database does not support imports")
+	}
+	if err := db.Open(); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to close database: %v\n", err)
+		}
+	}()
+
+	out := cmd.OutOrStdout()
+	imp, err := importer.New(store, importer.Options{
+		SourceNode: flags.sourceNode,
+		ClipMode:   clipMode,
+		ExportPath: settings.Realtime.Audio.Export.Path,
+		DryRun:     flags.dryRun,
+		Resolve:    importer.NewTaxonomyResolver(taxonomy),
+		Progress: func(r *importer.Result) {
+			_, _ = fmt.Fprintf(out, "\rRead %d, imported %d, duplicates %d", r.Read, r.Imported, r.Duplicates)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := imp.Import(ctx, reader)
+	if _, werr := fmt.Fprintln(out); werr != nil {
+		return fmt.Errorf("failed to write output: %w", werr)
+	}
+	if printErr := printResult(out, result, flags.dryRun); printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	return nil
+}
+
+// printResult writes a human readable import summary
+func printResult(w io.Writer, result *importer.Result, dryRun bool) error {
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	lines := []string{
+		fmt.Sprintf("%s %d of %d detections", verb, result.Imported, result.Read),
+		fmt.Sprintf("Skipped %d detections already in the database", result.Duplicates),
+	}
+	if result.Invalid > 0 {
+		lines = append(lines, fmt.Sprintf("Skipped %d rows without a known species or time", result.Invalid))
+	}
+	if result.ClipsLinked > 0 || result.ClipsMissing > 0 {
+		lines = append(lines, fmt.Sprintf("Attached %d clips, %d clips were not found", result.ClipsLinked, result.ClipsMissing))
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	return nil
+}
@@ -189,6 +189,14 @@ func (cm *ControlMonitor) handleControlSignal(signal string) {
 		cm.handleReconfigureTelemetry()
 	case "reconfigure_species_tracking":
 		cm.handleReconfigureSpeciesTracking()
+	case "pause_analysis":
+		myaudio.PauseAnalysis()
+		GetLogger().Info("Analysis paused")
+		cm.notifySuccess("Analysis paused")
+	case "resume_analysis":
+		myaudio.ResumeAnalysis()
+		GetLogger().Info("Analysis resumed")
+		cm.notifySuccess("Analysis resumed")
 	default:
 		GetLogger().Warn("Received unknown control signal", logger.String("signal", signal))
 	}
@@ -287,11 +295,11 @@ func (cm *ControlMonitor) handleReconfigureRTSP() {
 
 	// Prepare the list of active sources (using source IDs, not raw URLs)
 	var sources []string
-	if len(settings.Realtime.RTSP.URLs) > 0 {
+	if rtspStreams := settings.Realtime.RTSP.ActiveStreams(); len(rtspStreams) > 0 {
 		registry := myaudio.GetRegistry()
 		if registry != nil {
-			for _, url := range settings.Realtime.RTSP.URLs {
-				if rtspSource := registry.GetOrCreateSource(url, myaudio.SourceTypeRTSP); rtspSource != nil {
+			for i := range rtspStreams {
+				if rtspSource := registry.GetOrCreateRTSPSource(&rtspStreams[i]); rtspSource != nil {
 					sources = append(sources, rtspSource.ID)
 				} else {
 					GetLogger().Warn("Failed to get RTSP source ID from registry for URL during reconfiguration")
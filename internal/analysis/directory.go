@@ -15,6 +15,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observation"
 )
 
 // cleanupProcessingFiles removes all .processing files from the output directory
@@ -50,20 +51,14 @@ func isProcessed(path, outputPath string, processedFiles map[string]bool) bool {
 	// Get the base filename without extension
 	baseName := filepath.Base(path)
 
-	// Check for output files
-	outputPathCSV := filepath.Join(outputPath, baseName+".csv")
-	outputPathTable := filepath.Join(outputPath, baseName+".txt")
-	outputPathProcessing := filepath.Join(outputPath, baseName+".processing")
-
-	// Check if any of the output files exist
-	if _, err := os.Stat(outputPathCSV); err == nil {
-		processedFiles[path] = true
-		return true
-	}
-	if _, err := os.Stat(outputPathTable); err == nil {
-		processedFiles[path] = true
-		return true
+	// Check if an output file of any output type exists
+	for _, outputType := range observation.OutputTypes {
+		if _, err := os.Stat(filepath.Join(outputPath, observation.OutputFileName(baseName, outputType))); err == nil {
+			processedFiles[path] = true
+			return true
+		}
 	}
+	outputPathProcessing := filepath.Join(outputPath, baseName+".processing")
 
 	// Check for processing lock file
 	if info, err := os.Stat(outputPathProcessing); err == nil {
@@ -676,6 +676,34 @@ func writeResults(settings *conf.Settings, notes []datastore.Note) error {
 		if err := observation.WriteNotesCsv(settings, notes, outputFile); err != nil {
 			return fmt.Errorf("failed to write notes CSV: %w", err)
 		}
+		return nil
+	}
+	// Bioacoustics formats for Raven Pro, Audacity and BirdNET-Analyzer tooling
+	if settings.Output.File.Type != "table" && settings.Output.File.Type != "" {
+		if !observation.IsValidOutputType(settings.Output.File.Type) {
+			return fmt.Errorf("unsupported output type %q, use one of %s",
+				settings.Output.File.Type, strings.Join(observation.OutputTypes, ", "))
+		}
+		info := &observation.ExportInfo{
+			InputFile:    settings.Input.Path,
+			ModelVersion: modelVersion(settings),
+			Threshold:    settings.BirdNET.Threshold,
+			Start:        time.Time{}, // processAudioData times the chunks from the zero time
+		}
+		if err := observation.WriteNotesFile(settings.Output.File.Type, notes, outputFile, info); err != nil {
+			return fmt.Errorf("failed to write notes as %s: %w", settings.Output.File.Type, err)
+		}
 	}
 	return nil
 }
+
+// modelVersion returns the version of the BirdNET model used for the analysis
+func modelVersion(settings *conf.Settings) string {
+	if bn == nil || bn.ModelInfo.ID == "" {
+		return birdnet.DefaultModelVersion
+	}
+	if bn.ModelInfo.ID == "Custom" && settings.BirdNET.ModelPath != "" {
+		return filepath.Base(settings.BirdNET.ModelPath)
+	}
+	return bn.ModelInfo.ID
+}
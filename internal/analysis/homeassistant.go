@@ -0,0 +1,451 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/mqtt"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// homeAssistantPublishTimeout bounds the publishing of one round of discovery configs and states
+const homeAssistantPublishTimeout = 30 * time.Second
+
+// homeAssistantStats is the state of the detection count sensors
+type homeAssistantStats struct {
+	Detections int            `json:"detections"`
+	Species    map[string]int `json:"species"` // Detections today per common name
+	Counts     map[string]int `json:"counts"`  // Detections today per species key, see mqtt.SpeciesKey
+}
+
+// homeAssistantSystemState is the state of the CPU, memory and disk usage sensors
+type homeAssistantSystemState struct {
+	CPU    float64            `json:"cpu"`
+	Memory float64            `json:"memory"`
+	Disk   map[string]float64 `json:"disk"` // Usage per disk key, see mqtt.DiskKey
+}
+
+// homeAssistantStreamHealth is the state of the connectivity sensor of a stream
+type homeAssistantStreamHealth struct {
+	Healthy          bool      `json:"healthy"`
+	ReceivingData    bool      `json:"receiving_data"`
+	RestartCount     int       `json:"restart_count"`
+	LastDataReceived time.Time `json:"last_data_received,omitzero"`
+}
+
+// homeAssistantPublisher keeps the Home Assistant discovery configs in sync with the
+// configured node and its audio sources, and publishes the states of the entities
+type homeAssistantPublisher struct {
+	proc      *processor.Processor
+	ds        datastore.Interface
+	client    mqtt.Client             // Client the discovery configs were published with
+	published map[string]struct{}     // Discovery config topics currently published
+	species   []mqtt.DiscoverySpecies // Species detected today, kept when the counts cannot be read
+
+	// Subscription to the retained discovery configs of the node, which finds the
+	// entities published before a restart
+	subscription *mqtt.Subscription
+	filter       string // Topic filter of the subscription
+
+	retainedMu sync.Mutex
+	retained   map[string]struct{} // Discovery config topics retained on the broker
+}
+
+// startHomeAssistantPublisher starts publishing Home Assistant discovery configs and
+// entity states. Settings are read on every round so enabling, disabling and
+// reconfiguring the integration takes effect without a restart.
+func startHomeAssistantPublisher(wg *sync.WaitGroup, quitChan chan struct{}, proc *processor.Processor, ds datastore.Interface) {
+	publisher := &homeAssistantPublisher{
+		proc:      proc,
+		ds:        ds,
+		published: make(map[string]struct{}),
+		retained:  make(map[string]struct{}),
+	}
+
+	wg.Go(func() {
+		defer publisher.stopSubscription()
+		publisher.run(quitChan)
+	})
+}
+
+// run publishes every state interval until quitChan is closed
+func (p *homeAssistantPublisher) run(quitChan chan struct{}) {
+	for {
+		p.publish()
+
+		interval := conf.Setting().Realtime.MQTT.HomeAssistant.StateInterval
+		if interval < conf.MinHomeAssistantStateInterval {
+			interval = conf.MinHomeAssistantStateInterval
+		}
+
+		select {
+		case <-quitChan:
+			return
+		case <-time.After(time.Duration(interval) * time.Second):
+		}
+	}
+}
+
+// publish runs one round of discovery sync and state publishing
+func (p *homeAssistantPublisher) publish() {
+	settings := conf.Setting()
+	client := p.proc.GetMQTTClient()
+	if !settings.Realtime.MQTT.Enabled || client == nil || !client.IsConnected() {
+		// Retained configs stay on the broker, republish them all once the
+		// integration is back
+		p.stopSubscription()
+		p.client = nil
+		clear(p.published)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), homeAssistantPublishTimeout)
+	defer cancel()
+
+	if !settings.Realtime.MQTT.HomeAssistant.Enabled {
+		if err := p.removeDiscovery(ctx, client); err != nil {
+			GetLogger().Warn("failed to remove Home Assistant discovery configs",
+				logger.Error(err),
+				logger.String("operation", "homeassistant_discovery_remove"))
+		}
+		return
+	}
+
+	discovery := mqtt.NewHomeAssistantDiscovery(settings)
+	diskPaths := homeAssistantDiskPaths(settings)
+	sources := homeAssistantSources(settings)
+
+	if p.subscription == nil || p.client != client || p.filter != discovery.ConfigTopicFilter() {
+		p.subscribe(settings, discovery.ConfigTopicFilter())
+	}
+
+	// The species of today decide the per-species entities, so the counts are read first
+	stats, err := p.readStats(ctx)
+	if err != nil {
+		GetLogger().Warn("failed to read detection counts for Home Assistant",
+			logger.Error(err),
+			logger.String("operation", "homeassistant_stats"))
+	}
+
+	if err := p.syncDiscovery(ctx, client, discovery, diskPaths, sources, p.species); err != nil {
+		GetLogger().Warn("failed to publish Home Assistant discovery configs",
+			logger.Error(err),
+			logger.String("operation", "homeassistant_discovery"))
+		return
+	}
+
+	if err := p.publishStates(ctx, client, discovery, diskPaths, stats); err != nil {
+		GetLogger().Warn("failed to publish Home Assistant entity states",
+			logger.Error(err),
+			logger.String("operation", "homeassistant_states"))
+	}
+}
+
+// subscribe replaces the subscription to the retained discovery configs of the node.
+// The broker delivers the retained configs on subscribe, configs of entities which are
+// no longer wanted are removed on the next round.
+func (p *homeAssistantPublisher) subscribe(settings *conf.Settings, filter string) {
+	p.stopSubscription()
+
+	subscription := mqtt.NewSubscription(settings, "homeassistant", []string{filter}, p.onRetainedConfig)
+	if err := subscription.Start(context.Background()); err != nil {
+		GetLogger().Warn("failed to subscribe to Home Assistant discovery configs",
+			logger.Error(err),
+			logger.String("filter", filter),
+			logger.String("operation", "homeassistant_discovery_subscribe"))
+		return
+	}
+	p.subscription = subscription
+	p.filter = filter
+}
+
+// stopSubscription stops the subscription to the retained discovery configs and
+// forgets the configs it reported
+func (p *homeAssistantPublisher) stopSubscription() {
+	if p.subscription != nil {
+		p.subscription.Stop()
+		p.subscription = nil
+		p.filter = ""
+	}
+
+	p.retainedMu.Lock()
+	clear(p.retained)
+	p.retainedMu.Unlock()
+}
+
+// onRetainedConfig tracks the discovery configs on the broker, an empty payload
+// removes the entity
+func (p *homeAssistantPublisher) onRetainedConfig(topic string, payload []byte) {
+	p.retainedMu.Lock()
+	defer p.retainedMu.Unlock()
+
+	if len(payload) == 0 {
+		delete(p.retained, topic)
+		return
+	}
+	p.retained[topic] = struct{}{}
+}
+
+// knownTopics returns the discovery config topics published by this run or retained
+// on the broker
+func (p *homeAssistantPublisher) knownTopics() map[string]struct{} {
+	p.retainedMu.Lock()
+	known := maps.Clone(p.retained)
+	p.retainedMu.Unlock()
+
+	maps.Copy(known, p.published)
+	return known
+}
+
+// forgetTopics drops removed discovery config topics from the published and retained sets
+func (p *homeAssistantPublisher) forgetTopics(topics []string) {
+	p.retainedMu.Lock()
+	defer p.retainedMu.Unlock()
+
+	for _, topic := range topics {
+		delete(p.published, topic)
+		delete(p.retained, topic)
+	}
+}
+
+// removeDiscovery removes all entities of the node from Home Assistant after the
+// integration was disabled and stops the subscription
+func (p *homeAssistantPublisher) removeDiscovery(ctx context.Context, client mqtt.Client) error {
+	topics := slices.Sorted(maps.Keys(p.knownTopics()))
+	if err := mqtt.RemoveEntities(ctx, client, topics); err != nil {
+		return err
+	}
+	p.forgetTopics(topics)
+	p.stopSubscription()
+	p.client = nil
+
+	if len(topics) > 0 {
+		GetLogger().Info("Home Assistant discovery configs removed",
+			logger.Int("removed", len(topics)),
+			logger.String("operation", "homeassistant_discovery_remove"))
+	}
+	return nil
+}
+
+// readStats reads the detection counts of today and updates the species detected today.
+// It returns nil counts without a datastore.
+func (p *homeAssistantPublisher) readStats(ctx context.Context) (*homeAssistantStats, error) {
+	if p.ds == nil {
+		return nil, nil
+	}
+	today := time.Now().Format(time.DateOnly)
+	summary, err := p.ds.GetSpeciesSummaryData(ctx, today, today, "")
+	if err != nil {
+		return nil, err
+	}
+	stats := homeAssistantStatsFromSummary(summary)
+	p.species = homeAssistantSpecies(summary)
+	return &stats, nil
+}
+
+// syncDiscovery publishes the discovery configs of all entities and removes the
+// entities of sources which no longer exist and of species not detected today,
+// including those retained on the broker from before a restart. All configs are
+// republished when the client changes, e.g. after MQTT settings were reconfigured.
+func (p *homeAssistantPublisher) syncDiscovery(ctx context.Context, client mqtt.Client, discovery *mqtt.HomeAssistantDiscovery,
+	diskPaths []string, sources []mqtt.DiscoverySource, species []mqtt.DiscoverySpecies) error {
+	if p.client != client {
+		p.client = client
+		clear(p.published)
+	}
+
+	entities := discovery.NodeEntities(diskPaths)
+	for i := range sources {
+		entities = append(entities, discovery.SourceEntities(&sources[i])...)
+	}
+	entities = append(entities, discovery.SpeciesEntities(species)...)
+
+	desired := make(map[string]struct{}, len(entities))
+	var added []mqtt.DiscoveryEntity
+	for i := range entities {
+		desired[entities[i].Topic] = struct{}{}
+		if _, ok := p.published[entities[i].Topic]; !ok {
+			added = append(added, entities[i])
+		}
+	}
+
+	var stale []string
+	for topic := range p.knownTopics() {
+		if _, ok := desired[topic]; !ok {
+			stale = append(stale, topic)
+		}
+	}
+	slices.Sort(stale)
+
+	if err := mqtt.RemoveEntities(ctx, client, stale); err != nil {
+		return err
+	}
+	p.forgetTopics(stale)
+
+	if err := mqtt.PublishEntities(ctx, client, added); err != nil {
+		return err
+	}
+	for i := range added {
+		p.published[added[i].Topic] = struct{}{}
+	}
+
+	if len(added) > 0 || len(stale) > 0 {
+		GetLogger().Info("Home Assistant discovery configs updated",
+			logger.Int("published", len(added)),
+			logger.Int("removed", len(stale)),
+			logger.String("prefix", discovery.Prefix),
+			logger.String("operation", "homeassistant_discovery"))
+	}
+	return nil
+}
+
+// publishStates publishes the detection counts of today when they were read, the
+// system state and the health of the RTSP streams
+func (p *homeAssistantPublisher) publishStates(ctx context.Context, client mqtt.Client, discovery *mqtt.HomeAssistantDiscovery,
+	diskPaths []string, stats *homeAssistantStats) error {
+	var errs []error
+
+	if stats != nil {
+		errs = append(errs, publishJSON(ctx, client, discovery.StatsTopic(), stats))
+	}
+
+	errs = append(errs, publishJSON(ctx, client, discovery.SystemTopic(), readSystemState(diskPaths)))
+
+	registry := myaudio.GetRegistry()
+	health := myaudio.GetRTSPStreamHealth()
+	urls := slices.Sorted(maps.Keys(health))
+	for _, url := range urls {
+		source, ok := registry.GetSourceByConnection(url)
+		if !ok {
+			continue
+		}
+		h := health[url]
+		errs = append(errs, publishJSON(ctx, client, discovery.StreamHealthTopic(source.ID), homeAssistantStreamHealth{
+			Healthy:          h.IsHealthy,
+			ReceivingData:    h.IsReceivingData,
+			RestartCount:     h.RestartCount,
+			LastDataReceived: h.LastDataReceived,
+		}))
+	}
+
+	return errors.Join(errs...)
+}
+
+// homeAssistantStatsFromSummary sums the species summary of today into sensor state
+func homeAssistantStatsFromSummary(summary []datastore.SpeciesSummaryData) homeAssistantStats {
+	stats := homeAssistantStats{
+		Species: make(map[string]int, len(summary)),
+		Counts:  make(map[string]int, len(summary)),
+	}
+	for i := range summary {
+		stats.Species[homeAssistantSpeciesName(&summary[i])] += summary[i].Count
+		stats.Counts[mqtt.SpeciesKey(summary[i].SpeciesCode, summary[i].ScientificName)] += summary[i].Count
+		stats.Detections += summary[i].Count
+	}
+	return stats
+}
+
+// homeAssistantSpecies returns the species of the summary of today which get their
+// own detection count entity, sorted by key
+func homeAssistantSpecies(summary []datastore.SpeciesSummaryData) []mqtt.DiscoverySpecies {
+	seen := make(map[string]struct{}, len(summary))
+	species := make([]mqtt.DiscoverySpecies, 0, len(summary))
+	for i := range summary {
+		key := mqtt.SpeciesKey(summary[i].SpeciesCode, summary[i].ScientificName)
+		if _, ok := seen[key]; ok || key == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		species = append(species, mqtt.DiscoverySpecies{Key: key, Name: homeAssistantSpeciesName(&summary[i])})
+	}
+	slices.SortFunc(species, func(a, b mqtt.DiscoverySpecies) int { return strings.Compare(a.Key, b.Key) })
+	return species
+}
+
+// homeAssistantSpeciesName returns the common name of a species, or the scientific
+// name when it has none
+func homeAssistantSpeciesName(data *datastore.SpeciesSummaryData) string {
+	if data.CommonName != "" {
+		return data.CommonName
+	}
+	return data.ScientificName
+}
+
+// homeAssistantSources returns the audio sources which get their own entities
+func homeAssistantSources(settings *conf.Settings) []mqtt.DiscoverySource {
+	var bands []string
+	if settings.Realtime.Audio.SoundLevel.Enabled {
+		bands = myaudio.OctaveBandKeys()
+	}
+
+	registered := myaudio.GetRegistry().ListSources()
+	sources := make([]mqtt.DiscoverySource, 0, len(registered))
+	for _, source := range registered {
+		if source.Type == myaudio.SourceTypeFile {
+			continue
+		}
+		sources = append(sources, mqtt.DiscoverySource{
+			ID:          source.ID,
+			DisplayName: source.DisplayName,
+			IsStream:    source.Type == myaudio.SourceTypeRTSP,
+			SoundBands:  bands,
+		})
+	}
+	return sources
+}
+
+// homeAssistantDiskPaths returns the monitored disk paths, or the root path when
+// none are configured
+func homeAssistantDiskPaths(settings *conf.Settings) []string {
+	if len(settings.Realtime.Monitoring.Disk.Paths) > 0 {
+		return settings.Realtime.Monitoring.Disk.Paths
+	}
+	return []string{"/"}
+}
+
+// readSystemState reads the CPU, memory and disk usage, resources which cannot be
+// read are left out of the state
+func readSystemState(diskPaths []string) homeAssistantSystemState {
+	state := homeAssistantSystemState{Disk: make(map[string]float64, len(diskPaths))}
+
+	// Usage since the previous call, which is the previous round of publishing
+	if percent, err := cpu.Percent(0, false); err == nil && len(percent) > 0 {
+		state.CPU = roundToDecimalPlaces(percent[0], 1)
+	}
+	if memory, err := mem.VirtualMemory(); err == nil {
+		state.Memory = roundToDecimalPlaces(memory.UsedPercent, 1)
+	}
+	for _, path := range diskPaths {
+		if usage, err := disk.Usage(path); err == nil {
+			state.Disk[mqtt.DiskKey(path)] = roundToDecimalPlaces(usage.UsedPercent, 1)
+		}
+	}
+	return state
+}
+
+// publishJSON publishes the JSON encoding of a state
+func publishJSON(ctx context.Context, client mqtt.Client, topic string, state any) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return errors.New(err).
+			Component("analysis").
+			Category(errors.CategoryMQTTPublish).
+			Context("operation", "marshal_homeassistant_state").
+			Context("topic", topic).
+			Build()
+	}
+	return client.Publish(ctx, topic, string(payload))
+}
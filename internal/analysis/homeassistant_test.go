@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/mqtt"
+)
+
+func TestHomeAssistantSyncDiscovery(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Main.Name = "node"
+	settings.Realtime.MQTT.Topic = "birdnet"
+	settings.Realtime.MQTT.HomeAssistant.DiscoveryPrefix = "homeassistant"
+	discovery := mqtt.NewHomeAssistantDiscovery(settings)
+
+	published := make(map[string]string)
+	client := &mockMQTTClient{
+		connected: true,
+		publishFunc: func(_ context.Context, topic, payload string) error {
+			published[topic] = payload
+			return nil
+		},
+	}
+	publisher := &homeAssistantPublisher{
+		published: make(map[string]struct{}),
+		retained:  make(map[string]struct{}),
+	}
+	diskPaths := []string{"/"}
+	streamTopic := "homeassistant/binary_sensor/node/garden_stream/config"
+
+	sources := []mqtt.DiscoverySource{{ID: "garden", IsStream: true}}
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, sources, nil))
+	assert.NotEmpty(t, published[streamTopic], "stream entity is published")
+	assert.Len(t, publisher.published, len(discovery.NodeEntities(diskPaths))+1)
+
+	clear(published)
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, sources, nil))
+	assert.Empty(t, published, "unchanged entities are not republished")
+
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, nil, nil))
+	payload, ok := published[streamTopic]
+	require.True(t, ok, "entities of removed sources are cleaned up")
+	assert.Empty(t, payload)
+	assert.NotContains(t, publisher.published, streamTopic)
+
+	clear(published)
+	reconnected := &mockMQTTClient{connected: true, publishFunc: client.publishFunc}
+	require.NoError(t, publisher.syncDiscovery(t.Context(), reconnected, discovery, diskPaths, nil, nil))
+	assert.Len(t, published, len(discovery.NodeEntities(diskPaths)), "a new client republishes all entities")
+}
+
+func TestHomeAssistantSyncDiscoveryRemovesRetainedEntities(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Main.Name = "node"
+	settings.Realtime.MQTT.Topic = "birdnet"
+	settings.Realtime.MQTT.HomeAssistant.DiscoveryPrefix = "homeassistant"
+	discovery := mqtt.NewHomeAssistantDiscovery(settings)
+	assert.Equal(t, "homeassistant/+/node/+/config", discovery.ConfigTopicFilter())
+
+	published := make(map[string]string)
+	client := &mockMQTTClient{
+		connected: true,
+		publishFunc: func(_ context.Context, topic, payload string) error {
+			published[topic] = payload
+			return nil
+		},
+	}
+	publisher := &homeAssistantPublisher{
+		published: make(map[string]struct{}),
+		retained:  make(map[string]struct{}),
+	}
+	diskPaths := []string{"/"}
+
+	// Configs retained on the broker by the previous run, before the garden stream was removed
+	oldStream := "homeassistant/binary_sensor/node/garden_stream/config"
+	cpu := "homeassistant/sensor/node/cpu_usage/config"
+	publisher.onRetainedConfig(oldStream, []byte(`{"name":"garden stream"}`))
+	publisher.onRetainedConfig(cpu, []byte(`{"name":"CPU usage"}`))
+
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, nil, nil))
+	payload, ok := published[oldStream]
+	require.True(t, ok, "entities retained from a previous run are cleaned up")
+	assert.Empty(t, payload)
+	assert.NotEmpty(t, published[cpu], "wanted entities are published")
+	assert.NotContains(t, publisher.knownTopics(), oldStream)
+
+	// A removal seen on the subscription drops the topic
+	publisher.onRetainedConfig(cpu, nil)
+	publisher.retainedMu.Lock()
+	assert.NotContains(t, publisher.retained, cpu)
+	publisher.retainedMu.Unlock()
+
+	// Disabling the integration removes all entities
+	publisher.onRetainedConfig(oldStream, []byte(`{"name":"garden stream"}`))
+	clear(published)
+	require.NoError(t, publisher.removeDiscovery(t.Context(), client))
+	assert.Len(t, published, len(discovery.NodeEntities(diskPaths))+1)
+	for topic, payload := range published {
+		assert.Empty(t, payload, "config of %s is removed", topic)
+	}
+	assert.Empty(t, publisher.knownTopics())
+
+	clear(published)
+	require.NoError(t, publisher.removeDiscovery(t.Context(), client))
+	assert.Empty(t, published, "nothing is left to remove")
+}
+
+func TestHomeAssistantSyncDiscoverySpeciesRollover(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Main.Name = "node"
+	settings.Realtime.MQTT.Topic = "birdnet"
+	settings.Realtime.MQTT.HomeAssistant.DiscoveryPrefix = "homeassistant"
+	discovery := mqtt.NewHomeAssistantDiscovery(settings)
+
+	published := make(map[string]string)
+	client := &mockMQTTClient{
+		connected: true,
+		publishFunc: func(_ context.Context, topic, payload string) error {
+			published[topic] = payload
+			return nil
+		},
+	}
+	publisher := &homeAssistantPublisher{
+		published: make(map[string]struct{}),
+		retained:  make(map[string]struct{}),
+	}
+	diskPaths := []string{"/"}
+	blackbird := "homeassistant/sensor/node/eurbla_today/config"
+	tit := "homeassistant/sensor/node/gretit1_today/config"
+
+	yesterday := homeAssistantSpecies([]datastore.SpeciesSummaryData{
+		{ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", SpeciesCode: "eurbla", Count: 5},
+		{ScientificName: "Parus major", CommonName: "Great Tit", SpeciesCode: "gretit1", Count: 2},
+	})
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, nil, yesterday))
+	assert.NotEmpty(t, published[blackbird], "a sensor is published per species detected today")
+	assert.NotEmpty(t, published[tit])
+
+	// After midnight only the blackbird has been detected again
+	clear(published)
+	today := homeAssistantSpecies([]datastore.SpeciesSummaryData{
+		{ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", SpeciesCode: "eurbla", Count: 1},
+	})
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, nil, today))
+	payload, ok := published[tit]
+	require.True(t, ok, "sensors of species not detected today are removed")
+	assert.Empty(t, payload)
+	assert.NotContains(t, published, blackbird, "sensors of species detected again are kept")
+
+	// Sensors retained on the broker from before a restart are removed the same way
+	clear(published)
+	publisher.onRetainedConfig(tit, []byte(`{"name":"Great Tit today"}`))
+	require.NoError(t, publisher.syncDiscovery(t.Context(), client, discovery, diskPaths, nil, today))
+	payload, ok = published[tit]
+	require.True(t, ok)
+	assert.Empty(t, payload)
+}
+
+func TestHomeAssistantStatsFromSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := []datastore.SpeciesSummaryData{
+		{ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", SpeciesCode: "eurbla", Count: 5},
+		{ScientificName: "Parus major", CommonName: "Great Tit", SpeciesCode: "gretit1", Count: 2},
+		{ScientificName: "Strix aluco", Count: 1},
+	}
+	stats := homeAssistantStatsFromSummary(summary)
+	assert.Equal(t, 8, stats.Detections)
+	assert.Equal(t, map[string]int{"Eurasian Blackbird": 5, "Great Tit": 2, "Strix aluco": 1}, stats.Species)
+	assert.Equal(t, map[string]int{"eurbla": 5, "gretit1": 2, "strix_aluco": 1}, stats.Counts)
+
+	assert.Equal(t, []mqtt.DiscoverySpecies{
+		{Key: "eurbla", Name: "Eurasian Blackbird"},
+		{Key: "gretit1", Name: "Great Tit"},
+		{Key: "strix_aluco", Name: "Strix aluco"},
+	}, homeAssistantSpecies(summary))
+}
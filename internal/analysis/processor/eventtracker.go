@@ -23,14 +23,15 @@ const (
 )
 
 // EventBehaviorFunc defines the signature for functions that determine the behavior of an event.
-// It returns true if the event is allowed to be processed based on the given last event time and timeout.
-type EventBehaviorFunc func(lastEventTime time.Time, timeout time.Duration) bool
+// It returns true if the event is allowed to be processed at now based on the given last event time and timeout.
+type EventBehaviorFunc func(lastEventTime, now time.Time, timeout time.Duration) bool
 
 // EventHandler holds the state and behavior for a specific event type.
 type EventHandler struct {
 	LastEventTime map[string]time.Time // Tracks the last event time for each species
 	BehaviorFunc  EventBehaviorFunc    // Function that defines the event handling behavior
 	Mutex         sync.Mutex           // Mutex to ensure thread-safe access
+	now           func() time.Time     // Returns the current time, replaced in tests
 }
 
 // NewEventHandler creates a new EventHandler with the specified timeout and behavior function.
@@ -38,6 +39,7 @@ func NewEventHandler(timeout time.Duration, behaviorFunc EventBehaviorFunc) *Eve
 	return &EventHandler{
 		LastEventTime: make(map[string]time.Time),
 		BehaviorFunc:  behaviorFunc,
+		now:           time.Now,
 	}
 }
 
@@ -48,9 +50,10 @@ func (h *EventHandler) shouldHandleEventLocked(species string, timeout time.Dura
 	// Normalize species name to lowercase for consistent key usage
 	normalizedSpecies := strings.ToLower(species)
 
+	now := h.now()
 	lastTime, exists := h.LastEventTime[normalizedSpecies]
-	if !exists || h.BehaviorFunc(lastTime, timeout) {
-		h.LastEventTime[normalizedSpecies] = time.Now()
+	if !exists || h.BehaviorFunc(lastTime, now, timeout) {
+		h.LastEventTime[normalizedSpecies] = now
 		return true
 	}
 	return false
@@ -74,8 +77,8 @@ func (h *EventHandler) ResetEvent(species string) {
 
 // StandardEventBehavior is a default behavior function that allows an event to be handled
 // if the current time is greater than the last event time plus the timeout.
-func StandardEventBehavior(lastEventTime time.Time, timeout time.Duration) bool {
-	return time.Since(lastEventTime) >= timeout
+func StandardEventBehavior(lastEventTime, now time.Time, timeout time.Duration) bool {
+	return now.Sub(lastEventTime) >= timeout
 }
 
 // EventTracker manages event handling for different species across multiple event types.
@@ -132,6 +135,20 @@ func NewEventTrackerWithConfig(defaultInterval time.Duration, speciesConfigs map
 // TrackEvent checks if an event for a given species and event type should be processed.
 // It utilizes the respective event handler to make this determination, considering species-specific intervals.
 func (et *EventTracker) TrackEvent(species string, eventType EventType) bool {
+	return et.trackEvent(species, species, eventType, 0)
+}
+
+// TrackEventWithTimeout checks if an event tracked under key should be processed using
+// the given timeout instead of the species interval. A timeout of zero or less falls back
+// to the interval of the species. The key allows several actions of the same species and
+// event type to keep separate timers.
+func (et *EventTracker) TrackEventWithTimeout(key, species string, eventType EventType, timeout time.Duration) bool {
+	return et.trackEvent(key, species, eventType, timeout)
+}
+
+// trackEvent implements TrackEvent and TrackEventWithTimeout. The last event time is
+// stored under key, while the species is used to look up the configured interval.
+func (et *EventTracker) trackEvent(key, species string, eventType EventType, timeout time.Duration) bool {
 	// Normalize species key consistently for all map lookups
 	normalizedSpecies := strings.ToLower(species)
 
@@ -163,6 +180,10 @@ func (et *EventTracker) TrackEvent(species string, eventType EventType) bool {
 		}
 		// For zero interval, silently use the default interval (existing behavior)
 	}
+	if timeout > 0 {
+		// Explicit timeout from the caller overrides the species interval
+		effectiveTimeout = timeout
+	}
 
 	// 2. We unlock the EventTracker mutex BEFORE acquiring the handler's mutex
 	//    This is critical to prevent deadlocks that could occur if:
@@ -177,12 +198,23 @@ func (et *EventTracker) TrackEvent(species string, eventType EventType) bool {
 	handler.Mutex.Lock()
 	// Use the shared helper method to evaluate whether the event should be handled
 	// Pass the effective timeout as a parameter rather than modifying handler.Timeout
-	allowEvent := handler.shouldHandleEventLocked(normalizedSpecies, effectiveTimeout)
+	allowEvent := handler.shouldHandleEventLocked(key, effectiveTimeout)
 	handler.Mutex.Unlock()
 
 	return allowEvent
 }
 
+// setNow replaces the clock of all event handlers, used by tests to move time forward
+func (et *EventTracker) setNow(now func() time.Time) {
+	et.Mutex.RLock()
+	defer et.Mutex.RUnlock()
+	for _, handler := range et.Handlers {
+		handler.Mutex.Lock()
+		handler.now = now
+		handler.Mutex.Unlock()
+	}
+}
+
 // ResetEvent resets the state for a specific species and event type, clearing any tracked event timing.
 func (et *EventTracker) ResetEvent(species string, eventType EventType) {
 	// Normalize species key consistently
@@ -22,9 +22,23 @@ func (p *Processor) GetMQTTClient() mqtt.Client {
 func (p *Processor) SetMQTTClient(client mqtt.Client) {
 	p.mqttMutex.Lock()
 	defer p.mqttMutex.Unlock()
+	if client != nil {
+		client.SetControlChannel(p.controlChan)
+	}
 	p.MqttClient = client
 }
 
+// SetControlChannel sets the channel MQTT commands send control signals to, for the
+// current and all future MQTT clients. Set it to nil before closing the channel.
+func (p *Processor) SetControlChannel(ch chan string) {
+	p.mqttMutex.Lock()
+	defer p.mqttMutex.Unlock()
+	p.controlChan = ch
+	if p.MqttClient != nil {
+		p.MqttClient.SetControlChannel(ch)
+	}
+}
+
 // DisconnectMQTTClient safely disconnects and removes the MQTT client
 func (p *Processor) DisconnectMQTTClient() {
 	p.mqttMutex.Lock()
@@ -41,6 +41,10 @@ func (m *MockMqttClientWithCapture) Publish(_ context.Context, topic, data strin
 	return m.PublishError
 }
 
+func (m *MockMqttClientWithCapture) PublishRetained(ctx context.Context, topic, data string) error {
+	return m.Publish(ctx, topic, data)
+}
+
 func (m *MockMqttClientWithCapture) SetControlChannel(_ chan string) {
 	// Not needed for test
 }
@@ -0,0 +1,167 @@
+// notify.go
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/events"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// SendNotificationAction creates a detection notification for a species with a
+// SendNotification action configured. The notification is stored by the notification
+// service and forwarded by the push dispatcher to the configured providers.
+type SendNotificationAction struct {
+	Settings       *conf.Settings
+	Note           datastore.Note
+	EventTracker   *EventTracker
+	BirdImageCache *imageprovider.BirdImageCache
+	Title          string        // Title template, empty for the default title
+	Message        string        // Message template, empty for the default message
+	Providers      []string      // Push provider names to notify, empty for all providers
+	Cooldown       time.Duration // Minimum time between notifications, 0 uses the species interval
+	CooldownKey    string        // Event tracker key, separates the cooldowns of several actions
+	Description    string
+	CorrelationID  string     // Detection correlation ID for log tracking
+	mu             sync.Mutex // Protect concurrent access to Note
+}
+
+// GetDescription returns a human-readable description of the SendNotificationAction
+func (a *SendNotificationAction) GetDescription() string {
+	if a.Description != "" {
+		return a.Description
+	}
+	return "Send detection notification"
+}
+
+// Execute creates the notification unless the cooldown of the action is still active
+func (a *SendNotificationAction) Execute(data any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !notification.IsInitialized() {
+		GetLogger().Debug("Notification service not initialized, skipping species notification",
+			logger.String("component", "analysis.processor.notify"),
+			logger.String("detection_id", a.CorrelationID),
+			logger.String("species", a.Note.CommonName),
+			logger.String("operation", "send_notification"))
+		return nil
+	}
+
+	speciesName := strings.ToLower(a.Note.CommonName)
+	key := a.CooldownKey
+	if key == "" {
+		key = speciesName
+	}
+	if a.EventTracker != nil && !a.EventTracker.TrackEventWithTimeout(key, speciesName, SendNotification, a.Cooldown) {
+		return nil
+	}
+
+	event, err := events.NewDetectionEvent(
+		a.Note.CommonName,
+		a.Note.ScientificName,
+		float64(a.Note.Confidence),
+		a.Note.Source.DisplayName,
+		false,
+		0,
+	)
+	if err != nil {
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryValidation).
+			Context("operation", "create_detection_event").
+			Context("species", a.Note.CommonName).
+			Build()
+	}
+	if metadata := event.GetMetadata(); metadata != nil {
+		if a.Note.ID != 0 {
+			metadata["note_id"] = a.Note.ID
+		}
+		metadata["latitude"] = a.Note.Latitude
+		metadata["longitude"] = a.Note.Longitude
+		metadata["begin_time"] = a.Note.BeginTime
+		if a.BirdImageCache != nil {
+			if birdImage, err := a.BirdImageCache.Get(a.Note.ScientificName); err == nil && birdImage.URL != "" {
+				metadata["image_url"] = birdImage.URL
+			}
+		}
+	}
+
+	templateData := notification.NewTemplateData(event,
+		a.Settings.Security.GetBaseURL(a.Settings.WebServer.Port), a.Settings.Main.TimeAs24h)
+
+	title := a.render("title", a.Title, templateData)
+	if title == "" {
+		title = fmt.Sprintf("%s detected", a.Note.CommonName)
+	}
+	message := a.render("message", a.Message, templateData)
+	if message == "" {
+		message = fmt.Sprintf("%s (%s) detected with %s%% confidence at %s",
+			a.Note.CommonName, a.Note.ScientificName, templateData.ConfidencePercent, templateData.DetectionTime)
+	}
+
+	notif := notification.NewNotification(notification.TypeDetection, notification.PriorityHigh, title, message).
+		WithComponent("detection").
+		WithMetadata("species", a.Note.CommonName).
+		WithMetadata("scientific_name", a.Note.ScientificName).
+		WithMetadata("confidence", float64(a.Note.Confidence)).
+		WithMetadata("location", a.Note.Source.DisplayName).
+		WithMetadata("species_action", true).
+		WithExpiry(notification.DefaultDetectionExpiry)
+	notif = notification.EnrichWithTemplateData(notif, templateData)
+	if a.Note.ID != 0 {
+		notif = notif.WithMetadata("note_id", a.Note.ID)
+	}
+	if len(a.Providers) > 0 {
+		notif = notif.WithMetadata(notification.MetadataKeyProviders, a.Providers)
+	}
+
+	if err := notification.GetService().CreateWithMetadata(notif); err != nil {
+		// Allow a retry on the next detection instead of waiting for the cooldown
+		if a.EventTracker != nil {
+			a.EventTracker.ResetEvent(key, SendNotification)
+		}
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryProcessing).
+			Context("operation", "send_species_notification").
+			Context("species", a.Note.CommonName).
+			Build()
+	}
+
+	GetLogger().Info("Species notification sent",
+		logger.String("component", "analysis.processor.notify"),
+		logger.String("detection_id", a.CorrelationID),
+		logger.String("species", a.Note.CommonName),
+		logger.Float64("confidence", float64(a.Note.Confidence)),
+		logger.Int("provider_count", len(a.Providers)),
+		logger.String("operation", "send_notification"))
+	return nil
+}
+
+// render renders a title or message template, returning an empty string when the
+// template is not set or fails so the caller falls back to the default text
+func (a *SendNotificationAction) render(field, tmpl string, data *notification.TemplateData) string {
+	if tmpl == "" {
+		return ""
+	}
+	rendered, err := notification.RenderTemplate(field, tmpl, data)
+	if err != nil {
+		GetLogger().Warn("Failed to render species notification template, using default",
+			logger.String("component", "analysis.processor.notify"),
+			logger.String("detection_id", a.CorrelationID),
+			logger.String("field", field),
+			logger.String("template", tmpl),
+			logger.Error(err))
+		return ""
+	}
+	return strings.TrimSpace(rendered)
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestGetActionsForItem_SendNotification verifies that SendNotification species
+// actions are turned into configured SendNotificationAction instances
+func TestGetActionsForItem_SendNotification(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"test bird": {
+			Actions: []conf.SpeciesAction{
+				{
+					Type:            "SendNotification",
+					Title:           "Rare: {{.CommonName}}",
+					Message:         "{{.ConfidencePercent}}%",
+					Providers:       []string{"telegram"},
+					CooldownMinutes: 30,
+				},
+				{Type: "SendNotification"},
+			},
+		},
+	}
+	p := &Processor{Settings: settings, EventTracker: NewEventTracker(time.Minute)}
+
+	detection := testDetection()
+	actions := p.getActionsForItem(&detection)
+	require.Len(t, actions, 2, "only the custom actions should be returned")
+
+	first, ok := actions[0].(*SendNotificationAction)
+	require.True(t, ok, "expected SendNotificationAction, got %T", actions[0])
+	assert.Equal(t, "Rare: {{.CommonName}}", first.Title)
+	assert.Equal(t, "{{.ConfidencePercent}}%", first.Message)
+	assert.Equal(t, []string{"telegram"}, first.Providers)
+	assert.Equal(t, 30*time.Minute, first.Cooldown)
+	assert.Equal(t, "Test Bird", first.Note.CommonName)
+
+	second, ok := actions[1].(*SendNotificationAction)
+	require.True(t, ok, "expected SendNotificationAction, got %T", actions[1])
+	assert.Zero(t, second.Cooldown)
+	assert.NotEqual(t, first.CooldownKey, second.CooldownKey, "actions need separate cooldowns")
+}
+
+// TestEventTracker_TrackEventWithTimeout verifies explicit timeouts and per key tracking
+func TestEventTracker_TrackEventWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewEventTrackerWithConfig(time.Hour, map[string]conf.SpeciesConfig{
+		"test bird": {Interval: 1},
+	})
+	now := time.Date(2025, 5, 1, 6, 0, 0, 0, time.UTC)
+	tracker.setNow(func() time.Time { return now })
+
+	// Explicit timeout overrides the species interval
+	assert.True(t, tracker.TrackEventWithTimeout("test bird#0", "Test Bird", SendNotification, time.Hour))
+	assert.False(t, tracker.TrackEventWithTimeout("test bird#0", "Test Bird", SendNotification, time.Hour))
+
+	// A different key keeps its own timer
+	assert.True(t, tracker.TrackEventWithTimeout("test bird#1", "Test Bird", SendNotification, 0))
+
+	// Zero timeout uses the one second species interval
+	now = now.Add(999 * time.Millisecond)
+	assert.False(t, tracker.TrackEventWithTimeout("test bird#1", "Test Bird", SendNotification, 0))
+	now = now.Add(time.Millisecond)
+	assert.True(t, tracker.TrackEventWithTimeout("test bird#1", "Test Bird", SendNotification, 0))
+	assert.False(t, tracker.TrackEventWithTimeout("test bird#0", "Test Bird", SendNotification, time.Hour))
+
+	// Plain species tracking is unaffected by the keyed events
+	assert.True(t, tracker.TrackEvent("Test Bird", SendNotification))
+}
@@ -68,8 +68,8 @@ type Processor struct {
 	pendingMutex        sync.Mutex // Mutex to protect access to pendingDetections
 	lastDogDetectionLog map[string]time.Time
 	dogDetectionMutex   sync.Mutex
-	detectionMutex      sync.RWMutex // Mutex to protect LastDogDetection and LastHumanDetection maps
-	controlChan         chan string
+	detectionMutex      sync.RWMutex       // Mutex to protect LastDogDetection and LastHumanDetection maps
+	controlChan         chan string        // Control signals of MQTT commands, protected by mqttMutex
 	JobQueue            *jobqueue.JobQueue // Queue for managing job retries
 	workerCancel        context.CancelFunc // Function to cancel worker goroutines
 	thresholdsCtx       context.Context    // Context for threshold persistence/cleanup goroutines
@@ -87,6 +87,9 @@ type Processor struct {
 
 	// Log deduplication (extracted to separate type for SRP)
 	logDedup *LogDeduplicator // Handles log deduplication logic
+
+	// False positive suppression learned from reviews
+	reviewSuppression reviewSuppressionState
 }
 
 // DynamicThreshold represents the dynamic threshold configuration for a species.
@@ -267,7 +270,6 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 		DynamicThresholds:   make(map[string]*DynamicThreshold),
 		pendingDetections:   make(map[string]PendingDetection),
 		lastDogDetectionLog: make(map[string]time.Time),
-		controlChan:         make(chan string, 10),  // Buffered channel to prevent blocking
 		JobQueue:            jobqueue.NewJobQueue(), // Initialize the job queue
 	}
 
@@ -400,6 +402,10 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 		p.startThresholdCleanup()
 	}
 
+	// Learn false positive suppression from reviews, the learner skips its work
+	// while disabled so the setting can be changed without a restart
+	p.startReviewSuppression()
+
 	// Initialize spectrogram pre-renderer if mode is "prerender"
 	if settings.Realtime.Dashboard.Spectrogram.IsPreRenderEnabled() {
 		p.initPreRenderer()
@@ -650,6 +656,33 @@ func (p *Processor) shouldFilterDetection(result datastore.Results, commonName,
 		return true, confidenceThreshold
 	}
 
+	// Check species include and exclude lists of the named RTSP stream
+	if stream, ok := p.Settings.Realtime.RTSP.StreamByID(source); ok && !stream.IsSpeciesAllowed(result.Species) {
+		if p.Settings.Debug {
+			GetLogger().Debug("species not allowed on stream",
+				logger.String("species", result.Species),
+				logger.Float32("confidence", result.Confidence),
+				logger.String("source", p.getDisplayNameForSource(source)),
+				logger.String("operation", "stream_species_filter"))
+		}
+		return true, confidenceThreshold
+	}
+
+	// Check false positives learned from reviews
+	if p.Settings.Realtime.ReviewSuppression.Enabled {
+		if suppressed, threshold := p.checkReviewSuppression(speciesLowercase, source, result.Confidence, time.Now()); suppressed {
+			if p.Settings.Debug {
+				GetLogger().Debug("Detection suppressed by false positive reviews",
+					logger.String("species", result.Species),
+					logger.Float32("confidence", result.Confidence),
+					logger.Float32("threshold", threshold),
+					logger.String("source", p.getDisplayNameForSource(source)),
+					logger.String("operation", "review_suppression_filter"))
+			}
+			return true, max(confidenceThreshold, threshold)
+		}
+	}
+
 	return false, confidenceThreshold
 }
 
@@ -1108,7 +1141,7 @@ func (p *Processor) getActionsForItem(detection *Detections) []Action {
 		var executeDefaults bool
 
 		// Add custom actions from the new structure
-		for _, actionConfig := range speciesConfig.Actions {
+		for i, actionConfig := range speciesConfig.Actions {
 			switch actionConfig.Type {
 			case "ExecuteCommand":
 				if len(actionConfig.Parameters) > 0 {
@@ -1118,8 +1151,18 @@ func (p *Processor) getActionsForItem(detection *Detections) []Action {
 					})
 				}
 			case "SendNotification":
-				// Add notification action handling
-				// ... implementation ...
+				actions = append(actions, &SendNotificationAction{
+					Settings:       p.Settings,
+					Note:           detection.Note,
+					EventTracker:   p.GetEventTracker(),
+					BirdImageCache: p.BirdImageCache,
+					Title:          actionConfig.Title,
+					Message:        actionConfig.Message,
+					Providers:      actionConfig.Providers,
+					Cooldown:       time.Duration(actionConfig.CooldownMinutes) * time.Minute,
+					CooldownKey:    fmt.Sprintf("%s#%d", speciesName, i),
+					CorrelationID:  detection.CorrelationID,
+				})
 			}
 			// If any action has ExecuteDefaults set to true, we'll include default actions
 			if actionConfig.ExecuteDefaults {
@@ -1481,6 +1524,11 @@ func (p *Processor) Shutdown() error {
 		p.thresholdsCancel()
 	}
 
+	// Stop relearning false positive suppression
+	if p.reviewSuppression.cancel != nil {
+		p.reviewSuppression.cancel()
+	}
+
 	// Flush dynamic thresholds to database before shutting down with timeout
 	if p.Settings.Realtime.DynamicThreshold.Enabled {
 		// Use context-based timeout for cleaner cancellation handling
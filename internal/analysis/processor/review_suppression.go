@@ -0,0 +1,341 @@
+// review_suppression.go: Suppression of detections repeatedly reviewed as false positives
+package processor
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Actions of a learned review suppression rule
+const (
+	ReviewSuppressionRaise = "raise" // Raise the threshold above the highest false positive
+	ReviewSuppressionHold  = "hold"  // Hold back all matching detections
+)
+
+const (
+	// defaultReviewSuppressionHourBucket is used when the configured hour bucket is out of range
+	defaultReviewSuppressionHourBucket = 3
+
+	// reviewSuppressionCheckInterval is how often the settings are checked for changes
+	reviewSuppressionCheckInterval = time.Minute
+)
+
+// ErrReviewHistoryUnavailable is returned when the datastore cannot provide the review history
+var ErrReviewHistoryUnavailable = errors.NewStd("datastore does not provide review history")
+
+// reviewHistoryStore is implemented by datastores that keep the review history
+type reviewHistoryStore interface {
+	GetReviewHistory(ctx context.Context, since time.Time) ([]datastore.ReviewHistoryEntry, error)
+	GetReviewSuppressionResets() ([]datastore.ReviewSuppressionReset, error)
+	ResetReviewSuppression(speciesName string, at time.Time) error
+}
+
+// ReviewSuppressionRule is a suppression learned from the reviews of a species at
+// one audio source and time of day. It keeps the review counts it was learned from
+// so the web UI can explain why detections are suppressed.
+type ReviewSuppressionRule struct {
+	SpeciesName                string     `json:"speciesName"` // Common name (lowercase)
+	CommonName                 string     `json:"commonName"`
+	ScientificName             string     `json:"scientificName"`
+	SourceID                   string     `json:"sourceId"` // Empty when learned from detections without a source, matches every source
+	SourceName                 string     `json:"sourceName"`
+	StartHour                  int        `json:"startHour"` // First hour of the day the rule applies to
+	EndHour                    int        `json:"endHour"`   // Hour of the day the rule ends, exclusive
+	Reviews                    int        `json:"reviews"`
+	FalsePositives             int        `json:"falsePositives"`
+	FalsePositiveRate          float64    `json:"falsePositiveRate"`
+	MaxFalsePositiveConfidence float64    `json:"maxFalsePositiveConfidence"`
+	LastReviewedAt             time.Time  `json:"lastReviewedAt"`
+	Action                     string     `json:"action"`
+	Threshold                  float64    `json:"threshold"`  // Detections at or below this confidence are suppressed
+	Suppressed                 int        `json:"suppressed"` // Predictions suppressed since the rule was first learned
+	LastSuppressed             *time.Time `json:"lastSuppressed,omitempty"`
+	Explanation                string     `json:"explanation"`
+}
+
+// reviewSuppressionKey identifies the species, source and hour bucket of a rule
+type reviewSuppressionKey struct {
+	species string
+	source  string
+	bucket  int
+}
+
+// reviewSuppressionState holds the rules learned from false positive reviews
+type reviewSuppressionState struct {
+	mu         sync.RWMutex
+	rules      map[reviewSuppressionKey]*ReviewSuppressionRule
+	hourBucket int       // Hours per bucket the rules were learned with
+	learnedAt  time.Time // When the rules were last learned
+	cancel     context.CancelFunc
+}
+
+// reviewSuppressionHourBucket returns the configured hour bucket, or the default when out of range
+func reviewSuppressionHourBucket(settings *conf.ReviewSuppressionSettings) int {
+	if settings.HourBucket < 1 || settings.HourBucket > 24 {
+		return defaultReviewSuppressionHourBucket
+	}
+	return settings.HourBucket
+}
+
+// buildReviewSuppressionRules groups the reviews by species, source and hour bucket
+// and returns a rule for every group with enough reviews and false positives.
+// Reviews made before a reset of their species, or of all species, are ignored.
+func buildReviewSuppressionRules(entries []datastore.ReviewHistoryEntry, resets []datastore.ReviewSuppressionReset, settings *conf.ReviewSuppressionSettings) map[reviewSuppressionKey]*ReviewSuppressionRule {
+	resetAt := make(map[string]time.Time, len(resets))
+	for i := range resets {
+		resetAt[resets[i].SpeciesName] = resets[i].ResetAt
+	}
+	hourBucket := reviewSuppressionHourBucket(settings)
+
+	rules := make(map[reviewSuppressionKey]*ReviewSuppressionRule)
+	for i := range entries {
+		entry := &entries[i]
+		species := strings.ToLower(entry.CommonName)
+		if !entry.ReviewedAt.After(resetAt[""]) || !entry.ReviewedAt.After(resetAt[species]) {
+			continue
+		}
+		hour, err := strconv.Atoi(strings.SplitN(entry.Time, ":", 2)[0])
+		if err != nil || hour < 0 || hour > 23 {
+			continue
+		}
+
+		key := reviewSuppressionKey{species: species, source: entry.SourceID, bucket: hour / hourBucket}
+		rule, ok := rules[key]
+		if !ok {
+			rule = &ReviewSuppressionRule{
+				SpeciesName: species,
+				SourceID:    entry.SourceID,
+				StartHour:   key.bucket * hourBucket,
+				EndHour:     min((key.bucket+1)*hourBucket, 24),
+			}
+			rules[key] = rule
+		}
+		// Entries are oldest first, the rule keeps the latest names
+		rule.CommonName, rule.ScientificName = entry.CommonName, entry.ScientificName
+		if entry.SourceName != "" {
+			rule.SourceName = entry.SourceName
+		}
+		rule.Reviews++
+		if entry.Verified == "false_positive" {
+			rule.FalsePositives++
+			rule.MaxFalsePositiveConfidence = max(rule.MaxFalsePositiveConfidence, entry.Confidence)
+		}
+		if entry.ReviewedAt.After(rule.LastReviewedAt) {
+			rule.LastReviewedAt = entry.ReviewedAt
+		}
+	}
+
+	for key, rule := range rules {
+		rule.FalsePositiveRate = float64(rule.FalsePositives) / float64(rule.Reviews)
+		if rule.Reviews < settings.MinReviews || rule.FalsePositiveRate < settings.MinFalsePositiveRate {
+			delete(rules, key)
+			continue
+		}
+		rule.Action = settings.Action
+		if rule.Action == ReviewSuppressionHold {
+			rule.Threshold = 1
+		} else {
+			rule.Action = ReviewSuppressionRaise
+			rule.Threshold = rule.MaxFalsePositiveConfidence
+		}
+		rule.Explanation = explainReviewSuppressionRule(rule)
+	}
+	return rules
+}
+
+// explainReviewSuppressionRule describes a rule for the web UI
+func explainReviewSuppressionRule(rule *ReviewSuppressionRule) string {
+	source := rule.SourceName
+	switch {
+	case rule.SourceID == "":
+		source = "any source"
+	case source == "":
+		source = rule.SourceID
+	}
+	explanation := fmt.Sprintf("%d of %d reviews of %s on %s between %02d:00 and %02d:00 were false positives",
+		rule.FalsePositives, rule.Reviews, rule.CommonName, source, rule.StartHour, rule.EndHour)
+	if rule.Action == ReviewSuppressionHold {
+		return explanation + ", all detections are held back"
+	}
+	return explanation + fmt.Sprintf(", detections up to %.0f%% confidence are suppressed", rule.Threshold*100)
+}
+
+// LearnReviewSuppression relearns the suppression rules from the review history.
+// Suppression counts of rules that are learned again are kept.
+func (p *Processor) LearnReviewSuppression(ctx context.Context) error {
+	store, ok := p.Ds.(reviewHistoryStore)
+	if !ok {
+		return ErrReviewHistoryUnavailable
+	}
+	settings := p.Settings.Realtime.ReviewSuppression
+
+	resets, err := store.GetReviewSuppressionResets()
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if settings.LookbackDays > 0 {
+		since = time.Now().AddDate(0, 0, -settings.LookbackDays)
+	}
+	entries, err := store.GetReviewHistory(ctx, since)
+	if err != nil {
+		return err
+	}
+	rules := buildReviewSuppressionRules(entries, resets, &settings)
+
+	state := &p.reviewSuppression
+	state.mu.Lock()
+	for key, rule := range rules {
+		if previous, ok := state.rules[key]; ok {
+			rule.Suppressed, rule.LastSuppressed = previous.Suppressed, previous.LastSuppressed
+		}
+	}
+	state.rules = rules
+	state.hourBucket = reviewSuppressionHourBucket(&settings)
+	state.learnedAt = time.Now()
+	state.mu.Unlock()
+
+	GetLogger().Info("Learned false positive suppression from reviews",
+		logger.Int("reviews", len(entries)),
+		logger.Int("rules", len(rules)),
+		logger.String("action", settings.Action),
+		logger.String("operation", "learn_review_suppression"))
+	return nil
+}
+
+// checkReviewSuppression reports whether a detection of a species at a source and
+// time matches a learned rule, and the threshold of the matching rule. Rules of the
+// source are preferred over rules learned from detections without a source.
+func (p *Processor) checkReviewSuppression(speciesLowercase, source string, confidence float32, now time.Time) (suppressed bool, threshold float32) {
+	state := &p.reviewSuppression
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if len(state.rules) == 0 {
+		return false, 0
+	}
+	bucket := now.Hour() / state.hourBucket
+	rule, ok := state.rules[reviewSuppressionKey{species: speciesLowercase, source: source, bucket: bucket}]
+	if !ok {
+		rule, ok = state.rules[reviewSuppressionKey{species: speciesLowercase, bucket: bucket}]
+	}
+	if !ok || (rule.Action == ReviewSuppressionRaise && float64(confidence) > rule.Threshold) {
+		return false, 0
+	}
+
+	rule.Suppressed++
+	rule.LastSuppressed = &now
+	return true, float32(rule.Threshold)
+}
+
+// GetReviewSuppressionRules returns a copy of the learned rules for API access,
+// ordered by species, source and hour, and when the rules were learned
+func (p *Processor) GetReviewSuppressionRules() (rules []ReviewSuppressionRule, learnedAt time.Time) {
+	state := &p.reviewSuppression
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	rules = make([]ReviewSuppressionRule, 0, len(state.rules))
+	for _, rule := range state.rules {
+		rules = append(rules, *rule)
+	}
+	slices.SortFunc(rules, func(a, b ReviewSuppressionRule) int {
+		return cmp.Or(
+			cmp.Compare(a.SpeciesName, b.SpeciesName),
+			cmp.Compare(a.SourceID, b.SourceID),
+			cmp.Compare(a.StartHour, b.StartHour))
+	})
+	return rules, state.learnedAt
+}
+
+// ResetReviewSuppression forgets the suppression learned for a species, identified
+// by its common name, or for all species when speciesName is empty. The reset is
+// stored so reviews made before it are not learned from again. Returns the number
+// of rules removed.
+func (p *Processor) ResetReviewSuppression(speciesName string) (int, error) {
+	store, ok := p.Ds.(reviewHistoryStore)
+	if !ok {
+		return 0, ErrReviewHistoryUnavailable
+	}
+	speciesName = strings.ToLower(strings.TrimSpace(speciesName))
+	if err := store.ResetReviewSuppression(speciesName, time.Now()); err != nil {
+		return 0, err
+	}
+
+	state := &p.reviewSuppression
+	state.mu.Lock()
+	removed := 0
+	for key := range state.rules {
+		if speciesName == "" || key.species == speciesName {
+			delete(state.rules, key)
+			removed++
+		}
+	}
+	state.mu.Unlock()
+
+	GetLogger().Info("Reset false positive suppression learned from reviews",
+		logger.String("species", speciesName),
+		logger.Int("removed_rules", removed),
+		logger.String("operation", "reset_review_suppression"))
+	return removed, nil
+}
+
+// refreshReviewSuppression relearns the rules when enabled and the refresh interval
+// has passed since the last attempt. While disabled the rules are forgotten, so
+// enabling learns them again at the next check. Returns the time of the last attempt.
+func (p *Processor) refreshReviewSuppression(ctx context.Context, lastAttempt, now time.Time) time.Time {
+	settings := p.Settings.Realtime.ReviewSuppression
+	if !settings.Enabled {
+		state := &p.reviewSuppression
+		state.mu.Lock()
+		state.rules = nil
+		state.learnedAt = time.Time{}
+		state.mu.Unlock()
+		return time.Time{}
+	}
+
+	interval := time.Duration(max(settings.RefreshMinutes, 1)) * time.Minute
+	if !lastAttempt.IsZero() && now.Sub(lastAttempt) < interval {
+		return lastAttempt
+	}
+	if err := p.LearnReviewSuppression(ctx); err != nil && ctx.Err() == nil {
+		GetLogger().Warn("Failed to learn false positive suppression from reviews",
+			logger.Error(err),
+			logger.String("operation", "learn_review_suppression"))
+	}
+	return now
+}
+
+// startReviewSuppression checks the review suppression settings every minute and
+// relearns the rules periodically, so new reviews and changes of the enabled
+// setting and refresh interval take effect without a restart
+func (p *Processor) startReviewSuppression() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.reviewSuppression.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(reviewSuppressionCheckInterval)
+		defer ticker.Stop()
+
+		var lastAttempt time.Time
+		for {
+			lastAttempt = p.refreshReviewSuppression(ctx, lastAttempt, time.Now())
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,225 @@
+// review_suppression_test.go: Tests for false positive suppression learned from reviews
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// mockReviewHistoryStore adds the review history to MockDatastore
+type mockReviewHistoryStore struct {
+	MockDatastore
+	entries []datastore.ReviewHistoryEntry
+	resets  []datastore.ReviewSuppressionReset
+}
+
+func (m *mockReviewHistoryStore) GetReviewHistory(_ context.Context, _ time.Time) ([]datastore.ReviewHistoryEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockReviewHistoryStore) GetReviewSuppressionResets() ([]datastore.ReviewSuppressionReset, error) {
+	return m.resets, nil
+}
+
+func (m *mockReviewHistoryStore) ResetReviewSuppression(speciesName string, at time.Time) error {
+	m.resets = append(m.resets, datastore.ReviewSuppressionReset{SpeciesName: speciesName, ResetAt: at})
+	return nil
+}
+
+// reviewEntries returns n reviews of a species at a source and time of day
+func reviewEntries(common, source, clock, verified string, confidence float64, n int) []datastore.ReviewHistoryEntry {
+	entries := make([]datastore.ReviewHistoryEntry, n)
+	for i := range entries {
+		entries[i] = datastore.ReviewHistoryEntry{
+			ScientificName: "Testus " + common,
+			CommonName:     common,
+			SourceID:       source,
+			SourceName:     source + " name",
+			Time:           clock,
+			Confidence:     confidence - float64(i)*0.01,
+			Verified:       verified,
+			ReviewedAt:     time.Now().Add(-time.Hour),
+		}
+	}
+	return entries
+}
+
+// testReviewSuppressionSettings returns enabled review suppression settings
+func testReviewSuppressionSettings(action string) conf.ReviewSuppressionSettings {
+	return conf.ReviewSuppressionSettings{
+		Enabled:              true,
+		Action:               action,
+		MinReviews:           5,
+		MinFalsePositiveRate: 0.8,
+		HourBucket:           3,
+		RefreshMinutes:       60,
+	}
+}
+
+func TestBuildReviewSuppressionRules(t *testing.T) {
+	t.Parallel()
+
+	var entries []datastore.ReviewHistoryEntry
+	entries = append(entries, reviewEntries("Chiffchaff", "gate", "06:10:00", "false_positive", 0.75, 5)...)
+	entries = append(entries, reviewEntries("Chiffchaff", "gate", "07:59:00", "correct", 0.9, 1)...)
+	entries = append(entries, reviewEntries("Chiffchaff", "gate", "14:00:00", "false_positive", 0.6, 2)...)
+	entries = append(entries, reviewEntries("Great Tit", "gate", "06:00:00", "false_positive", 0.7, 3)...)
+	entries = append(entries, reviewEntries("Great Tit", "gate", "06:00:00", "correct", 0.7, 3)...)
+	entries = append(entries, reviewEntries("Blackbird", "", "22:30:00", "false_positive", 0.5, 5)...)
+	entries = append(entries, reviewEntries("Robin", "gate", "06:00:00", "false_positive", 0.5, 5)...)
+	entries = append(entries, reviewEntries("Robin", "gate", "invalid", "false_positive", 0.5, 5)...)
+
+	settings := testReviewSuppressionSettings(ReviewSuppressionRaise)
+	resets := []datastore.ReviewSuppressionReset{{SpeciesName: "robin", ResetAt: time.Now().Add(-time.Minute)}}
+	rules := buildReviewSuppressionRules(entries, resets, &settings)
+	require.Len(t, rules, 2)
+
+	rule := rules[reviewSuppressionKey{species: "chiffchaff", source: "gate", bucket: 2}]
+	require.NotNil(t, rule, "reviews of the same hour bucket are learned together")
+	assert.Equal(t, 6, rule.Reviews)
+	assert.Equal(t, 5, rule.FalsePositives)
+	assert.Equal(t, 6, rule.StartHour)
+	assert.Equal(t, 9, rule.EndHour)
+	assert.InDelta(t, 0.75, rule.Threshold, 1e-9, "the threshold is raised to the highest false positive")
+	assert.Equal(t, ReviewSuppressionRaise, rule.Action)
+	assert.Equal(t, "5 of 6 reviews of Chiffchaff on gate name between 06:00 and 09:00 were false positives, "+
+		"detections up to 75% confidence are suppressed", rule.Explanation)
+
+	rule = rules[reviewSuppressionKey{species: "blackbird", bucket: 7}]
+	require.NotNil(t, rule, "reviews without a source are learned for any source")
+	assert.Equal(t, 24, rule.EndHour)
+	assert.Contains(t, rule.Explanation, "any source")
+
+	settings.Action = ReviewSuppressionHold
+	settings.HourBucket = 0
+	rules = buildReviewSuppressionRules(entries, nil, &settings)
+	rule = rules[reviewSuppressionKey{species: "robin", source: "gate", bucket: 2}]
+	require.NotNil(t, rule, "without a reset all reviews are learned from")
+	assert.InDelta(t, 1.0, rule.Threshold, 1e-9)
+	assert.Contains(t, rule.Explanation, "all detections are held back")
+}
+
+func TestReviewSuppressionFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	clock := now.Format("15:04:05")
+	store := &mockReviewHistoryStore{}
+	store.entries = append(store.entries, reviewEntries("Common Chiffchaff", "gate", clock, "false_positive", 0.75, 5)...)
+	store.entries = append(store.entries, reviewEntries("Great Tit", "", clock, "false_positive", 0.6, 5)...)
+
+	settings := &conf.Settings{}
+	settings.Realtime.ReviewSuppression = testReviewSuppressionSettings(ReviewSuppressionRaise)
+	settings.BirdNET.RangeFilter.Species = []string{"Phylloscopus collybita_Common Chiffchaff"}
+	p := &Processor{Settings: settings, Ds: store}
+	require.NoError(t, p.LearnReviewSuppression(t.Context()))
+
+	chiffchaff := func(confidence float32) datastore.Results {
+		return datastore.Results{Species: "Phylloscopus collybita_Common Chiffchaff", Confidence: confidence}
+	}
+	filtered, threshold := p.shouldFilterDetection(chiffchaff(0.72), "Common Chiffchaff", "common chiffchaff", 0.5, "gate")
+	assert.True(t, filtered, "detections at or below past false positives are suppressed")
+	assert.InDelta(t, 0.75, threshold, 1e-6)
+
+	filtered, _ = p.shouldFilterDetection(chiffchaff(0.72), "Common Chiffchaff", "common chiffchaff", 0.5, "porch")
+	assert.False(t, filtered, "rules apply to the source they were learned at")
+
+	filtered, _ = p.shouldFilterDetection(chiffchaff(0.8), "Common Chiffchaff", "common chiffchaff", 0.5, "gate")
+	assert.False(t, filtered, "detections above past false positives pass")
+
+	suppressed, _ := p.checkReviewSuppression("great tit", "porch", 0.55, now)
+	assert.True(t, suppressed, "rules without a source match every source")
+	suppressed, _ = p.checkReviewSuppression("great tit", "porch", 0.55, now.Add(12*time.Hour))
+	assert.False(t, suppressed, "rules apply to the time of day they were learned at")
+
+	settings.Realtime.ReviewSuppression.Enabled = false
+	filtered, _ = p.shouldFilterDetection(chiffchaff(0.72), "Common Chiffchaff", "common chiffchaff", 0.5, "gate")
+	assert.False(t, filtered, "the stage is skipped when disabled")
+
+	rules, learnedAt := p.GetReviewSuppressionRules()
+	require.Len(t, rules, 2)
+	assert.False(t, learnedAt.IsZero())
+	assert.Equal(t, "common chiffchaff", rules[0].SpeciesName)
+	assert.Equal(t, 1, rules[0].Suppressed)
+	require.NotNil(t, rules[0].LastSuppressed)
+
+	require.NoError(t, p.LearnReviewSuppression(t.Context()))
+	rules, _ = p.GetReviewSuppressionRules()
+	assert.Equal(t, 1, rules[0].Suppressed, "relearning keeps suppression counts")
+
+	t.Run("reset", func(t *testing.T) {
+		removed, err := p.ResetReviewSuppression("Common Chiffchaff")
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		require.NoError(t, p.LearnReviewSuppression(t.Context()))
+		rules, _ := p.GetReviewSuppressionRules()
+		require.Len(t, rules, 1, "reviews before the reset are not learned again")
+		assert.Equal(t, "great tit", rules[0].SpeciesName)
+
+		removed, err = p.ResetReviewSuppression("")
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		rules, _ = p.GetReviewSuppressionRules()
+		assert.Empty(t, rules)
+	})
+}
+
+func TestRefreshReviewSuppressionFollowsSettings(t *testing.T) {
+	t.Parallel()
+
+	store := &mockReviewHistoryStore{}
+	store.entries = reviewEntries("Great Tit", "", "06:00:00", "false_positive", 0.6, 5)
+
+	settings := &conf.Settings{}
+	settings.Realtime.ReviewSuppression = testReviewSuppressionSettings(ReviewSuppressionRaise)
+	settings.Realtime.ReviewSuppression.Enabled = false
+	p := &Processor{Settings: settings, Ds: store}
+
+	now := time.Now()
+	lastAttempt := p.refreshReviewSuppression(t.Context(), time.Time{}, now)
+	assert.True(t, lastAttempt.IsZero(), "nothing is learned while disabled")
+	rules, _ := p.GetReviewSuppressionRules()
+	assert.Empty(t, rules)
+
+	settings.Realtime.ReviewSuppression.Enabled = true
+	lastAttempt = p.refreshReviewSuppression(t.Context(), lastAttempt, now.Add(time.Minute))
+	assert.Equal(t, now.Add(time.Minute), lastAttempt, "enabling learns at the next check")
+	rules, _ = p.GetReviewSuppressionRules()
+	require.Len(t, rules, 1)
+
+	store.entries = append(store.entries, reviewEntries("Blackbird", "", "06:00:00", "false_positive", 0.5, 5)...)
+	lastAttempt = p.refreshReviewSuppression(t.Context(), lastAttempt, now.Add(30*time.Minute))
+	rules, _ = p.GetReviewSuppressionRules()
+	assert.Len(t, rules, 1, "rules are not relearned before the refresh interval")
+
+	settings.Realtime.ReviewSuppression.RefreshMinutes = 15
+	p.refreshReviewSuppression(t.Context(), lastAttempt, now.Add(30*time.Minute))
+	rules, _ = p.GetReviewSuppressionRules()
+	assert.Len(t, rules, 2, "a shorter refresh interval applies at the next check")
+
+	settings.Realtime.ReviewSuppression.Enabled = false
+	lastAttempt = p.refreshReviewSuppression(t.Context(), lastAttempt, now.Add(31*time.Minute))
+	assert.True(t, lastAttempt.IsZero())
+	rules, learnedAt := p.GetReviewSuppressionRules()
+	assert.Empty(t, rules, "disabling forgets the rules")
+	assert.True(t, learnedAt.IsZero())
+}
+
+func TestReviewSuppressionUnsupportedDatastore(t *testing.T) {
+	t.Parallel()
+
+	p := &Processor{Settings: &conf.Settings{}, Ds: &MockDatastore{}}
+	require.ErrorIs(t, p.LearnReviewSuppression(t.Context()), ErrReviewHistoryUnavailable)
+	_, err := p.ResetReviewSuppression("")
+	require.ErrorIs(t, err, ErrReviewHistoryUnavailable)
+
+	suppressed, _ := p.checkReviewSuppression("great tit", "", 0.5, time.Now())
+	assert.False(t, suppressed, "nothing is suppressed without learned rules")
+}
@@ -0,0 +1,41 @@
+// stream_species_filter_test.go: Tests for the species lists of named RTSP streams
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+func TestStreamSpeciesFilter(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.RangeFilter.Species = []string{
+		"Passer domesticus_House Sparrow",
+		"Turdus merula_Eurasian Blackbird",
+	}
+	settings.Realtime.RTSP.Streams = []conf.RTSPStreamSettings{
+		{ID: "feeder", URL: "rtsp://feeder.local/stream", Species: conf.RTSPStreamSpeciesSettings{Exclude: []string{"House Sparrow"}}},
+		{ID: "forest", URL: "rtsp://forest.local/stream", Species: conf.RTSPStreamSpeciesSettings{Include: []string{"Turdus merula"}}},
+	}
+	p := &Processor{Settings: settings}
+
+	sparrow := datastore.Results{Species: "Passer domesticus_House Sparrow", Confidence: 0.9}
+	blackbird := datastore.Results{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}
+
+	filtered, _ := p.shouldFilterDetection(sparrow, "House Sparrow", "house sparrow", 0.5, "feeder")
+	assert.True(t, filtered, "species excluded on the stream are filtered")
+	filtered, _ = p.shouldFilterDetection(blackbird, "Eurasian Blackbird", "eurasian blackbird", 0.5, "feeder")
+	assert.False(t, filtered)
+
+	filtered, _ = p.shouldFilterDetection(sparrow, "House Sparrow", "house sparrow", 0.5, "forest")
+	assert.True(t, filtered, "species not included on the stream are filtered")
+	filtered, _ = p.shouldFilterDetection(blackbird, "Eurasian Blackbird", "eurasian blackbird", 0.5, "forest")
+	assert.False(t, filtered)
+
+	filtered, _ = p.shouldFilterDetection(sparrow, "House Sparrow", "house sparrow", 0.5, "rtsp_1a2b3c4d")
+	assert.False(t, filtered, "sources without a named stream are not filtered by stream lists")
+}
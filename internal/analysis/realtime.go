@@ -17,6 +17,8 @@ import (
 	"github.com/tphakala/birdnet-go/internal/api"
 	apiv2 "github.com/tphakala/birdnet-go/internal/api/v2"
 	"github.com/tphakala/birdnet-go/internal/backup"
+	backupsources "github.com/tphakala/birdnet-go/internal/backup/sources"
+	"github.com/tphakala/birdnet-go/internal/backup/targets"
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
@@ -183,6 +185,13 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 	// Ensure the database connection is closed when the function returns.
 	defer closeDataStore(dataStore)
 
+	// Keep notification history in the database. The detach is deferred after the
+	// close above so it runs first: notifications raised during shutdown must not
+	// be written to a closed database.
+	if initializeNotificationStore(settings, dataStore) {
+		defer detachNotificationStore()
+	}
+
 	// Note: datastore monitoring is automatically started when the database is opened
 
 	// Initialize bird image cache if needed
@@ -190,10 +199,12 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 
 	// Initialize processor with analysis logger for hierarchical logging
 	proc := processor.New(settings, dataStore, bn, metrics, birdImageCache, GetLogger())
+	// MQTT commands are forwarded to the control monitor
+	proc.SetControlChannel(controlChan)
 
 	// Initialize Backup system using centralized logger
 	backupLog := logger.Global().Module("backup")
-	backupManager, backupScheduler, err := initializeBackupSystem(settings, backupLog)
+	backupManager, backupScheduler, err := initializeBackupSystem(settings, dataStore, backupLog)
 	if err != nil {
 		// Log the specific error from initialization
 		backupLog.Error("Failed to initialize backup system", logger.Error(err))
@@ -250,7 +261,8 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 	bufferManager := MustNewBufferManager(bn, quitChan, &wg)
 
 	// Start buffer monitors for each audio source only if we have active sources
-	if len(settings.Realtime.RTSP.URLs) > 0 || settings.Realtime.Audio.Source != "" {
+	rtspStreamCount := len(settings.Realtime.RTSP.ActiveStreams())
+	if rtspStreamCount > 0 || settings.Realtime.Audio.Source != "" {
 		if err := bufferManager.UpdateMonitors(sources); err != nil {
 			// Use structured logging to improve error visibility and triage
 			// Extract error details from the enhanced error if available
@@ -265,7 +277,7 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 		}
 	} else {
 		GetLogger().Warn("starting without active audio sources",
-			logger.Int("rtsp_urls", len(settings.Realtime.RTSP.URLs)),
+			logger.Int("rtsp_urls", rtspStreamCount),
 			logger.String("audio_source", settings.Realtime.Audio.Source),
 			logger.String("operation", "startup_audio_check"))
 	}
@@ -277,9 +289,9 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 	// The control monitor will start sound level monitoring if enabled in settings.
 
 	// RTSP health monitoring is now built into the FFmpeg manager
-	if len(settings.Realtime.RTSP.URLs) > 0 {
+	if rtspStreamCount > 0 {
 		GetLogger().Info("RTSP streams will be monitored by FFmpeg manager",
-			logger.Int("stream_count", len(settings.Realtime.RTSP.URLs)),
+			logger.Int("stream_count", rtspStreamCount),
 			logger.String("operation", "rtsp_monitoring_setup"))
 	}
 
@@ -293,6 +305,9 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 		startWeatherPolling(&wg, settings, dataStore, metrics, quitChan)
 	}
 
+	// start Home Assistant discovery, idle until enabled in MQTT settings
+	startHomeAssistantPublisher(&wg, quitChan, proc, dataStore)
+
 	// Telemetry endpoint initialization is handled by control monitor for hot reload support.
 	// Unlike other services that start directly here, telemetry is managed by the control monitor
 	// to allow users to dynamically enable/disable metrics and change the listen address without
@@ -401,6 +416,7 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 				// Now it's safe to close controlChan after HTTP server is down
 				log.Info("closing control channel after producers shutdown",
 					logger.String("operation", "close_control_channel"))
+				proc.SetControlChannel(nil) // waits for MQTT commands sending a signal
 				close(controlChan)
 
 				if ctx.Err() != nil {
@@ -592,6 +608,53 @@ func monitorShutdownSignals(quitChan chan struct{}) {
 	}()
 }
 
+// notificationStoreProvider is implemented by datastores that can persist notifications
+type notificationStoreProvider interface {
+	NewNotificationStore(retention time.Duration, maxEntries int) *datastore.NotificationStore
+}
+
+// initializeNotificationStore switches the notification service to the database backed
+// store when persistence is enabled. It reports whether the store was switched.
+func initializeNotificationStore(settings *conf.Settings, dataStore datastore.Interface) bool {
+	storeSettings := settings.Notification.Store
+	if !storeSettings.Persist || !notification.IsInitialized() {
+		return false
+	}
+	provider, ok := dataStore.(notificationStoreProvider)
+	if !ok {
+		return false
+	}
+
+	retention := time.Duration(storeSettings.RetentionDays) * 24 * time.Hour
+	store := provider.NewNotificationStore(retention, storeSettings.MaxEntries)
+	if err := notification.GetService().SetStore(store); err != nil {
+		GetLogger().Warn("failed to enable persistent notification store, notifications will not survive restarts",
+			logger.Error(err),
+			logger.String("operation", "notification_store_init"))
+		return false
+	}
+
+	GetLogger().Info("persistent notification store enabled",
+		logger.Int("retention_days", storeSettings.RetentionDays),
+		logger.Int("max_entries", storeSettings.MaxEntries),
+		logger.String("operation", "notification_store_init"))
+	return true
+}
+
+// detachNotificationStore moves the notification service back to an in-memory store
+// so notifications raised during shutdown do not hit a closed database.
+func detachNotificationStore() {
+	service := notification.GetService()
+	if service == nil {
+		return
+	}
+	if err := service.SetStore(notification.NewInMemoryStore(notification.DefaultMaxNotifications)); err != nil {
+		GetLogger().Warn("failed to detach persistent notification store",
+			logger.Error(err),
+			logger.String("operation", "notification_store_detach"))
+	}
+}
+
 // closeDataStore attempts to close the database connection and logs the result.
 func closeDataStore(store datastore.Interface) {
 	log := GetLogger()
@@ -1268,7 +1331,7 @@ func logHLSCleanup(err error) {
 }
 
 // initializeBackupSystem sets up the backup manager and scheduler.
-func initializeBackupSystem(settings *conf.Settings, backupLog logger.Logger) (*backup.Manager, *backup.Scheduler, error) {
+func initializeBackupSystem(settings *conf.Settings, dataStore datastore.Interface, backupLog logger.Logger) (*backup.Manager, *backup.Scheduler, error) {
 	backupLog.Info("Initializing backup system...")
 
 	stateManager, err := backup.NewStateManager(backupLog)
@@ -1316,6 +1379,13 @@ func initializeBackupSystem(settings *conf.Settings, backupLog logger.Logger) (*
 
 	// Start backupManager and backupScheduler if backup is enabled
 	if settings.Backup.Enabled {
+		// Register sources for the enabled datastores and configured targets,
+		// failures are logged per source and target. The datastore links
+		// backed up clips to their detections.
+		clipStore, _ := dataStore.(backupsources.ClipNoteStore)
+		backupsources.RegisterConfiguredSources(backupManager, settings, clipStore, backupLog)
+		targets.RegisterConfiguredTargets(backupManager, &settings.Backup, backupLog)
+
 		backupLog.Info("Starting backup manager")
 		if err := backupManager.Start(); err != nil {
 			// Log the error but don't necessarily stop initialization
@@ -1386,8 +1456,9 @@ func initializeBirdImageCacheIfNeeded(settings *conf.Settings, dataStore datasto
 func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 	log := GetLogger()
 	var sources []string
-	if len(settings.Realtime.RTSP.URLs) > 0 || settings.Realtime.Audio.Source != "" {
-		if len(settings.Realtime.RTSP.URLs) > 0 {
+	rtspStreams := settings.Realtime.RTSP.ActiveStreams()
+	if len(rtspStreams) > 0 || settings.Realtime.Audio.Source != "" {
+		if len(rtspStreams) > 0 {
 			// Register RTSP sources in the registry and get their source IDs
 			registry := myaudio.GetRegistry()
 			if registry == nil {
@@ -1395,16 +1466,13 @@ func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 			}
 
 			var failedSources []string
-			for _, url := range settings.Realtime.RTSP.URLs {
-				if url == "" {
-					log.Warn("skipping empty RTSP URL")
-					continue
-				}
+			for i := range rtspStreams {
+				url := rtspStreams[i].URL
 
-				// Register the source
+				// Register the source, unnamed streams get a generated UUID and display name
 				source, err := registry.RegisterSource(url, myaudio.SourceConfig{
-					ID:          "", // Let registry generate UUID
-					DisplayName: "", // Let auto-generation use SafeString
+					ID:          rtspStreams[i].ID,
+					DisplayName: rtspStreams[i].DisplayName(),
 					Type:        myaudio.SourceTypeRTSP,
 				})
 				if err != nil {
@@ -1423,7 +1491,7 @@ func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 			if len(failedSources) > 0 {
 				log.Warn("some RTSP sources failed to register",
 					logger.Int("failed_count", len(failedSources)),
-					logger.Int("total_count", len(settings.Realtime.RTSP.URLs)),
+					logger.Int("total_count", len(rtspStreams)),
 					logger.Any("failed_sources", failedSources))
 			}
 		}
@@ -15,6 +15,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/mqtt"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/observability"
 	"github.com/tphakala/birdnet-go/internal/privacy"
@@ -417,6 +418,21 @@ func publishSoundLevelToMQTT(soundData myaudio.SoundLevelData, proc *processor.P
 
 	LogSoundLevelMQTTPublished(topic, soundData.Source, len(soundData.OctaveBands))
 
+	// Home Assistant sound level sensors read a topic per source
+	if settings.Realtime.MQTT.HomeAssistant.Enabled {
+		sourceTopic := mqtt.NewHomeAssistantDiscovery(settings).SoundLevelTopic(soundData.Source)
+		if err := proc.PublishMQTT(ctx, sourceTopic, string(jsonData)); err != nil {
+			return errors.New(err).
+				Component("analysis.soundlevel").
+				Category(errors.CategorySoundLevel).
+				Context("operation", "publish_mqtt_source").
+				Context("topic", sourceTopic).
+				Context("source", soundData.Source).
+				Context("retryable", true).
+				Build()
+		}
+	}
+
 	// Log detailed sound level data if debug is enabled
 	// These logs are for publishing events, not realtime processing
 	if settings.Realtime.Audio.SoundLevel.Debug {
@@ -460,20 +476,68 @@ func startSoundLevelPublishers(wg *sync.WaitGroup, doneChan chan struct{}, proc
 		close(mergedQuitChan)
 	}()
 
+	// Every publisher gets its own copy of the sound level data
+	var consumers []chan myaudio.SoundLevelData
+	newConsumer := func() chan myaudio.SoundLevelData {
+		ch := make(chan myaudio.SoundLevelData, cap(soundLevelChan))
+		consumers = append(consumers, ch)
+		return ch
+	}
+
 	// Start MQTT publisher if enabled
 	if settings.Realtime.MQTT.Enabled {
-		startSoundLevelMQTTPublisherWithDone(wg, mergedQuitChan, proc, soundLevelChan)
+		startSoundLevelMQTTPublisherWithDone(wg, mergedQuitChan, proc, newConsumer())
 	}
 
 	// Start SSE publisher if API is available
 	if apiController != nil {
-		startSoundLevelSSEPublisherWithDone(wg, mergedQuitChan, apiController, soundLevelChan)
+		startSoundLevelSSEPublisherWithDone(wg, mergedQuitChan, apiController, newConsumer())
 	}
 
 	// Start metrics publisher
 	if proc != nil && proc.Metrics != nil && proc.Metrics.SoundLevel != nil {
-		startSoundLevelMetricsPublisherWithDone(wg, mergedQuitChan, proc.Metrics, soundLevelChan)
+		startSoundLevelMetricsPublisherWithDone(wg, mergedQuitChan, proc.Metrics, newConsumer())
+	}
+
+	// Start store publisher if saving sound levels is enabled and supported by the datastore
+	if settings.Realtime.Audio.SoundLevel.Storage.Enabled && proc != nil {
+		if store, ok := proc.Ds.(soundLevelStore); ok {
+			startSoundLevelStorePublisherWithDone(wg, mergedQuitChan, store, newConsumer())
+		} else {
+			getSoundLevelLogger().Warn("datastore does not support saving sound levels")
+		}
+	}
+
+	startSoundLevelFanOut(wg, mergedQuitChan, soundLevelChan, consumers)
+}
+
+// startSoundLevelFanOut starts a goroutine copying sound level data to each publisher,
+// which would otherwise compete for the data of the shared channel
+func startSoundLevelFanOut(wg *sync.WaitGroup, doneChan <-chan struct{}, soundLevelChan <-chan myaudio.SoundLevelData, consumers []chan myaudio.SoundLevelData) {
+	if len(consumers) == 0 {
+		return
 	}
+
+	wg.Go(func() {
+		for {
+			select {
+			case <-doneChan:
+				return
+			case soundData, ok := <-soundLevelChan:
+				if !ok {
+					// Publishers stop on the done channel
+					return
+				}
+				for _, ch := range consumers {
+					select {
+					case ch <- soundData:
+					default:
+						// Publisher is behind, drop data
+					}
+				}
+			}
+		}
+	})
 }
 
 // startSoundLevelMQTTPublisherWithDone starts MQTT publisher with a custom done channel
@@ -653,13 +717,15 @@ func registerSoundLevelProcessorsForActiveSources(settings *conf.Settings) error
 
 	// Register for each configured RTSP source, but prioritize actually running streams
 	configuredURLs := make(map[string]bool)
-	for _, url := range settings.Realtime.RTSP.URLs {
+	rtspStreams := settings.Realtime.RTSP.ActiveStreams()
+	for i := range rtspStreams {
+		url := rtspStreams[i].URL
 		configuredURLs[url] = true
 		totalSources++
 
 		// Get or create the RTSP source in the registry
 		registry := myaudio.GetRegistry()
-		audioSource := registry.GetOrCreateSource(url, myaudio.SourceTypeRTSP)
+		audioSource := registry.GetOrCreateRTSPSource(&rtspStreams[i])
 		if audioSource == nil {
 			errs = append(errs, errors.Newf("failed to get/create RTSP source").
 				Component("realtime-analysis").
@@ -740,7 +806,7 @@ func unregisterAllSoundLevelProcessors(settings *conf.Settings) {
 	}
 
 	// Unregister all RTSP sources
-	for _, url := range settings.Realtime.RTSP.URLs {
+	for _, url := range settings.Realtime.RTSP.StreamURLs() {
 		myaudio.UnregisterSoundLevelProcessor(url)
 		LogSoundLevelProcessorUnregistered(privacy.SanitizeRTSPUrl(url), "rtsp_stream", "analysis.soundlevel")
 	}
@@ -0,0 +1,205 @@
+package analysis
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// soundLevelRetentionInterval is how often sound levels older than the retention are deleted
+const soundLevelRetentionInterval = time.Hour
+
+// soundLevelStore is implemented by datastores saving sound levels
+type soundLevelStore interface {
+	SaveSoundLevels(levels []datastore.SoundLevel) error
+	DeleteSoundLevelsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// soundLevelBandSum accumulates the levels of one octave band within a storage interval
+type soundLevelBandSum struct {
+	centerFreq float64
+	min, max   float64
+	energy     float64 // Duration weighted energy of the band means
+}
+
+// soundLevelBucket accumulates the measurements of a source started within a storage interval
+type soundLevelBucket struct {
+	start    time.Time
+	name     string
+	duration float64 // Measured seconds
+	energy   float64 // Duration weighted energy of the A-weighted levels
+	bands    map[string]*soundLevelBandSum
+}
+
+// soundLevelRecorder downsamples sound level measurements to one sound level per source
+// and storage interval
+type soundLevelRecorder struct {
+	interval time.Duration
+	buckets  map[string]*soundLevelBucket // By source ID
+}
+
+// newSoundLevelRecorder creates a recorder combining measurements into intervals of the given length
+func newSoundLevelRecorder(interval time.Duration) *soundLevelRecorder {
+	return &soundLevelRecorder{
+		interval: interval,
+		buckets:  make(map[string]*soundLevelBucket),
+	}
+}
+
+// add adds a measurement to the interval it started in and returns the sound level of
+// the previous interval of the source when the measurement starts a new one
+func (r *soundLevelRecorder) add(data *myaudio.SoundLevelData) []datastore.SoundLevel {
+	duration := float64(data.Duration)
+	start := data.Timestamp.Add(-time.Duration(data.Duration) * time.Second).Truncate(r.interval)
+
+	var completed []datastore.SoundLevel
+	bucket := r.buckets[data.Source]
+	if bucket != nil && !bucket.start.Equal(start) {
+		completed = append(completed, r.complete(data.Source, bucket)...)
+		bucket = nil
+	}
+	if bucket == nil {
+		bucket = &soundLevelBucket{start: start, bands: make(map[string]*soundLevelBandSum)}
+		r.buckets[data.Source] = bucket
+	}
+
+	bucket.name = data.Name
+	bucket.duration += duration
+	bucket.energy += math.Pow(10, myaudio.AWeightedLevel(data.OctaveBands)/10) * duration
+	for key, band := range data.OctaveBands {
+		sum := bucket.bands[key]
+		if sum == nil {
+			sum = &soundLevelBandSum{centerFreq: band.CenterFreq, min: band.Min, max: band.Max}
+			bucket.bands[key] = sum
+		}
+		sum.min = math.Min(sum.min, band.Min)
+		sum.max = math.Max(sum.max, band.Max)
+		sum.energy += math.Pow(10, band.Mean/10) * duration
+	}
+	return completed
+}
+
+// flush returns the sound levels of the intervals which ended before a time, e.g. of
+// sources which stopped, or of all intervals for a zero time
+func (r *soundLevelRecorder) flush(before time.Time) []datastore.SoundLevel {
+	var completed []datastore.SoundLevel
+	for source, bucket := range r.buckets {
+		if before.IsZero() || bucket.start.Add(r.interval).Before(before) {
+			completed = append(completed, r.complete(source, bucket)...)
+		}
+	}
+	return completed
+}
+
+// complete removes the bucket of a source and returns its sound level
+func (r *soundLevelRecorder) complete(source string, bucket *soundLevelBucket) []datastore.SoundLevel {
+	delete(r.buckets, source)
+	if bucket.duration <= 0 {
+		return nil
+	}
+
+	bands := make(map[string]datastore.SoundLevelBand, len(bucket.bands))
+	for key, sum := range bucket.bands {
+		bands[key] = datastore.SoundLevelBand{
+			CenterFreq: sum.centerFreq,
+			Min:        roundToDecimalPlaces(sum.min, 2),
+			Max:        roundToDecimalPlaces(sum.max, 2),
+			Mean:       roundToDecimalPlaces(10*math.Log10(sum.energy/bucket.duration), 2),
+		}
+	}
+
+	level := datastore.SoundLevel{
+		SourceID:   source,
+		SourceName: bucket.name,
+		Time:       bucket.start,
+		Duration:   int(bucket.duration),
+		LAeq:       roundToDecimalPlaces(10*math.Log10(bucket.energy/bucket.duration), 2),
+	}
+	if err := level.SetBands(bands); err != nil {
+		getSoundLevelLogger().Warn("failed to encode sound level bands",
+			logger.String("source", source),
+			logger.Error(err))
+	}
+	return []datastore.SoundLevel{level}
+}
+
+// startSoundLevelStorePublisherWithDone starts a goroutine saving downsampled sound levels
+// in the datastore and deleting the sound levels older than the retention
+func startSoundLevelStorePublisherWithDone(wg *sync.WaitGroup, doneChan <-chan struct{}, store soundLevelStore, soundLevelChan <-chan myaudio.SoundLevelData) {
+	settings := conf.Setting().Realtime.Audio.SoundLevel
+	interval := time.Duration(settings.Storage.Interval) * time.Second
+	measurementInterval := time.Duration(settings.Interval) * time.Second
+	retention := settings.Storage.Retention
+	lg := getSoundLevelLogger()
+
+	wg.Go(func() {
+		lg.Info("started sound level store publisher",
+			logger.Int("interval_seconds", settings.Storage.Interval),
+			logger.Int("retention_days", retention))
+
+		recorder := newSoundLevelRecorder(interval)
+		save := func(levels []datastore.SoundLevel) {
+			if err := store.SaveSoundLevels(levels); err != nil {
+				lg.Error("failed to save sound levels",
+					logger.Error(err),
+					logger.Int("count", len(levels)))
+			}
+		}
+		deleteExpired := func() {
+			if retention <= 0 {
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			deleted, err := store.DeleteSoundLevelsBefore(ctx, time.Now().AddDate(0, 0, -retention))
+			if err != nil {
+				lg.Error("failed to delete expired sound levels", logger.Error(err))
+			} else if deleted > 0 {
+				lg.Debug("deleted expired sound levels", logger.Int64("count", deleted))
+			}
+		}
+
+		flushTicker := time.NewTicker(interval)
+		defer flushTicker.Stop()
+		retentionTicker := time.NewTicker(soundLevelRetentionInterval)
+		defer retentionTicker.Stop()
+		deleteExpired()
+
+		for {
+			select {
+			case <-doneChan:
+				// Save the partial intervals, their durations tell how much was measured
+				save(recorder.flush(time.Time{}))
+				lg.Info("stopping sound level store publisher")
+				return
+			case soundData, ok := <-soundLevelChan:
+				if !ok {
+					save(recorder.flush(time.Time{}))
+					return
+				}
+				if err := validateSoundLevelData(&soundData); err != nil {
+					if conf.Setting().Realtime.Audio.SoundLevel.Debug {
+						lg.Debug("sound level data validation failed for store",
+							logger.String("source", soundData.Source),
+							logger.Error(err))
+					}
+					continue
+				}
+				sanitized := sanitizeSoundLevelData(soundData)
+				save(recorder.add(&sanitized))
+			case now := <-flushTicker.C:
+				// Intervals of sources without new measurements are complete once the
+				// next measurement is overdue
+				save(recorder.flush(now.Add(-measurementInterval)))
+			case <-retentionTicker.C:
+				deleteExpired()
+			}
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// newTestSoundLevelData creates a 10 second measurement of one 1 kHz band ending at end
+func newTestSoundLevelData(source string, end time.Time, mean float64) *myaudio.SoundLevelData {
+	return &myaudio.SoundLevelData{
+		Timestamp: end,
+		Source:    source,
+		Name:      source + " name",
+		Duration:  10,
+		OctaveBands: map[string]myaudio.OctaveBandData{
+			"1.0_kHz": {CenterFreq: 1000, Min: mean - 5, Max: mean + 5, Mean: mean},
+		},
+	}
+}
+
+// TestSoundLevelRecorderDownsampling tests combining measurements into storage intervals
+func TestSoundLevelRecorderDownsampling(t *testing.T) {
+	recorder := newSoundLevelRecorder(time.Minute)
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Six measurements of the first minute, half of them 10 dB louder
+	for i := 1; i <= 6; i++ {
+		mean := -50.0
+		if i%2 == 0 {
+			mean = -40
+		}
+		completed := recorder.add(newTestSoundLevelData("mic", start.Add(time.Duration(i*10)*time.Second), mean))
+		assert.Empty(t, completed, "the interval is complete only when the next one starts")
+	}
+
+	completed := recorder.add(newTestSoundLevelData("mic", start.Add(70*time.Second), -60))
+	require.Len(t, completed, 1)
+	level := completed[0]
+	assert.Equal(t, "mic", level.SourceID)
+	assert.Equal(t, "mic name", level.SourceName)
+	assert.True(t, level.Time.Equal(start))
+	assert.Equal(t, 60, level.Duration)
+	// Energy average of -40 and -50 dB is -42.6 dB, A-weighting of 1 kHz is 0 dB
+	assert.InDelta(t, -42.6, level.LAeq, 0.05)
+
+	bands, err := level.GetBands()
+	require.NoError(t, err)
+	require.Contains(t, bands, "1.0_kHz")
+	assert.InDelta(t, -42.6, bands["1.0_kHz"].Mean, 0.05)
+	assert.InDelta(t, -55.0, bands["1.0_kHz"].Min, 0.001)
+	assert.InDelta(t, -35.0, bands["1.0_kHz"].Max, 0.001)
+
+	// The second interval is flushed once it ended before the given time
+	assert.Empty(t, recorder.flush(start.Add(2*time.Minute)))
+	completed = recorder.flush(start.Add(2*time.Minute + time.Second))
+	require.Len(t, completed, 1)
+	assert.True(t, completed[0].Time.Equal(start.Add(time.Minute)))
+	assert.Equal(t, 10, completed[0].Duration)
+}
+
+// TestSoundLevelRecorderSources tests that sources are recorded separately
+func TestSoundLevelRecorderSources(t *testing.T) {
+	recorder := newSoundLevelRecorder(time.Minute)
+	end := time.Date(2024, 6, 1, 12, 0, 30, 0, time.UTC)
+
+	assert.Empty(t, recorder.add(newTestSoundLevelData("mic", end, -40)))
+	assert.Empty(t, recorder.add(newTestSoundLevelData("rtsp", end, -60)))
+
+	completed := recorder.flush(time.Time{})
+	require.Len(t, completed, 2)
+	levels := map[string]float64{}
+	for _, level := range completed {
+		levels[level.SourceID] = level.LAeq
+	}
+	assert.InDelta(t, -40.0, levels["mic"], 0.01)
+	assert.InDelta(t, -60.0, levels["rtsp"], 0.01)
+	assert.Empty(t, recorder.flush(time.Time{}), "flushed intervals are not returned again")
+}
+
+// TestSoundLevelFanOut tests that every publisher receives all sound level data
+func TestSoundLevelFanOut(t *testing.T) {
+	var wg sync.WaitGroup
+	doneChan := make(chan struct{})
+	soundLevelChan := make(chan myaudio.SoundLevelData, 10)
+	consumers := []chan myaudio.SoundLevelData{
+		make(chan myaudio.SoundLevelData, 10),
+		make(chan myaudio.SoundLevelData, 10),
+	}
+	startSoundLevelFanOut(&wg, doneChan, soundLevelChan, consumers)
+
+	for i := range 3 {
+		soundLevelChan <- *newTestSoundLevelData("mic", time.Now().Add(time.Duration(i)*time.Second), -40)
+	}
+
+	for _, ch := range consumers {
+		for range 3 {
+			select {
+			case data := <-ch:
+				assert.Equal(t, "mic", data.Source)
+			case <-time.After(time.Second):
+				require.Fail(t, "publisher did not receive sound level data")
+			}
+		}
+	}
+
+	close(doneChan)
+	wg.Wait()
+}
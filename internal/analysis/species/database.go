@@ -99,7 +99,7 @@ func (t *SpeciesTracker) loadLifetimeDataFromDatabase(now time.Time) error {
 	// TODO(graceful-shutdown): Accept context parameter to enable graceful cancellation during shutdown
 	// TODO(context-timeout): Add timeout context (e.g., 60s) for database initialization operations
 	// TODO(telemetry): Report initialization timeouts/failures to internal/telemetry for monitoring
-	newSpeciesData, err := t.ds.GetNewSpeciesDetections(context.Background(), startDate, endDate, defaultDBQueryLimit, 0)
+	newSpeciesData, err := t.ds.GetNewSpeciesDetections(context.Background(), startDate, endDate, "", defaultDBQueryLimit, 0)
 	if err != nil {
 		return errors.Newf("failed to load lifetime species data from database: %w", err).
 			Component("new-species-tracker").
@@ -151,7 +151,7 @@ func (t *SpeciesTracker) loadYearlyDataFromDatabase(now time.Time) error {
 	// Use GetSpeciesFirstDetectionInPeriod for yearly tracking
 	// TODO(graceful-shutdown): Accept context parameter for graceful cancellation
 	// TODO(telemetry): Report database load failures to internal/telemetry
-	yearlyData, err := t.ds.GetSpeciesFirstDetectionInPeriod(context.Background(), startDate, endDate, defaultDBQueryLimit, 0)
+	yearlyData, err := t.ds.GetSpeciesFirstDetectionInPeriod(context.Background(), startDate, endDate, "", defaultDBQueryLimit, 0)
 	if err != nil {
 		return errors.Newf("failed to load yearly species data from database: %w", err).
 			Component("new-species-tracker").
@@ -212,7 +212,7 @@ func (t *SpeciesTracker) loadSingleSeasonData(seasonName string, now time.Time)
 	// Get first detection of each species within this season period
 	// TODO(graceful-shutdown): Accept context parameter for cancellation during shutdown
 	// TODO(telemetry): Report seasonal data load failures to internal/telemetry
-	seasonalData, err := t.ds.GetSpeciesFirstDetectionInPeriod(context.Background(), startDate, endDate, defaultDBQueryLimit, 0)
+	seasonalData, err := t.ds.GetSpeciesFirstDetectionInPeriod(context.Background(), startDate, endDate, "", defaultDBQueryLimit, 0)
 	if err != nil {
 		return nil, errors.Newf("failed to load seasonal species data from database for %s: %w", seasonName, err).
 			Component("new-species-tracker").
@@ -78,8 +78,8 @@ func getLog() logger.Logger {
 
 // SpeciesDatastore defines the minimal interface needed by SpeciesTracker
 type SpeciesDatastore interface {
-	GetNewSpeciesDetections(ctx context.Context, startDate, endDate string, limit, offset int) ([]datastore.NewSpeciesData, error)
-	GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate, endDate string, limit, offset int) ([]datastore.NewSpeciesData, error)
+	GetNewSpeciesDetections(ctx context.Context, startDate, endDate, source string, limit, offset int) ([]datastore.NewSpeciesData, error)
+	GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate, endDate, source string, limit, offset int) ([]datastore.NewSpeciesData, error)
 	// Notification history methods for BG-17 fix
 	GetActiveNotificationHistory(after time.Time) ([]datastore.NotificationHistory, error)
 	SaveNotificationHistory(history *datastore.NotificationHistory) error
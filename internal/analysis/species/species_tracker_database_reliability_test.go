@@ -108,10 +108,10 @@ func TestLoadLifetimeDataFromDatabase_CriticalReliability(t *testing.T) {
 			// Create mock datastore
 			ds := mocks.NewMockInterface(t)
 			if tt.mockError != nil {
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, tt.mockError).Maybe()
 			} else {
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(tt.mockData, nil).Maybe()
 			}
 
@@ -120,7 +120,7 @@ func TestLoadLifetimeDataFromDatabase_CriticalReliability(t *testing.T) {
 				Return([]datastore.NotificationHistory{}, nil).Maybe()
 
 			// Mock other required methods (optional based on settings)
-			ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 				Return([]datastore.NewSpeciesData{}, nil).Maybe()
 
 			// Create tracker
@@ -247,17 +247,17 @@ func TestLoadYearlyDataFromDatabase_CriticalReliability(t *testing.T) {
 
 			// Create mock datastore
 			ds := mocks.NewMockInterface(t)
-			ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 				Return([]datastore.NewSpeciesData{}, nil).Maybe()
 			// BG-17: InitFromDatabase now loads notification history
 			ds.On("GetActiveNotificationHistory", mock.AnythingOfType("time.Time")).
 				Return([]datastore.NotificationHistory{}, nil).Maybe() // Lifetime data
 
 			if tt.mockError != nil {
-				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, tt.mockError).Maybe()
 			} else {
-				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(tt.mockData, nil).Maybe()
 			}
 
@@ -411,15 +411,15 @@ func TestSyncIfNeeded_CriticalReliability(t *testing.T) {
 			}
 
 			// First call for InitFromDatabase (during tracker creation)
-			ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 				Return(initialData, nil).Once()
 
 			// Subsequent calls for sync
 			if tt.expectSync && syncError != nil {
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, syncError).Once()
 			} else if tt.expectSync {
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(syncData, nil).Once()
 			}
 
@@ -428,7 +428,7 @@ func TestSyncIfNeeded_CriticalReliability(t *testing.T) {
 				Return([]datastore.NotificationHistory{}, nil).Maybe()
 
 			// Always setup for period data calls (optional based on settings)
-			ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 				Return([]datastore.NewSpeciesData{}, nil).Maybe()
 
 			// Create tracker
@@ -639,6 +639,7 @@ func TestLoadSingleSeasonData_ErrorPaths(t *testing.T) {
 					mock.Anything,
 					mock.AnythingOfType("string"),
 					mock.AnythingOfType("string"),
+					mock.AnythingOfType("string"),
 					mock.AnythingOfType("int"),
 					mock.AnythingOfType("int"),
 				).
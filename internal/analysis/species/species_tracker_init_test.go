@@ -35,7 +35,7 @@ func TestInitFromDatabase_CriticalReliability(t *testing.T) {
 			"successful_full_initialization",
 			func(ds *mocks.MockInterface) {
 				// Lifetime data
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]datastore.NewSpeciesData{
 						{ScientificName: "Lifetime_Species_1", FirstSeenDate: "2024-01-01"},
 						{ScientificName: "Lifetime_Species_2", FirstSeenDate: "2024-02-01"},
@@ -44,13 +44,13 @@ func TestInitFromDatabase_CriticalReliability(t *testing.T) {
 				ds.On("GetActiveNotificationHistory", mock.AnythingOfType("time.Time")).
 					Return([]datastore.NotificationHistory{}, nil).Maybe()
 				// Yearly data
-				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]datastore.NewSpeciesData{
 						{ScientificName: "Yearly_Species_1", FirstSeenDate: "2024-03-01"},
 					}, nil).Once()
 				// Seasonal data (4 seasons)
 				for i := range 4 {
-					ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 						Return([]datastore.NewSpeciesData{
 							{ScientificName: fmt.Sprintf("Seasonal_Species_%d", i), FirstSeenDate: "2024-04-01"},
 						}, nil).Once()
@@ -85,7 +85,7 @@ func TestInitFromDatabase_CriticalReliability(t *testing.T) {
 		{
 			"lifetime_data_load_failure",
 			func(ds *mocks.MockInterface) {
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, fmt.Errorf("database connection lost"))
 			},
 			&conf.SpeciesTrackingSettings{
@@ -98,13 +98,13 @@ func TestInitFromDatabase_CriticalReliability(t *testing.T) {
 			"yearly_data_load_failure",
 			func(ds *mocks.MockInterface) {
 				// Lifetime succeeds
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]datastore.NewSpeciesData{}, nil).Maybe()
 				// BG-17: InitFromDatabase now loads notification history
 				ds.On("GetActiveNotificationHistory", mock.AnythingOfType("time.Time")).
 					Return([]datastore.NotificationHistory{}, nil).Maybe()
 				// Yearly fails
-				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, fmt.Errorf("query timeout"))
 			},
 			&conf.SpeciesTrackingSettings{
@@ -120,13 +120,13 @@ func TestInitFromDatabase_CriticalReliability(t *testing.T) {
 			"partial_seasonal_failure_continues",
 			func(ds *mocks.MockInterface) {
 				// Lifetime succeeds
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]datastore.NewSpeciesData{}, nil).Maybe()
 				// BG-17: InitFromDatabase now loads notification history
 				ds.On("GetActiveNotificationHistory", mock.AnythingOfType("time.Time")).
 					Return([]datastore.NotificationHistory{}, nil).Maybe()
 				// First season fails
-				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, fmt.Errorf("seasonal query failed")).Once()
 			},
 			&conf.SpeciesTrackingSettings{
@@ -141,12 +141,12 @@ func TestInitFromDatabase_CriticalReliability(t *testing.T) {
 		{
 			"empty_database_initialization",
 			func(ds *mocks.MockInterface) {
-				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetNewSpeciesDetections", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]datastore.NewSpeciesData{}, nil).Maybe()
 				// BG-17: InitFromDatabase now loads notification history
 				ds.On("GetActiveNotificationHistory", mock.AnythingOfType("time.Time")).
 					Return([]datastore.NotificationHistory{}, nil).Maybe()
-				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]datastore.NewSpeciesData{}, nil).Maybe()
 			},
 			&conf.SpeciesTrackingSettings{
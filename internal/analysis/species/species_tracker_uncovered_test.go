@@ -155,7 +155,7 @@ func TestSpeciesTracker_loadYearlyDataFromDatabase(t *testing.T) {
 			},
 		}
 
-		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, 10000, 0).
+		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10000, 0).
 			Return(yearlyData, nil).Maybe()
 
 		settings := &conf.SpeciesTrackingSettings{
@@ -182,7 +182,7 @@ func TestSpeciesTracker_loadYearlyDataFromDatabase(t *testing.T) {
 
 	t.Run("empty database preserves existing", func(t *testing.T) {
 		ds := mocks.NewMockInterface(t)
-		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, 10000, 0).
+		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10000, 0).
 			Return([]datastore.NewSpeciesData{}, nil).Maybe()
 
 		settings := &conf.SpeciesTrackingSettings{
@@ -209,7 +209,7 @@ func TestSpeciesTracker_loadYearlyDataFromDatabase(t *testing.T) {
 
 	t.Run("database error", func(t *testing.T) {
 		ds := mocks.NewMockInterface(t)
-		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, 10000, 0).
+		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10000, 0).
 			Return(nil, assert.AnError).Maybe()
 
 		settings := &conf.SpeciesTrackingSettings{
@@ -247,7 +247,7 @@ func TestSpeciesTracker_loadSeasonalDataFromDatabase(t *testing.T) {
 			},
 		}
 
-		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, 10000, 0).
+		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10000, 0).
 			Return(seasonalData, nil).Maybe()
 
 		settings := &conf.SpeciesTrackingSettings{
@@ -306,7 +306,7 @@ func TestSpeciesTracker_loadSeasonalDataFromDatabase(t *testing.T) {
 
 	t.Run("empty database preserves existing", func(t *testing.T) {
 		ds := mocks.NewMockInterface(t)
-		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, 10000, 0).
+		ds.On("GetSpeciesFirstDetectionInPeriod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10000, 0).
 			Return([]datastore.NewSpeciesData{}, nil).Maybe()
 
 		settings := &conf.SpeciesTrackingSettings{
@@ -36,7 +36,7 @@ func createTestTrackerWithMocks(t *testing.T, settings *conf.SpeciesTrackingSett
 	// Set up expectations using the expecter pattern with .Maybe() for flexibility
 	// This allows the helper to work across different test scenarios
 	mockDS.EXPECT().
-		GetNewSpeciesDetections(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		GetNewSpeciesDetections(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return([]datastore.NewSpeciesData{}, nil).
 		Maybe() // Flexible - always called but .Maybe() allows helper reuse
 
@@ -47,7 +47,7 @@ func createTestTrackerWithMocks(t *testing.T, settings *conf.SpeciesTrackingSett
 		Maybe() // Conditional - only called if NotificationSuppressionHours > 0
 
 	mockDS.EXPECT().
-		GetSpeciesFirstDetectionInPeriod(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		GetSpeciesFirstDetectionInPeriod(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return([]datastore.NewSpeciesData{}, nil).
 		Maybe() // Conditional - called for yearly and/or seasonal tracking
 
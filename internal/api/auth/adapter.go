@@ -2,27 +2,54 @@
 package auth
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"crypto/subtle"
+	"errors"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/markbates/goth/gothic"
+	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/security"
 )
 
+// pendingLoginExpiry is how long a password login of a user account may take to
+// complete the auth code exchange
+const pendingLoginExpiry = 10 * time.Minute
+
 // SecurityAdapter adapts the security package to our API auth interface
 type SecurityAdapter struct {
 	OAuth2Server *security.OAuth2Server
+	// Tokens validates scoped API tokens, nil disables API token authentication
+	Tokens *TokenManager
+	// Users authenticates user accounts, nil allows only the admin password and
+	// the OAuth user IDs from the config
+	Users *UserManager
+
+	// pendingLogins maps the auth codes and then the access tokens of password
+	// logins to the account that logged in, until the session is established
+	mu            sync.Mutex
+	pendingLogins map[string]pendingLogin
+}
+
+// pendingLogin is a user account login waiting for its session
+type pendingLogin struct {
+	username string
+	expires  time.Time
 }
 
 // NewSecurityAdapter creates a new adapter for the security package
 func NewSecurityAdapter(oauth2Server *security.OAuth2Server) *SecurityAdapter {
 	return &SecurityAdapter{
-		OAuth2Server: oauth2Server,
+		OAuth2Server:  oauth2Server,
+		pendingLogins: make(map[string]pendingLogin),
 	}
 }
 
@@ -34,12 +61,56 @@ func (a *SecurityAdapter) log() logger.Logger {
 // CheckAccess validates if a request has access to protected resources
 // Returns nil if authenticated, ErrSessionNotFound otherwise.
 func (a *SecurityAdapter) CheckAccess(c echo.Context) error {
+	if username := sessionAccount(c); username != "" {
+		return a.checkAccountAccess(c, username)
+	}
 	if a.OAuth2Server.IsUserAuthenticated(c) {
 		return nil // Success
 	}
 	return ErrSessionNotFound // Failure
 }
 
+// checkAccountAccess validates a session of a user account. Password logins hold
+// an access token, OAuth logins a provider session. Sessions of deleted and
+// disabled users are rejected. The role of a valid session is stored in the
+// context, so GetRole does not look up the user again.
+func (a *SecurityAdapter) checkAccountAccess(c echo.Context, username string) error {
+	if a.Users == nil {
+		return ErrSessionNotFound
+	}
+	user, err := a.Users.Lookup(username)
+	if err != nil {
+		a.log().Warn("Session belongs to a deleted or disabled user",
+			logger.String("username", username),
+			logger.String("ip", c.RealIP()))
+		return ErrSessionNotFound
+	}
+	if a.OAuth2Server.IsUserAuthenticated(c) || hasProviderSession(c) {
+		c.Set(CtxKeyRole, Role(user.Role))
+		return nil
+	}
+	return ErrSessionNotFound
+}
+
+// sessionAccount returns the username of the user account of the session, if any
+func sessionAccount(c echo.Context) string {
+	username, err := gothic.GetFromSession(SessionKeyAccount, c.Request())
+	if err != nil {
+		return ""
+	}
+	return username
+}
+
+// hasProviderSession reports whether the session holds an OAuth provider login
+func hasProviderSession(c echo.Context) bool {
+	for _, provider := range security.ConfigToGothProvider {
+		if value, err := gothic.GetFromSession(provider, c.Request()); err == nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAuthRequired checks if authentication is required for this request
 func (a *SecurityAdapter) IsAuthRequired(c echo.Context) bool {
 	return a.OAuth2Server.IsAuthenticationEnabled(c.RealIP())
@@ -55,7 +126,12 @@ func (a *SecurityAdapter) GetUsername(c echo.Context) string {
 		}
 	}
 
-	// 2. Fallback: Try to get username from session (for cases where middleware might not have set it, though it should)
+	// 2. User accounts are known by their username
+	if username := sessionAccount(c); username != "" {
+		return username
+	}
+
+	// 3. Fallback: Try to get username from session (for cases where middleware might not have set it, though it should)
 	//    NOTE: Removed the redundant token validation logic that was here.
 	//    If authentication succeeded, the username should already be in the context.
 	userId, err := gothic.GetFromSession("userId", c.Request())
@@ -117,6 +193,27 @@ func (a *SecurityAdapter) GetAuthMethod(c echo.Context) AuthMethod {
 	return AuthMethodNone // Use None for explicitly no authentication
 }
 
+// GetRole returns the role of the authenticated user. Sessions of user accounts
+// have the role of the account, all other authenticated requests are admins as
+// the admin password and the OAuth user IDs in the config have full access.
+func (a *SecurityAdapter) GetRole(c echo.Context) Role {
+	if role, ok := c.Get(CtxKeyRole).(Role); ok {
+		return role
+	}
+	username := sessionAccount(c)
+	if username == "" {
+		return RoleAdmin
+	}
+	if a.Users == nil {
+		return ""
+	}
+	user, err := a.Users.Lookup(username)
+	if err != nil {
+		return ""
+	}
+	return Role(user.Role)
+}
+
 // AuthMethodFromString converts a string representation to its AuthMethod constant.
 // Returns AuthMethodUnknown if the string does not match any known method.
 func AuthMethodFromString(s string) AuthMethod {
@@ -149,9 +246,10 @@ func (a *SecurityAdapter) ValidateToken(token string) error {
 }
 
 // AuthenticateBasic handles basic authentication with username/password.
-// NOTE: This application does not support multiple user accounts or authorization levels.
-// Basic authentication relies on a single, fixed username/password combination
-// configured in settings (Security.BasicAuth.ClientID and Security.BasicAuth.Password).
+// When the username belongs to a user account, the password of the account is
+// checked and the account is remembered until the session is established.
+// Otherwise the single, fixed admin username/password combination configured in
+// settings (Security.BasicAuth.ClientID and Security.BasicAuth.Password) is used.
 //
 // Username validation behavior:
 // - If ClientID is configured (non-empty): username MUST match ClientID
@@ -169,6 +267,19 @@ func (a *SecurityAdapter) AuthenticateBasic(c echo.Context, username, password s
 		return "", err
 	}
 
+	if a.Users != nil {
+		user, err := a.Users.Authenticate(username, password)
+		switch {
+		case err == nil:
+			return a.generateAccountAuthCode(user)
+		case !errors.Is(err, datastore.ErrUserNotFound):
+			a.log().Warn("Basic authentication failed for user account",
+				logger.String("username", username),
+				logger.Error(err))
+			return "", ErrInvalidCredentials
+		}
+	}
+
 	storedPassword := a.OAuth2Server.Settings.Security.BasicAuth.Password
 	storedClientID := a.OAuth2Server.Settings.Security.BasicAuth.ClientID
 
@@ -253,13 +364,49 @@ func (a *SecurityAdapter) generateAuthCodeOnSuccess(username string) (string, er
 	return authCode, nil
 }
 
+// generateAccountAuthCode generates an auth code for a user account and remembers
+// the account until its session is established.
+func (a *SecurityAdapter) generateAccountAuthCode(user *datastore.User) (string, error) {
+	authCode, err := a.generateAuthCodeOnSuccess(user.Username)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.prunePendingLogins(time.Now())
+	a.pendingLogins[authCode] = pendingLogin{username: user.Username, expires: time.Now().Add(pendingLoginExpiry)}
+	a.mu.Unlock()
+	return authCode, nil
+}
+
+// takePendingLogin removes and returns the account of a pending login
+func (a *SecurityAdapter) takePendingLogin(key string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	login, ok := a.pendingLogins[key]
+	if !ok {
+		return "", false
+	}
+	delete(a.pendingLogins, key)
+	return login.username, time.Now().Before(login.expires)
+}
+
+// prunePendingLogins removes expired pending logins, a.mu must be held
+func (a *SecurityAdapter) prunePendingLogins(now time.Time) {
+	for key, login := range a.pendingLogins {
+		if !now.Before(login.expires) {
+			delete(a.pendingLogins, key)
+		}
+	}
+}
+
 // Logout invalidates the current session/token
 func (a *SecurityAdapter) Logout(c echo.Context) error {
 	// Clear all session values
-	gothic.StoreInSession("userId", "", c.Request(), c.Response())       //nolint:errcheck // Error checking not critical during logout
-	gothic.StoreInSession("access_token", "", c.Request(), c.Response()) //nolint:errcheck // Error checking not critical during logout
-	gothic.StoreInSession("google", "", c.Request(), c.Response())       //nolint:errcheck // Error checking not critical during logout
-	gothic.StoreInSession("github", "", c.Request(), c.Response())       //nolint:errcheck // Error checking not critical during logout
+	gothic.StoreInSession("userId", "", c.Request(), c.Response())          //nolint:errcheck // Error checking not critical during logout
+	gothic.StoreInSession("access_token", "", c.Request(), c.Response())    //nolint:errcheck // Error checking not critical during logout
+	gothic.StoreInSession(SessionKeyAccount, "", c.Request(), c.Response()) //nolint:errcheck // Error checking not critical during logout
+	gothic.StoreInSession("google", "", c.Request(), c.Response())          //nolint:errcheck // Error checking not critical during logout
+	gothic.StoreInSession("github", "", c.Request(), c.Response())          //nolint:errcheck // Error checking not critical during logout
 
 	// Log out from gothic session
 	return gothic.Logout(c.Response().Writer, c.Request())
@@ -268,7 +415,17 @@ func (a *SecurityAdapter) Logout(c echo.Context) error {
 // ExchangeAuthCode exchanges an authorization code for an access token.
 // This delegates to the underlying OAuth2Server.
 func (a *SecurityAdapter) ExchangeAuthCode(ctx context.Context, code string) (string, error) {
-	return a.OAuth2Server.ExchangeAuthCode(ctx, code)
+	accessToken, err := a.OAuth2Server.ExchangeAuthCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	// Carry the user account of a password login over to the access token
+	if username, ok := a.takePendingLogin(code); ok {
+		a.mu.Lock()
+		a.pendingLogins[accessToken] = pendingLogin{username: username, expires: time.Now().Add(pendingLoginExpiry)}
+		a.mu.Unlock()
+	}
+	return accessToken, nil
 }
 
 // EstablishSession creates a new session with the given access token.
@@ -284,6 +441,16 @@ func (a *SecurityAdapter) EstablishSession(c echo.Context, accessToken string) e
 		log.Info("Successfully cleared old session before storing new token (session fixation mitigation)")
 	}
 
+	// Sessions of user accounts also remember the account for role lookups
+	if username, ok := a.takePendingLogin(accessToken); ok {
+		if err := StoreInSession(c, map[string]string{"access_token": accessToken, SessionKeyAccount: username}); err != nil {
+			log.Error("Failed to store access token in new session after logout/regeneration", logger.Error(err))
+			return err
+		}
+		log.Info("Successfully stored access token in new session", logger.String("username", username))
+		return nil
+	}
+
 	// Store access token in new session
 	if err := gothic.StoreInSession("access_token", accessToken, c.Request(), c.Response()); err != nil {
 		log.Error("Failed to store access token in new session after logout/regeneration", logger.Error(err))
@@ -294,6 +461,25 @@ func (a *SecurityAdapter) EstablishSession(c echo.Context, accessToken string) e
 	return nil
 }
 
+// StoreInSession stores several values in the session with a single save. Separate
+// gothic.StoreInSession calls each start from the session of the request, so a new
+// session would only keep the last value. Values are gzipped like gothic does.
+func StoreInSession(c echo.Context, values map[string]string) error {
+	session, _ := gothic.Store.New(c.Request(), gothic.SessionName)
+	for key, value := range values {
+		var b bytes.Buffer
+		gz := gzip.NewWriter(&b)
+		if _, err := gz.Write([]byte(value)); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		session.Values[key] = b.String()
+	}
+	return session.Save(c.Request(), c.Response())
+}
+
 // IsAuthenticated checks if a request is authenticated via any supported method.
 // Returns true if auth is bypassed (not required) or if token/session auth succeeds.
 // This centralizes authentication checking logic to avoid duplication across handlers.
@@ -307,6 +493,11 @@ func (a *SecurityAdapter) IsAuthenticated(c echo.Context) bool {
 	if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
 		parts := strings.Fields(authHeader)
 		if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+			if IsAPIToken(parts[1]) && a.Tokens != nil {
+				// API tokens authenticate public endpoints when they grant read access
+				record, err := a.Tokens.Validate(parts[1], c.RealIP())
+				return err == nil && HasScope(record, ScopeReadDetections)
+			}
 			if a.ValidateToken(parts[1]) == nil {
 				return true
 			}
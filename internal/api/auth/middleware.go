@@ -2,11 +2,13 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/security"
 )
@@ -24,11 +26,17 @@ const (
 	CtxKeyAuthMethod = "auth:authMethod"
 	// CtxKeyUsername contains the authenticated user's username (if available).
 	CtxKeyUsername = "auth:username"
+	// CtxKeyAPIToken contains the *datastore.APIToken of requests authenticated with an API token.
+	CtxKeyAPIToken = "auth:apiToken"
+	// CtxKeyRole contains the Role of the authenticated user, not set for API token requests.
+	CtxKeyRole = "auth:role"
 )
 
 // Middleware provides authentication middleware with the Service
 type Middleware struct {
 	AuthService Service
+	// Tokens validates scoped API tokens, nil disables API token authentication
+	Tokens *TokenManager
 }
 
 // NewMiddleware creates a new auth middleware
@@ -96,6 +104,7 @@ func (m *Middleware) shouldBypassAuth(c echo.Context) bool {
 			logger.String("path", c.Request().URL.Path))
 		c.Set(CtxKeyIsAuthenticated, true) // Bypassed = effectively authenticated
 		c.Set(CtxKeyAuthMethod, AuthMethodNone)
+		c.Set(CtxKeyRole, RoleAdmin)
 		return true
 	}
 	return false
@@ -125,6 +134,9 @@ func (m *Middleware) tryTokenAuth(c echo.Context) authResult {
 	}
 
 	token := strings.TrimSpace(parts[1])
+	if IsAPIToken(token) && m.Tokens != nil {
+		return m.tryAPITokenAuth(c, token, path, ip)
+	}
 	if err := m.AuthService.ValidateToken(token); err != nil {
 		return m.handleInvalidToken(c, path, ip)
 	}
@@ -138,6 +150,49 @@ func (m *Middleware) tryTokenAuth(c echo.Context) authResult {
 	// TODO: Consider adding username to AccessToken struct to support this use case.
 	c.Set(CtxKeyUsername, "")
 	c.Set(CtxKeyAuthMethod, AuthMethodToken)
+	c.Set(CtxKeyRole, RoleAdmin)
+	return authResult{handled: true, err: nil}
+}
+
+// tryAPITokenAuth authenticates a request with a scoped API token. The token must
+// grant the scope RequiredScope returns for the request.
+func (m *Middleware) tryAPITokenAuth(c echo.Context, token, path, ip string) authResult {
+	record, err := m.Tokens.Validate(token, ip)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidToken) {
+			m.log().Error("API token validation failed",
+				logger.String("path", path),
+				logger.String("ip", ip),
+				logger.Error(err))
+		}
+		return m.handleInvalidToken(c, path, ip)
+	}
+
+	scope := RequiredScope(c.Request().Method, path)
+	if !HasScope(record, scope) {
+		m.log().Warn("API token lacks required scope",
+			logger.String("path", path),
+			logger.String("ip", ip),
+			logger.String("token_prefix", record.Prefix),
+			logger.String("required_scope", scope))
+		c.Response().Header().Set("WWW-Authenticate",
+			`Bearer realm="api", error="insufficient_scope", scope="`+scope+`"`)
+		return authResult{
+			handled: true,
+			err: c.JSON(http.StatusForbidden, map[string]string{
+				"error": "API token does not grant the " + scope + " scope",
+			}),
+		}
+	}
+
+	m.log().Debug("API token authentication successful",
+		logger.String("path", path),
+		logger.String("ip", ip),
+		logger.String("token_prefix", record.Prefix))
+	c.Set(CtxKeyIsAuthenticated, true)
+	c.Set(CtxKeyUsername, "token:"+record.Name)
+	c.Set(CtxKeyAuthMethod, AuthMethodAPIKey)
+	c.Set(CtxKeyAPIToken, record)
 	return authResult{handled: true, err: nil}
 }
 
@@ -182,6 +237,13 @@ func (m *Middleware) trySessionAuth(c echo.Context) bool {
 	if err := m.AuthService.CheckAccess(c); err != nil {
 		return false
 	}
+	role := m.AuthService.GetRole(c)
+	if role == "" {
+		log.Warn("Session has no valid role",
+			logger.String("path", path),
+			logger.String("ip", ip))
+		return false
+	}
 
 	log.Debug("Session authentication successful",
 		logger.String("path", path),
@@ -189,9 +251,46 @@ func (m *Middleware) trySessionAuth(c echo.Context) bool {
 	c.Set(CtxKeyIsAuthenticated, true)
 	c.Set(CtxKeyAuthMethod, m.AuthService.GetAuthMethod(c))
 	c.Set(CtxKeyUsername, m.AuthService.GetUsername(c))
+	c.Set(CtxKeyRole, role)
 	return true
 }
 
+// RequireRole returns middleware that allows only users with at least the
+// required role. It must run after Authenticate. API tokens need one of the
+// scopes of the role, so admin routes need control or settings whatever the
+// request method. Requests without a role are rejected, Authenticate sets
+// the admin role when authentication is not required.
+func RequireRole(required Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if HasRole(c, required) {
+				return next(c)
+			}
+
+			role, _ := c.Get(CtxKeyRole).(Role)
+			GetLogger().Warn("User role does not allow this action",
+				logger.String("path", c.Request().URL.Path),
+				logger.String("ip", c.RealIP()),
+				logger.String("role", string(role)),
+				logger.String("required_role", string(required)))
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "This action requires the " + string(required) + " role",
+			})
+		}
+	}
+}
+
+// HasRole reports whether the request may perform actions of the required role,
+// with the same rules as RequireRole. Handlers use it when the required role
+// depends on the request.
+func HasRole(c echo.Context, required Role) bool {
+	if token, ok := c.Get(CtxKeyAPIToken).(*datastore.APIToken); ok {
+		return tokenHasRole(token, required)
+	}
+	role, ok := c.Get(CtxKeyRole).(Role)
+	return ok && role.Allows(required)
+}
+
 // log returns the auth package logger.
 func (m *Middleware) log() logger.Logger {
 	return GetLogger()
@@ -57,6 +57,10 @@ type Service interface {
 	// GetAuthMethod returns the authentication method used as a defined constant.
 	GetAuthMethod(c echo.Context) AuthMethod
 
+	// GetRole returns the role of the authenticated user. Requests that are not
+	// tied to a user account, such as the admin password, have the admin role.
+	GetRole(c echo.Context) Role
+
 	// ValidateToken checks if a bearer token is valid.
 	// Returns nil on success, or ErrInvalidToken on failure.
 	ValidateToken(token string) error
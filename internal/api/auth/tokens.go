@@ -0,0 +1,253 @@
+// internal/api/auth/tokens.go
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// APITokenPrefix starts every API token, it tells API tokens apart from OAuth access tokens
+const APITokenPrefix = "bng_"
+
+// API token scopes
+const (
+	ScopeReadDetections = "read-detections" // Read access to detections, analytics and other data
+	ScopeReview         = "review"          // Review, lock, comment and delete detections
+	ScopeControl        = "control"         // Control actions such as restarts, backups and debug tools
+	ScopeSettings       = "settings"        // Read and change settings and manage API tokens
+)
+
+// Scopes lists all API token scopes
+var Scopes = []string{ScopeReadDetections, ScopeReview, ScopeControl, ScopeSettings}
+
+const (
+	apiTokenRandomBytes   = 32              // Entropy of a generated token
+	apiTokenDisplayLength = 12              // Length of the token start stored for display
+	apiTokenUsedInterval  = 1 * time.Minute // Minimum time between last used updates of a token
+	maxAPITokenNameLength = 100             // Matches the size of the name column
+)
+
+// Sentinel errors for API tokens
+var (
+	ErrInsufficientScope = errors.New("token does not grant the required scope")
+	ErrInvalidScope      = errors.New("unknown API token scope")
+	ErrInvalidTokenName  = errors.New("token name must be between 1 and 100 characters")
+	ErrInvalidExpiry     = errors.New("token expiry must be in the future")
+)
+
+// APITokenStore persists API tokens, implemented by datastore.APITokenStore
+type APITokenStore interface {
+	Create(token *datastore.APIToken) error
+	List() ([]datastore.APIToken, error)
+	GetByHash(hash string) (*datastore.APIToken, error)
+	Revoke(id uint, at time.Time) error
+	MarkUsed(id uint, at time.Time, ip string) error
+}
+
+// TokenManager creates, validates and revokes API tokens
+type TokenManager struct {
+	store    APITokenStore
+	mu       sync.Mutex
+	lastUsed map[uint]time.Time // Last time the use of a token was written to the store
+}
+
+// NewTokenManager creates a token manager backed by store
+func NewTokenManager(store APITokenStore) *TokenManager {
+	return &TokenManager{
+		store:    store,
+		lastUsed: make(map[uint]time.Time),
+	}
+}
+
+// IsAPIToken reports whether token has the format of an API token
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// HashAPIToken returns the hex encoded SHA-256 hash stored for a token
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeScopes validates scopes and returns them sorted without duplicates
+func NormalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(Scopes, scope) {
+			return nil, fmt.Errorf("%w: %q, valid scopes are %s", ErrInvalidScope, scope, strings.Join(Scopes, ", "))
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized, nil
+}
+
+// Create generates a new API token. The returned token string is the only copy
+// of the token, the store only keeps its hash. A nil expiresAt never expires.
+func (tm *TokenManager) Create(name string, scopes []string, expiresAt *time.Time, createdBy string) (string, *datastore.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPITokenNameLength {
+		return "", nil, ErrInvalidTokenName
+	}
+	normalized, err := NormalizeScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, ErrInvalidExpiry
+	}
+
+	random := make([]byte, apiTokenRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	plain := APITokenPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	record := &datastore.APIToken{
+		Name:      name,
+		TokenHash: HashAPIToken(plain),
+		Prefix:    plain[:apiTokenDisplayLength],
+		Scopes:    strings.Join(normalized, ","),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := tm.store.Create(record); err != nil {
+		return "", nil, err
+	}
+
+	GetLogger().Info("API token created",
+		logger.String("name", name),
+		logger.String("prefix", record.Prefix),
+		logger.String("scopes", record.Scopes),
+		logger.String("created_by", createdBy))
+	return plain, record, nil
+}
+
+// List returns all API tokens, including expired and revoked ones
+func (tm *TokenManager) List() ([]datastore.APIToken, error) {
+	return tm.store.List()
+}
+
+// Revoke revokes an API token, requests using it are rejected from then on
+func (tm *TokenManager) Revoke(id uint) error {
+	if err := tm.store.Revoke(id, time.Now()); err != nil {
+		return err
+	}
+	tm.mu.Lock()
+	delete(tm.lastUsed, id)
+	tm.mu.Unlock()
+
+	GetLogger().Info("API token revoked", logger.Int64("token_id", int64(id)))
+	return nil
+}
+
+// Validate checks an API token and records its use by ip. It returns
+// ErrInvalidToken for unknown, expired and revoked tokens.
+func (tm *TokenManager) Validate(token, ip string) (*datastore.APIToken, error) {
+	if !IsAPIToken(token) {
+		return nil, ErrInvalidToken
+	}
+	record, err := tm.store.GetByHash(HashAPIToken(token))
+	if err != nil {
+		if errors.Is(err, datastore.ErrAPITokenNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if record.RevokedAt != nil || (record.ExpiresAt != nil && !now.Before(*record.ExpiresAt)) {
+		return nil, ErrInvalidToken
+	}
+
+	tm.markUsed(record, now, ip)
+	return record, nil
+}
+
+// markUsed writes the last use of a token to the store, at most once per
+// apiTokenUsedInterval so that busy clients do not write on every request
+func (tm *TokenManager) markUsed(record *datastore.APIToken, now time.Time, ip string) {
+	tm.mu.Lock()
+	last, ok := tm.lastUsed[record.ID]
+	if ok && now.Sub(last) < apiTokenUsedInterval {
+		tm.mu.Unlock()
+		return
+	}
+	tm.lastUsed[record.ID] = now
+	tm.mu.Unlock()
+
+	if err := tm.store.MarkUsed(record.ID, now, ip); err != nil {
+		GetLogger().Warn("Failed to record API token use",
+			logger.Int64("token_id", int64(record.ID)),
+			logger.Error(err))
+		return
+	}
+	record.LastUsedAt = &now
+	record.LastUsedIP = ip
+}
+
+// HasScope reports whether the token grants scope
+func HasScope(token *datastore.APIToken, scope string) bool {
+	return slices.Contains(token.ScopeList(), scope)
+}
+
+// RequiredScope returns the scope an API token needs for a request. Settings,
+// token and user management need the settings scope, backups need control for
+// every method as backup archives hold the database and the config, reads need
+// read-detections, changes to detections need review and all other changes need control.
+func RequiredScope(method, path string) string {
+	switch {
+	case hasPathPrefix(path, "/api/v2/settings"), hasPathPrefix(path, "/api/v2/tokens"),
+		hasPathPrefix(path, "/api/v2/users"):
+		return ScopeSettings
+	case hasPathPrefix(path, "/api/v2/backups"):
+		return ScopeControl
+	case method == http.MethodGet, method == http.MethodHead, method == http.MethodOptions:
+		return ScopeReadDetections
+	case hasPathPrefix(path, "/api/v2/detections"):
+		return ScopeReview
+	default:
+		return ScopeControl
+	}
+}
+
+// roleScopes lists the scopes of which an API token needs one to act with a role.
+// Roles that are not listed are allowed for every token.
+var roleScopes = map[Role][]string{
+	RoleReviewer: {ScopeReview, ScopeControl, ScopeSettings},
+	RoleAdmin:    {ScopeControl, ScopeSettings},
+}
+
+// tokenHasRole reports whether the token grants the actions of the required role
+func tokenHasRole(token *datastore.APIToken, required Role) bool {
+	scopes, ok := roleScopes[required]
+	if !ok {
+		return true
+	}
+	return slices.ContainsFunc(scopes, func(scope string) bool { return HasScope(token, scope) })
+}
+
+// hasPathPrefix reports whether path is prefix or a path below it
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
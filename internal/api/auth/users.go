@@ -0,0 +1,360 @@
+// internal/api/auth/users.go
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Role is the authorization level of a user
+type Role string
+
+// User roles, each role has the permissions of the roles before it
+const (
+	RoleViewer   Role = "viewer"   // Read detections, analytics and system status
+	RoleReviewer Role = "reviewer" // Verify, comment and lock detections
+	RoleAdmin    Role = "admin"    // Change settings, manage users and control the system
+)
+
+// Roles lists all roles from the least to the most privileged
+var Roles = []Role{RoleViewer, RoleReviewer, RoleAdmin}
+
+// SessionKeyAccount is the session key holding the username of a user account.
+// Sessions without it belong to the admin password or an OAuth user ID from the config.
+const SessionKeyAccount = "account"
+
+const (
+	maxUsernameLength = 100 // Matches the size of the username column
+	minPasswordLength = 8
+	maxPasswordLength = 72 // bcrypt ignores anything longer
+)
+
+// Sentinel errors for user accounts
+var (
+	ErrInvalidRole     = errors.New("unknown role")
+	ErrInvalidUsername = errors.New("username must be 1 to 100 characters without spaces or colons")
+	ErrInvalidPassword = errors.New("password must be between 8 and 72 characters")
+	ErrInvalidProvider = errors.New("unknown OAuth provider")
+	ErrUsernameTaken   = errors.New("username is already in use")
+	ErrIdentityTaken   = errors.New("OAuth identity is already mapped to a user")
+)
+
+// ParseRole validates a role name
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Roles, role) {
+		return "", fmt.Errorf("%w: %q, valid roles are viewer, reviewer and admin", ErrInvalidRole, s)
+	}
+	return role, nil
+}
+
+// Allows reports whether the role has the permissions of required
+func (r Role) Allows(required Role) bool {
+	have := slices.Index(Roles, r)
+	return have >= 0 && have >= slices.Index(Roles, required)
+}
+
+// UserStore persists user accounts, implemented by datastore.UserStore
+type UserStore interface {
+	Create(user *datastore.User) error
+	List() ([]datastore.User, error)
+	Get(id uint) (*datastore.User, error)
+	GetByUsername(username string) (*datastore.User, error)
+	GetByIdentity(provider, subject string) (*datastore.User, error)
+	Update(user *datastore.User) error
+	Delete(id uint) error
+	AddIdentity(identity *datastore.UserIdentity) error
+	DeleteIdentity(userID, identityID uint) error
+	MarkLogin(id uint, at time.Time) error
+}
+
+// UserUpdate lists the fields of a user to change, nil fields are kept
+type UserUpdate struct {
+	DisplayName *string
+	Role        *Role
+	Password    *string // An empty password removes password sign in
+	Disabled    *bool
+}
+
+// UserManager manages user accounts and authenticates them
+type UserManager struct {
+	store UserStore
+	// settings holds the admin username (Security.BasicAuth.ClientID), which
+	// user accounts cannot take since accounts are checked before the admin login
+	settings *conf.Settings
+	// dummyHash is compared against for unknown usernames so that a failed
+	// login takes the same time whether or not the user exists
+	dummyHash []byte
+}
+
+// NewUserManager creates a user manager backed by store. Settings may be nil
+// when no admin username is configured.
+func NewUserManager(store UserStore, settings *conf.Settings) *UserManager {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("birdnet-go-dummy-password"), bcrypt.DefaultCost)
+	return &UserManager{
+		store:     store,
+		settings:  settings,
+		dummyHash: dummyHash,
+	}
+}
+
+// NormalizeUsername returns the stored form of a username
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// validateUsername checks that a normalized username can be stored. Colons are
+// reserved for the "token:" names of API token requests.
+func validateUsername(username string) error {
+	if username == "" || len(username) > maxUsernameLength {
+		return ErrInvalidUsername
+	}
+	if strings.ContainsFunc(username, func(r rune) bool { return unicode.IsSpace(r) || r == ':' }) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
+
+// isAdminUsername reports whether a normalized username is the configured admin username
+func (um *UserManager) isAdminUsername(username string) bool {
+	if um.settings == nil {
+		return false
+	}
+	clientID := NormalizeUsername(um.settings.Security.BasicAuth.ClientID)
+	return clientID != "" && username == clientID
+}
+
+// hashPassword validates and hashes a password, an empty password returns an empty hash
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", ErrInvalidPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// NormalizeProvider returns the config ID of an OAuth provider, accepting both
+// config IDs such as "microsoft" and goth provider names such as "microsoftonline"
+func NormalizeProvider(provider string) (string, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	for configID, gothName := range security.ConfigToGothProvider {
+		if provider == configID || provider == gothName {
+			return configID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidProvider, provider)
+}
+
+// Create adds a user. Users without a password can only sign in with an OAuth identity.
+func (um *UserManager) Create(username, displayName string, role Role, password string) (*datastore.User, error) {
+	username = NormalizeUsername(username)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	if um.isAdminUsername(username) {
+		return nil, fmt.Errorf("%w: %q is the admin username", ErrUsernameTaken, username)
+	}
+	if _, err := ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+	if _, err := um.store.GetByUsername(username); err == nil {
+		return nil, ErrUsernameTaken
+	} else if !errors.Is(err, datastore.ErrUserNotFound) {
+		return nil, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &datastore.User{
+		Username:     username,
+		DisplayName:  strings.TrimSpace(displayName),
+		Role:         string(role),
+		PasswordHash: hash,
+	}
+	if err := um.store.Create(user); err != nil {
+		return nil, err
+	}
+
+	GetLogger().Info("User created",
+		logger.String("username", username),
+		logger.String("role", string(role)))
+	return user, nil
+}
+
+// List returns all users with their identities
+func (um *UserManager) List() ([]datastore.User, error) {
+	return um.store.List()
+}
+
+// Get returns a user with its identities
+func (um *UserManager) Get(id uint) (*datastore.User, error) {
+	return um.store.Get(id)
+}
+
+// Update changes the fields of a user set in update
+func (um *UserManager) Update(id uint, update UserUpdate) (*datastore.User, error) {
+	user, err := um.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if update.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*update.DisplayName)
+	}
+	if update.Role != nil {
+		role, err := ParseRole(string(*update.Role))
+		if err != nil {
+			return nil, err
+		}
+		user.Role = string(role)
+	}
+	if update.Password != nil {
+		hash, err := hashPassword(*update.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = hash
+	}
+	if update.Disabled != nil {
+		user.Disabled = *update.Disabled
+	}
+	if err := um.store.Update(user); err != nil {
+		return nil, err
+	}
+
+	GetLogger().Info("User updated",
+		logger.String("username", user.Username),
+		logger.String("role", user.Role),
+		logger.Bool("disabled", user.Disabled))
+	return user, nil
+}
+
+// Delete removes a user and its identities
+func (um *UserManager) Delete(id uint) error {
+	if err := um.store.Delete(id); err != nil {
+		return err
+	}
+	GetLogger().Info("User deleted", logger.Int64("user_id", int64(id)))
+	return nil
+}
+
+// AddIdentity maps an OAuth provider account to a user. Subject is the user ID
+// or email reported by the provider.
+func (um *UserManager) AddIdentity(userID uint, provider, subject string) (*datastore.UserIdentity, error) {
+	provider, err := NormalizeProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: subject is required", ErrInvalidProvider)
+	}
+	if _, err := um.store.Get(userID); err != nil {
+		return nil, err
+	}
+	if _, err := um.store.GetByIdentity(provider, subject); err == nil {
+		return nil, ErrIdentityTaken
+	} else if !errors.Is(err, datastore.ErrUserNotFound) {
+		return nil, err
+	}
+
+	identity := &datastore.UserIdentity{UserID: userID, Provider: provider, Subject: subject}
+	if err := um.store.AddIdentity(identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// RemoveIdentity removes an OAuth identity from a user
+func (um *UserManager) RemoveIdentity(userID, identityID uint) error {
+	return um.store.DeleteIdentity(userID, identityID)
+}
+
+// Authenticate checks the password of a user. It returns datastore.ErrUserNotFound
+// when no user has the username, so that the caller can fall back to the admin
+// password, and ErrInvalidCredentials for wrong passwords and disabled users.
+func (um *UserManager) Authenticate(username, password string) (*datastore.User, error) {
+	user, err := um.store.GetByUsername(NormalizeUsername(username))
+	if err != nil {
+		return nil, err
+	}
+	if user.PasswordHash == "" || user.Disabled {
+		// Spend the time of a password check so the response does not reveal the account state
+		_ = bcrypt.CompareHashAndPassword(um.dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	um.markLogin(user)
+	return user, nil
+}
+
+// LookupIdentity returns the enabled user an OAuth account is mapped to. Providers
+// report both a user ID and an email, any of the subjects may match.
+func (um *UserManager) LookupIdentity(provider string, subjects ...string) (*datastore.User, error) {
+	provider, err := NormalizeProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	for _, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+		user, err := um.store.GetByIdentity(provider, subject)
+		if errors.Is(err, datastore.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user.Disabled {
+			return nil, datastore.ErrUserNotFound
+		}
+		um.markLogin(user)
+		return user, nil
+	}
+	return nil, datastore.ErrUserNotFound
+}
+
+// Lookup returns the enabled user with the given username, used to check that
+// the account of a session still exists
+func (um *UserManager) Lookup(username string) (*datastore.User, error) {
+	user, err := um.store.GetByUsername(NormalizeUsername(username))
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, datastore.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// markLogin records a successful sign in, failures are only logged
+func (um *UserManager) markLogin(user *datastore.User) {
+	now := time.Now()
+	if err := um.store.MarkLogin(user.ID, now); err != nil {
+		GetLogger().Warn("Failed to record user login",
+			logger.String("username", user.Username),
+			logger.Error(err))
+		return
+	}
+	user.LastLoginAt = &now
+}
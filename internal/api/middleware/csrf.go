@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -38,7 +39,7 @@ type CSRFConfig struct {
 }
 
 // DefaultCSRFSkipper returns the default skipper function that exempts
-// static assets, media streams, SSE, and auth endpoints from CSRF protection.
+// static assets, media streams, SSE, auth and weather station endpoints from CSRF protection.
 func DefaultCSRFSkipper(c echo.Context) bool {
 	path := c.Request().URL.Path
 
@@ -68,14 +69,32 @@ func DefaultCSRFSkipper(c echo.Context) bool {
 		return true
 	}
 
+	// Skip for weather station pushes, which are authenticated by the secret in the
+	// path and sent by devices that cannot obtain a CSRF token
+	if strings.HasPrefix(path, WeatherStationPathPrefix) {
+		return true
+	}
+
 	// Skip for social OAuth endpoints (GET requests for OAuth flow)
 	if strings.HasPrefix(path, "/auth/") {
 		return true
 	}
 
+	// Skip for requests authenticated with an API token. The token is sent in the
+	// Authorization header, which browsers never attach to cross-site requests.
+	if isAPITokenRequest(c) {
+		return true
+	}
+
 	return false
 }
 
+// isAPITokenRequest reports whether the request carries a scoped API token as bearer token
+func isAPITokenRequest(c echo.Context) bool {
+	scheme, token, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+	return ok && strings.EqualFold(scheme, "bearer") && auth.IsAPIToken(strings.TrimSpace(token))
+}
+
 // NewCSRF creates a CSRF middleware with the given configuration.
 // If config is nil, sensible defaults are used that match the legacy implementation.
 func NewCSRF(config *CSRFConfig) echo.MiddlewareFunc {
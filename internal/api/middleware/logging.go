@@ -2,11 +2,17 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
+// WeatherStationPathPrefix is the path of weather station pushes, which carry the
+// station secret in the path and Wunderground passwords in the query string
+const WeatherStationPathPrefix = "/api/v2/weather/station/"
+
 // NewRequestLogger creates a request logging middleware using Echo 4.14.0+ RequestLoggerWithConfig.
 // This replaces the deprecated middleware.Logger().
 func NewRequestLogger() echo.MiddlewareFunc {
@@ -25,11 +31,12 @@ func NewRequestLoggerWithSkipper(skipper middleware.Skipper) echo.MiddlewareFunc
 		LogError:    true,
 		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
 			log := GetLogger()
+			uri := redactURI(v.URI)
 
 			if v.Error != nil {
 				log.Info("request",
 					logger.String("method", v.Method),
-					logger.String("uri", v.URI),
+					logger.String("uri", uri),
 					logger.Int("status", v.Status),
 					logger.String("ip", v.RemoteIP),
 					logger.Int64("latency_ms", v.Latency.Milliseconds()),
@@ -37,7 +44,7 @@ func NewRequestLoggerWithSkipper(skipper middleware.Skipper) echo.MiddlewareFunc
 			} else {
 				log.Info("request",
 					logger.String("method", v.Method),
-					logger.String("uri", v.URI),
+					logger.String("uri", uri),
 					logger.Int("status", v.Status),
 					logger.String("ip", v.RemoteIP),
 					logger.Int64("latency_ms", v.Latency.Milliseconds()))
@@ -46,3 +53,11 @@ func NewRequestLoggerWithSkipper(skipper middleware.Skipper) echo.MiddlewareFunc
 		},
 	})
 }
+
+// redactURI removes secrets from a request URI before it is logged
+func redactURI(uri string) string {
+	if strings.HasPrefix(uri, WeatherStationPathPrefix) {
+		return WeatherStationPathPrefix + "[REDACTED]"
+	}
+	return uri
+}
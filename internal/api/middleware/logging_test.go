@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		uri      string
+		expected string
+	}{
+		{"regular", "/api/v2/detections?limit=10", "/api/v2/detections?limit=10"},
+		{"station token", "/api/v2/weather/station/secret-token", "/api/v2/weather/station/[REDACTED]"},
+		{"wunderground password", "/api/v2/weather/station/secret-token?ID=x&PASSWORD=hunter2&tempf=50", "/api/v2/weather/station/[REDACTED]"},
+		{"other weather routes", "/api/v2/weather/latest", "/api/v2/weather/latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, redactURI(tt.uri))
+		})
+	}
+}
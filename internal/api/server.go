@@ -62,6 +62,8 @@ type Server struct {
 	// Auth components (owned by server, injected into controllers)
 	authService    auth.Service
 	authMiddleware echo.MiddlewareFunc
+	tokenManager   *auth.TokenManager // Scoped API tokens, nil when the datastore cannot store them
+	userManager    *auth.UserManager  // User accounts with roles, nil when the datastore cannot store them
 
 	// Channels
 	controlChan    chan string
@@ -210,6 +212,16 @@ func New(settings *conf.Settings, opts ...ServerOption) (*Server, error) {
 	return s, nil
 }
 
+// apiTokenStoreProvider is implemented by datastores that can store API tokens
+type apiTokenStoreProvider interface {
+	NewAPITokenStore() *datastore.APITokenStore
+}
+
+// userStoreProvider is implemented by datastores that can store user accounts
+type userStoreProvider interface {
+	NewUserStore() *datastore.UserStore
+}
+
 // initAuth initializes authentication service and middleware at server level.
 // This is called before setupRoutes to ensure auth is available for route protection.
 func (s *Server) initAuth() {
@@ -219,12 +231,26 @@ func (s *Server) initAuth() {
 	}
 
 	// Create auth service adapter (uses centralized logger internally)
-	s.authService = auth.NewSecurityAdapter(s.oauth2Server)
+	adapter := auth.NewSecurityAdapter(s.oauth2Server)
+	s.authService = adapter
 
 	// Create auth middleware (uses centralized logger internally)
 	authMw := auth.NewMiddleware(s.authService)
 	s.authMiddleware = authMw.Authenticate
 
+	// Accept scoped API tokens when the datastore can store them
+	if provider, ok := s.dataStore.(apiTokenStoreProvider); ok {
+		s.tokenManager = auth.NewTokenManager(provider.NewAPITokenStore())
+		adapter.Tokens = s.tokenManager
+		authMw.Tokens = s.tokenManager
+	}
+
+	// Accept user accounts with roles when the datastore can store them
+	if provider, ok := s.dataStore.(userStoreProvider); ok {
+		s.userManager = auth.NewUserManager(provider.NewUserStore(), s.oauth2Server.Settings)
+		adapter.Users = s.userManager
+	}
+
 	s.slogger.Info("Auth middleware initialized at server level")
 }
 
@@ -296,6 +322,8 @@ func (s *Server) setupRoutes() error {
 		s.metrics,
 		apiv2.WithAuthMiddleware(s.authMiddleware),
 		apiv2.WithAuthService(s.authService),
+		apiv2.WithTokenManager(s.tokenManager),
+		apiv2.WithUserManager(s.userManager),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to initialize API v2: %w", err)
@@ -633,8 +661,21 @@ func (s *Server) handleOAuthCallback(c echo.Context) error {
 		logger.String("ip", c.RealIP()),
 	)
 
+	// Identities mapped to a user account sign in as that account with its role
+	var account string
+	if s.userManager != nil {
+		if mapped, err := s.userManager.LookupIdentity(provider, user.UserID, user.Email); err == nil {
+			account = mapped.Username
+		} else if !errors.Is(err, datastore.ErrUserNotFound) {
+			s.slogger.Error("Failed to look up OAuth identity",
+				logger.String("provider", provider),
+				logger.Error(err),
+			)
+		}
+	}
+
 	// Validate user is allowed (check against configured allowed user IDs)
-	if !s.isAllowedOAuthUser(provider, user.UserID, user.Email) {
+	if account == "" && !s.isAllowedOAuthUser(provider, user.UserID, user.Email) {
 		s.slogger.Warn("OAuth user not in allowed list",
 			logger.String("provider", provider),
 			logger.String("user_id", user.UserID),
@@ -651,19 +692,23 @@ func (s *Server) handleOAuthCallback(c echo.Context) error {
 		userId = user.UserID
 	}
 
-	if err := gothic.StoreInSession("userId", userId, req, c.Response()); err != nil {
-		s.slogger.Error("Failed to store userId in session",
-			logger.Error(err),
-			logger.String("provider", provider),
-		)
+	// Store the user ID and the provider name in one save, along with the user
+	// account so that requests get the role of the account
+	values := map[string]string{
+		"userId": userId,
+		provider: user.UserID,
 	}
-
-	// Store provider name in session for later reference
-	if err := gothic.StoreInSession(provider, user.UserID, req, c.Response()); err != nil {
-		s.slogger.Error("Failed to store provider session",
+	if account != "" {
+		values[auth.SessionKeyAccount] = account
+	}
+	if err := auth.StoreInSession(c, values); err != nil {
+		s.slogger.Error("Failed to store OAuth user in session",
 			logger.Error(err),
 			logger.String("provider", provider),
 		)
+		if account != "" {
+			return c.String(http.StatusInternalServerError, "Session error during login. Please try again.")
+		}
 	}
 
 	s.slogger.Info("OAuth session established, redirecting to dashboard",
@@ -116,6 +116,7 @@ func (c *Controller) initAnalyticsRoutes() {
 	speciesGroup.GET("/summary", c.GetSpeciesSummary)
 	speciesGroup.GET("/detections/new", c.GetNewSpeciesDetections) // Renamed endpoint
 	speciesGroup.GET("/thumbnails", c.GetSpeciesThumbnails)        // Batch thumbnail endpoint
+	speciesGroup.GET("/precision", c.GetSpeciesPrecision)          // Review precision and threshold suggestions
 
 	// Time analytics routes (can be implemented later)
 	timeGroup := analyticsGroup.Group("/time")
@@ -602,7 +603,7 @@ func (c *Controller) GetSpeciesSummary(ctx echo.Context) error {
 // fetchSpeciesSummaryData fetches species summary data with timing
 func (c *Controller) fetchSpeciesSummaryData(ctx echo.Context, startDate, endDate string) ([]datastore.SpeciesSummaryData, time.Duration, error) {
 	dbStart := time.Now()
-	summaryData, err := c.DS.GetSpeciesSummaryData(ctx.Request().Context(), startDate, endDate)
+	summaryData, err := c.DS.GetSpeciesSummaryData(ctx.Request().Context(), startDate, endDate, ctx.QueryParam("source"))
 	return summaryData, time.Since(dbStart), err
 }
 
@@ -737,7 +738,7 @@ func (c *Controller) GetHourlyAnalytics(ctx echo.Context) error {
 	// TODO(context-timeout): Add configurable timeout for analytics queries to prevent resource exhaustion
 	// Example: ctx, cancel := context.WithTimeout(ctx.Request().Context(), 30*time.Second); defer cancel()
 	// TODO(telemetry): Report query timeouts and context cancellations to Sentry via internal/telemetry
-	hourlyData, err := c.DS.GetHourlyAnalyticsData(ctx.Request().Context(), date, speciesParam)
+	hourlyData, err := c.DS.GetHourlyAnalyticsData(ctx.Request().Context(), date, speciesParam, ctx.QueryParam("source"))
 	if err != nil {
 		c.logErrorIfEnabled("Failed to get hourly analytics data",
 			logger.String("date", date),
@@ -826,7 +827,7 @@ func (c *Controller) GetDailyAnalytics(ctx echo.Context) error {
 	// Get daily analytics data from the datastore
 	// TODO(context-timeout): Add configurable timeout for analytics queries to prevent resource exhaustion
 	// TODO(telemetry): Report query timeouts and context cancellations to Sentry via internal/telemetry
-	dailyData, err := c.DS.GetDailyAnalyticsData(ctx.Request().Context(), startDate, endDate, speciesParam)
+	dailyData, err := c.DS.GetDailyAnalyticsData(ctx.Request().Context(), startDate, endDate, speciesParam, ctx.QueryParam("source"))
 	if err != nil {
 		c.logErrorIfEnabled("Failed to get daily analytics data",
 			logger.String("start_date", startDate),
@@ -906,7 +907,7 @@ func (c *Controller) GetTimeOfDayDistribution(ctx echo.Context) error {
 	}
 
 	// Get hourly distribution data from the datastore
-	hourlyData, err := c.DS.GetHourlyDistribution(ctx.Request().Context(), startDate, endDate, speciesParam)
+	hourlyData, err := c.DS.GetHourlyDistribution(ctx.Request().Context(), startDate, endDate, speciesParam, ctx.QueryParam("source"))
 	if err != nil {
 		return c.HandleError(ctx, err, "Failed to get hourly distribution data", http.StatusInternalServerError)
 	}
@@ -951,7 +952,7 @@ func (c *Controller) GetNewSpeciesDetections(ctx echo.Context) error {
 	}
 
 	// Fetch data from datastore
-	newSpeciesData, err := c.DS.GetNewSpeciesDetections(ctx.Request().Context(), startDate, endDate, limit, offset)
+	newSpeciesData, err := c.DS.GetNewSpeciesDetections(ctx.Request().Context(), startDate, endDate, ctx.QueryParam("source"), limit, offset)
 	if err != nil {
 		c.logErrorIfEnabled("Failed to get new species detections",
 			logger.String("start_date", startDate),
@@ -1226,7 +1227,7 @@ func (c *Controller) processHourlyBatchSpecies(ctx echo.Context, speciesParams [
 		}
 		seen[speciesItem] = true
 
-		hourlyData, err := c.DS.GetHourlyAnalyticsData(ctx.Request().Context(), date, speciesItem)
+		hourlyData, err := c.DS.GetHourlyAnalyticsData(ctx.Request().Context(), date, speciesItem, ctx.QueryParam("source"))
 		if err != nil {
 			processingErrors = append(processingErrors, fmt.Sprintf("Failed to get hourly data for species %s: %v", speciesItem, err))
 			c.logErrorIfEnabled("Error getting hourly data for species in batch request",
@@ -1331,7 +1332,7 @@ func (c *Controller) processDailyBatchSpecies(ctx echo.Context, uniqueSpecies []
 	processingErrors = make([]string, 0)
 
 	for _, speciesItem := range uniqueSpecies {
-		dailyData, err := c.DS.GetDailyAnalyticsData(ctx.Request().Context(), startDate, endDate, speciesItem)
+		dailyData, err := c.DS.GetDailyAnalyticsData(ctx.Request().Context(), startDate, endDate, speciesItem, ctx.QueryParam("source"))
 		if err != nil {
 			processingErrors = append(processingErrors, fmt.Sprintf("Failed to get daily data for species %s: %v", speciesItem, err))
 			c.logErrorIfEnabled("Error getting daily data for species in batch request",
@@ -0,0 +1,223 @@
+// internal/api/v2/analytics_precision.go
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Precision report defaults and limits
+const (
+	defaultPrecisionBucketSize = 0.05 // Width of the confidence buckets
+	defaultTargetPrecision     = 0.9  // Precision the suggested thresholds aim for
+	defaultMinReviews          = 10   // Reviews a species needs before a threshold is suggested
+	minPrecisionBucketSize     = 0.01
+	maxPrecisionBucketSize     = 0.5
+)
+
+// Threshold suggestions of the precision report
+const (
+	ThresholdSuggestionKeep             = "keep"              // The current threshold is the suggested one
+	ThresholdSuggestionRaise            = "raise"             // Too many false positives above the current threshold
+	ThresholdSuggestionLower            = "lower"             // Reviews show the target precision below the current threshold
+	ThresholdSuggestionInsufficientData = "insufficient_data" // Fewer reviews than min_reviews
+	ThresholdSuggestionUnreachable      = "unreachable"       // No threshold reaches the target precision
+)
+
+// reviewStatsProvider is implemented by datastores that can count reviews by confidence
+type reviewStatsProvider interface {
+	GetSpeciesReviewStats(ctx context.Context, startDate, endDate, source string, bucketSize float64) ([]datastore.SpeciesReviewStats, error)
+}
+
+// PrecisionBucket is the review precision of one confidence range of a species
+type PrecisionBucket struct {
+	MinConfidence float64 `json:"min_confidence"`
+	MaxConfidence float64 `json:"max_confidence"`
+	Correct       int     `json:"correct"`
+	FalsePositive int     `json:"false_positive"`
+	Precision     float64 `json:"precision"`
+	// Precision of all reviewed detections at or above MinConfidence
+	CumulativePrecision float64 `json:"cumulative_precision"`
+}
+
+// SpeciesPrecision is the review precision of a species and its suggested threshold
+type SpeciesPrecision struct {
+	ScientificName     string            `json:"scientific_name"`
+	CommonName         string            `json:"common_name"`
+	Reviewed           int               `json:"reviewed"`
+	Correct            int               `json:"correct"`
+	FalsePositive      int               `json:"false_positive"`
+	Precision          float64           `json:"precision"`
+	Buckets            []PrecisionBucket `json:"buckets"`
+	ConfigKey          string            `json:"config_key"`        // Key of the species in realtime.species.config
+	CurrentThreshold   float64           `json:"current_threshold"` // Species threshold, or the BirdNET threshold without one
+	CustomThreshold    bool              `json:"custom_threshold"`  // Whether the species has its own threshold
+	SuggestedThreshold *float64          `json:"suggested_threshold,omitempty"`
+	Suggestion         string            `json:"suggestion"`
+}
+
+// SpeciesPrecisionReport is the response of GET /api/v2/analytics/species/precision
+type SpeciesPrecisionReport struct {
+	BucketSize      float64            `json:"bucket_size"`
+	TargetPrecision float64            `json:"target_precision"`
+	MinReviews      int                `json:"min_reviews"`
+	Species         []SpeciesPrecision `json:"species"`
+}
+
+// GetSpeciesPrecision handles GET /api/v2/analytics/species/precision
+// Computes per-species precision from review verdicts by confidence bucket and
+// suggests the lowest threshold reaching the target precision for each species.
+func (c *Controller) GetSpeciesPrecision(ctx echo.Context) error {
+	provider, ok := c.DS.(reviewStatsProvider)
+	if !ok {
+		return c.HandleError(ctx, errors.Newf("datastore does not support review statistics").
+			Category(errors.CategorySystem).
+			Component("api-analytics").
+			Build(), "Precision report is not available", http.StatusServiceUnavailable)
+	}
+
+	startDate, endDate := ctx.QueryParam("start_date"), ctx.QueryParam("end_date")
+	if err := c.validateDateRangeWithResponse(ctx, startDate, endDate, "species precision"); err != nil {
+		return err
+	}
+
+	report := SpeciesPrecisionReport{
+		BucketSize:      defaultPrecisionBucketSize,
+		TargetPrecision: defaultTargetPrecision,
+		MinReviews:      defaultMinReviews,
+	}
+	for _, p := range []struct {
+		name     string
+		target   *float64
+		min, max float64
+	}{
+		{"bucket_size", &report.BucketSize, minPrecisionBucketSize, maxPrecisionBucketSize},
+		{"target_precision", &report.TargetPrecision, 0.5, 1},
+	} {
+		if v := ctx.QueryParam(p.name); v != "" {
+			value, err := strconv.ParseFloat(v, 64)
+			if err != nil || value < p.min || value > p.max {
+				return c.HandleError(ctx, err, "Invalid "+p.name+", use a number between "+
+					strconv.FormatFloat(p.min, 'f', -1, 64)+" and "+strconv.FormatFloat(p.max, 'f', -1, 64), http.StatusBadRequest)
+			}
+			*p.target = value
+		}
+	}
+	if v := ctx.QueryParam("min_reviews"); v != "" {
+		value, err := strconv.Atoi(v)
+		if err != nil || value < 1 {
+			return c.HandleError(ctx, err, "Invalid min_reviews, use a positive number", http.StatusBadRequest)
+		}
+		report.MinReviews = value
+	}
+
+	stats, err := provider.GetSpeciesReviewStats(ctx.Request().Context(), startDate, endDate, ctx.QueryParam("source"), report.BucketSize)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get review statistics", http.StatusInternalServerError)
+	}
+
+	report.Species = make([]SpeciesPrecision, 0, len(stats))
+	for i := range stats {
+		report.Species = append(report.Species, c.newSpeciesPrecision(&stats[i], report.TargetPrecision, report.MinReviews))
+	}
+
+	c.logInfoIfEnabled("Species precision report retrieved",
+		logger.String("start_date", startDate),
+		logger.String("end_date", endDate),
+		logger.Int("species", len(report.Species)),
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// precision returns the share of correct verdicts, 0 without verdicts
+func precision(correct, falsePositive int) float64 {
+	if correct+falsePositive == 0 {
+		return 0
+	}
+	return float64(correct) / float64(correct+falsePositive)
+}
+
+// speciesThreshold returns the threshold the processor applies to a species and
+// whether it is configured for the species, mirroring Processor.shouldFilterDetection
+func (c *Controller) speciesThreshold(configKey, scientificName string) (threshold float64, custom bool) {
+	for _, key := range []string{configKey, strings.ToLower(scientificName)} {
+		if config, ok := c.Settings.Realtime.Species.Config[key]; ok && config.Threshold > 0 {
+			return config.Threshold, true
+		}
+	}
+	return c.Settings.BirdNET.Threshold, false
+}
+
+// newSpeciesPrecision computes the precision of a species by bucket and suggests
+// the lowest bucket bound above which the reviews reach the target precision
+func (c *Controller) newSpeciesPrecision(stats *datastore.SpeciesReviewStats, targetPrecision float64, minReviews int) SpeciesPrecision {
+	sp := SpeciesPrecision{
+		ScientificName: stats.ScientificName,
+		CommonName:     stats.CommonName,
+		Reviewed:       stats.Correct + stats.FalsePositive,
+		Correct:        stats.Correct,
+		FalsePositive:  stats.FalsePositive,
+		Precision:      precision(stats.Correct, stats.FalsePositive),
+		Buckets:        make([]PrecisionBucket, len(stats.Buckets)),
+		ConfigKey:      strings.ToLower(stats.CommonName),
+	}
+	sp.CurrentThreshold, sp.CustomThreshold = c.speciesThreshold(sp.ConfigKey, stats.ScientificName)
+
+	// Walk down from the most confident bucket, accumulating the verdicts above each bound
+	var correct, falsePositive int
+	suggested, suggestedMax := -1.0, 0.0
+	for i := len(stats.Buckets) - 1; i >= 0; i-- {
+		b := &stats.Buckets[i]
+		correct += b.Correct
+		falsePositive += b.FalsePositive
+		sp.Buckets[i] = PrecisionBucket{
+			MinConfidence:       roundConfidence(b.MinConfidence),
+			MaxConfidence:       roundConfidence(b.MaxConfidence),
+			Correct:             b.Correct,
+			FalsePositive:       b.FalsePositive,
+			Precision:           precision(b.Correct, b.FalsePositive),
+			CumulativePrecision: precision(correct, falsePositive),
+		}
+		if sp.Buckets[i].CumulativePrecision >= targetPrecision {
+			suggested, suggestedMax = sp.Buckets[i].MinConfidence, sp.Buckets[i].MaxConfidence
+		}
+	}
+	// Detections below the current threshold were never saved, so a bucket
+	// straddling it says nothing about the confidences below it
+	if suggested >= 0 && suggested < sp.CurrentThreshold && sp.CurrentThreshold < suggestedMax {
+		suggested = sp.CurrentThreshold
+	}
+
+	switch {
+	case sp.Reviewed < minReviews:
+		sp.Suggestion = ThresholdSuggestionInsufficientData
+	case suggested < 0:
+		sp.Suggestion = ThresholdSuggestionUnreachable
+	default:
+		sp.SuggestedThreshold = &suggested
+		switch {
+		case math.Abs(suggested-sp.CurrentThreshold) < 1e-9:
+			sp.Suggestion = ThresholdSuggestionKeep
+		case suggested > sp.CurrentThreshold:
+			sp.Suggestion = ThresholdSuggestionRaise
+		default:
+			sp.Suggestion = ThresholdSuggestionLower
+		}
+	}
+	return sp
+}
+
+// roundConfidence removes floating point noise from bucket bounds
+func roundConfidence(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,101 @@
+// analytics_precision_test.go: Tests for the species precision report
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// setupPrecisionTest creates a controller with reviewed detections of four species
+func setupPrecisionTest(t *testing.T) *Controller {
+	t.Helper()
+
+	controller, db := newSQLiteTestController(t, &datastore.Note{}, &datastore.NoteReview{})
+	controller.Settings.BirdNET.Threshold = 0.7
+	controller.Settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{
+		"great tit": {Threshold: 0.6},
+	}
+
+	review := func(scientific, common string, confidence float64, verified string, n int) {
+		for range n {
+			note := datastore.Note{Date: "2024-05-01", Time: "06:00:00", ScientificName: scientific, CommonName: common, Confidence: confidence}
+			require.NoError(t, db.Create(&note).Error)
+			require.NoError(t, db.Create(&datastore.NoteReview{NoteID: note.ID, Verified: verified}).Error)
+		}
+	}
+	// Blackbirds are reliable from 0.8 on
+	review("Turdus merula", "Eurasian Blackbird", 0.72, "false_positive", 4)
+	review("Turdus merula", "Eurasian Blackbird", 0.72, "correct", 1)
+	review("Turdus merula", "Eurasian Blackbird", 0.85, "correct", 9)
+	review("Turdus merula", "Eurasian Blackbird", 0.95, "correct", 9)
+	review("Turdus merula", "Eurasian Blackbird", 0.95, "false_positive", 1)
+	// Great tits are reliable everywhere
+	review("Parus major", "Great Tit", 0.62, "correct", 10)
+	// Warblers are never reliable
+	review("Phylloscopus collybita", "Common Chiffchaff", 0.9, "false_positive", 12)
+	// Too few reviews
+	review("Pica pica", "Eurasian Magpie", 0.9, "correct", 2)
+	return controller
+}
+
+func TestGetSpeciesPrecision(t *testing.T) {
+	controller := setupPrecisionTest(t)
+
+	report := decodeJSON[SpeciesPrecisionReport](t, doTestRequest(t, controller, controller.GetSpeciesPrecision, http.MethodGet,
+		"/api/v2/analytics/species/precision?bucket_size=0.1", ""))
+	assert.InDelta(t, 0.9, report.TargetPrecision, 1e-9)
+	assert.Equal(t, 10, report.MinReviews)
+
+	bySpecies := make(map[string]SpeciesPrecision)
+	for _, sp := range report.Species {
+		bySpecies[sp.ScientificName] = sp
+	}
+	require.Len(t, bySpecies, 4)
+
+	blackbird := bySpecies["Turdus merula"]
+	assert.Equal(t, 24, blackbird.Reviewed)
+	assert.Equal(t, "eurasian blackbird", blackbird.ConfigKey)
+	require.Len(t, blackbird.Buckets, 3)
+	assert.InDelta(t, 0.2, blackbird.Buckets[0].Precision, 1e-9)
+	assert.InDelta(t, 0.9, blackbird.Buckets[2].Precision, 1e-9)
+	assert.InDelta(t, 18.0/19.0, blackbird.Buckets[1].CumulativePrecision, 1e-9)
+	require.NotNil(t, blackbird.SuggestedThreshold)
+	assert.InDelta(t, 0.8, *blackbird.SuggestedThreshold, 1e-9)
+	assert.Equal(t, ThresholdSuggestionRaise, blackbird.Suggestion)
+	assert.False(t, blackbird.CustomThreshold)
+
+	tit := bySpecies["Parus major"]
+	assert.True(t, tit.CustomThreshold)
+	assert.InDelta(t, 0.6, tit.CurrentThreshold, 1e-9)
+	require.NotNil(t, tit.SuggestedThreshold)
+	assert.Equal(t, ThresholdSuggestionKeep, tit.Suggestion)
+
+	assert.Equal(t, ThresholdSuggestionUnreachable, bySpecies["Phylloscopus collybita"].Suggestion)
+	assert.Nil(t, bySpecies["Phylloscopus collybita"].SuggestedThreshold)
+	assert.Equal(t, ThresholdSuggestionInsufficientData, bySpecies["Pica pica"].Suggestion)
+
+	t.Run("lower target precision", func(t *testing.T) {
+		report := decodeJSON[SpeciesPrecisionReport](t, doTestRequest(t, controller, controller.GetSpeciesPrecision, http.MethodGet,
+			"/api/v2/analytics/species/precision?bucket_size=0.1&target_precision=0.75&min_reviews=1", ""))
+		for _, sp := range report.Species {
+			if sp.ScientificName == "Turdus merula" {
+				require.NotNil(t, sp.SuggestedThreshold)
+				assert.InDelta(t, 0.7, *sp.SuggestedThreshold, 1e-9, "the bucket starts at the current threshold")
+				assert.Equal(t, ThresholdSuggestionKeep, sp.Suggestion)
+			}
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"?bucket_size=0", "?bucket_size=abc", "?target_precision=1.5", "?min_reviews=0", "?start_date=2024-13-01"} {
+			rec := doTestRequest(t, controller, controller.GetSpeciesPrecision, http.MethodGet,
+				"/api/v2/analytics/species/precision"+query, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+}
@@ -80,7 +80,7 @@ func TestGetSpeciesSummary(t *testing.T) {
 
 	// Setup mock expectations
 	// Expect call with specific empty strings for no date filters
-	mockDS.On("GetSpeciesSummaryData", mock.Anything, "", "").Return(mockSummaryData, nil)
+	mockDS.On("GetSpeciesSummaryData", mock.Anything, "", "", mock.Anything).Return(mockSummaryData, nil)
 
 	// Create a request
 	req := httptest.NewRequest(http.MethodGet, "/api/v2/analytics/species/summary", http.NoBody) // Corrected path
@@ -132,7 +132,7 @@ func TestGetSpeciesSummaryDatabaseError(t *testing.T) {
 
 	// Setup mock to return a database error (like the SQL aggregate error)
 	dbError := errors.New("Error 1140 (42000): In aggregated query without GROUP BY, expression #3 of SELECT list contains nonaggregated column 'datastore.notes.species_code'")
-	mockDS.On("GetSpeciesSummaryData", mock.Anything, "", "").Return([]datastore.SpeciesSummaryData{}, dbError)
+	mockDS.On("GetSpeciesSummaryData", mock.Anything, "", "", mock.Anything).Return([]datastore.SpeciesSummaryData{}, dbError)
 
 	// Create a request
 	req := httptest.NewRequest(http.MethodGet, "/api/v2/analytics/species/summary", http.NoBody)
@@ -192,7 +192,7 @@ func TestGetSpeciesSummaryWithDateFilters(t *testing.T) {
 	}
 
 	// Setup mock expectations with date filters
-	mockDS.On("GetSpeciesSummaryData", mock.Anything, "2024-01-15", "2024-01-16").Return(mockSummaryData, nil)
+	mockDS.On("GetSpeciesSummaryData", mock.Anything, "2024-01-15", "2024-01-16", mock.Anything).Return(mockSummaryData, nil)
 
 	// Create a request with date parameters
 	req := httptest.NewRequest(http.MethodGet, "/api/v2/analytics/species/summary?start_date=2024-01-15&end_date=2024-01-16", http.NoBody)
@@ -252,7 +252,7 @@ func TestGetHourlyAnalytics(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockDS.On("GetHourlyAnalyticsData", mock.Anything, date, species).Return(mockHourlyData, nil)
+	mockDS.On("GetHourlyAnalyticsData", mock.Anything, date, species, mock.Anything).Return(mockHourlyData, nil)
 
 	// Create a request
 	req := httptest.NewRequest(http.MethodGet, "/api/v2/analytics/time/hourly?date=2023-01-01&species=Turdus+migratorius", http.NoBody)
@@ -321,7 +321,7 @@ func TestGetDailyAnalytics(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockDS.On("GetDailyAnalyticsData", mock.Anything, startDate, endDate, species).Return(mockDailyData, nil)
+	mockDS.On("GetDailyAnalyticsData", mock.Anything, startDate, endDate, species, mock.Anything).Return(mockDailyData, nil)
 
 	// Create a request
 	req := httptest.NewRequest(http.MethodGet,
@@ -401,7 +401,7 @@ func TestGetDailyAnalyticsWithoutSpecies(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockDS.On("GetDailyAnalyticsData", mock.Anything, startDate, endDate, "").Return(mockDailyData, nil)
+	mockDS.On("GetDailyAnalyticsData", mock.Anything, startDate, endDate, "", mock.Anything).Return(mockDailyData, nil)
 
 	// Create a request
 	req := httptest.NewRequest(http.MethodGet,
@@ -437,6 +437,25 @@ func TestGetDailyAnalyticsWithoutSpecies(t *testing.T) {
 	mockDS.AssertExpectations(t)
 }
 
+// TestGetDailyAnalyticsWithSource tests that the source parameter is passed to the datastore
+func TestGetDailyAnalyticsWithSource(t *testing.T) {
+	t.Parallel()
+	e, mockDS, controller := setupAnalyticsTestEnvironment(t)
+
+	mockDS.On("GetDailyAnalyticsData", mock.Anything, testDate, "2023-01-07", "", "rtsp_garden").
+		Return([]datastore.DailyAnalyticsData{{Date: "2023-01-07", Count: 12}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v2/analytics/time/daily?start_date=2023-01-01&end_date=2023-01-07&source=rtsp_garden", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v2/analytics/time/daily")
+
+	require.NoError(t, controller.GetDailyAnalytics(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockDS.AssertExpectations(t)
+}
+
 // TestGetInvalidAnalyticsRequests tests various invalid requests to analytics endpoints
 func TestGetInvalidAnalyticsRequests(t *testing.T) {
 	t.Parallel()
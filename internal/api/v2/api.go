@@ -18,6 +18,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	"github.com/tphakala/birdnet-go/internal/api/auth"
+	mw "github.com/tphakala/birdnet-go/internal/api/middleware"
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
@@ -37,17 +38,20 @@ const tunnelProviderUnknown = "unknown"
 
 // Controller manages the API routes and handlers
 type Controller struct {
-	Echo                *echo.Echo
-	Group               *echo.Group
-	DS                  datastore.Interface
-	Settings            *conf.Settings
-	BirdImageCache      *imageprovider.BirdImageCache
-	SunCalc             *suncalc.SunCalc
-	Processor           *processor.Processor
-	EBirdClient         *ebird.Client
-	TaxonomyDB          *birdnet.TaxonomyDatabase
-	controlChan         chan string
-	speciesExcludeMutex sync.RWMutex // Mutex for species exclude list operations
+	Echo            *echo.Echo
+	Group           *echo.Group
+	DS              datastore.Interface
+	Settings        *conf.Settings
+	BirdImageCache  *imageprovider.BirdImageCache
+	SunCalc         *suncalc.SunCalc
+	Processor       *processor.Processor
+	EBirdClient     *ebird.Client
+	TaxonomyDB      *birdnet.TaxonomyDatabase
+	controlChan     chan string
+	backupRestoreMu sync.Mutex     // Allows only one backup restore at a time
+	backupJob       backupJob      // Progress of the on-demand backup started through the API
+	uploads         uploadQueue    // Uploaded audio files queued for analysis
+	uploadPredictor chunkPredictor // Analyses uploads instead of the BirdNET instance when set
 	// DisableSaveSettings prevents persisting settings changes to disk.
 	// When set to true, all settings modifications remain in memory only.
 	// This is primarily used in testing but can be used in production for read-only mode.
@@ -64,6 +68,8 @@ type Controller struct {
 	// Auth related fields (injected from server via functional options)
 	authService    auth.Service        // Authentication service (injected from server)
 	authMiddleware echo.MiddlewareFunc // Authentication middleware function (injected from server)
+	tokenManager   *auth.TokenManager  // Scoped API tokens (injected from server, nil when unavailable)
+	userManager    *auth.UserManager   // User accounts with roles (injected from server, nil when unavailable)
 
 	// SSE related fields
 	sseManager *SSEManager // Manager for Server-Sent Events connections
@@ -103,6 +109,20 @@ func WithAuthService(svc auth.Service) Option {
 	}
 }
 
+// WithTokenManager sets the API token manager for the controller.
+func WithTokenManager(tm *auth.TokenManager) Option {
+	return func(c *Controller) {
+		c.tokenManager = tm
+	}
+}
+
+// WithUserManager sets the user account manager for the controller.
+func WithUserManager(um *auth.UserManager) Option {
+	return func(c *Controller) {
+		c.userManager = um
+	}
+}
+
 // parseIPFromHeader attempts to parse a valid IP from a header value.
 // Returns the IP string if valid, empty string otherwise.
 func parseIPFromHeader(headerValue string) string {
@@ -403,11 +423,17 @@ func (c *Controller) LoggingMiddleware() echo.MiddlewareFunc {
 			isTunneled, _ := ctx.Get("is_tunneled").(bool)
 			tunnelProvider, _ := ctx.Get("tunnel_provider").(string)
 
+			// Weather station pushes carry their secret in the path and query
+			path, query := req.URL.Path, req.URL.RawQuery
+			if strings.HasPrefix(path, mw.WeatherStationPathPrefix) {
+				path, query = mw.WeatherStationPathPrefix+"[REDACTED]", ""
+			}
+
 			// Log the request with structured data
 			fields := []logger.Field{
 				logger.String("method", req.Method),
-				logger.String("path", req.URL.Path),
-				logger.String("query", req.URL.RawQuery),
+				logger.String("path", path),
+				logger.String("query", query),
 				logger.Int("status", res.Status),
 				logger.String("ip", ctx.RealIP()), // Uses custom extractor
 				logger.Bool("tunneled", isTunneled),
@@ -458,6 +484,12 @@ func (c *Controller) initRoutes() {
 		{"debug routes", c.initDebugRoutes},
 		{"species routes", c.initSpeciesRoutes},
 		{"dynamic threshold routes", c.initDynamicThresholdRoutes},
+		{"review suppression routes", c.initReviewSuppressionRoutes},
+		{"sound level routes", c.initSoundLevelRoutes},
+		{"backup routes", c.initBackupRoutes},
+		{"api token routes", c.initTokenRoutes},
+		{"user routes", c.initUserRoutes},
+		{"upload analysis routes", c.initUploadRoutes},
 	}
 
 	for _, initializer := range routeInitializers {
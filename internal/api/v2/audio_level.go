@@ -403,8 +403,9 @@ func (c *Controller) getAudioCardSource(registry *myaudio.AudioSourceRegistry) *
 
 // addRTSPSourcesToLevels adds all configured RTSP sources to the levels map.
 func (c *Controller) addRTSPSourcesToLevels(registry *myaudio.AudioSourceRegistry, levels map[string]myaudio.AudioLevelData, isAuthenticated bool) {
-	for i, url := range c.Settings.Realtime.RTSP.URLs {
-		source := registry.GetOrCreateSource(url, myaudio.SourceTypeRTSP)
+	rtspStreams := c.Settings.Realtime.RTSP.ActiveStreams()
+	for i := range rtspStreams {
+		source := registry.GetOrCreateRTSPSource(&rtspStreams[i])
 		if source == nil {
 			continue
 		}
@@ -59,6 +59,7 @@ type AuthStatus struct {
 	Authenticated bool   `json:"authenticated"`
 	Username      string `json:"username,omitempty"`
 	Method        string `json:"auth_method,omitempty"`
+	Role          string `json:"role,omitempty"` // viewer, reviewer or admin, empty for API tokens
 }
 
 // initAuthRoutes registers all authentication-related API endpoints
@@ -312,18 +313,21 @@ func (c *Controller) GetAuthStatus(ctx echo.Context) error {
 	// relied on specific string literals. The middleware now sets the context
 	// value using the string representation of the new AuthMethod constants.
 	authMethod := stringFromCtx(ctx, auth.CtxKeyAuthMethod, auth.AuthMethodUnknown.String())
+	role, _ := ctx.Get(auth.CtxKeyRole).(auth.Role)
 
 	// Construct the response based on context values
 	status := AuthStatus{
 		Authenticated: isAuthenticated,
 		Username:      username,
 		Method:        authMethod,
+		Role:          string(role),
 	}
 
 	c.logInfoIfEnabled("Auth status check",
 		logger.Bool("authenticated", status.Authenticated),
 		logger.String("username", status.Username),
 		logger.String("method", status.Method),
+		logger.String("role", status.Role),
 		logger.String("ip", ctx.RealIP()),
 		logger.String("path", ctx.Request().URL.Path),
 		logger.String("user_agent", ctx.Request().Header.Get("User-Agent")),
@@ -0,0 +1,629 @@
+// internal/api/v2/backup.go
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/securefs"
+)
+
+// RestoreBackupRequest is the request body for restoring a backup
+type RestoreBackupRequest struct {
+	Target       string `json:"target"`        // Only fetch the backup from this target
+	DryRun       bool   `json:"dry_run"`       // Verify the backup without writing anything
+	OutputDir    string `json:"output_dir"`    // Absolute directory below a local backup target or the data directory, required unless dry run
+	SkipDatabase bool   `json:"skip_database"` // Do not restore the database
+	SkipConfig   bool   `json:"skip_config"`   // Do not restore the configuration file
+}
+
+// BackupResponse is a stored backup in the backup list
+type BackupResponse struct {
+	backup.Metadata
+	Target string `json:"target"` // Name of the target storing the backup
+}
+
+// BackupTargetList lists the backups stored in one target
+type BackupTargetList struct {
+	Target  string           `json:"target"`
+	Backups []BackupResponse `json:"backups"`
+}
+
+// BackupListResponse is the response of the backup list endpoint
+type BackupListResponse struct {
+	Targets []BackupTargetList `json:"targets"`
+	Total   int                `json:"total"`
+	Errors  []string           `json:"errors,omitempty"` // Targets that could not be listed
+}
+
+// BackupStatsResponse contains the backup statistics of a target
+type BackupStatsResponse struct {
+	TotalBackups     int       `json:"total_backups"`
+	DailyBackups     int       `json:"daily_backups"`
+	WeeklyBackups    int       `json:"weekly_backups"`
+	OldestBackup     time.Time `json:"oldest_backup"`
+	NewestBackup     time.Time `json:"newest_backup"`
+	TotalSize        int64     `json:"total_size"`
+	LastBackupStatus string    `json:"last_backup_status"`
+	LastBackupTime   time.Time `json:"last_backup_time"`
+}
+
+// BackupStatusResponse reports whether an on-demand backup is running
+type BackupStatusResponse struct {
+	Running bool              `json:"running"`
+	Events  []backup.Progress `json:"events"` // Progress of the running or last on-demand backup
+}
+
+// backupProgressBufferSize is the number of progress events buffered per SSE client
+const backupProgressBufferSize = 32
+
+// backupJob tracks the on-demand backup started through the API and fans out
+// its progress to the SSE clients following it
+type backupJob struct {
+	mu          sync.Mutex
+	running     bool
+	events      []backup.Progress
+	subscribers map[chan backup.Progress]struct{}
+}
+
+// start marks a backup as running, it returns false when one is already running
+func (j *backupJob) start() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.running {
+		return false
+	}
+	j.running = true
+	j.events = nil
+	return true
+}
+
+// publish records a progress event and sends it to the subscribers, slow
+// subscribers miss events instead of blocking the backup
+func (j *backupJob) publish(p backup.Progress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.events = append(j.events, p)
+	for ch := range j.subscribers {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// finish marks the backup as done and closes the subscriber channels. A failure
+// the backup did not report itself, e.g. a scheduled backup holding the lock,
+// is published as a failed event.
+func (j *backupJob) finish(err error) {
+	if err != nil {
+		j.mu.Lock()
+		reported := len(j.events) > 0 && j.events[len(j.events)-1].Stage == backup.ProgressFailed
+		j.mu.Unlock()
+		if !reported {
+			j.publish(backup.Progress{Stage: backup.ProgressFailed, Error: err.Error(), Time: time.Now()})
+		}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+	for ch := range j.subscribers {
+		close(ch)
+	}
+	j.subscribers = nil
+}
+
+// subscribe returns the events so far and, while a backup is running, a channel
+// receiving the following events. The channel is closed when the backup finishes.
+func (j *backupJob) subscribe() ([]backup.Progress, chan backup.Progress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	events := make([]backup.Progress, len(j.events))
+	copy(events, j.events)
+	if !j.running {
+		return events, nil
+	}
+	ch := make(chan backup.Progress, backupProgressBufferSize)
+	if j.subscribers == nil {
+		j.subscribers = make(map[chan backup.Progress]struct{})
+	}
+	j.subscribers[ch] = struct{}{}
+	return events, ch
+}
+
+// unsubscribe removes a subscriber that stops following the backup
+func (j *backupJob) unsubscribe(ch chan backup.Progress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.subscribers[ch]; ok {
+		delete(j.subscribers, ch)
+		close(ch)
+	}
+}
+
+// status returns whether a backup is running and its events so far
+func (j *backupJob) status() BackupStatusResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	events := make([]backup.Progress, len(j.events))
+	copy(events, j.events)
+	return BackupStatusResponse{Running: j.running, Events: events}
+}
+
+// initBackupRoutes registers all backup-related API endpoints
+func (c *Controller) initBackupRoutes() {
+	c.logInfoIfEnabled("Initializing backup routes")
+
+	// All backup operations require authentication
+	backupGroup := c.Group.Group("/backups", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+
+	backupGroup.GET("", c.ListBackups)
+	backupGroup.POST("", c.TriggerBackup)
+	backupGroup.GET("/status", c.GetBackupStatus)
+	backupGroup.GET("/progress", c.StreamBackupProgress)
+	backupGroup.GET("/stats", c.GetBackupStats)
+	backupGroup.GET("/missed", c.GetMissedBackups)
+	backupGroup.GET("/:id/download", c.DownloadBackup)
+	backupGroup.DELETE("/:id", c.DeleteBackup)
+	backupGroup.POST("/:id/restore", c.RestoreBackup)
+
+	c.logInfoIfEnabled("Backup routes initialized successfully")
+}
+
+// getBackupManager returns the backup manager registered with the processor
+func (c *Controller) getBackupManager() *backup.Manager {
+	if c.Processor == nil {
+		return nil
+	}
+	manager, _ := c.Processor.GetBackupManager().(*backup.Manager)
+	return manager
+}
+
+// getBackupScheduler returns the backup scheduler registered with the processor
+func (c *Controller) getBackupScheduler() *backup.Scheduler {
+	if c.Processor == nil {
+		return nil
+	}
+	scheduler, _ := c.Processor.GetBackupScheduler().(*backup.Scheduler)
+	return scheduler
+}
+
+// backupUnavailable responds that the backup system is not running
+func (c *Controller) backupUnavailable(ctx echo.Context) error {
+	return c.HandleError(ctx, errors.Newf("backup system not available").
+		Category(errors.CategorySystem).
+		Component("api-backup").
+		Build(), "Backup system not available", http.StatusServiceUnavailable)
+}
+
+// ListBackups handles GET /api/v2/backups
+// Lists the stored backups grouped by target, newest first. The optional target
+// query parameter limits the list to one target. Targets that fail to list are
+// reported in the errors field while the backups of the others are returned.
+func (c *Controller) ListBackups(ctx echo.Context) error {
+	manager := c.getBackupManager()
+	if manager == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	backups, err := manager.ListBackups(ctx.Request().Context())
+	if err != nil && len(backups) == 0 {
+		return c.HandleError(ctx, err, "Failed to list backups", http.StatusInternalServerError)
+	}
+
+	response := BackupListResponse{Targets: groupBackupsByTarget(backups, ctx.QueryParam("target"))}
+	for i := range response.Targets {
+		response.Total += len(response.Targets[i].Backups)
+	}
+	if err != nil {
+		response.Errors = []string{err.Error()}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// groupBackupsByTarget groups backups by target in target name order, keeping
+// the order of the backups within a target
+func groupBackupsByTarget(backups []backup.BackupInfo, target string) []BackupTargetList {
+	lists := make([]BackupTargetList, 0)
+	index := make(map[string]int)
+	for i := range backups {
+		name := backups[i].Target
+		if target != "" && name != target {
+			continue
+		}
+		n, ok := index[name]
+		if !ok {
+			n = len(lists)
+			index[name] = n
+			lists = append(lists, BackupTargetList{Target: name, Backups: []BackupResponse{}})
+		}
+		lists[n].Backups = append(lists[n].Backups, BackupResponse{Metadata: backups[i].Metadata, Target: name})
+	}
+	slices.SortFunc(lists, func(a, b BackupTargetList) int {
+		return strings.Compare(a.Target, b.Target)
+	})
+	return lists
+}
+
+// TriggerBackup handles POST /api/v2/backups
+// Starts an on-demand backup of all sources. The backup runs in the background,
+// its progress is available from /api/v2/backups/progress and /api/v2/backups/status.
+func (c *Controller) TriggerBackup(ctx echo.Context) error {
+	scheduler := c.getBackupScheduler()
+	if scheduler == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	if !c.backupJob.start() {
+		return c.HandleError(ctx, errors.Newf("backup already in progress").
+			Category(errors.CategoryConflict).
+			Component("api-backup").
+			Build(), "A backup is already in progress", http.StatusConflict)
+	}
+
+	c.logInfoIfEnabled("Starting on-demand backup",
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	// The backup outlives the request, it is only cancelled on shutdown
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	c.wg.Go(func() {
+		backupCtx := backup.WithProgress(parent, c.backupJob.publish)
+		err := scheduler.TriggerBackup(backupCtx)
+		if err != nil {
+			c.logErrorIfEnabled("On-demand backup failed", logger.Error(err))
+		} else {
+			c.logInfoIfEnabled("On-demand backup completed")
+		}
+		c.backupJob.finish(err)
+	})
+
+	return ctx.JSON(http.StatusAccepted, map[string]any{
+		"message": "Backup started",
+		"status":  "running",
+	})
+}
+
+// GetBackupStatus handles GET /api/v2/backups/status
+// Returns whether an on-demand backup is running and the progress events of the
+// running or last on-demand backup.
+func (c *Controller) GetBackupStatus(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, c.backupJob.status())
+}
+
+// StreamBackupProgress handles GET /api/v2/backups/progress
+// Streams the progress of the on-demand backup as SSE "progress" events. The
+// events so far are replayed on connect, and a final "done" event is sent when
+// the backup has finished or when no backup is running.
+func (c *Controller) StreamBackupProgress(ctx echo.Context) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx.Request().Context(), maxSSEStreamDuration)
+	defer cancel()
+	ctx.SetRequest(ctx.Request().WithContext(timeoutCtx))
+
+	setSSEHeaders(ctx)
+	clientID := generateCorrelationID()
+
+	c.logSSEConnection(clientID, ctx.RealIP(), ctx.Request().UserAgent(), "backup-progress", true)
+	defer c.logSSEConnection(clientID, ctx.RealIP(), "", "backup-progress", false)
+
+	events, ch := c.backupJob.subscribe()
+	if ch != nil {
+		defer c.backupJob.unsubscribe(ch)
+	}
+
+	if err := c.sendConnectionMessage(ctx, clientID, "Connected to backup progress", "backup_progress"); err != nil {
+		return err
+	}
+	for i := range events {
+		if err := c.sendSSEMessage(ctx, "progress", events[i]); err != nil {
+			return err
+		}
+	}
+	if ch == nil {
+		return c.sendSSEMessage(ctx, "done", c.backupJob.status())
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case p, ok := <-ch:
+			if !ok {
+				return c.sendSSEMessage(ctx, "done", c.backupJob.status())
+			}
+			if err := c.sendSSEMessage(ctx, "progress", p); err != nil {
+				return err
+			}
+		case <-heartbeat.C:
+			if err := c.sendSSEHeartbeat(ctx, clientID, "backup_progress"); err != nil {
+				return err
+			}
+		case <-ctx.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// GetBackupStats handles GET /api/v2/backups/stats
+// Returns the backup statistics of each target.
+func (c *Controller) GetBackupStats(ctx echo.Context) error {
+	manager := c.getBackupManager()
+	if manager == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	stats, err := manager.GetBackupStats(ctx.Request().Context())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get backup statistics", http.StatusInternalServerError)
+	}
+
+	response := make(map[string]BackupStatsResponse, len(stats))
+	for target := range stats {
+		s := stats[target]
+		response[target] = BackupStatsResponse{
+			TotalBackups:     s.TotalBackups,
+			DailyBackups:     s.DailyBackups,
+			WeeklyBackups:    s.WeeklyBackups,
+			OldestBackup:     s.OldestBackup,
+			NewestBackup:     s.NewestBackup,
+			TotalSize:        s.TotalSize,
+			LastBackupStatus: s.LastBackupStatus,
+			LastBackupTime:   s.LastBackupTime,
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetMissedBackups handles GET /api/v2/backups/missed
+// Returns the scheduled backup runs that were missed or failed.
+func (c *Controller) GetMissedBackups(ctx echo.Context) error {
+	scheduler := c.getBackupScheduler()
+	if scheduler == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	return ctx.JSON(http.StatusOK, scheduler.GetMissedBackups())
+}
+
+// DownloadBackup handles GET /api/v2/backups/:id/download
+// Downloads the archive of a backup as stored in the target, the optional target
+// query parameter selects the target to fetch it from.
+func (c *Controller) DownloadBackup(ctx echo.Context) error {
+	backupID := ctx.Param("id")
+
+	manager := c.getBackupManager()
+	if manager == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	tempDir, err := os.MkdirTemp("", "birdnet-go-backup-download-*")
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to prepare backup download", http.StatusInternalServerError)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			c.logWarnIfEnabled("Failed to remove backup download directory", logger.String("dir", tempDir), logger.Error(err))
+		}
+	}()
+
+	archivePath, info, err := manager.RetrieveBackup(ctx.Request().Context(), backupID, ctx.QueryParam("target"), tempDir)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to retrieve backup", restoreErrorStatus(err))
+	}
+
+	c.logInfoIfEnabled("Downloading backup",
+		logger.String("backup_id", backupID),
+		logger.String("target", info.Target),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	filename := backupID + ".tar"
+	if info.Encrypted {
+		filename += ".enc"
+	}
+	return ctx.Attachment(archivePath, filename)
+}
+
+// DeleteBackup handles DELETE /api/v2/backups/:id
+// Deletes a backup from the target that stores it.
+func (c *Controller) DeleteBackup(ctx echo.Context) error {
+	backupID := ctx.Param("id")
+
+	manager := c.getBackupManager()
+	if manager == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	if err := manager.DeleteBackup(ctx.Request().Context(), backupID); err != nil {
+		return c.HandleError(ctx, err, fmt.Sprintf("Failed to delete backup %s", backupID), restoreErrorStatus(err))
+	}
+
+	c.logInfoIfEnabled("Backup deleted",
+		logger.String("backup_id", backupID),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// RestoreBackup handles POST /api/v2/backups/:id/restore
+// Fetches, verifies and restores a backup. The server keeps the live database
+// open, so restores through the API write into an output directory below the
+// backup or data directory; restoring into the live locations is CLI only.
+func (c *Controller) RestoreBackup(ctx echo.Context) error {
+	backupID := ctx.Param("id")
+
+	var req RestoreBackupRequest
+	if ctx.Request().ContentLength != 0 {
+		if err := ctx.Bind(&req); err != nil {
+			return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+		}
+	}
+
+	if req.OutputDir == "" && !req.DryRun {
+		return c.restoreValidationError(ctx, "output_dir is required, restoring into the live locations is only supported by the restore command")
+	}
+	if req.OutputDir != "" {
+		outputDir, err := c.validateRestoreOutputDir(req.OutputDir)
+		if err != nil {
+			return c.restoreValidationError(ctx, err.Error())
+		}
+		req.OutputDir = outputDir
+	}
+
+	manager := c.getBackupManager()
+	if manager == nil {
+		return c.backupUnavailable(ctx)
+	}
+
+	if !c.backupRestoreMu.TryLock() {
+		return c.HandleError(ctx, errors.Newf("restore already in progress").
+			Category(errors.CategoryConflict).
+			Component("api-backup").
+			Build(), "A restore is already in progress", http.StatusConflict)
+	}
+	defer c.backupRestoreMu.Unlock()
+
+	c.logInfoIfEnabled("Restoring backup",
+		logger.String("backup_id", backupID),
+		logger.String("target", req.Target),
+		logger.Bool("dry_run", req.DryRun),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	result, err := manager.Restore(ctx.Request().Context(), &backup.RestoreOptions{
+		BackupID:     backupID,
+		Target:       req.Target,
+		OutputDir:    req.OutputDir,
+		DryRun:       req.DryRun,
+		SkipDatabase: req.SkipDatabase,
+		SkipConfig:   req.SkipConfig,
+	})
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to restore backup", restoreErrorStatus(err))
+	}
+
+	c.logInfoIfEnabled("Backup restore finished",
+		logger.String("backup_id", backupID),
+		logger.Bool("dry_run", result.DryRun),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// validateRestoreOutputDir checks that a restore output directory is a subdirectory of
+// a local backup target or of the data directory, and not a directory holding the
+// live database or configuration. Returns the cleaned absolute path.
+func (c *Controller) validateRestoreOutputDir(outputDir string) (string, error) {
+	if !filepath.IsAbs(outputDir) {
+		return "", errors.NewStd("output_dir must be an absolute path")
+	}
+	outputDir = filepath.Clean(outputDir)
+
+	var liveDirs []string
+	if dbPath := c.Settings.Output.SQLite.Path; dbPath != "" {
+		if absPath, err := filepath.Abs(dbPath); err == nil {
+			liveDirs = append(liveDirs, filepath.Dir(absPath))
+		}
+	}
+	if configPath, err := conf.FindConfigFile(); err == nil {
+		liveDirs = append(liveDirs, filepath.Dir(configPath))
+	}
+	for _, dir := range liveDirs {
+		if sameRestorePath(dir, outputDir) {
+			return "", errors.NewStd("output_dir must not be the directory of the live database or configuration")
+		}
+	}
+
+	for _, base := range c.restoreOutputBases() {
+		if sameRestorePath(base, outputDir) {
+			continue
+		}
+		if within, err := securefs.IsPathWithinBase(base, outputDir); err == nil && within {
+			return outputDir, nil
+		}
+	}
+	return "", errors.NewStd("output_dir must be a subdirectory of a local backup target or of the data directory")
+}
+
+// restoreOutputBases returns the directories restores through the API may write below:
+// the paths of local backup targets and the directory of the SQLite database
+func (c *Controller) restoreOutputBases() []string {
+	var bases []string
+	for _, target := range c.Settings.Backup.Targets {
+		if !strings.EqualFold(target.Type, "local") {
+			continue
+		}
+		if path, ok := target.Settings["path"].(string); ok && path != "" {
+			if absPath, err := filepath.Abs(path); err == nil {
+				bases = append(bases, absPath)
+			}
+		}
+	}
+	if dbPath := c.Settings.Output.SQLite.Path; dbPath != "" {
+		if absPath, err := filepath.Abs(dbPath); err == nil {
+			bases = append(bases, filepath.Dir(absPath))
+		}
+	}
+	return bases
+}
+
+// sameRestorePath reports whether two absolute paths name the same directory,
+// resolving symlinks where the paths exist
+func sameRestorePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// restoreValidationError responds 400 with a restore request validation message
+func (c *Controller) restoreValidationError(ctx echo.Context, message string) error {
+	return c.HandleError(ctx, errors.Newf("%s", message).
+		Category(errors.CategoryValidation).
+		Component("api-backup").
+		Build(), message, http.StatusBadRequest)
+}
+
+// restoreErrorStatus maps backup error codes to HTTP status codes
+func restoreErrorStatus(err error) int {
+	switch {
+	case backup.IsErrorCode(err, backup.ErrNotFound):
+		return http.StatusNotFound
+	case backup.IsErrorCode(err, backup.ErrValidation), backup.IsErrorCode(err, backup.ErrSecurity):
+		return http.StatusBadRequest
+	case backup.IsErrorCode(err, backup.ErrCorruption), backup.IsErrorCode(err, backup.ErrEncryption):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
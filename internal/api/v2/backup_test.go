@@ -0,0 +1,198 @@
+// backup_test.go: Package api provides tests for backup API v2 endpoints.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestRestoreBackupWithoutManager tests that restore fails cleanly when the backup system is not running
+func TestRestoreBackupWithoutManager(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Processor = nil
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/backups/birdnet-20250102-030000/restore",
+		strings.NewReader(`{"dry_run": true}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v2/backups/:id/restore")
+	c.SetParamNames("id")
+	c.SetParamValues("birdnet-20250102-030000")
+
+	err := controller.RestoreBackup(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+// TestRestoreBackupOutputDir tests that API restores are confined to an output directory
+// below a local backup target or the data directory
+func TestRestoreBackupOutputDir(t *testing.T) {
+	dataDir := t.TempDir()
+	backupDir := t.TempDir()
+	e, _, controller := setupTestEnvironment(t)
+	controller.Processor = nil
+	controller.Settings.Output.SQLite.Path = filepath.Join(dataDir, "birdnet.db")
+	controller.Settings.Backup.Targets = []conf.BackupTarget{
+		{Type: "local", Enabled: true, Settings: map[string]any{"path": backupDir}},
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"live locations", `{}`, http.StatusBadRequest},
+		{"relative", `{"output_dir": "restore"}`, http.StatusBadRequest},
+		{"outside", fmt.Sprintf(`{"output_dir": %q}`, t.TempDir()), http.StatusBadRequest},
+		{"data directory itself", fmt.Sprintf(`{"output_dir": %q}`, dataDir), http.StatusBadRequest},
+		{"traversal", fmt.Sprintf(`{"output_dir": %q}`, dataDir+"/restore/../../etc"), http.StatusBadRequest},
+		// Valid requests reach the backup manager, which is not running in tests
+		{"dry run", `{"dry_run": true}`, http.StatusServiceUnavailable},
+		{"below data directory", fmt.Sprintf(`{"output_dir": %q}`, filepath.Join(dataDir, "restore")), http.StatusServiceUnavailable},
+		{"below backup target", fmt.Sprintf(`{"output_dir": %q}`, filepath.Join(backupDir, "restore")), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/backups/birdnet-20250102-030000/restore",
+				strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v2/backups/:id/restore")
+			c.SetParamNames("id")
+			c.SetParamValues("birdnet-20250102-030000")
+
+			require.NoError(t, controller.RestoreBackup(c))
+			assert.Equal(t, tt.expected, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+// TestRestoreErrorStatus tests the mapping of backup error codes to HTTP status codes
+func TestRestoreErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"not found", backup.NewError(backup.ErrNotFound, "missing", nil), http.StatusNotFound},
+		{"validation", backup.NewError(backup.ErrValidation, "invalid", nil), http.StatusBadRequest},
+		{"security", backup.NewError(backup.ErrSecurity, "traversal", nil), http.StatusBadRequest},
+		{"corruption", backup.NewError(backup.ErrCorruption, "checksum mismatch", nil), http.StatusUnprocessableEntity},
+		{"encryption", backup.NewError(backup.ErrEncryption, "no key", nil), http.StatusUnprocessableEntity},
+		{"io", backup.NewError(backup.ErrIO, "disk", nil), http.StatusInternalServerError},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, restoreErrorStatus(tt.err))
+		})
+	}
+}
+
+// TestBackupEndpointsWithoutBackupSystem tests that the backup endpoints fail cleanly when the backup system is not running
+func TestBackupEndpointsWithoutBackupSystem(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Processor = nil
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		handler func(echo.Context) error
+	}{
+		{"list", http.MethodGet, "/api/v2/backups", controller.ListBackups},
+		{"trigger", http.MethodPost, "/api/v2/backups", controller.TriggerBackup},
+		{"stats", http.MethodGet, "/api/v2/backups/stats", controller.GetBackupStats},
+		{"missed", http.MethodGet, "/api/v2/backups/missed", controller.GetMissedBackups},
+		{"download", http.MethodGet, "/api/v2/backups/birdnet-20250102-030000/download", controller.DownloadBackup},
+		{"delete", http.MethodDelete, "/api/v2/backups/birdnet-20250102-030000", controller.DeleteBackup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			require.NoError(t, tt.handler(c))
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		})
+	}
+}
+
+// TestGroupBackupsByTarget tests grouping and filtering of the backup list
+func TestGroupBackupsByTarget(t *testing.T) {
+	t.Parallel()
+
+	backups := []backup.BackupInfo{
+		{Metadata: backup.Metadata{ID: "birdnet-20250103-030000"}, Target: "sftp"},
+		{Metadata: backup.Metadata{ID: "birdnet-20250103-030000"}, Target: "local"},
+		{Metadata: backup.Metadata{ID: "birdnet-20250102-030000"}, Target: "local"},
+	}
+
+	lists := groupBackupsByTarget(backups, "")
+	require.Len(t, lists, 2)
+	assert.Equal(t, "local", lists[0].Target)
+	require.Len(t, lists[0].Backups, 2)
+	assert.Equal(t, "birdnet-20250103-030000", lists[0].Backups[0].ID)
+	assert.Equal(t, "local", lists[0].Backups[0].Target)
+	assert.Equal(t, "sftp", lists[1].Target)
+
+	lists = groupBackupsByTarget(backups, "sftp")
+	require.Len(t, lists, 1)
+	assert.Len(t, lists[0].Backups, 1)
+
+	assert.Empty(t, groupBackupsByTarget(backups, "ftp"))
+}
+
+// TestBackupJobProgress tests that progress events reach subscribers and the job can only run once at a time
+func TestBackupJobProgress(t *testing.T) {
+	t.Parallel()
+
+	var job backupJob
+	events, ch := job.subscribe()
+	assert.Empty(t, events)
+	assert.Nil(t, ch, "no channel is returned when no backup is running")
+
+	require.True(t, job.start())
+	assert.False(t, job.start(), "a second backup must not start while one is running")
+
+	job.publish(backup.Progress{Stage: backup.ProgressStarted, SourcesTotal: 1})
+	events, ch = job.subscribe()
+	require.NotNil(t, ch)
+	require.Len(t, events, 1)
+
+	job.publish(backup.Progress{Stage: backup.ProgressSourceStarted, Source: "sqlite"})
+	p := <-ch
+	assert.Equal(t, "sqlite", p.Source)
+
+	job.finish(errors.New("another backup is already in progress"))
+	p = <-ch
+	assert.Equal(t, backup.ProgressFailed, p.Stage)
+	_, ok := <-ch
+	assert.False(t, ok, "the channel is closed when the backup finishes")
+
+	status := job.status()
+	assert.False(t, status.Running)
+	assert.Len(t, status.Events, 3)
+	assert.True(t, job.start(), "a new backup can start after the previous one finished")
+}
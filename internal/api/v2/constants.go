@@ -50,6 +50,9 @@ const (
 	WeatherProviderOpenWeather  = "openweather"
 	WeatherProviderWunderground = "wunderground"
 	WeatherProviderYrno         = "yrno"
+	WeatherProviderOpenMeteo    = "openmeteo"
+	WeatherProviderLocalStation = "localstation"
+	WeatherProviderMQTT         = "mqtt"
 	WeatherUnitMetric           = "metric"
 )
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -43,7 +44,7 @@ func (c *Controller) initControlRoutes() {
 	c.logInfoIfEnabled("Initializing control routes")
 
 	// Create control API group with auth middleware
-	controlGroup := c.Group.Group("/control", c.authMiddleware)
+	controlGroup := c.Group.Group("/control", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
 
 	// Control routes
 	controlGroup.POST("/restart", c.RestartAnalysis)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -54,7 +55,7 @@ func (c *Controller) initDebugRoutes() {
 	}
 
 	// Debug endpoints require authentication
-	debugGroup := c.Group.Group("/debug", c.authMiddleware)
+	debugGroup := c.Group.Group("/debug", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
 
 	debugGroup.POST("/trigger-error", c.DebugTriggerError)
 	debugGroup.POST("/trigger-notification", c.DebugTriggerNotification)
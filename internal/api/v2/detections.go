@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
-	"slices"
 	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/patrickmn/go-cache"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/errors"
@@ -120,12 +120,17 @@ func (c *Controller) initDetectionRoutes() {
 	c.Group.GET("/detections/:id/time-of-day", c.GetDetectionTimeOfDay)
 
 	// Protected detection management endpoints
+	// Reviewers verify, comment and lock detections, deleting detections and
+	// ignoring species are left to admins
 	detectionGroup := c.Group.Group("/detections", c.authMiddleware)
-	detectionGroup.DELETE("/:id", c.DeleteDetection)
-	detectionGroup.POST("/:id/review", c.ReviewDetection)
-	detectionGroup.POST("/:id/lock", c.LockDetection)
-	detectionGroup.POST("/ignore", c.IgnoreSpecies)
+	detectionGroup.DELETE("/:id", c.DeleteDetection, auth.RequireRole(auth.RoleAdmin))
+	detectionGroup.POST("/:id/review", c.ReviewDetection, auth.RequireRole(auth.RoleReviewer))
+	detectionGroup.POST("/:id/lock", c.LockDetection, auth.RequireRole(auth.RoleReviewer))
+	detectionGroup.POST("/ignore", c.IgnoreSpecies, auth.RequireRole(auth.RoleAdmin))
 	detectionGroup.GET("/ignored", c.GetExcludedSpecies)
+	detectionGroup.GET("/export", c.ExportDetections)
+	c.initBulkDetectionRoutes(detectionGroup)
+	c.initCorrectionRoutes(detectionGroup)
 }
 
 // DetectionResponse represents a detection in the API response
@@ -134,6 +139,7 @@ type DetectionResponse struct {
 	Date               string       `json:"date"`
 	Time               string       `json:"time"`
 	Source             string       `json:"source"`
+	SourceID           string       `json:"sourceId,omitempty"`
 	BeginTime          string       `json:"beginTime"`
 	EndTime            string       `json:"endTime"`
 	SpeciesCode        string       `json:"speciesCode"`
@@ -141,6 +147,7 @@ type DetectionResponse struct {
 	CommonName         string       `json:"commonName"`
 	Confidence         float64      `json:"confidence"`
 	Verified           string       `json:"verified"`
+	ReviewedBy         string       `json:"reviewedBy,omitempty"` // User who last reviewed the detection
 	Locked             bool         `json:"locked"`
 	Comments           []string     `json:"comments,omitempty"`
 	Weather            *WeatherInfo `json:"weather,omitempty"`
@@ -210,6 +217,7 @@ type detectionQueryParams struct {
 	HourRange  string
 	Verified   string
 	Location   string
+	Source     string
 	Locked     string
 	// Include additional data
 	IncludeWeather bool
@@ -218,10 +226,10 @@ type detectionQueryParams struct {
 // advancedSearchCacheKey generates a deterministic cache key for advanced search queries.
 // Includes all filter parameters to avoid cache collisions.
 func (p *detectionQueryParams) advancedSearchCacheKey() string {
-	return fmt.Sprintf("adv_search:%s:%d:%d:%s:%s:%s:%s:%s:%s:%s:%s:%s",
+	return fmt.Sprintf("adv_search:%s:%d:%d:%s:%s:%s:%s:%s:%s:%s:%s:%s:%s",
 		p.Search, p.NumResults, p.Offset,
 		p.Confidence, p.TimeOfDay, p.HourRange,
-		p.Verified, p.Location, p.Locked,
+		p.Verified, p.Location, p.Source, p.Locked,
 		p.Species, p.Date, p.StartDate+":"+p.EndDate)
 }
 
@@ -241,6 +249,7 @@ func (c *Controller) parseDetectionQueryParams(ctx echo.Context) (*detectionQuer
 		HourRange:  ctx.QueryParam("hourRange"),
 		Verified:   ctx.QueryParam("verified"),
 		Location:   ctx.QueryParam("location"),
+		Source:     ctx.QueryParam("source"),
 		Locked:     ctx.QueryParam("locked"),
 		// Include weather data
 		IncludeWeather: ctx.QueryParam("includeWeather") == QueryValueTrue,
@@ -491,7 +500,7 @@ func (c *Controller) getDetectionsByQueryType(params *detectionQueryParams) ([]d
 	// Check if advanced filters are present
 	hasAdvancedFilters := params.Confidence != "" || params.TimeOfDay != "" ||
 		params.HourRange != "" || params.Verified != "" ||
-		params.Location != "" || params.Locked != ""
+		params.Location != "" || params.Source != "" || params.Locked != ""
 
 	switch params.QueryType {
 	case "hourly":
@@ -535,6 +544,7 @@ func (c *Controller) noteToDetectionResponse(note *datastore.Note, includeWeathe
 		Date:           note.Date,
 		Time:           note.Time,
 		Source:         note.Source.SafeString,
+		SourceID:       note.Source.ID,
 		BeginTime:      note.BeginTime.Format(time.RFC3339),
 		EndTime:        note.EndTime.Format(time.RFC3339),
 		SpeciesCode:    note.SpeciesCode,
@@ -543,9 +553,16 @@ func (c *Controller) noteToDetectionResponse(note *datastore.Note, includeWeathe
 		Confidence:     note.Confidence,
 		Locked:         note.Locked,
 	}
+	// Notes loaded from the database only carry the stored source ID and display name
+	if detection.Source == "" {
+		detection.Source = note.Source.DisplayName
+	}
 
 	c.applySpeciesTrackingMetadata(&detection, note.ScientificName)
 	detection.Verified = c.mapVerificationStatus(note.Verified)
+	if note.Review != nil {
+		detection.ReviewedBy = note.Review.ReviewedBy
+	}
 	detection.Comments = extractNoteComments(note.Comments)
 
 	if includeWeather {
@@ -864,6 +881,9 @@ func (c *Controller) buildAdvancedSearchFilters(params *detectionQueryParams) da
 	if params.Location != "" {
 		filters.Location = []string{params.Location}
 	}
+	if params.Source != "" {
+		filters.Source = []string{params.Source}
+	}
 
 	// Apply boolean filters
 	if params.Verified != "" {
@@ -1091,15 +1111,27 @@ func (c *Controller) ReviewDetection(ctx echo.Context) error {
 		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
 	}
 
+	// Ignoring a species changes the global exclude list, which is left to admins
+	// like the dedicated ignore endpoint
+	if req.IgnoreSpecies != "" && !auth.HasRole(ctx, auth.RoleAdmin) {
+		return c.HandleError(ctx, errors.Newf("ignoring species requires the %s role", auth.RoleAdmin).
+			Category(errors.CategoryValidation).
+			Component("api-detections").
+			Build(), "Ignoring species requires the "+string(auth.RoleAdmin)+" role", http.StatusForbidden)
+	}
+
 	// Check lock status (both in-memory and database for race condition)
 	if c.checkDetectionNotLocked(ctx, idStr, note.Locked) {
 		return nil // Response already handled by checkDetectionNotLocked
 	}
 
+	// Reviews and comments are attributed to the signed in user
+	user := stringFromCtx(ctx, auth.CtxKeyUsername, "")
+
 	// Handle comment if provided
 	if req.Comment != "" {
 		// Save comment using the datastore method for adding comments
-		err = c.AddComment(note.ID, req.Comment)
+		err = c.AddComment(note.ID, req.Comment, user)
 		if err != nil {
 			return c.HandleError(ctx, err, fmt.Sprintf("Failed to add comment: %v", err), http.StatusInternalServerError)
 		}
@@ -1113,7 +1145,7 @@ func (c *Controller) ReviewDetection(ctx echo.Context) error {
 
 	if verification.IsSet {
 		// Save review using the datastore method for reviews
-		if err := c.AddReview(note.ID, verification.Verified); err != nil {
+		if err := c.AddReview(note.ID, verification.Verified, user); err != nil {
 			return c.HandleError(ctx, err, fmt.Sprintf("Failed to update verification: %v", err), http.StatusInternalServerError)
 		}
 
@@ -1236,13 +1268,7 @@ func (c *Controller) IgnoreSpecies(ctx echo.Context) error {
 
 // GetExcludedSpecies returns the list of excluded species
 func (c *Controller) GetExcludedSpecies(ctx echo.Context) error {
-	settings := conf.GetSettings()
-
-	// Create a copy of the slice to avoid race conditions
-	c.speciesExcludeMutex.Lock()
-	species := make([]string, len(settings.Realtime.Species.Exclude))
-	copy(species, settings.Realtime.Species.Exclude)
-	c.speciesExcludeMutex.Unlock()
+	species := conf.ListedSpecies(conf.SpeciesListExclude)
 
 	return ctx.JSON(http.StatusOK, ExcludedSpeciesResponse{
 		Species: species,
@@ -1258,7 +1284,7 @@ func (c *Controller) addToIgnoredSpecies(verified, ignoreSpecies string) error {
 	return nil
 }
 
-// toggleSpeciesInIgnoredList toggles a species in the ignore list with proper concurrency control.
+// toggleSpeciesInIgnoredList toggles a species in the ignore list.
 // If the species is already excluded, it removes it. If not excluded, it adds it.
 // Returns the action taken ("added" or "removed"), the new excluded state, and any error.
 func (c *Controller) toggleSpeciesInIgnoredList(species string) (action string, isExcluded bool, err error) {
@@ -1266,43 +1292,16 @@ func (c *Controller) toggleSpeciesInIgnoredList(species string) (action string,
 		return "", false, nil
 	}
 
-	// Use the controller's mutex to protect this operation
-	c.speciesExcludeMutex.Lock()
-	defer c.speciesExcludeMutex.Unlock()
-
-	// Access the latest settings using the settings accessor function
-	settings := conf.GetSettings()
-
-	// Check if species is already in the excluded list
-	wasExcluded := slices.Contains(settings.Realtime.Species.Exclude, species)
-
-	if wasExcluded {
-		// Remove from excluded list
-		newExcludeList := make([]string, 0, len(settings.Realtime.Species.Exclude)-1)
-		for _, s := range settings.Realtime.Species.Exclude {
-			if s != species {
-				newExcludeList = append(newExcludeList, s)
-			}
-		}
-		settings.Realtime.Species.Exclude = newExcludeList
-		action = "removed"
-		isExcluded = false
-	} else {
-		// Add to excluded list
-		newExcludeList := make([]string, len(settings.Realtime.Species.Exclude))
-		copy(newExcludeList, settings.Realtime.Species.Exclude)
-		newExcludeList = append(newExcludeList, species)
-		settings.Realtime.Species.Exclude = newExcludeList
-		action = "added"
-		isExcluded = true
+	// The conf helper serializes changes with the MQTT species commands
+	added, err := conf.ToggleListedSpecies(conf.SpeciesListExclude, species)
+	if err != nil {
+		return "", !added, fmt.Errorf("failed to save settings: %w", err)
 	}
 
-	// Save settings using the package function that handles concurrency
-	if err := conf.SaveSettings(); err != nil {
-		return "", wasExcluded, fmt.Errorf("failed to save settings: %w", err)
+	if added {
+		return "added", true, nil
 	}
-
-	return action, isExcluded, nil
+	return "removed", false, nil
 }
 
 // addSpeciesToIgnoredList adds a species to the ignore list (used by review endpoint).
@@ -1312,39 +1311,14 @@ func (c *Controller) addSpeciesToIgnoredList(species string) error {
 		return nil
 	}
 
-	// Use the controller's mutex to protect this operation
-	c.speciesExcludeMutex.Lock()
-	defer c.speciesExcludeMutex.Unlock()
-
-	// Access the latest settings using the settings accessor function
-	settings := conf.GetSettings()
-
-	// Check if species is already in the excluded list
-	isExcluded := slices.Contains(settings.Realtime.Species.Exclude, species)
-
-	// If not already excluded, add it
-	if !isExcluded {
-		// Create a copy of the current exclude list to avoid race conditions
-		newExcludeList := make([]string, len(settings.Realtime.Species.Exclude))
-		copy(newExcludeList, settings.Realtime.Species.Exclude)
-
-		// Add the new species to the list
-		newExcludeList = append(newExcludeList, species)
-
-		// Update the settings with the new list
-		settings.Realtime.Species.Exclude = newExcludeList
-
-		// Save settings using the package function that handles concurrency
-		if err := conf.SaveSettings(); err != nil {
-			return fmt.Errorf("failed to save settings: %w", err)
-		}
+	if _, err := conf.AddListedSpecies(conf.SpeciesListExclude, species); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
 	}
-
 	return nil
 }
 
-// AddComment creates a comment for a note
-func (c *Controller) AddComment(noteID uint, commentText string) error {
+// AddComment creates a comment for a note, attributed to user
+func (c *Controller) AddComment(noteID uint, commentText, user string) error {
 	if commentText == "" {
 		return nil // No comment to add
 	}
@@ -1352,6 +1326,7 @@ func (c *Controller) AddComment(noteID uint, commentText string) error {
 	comment := &datastore.NoteComment{
 		NoteID:    noteID,
 		Entry:     commentText,
+		CreatedBy: user,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -1359,8 +1334,8 @@ func (c *Controller) AddComment(noteID uint, commentText string) error {
 	return c.DS.SaveNoteComment(comment)
 }
 
-// AddReview creates or updates a review for a note
-func (c *Controller) AddReview(noteID uint, verified bool) error {
+// AddReview creates or updates a review for a note, attributed to user
+func (c *Controller) AddReview(noteID uint, verified bool, user string) error {
 	// Convert bool to string value
 	verifiedStr := map[bool]string{
 		true:  "correct",
@@ -1368,10 +1343,11 @@ func (c *Controller) AddReview(noteID uint, verified bool) error {
 	}[verified]
 
 	review := &datastore.NoteReview{
-		NoteID:    noteID,
-		Verified:  verifiedStr,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		NoteID:     noteID,
+		Verified:   verifiedStr,
+		ReviewedBy: user,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
 	}
 
 	return c.DS.SaveNoteReview(review)
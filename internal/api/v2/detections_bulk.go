@@ -0,0 +1,543 @@
+// internal/api/v2/detections_bulk.go
+package api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/observation"
+)
+
+const (
+	maxBulkDetections   = 5000             // Most detections changed by one bulk operation
+	bulkUndoWindow      = 15 * time.Minute // How long a bulk operation can be undone
+	bulkPurgeInterval   = 5 * time.Minute  // How often expired undo records are removed
+	bulkUndoClipsDir    = ".bulk-undo"     // Directory under the clip path holding clips of deleted detections, hidden so retention and backups skip it
+	bulkClipDirPerm     = 0o755
+	spectrogramFileExt  = ".png"
+	spectrogramSizeMark = "px"
+)
+
+// bulkNoteStore is implemented by datastores supporting bulk detection changes
+type bulkNoteStore interface {
+	SearchNoteIDsAdvanced(filters *datastore.AdvancedSearchFilters, limit int) ([]uint, error)
+	ApplyBulkNoteChange(noteIDs []uint, change *datastore.BulkNoteChange) (*datastore.BulkNoteResult, error)
+	UndoBulkOperation(id uint) (*datastore.BulkNoteResult, error)
+	GetBulkOperation(id uint) (*datastore.BulkOperation, error)
+	GetBulkOperations() ([]datastore.BulkOperation, error)
+	PurgeBulkOperations(before time.Time) ([]datastore.BulkOperation, error)
+}
+
+// BulkDetectionFilter selects detections with the filters of GET /detections
+type BulkDetectionFilter struct {
+	Search     string `json:"search,omitempty"`
+	Species    string `json:"species,omitempty"`
+	Date       string `json:"date,omitempty"`
+	StartDate  string `json:"start_date,omitempty"`
+	EndDate    string `json:"end_date,omitempty"`
+	Confidence string `json:"confidence,omitempty"`
+	TimeOfDay  string `json:"timeOfDay,omitempty"`
+	HourRange  string `json:"hourRange,omitempty"`
+	Verified   string `json:"verified,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Locked     string `json:"locked,omitempty"`
+}
+
+// empty reports whether the filter has no conditions and would select every detection
+func (f *BulkDetectionFilter) empty() bool {
+	return *f == BulkDetectionFilter{}
+}
+
+// BulkDetectionRequest selects detections by ID or by filter for a bulk operation
+type BulkDetectionRequest struct {
+	IDs        []uint               `json:"ids,omitempty"`
+	Filter     *BulkDetectionFilter `json:"filter,omitempty"`
+	Verified   string               `json:"verified,omitempty"`   // Review verdict, "correct" or "false_positive"
+	Species    string               `json:"species,omitempty"`    // Scientific name of the new species
+	CommonName string               `json:"commonName,omitempty"` // Common name of the new species when it is not in the model labels
+}
+
+// BulkOperationResponse describes a bulk operation and its undo window
+type BulkOperationResponse struct {
+	ID            uint       `json:"id"`
+	Action        string     `json:"action"`
+	Details       string     `json:"details,omitempty"`
+	NoteCount     int        `json:"noteCount"`
+	PerformedBy   string     `json:"performedBy,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UndoExpiresAt time.Time  `json:"undoExpiresAt"`
+	UndoneAt      *time.Time `json:"undoneAt,omitempty"`
+}
+
+// BulkDetectionResponse is the outcome of a bulk operation or of undoing one
+type BulkDetectionResponse struct {
+	Operation  *BulkOperationResponse `json:"operation,omitempty"` // Omitted when no detection was changed
+	Changed    []uint                 `json:"changed"`
+	Skipped    []uint                 `json:"skipped"`              // Locked or already in the requested state
+	Missing    []uint                 `json:"missing"`              // IDs that did not match a detection
+	ClipErrors int                    `json:"clipErrors,omitempty"` // Clips that could not be moved
+}
+
+// newBulkOperationResponse converts a bulk operation to its API representation
+func newBulkOperationResponse(op *datastore.BulkOperation) *BulkOperationResponse {
+	return &BulkOperationResponse{
+		ID:            op.ID,
+		Action:        op.Action,
+		Details:       op.Details,
+		NoteCount:     op.NoteCount,
+		PerformedBy:   op.PerformedBy,
+		CreatedAt:     op.CreatedAt,
+		UndoExpiresAt: op.ExpiresAt,
+		UndoneAt:      op.UndoneAt,
+	}
+}
+
+// newBulkDetectionResponse converts a bulk result to its API representation
+func newBulkDetectionResponse(result *datastore.BulkNoteResult) *BulkDetectionResponse {
+	resp := &BulkDetectionResponse{
+		Changed: make([]uint, 0, len(result.Notes)),
+		Skipped: result.Skipped,
+		Missing: result.Missing,
+	}
+	if result.Operation != nil {
+		resp.Operation = newBulkOperationResponse(result.Operation)
+	}
+	for i := range result.Notes {
+		resp.Changed = append(resp.Changed, result.Notes[i].ID)
+	}
+	if resp.Skipped == nil {
+		resp.Skipped = []uint{}
+	}
+	if resp.Missing == nil {
+		resp.Missing = []uint{}
+	}
+	return resp
+}
+
+// bulkActionRole returns the role needed to apply a bulk action and to undo it
+func bulkActionRole(action string) auth.Role {
+	if action == datastore.BulkActionDelete {
+		return auth.RoleAdmin
+	}
+	return auth.RoleReviewer
+}
+
+// initBulkDetectionRoutes registers the bulk detection endpoints on the protected detection group
+func (c *Controller) initBulkDetectionRoutes(detectionGroup *echo.Group) {
+	reviewer := auth.RequireRole(auth.RoleReviewer)
+	detectionGroup.GET("/bulk", c.ListBulkOperations, reviewer)
+	detectionGroup.POST("/bulk/review", c.BulkReviewDetections, auth.RequireRole(bulkActionRole(datastore.BulkActionReview)))
+	detectionGroup.POST("/bulk/lock", c.BulkLockDetections, auth.RequireRole(bulkActionRole(datastore.BulkActionLock)))
+	detectionGroup.POST("/bulk/unlock", c.BulkUnlockDetections, auth.RequireRole(bulkActionRole(datastore.BulkActionUnlock)))
+	detectionGroup.POST("/bulk/reassign", c.BulkReassignDetections, auth.RequireRole(bulkActionRole(datastore.BulkActionReassign)))
+	detectionGroup.POST("/bulk/delete", c.BulkDeleteDetections, auth.RequireRole(bulkActionRole(datastore.BulkActionDelete)))
+	// The undo handler checks the role needed by the action of the operation
+	detectionGroup.POST("/bulk/:id/undo", c.UndoBulkOperation, reviewer)
+
+	if _, ok := c.DS.(bulkNoteStore); ok {
+		c.wg.Go(c.runBulkPurge)
+	}
+}
+
+// getBulkStore returns the datastore as a bulkNoteStore, responding with 503 when unsupported
+func (c *Controller) getBulkStore(ctx echo.Context) (bulkNoteStore, error) {
+	store, ok := c.DS.(bulkNoteStore)
+	if !ok {
+		return nil, c.HandleError(ctx, errors.Newf("datastore does not support bulk operations").
+			Category(errors.CategorySystem).
+			Component("api-detections").
+			Build(), "Bulk operations are not available", http.StatusServiceUnavailable)
+	}
+	return store, nil
+}
+
+// BulkReviewDetections handles POST /api/v2/detections/bulk/review
+// Sets the review verdict of the selected detections, locked detections are skipped.
+func (c *Controller) BulkReviewDetections(ctx echo.Context) error {
+	return c.handleBulkChange(ctx, datastore.BulkActionReview)
+}
+
+// BulkLockDetections handles POST /api/v2/detections/bulk/lock
+func (c *Controller) BulkLockDetections(ctx echo.Context) error {
+	return c.handleBulkChange(ctx, datastore.BulkActionLock)
+}
+
+// BulkUnlockDetections handles POST /api/v2/detections/bulk/unlock
+func (c *Controller) BulkUnlockDetections(ctx echo.Context) error {
+	return c.handleBulkChange(ctx, datastore.BulkActionUnlock)
+}
+
+// BulkReassignDetections handles POST /api/v2/detections/bulk/reassign
+// Changes the species of the selected detections, locked detections are skipped.
+func (c *Controller) BulkReassignDetections(ctx echo.Context) error {
+	return c.handleBulkChange(ctx, datastore.BulkActionReassign)
+}
+
+// BulkDeleteDetections handles POST /api/v2/detections/bulk/delete
+// Deletes the selected detections and moves their clips aside until the undo
+// window has passed, locked detections are skipped.
+func (c *Controller) BulkDeleteDetections(ctx echo.Context) error {
+	return c.handleBulkChange(ctx, datastore.BulkActionDelete)
+}
+
+// handleBulkChange selects the detections of the request and applies the action to them
+func (c *Controller) handleBulkChange(ctx echo.Context, action string) error {
+	store, err := c.getBulkStore(ctx)
+	if store == nil {
+		return err
+	}
+	c.purgeBulkOperations(store)
+
+	req := &BulkDetectionRequest{}
+	if err := ctx.Bind(req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+
+	change := &datastore.BulkNoteChange{
+		Action:      action,
+		PerformedBy: stringFromCtx(ctx, auth.CtxKeyUsername, ""),
+		UndoWindow:  bulkUndoWindow,
+	}
+	switch action {
+	case datastore.BulkActionReview:
+		if _, err := parseVerificationStatus(req.Verified); err != nil || req.Verified == "" {
+			return c.bulkValidationError(ctx, "verified must be correct or false_positive")
+		}
+		change.Verified = req.Verified
+	case datastore.BulkActionReassign:
+		scientific, common, code, ok := c.resolveSpeciesLabel(req.Species)
+		if !ok && req.CommonName == "" {
+			return c.bulkValidationError(ctx, "species must be a scientific name from the BirdNET labels")
+		}
+		change.SpeciesSource = datastore.CorrectionSourceLabel
+		if !ok {
+			scientific, common = strings.TrimSpace(req.Species), strings.TrimSpace(req.CommonName)
+			change.SpeciesSource = datastore.CorrectionSourceManual
+		}
+		if scientific == "" {
+			return c.bulkValidationError(ctx, "species is required")
+		}
+		change.ScientificName, change.CommonName, change.SpeciesCode = scientific, common, code
+	}
+
+	ids, err := c.selectBulkDetections(ctx, store, req)
+	if ids == nil {
+		return err
+	}
+
+	result, err := store.ApplyBulkNoteChange(ids, change)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to apply bulk operation", http.StatusInternalServerError)
+	}
+
+	resp := newBulkDetectionResponse(result)
+	if action == datastore.BulkActionDelete && result.Operation != nil {
+		for i := range result.Notes {
+			if err := c.holdDeletedClip(result.Operation.ID, result.Notes[i].ClipName); err != nil {
+				resp.ClipErrors++
+				c.logWarnIfEnabled("Failed to move clip of deleted detection",
+					logger.Int("detection_id", int(result.Notes[i].ID)),
+					logger.Error(err))
+			}
+		}
+	}
+
+	c.invalidateDetectionCache()
+	if speciesChanged(action) && result.Operation != nil {
+		c.refreshSpeciesTracker()
+	}
+	c.logInfoIfEnabled("Bulk detection operation applied",
+		logger.String("action", action),
+		logger.Int("requested", len(ids)),
+		logger.Int("changed", len(resp.Changed)),
+		logger.Int("skipped", len(resp.Skipped)),
+		logger.String("username", change.PerformedBy),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// speciesChanged reports whether a bulk action changes which species were detected
+func speciesChanged(action string) bool {
+	return action == datastore.BulkActionReassign || action == datastore.BulkActionDelete
+}
+
+// bulkValidationError responds with a 400 error for invalid bulk requests
+func (c *Controller) bulkValidationError(ctx echo.Context, message string) error {
+	return c.HandleError(ctx, errors.Newf("%s", message).
+		Category(errors.CategoryValidation).
+		Component("api-detections").
+		Build(), message, http.StatusBadRequest)
+}
+
+// selectBulkDetections returns the IDs selected by the request. It returns nil
+// IDs after responding with an error.
+func (c *Controller) selectBulkDetections(ctx echo.Context, store bulkNoteStore, req *BulkDetectionRequest) ([]uint, error) {
+	hasFilter := req.Filter != nil && !req.Filter.empty()
+	switch {
+	case len(req.IDs) > 0 && hasFilter:
+		return nil, c.bulkValidationError(ctx, "Select detections either by ids or by filter, not both")
+	case len(req.IDs) > maxBulkDetections:
+		return nil, c.bulkValidationError(ctx, "At most "+strconv.Itoa(maxBulkDetections)+" detections can be changed at once")
+	case len(req.IDs) > 0:
+		return req.IDs, nil
+	case !hasFilter:
+		return nil, c.bulkValidationError(ctx, "Select detections by ids or by a non-empty filter")
+	}
+
+	f := req.Filter
+	for _, dp := range []struct{ value, name string }{{f.Date, "date"}, {f.StartDate, "start_date"}, {f.EndDate, "end_date"}} {
+		if err := validateDateParam(dp.value, dp.name); err != nil {
+			return nil, c.bulkValidationError(ctx, err.Error())
+		}
+	}
+	filters := c.buildAdvancedSearchFilters(&detectionQueryParams{
+		Search:     f.Search,
+		Species:    f.Species,
+		Date:       f.Date,
+		StartDate:  f.StartDate,
+		EndDate:    f.EndDate,
+		Confidence: f.Confidence,
+		TimeOfDay:  f.TimeOfDay,
+		HourRange:  f.HourRange,
+		Verified:   f.Verified,
+		Source:     f.Source,
+		Locked:     f.Locked,
+	})
+
+	// One extra ID tells whether the filter selects too many detections
+	ids, err := store.SearchNoteIDsAdvanced(&filters, maxBulkDetections+1)
+	if err != nil {
+		return nil, c.HandleError(ctx, err, "Failed to select detections", http.StatusInternalServerError)
+	}
+	if len(ids) > maxBulkDetections {
+		return nil, c.bulkValidationError(ctx, "The filter matches more than "+strconv.Itoa(maxBulkDetections)+" detections, narrow it down")
+	}
+	if len(ids) == 0 {
+		return nil, ctx.JSON(http.StatusOK, &BulkDetectionResponse{Changed: []uint{}, Skipped: []uint{}, Missing: []uint{}})
+	}
+	return ids, nil
+}
+
+// resolveSpeciesLabel finds a species in the BirdNET labels by scientific name
+func (c *Controller) resolveSpeciesLabel(scientificName string) (scientific, common, code string, ok bool) {
+	scientificName = strings.TrimSpace(scientificName)
+	if scientificName == "" {
+		return "", "", "", false
+	}
+	for _, label := range c.Settings.BirdNET.Labels {
+		labelSci, labelCommon, labelCode := observation.ParseSpeciesString(label)
+		if strings.EqualFold(labelSci, scientificName) {
+			return labelSci, labelCommon, labelCode, true
+		}
+	}
+	return "", "", "", false
+}
+
+// ListBulkOperations handles GET /api/v2/detections/bulk
+// Lists the bulk operations that can still be undone, newest first.
+func (c *Controller) ListBulkOperations(ctx echo.Context) error {
+	store, err := c.getBulkStore(ctx)
+	if store == nil {
+		return err
+	}
+	c.purgeBulkOperations(store)
+
+	ops, err := store.GetBulkOperations()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list bulk operations", http.StatusInternalServerError)
+	}
+	resp := make([]*BulkOperationResponse, 0, len(ops))
+	for i := range ops {
+		resp = append(resp, newBulkOperationResponse(&ops[i]))
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// UndoBulkOperation handles POST /api/v2/detections/bulk/:id/undo
+// Restores the detections changed by a bulk operation within its undo window.
+// Undoing an operation requires the role its action required, admin for deletes.
+func (c *Controller) UndoBulkOperation(ctx echo.Context) error {
+	store, err := c.getBulkStore(ctx)
+	if store == nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return c.bulkValidationError(ctx, "Invalid bulk operation ID")
+	}
+
+	op, err := store.GetBulkOperation(uint(id))
+	switch {
+	case errors.Is(err, datastore.ErrBulkOperationNotFound):
+		return c.HandleError(ctx, err, "Bulk operation not found", http.StatusNotFound)
+	case err != nil:
+		return c.HandleError(ctx, err, "Failed to check bulk operation", http.StatusInternalServerError)
+	}
+	if required := bulkActionRole(op.Action); !auth.HasRole(ctx, required) {
+		return ctx.JSON(http.StatusForbidden, map[string]string{
+			"error": "This action requires the " + string(required) + " role",
+		})
+	}
+
+	result, err := store.UndoBulkOperation(uint(id))
+	switch {
+	case errors.Is(err, datastore.ErrBulkOperationNotFound):
+		return c.HandleError(ctx, err, "Bulk operation not found", http.StatusNotFound)
+	case errors.Is(err, datastore.ErrBulkOperationNotUndoable):
+		return c.HandleError(ctx, err, "Bulk operation was already undone or its undo window has passed", http.StatusConflict)
+	case err != nil:
+		return c.HandleError(ctx, err, "Failed to undo bulk operation", http.StatusInternalServerError)
+	}
+
+	resp := newBulkDetectionResponse(result)
+	if result.Operation.Action == datastore.BulkActionDelete {
+		for i := range result.Notes {
+			if err := c.restoreHeldClip(result.Operation.ID, result.Notes[i].ClipName); err != nil {
+				resp.ClipErrors++
+				c.logWarnIfEnabled("Failed to restore clip of detection",
+					logger.Int("detection_id", int(result.Notes[i].ID)),
+					logger.Error(err))
+			}
+		}
+		c.removeHeldClips(result.Operation.ID)
+	}
+
+	c.invalidateDetectionCache()
+	if speciesChanged(result.Operation.Action) {
+		c.refreshSpeciesTracker()
+	}
+	c.logInfoIfEnabled("Bulk detection operation undone",
+		logger.Int("operation_id", int(id)),
+		logger.String("action", result.Operation.Action),
+		logger.Int("restored", len(resp.Changed)),
+		logger.String("username", stringFromCtx(ctx, auth.CtxKeyUsername, "")),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// runBulkPurge removes expired undo records and held clips until shutdown
+func (c *Controller) runBulkPurge() {
+	store, ok := c.DS.(bulkNoteStore)
+	if !ok || c.ctx == nil {
+		return
+	}
+	ticker := time.NewTicker(bulkPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.purgeBulkOperations(store)
+		}
+	}
+}
+
+// purgeBulkOperations removes the undo records and held clips of expired or undone operations
+func (c *Controller) purgeBulkOperations(store bulkNoteStore) {
+	ops, err := store.PurgeBulkOperations(time.Now())
+	if err != nil {
+		c.logWarnIfEnabled("Failed to purge expired bulk operations", logger.Error(err))
+		return
+	}
+	for i := range ops {
+		if ops[i].Action == datastore.BulkActionDelete && ops[i].UndoneAt == nil {
+			c.removeHeldClips(ops[i].ID)
+		}
+	}
+}
+
+// bulkClipPaths returns the absolute paths of a clip and of its held copy for an operation
+func (c *Controller) bulkClipPaths(operationID uint, clipName string) (clipPath, heldPath string, ok bool) {
+	if c.SFS == nil || clipName == "" {
+		return "", "", false
+	}
+	relPath, err := c.SFS.ValidateRelativePath(NormalizeClipPath(clipName, c.Settings.Realtime.Audio.Export.Path))
+	if err != nil || relPath == "" || relPath == "." {
+		return "", "", false
+	}
+	base := c.SFS.BaseDir()
+	return filepath.Join(base, relPath),
+		filepath.Join(base, bulkUndoClipsDir, strconv.FormatUint(uint64(operationID), 10), relPath), true
+}
+
+// holdDeletedClip moves the clip of a deleted detection aside so that an undo can
+// restore it, and removes the spectrograms rendered from it
+func (c *Controller) holdDeletedClip(operationID uint, clipName string) error {
+	clipPath, heldPath, ok := c.bulkClipPaths(operationID, clipName)
+	if !ok {
+		return nil
+	}
+	if exists, _ := c.SFS.Exists(clipPath); !exists {
+		return nil
+	}
+	if err := c.SFS.MkdirAll(filepath.Dir(heldPath), bulkClipDirPerm); err != nil {
+		return err
+	}
+	if err := c.SFS.Rename(clipPath, heldPath); err != nil {
+		return err
+	}
+
+	// Spectrograms are named after the clip with the width, e.g. clip_400px.png
+	dir := filepath.Dir(clipPath)
+	prefix := strings.TrimSuffix(filepath.Base(clipPath), filepath.Ext(clipPath)) + "_"
+	entries, err := c.SFS.ReadDir(dir)
+	if err != nil {
+		return nil //nolint:nilerr // The clip is held, leftover spectrograms are harmless
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, spectrogramFileExt) &&
+			strings.Contains(strings.TrimPrefix(name, prefix), spectrogramSizeMark) {
+			if err := c.SFS.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				c.logWarnIfEnabled("Failed to remove spectrogram of deleted detection",
+					logger.String("file", name),
+					logger.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// restoreHeldClip moves a held clip back to its place
+func (c *Controller) restoreHeldClip(operationID uint, clipName string) error {
+	clipPath, heldPath, ok := c.bulkClipPaths(operationID, clipName)
+	if !ok {
+		return nil
+	}
+	if exists, _ := c.SFS.Exists(heldPath); !exists {
+		return nil
+	}
+	if err := c.SFS.MkdirAll(filepath.Dir(clipPath), bulkClipDirPerm); err != nil {
+		return err
+	}
+	return c.SFS.Rename(heldPath, clipPath)
+}
+
+// removeHeldClips deletes the clips held for an operation
+func (c *Controller) removeHeldClips(operationID uint) {
+	if c.SFS == nil {
+		return
+	}
+	dir := filepath.Join(c.SFS.BaseDir(), bulkUndoClipsDir, strconv.FormatUint(uint64(operationID), 10))
+	if exists, _ := c.SFS.Exists(dir); !exists {
+		return
+	}
+	if err := c.SFS.RemoveAll(dir); err != nil {
+		c.logWarnIfEnabled("Failed to remove held clips of bulk operation",
+			logger.Int("operation_id", int(operationID)),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,201 @@
+// detections_bulk_test.go: Tests for bulk detection operations and their undo
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"gorm.io/gorm"
+)
+
+// setupBulkTest creates a controller with a SQLite datastore holding three
+// detections with clips, the last one locked
+func setupBulkTest(t *testing.T) (*Controller, *gorm.DB) {
+	t.Helper()
+
+	controller, db := newSQLiteTestController(t, &datastore.Note{}, &datastore.Results{}, &datastore.NoteReview{},
+		&datastore.NoteLock{}, &datastore.NoteComment{}, &datastore.BulkOperation{}, &datastore.BulkOperationNote{},
+		&datastore.SpeciesCorrection{})
+	controller.Settings.BirdNET.Labels = []string{
+		"Turdus merula_Eurasian Blackbird",
+		"Turdus philomelos_Song Thrush",
+	}
+
+	clipDir := filepath.Join(controller.SFS.BaseDir(), "2024", "05")
+	require.NoError(t, os.MkdirAll(clipDir, 0o755))
+	notes := []datastore.Note{
+		{Date: "2024-05-01", Time: "05:10:00", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird",
+			Confidence: 0.91, ClipName: "2024/05/blackbird.wav"},
+		{Date: "2024-05-01", Time: "05:20:00", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.75},
+		{Date: "2024-05-02", Time: "06:00:00", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird",
+			Confidence: 0.65},
+	}
+	for i := range notes {
+		require.NoError(t, db.Create(&notes[i]).Error)
+	}
+	require.NoError(t, db.Create(&datastore.NoteLock{NoteID: 3}).Error)
+	require.NoError(t, os.WriteFile(filepath.Join(clipDir, "blackbird.wav"), []byte("RIFF"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(clipDir, "blackbird_400px.png"), []byte("PNG"), 0o600))
+
+	return controller, db
+}
+
+// asUser wraps a handler so that it runs as bob with the given role
+func asUser(role auth.Role, handler echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		ctx.Set(auth.CtxKeyRole, role)
+		ctx.Set(auth.CtxKeyUsername, "bob")
+		return handler(ctx)
+	}
+}
+
+func TestBulkReviewDetections(t *testing.T) {
+	controller, db := setupBulkTest(t)
+
+	resp := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.BulkReviewDetections),
+		http.MethodPost, "/api/v2/detections/bulk", `{"ids":[1,2,3,42],"verified":"false_positive"}`))
+	require.NotNil(t, resp.Operation)
+	assert.Equal(t, []uint{1, 2}, resp.Changed)
+	assert.Equal(t, []uint{3}, resp.Skipped)
+	assert.Equal(t, []uint{42}, resp.Missing)
+	assert.Equal(t, "bob", resp.Operation.PerformedBy)
+
+	var review datastore.NoteReview
+	require.NoError(t, db.Where("note_id = ?", 2).First(&review).Error)
+	assert.Equal(t, "false_positive", review.Verified)
+	assert.Equal(t, "bob", review.ReviewedBy)
+
+	undo := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+		http.MethodPost, "/", "", "id", strconv.FormatUint(uint64(resp.Operation.ID), 10)))
+	assert.Equal(t, []uint{1, 2}, undo.Changed)
+	var count int64
+	require.NoError(t, db.Model(&datastore.NoteReview{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+		http.MethodPost, "/", "", "id", strconv.FormatUint(uint64(resp.Operation.ID), 10))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+		http.MethodPost, "/", "", "id", "999")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestBulkDeleteDetectionsByFilter(t *testing.T) {
+	controller, db := setupBulkTest(t)
+	base := controller.SFS.BaseDir()
+
+	resp := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleAdmin, controller.BulkDeleteDetections),
+		http.MethodPost, "/api/v2/detections/bulk", `{"filter":{"species":"Turdus merula"}}`))
+	require.NotNil(t, resp.Operation)
+	assert.Equal(t, []uint{1}, resp.Changed)
+	assert.Equal(t, []uint{3}, resp.Skipped, "locked detections are not deleted")
+	assert.Zero(t, resp.ClipErrors)
+
+	assert.NoFileExists(t, filepath.Join(base, "2024", "05", "blackbird.wav"))
+	assert.NoFileExists(t, filepath.Join(base, "2024", "05", "blackbird_400px.png"))
+	heldClip := filepath.Join(base, bulkUndoClipsDir, strconv.FormatUint(uint64(resp.Operation.ID), 10), "2024", "05", "blackbird.wav")
+	assert.FileExists(t, heldClip)
+
+	t.Run("undo requires admin", func(t *testing.T) {
+		rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+			http.MethodPost, "/", "", "id", strconv.FormatUint(uint64(resp.Operation.ID), 10))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	undo := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleAdmin, controller.UndoBulkOperation),
+		http.MethodPost, "/", "", "id", strconv.FormatUint(uint64(resp.Operation.ID), 10)))
+	assert.Equal(t, []uint{1}, undo.Changed)
+	assert.FileExists(t, filepath.Join(base, "2024", "05", "blackbird.wav"))
+	assert.NoDirExists(t, filepath.Join(base, bulkUndoClipsDir, strconv.FormatUint(uint64(resp.Operation.ID), 10)))
+
+	var note datastore.Note
+	require.NoError(t, db.First(&note, 1).Error)
+	assert.Equal(t, "2024/05/blackbird.wav", note.ClipName)
+
+	t.Run("undone delete still requires admin", func(t *testing.T) {
+		rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+			http.MethodPost, "/", "", "id", strconv.FormatUint(uint64(resp.Operation.ID), 10))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("unknown operation", func(t *testing.T) {
+		rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+			http.MethodPost, "/", "", "id", "12345")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestBulkReassignDetections(t *testing.T) {
+	controller, db := setupBulkTest(t)
+
+	resp := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.BulkReassignDetections),
+		http.MethodPost, "/api/v2/detections/bulk", `{"ids":[1,2],"species":"turdus philomelos"}`))
+	assert.Equal(t, []uint{1, 2}, resp.Changed)
+
+	var note datastore.Note
+	require.NoError(t, db.First(&note, 2).Error)
+	assert.Equal(t, "Turdus philomelos", note.ScientificName)
+	assert.Equal(t, "Song Thrush", note.CommonName)
+
+	t.Run("species outside the labels needs a common name", func(t *testing.T) {
+		rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.BulkReassignDetections),
+			http.MethodPost, "/api/v2/detections/bulk", `{"ids":[1],"species":"Pica pica"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		resp := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.BulkReassignDetections),
+			http.MethodPost, "/api/v2/detections/bulk", `{"ids":[1],"species":"Pica pica","commonName":"Eurasian Magpie"}`))
+		assert.Equal(t, []uint{1}, resp.Changed)
+	})
+}
+
+func TestBulkDetectionValidation(t *testing.T) {
+	controller, _ := setupBulkTest(t)
+
+	tests := []struct {
+		name    string
+		handler echo.HandlerFunc
+		body    string
+	}{
+		{"no selection", controller.BulkLockDetections, `{}`},
+		{"empty filter", controller.BulkLockDetections, `{"filter":{}}`},
+		{"ids and filter", controller.BulkLockDetections, `{"ids":[1],"filter":{"species":"Parus major"}}`},
+		{"invalid date", controller.BulkLockDetections, `{"filter":{"start_date":"2024-13-01"}}`},
+		{"missing verdict", controller.BulkReviewDetections, `{"ids":[1]}`},
+		{"invalid verdict", controller.BulkReviewDetections, `{"ids":[1],"verified":"maybe"}`},
+		{"missing species", controller.BulkReassignDetections, `{"ids":[1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doTestRequest(t, controller, asUser(auth.RoleAdmin, tt.handler),
+				http.MethodPost, "/api/v2/detections/bulk", tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+
+	t.Run("filter without matches changes nothing", func(t *testing.T) {
+		resp := decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleAdmin, controller.BulkLockDetections),
+			http.MethodPost, "/api/v2/detections/bulk", `{"filter":{"species":"Pica pica"}}`))
+		assert.Nil(t, resp.Operation)
+		assert.Empty(t, resp.Changed)
+	})
+
+	t.Run("list undoable operations", func(t *testing.T) {
+		decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleAdmin, controller.BulkUnlockDetections),
+			http.MethodPost, "/api/v2/detections/bulk", `{"ids":[3]}`))
+		rec := doTestRequest(t, controller, controller.ListBulkOperations, http.MethodGet, "/api/v2/detections/bulk", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var ops []BulkOperationResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ops))
+		require.Len(t, ops, 1)
+		assert.Equal(t, datastore.BulkActionUnlock, ops[0].Action)
+	})
+}
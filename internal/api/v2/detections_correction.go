@@ -0,0 +1,511 @@
+// internal/api/v2/detections_correction.go
+package api
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/observation"
+)
+
+const (
+	defaultCorrectionLimit = 100  // Corrections listed per page by default
+	maxCorrectionLimit     = 1000 // Most corrections listed per page
+)
+
+// speciesCorrectionStore is implemented by datastores supporting species corrections
+type speciesCorrectionStore interface {
+	CorrectNoteSpecies(noteID uint, change *datastore.SpeciesCorrectionChange) (*datastore.SpeciesCorrection, error)
+	GetNoteResults(noteID uint) ([]datastore.Results, error)
+	GetSpeciesCorrections(filter *datastore.SpeciesCorrectionFilter) ([]datastore.SpeciesCorrection, int64, error)
+	GetSpeciesCorrectionCounts(filter *datastore.SpeciesCorrectionFilter) ([]datastore.SpeciesCorrectionCount, error)
+}
+
+// SpeciesCorrectionRequest is the body of POST /api/v2/detections/:id/correct
+type SpeciesCorrectionRequest struct {
+	Species    string `json:"species"`              // Scientific name of the correct species
+	CommonName string `json:"commonName,omitempty"` // Common name, required for species not in the model labels
+	Source     string `json:"source,omitempty"`     // label, result or manual, chosen automatically when empty
+}
+
+// SpeciesCorrectionResponse is a species correction in the audit trail
+type SpeciesCorrectionResponse struct {
+	ID                      uint      `json:"id"`
+	NoteID                  uint      `json:"noteId"`
+	OriginalScientificName  string    `json:"originalScientificName"`
+	OriginalCommonName      string    `json:"originalCommonName"`
+	OriginalSpeciesCode     string    `json:"originalSpeciesCode,omitempty"`
+	OriginalConfidence      float64   `json:"originalConfidence"`
+	CorrectedScientificName string    `json:"correctedScientificName"`
+	CorrectedCommonName     string    `json:"correctedCommonName"`
+	CorrectedSpeciesCode    string    `json:"correctedSpeciesCode,omitempty"`
+	Source                  string    `json:"source"`
+	ResultConfidence        float64   `json:"resultConfidence"`
+	BulkOperationID         *uint     `json:"bulkOperationId,omitempty"`
+	CorrectedBy             string    `json:"correctedBy,omitempty"`
+	CorrectedAt             time.Time `json:"correctedAt"`
+}
+
+// SpeciesAlternative is a species the model considered for a detection
+type SpeciesAlternative struct {
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Confidence     float64 `json:"confidence"`
+}
+
+// DetectionCorrectionsResponse lists the corrections of a detection and the
+// alternative species it can be corrected to
+type DetectionCorrectionsResponse struct {
+	Corrections  []SpeciesCorrectionResponse `json:"corrections"`
+	Alternatives []SpeciesAlternative        `json:"alternatives"`
+}
+
+// SpeciesCorrectionListResponse is a page of the correction audit trail
+type SpeciesCorrectionListResponse struct {
+	Corrections []SpeciesCorrectionResponse `json:"corrections"`
+	Total       int64                       `json:"total"`
+	Limit       int                         `json:"limit"`
+	Offset      int                         `json:"offset"`
+}
+
+// CorrectedSpeciesCount is the number of predictions corrected to one species
+type CorrectedSpeciesCount struct {
+	ScientificName string `json:"scientificName"`
+	CommonName     string `json:"commonName"`
+	Count          int64  `json:"count"`
+}
+
+// SpeciesCorrectionSummary summarises the corrections of the predictions of one species
+type SpeciesCorrectionSummary struct {
+	ScientificName    string                  `json:"scientificName"`
+	CommonName        string                  `json:"commonName"`
+	Corrections       int64                   `json:"corrections"`
+	AverageConfidence float64                 `json:"averageConfidence"` // Mean confidence of the corrected predictions
+	CorrectedTo       []CorrectedSpeciesCount `json:"correctedTo"`
+}
+
+// newSpeciesCorrectionResponse converts a correction to its API representation
+func newSpeciesCorrectionResponse(c *datastore.SpeciesCorrection) SpeciesCorrectionResponse {
+	return SpeciesCorrectionResponse{
+		ID:                      c.ID,
+		NoteID:                  c.NoteID,
+		OriginalScientificName:  c.OriginalScientificName,
+		OriginalCommonName:      c.OriginalCommonName,
+		OriginalSpeciesCode:     c.OriginalSpeciesCode,
+		OriginalConfidence:      c.OriginalConfidence,
+		CorrectedScientificName: c.CorrectedScientificName,
+		CorrectedCommonName:     c.CorrectedCommonName,
+		CorrectedSpeciesCode:    c.CorrectedSpeciesCode,
+		Source:                  c.Source,
+		ResultConfidence:        c.ResultConfidence,
+		BulkOperationID:         c.BulkOperationID,
+		CorrectedBy:             c.CorrectedBy,
+		CorrectedAt:             c.CreatedAt,
+	}
+}
+
+// initCorrectionRoutes registers the species correction endpoints on the protected detection group
+func (c *Controller) initCorrectionRoutes(detectionGroup *echo.Group) {
+	reviewer := auth.RequireRole(auth.RoleReviewer)
+	detectionGroup.GET("/corrections", c.GetSpeciesCorrections, reviewer)
+	detectionGroup.GET("/corrections/export", c.ExportSpeciesCorrections, reviewer)
+	detectionGroup.GET("/corrections/summary", c.GetSpeciesCorrectionSummary, reviewer)
+	detectionGroup.GET("/:id/corrections", c.GetDetectionCorrections, reviewer)
+	detectionGroup.POST("/:id/correct", c.CorrectDetectionSpecies, reviewer)
+}
+
+// getCorrectionStore returns the datastore as a speciesCorrectionStore, responding with 503 when unsupported
+func (c *Controller) getCorrectionStore(ctx echo.Context) (speciesCorrectionStore, error) {
+	store, ok := c.DS.(speciesCorrectionStore)
+	if !ok {
+		return nil, c.HandleError(ctx, errors.Newf("datastore does not support species corrections").
+			Category(errors.CategorySystem).
+			Component("api-detections").
+			Build(), "Species corrections are not available", http.StatusServiceUnavailable)
+	}
+	return store, nil
+}
+
+// parseDetectionID parses the :id path parameter, responding with 400 when invalid
+func (c *Controller) parseDetectionID(ctx echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, c.bulkValidationError(ctx, "Invalid detection ID")
+	}
+	return uint(id), nil
+}
+
+// speciesAlternatives converts the results of a detection to the species they name
+func speciesAlternatives(results []datastore.Results) []SpeciesAlternative {
+	alternatives := make([]SpeciesAlternative, 0, len(results))
+	for i := range results {
+		scientific, common, _ := observation.ParseSpeciesString(results[i].Species)
+		if scientific == "" {
+			continue
+		}
+		alternatives = append(alternatives, SpeciesAlternative{
+			ScientificName: scientific,
+			CommonName:     common,
+			Confidence:     float64(results[i].Confidence),
+		})
+	}
+	return alternatives
+}
+
+// CorrectDetectionSpecies handles POST /api/v2/detections/:id/correct
+// Changes the species of a detection to one from its alternative results, the
+// model labels or, with a common name, a species the model does not know. The
+// original prediction is kept in the correction audit trail.
+func (c *Controller) CorrectDetectionSpecies(ctx echo.Context) error {
+	store, err := c.getCorrectionStore(ctx)
+	if store == nil {
+		return err
+	}
+	id, err := c.parseDetectionID(ctx)
+	if id == 0 {
+		return err
+	}
+
+	req := &SpeciesCorrectionRequest{}
+	if err := ctx.Bind(req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	req.Species = strings.TrimSpace(req.Species)
+	if req.Species == "" {
+		return c.bulkValidationError(ctx, "species is required")
+	}
+
+	change := &datastore.SpeciesCorrectionChange{CorrectedBy: stringFromCtx(ctx, auth.CtxKeyUsername, "")}
+	if req.Source == "" || req.Source == datastore.CorrectionSourceResult {
+		results, err := store.GetNoteResults(id)
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to get detection results", http.StatusInternalServerError)
+		}
+		for _, alt := range speciesAlternatives(results) {
+			if strings.EqualFold(alt.ScientificName, req.Species) {
+				change.ScientificName, change.CommonName = alt.ScientificName, alt.CommonName
+				change.Source = datastore.CorrectionSourceResult
+				break
+			}
+		}
+		if change.Source == "" && req.Source == datastore.CorrectionSourceResult {
+			return c.bulkValidationError(ctx, "species is not among the results of the detection")
+		}
+	}
+	if change.Source == "" && req.Source != datastore.CorrectionSourceManual {
+		if scientific, common, _, ok := c.resolveSpeciesLabel(req.Species); ok {
+			change.ScientificName, change.CommonName = scientific, common
+			change.Source = datastore.CorrectionSourceLabel
+		} else if req.Source == datastore.CorrectionSourceLabel {
+			return c.bulkValidationError(ctx, "species must be a scientific name from the BirdNET labels")
+		}
+	}
+	if change.Source == "" {
+		if strings.TrimSpace(req.CommonName) == "" {
+			return c.bulkValidationError(ctx, "commonName is required for species not in the BirdNET labels")
+		}
+		change.ScientificName, change.CommonName = req.Species, strings.TrimSpace(req.CommonName)
+		change.Source = datastore.CorrectionSourceManual
+	}
+	// Species codes come from the labels, also for species chosen from the results
+	if _, _, code, ok := c.resolveSpeciesLabel(change.ScientificName); ok {
+		change.SpeciesCode = code
+	}
+
+	correction, err := store.CorrectNoteSpecies(id, change)
+	switch {
+	case errors.Is(err, datastore.ErrNoteNotFound):
+		return c.HandleError(ctx, err, "Detection not found", http.StatusNotFound)
+	case errors.Is(err, datastore.ErrNoteLocked):
+		return c.HandleError(ctx, err, "Detection is locked and its species cannot be changed", http.StatusConflict)
+	case errors.Is(err, datastore.ErrSpeciesUnchanged):
+		return c.HandleError(ctx, err, "Detection already has this species", http.StatusBadRequest)
+	case err != nil:
+		return c.HandleError(ctx, err, "Failed to correct detection species", http.StatusInternalServerError)
+	}
+
+	c.invalidateDetectionCache()
+	c.refreshSpeciesTracker()
+	c.logInfoIfEnabled("Detection species corrected",
+		logger.Int("detection_id", int(id)),
+		logger.String("original_species", correction.OriginalScientificName),
+		logger.String("corrected_species", correction.CorrectedScientificName),
+		logger.String("source", correction.Source),
+		logger.String("username", correction.CorrectedBy),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, newSpeciesCorrectionResponse(correction))
+}
+
+// GetDetectionCorrections handles GET /api/v2/detections/:id/corrections
+// Lists the corrections of a detection, newest first, and the alternative
+// species from its results.
+func (c *Controller) GetDetectionCorrections(ctx echo.Context) error {
+	store, err := c.getCorrectionStore(ctx)
+	if store == nil {
+		return err
+	}
+	id, err := c.parseDetectionID(ctx)
+	if id == 0 {
+		return err
+	}
+
+	corrections, _, err := store.GetSpeciesCorrections(&datastore.SpeciesCorrectionFilter{NoteID: id})
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get species corrections", http.StatusInternalServerError)
+	}
+	results, err := store.GetNoteResults(id)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get detection results", http.StatusInternalServerError)
+	}
+
+	resp := DetectionCorrectionsResponse{
+		Corrections:  make([]SpeciesCorrectionResponse, 0, len(corrections)),
+		Alternatives: speciesAlternatives(results),
+	}
+	for i := range corrections {
+		resp.Corrections = append(resp.Corrections, newSpeciesCorrectionResponse(&corrections[i]))
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// parseCorrectionFilter reads the species, start_date and end_date query parameters,
+// responding with 400 when invalid. The end date is inclusive.
+func (c *Controller) parseCorrectionFilter(ctx echo.Context) (*datastore.SpeciesCorrectionFilter, error) {
+	filter := &datastore.SpeciesCorrectionFilter{Species: strings.TrimSpace(ctx.QueryParam("species"))}
+	for _, dp := range []struct {
+		name   string
+		target *time.Time
+		days   int
+	}{{"start_date", &filter.StartDate, 0}, {"end_date", &filter.EndDate, 1}} {
+		value := ctx.QueryParam(dp.name)
+		if err := validateDateParam(value, dp.name); err != nil {
+			return nil, c.bulkValidationError(ctx, err.Error())
+		}
+		if value != "" {
+			date, _ := time.ParseInLocation(time.DateOnly, value, time.Local)
+			*dp.target = date.AddDate(0, 0, dp.days)
+		}
+	}
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && !filter.StartDate.Before(filter.EndDate) {
+		return nil, c.bulkValidationError(ctx, "start_date must not be after end_date")
+	}
+	return filter, nil
+}
+
+// GetSpeciesCorrections handles GET /api/v2/detections/corrections
+// Lists the correction audit trail, newest first, filtered by species and date range.
+func (c *Controller) GetSpeciesCorrections(ctx echo.Context) error {
+	store, err := c.getCorrectionStore(ctx)
+	if store == nil {
+		return err
+	}
+	filter, err := c.parseCorrectionFilter(ctx)
+	if filter == nil {
+		return err
+	}
+
+	filter.Limit = defaultCorrectionLimit
+	if v := ctx.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxCorrectionLimit {
+			return c.bulkValidationError(ctx, "limit must be between 1 and "+strconv.Itoa(maxCorrectionLimit))
+		}
+		filter.Limit = limit
+	}
+	if v := ctx.QueryParam("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return c.bulkValidationError(ctx, "offset must be a non-negative number")
+		}
+		filter.Offset = offset
+	}
+
+	corrections, total, err := store.GetSpeciesCorrections(filter)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get species corrections", http.StatusInternalServerError)
+	}
+	resp := SpeciesCorrectionListResponse{
+		Corrections: make([]SpeciesCorrectionResponse, 0, len(corrections)),
+		Total:       total,
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	}
+	for i := range corrections {
+		resp.Corrections = append(resp.Corrections, newSpeciesCorrectionResponse(&corrections[i]))
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetSpeciesCorrectionSummary handles GET /api/v2/detections/corrections/summary
+// Counts the corrections by predicted species and the species they were corrected
+// to, the species most often corrected first.
+func (c *Controller) GetSpeciesCorrectionSummary(ctx echo.Context) error {
+	store, err := c.getCorrectionStore(ctx)
+	if store == nil {
+		return err
+	}
+	filter, err := c.parseCorrectionFilter(ctx)
+	if filter == nil {
+		return err
+	}
+
+	counts, err := store.GetSpeciesCorrectionCounts(filter)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to count species corrections", http.StatusInternalServerError)
+	}
+
+	summaries := make([]*SpeciesCorrectionSummary, 0)
+	bySpecies := make(map[string]*SpeciesCorrectionSummary)
+	for i := range counts {
+		count := &counts[i]
+		summary, ok := bySpecies[count.OriginalScientificName]
+		if !ok {
+			summary = &SpeciesCorrectionSummary{
+				ScientificName: count.OriginalScientificName,
+				CommonName:     count.OriginalCommonName,
+				CorrectedTo:    []CorrectedSpeciesCount{},
+			}
+			bySpecies[count.OriginalScientificName] = summary
+			summaries = append(summaries, summary)
+		}
+		// Weighted mean over the species pairs of the prediction
+		total := summary.Corrections + count.Count
+		summary.AverageConfidence = (summary.AverageConfidence*float64(summary.Corrections) +
+			count.AverageConfidence*float64(count.Count)) / float64(total)
+		summary.Corrections = total
+		summary.CorrectedTo = append(summary.CorrectedTo, CorrectedSpeciesCount{
+			ScientificName: count.CorrectedScientificName,
+			CommonName:     count.CorrectedCommonName,
+			Count:          count.Count,
+		})
+	}
+	// Counts are ordered by pair, order the species by their total
+	slices.SortStableFunc(summaries, func(a, b *SpeciesCorrectionSummary) int {
+		return cmp.Compare(b.Corrections, a.Corrections)
+	})
+	return ctx.JSON(http.StatusOK, summaries)
+}
+
+// correctionExportColumns is the header row of the CSV correction export
+var correctionExportColumns = []string{
+	"id", "note_id", "corrected_at", "original_scientific_name", "original_common_name",
+	"original_species_code", "original_confidence", "corrected_scientific_name",
+	"corrected_common_name", "corrected_species_code", "source", "result_confidence",
+	"bulk_operation_id", "corrected_by",
+}
+
+// ExportSpeciesCorrections handles GET /api/v2/detections/corrections/export
+// Downloads the correction audit trail as CSV or NDJSON, filtered like GET
+// /api/v2/detections/corrections, for measuring model accuracy per species.
+func (c *Controller) ExportSpeciesCorrections(ctx echo.Context) error {
+	store, err := c.getCorrectionStore(ctx)
+	if store == nil {
+		return err
+	}
+
+	format := strings.ToLower(ctx.QueryParam("format"))
+	switch format {
+	case "":
+		format = ExportFormatCSV
+	case "jsonl":
+		format = ExportFormatNDJSON
+	case ExportFormatCSV, ExportFormatNDJSON:
+	default:
+		return c.bulkValidationError(ctx, "Invalid format, use csv or ndjson")
+	}
+	filter, err := c.parseCorrectionFilter(ctx)
+	if filter == nil {
+		return err
+	}
+
+	// The audit trail only grows with manual corrections, it is read in one query
+	corrections, _, err := store.GetSpeciesCorrections(filter)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to export species corrections", http.StatusInternalServerError)
+	}
+
+	c.setExportHeaders(ctx, "species-corrections", format)
+	ctx.Response().WriteHeader(http.StatusOK)
+	if format == ExportFormatNDJSON {
+		enc := json.NewEncoder(ctx.Response())
+		for i := 0; i < len(corrections) && err == nil; i++ {
+			err = enc.Encode(newSpeciesCorrectionResponse(&corrections[i]))
+		}
+	} else {
+		err = writeCorrectionCSV(ctx.Response(), corrections)
+	}
+	if err != nil {
+		// The status has already been sent, the client sees a truncated download
+		c.logErrorIfEnabled("Failed to finish species correction export",
+			logger.Error(err),
+			logger.String("ip", ctx.RealIP()))
+		return nil
+	}
+
+	c.logInfoIfEnabled("Species corrections exported",
+		logger.String("format", format),
+		logger.Int("exported", len(corrections)),
+		logger.String("ip", ctx.RealIP()))
+	return nil
+}
+
+// writeCorrectionCSV writes corrections as CSV with a header row
+func writeCorrectionCSV(out io.Writer, corrections []datastore.SpeciesCorrection) error {
+	w := csv.NewWriter(out)
+	err := w.Write(correctionExportColumns)
+	for i := 0; i < len(corrections) && err == nil; i++ {
+		r := &corrections[i]
+		bulkID := ""
+		if r.BulkOperationID != nil {
+			bulkID = strconv.FormatUint(uint64(*r.BulkOperationID), 10)
+		}
+		err = w.Write([]string{
+			strconv.FormatUint(uint64(r.ID), 10),
+			strconv.FormatUint(uint64(r.NoteID), 10),
+			formatExportTime(r.CreatedAt),
+			r.OriginalScientificName,
+			r.OriginalCommonName,
+			r.OriginalSpeciesCode,
+			strconv.FormatFloat(r.OriginalConfidence, 'f', 4, 64),
+			r.CorrectedScientificName,
+			r.CorrectedCommonName,
+			r.CorrectedSpeciesCode,
+			r.Source,
+			strconv.FormatFloat(r.ResultConfidence, 'f', 4, 64),
+			bulkID,
+			r.CorrectedBy,
+		})
+	}
+	w.Flush()
+	if err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// refreshSpeciesTracker reloads the new species tracker from the database in the
+// background, so first and last sightings follow corrected species
+func (c *Controller) refreshSpeciesTracker() {
+	if c.Processor == nil || c.Processor.NewSpeciesTracker == nil {
+		return
+	}
+	tracker := c.Processor.NewSpeciesTracker
+	c.wg.Go(func() {
+		if err := tracker.InitFromDatabase(); err != nil {
+			c.logWarnIfEnabled("Failed to refresh species tracker after species correction", logger.Error(err))
+		}
+	})
+}
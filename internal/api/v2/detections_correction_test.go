@@ -0,0 +1,153 @@
+// detections_correction_test.go: Tests for species corrections and their audit trail
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+func TestCorrectDetectionSpecies(t *testing.T) {
+	controller, db := setupBulkTest(t)
+	require.NoError(t, db.Create(&datastore.Results{NoteID: 2, Species: "Periparus ater_Coal Tit", Confidence: 0.42}).Error)
+
+	rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.CorrectDetectionSpecies),
+		http.MethodPost, "/", `{"species":"periparus ater"}`, "id", "2")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var correction SpeciesCorrectionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &correction))
+	assert.Equal(t, "Parus major", correction.OriginalScientificName)
+	assert.Equal(t, "Periparus ater", correction.CorrectedScientificName)
+	assert.Equal(t, "Coal Tit", correction.CorrectedCommonName)
+	assert.Equal(t, datastore.CorrectionSourceResult, correction.Source)
+	assert.InDelta(t, 0.42, correction.ResultConfidence, 0.0001)
+	assert.Equal(t, "bob", correction.CorrectedBy)
+
+	var note datastore.Note
+	require.NoError(t, db.First(&note, 2).Error)
+	assert.Equal(t, "Periparus ater", note.ScientificName)
+
+	rec = doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.CorrectDetectionSpecies),
+		http.MethodPost, "/", `{"species":"Turdus philomelos"}`, "id", "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &correction))
+	assert.Equal(t, datastore.CorrectionSourceLabel, correction.Source)
+	assert.Equal(t, "Song Thrush", correction.CorrectedCommonName)
+
+	rec = doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.CorrectDetectionSpecies),
+		http.MethodPost, "/", `{"species":"Pica pica","commonName":"Eurasian Magpie"}`, "id", "1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &correction))
+	assert.Equal(t, datastore.CorrectionSourceManual, correction.Source)
+	assert.Equal(t, "Turdus philomelos", correction.OriginalScientificName)
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			id   uint
+			body string
+			code int
+		}{
+			{"missing species", 1, `{}`, http.StatusBadRequest},
+			{"unknown species without common name", 1, `{"species":"Corvus corax"}`, http.StatusBadRequest},
+			{"species not in results", 1, `{"species":"Turdus merula","source":"result"}`, http.StatusBadRequest},
+			{"unchanged species", 1, `{"species":"Pica pica","commonName":"Eurasian Magpie"}`, http.StatusBadRequest},
+			{"locked detection", 3, `{"species":"Turdus philomelos"}`, http.StatusConflict},
+			{"unknown detection", 99, `{"species":"Turdus philomelos"}`, http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.CorrectDetectionSpecies),
+					http.MethodPost, "/", tt.body, "id", strconv.FormatUint(uint64(tt.id), 10))
+				assert.Equal(t, tt.code, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("detection history and alternatives", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.GetDetectionCorrections, http.MethodGet, "/", "", "id", "2")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp DetectionCorrectionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Corrections, 1)
+		require.Len(t, resp.Alternatives, 1)
+		assert.Equal(t, "Periparus ater", resp.Alternatives[0].ScientificName)
+	})
+}
+
+func TestSpeciesCorrectionReports(t *testing.T) {
+	controller, _ := setupBulkTest(t)
+
+	decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.BulkReassignDetections),
+		http.MethodPost, "/api/v2/detections/bulk", `{"ids":[1,2],"species":"Turdus philomelos"}`))
+
+	t.Run("list", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.GetSpeciesCorrections, http.MethodGet, "/?species=Parus+major&limit=10", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp SpeciesCorrectionListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, int64(1), resp.Total)
+		require.Len(t, resp.Corrections, 1)
+		require.NotNil(t, resp.Corrections[0].BulkOperationID, "bulk reassigns are part of the audit trail")
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.GetSpeciesCorrectionSummary, http.MethodGet, "/", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp []SpeciesCorrectionSummary
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp, 2)
+		for _, summary := range resp {
+			assert.Equal(t, int64(1), summary.Corrections)
+			require.Len(t, summary.CorrectedTo, 1)
+			assert.Equal(t, "Turdus philomelos", summary.CorrectedTo[0].ScientificName)
+		}
+	})
+
+	t.Run("csv export", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ExportSpeciesCorrections, http.MethodGet, "/", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "species-corrections-")
+		rows, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, correctionExportColumns, rows[0])
+	})
+
+	t.Run("ndjson export", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ExportSpeciesCorrections, http.MethodGet, "/?format=ndjson", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Len(t, strings.Split(strings.TrimSpace(rec.Body.String()), "\n"), 2)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, target := range []string{"/?start_date=2024-02-30", "/?start_date=2024-05-02&end_date=2024-05-01", "/?limit=0"} {
+			rec := doTestRequest(t, controller, controller.GetSpeciesCorrections, http.MethodGet, target, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+		rec := doTestRequest(t, controller, controller.ExportSpeciesCorrections, http.MethodGet, "/?format=xml", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("undo removes bulk corrections", func(t *testing.T) {
+		ops := doTestRequest(t, controller, controller.ListBulkOperations, http.MethodGet, "/", "")
+		var list []BulkOperationResponse
+		require.NoError(t, json.Unmarshal(ops.Body.Bytes(), &list))
+		require.Len(t, list, 1)
+		decodeJSON[BulkDetectionResponse](t, doTestRequest(t, controller, asUser(auth.RoleReviewer, controller.UndoBulkOperation),
+			http.MethodPost, "/", "", "id", strconv.FormatUint(uint64(list[0].ID), 10)))
+
+		rec := doTestRequest(t, controller, controller.GetSpeciesCorrections, http.MethodGet, "/", "")
+		var resp SpeciesCorrectionListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Zero(t, resp.Total)
+	})
+}
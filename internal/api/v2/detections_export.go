@@ -0,0 +1,604 @@
+// internal/api/v2/detections_export.go
+package api
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Detection export formats
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatDwCA   = "dwca" // Darwin Core Archive
+)
+
+const (
+	exportBatchSize     = 500              // Detections read from the database at a time
+	exportWriteDeadline = 60 * time.Second // Write deadline per batch, extended while the export runs
+	dwcTermsNamespace   = "http://rs.tdwg.org/dwc/terms/"
+)
+
+// noteExporter is implemented by datastores that can stream notes matching advanced search filters
+type noteExporter interface {
+	ExportNotesAdvanced(filters *datastore.AdvancedSearchFilters, batchSize int, fn func(notes []datastore.Note) error) error
+}
+
+// DetectionExportRecord is a detection as written by the export endpoint
+type DetectionExportRecord struct {
+	ID             uint     `json:"id"`
+	Date           string   `json:"date"`
+	Time           string   `json:"time"`
+	BeginTime      string   `json:"beginTime"`
+	EndTime        string   `json:"endTime"`
+	ScientificName string   `json:"scientificName"`
+	CommonName     string   `json:"commonName"`
+	SpeciesCode    string   `json:"speciesCode"`
+	Confidence     float64  `json:"confidence"`
+	Source         string   `json:"source"`
+	SourceID       string   `json:"sourceId,omitempty"`
+	SourceNode     string   `json:"sourceNode,omitempty"`
+	Latitude       float64  `json:"latitude"`
+	Longitude      float64  `json:"longitude"`
+	Verified       string   `json:"verified"`
+	ReviewedBy     string   `json:"reviewedBy,omitempty"`
+	Locked         bool     `json:"locked"`
+	Comments       []string `json:"comments,omitempty"`
+}
+
+// detectionExportWriter writes exported detections in one format
+type detectionExportWriter interface {
+	Write(record *DetectionExportRecord) error
+	// Close writes any trailing data, it does not close the underlying writer
+	Close() error
+}
+
+// ExportDetections handles GET /api/v2/detections/export
+// Streams all detections matching the same filters as GET /api/v2/detections as
+// CSV, NDJSON or a Darwin Core Archive. Pagination parameters are ignored.
+func (c *Controller) ExportDetections(ctx echo.Context) error {
+	exporter, ok := c.DS.(noteExporter)
+	if !ok {
+		return c.HandleError(ctx, errors.Newf("datastore does not support exporting detections").
+			Category(errors.CategorySystem).
+			Component("api-export").
+			Build(), "Detection export is not available", http.StatusServiceUnavailable)
+	}
+
+	format := strings.ToLower(ctx.QueryParam("format"))
+	switch format {
+	case "":
+		format = ExportFormatCSV
+	case "jsonl":
+		format = ExportFormatNDJSON
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatDwCA:
+	default:
+		return c.HandleError(ctx, errors.Newf("unsupported export format %q", format).
+			Category(errors.CategoryValidation).
+			Component("api-export").
+			Build(), "Invalid format, use csv, ndjson or dwca", http.StatusBadRequest)
+	}
+
+	params, err := c.parseDetectionQueryParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	filters := c.buildAdvancedSearchFilters(params)
+	filters.Limit, filters.Offset = 0, 0
+	// Occurrence datasets should not publish detections a reviewer rejected,
+	// unless the review state was filtered explicitly
+	skipFalsePositives := format == ExportFormatDwCA && params.Verified == ""
+
+	var writer detectionExportWriter
+	var count int
+	start := func() (err error) {
+		c.setExportHeaders(ctx, "detections", format)
+		ctx.Response().WriteHeader(http.StatusOK)
+		writer, err = c.newDetectionExportWriter(ctx.Response(), format)
+		return err
+	}
+
+	err = exporter.ExportNotesAdvanced(&filters, exportBatchSize, func(notes []datastore.Note) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		c.extendExportWriteDeadline(ctx)
+		for i := range notes {
+			record := c.newDetectionExportRecord(&notes[i])
+			if skipFalsePositives && record.Verified == VerificationStatusFalsePositive {
+				continue
+			}
+			if err := writer.Write(&record); err != nil {
+				return err
+			}
+			count++
+		}
+		ctx.Response().Flush()
+		// Stop reading the database when the client has gone away
+		return ctx.Request().Context().Err()
+	})
+	if err != nil {
+		if writer == nil {
+			return c.HandleError(ctx, err, "Failed to export detections", http.StatusInternalServerError)
+		}
+		// The status has already been sent, the client sees a truncated download
+		c.logErrorIfEnabled("Detection export interrupted",
+			logger.String("format", format),
+			logger.Int("exported", count),
+			logger.Error(err),
+			logger.String("ip", ctx.RealIP()))
+		return nil
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		c.logErrorIfEnabled("Failed to finish detection export",
+			logger.String("format", format),
+			logger.Error(err),
+			logger.String("ip", ctx.RealIP()))
+		return nil
+	}
+
+	c.logInfoIfEnabled("Detections exported",
+		logger.String("format", format),
+		logger.Int("exported", count),
+		logger.String("ip", ctx.RealIP()))
+	return nil
+}
+
+// setExportHeaders sets the content type and download file name of an export,
+// the file name starts with the given name followed by the time of the export
+func (c *Controller) setExportHeaders(ctx echo.Context, name, format string) {
+	var contentType, extension string
+	switch format {
+	case ExportFormatNDJSON:
+		contentType, extension = "application/x-ndjson", "ndjson"
+	case ExportFormatDwCA:
+		contentType, extension = "application/zip", "zip"
+	default:
+		contentType, extension = "text/csv; charset=utf-8", "csv"
+	}
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), extension)
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderContentType, contentType)
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	header.Set("Cache-Control", "no-store")
+	header.Set("X-Content-Type-Options", "nosniff")
+}
+
+// extendExportWriteDeadline keeps the server WriteTimeout from cutting off long exports
+func (c *Controller) extendExportWriteDeadline(ctx echo.Context) {
+	if conn, ok := ctx.Response().Writer.(WriteDeadlineSetter); ok {
+		if err := conn.SetWriteDeadline(time.Now().Add(exportWriteDeadline)); err != nil {
+			c.logDebugIfEnabled("Failed to set write deadline for detection export", logger.Error(err))
+		}
+	}
+}
+
+// newDetectionExportRecord converts a note to an export record, falling back to
+// the configured station location for detections saved without coordinates
+func (c *Controller) newDetectionExportRecord(note *datastore.Note) DetectionExportRecord {
+	record := DetectionExportRecord{
+		ID:             note.ID,
+		Date:           note.Date,
+		Time:           note.Time,
+		BeginTime:      formatExportTime(note.BeginTime),
+		EndTime:        formatExportTime(note.EndTime),
+		ScientificName: note.ScientificName,
+		CommonName:     note.CommonName,
+		SpeciesCode:    note.SpeciesCode,
+		Confidence:     note.Confidence,
+		Source:         note.Source.DisplayName,
+		SourceID:       note.Source.ID,
+		SourceNode:     note.SourceNode,
+		Latitude:       note.Latitude,
+		Longitude:      note.Longitude,
+		Verified:       c.mapVerificationStatus(note.Verified),
+		Locked:         note.Locked,
+		Comments:       extractNoteComments(note.Comments),
+	}
+	if note.Review != nil {
+		record.ReviewedBy = note.Review.ReviewedBy
+	}
+	if record.Latitude == 0 && record.Longitude == 0 && c.Settings != nil {
+		record.Latitude = c.Settings.BirdNET.Latitude
+		record.Longitude = c.Settings.BirdNET.Longitude
+	}
+	return record
+}
+
+// formatExportTime formats a time as RFC 3339, zero times are left empty
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// newDetectionExportWriter creates the writer of an export format
+func (c *Controller) newDetectionExportWriter(w io.Writer, format string) (detectionExportWriter, error) {
+	switch format {
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}, nil
+	case ExportFormatDwCA:
+		return newDwCAExportWriter(w, dwcaStationFromSettings(c.Settings))
+	default:
+		return newCSVExportWriter(w)
+	}
+}
+
+// csvExportColumns are the header of CSV exports
+var csvExportColumns = []string{
+	"id", "date", "time", "begin_time", "end_time", "scientific_name", "common_name", "species_code",
+	"confidence", "source", "source_id", "source_node", "latitude", "longitude", "verified",
+	"reviewed_by", "locked", "comments",
+}
+
+// csvExportWriter writes detections as CSV with a header row
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvExportColumns); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: cw}, nil
+}
+
+func (cw *csvExportWriter) Write(r *DetectionExportRecord) error {
+	err := cw.w.Write([]string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		r.Date,
+		r.Time,
+		r.BeginTime,
+		r.EndTime,
+		r.ScientificName,
+		r.CommonName,
+		r.SpeciesCode,
+		strconv.FormatFloat(r.Confidence, 'f', 4, 64),
+		r.Source,
+		r.SourceID,
+		r.SourceNode,
+		strconv.FormatFloat(r.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(r.Longitude, 'f', -1, 64),
+		r.Verified,
+		r.ReviewedBy,
+		strconv.FormatBool(r.Locked),
+		strings.Join(r.Comments, " | "),
+	})
+	if err != nil {
+		return err
+	}
+	// Flush every row so the batch flush of the response sends complete rows
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvExportWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// ndjsonExportWriter writes one JSON object per line
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (nw *ndjsonExportWriter) Write(r *DetectionExportRecord) error {
+	return nw.enc.Encode(r)
+}
+
+func (nw *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// dwcOccurrenceTerms are the Darwin Core terms of the occurrence.txt columns,
+// the first column is the core ID
+var dwcOccurrenceTerms = []string{
+	"occurrenceID", "basisOfRecord", "occurrenceStatus", "eventDate", "scientificName",
+	"vernacularName", "decimalLatitude", "decimalLongitude", "geodeticDatum", "locality",
+	"recordedBy", "identifiedBy", "identificationVerificationStatus", "identificationRemarks",
+	"samplingProtocol", "occurrenceRemarks",
+}
+
+// dwcaExportWriter writes a Darwin Core Archive. occurrence.txt is streamed
+// first, meta.xml and eml.xml are added when the export is closed.
+type dwcaExportWriter struct {
+	zw        *zip.Writer
+	tsv       *bufio.Writer
+	station   dwcaStation
+	firstDate string // Earliest and latest detection dates for the temporal coverage
+	lastDate  string
+}
+
+// dwcaStation describes the station in the dataset metadata
+type dwcaStation struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// dwcaStationFromSettings returns the station name and location of the settings
+func dwcaStationFromSettings(settings *conf.Settings) dwcaStation {
+	station := dwcaStation{Name: "BirdNET-Go"}
+	if settings == nil {
+		return station
+	}
+	if settings.Main.Name != "" {
+		station.Name = settings.Main.Name
+	}
+	station.Latitude = settings.BirdNET.Latitude
+	station.Longitude = settings.BirdNET.Longitude
+	return station
+}
+
+func newDwCAExportWriter(w io.Writer, station dwcaStation) (*dwcaExportWriter, error) {
+	zw := zip.NewWriter(w)
+	occurrences, err := zw.Create("occurrence.txt")
+	if err != nil {
+		return nil, err
+	}
+	dw := &dwcaExportWriter{zw: zw, tsv: bufio.NewWriter(occurrences), station: station}
+	if _, err := dw.tsv.WriteString(strings.Join(dwcOccurrenceTerms, "\t") + "\n"); err != nil {
+		return nil, err
+	}
+	return dw, nil
+}
+
+func (dw *dwcaExportWriter) Write(r *DetectionExportRecord) error {
+	identifiedBy := "BirdNET"
+	if r.ReviewedBy != "" {
+		identifiedBy += " | " + r.ReviewedBy
+	}
+	locality := r.Source
+	if locality == "" {
+		locality = dw.station.Name
+	}
+
+	fields := []string{
+		fmt.Sprintf("urn:birdnet-go:%s:detection:%d", url.PathEscape(dw.station.Name), r.ID),
+		"MachineObservation",
+		"present",
+		dwcEventDate(r),
+		r.ScientificName,
+		r.CommonName,
+		strconv.FormatFloat(r.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(r.Longitude, 'f', -1, 64),
+		"WGS84",
+		locality,
+		dw.station.Name,
+		identifiedBy,
+		dwcVerificationStatus(r.Verified),
+		"BirdNET confidence " + strconv.FormatFloat(r.Confidence, 'f', 4, 64),
+		"passive acoustic monitoring",
+		strings.Join(r.Comments, " | "),
+	}
+	for i, field := range fields {
+		fields[i] = dwcSanitizeField(field)
+	}
+	if _, err := dw.tsv.WriteString(strings.Join(fields, "\t") + "\n"); err != nil {
+		return err
+	}
+
+	if dw.firstDate == "" || r.Date < dw.firstDate {
+		dw.firstDate = r.Date
+	}
+	if r.Date > dw.lastDate {
+		dw.lastDate = r.Date
+	}
+	// Push the buffered rows through the zip compressor so that they reach the client
+	return dw.tsv.Flush()
+}
+
+func (dw *dwcaExportWriter) Close() error {
+	if err := dw.tsv.Flush(); err != nil {
+		return err
+	}
+	if err := dw.writeXML("meta.xml", newDwCAMeta()); err != nil {
+		return err
+	}
+	if err := dw.writeXML("eml.xml", newDwCAEML(dw.station, dw.firstDate, dw.lastDate)); err != nil {
+		return err
+	}
+	return dw.zw.Close()
+}
+
+// writeXML adds an XML document to the archive
+func (dw *dwcaExportWriter) writeXML(name string, document any) error {
+	w, err := dw.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(document); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// dwcEventDate returns the local detection time with its UTC offset, or the date
+// alone when the time cannot be parsed
+func dwcEventDate(r *DetectionExportRecord) string {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", r.Date+" "+r.Time, time.Local)
+	if err != nil {
+		return r.Date
+	}
+	return t.Format(time.RFC3339)
+}
+
+// dwcVerificationStatus maps a review state to identificationVerificationStatus
+func dwcVerificationStatus(verified string) string {
+	switch verified {
+	case VerificationStatusCorrect:
+		return "verified by human reviewer"
+	case VerificationStatusFalsePositive:
+		return "rejected by human reviewer"
+	default:
+		return "unverified"
+	}
+}
+
+// dwcSanitizeField removes the characters that would break the unquoted tab separated occurrence file
+func dwcSanitizeField(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, value)
+}
+
+// dwcaMeta is the meta.xml descriptor of the archive
+type dwcaMeta struct {
+	XMLName  xml.Name `xml:"http://rs.tdwg.org/dwc/text/ archive"`
+	Metadata string   `xml:"metadata,attr"`
+	Core     dwcaCore `xml:"core"`
+}
+
+type dwcaCore struct {
+	Encoding           string      `xml:"encoding,attr"`
+	FieldsTerminatedBy string      `xml:"fieldsTerminatedBy,attr"`
+	LinesTerminatedBy  string      `xml:"linesTerminatedBy,attr"`
+	FieldsEnclosedBy   string      `xml:"fieldsEnclosedBy,attr"`
+	IgnoreHeaderLines  int         `xml:"ignoreHeaderLines,attr"`
+	RowType            string      `xml:"rowType,attr"`
+	Location           string      `xml:"files>location"`
+	ID                 dwcaIndex   `xml:"id"`
+	Fields             []dwcaField `xml:"field"`
+}
+
+type dwcaIndex struct {
+	Index int `xml:"index,attr"`
+}
+
+type dwcaField struct {
+	Index int    `xml:"index,attr"`
+	Term  string `xml:"term,attr"`
+}
+
+func newDwCAMeta() dwcaMeta {
+	fields := make([]dwcaField, 0, len(dwcOccurrenceTerms))
+	for i, term := range dwcOccurrenceTerms {
+		fields = append(fields, dwcaField{Index: i, Term: dwcTermsNamespace + term})
+	}
+	return dwcaMeta{
+		Metadata: "eml.xml",
+		Core: dwcaCore{
+			Encoding:           "UTF-8",
+			FieldsTerminatedBy: `\t`,
+			LinesTerminatedBy:  `\n`,
+			FieldsEnclosedBy:   "",
+			IgnoreHeaderLines:  1,
+			RowType:            dwcTermsNamespace + "Occurrence",
+			Location:           "occurrence.txt",
+			ID:                 dwcaIndex{Index: 0},
+			Fields:             fields,
+		},
+	}
+}
+
+// dwcaEML is the minimal EML dataset description of the archive
+type dwcaEML struct {
+	XMLName   xml.Name    `xml:"eml:eml"`
+	NSEML     string      `xml:"xmlns:eml,attr"`
+	PackageID string      `xml:"packageId,attr"`
+	System    string      `xml:"system,attr"`
+	Dataset   dwcaDataset `xml:"dataset"`
+}
+
+type dwcaDataset struct {
+	Title    string        `xml:"title"`
+	Creator  dwcaParty     `xml:"creator"`
+	PubDate  string        `xml:"pubDate"`
+	Abstract string        `xml:"abstract>para"`
+	Coverage dwcaCoverage  `xml:"coverage"`
+	Contact  dwcaParty     `xml:"contact"`
+	Methods  dwcaMethodDoc `xml:"methods"`
+}
+
+type dwcaParty struct {
+	OrganizationName string `xml:"organizationName"`
+}
+
+type dwcaCoverage struct {
+	Geographic dwcaGeographic `xml:"geographicCoverage"`
+	Temporal   *dwcaTemporal  `xml:"temporalCoverage,omitempty"`
+}
+
+type dwcaGeographic struct {
+	Description string  `xml:"geographicDescription"`
+	West        float64 `xml:"boundingCoordinates>westBoundingCoordinate"`
+	East        float64 `xml:"boundingCoordinates>eastBoundingCoordinate"`
+	North       float64 `xml:"boundingCoordinates>northBoundingCoordinate"`
+	South       float64 `xml:"boundingCoordinates>southBoundingCoordinate"`
+}
+
+type dwcaTemporal struct {
+	Begin string `xml:"rangeOfDates>beginDate>calendarDate"`
+	End   string `xml:"rangeOfDates>endDate>calendarDate"`
+}
+
+type dwcaMethodDoc struct {
+	Description string `xml:"methodStep>description>para"`
+}
+
+func newDwCAEML(station dwcaStation, firstDate, lastDate string) dwcaEML {
+	eml := dwcaEML{
+		NSEML:     "eml://ecoinformatics.org/eml-2.1.1",
+		PackageID: "birdnet-go-" + time.Now().UTC().Format("20060102T150405Z"),
+		System:    "BirdNET-Go",
+		Dataset: dwcaDataset{
+			Title:    "BirdNET-Go detections from " + station.Name,
+			Creator:  dwcaParty{OrganizationName: station.Name},
+			PubDate:  time.Now().Format(time.DateOnly),
+			Abstract: "Bird vocalizations detected by the BirdNET-Go station " + station.Name + " with the BirdNET model.",
+			Coverage: dwcaCoverage{
+				Geographic: dwcaGeographic{
+					Description: "Location of the " + station.Name + " recording station",
+					West:        station.Longitude,
+					East:        station.Longitude,
+					North:       station.Latitude,
+					South:       station.Latitude,
+				},
+			},
+			Contact: dwcaParty{OrganizationName: station.Name},
+			Methods: dwcaMethodDoc{
+				Description: "Continuous audio recording analysed with the BirdNET neural network. Detections are machine identifications, " +
+					"identificationVerificationStatus shows whether a person reviewed them.",
+			},
+		},
+	}
+	if firstDate != "" {
+		eml.Dataset.Coverage.Temporal = &dwcaTemporal{Begin: firstDate, End: lastDate}
+	}
+	return eml
+}
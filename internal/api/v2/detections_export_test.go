@@ -0,0 +1,164 @@
+// detections_export_test.go: Tests for the streaming detection export endpoint
+package api
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// setupExportTest creates a controller backed by a SQLite datastore with three detections
+func setupExportTest(t *testing.T) *Controller {
+	t.Helper()
+
+	controller, db := newSQLiteTestController(t,
+		&datastore.Note{}, &datastore.NoteReview{}, &datastore.NoteLock{}, &datastore.NoteComment{})
+
+	notes := []datastore.Note{
+		{Date: "2024-05-01", Time: "05:10:00", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird",
+			Confidence: 0.91, Source: datastore.AudioSource{ID: "rtsp_garden", DisplayName: "Garden"}},
+		{Date: "2024-05-01", Time: "05:20:00", ScientificName: "Parus major", CommonName: "Great Tit",
+			Confidence: 0.75, Latitude: 61.5, Longitude: 23.8},
+		{Date: "2024-05-02", Time: "06:00:00", ScientificName: "Corvus corax", CommonName: "Common Raven",
+			Confidence: 0.65},
+	}
+	for i := range notes {
+		require.NoError(t, db.Create(&notes[i]).Error)
+	}
+	require.NoError(t, db.Create(&datastore.NoteReview{NoteID: 1, Verified: "correct", ReviewedBy: "alice"}).Error)
+	require.NoError(t, db.Create(&datastore.NoteReview{NoteID: 3, Verified: "false_positive"}).Error)
+	require.NoError(t, db.Create(&datastore.NoteComment{NoteID: 1, Entry: "sang\tfrom the roof"}).Error)
+
+	controller.Settings.Main.Name = "Test Station"
+	controller.Settings.BirdNET.Latitude = 60.17
+	controller.Settings.BirdNET.Longitude = 24.94
+	return controller
+}
+
+func TestExportDetectionsCSV(t *testing.T) {
+	controller := setupExportTest(t)
+
+	rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/csv")
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4, "header and three detections")
+	assert.Equal(t, csvExportColumns, rows[0])
+	assert.Equal(t, "Turdus merula", rows[1][5])
+	assert.Equal(t, "Garden", rows[1][9])
+	assert.Equal(t, "correct", rows[1][14])
+	assert.Equal(t, "alice", rows[1][15])
+	assert.Equal(t, "60.17", rows[1][12], "detections without coordinates use the station location")
+	assert.Equal(t, "61.5", rows[2][12])
+
+	t.Run("filters", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?species=Parus+major", "")
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "Great Tit", rows[1][6])
+	})
+
+	t.Run("no matches still writes the header", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?species=Pica+pica", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, rows, 1)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?format=xlsx", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestExportDetectionsNDJSON(t *testing.T) {
+	controller := setupExportTest(t)
+
+	rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?format=ndjson&start_date=2024-05-01&end_date=2024-05-01", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+	var records []DetectionExportRecord
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var record DetectionExportRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"sang\tfrom the roof"}, records[0].Comments)
+	assert.Equal(t, "unverified", records[1].Verified)
+}
+
+func TestExportDetectionsDarwinCore(t *testing.T) {
+	controller := setupExportTest(t)
+
+	rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?format=dwca", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+
+	body := rec.Body.Bytes()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(data)
+	}
+	require.Contains(t, files, "occurrence.txt")
+	require.Contains(t, files, "meta.xml")
+	require.Contains(t, files, "eml.xml")
+
+	lines := strings.Split(strings.TrimSuffix(files["occurrence.txt"], "\n"), "\n")
+	require.Len(t, lines, 3, "header and two detections, the false positive is left out")
+	header := strings.Split(lines[0], "\t")
+	assert.Equal(t, dwcOccurrenceTerms, header)
+	for _, line := range lines[1:] {
+		assert.Len(t, strings.Split(line, "\t"), len(dwcOccurrenceTerms), "comments must not add columns")
+	}
+	first := strings.Split(lines[1], "\t")
+	assert.Equal(t, "urn:birdnet-go:Test%20Station:detection:1", first[0])
+	assert.Equal(t, "MachineObservation", first[1])
+	assert.Equal(t, "Turdus merula", first[4])
+	assert.Equal(t, "verified by human reviewer", first[12])
+
+	var meta dwcaMeta
+	require.NoError(t, xml.Unmarshal([]byte(files["meta.xml"]), &meta))
+	assert.Equal(t, "occurrence.txt", meta.Core.Location)
+	require.Len(t, meta.Core.Fields, len(dwcOccurrenceTerms))
+	assert.Equal(t, dwcTermsNamespace+"occurrenceID", meta.Core.Fields[0].Term)
+
+	assert.Contains(t, files["eml.xml"], "<calendarDate>2024-05-01</calendarDate>")
+	assert.Contains(t, files["eml.xml"], "Test Station")
+
+	t.Run("explicit review filter keeps false positives", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ExportDetections, http.MethodGet, "/api/v2/detections/export?format=dwca&verified=true", "")
+		body := rec.Body.Bytes()
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		r, err := archive.Open("occurrence.txt")
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), 3, "header and both reviewed detections")
+	})
+}
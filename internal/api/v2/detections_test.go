@@ -1287,7 +1287,7 @@ func TestAddCommentMethod(t *testing.T) {
 			tc.mockSetup(&mockDS.Mock)
 
 			// Call method directly
-			err := controller.AddComment(tc.noteID, tc.commentText)
+			err := controller.AddComment(tc.noteID, tc.commentText, "")
 
 			// Check result
 			if tc.expectError {
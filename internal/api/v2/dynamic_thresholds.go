@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/errors"
 )
 
@@ -118,8 +119,8 @@ func (c *Controller) initDynamicThresholdRoutes() {
 	c.Group.GET("/dynamic-thresholds/:species/events", c.GetThresholdEvents)
 
 	// Protected endpoints for modifying thresholds (require authentication)
-	c.Group.DELETE("/dynamic-thresholds/:species", c.ResetDynamicThreshold, c.authMiddleware)
-	c.Group.DELETE("/dynamic-thresholds", c.ResetAllDynamicThresholds, c.authMiddleware)
+	c.Group.DELETE("/dynamic-thresholds/:species", c.ResetDynamicThreshold, c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+	c.Group.DELETE("/dynamic-thresholds", c.ResetAllDynamicThresholds, c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
 }
 
 // GetDynamicThresholds returns all dynamic thresholds with optional pagination
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -39,7 +40,7 @@ func (c *Controller) initFileSystemRoutes() {
 	c.logInfoIfEnabled("Initializing filesystem routes")
 
 	// Create filesystem API group with authentication
-	fsGroup := c.Group.Group("/filesystem", c.authMiddleware)
+	fsGroup := c.Group.Group("/filesystem", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
 
 	// GET /api/v2/filesystem/browse - Browse files and directories
 	fsGroup.GET("/browse", c.BrowseFileSystem)
@@ -13,14 +13,17 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/securefs"
 )
 
-// passthroughMiddleware returns a middleware that does nothing (allows all requests).
+// passthroughMiddleware returns a middleware that allows all requests with the admin
+// role, like the auth middleware does when authentication is not required.
 // Used for testing endpoints that require authentication middleware.
 func passthroughMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			c.Set(auth.CtxKeyRole, auth.RoleAdmin)
 			return next(c)
 		}
 	}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/birdweather"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -201,7 +202,7 @@ func (c *Controller) initIntegrationsRoutes() {
 	c.logInfoIfEnabled("Initializing integrations routes")
 
 	// Create integrations API group with auth middleware
-	integrationsGroup := c.Group.Group("/integrations", c.authMiddleware)
+	integrationsGroup := c.Group.Group("/integrations", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
 
 	// MQTT routes
 	mqttGroup := integrationsGroup.Group("/mqtt")
@@ -478,6 +479,7 @@ type WeatherTestRequest struct {
 	Debug        bool                      `json:"debug"`
 	OpenWeather  conf.OpenWeatherSettings  `json:"openWeather"`
 	Wunderground conf.WundergroundSettings `json:"wunderground"`
+	OpenMeteo    conf.OpenMeteoSettings    `json:"openMeteo"`
 }
 
 // WeatherTestStage represents the result of a weather test stage
@@ -525,6 +527,7 @@ func (c *Controller) TestWeatherConnection(ctx echo.Context) error {
 				PollInterval: request.PollInterval,
 				OpenWeather:  request.OpenWeather,
 				Wunderground: request.Wunderground,
+				OpenMeteo:    request.OpenMeteo,
 			},
 		},
 	}
@@ -621,6 +624,8 @@ func (c *Controller) testWeatherAPIConnectivity(ctx context.Context, settings *c
 		testURL = "https://api.openweathermap.org"
 	case WeatherProviderWunderground:
 		testURL = "https://api.weather.com"
+	case WeatherProviderOpenMeteo:
+		testURL = "https://api.open-meteo.com"
 	default:
 		return "", fmt.Errorf("unsupported weather provider: %s", provider)
 	}
@@ -702,6 +707,8 @@ func (c *Controller) testWeatherDataFetch(ctx context.Context, settings *conf.Se
 		provider = weather.NewOpenWeatherProvider()
 	case WeatherProviderWunderground:
 		provider = weather.NewWundergroundProvider(nil)
+	case WeatherProviderOpenMeteo:
+		provider = weather.NewOpenMeteoProvider(nil)
 	default:
 		return "", fmt.Errorf("unsupported weather provider: %s", settings.Realtime.Weather.Provider)
 	}
@@ -734,6 +741,12 @@ func getProviderDisplayName(provider string) string {
 		return "OpenWeather"
 	case WeatherProviderWunderground:
 		return "Weather Underground"
+	case WeatherProviderOpenMeteo:
+		return "Open-Meteo"
+	case WeatherProviderLocalStation:
+		return "Local Weather Station"
+	case WeatherProviderMQTT:
+		return "MQTT"
 	default:
 		// Simple capitalization for unknown providers
 		if provider != "" {
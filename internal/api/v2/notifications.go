@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
@@ -156,17 +157,18 @@ func (c *Controller) SetupNotificationRoutes() {
 	c.Group.GET("/notifications/stream", c.StreamNotifications, c.authMiddleware, middleware.RateLimiterWithConfig(rateLimiterConfig))
 
 	// REST endpoints for notification management (authenticated)
-	// All notification endpoints require authentication when security is enabled
+	// All notification endpoints require authentication when security is enabled.
+	// Notifications are shared, so marking them is left to reviewers and deleting to admins.
 	notificationsGroup := c.Group.Group("/notifications", c.authMiddleware)
 	notificationsGroup.GET("", c.GetNotifications)
 	notificationsGroup.GET("/:id", c.GetNotification)
-	notificationsGroup.PUT("/:id/read", c.MarkNotificationRead)
-	notificationsGroup.PUT("/:id/acknowledge", c.MarkNotificationAcknowledged)
-	notificationsGroup.DELETE("/:id", c.DeleteNotification)
+	notificationsGroup.PUT("/:id/read", c.MarkNotificationRead, auth.RequireRole(auth.RoleReviewer))
+	notificationsGroup.PUT("/:id/acknowledge", c.MarkNotificationAcknowledged, auth.RequireRole(auth.RoleReviewer))
+	notificationsGroup.DELETE("/:id", c.DeleteNotification, auth.RequireRole(auth.RoleAdmin))
 	notificationsGroup.GET("/unread/count", c.GetUnreadCount)
 
 	// Test endpoints for notification system (authenticated)
-	notificationsGroup.POST("/test/new-species", c.CreateTestNewSpeciesNotification)
+	notificationsGroup.POST("/test/new-species", c.CreateTestNewSpeciesNotification, auth.RequireRole(auth.RoleAdmin))
 }
 
 // StreamNotifications handles the SSE connection for real-time notification streaming
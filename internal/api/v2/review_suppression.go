@@ -0,0 +1,148 @@
+// internal/api/v2/review_suppression.go
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// ReviewSuppressionResponse is the response of GET /api/v2/review-suppression
+type ReviewSuppressionResponse struct {
+	Enabled              bool                              `json:"enabled"`
+	Action               string                            `json:"action"`
+	MinReviews           int                               `json:"minReviews"`
+	MinFalsePositiveRate float64                           `json:"minFalsePositiveRate"`
+	HourBucket           int                               `json:"hourBucket"`
+	LookbackDays         int                               `json:"lookbackDays"`
+	LearnedAt            *time.Time                        `json:"learnedAt,omitempty"` // Nil until the rules are first learned
+	Rules                []processor.ReviewSuppressionRule `json:"rules"`
+}
+
+// initReviewSuppressionRoutes registers the endpoints of the false positive suppression learned from reviews
+func (c *Controller) initReviewSuppressionRoutes() {
+	// Public endpoint explaining what is suppressed and why
+	c.Group.GET("/review-suppression", c.GetReviewSuppression)
+
+	// Protected endpoints for relearning and resetting the learned state
+	c.Group.POST("/review-suppression/refresh", c.RefreshReviewSuppression, c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+	c.Group.DELETE("/review-suppression/:species", c.ResetReviewSuppression, c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+	c.Group.DELETE("/review-suppression", c.ResetAllReviewSuppression, c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+}
+
+// GetReviewSuppression returns the learned suppression rules with the reviews they were learned from
+// GET /api/v2/review-suppression
+func (c *Controller) GetReviewSuppression(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.reviewSuppressionUnavailable(ctx)
+	}
+
+	settings := c.Settings.Realtime.ReviewSuppression
+	rules, learnedAt := c.Processor.GetReviewSuppressionRules()
+	response := ReviewSuppressionResponse{
+		Enabled:              settings.Enabled,
+		Action:               settings.Action,
+		MinReviews:           settings.MinReviews,
+		MinFalsePositiveRate: settings.MinFalsePositiveRate,
+		HourBucket:           settings.HourBucket,
+		LookbackDays:         settings.LookbackDays,
+		Rules:                rules,
+	}
+	if !learnedAt.IsZero() {
+		response.LearnedAt = &learnedAt
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// RefreshReviewSuppression relearns the suppression rules from the current reviews.
+// Learning also works while the filter stage is disabled, to preview its effect.
+// POST /api/v2/review-suppression/refresh
+func (c *Controller) RefreshReviewSuppression(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.reviewSuppressionUnavailable(ctx)
+	}
+
+	if err := c.Processor.LearnReviewSuppression(ctx.Request().Context()); err != nil {
+		return c.handleReviewSuppressionError(ctx, err, "Failed to learn from reviews")
+	}
+
+	c.logInfoIfEnabled("Review suppression relearned",
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	return c.GetReviewSuppression(ctx)
+}
+
+// ResetReviewSuppression forgets the suppression learned for a species
+// DELETE /api/v2/review-suppression/:species
+func (c *Controller) ResetReviewSuppression(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.reviewSuppressionUnavailable(ctx)
+	}
+
+	// An empty species would reset all species, the error response is already written
+	species, err := c.parseSpeciesParam(ctx)
+	if err != nil || species == "" {
+		return err
+	}
+
+	removed, err := c.Processor.ResetReviewSuppression(species)
+	if err != nil {
+		return c.handleReviewSuppressionError(ctx, err, "Failed to reset review suppression")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"success": true,
+		"message": "Review suppression reset successfully",
+		"species": species,
+		"count":   removed,
+	})
+}
+
+// ResetAllReviewSuppression forgets the suppression learned for all species
+// DELETE /api/v2/review-suppression?confirm=true
+func (c *Controller) ResetAllReviewSuppression(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.reviewSuppressionUnavailable(ctx)
+	}
+
+	// Require confirmation query parameter for safety
+	if ctx.QueryParam("confirm") != "true" {
+		return c.HandleError(ctx, errors.Newf("confirmation required").
+			Category(errors.CategoryValidation).
+			Component("api-review-suppression").
+			Build(), "Must include ?confirm=true query parameter", http.StatusBadRequest)
+	}
+
+	removed, err := c.Processor.ResetReviewSuppression("")
+	if err != nil {
+		return c.handleReviewSuppressionError(ctx, err, "Failed to reset review suppression")
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"success": true,
+		"message": "All review suppression reset successfully",
+		"count":   removed,
+	})
+}
+
+// reviewSuppressionUnavailable responds 503 when there is no processor to learn in
+func (c *Controller) reviewSuppressionUnavailable(ctx echo.Context) error {
+	return c.HandleError(ctx, errors.Newf("processor not available").
+		Category(errors.CategorySystem).
+		Component("api-review-suppression").
+		Build(), "Processor not available", http.StatusServiceUnavailable)
+}
+
+// handleReviewSuppressionError responds 503 when the datastore keeps no review history
+func (c *Controller) handleReviewSuppressionError(ctx echo.Context, err error, message string) error {
+	if errors.Is(err, processor.ErrReviewHistoryUnavailable) {
+		return c.HandleError(ctx, err, "Review suppression is not available", http.StatusServiceUnavailable)
+	}
+	return c.HandleError(ctx, err, message, http.StatusInternalServerError)
+}
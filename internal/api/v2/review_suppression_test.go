@@ -0,0 +1,90 @@
+// review_suppression_test.go: Tests for the false positive suppression learned from reviews
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+)
+
+// setupReviewSuppressionTest creates a controller whose processor learns from
+// five false positive reviews of a species at the current time of day
+func setupReviewSuppressionTest(t *testing.T) *Controller {
+	t.Helper()
+
+	controller, db := newSQLiteTestController(t, &datastore.Note{}, &datastore.NoteReview{}, &datastore.ReviewSuppressionReset{})
+
+	now := time.Now()
+	for i := range 5 {
+		note := datastore.Note{
+			Date: now.Format("2006-01-02"), Time: now.Format("15:04:05"),
+			ScientificName: "Phylloscopus collybita", CommonName: "Common Chiffchaff",
+			Confidence: 0.7 + float64(i)*0.01, Source: datastore.AudioSource{ID: "gate", DisplayName: "Gate"},
+		}
+		require.NoError(t, db.Create(&note).Error)
+		require.NoError(t, db.Create(&datastore.NoteReview{NoteID: note.ID, Verified: "false_positive"}).Error)
+	}
+
+	controller.Settings.Realtime.ReviewSuppression.Action = processor.ReviewSuppressionRaise
+	controller.Settings.Realtime.ReviewSuppression.MinReviews = 5
+	controller.Settings.Realtime.ReviewSuppression.MinFalsePositiveRate = 0.8
+	controller.Settings.Realtime.ReviewSuppression.HourBucket = 3
+	controller.Processor = &processor.Processor{Settings: controller.Settings, Ds: controller.DS}
+	return controller
+}
+
+func TestReviewSuppressionEndpoints(t *testing.T) {
+	controller := setupReviewSuppressionTest(t)
+
+	resp := decodeJSON[ReviewSuppressionResponse](t, doTestRequest(t, controller, controller.GetReviewSuppression, http.MethodGet, "/", ""))
+	assert.False(t, resp.Enabled)
+	assert.Nil(t, resp.LearnedAt)
+	assert.Empty(t, resp.Rules)
+
+	resp = decodeJSON[ReviewSuppressionResponse](t, doTestRequest(t, controller, controller.RefreshReviewSuppression, http.MethodPost, "/", ""))
+	require.NotNil(t, resp.LearnedAt)
+	require.Len(t, resp.Rules, 1, "rules can be previewed while the stage is disabled")
+	rule := resp.Rules[0]
+	assert.Equal(t, "common chiffchaff", rule.SpeciesName)
+	assert.Equal(t, "Gate", rule.SourceName)
+	assert.Equal(t, 5, rule.FalsePositives)
+	assert.InDelta(t, 0.74, rule.Threshold, 1e-9)
+	assert.Contains(t, rule.Explanation, "5 of 5 reviews of Common Chiffchaff on Gate")
+
+	t.Run("reset species", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ResetReviewSuppression, http.MethodDelete, "/", "", "species", "Common%20Chiffchaff")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.JSONEq(t, `{"success":true,"message":"Review suppression reset successfully","species":"Common Chiffchaff","count":1}`, rec.Body.String())
+
+		resp := decodeJSON[ReviewSuppressionResponse](t, doTestRequest(t, controller, controller.RefreshReviewSuppression, http.MethodPost, "/", ""))
+		assert.Empty(t, resp.Rules, "reviews before the reset are not learned again")
+	})
+
+	t.Run("missing species", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ResetReviewSuppression, http.MethodDelete, "/", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("reset all requires confirmation", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.ResetAllReviewSuppression, http.MethodDelete, "/", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		rec = doTestRequest(t, controller, controller.ResetAllReviewSuppression, http.MethodDelete, "/?confirm=true", "")
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		controller.Processor.Ds = mocks.NewMockInterface(t)
+		rec := doTestRequest(t, controller, controller.RefreshReviewSuppression, http.MethodPost, "/", "")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		controller.Processor = nil
+		rec = doTestRequest(t, controller, controller.GetReviewSuppression, http.MethodGet, "/", "")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -34,6 +34,7 @@ type SearchRequest struct {
 	VerifiedStatus string  `json:"verifiedStatus"`
 	LockedStatus   string  `json:"lockedStatus"`
 	DeviceFilter   string  `json:"deviceFilter"`
+	SourceFilter   string  `json:"sourceFilter"`
 	TimeOfDay      string  `json:"timeOfDay"`
 	Page           int     `json:"page"`
 	SortBy         string  `json:"sortBy"`
@@ -111,6 +112,7 @@ func (c *Controller) logValidatedRequest(path, ip string, req *SearchRequest) {
 		logger.String("verifiedStatus", req.VerifiedStatus),
 		logger.String("lockedStatus", req.LockedStatus),
 		logger.String("deviceFilter", req.DeviceFilter),
+		logger.String("sourceFilter", req.SourceFilter),
 		logger.String("timeOfDay", req.TimeOfDay),
 		logger.Int("page", req.Page),
 		logger.String("sortBy", req.SortBy),
@@ -132,6 +134,7 @@ func (c *Controller) buildSearchFilters(req *SearchRequest, ctxTimeout context.C
 		LockedOnly:     req.LockedStatus == "locked",
 		UnlockedOnly:   req.LockedStatus == "unlocked",
 		Device:         req.DeviceFilter,
+		Source:         req.SourceFilter,
 		TimeOfDay:      req.TimeOfDay,
 		Page:           req.Page,
 		PerPage:        defaultPerPage,
@@ -16,6 +16,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -44,7 +45,7 @@ func (c *Controller) initSettingsRoutes() {
 	c.logInfoIfEnabled("Initializing settings routes")
 
 	// Create settings API group
-	settingsGroup := c.Group.Group("/settings", c.authMiddleware)
+	settingsGroup := c.Group.Group("/settings", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
 
 	// Routes for settings
 	// GET /api/v2/settings - Retrieves all application settings
@@ -796,7 +797,17 @@ func validateRTSPSection(data json.RawMessage) error {
 	}
 
 	// Validate RTSP URLs
-	return validateRTSPURLs(rtspSettings.URLs)
+	if err := validateRTSPURLs(rtspSettings.URLs); err != nil {
+		return err
+	}
+
+	// Validate the URLs of named streams
+	for i := range rtspSettings.Streams {
+		if err := validateSingleRTSPURL(rtspSettings.Streams[i].URL); err != nil {
+			return fmt.Errorf("RTSP stream %q: %w", rtspSettings.Streams[i].ID, err)
+		}
+	}
+	return nil
 }
 
 // securitySectionAllowedFields defines which fields in the security section can be updated via API
@@ -1534,7 +1545,9 @@ func mqttSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		oldMQTT.TLS.InsecureSkipVerify != newMQTT.TLS.InsecureSkipVerify ||
 		oldMQTT.TLS.CACert != newMQTT.TLS.CACert ||
 		oldMQTT.TLS.ClientCert != newMQTT.TLS.ClientCert ||
-		oldMQTT.TLS.ClientKey != newMQTT.TLS.ClientKey
+		oldMQTT.TLS.ClientKey != newMQTT.TLS.ClientKey ||
+		oldMQTT.HomeAssistant.Enabled != newMQTT.HomeAssistant.Enabled || // availability will is set on connect
+		oldMQTT.Commands != newMQTT.Commands
 }
 
 // rtspSettingsChanged checks if RTSP settings have changed
@@ -1558,7 +1571,19 @@ func rtspSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		}
 	}
 
-	return false
+	// Check for changes in named streams, species lists are read live by the processor
+	// and are ignored here
+	return !reflect.DeepEqual(rtspStreamsWithoutSpecies(oldRTSP.Streams), rtspStreamsWithoutSpecies(newRTSP.Streams))
+}
+
+// rtspStreamsWithoutSpecies returns a copy of the named streams without their species lists
+func rtspStreamsWithoutSpecies(streams []conf.RTSPStreamSettings) []conf.RTSPStreamSettings {
+	stripped := make([]conf.RTSPStreamSettings, len(streams))
+	for i := range streams {
+		stripped[i] = streams[i]
+		stripped[i].Species = conf.RTSPStreamSpeciesSettings{}
+	}
+	return stripped
 }
 
 // speciesIntervalSettingsChanged checks if any species-specific interval settings have changed
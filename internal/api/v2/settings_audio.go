@@ -27,6 +27,12 @@ func soundLevelSettingsChanged(oldSettings, currentSettings *conf.Settings) bool
 		return true
 	}
 
+	// Check for changes in saving sound levels (only if enabled)
+	if currentSettings.Realtime.Audio.SoundLevel.Enabled &&
+		oldSettings.Realtime.Audio.SoundLevel.Storage != currentSettings.Realtime.Audio.SoundLevel.Storage {
+		return true
+	}
+
 	return false
 }
 
@@ -0,0 +1,307 @@
+// internal/api/v2/sound_levels.go
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	soundLevelPeriodHour = "hour"
+	soundLevelPeriodDay  = "day"
+
+	maxSoundLevelHistoryDays = 31 // Most days of saved sound levels listed per request
+	maxSoundLevelHourlyDays  = 31 // Most days of hourly indicators per request
+	maxSoundLevelDailyDays   = 92 // Most days of daily indicators per request
+
+	// Percentages of the time exceeded by the L10 and L90 levels
+	soundLevelPeakPercent       = 10
+	soundLevelBackgroundPercent = 90
+)
+
+// soundLevelHistoryStore is implemented by datastores saving sound levels
+type soundLevelHistoryStore interface {
+	GetSoundLevels(ctx context.Context, sourceID string, start, end time.Time) ([]datastore.SoundLevel, error)
+	GetSoundLevelSources(ctx context.Context) ([]datastore.SoundLevelSource, error)
+}
+
+// SoundLevelBandResponse is the level of one 1/3rd octave band of a saved sound level
+type SoundLevelBandResponse struct {
+	CenterFreq float64 `json:"centerFrequencyHz"`
+	Min        float64 `json:"minDb"`
+	Max        float64 `json:"maxDb"`
+	Mean       float64 `json:"meanDb"`
+}
+
+// SoundLevelResponse is a saved sound level of a storage interval
+type SoundLevelResponse struct {
+	Time     time.Time                         `json:"time"`
+	Duration int                               `json:"durationSeconds"`
+	LAeq     float64                           `json:"laeq"`
+	Bands    map[string]SoundLevelBandResponse `json:"bands,omitempty"` // Only included when requested
+}
+
+// SoundLevelHistoryResponse is the response of GET /api/v2/soundlevels/history
+type SoundLevelHistoryResponse struct {
+	SourceID string               `json:"sourceId"`
+	Levels   []SoundLevelResponse `json:"levels"`
+}
+
+// SoundLevelIndicators are the noise indicators of an hour or a day. Levels are nil
+// when they cannot be computed, e.g. Lden of a day without night measurements.
+type SoundLevelIndicators struct {
+	Start    time.Time `json:"start"`
+	Duration int       `json:"durationSeconds"` // Measured seconds within the period
+	Leq      *float64  `json:"leq"`
+	L10      *float64  `json:"l10"` // Level exceeded 10% of the time, e.g. traffic peaks
+	L90      *float64  `json:"l90"` // Level exceeded 90% of the time, the background level
+	Lday     *float64  `json:"lday,omitempty"`
+	Levening *float64  `json:"levening,omitempty"`
+	Lnight   *float64  `json:"lnight,omitempty"`
+	Lden     *float64  `json:"lden,omitempty"`
+}
+
+// SoundLevelIndicatorsResponse is the response of GET /api/v2/soundlevels/indicators
+type SoundLevelIndicatorsResponse struct {
+	SourceID   string                 `json:"sourceId"`
+	Period     string                 `json:"period"`
+	Indicators []SoundLevelIndicators `json:"indicators"`
+}
+
+// soundLevelQuery is the source and the local date range of a sound level request
+type soundLevelQuery struct {
+	sourceID   string
+	start, end time.Time // Midnight of the first date and of the day after the last date
+}
+
+// initSoundLevelRoutes registers the endpoints of saved sound levels and their noise indicators
+func (c *Controller) initSoundLevelRoutes() {
+	// Public endpoints like the live sound level stream
+	c.Group.GET("/soundlevels/sources", c.GetSoundLevelSources)
+	c.Group.GET("/soundlevels/history", c.GetSoundLevelHistory)
+	c.Group.GET("/soundlevels/indicators", c.GetSoundLevelIndicators)
+}
+
+// GetSoundLevelSources lists the audio sources with saved sound levels
+// GET /api/v2/soundlevels/sources
+func (c *Controller) GetSoundLevelSources(ctx echo.Context) error {
+	store, err := c.getSoundLevelStore(ctx)
+	if store == nil {
+		return err
+	}
+
+	sources, err := store.GetSoundLevelSources(ctx.Request().Context())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get sound level sources", http.StatusInternalServerError)
+	}
+	if sources == nil {
+		sources = []datastore.SoundLevelSource{}
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"sources": sources})
+}
+
+// GetSoundLevelHistory lists the saved sound levels of a source, oldest first
+// GET /api/v2/soundlevels/history?source=&start_date=&end_date=&bands=true
+func (c *Controller) GetSoundLevelHistory(ctx echo.Context) error {
+	store, err := c.getSoundLevelStore(ctx)
+	if store == nil {
+		return err
+	}
+	query, err := c.parseSoundLevelQuery(ctx, maxSoundLevelHistoryDays)
+	if query == nil {
+		return err
+	}
+
+	levels, err := store.GetSoundLevels(ctx.Request().Context(), query.sourceID, query.start, query.end)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get sound levels", http.StatusInternalServerError)
+	}
+
+	includeBands := ctx.QueryParam("bands") == "true"
+	response := SoundLevelHistoryResponse{
+		SourceID: query.sourceID,
+		Levels:   make([]SoundLevelResponse, 0, len(levels)),
+	}
+	for i := range levels {
+		level := SoundLevelResponse{
+			Time:     levels[i].Time,
+			Duration: levels[i].Duration,
+			LAeq:     levels[i].LAeq,
+		}
+		if includeBands {
+			bands, err := levels[i].GetBands()
+			if err != nil {
+				return c.HandleError(ctx, err, "Failed to decode sound level bands", http.StatusInternalServerError)
+			}
+			level.Bands = make(map[string]SoundLevelBandResponse, len(bands))
+			for key, band := range bands {
+				level.Bands[key] = SoundLevelBandResponse(band)
+			}
+		}
+		response.Levels = append(response.Levels, level)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetSoundLevelIndicators computes the Leq, L10 and L90 of each hour or day with saved
+// sound levels of a source, and the day, evening, night levels and Lden of each day
+// GET /api/v2/soundlevels/indicators?source=&start_date=&end_date=&period=hour|day
+func (c *Controller) GetSoundLevelIndicators(ctx echo.Context) error {
+	store, err := c.getSoundLevelStore(ctx)
+	if store == nil {
+		return err
+	}
+
+	period := ctx.QueryParam("period")
+	maxDays := maxSoundLevelDailyDays
+	switch period {
+	case "", soundLevelPeriodDay:
+		period = soundLevelPeriodDay
+	case soundLevelPeriodHour:
+		maxDays = maxSoundLevelHourlyDays
+	default:
+		return c.soundLevelValidationError(ctx, "period must be hour or day")
+	}
+
+	query, err := c.parseSoundLevelQuery(ctx, maxDays)
+	if query == nil {
+		return err
+	}
+
+	levels, err := store.GetSoundLevels(ctx.Request().Context(), query.sourceID, query.start, query.end)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get sound levels", http.StatusInternalServerError)
+	}
+
+	// Group the levels by the local hour or date they started in, levels are sorted by time
+	var starts []time.Time
+	groups := make(map[time.Time][]myaudio.TimedLevel)
+	for i := range levels {
+		t := levels[i].Time.In(time.Local)
+		start := t.Truncate(time.Hour)
+		if period == soundLevelPeriodDay {
+			start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		}
+		if _, ok := groups[start]; !ok {
+			starts = append(starts, start)
+		}
+		groups[start] = append(groups[start], myaudio.TimedLevel{
+			Time:     t,
+			Level:    levels[i].LAeq,
+			Duration: float64(levels[i].Duration),
+		})
+	}
+
+	response := SoundLevelIndicatorsResponse{
+		SourceID:   query.sourceID,
+		Period:     period,
+		Indicators: make([]SoundLevelIndicators, 0, len(starts)),
+	}
+	for _, start := range starts {
+		response.Indicators = append(response.Indicators, computeSoundLevelIndicators(start, groups[start], period == soundLevelPeriodDay))
+	}
+
+	c.logDebugIfEnabled("Computed sound level indicators",
+		logger.String("source", query.sourceID),
+		logger.String("period", period),
+		logger.Int("levels", len(levels)),
+		logger.Int("periods", len(response.Indicators)))
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// computeSoundLevelIndicators computes the noise indicators of the levels of one period
+func computeSoundLevelIndicators(start time.Time, levels []myaudio.TimedLevel, daily bool) SoundLevelIndicators {
+	var duration float64
+	for _, l := range levels {
+		duration += l.Duration
+	}
+
+	indicators := SoundLevelIndicators{
+		Start:    start,
+		Duration: int(duration),
+		Leq:      soundLevelValue(myaudio.Leq(levels)),
+		L10:      soundLevelValue(myaudio.PercentileLevel(levels, soundLevelPeakPercent)),
+		L90:      soundLevelValue(myaudio.PercentileLevel(levels, soundLevelBackgroundPercent)),
+	}
+	if daily {
+		den := myaudio.ComputeDayEveningNight(levels)
+		indicators.Lday = soundLevelValue(den.Lday)
+		indicators.Levening = soundLevelValue(den.Levening)
+		indicators.Lnight = soundLevelValue(den.Lnight)
+		indicators.Lden = soundLevelValue(den.Lden)
+	}
+	return indicators
+}
+
+// soundLevelValue rounds a level to one decimal, nil for NaN which JSON cannot represent
+func soundLevelValue(level float64) *float64 {
+	if math.IsNaN(level) || math.IsInf(level, 0) {
+		return nil
+	}
+	rounded := math.Round(level*10) / 10
+	return &rounded
+}
+
+// parseSoundLevelQuery reads the source, start_date and end_date query parameters,
+// responding with 400 when invalid. The dates are inclusive and default to today.
+func (c *Controller) parseSoundLevelQuery(ctx echo.Context, maxDays int) (*soundLevelQuery, error) {
+	query := &soundLevelQuery{sourceID: strings.TrimSpace(ctx.QueryParam("source"))}
+	if query.sourceID == "" {
+		return nil, c.soundLevelValidationError(ctx, "source is required")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	query.start, query.end = today, today
+	for _, dp := range []struct {
+		name   string
+		target *time.Time
+	}{{"start_date", &query.start}, {"end_date", &query.end}} {
+		value := ctx.QueryParam(dp.name)
+		if err := validateDateParam(value, dp.name); err != nil {
+			return nil, c.soundLevelValidationError(ctx, err.Error())
+		}
+		if value != "" {
+			*dp.target, _ = time.ParseInLocation(time.DateOnly, value, time.Local)
+		}
+	}
+	if query.start.After(query.end) {
+		return nil, c.soundLevelValidationError(ctx, "start_date must not be after end_date")
+	}
+	if query.start.AddDate(0, 0, maxDays).Before(query.end.AddDate(0, 0, 1)) {
+		return nil, c.soundLevelValidationError(ctx, "date range must not exceed "+strconv.Itoa(maxDays)+" days")
+	}
+	query.end = query.end.AddDate(0, 0, 1)
+	return query, nil
+}
+
+// getSoundLevelStore returns the datastore as a soundLevelHistoryStore, responding with 503 when unsupported
+func (c *Controller) getSoundLevelStore(ctx echo.Context) (soundLevelHistoryStore, error) {
+	store, ok := c.DS.(soundLevelHistoryStore)
+	if !ok {
+		return nil, c.HandleError(ctx, errors.Newf("datastore does not support saved sound levels").
+			Category(errors.CategorySystem).
+			Component("api-soundlevels").
+			Build(), "Saved sound levels are not available", http.StatusServiceUnavailable)
+	}
+	return store, nil
+}
+
+// soundLevelValidationError responds 400 with a validation message
+func (c *Controller) soundLevelValidationError(ctx echo.Context, message string) error {
+	return c.HandleError(ctx, errors.Newf("%s", message).
+		Category(errors.CategoryValidation).
+		Component("api-soundlevels").
+		Build(), message, http.StatusBadRequest)
+}
@@ -0,0 +1,125 @@
+// sound_levels_test.go: Tests for saved sound levels and their noise indicators
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// setupSoundLevelTest creates a controller with a datastore holding a day of sound
+// levels: 50 dB during the day and evening and 40 dB at night, one level per hour
+func setupSoundLevelTest(t *testing.T) *Controller {
+	t.Helper()
+
+	controller, _ := newSQLiteTestController(t, &datastore.SoundLevel{})
+	store := controller.DS.(*datastore.SQLiteStore)
+
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	levels := make([]datastore.SoundLevel, 0, 24)
+	for hour := range 24 {
+		level := 50.0
+		if hour < 7 || hour >= 23 {
+			level = 40
+		}
+		levels = append(levels, datastore.SoundLevel{
+			SourceID: "mic", SourceName: "Microphone",
+			Time: day.Add(time.Duration(hour) * time.Hour), Duration: 3600, LAeq: level,
+		})
+	}
+	require.NoError(t, levels[0].SetBands(map[string]datastore.SoundLevelBand{
+		"1.0_kHz": {CenterFreq: 1000, Min: 35, Max: 45, Mean: 40},
+	}))
+	require.NoError(t, store.SaveSoundLevels(levels))
+	return controller
+}
+
+func TestGetSoundLevelSources(t *testing.T) {
+	controller := setupSoundLevelTest(t)
+
+	rec := doTestRequest(t, controller, controller.GetSoundLevelSources, http.MethodGet,
+		"/api/v2/soundlevels/sources", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response struct {
+		Sources []datastore.SoundLevelSource `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []datastore.SoundLevelSource{{SourceID: "mic", SourceName: "Microphone", Count: 24}}, response.Sources)
+}
+
+func TestGetSoundLevelHistory(t *testing.T) {
+	controller := setupSoundLevelTest(t)
+
+	rec := doTestRequest(t, controller, controller.GetSoundLevelHistory, http.MethodGet,
+		"/api/v2/soundlevels/history?source=mic&start_date=2024-06-01&end_date=2024-06-01&bands=true", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response SoundLevelHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Levels, 24)
+	assert.InDelta(t, 40.0, response.Levels[0].LAeq, 0.001)
+	assert.InDelta(t, 45.0, response.Levels[0].Bands["1.0_kHz"].Max, 0.001)
+	assert.Empty(t, response.Levels[1].Bands)
+
+	rec = doTestRequest(t, controller, controller.GetSoundLevelHistory, http.MethodGet,
+		"/api/v2/soundlevels/history?source=other&start_date=2024-06-01&end_date=2024-06-01", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Empty(t, response.Levels, "other sources have no sound levels")
+	assert.NotNil(t, response.Levels)
+}
+
+func TestGetSoundLevelIndicators(t *testing.T) {
+	controller := setupSoundLevelTest(t)
+
+	t.Run("daily", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.GetSoundLevelIndicators, http.MethodGet,
+			"/api/v2/soundlevels/indicators?source=mic&start_date=2024-05-31&end_date=2024-06-01", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response SoundLevelIndicatorsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, soundLevelPeriodDay, response.Period)
+		require.Len(t, response.Indicators, 1, "days without sound levels are left out")
+
+		day := response.Indicators[0]
+		assert.Equal(t, 24*3600, day.Duration)
+		require.NotNil(t, day.Lden)
+		assert.InDelta(t, 50.0, *day.Lday, 0.001)
+		assert.InDelta(t, 50.0, *day.Levening, 0.001)
+		assert.InDelta(t, 40.0, *day.Lnight, 0.001)
+		// 10*log10((12*10^5 + 4*10^5.5 + 8*10^5)/24) = 51.3
+		assert.InDelta(t, 51.3, *day.Lden, 0.1)
+		assert.InDelta(t, 50.0, *day.L10, 0.001)
+		assert.InDelta(t, 40.0, *day.L90, 0.001)
+	})
+
+	t.Run("hourly", func(t *testing.T) {
+		rec := doTestRequest(t, controller, controller.GetSoundLevelIndicators, http.MethodGet,
+			"/api/v2/soundlevels/indicators?source=mic&start_date=2024-06-01&end_date=2024-06-01&period=hour", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response SoundLevelIndicatorsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Indicators, 24)
+		assert.InDelta(t, 40.0, *response.Indicators[0].Leq, 0.001)
+		assert.InDelta(t, 50.0, *response.Indicators[12].Leq, 0.001)
+		assert.Nil(t, response.Indicators[12].Lden, "Lden is only computed for days")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, target := range []string{
+			"/api/v2/soundlevels/indicators?start_date=2024-06-01",
+			"/api/v2/soundlevels/indicators?source=mic&period=week",
+			"/api/v2/soundlevels/indicators?source=mic&start_date=2024-06-02&end_date=2024-06-01",
+			"/api/v2/soundlevels/indicators?source=mic&start_date=2024-06-01&end_date=2024-08-01&period=hour",
+			"/api/v2/soundlevels/indicators?source=mic&start_date=2024-13-01",
+		} {
+			rec := doTestRequest(t, controller, controller.GetSoundLevelIndicators, http.MethodGet,
+				target, "")
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+	})
+}
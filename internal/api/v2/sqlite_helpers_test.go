@@ -0,0 +1,74 @@
+// sqlite_helpers_test.go: Shared helpers for tests that run handlers against a SQLite datastore
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newSQLiteTestController creates a test controller backed by a SQLite database
+// with the given models migrated. The database is returned for seeding test data.
+func newSQLiteTestController(t *testing.T, models ...any) (*Controller, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(models...))
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	_, _, controller := setupTestEnvironment(t)
+	controller.DS = &datastore.SQLiteStore{DataStore: datastore.DataStore{DB: db}}
+	return controller, db
+}
+
+// doTestRequest calls a handler and returns the recorded response. A non-empty
+// body is sent as JSON, path parameters are given as name and value pairs.
+// Handlers may return ErrResponseHandled after writing an error response.
+func doTestRequest(t *testing.T, controller *Controller, handler echo.HandlerFunc, method, target, body string, params ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	} else {
+		req = httptest.NewRequest(method, target, http.NoBody)
+	}
+	rec := httptest.NewRecorder()
+	ctx := controller.Echo.NewContext(req, rec)
+	if len(params) > 0 {
+		var names, values []string
+		for i := 0; i+1 < len(params); i += 2 {
+			names = append(names, params[i])
+			values = append(values, params[i+1])
+		}
+		ctx.SetParamNames(names...)
+		ctx.SetParamValues(values...)
+	}
+	if err := handler(ctx); !errors.Is(err, ErrResponseHandled) {
+		require.NoError(t, err)
+	}
+	return rec
+}
+
+// decodeJSON requires a 200 response and decodes its JSON body
+func decodeJSON[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var v T
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v))
+	return v
+}
@@ -0,0 +1,217 @@
+// internal/api/v2/tokens.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// API token states reported to clients
+const (
+	tokenStatusActive  = "active"
+	tokenStatusExpired = "expired"
+	tokenStatusRevoked = "revoked"
+)
+
+// maxTokenExpiryDays limits how far in the future a token expiry can be set
+const maxTokenExpiryDays = 3650
+
+// CreateTokenRequest is the request body for creating an API token
+type CreateTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"` // 0 creates a token that never expires
+}
+
+// TokenResponse describes an API token, the token itself is never returned after creation
+type TokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Status     string     `json:"status"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreateTokenResponse is returned once when a token is created
+type CreateTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"` // The API token, shown only in this response
+}
+
+// initTokenRoutes registers the API token management endpoints
+func (c *Controller) initTokenRoutes() {
+	c.logInfoIfEnabled("Initializing API token routes")
+
+	tokenGroup := c.Group.Group("/tokens", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+	tokenGroup.GET("", c.ListTokens)
+	tokenGroup.POST("", c.CreateToken)
+	tokenGroup.GET("/scopes", c.GetTokenScopes)
+	tokenGroup.DELETE("/:id", c.RevokeToken)
+
+	c.logInfoIfEnabled("API token routes initialized successfully")
+}
+
+// tokensUnavailable responds that API tokens are not supported by the datastore
+func (c *Controller) tokensUnavailable(ctx echo.Context) error {
+	return c.HandleError(ctx, errors.Newf("api token manager not available").
+		Category(errors.CategorySystem).
+		Component("api-tokens").
+		Build(), "API tokens are not available", http.StatusServiceUnavailable)
+}
+
+// newTokenResponse converts a stored token to its API representation
+func newTokenResponse(token *datastore.APIToken, now time.Time) TokenResponse {
+	status := tokenStatusActive
+	switch {
+	case token.RevokedAt != nil:
+		status = tokenStatusRevoked
+	case token.ExpiresAt != nil && !now.Before(*token.ExpiresAt):
+		status = tokenStatusExpired
+	}
+
+	scopes := token.ScopeList()
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return TokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     scopes,
+		Status:     status,
+		CreatedBy:  token.CreatedBy,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		LastUsedIP: token.LastUsedIP,
+		RevokedAt:  token.RevokedAt,
+	}
+}
+
+// ListTokens handles GET /api/v2/tokens
+// Lists all API tokens with their scopes, expiry and last use.
+func (c *Controller) ListTokens(ctx echo.Context) error {
+	if c.tokenManager == nil {
+		return c.tokensUnavailable(ctx)
+	}
+
+	tokens, err := c.tokenManager.List()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list API tokens", http.StatusInternalServerError)
+	}
+
+	now := time.Now()
+	response := make([]TokenResponse, 0, len(tokens))
+	for i := range tokens {
+		response = append(response, newTokenResponse(&tokens[i], now))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetTokenScopes handles GET /api/v2/tokens/scopes
+// Lists the scopes that can be granted to API tokens.
+func (c *Controller) GetTokenScopes(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, auth.Scopes)
+}
+
+// CreateToken handles POST /api/v2/tokens
+// Creates an API token. The token is returned only in this response. A request
+// authenticated with an API token can only grant scopes that token has itself.
+func (c *Controller) CreateToken(ctx echo.Context) error {
+	if c.tokenManager == nil {
+		return c.tokensUnavailable(ctx)
+	}
+
+	var req CreateTokenRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxTokenExpiryDays {
+		return c.HandleError(ctx, errors.Newf("invalid token expiry %d days", req.ExpiresInDays).
+			Category(errors.CategoryValidation).
+			Component("api-tokens").
+			Build(), "Token expiry must be between 0 and "+strconv.Itoa(maxTokenExpiryDays)+" days", http.StatusBadRequest)
+	}
+
+	scopes, err := auth.NormalizeScopes(req.Scopes)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	if current, ok := ctx.Get(auth.CtxKeyAPIToken).(*datastore.APIToken); ok {
+		for _, scope := range scopes {
+			if !auth.HasScope(current, scope) {
+				return c.HandleError(ctx, auth.ErrInsufficientScope,
+					"An API token cannot grant the "+scope+" scope it does not have", http.StatusForbidden)
+			}
+		}
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	createdBy := stringFromCtx(ctx, auth.CtxKeyUsername, "")
+	plain, token, err := c.tokenManager.Create(req.Name, scopes, expiresAt, createdBy)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidTokenName) || errors.Is(err, auth.ErrInvalidExpiry) {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to create API token", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("API token created",
+		logger.String("name", token.Name),
+		logger.String("prefix", token.Prefix),
+		logger.String("scopes", token.Scopes),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	return ctx.JSON(http.StatusCreated, CreateTokenResponse{
+		TokenResponse: newTokenResponse(token, time.Now()),
+		Token:         plain,
+	})
+}
+
+// RevokeToken handles DELETE /api/v2/tokens/:id
+// Revokes an API token, requests using it are rejected from then on.
+func (c *Controller) RevokeToken(ctx echo.Context) error {
+	if c.tokenManager == nil {
+		return c.tokensUnavailable(ctx)
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid token ID", http.StatusBadRequest)
+	}
+
+	if err := c.tokenManager.Revoke(uint(id)); err != nil {
+		if errors.Is(err, datastore.ErrAPITokenNotFound) {
+			return c.HandleError(ctx, err, "API token not found", http.StatusNotFound)
+		}
+		return c.HandleError(ctx, err, "Failed to revoke API token", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("API token revoked",
+		logger.Int64("token_id", int64(id)),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	return ctx.NoContent(http.StatusNoContent)
+}
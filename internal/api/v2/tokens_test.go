@@ -0,0 +1,262 @@
+// tokens_test.go: Tests for scoped API tokens and their management endpoints
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTokenTest creates a controller with authentication required and an API token manager
+func setupTokenTest(t *testing.T) (*echo.Echo, *auth.Middleware, *auth.TokenManager) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&datastore.APIToken{}))
+	tokens := auth.NewTokenManager((&datastore.DataStore{DB: db}).NewAPITokenStore())
+
+	settings := &conf.Settings{
+		Realtime: conf.RealtimeSettings{
+			Audio: conf.AudioSettings{Export: conf.ExportSettings{Path: t.TempDir()}},
+		},
+		Security: conf.Security{
+			SessionSecret: "test-session-secret-32-chars-long",
+			BasicAuth: conf.BasicAuth{
+				Enabled:        true,
+				Password:       "testpassword123",
+				AuthCodeExp:    5 * time.Minute,
+				AccessTokenExp: 24 * time.Hour,
+			},
+		},
+	}
+
+	mockImageProvider := &MockImageProvider{}
+	mockImageProvider.On("Fetch", mock.Anything).Return(imageprovider.BirdImage{}, nil).Maybe()
+	birdImageCache := &imageprovider.BirdImageCache{}
+	birdImageCache.SetImageProvider(mockImageProvider)
+	mockMetrics, _ := observability.NewMetrics()
+	controlChan := make(chan string, 10)
+
+	authService := auth.NewSecurityAdapter(createTestOAuth2Server(settings))
+	authService.Tokens = tokens
+	authMw := auth.NewMiddleware(authService)
+	authMw.Tokens = tokens
+
+	e := echo.New()
+	controller, err := NewWithOptions(e, mocks.NewMockInterface(t), settings, birdImageCache,
+		suncalc.NewSunCalc(60.1699, 24.9384), controlChan, mockMetrics, true,
+		WithAuthMiddleware(authMw.Authenticate), WithAuthService(authService), WithTokenManager(tokens))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		close(controlChan)
+	})
+
+	return e, authMw, tokens
+}
+
+// doTokenRequest performs a request authenticated with an API token
+func doTokenRequest(e *echo.Echo, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTokenManagementEndpoints(t *testing.T) {
+	e, _, tokens := setupTokenTest(t)
+
+	readToken, readRecord, err := tokens.Create("dashboard", []string{auth.ScopeReadDetections}, nil, "admin")
+	require.NoError(t, err)
+	settingsToken, _, err := tokens.Create("automation", []string{auth.ScopeSettings, auth.ScopeReview}, nil, "admin")
+	require.NoError(t, err)
+
+	t.Run("missing scope is forbidden", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodGet, "/api/v2/tokens", readToken, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("unknown token is unauthorized", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodGet, "/api/v2/tokens", auth.APITokenPrefix+"unknown", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("list tokens", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodGet, "/api/v2/tokens", settingsToken, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var list []TokenResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		require.Len(t, list, 2)
+		for i := range list {
+			assert.Equal(t, tokenStatusActive, list[i].Status)
+			assert.NotContains(t, rec.Body.String(), readToken, "tokens are never listed")
+		}
+	})
+
+	t.Run("token cannot grant scopes it lacks", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodPost, "/api/v2/tokens", settingsToken,
+			`{"name": "escalate", "scopes": ["control"]}`)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("invalid scope is rejected", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodPost, "/api/v2/tokens", settingsToken,
+			`{"name": "typo", "scopes": ["reviews"]}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("create token", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodPost, "/api/v2/tokens", settingsToken,
+			`{"name": "home-assistant", "scopes": ["review", "review"], "expiresInDays": 30}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var created CreateTokenResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.True(t, auth.IsAPIToken(created.Token))
+		assert.Equal(t, []string{auth.ScopeReview}, created.Scopes)
+		assert.Equal(t, "token:automation", created.CreatedBy)
+		require.NotNil(t, created.ExpiresAt)
+		assert.True(t, strings.HasPrefix(created.Token, created.Prefix))
+	})
+
+	t.Run("revoke token", func(t *testing.T) {
+		path := "/api/v2/tokens/" + strconv.FormatUint(uint64(readRecord.ID), 10)
+		rec := doTokenRequest(e, http.MethodDelete, path, settingsToken, "")
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		_, err := tokens.Validate(readToken, "192.0.2.1")
+		require.ErrorIs(t, err, auth.ErrInvalidToken)
+
+		rec = doTokenRequest(e, http.MethodDelete, "/api/v2/tokens/9999", settingsToken, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAPITokenScopeEnforcement(t *testing.T) {
+	_, authMw, tokens := setupTokenTest(t)
+
+	// A separate router exercises the middleware without the handlers' dependencies
+	e := echo.New()
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/api/v2/detections", ok, authMw.Authenticate)
+	e.POST("/api/v2/detections/:id/review", ok, authMw.Authenticate)
+	e.POST("/api/v2/control/restart", ok, authMw.Authenticate)
+
+	readToken, _, err := tokens.Create("reader", []string{auth.ScopeReadDetections}, nil, "")
+	require.NoError(t, err)
+	reviewToken, _, err := tokens.Create("reviewer", []string{auth.ScopeReadDetections, auth.ScopeReview}, nil, "")
+	require.NoError(t, err)
+	expiry := time.Now().Add(time.Hour)
+	expiringToken, _, err := tokens.Create("expiring", []string{auth.ScopeControl}, &expiry, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"read detections", http.MethodGet, "/api/v2/detections", readToken, http.StatusOK},
+		{"review without scope", http.MethodPost, "/api/v2/detections/1/review", readToken, http.StatusForbidden},
+		{"review with scope", http.MethodPost, "/api/v2/detections/1/review", reviewToken, http.StatusOK},
+		{"control without scope", http.MethodPost, "/api/v2/control/restart", reviewToken, http.StatusForbidden},
+		{"control with scope", http.MethodPost, "/api/v2/control/restart", expiringToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doTokenRequest(e, tt.method, tt.path, tt.token, "")
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestAPITokenAdminRoutes(t *testing.T) {
+	e, _, tokens := setupTokenTest(t)
+
+	readToken, _, err := tokens.Create("dashboard", []string{auth.ScopeReadDetections}, nil, "")
+	require.NoError(t, err)
+	reviewToken, _, err := tokens.Create("reviewer", []string{auth.ScopeReadDetections, auth.ScopeReview}, nil, "")
+	require.NoError(t, err)
+	controlToken, _, err := tokens.Create("backups", []string{auth.ScopeControl}, nil, "")
+	require.NoError(t, err)
+
+	t.Run("read token cannot download backups", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodGet, "/api/v2/backups/birdnet-20250101-030000/download", readToken, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("read token cannot list backups", func(t *testing.T) {
+		for _, path := range []string{"/api/v2/backups", "/api/v2/backups/stats", "/api/v2/backups/missed"} {
+			rec := doTokenRequest(e, http.MethodGet, path, readToken, "")
+			assert.Equal(t, http.StatusForbidden, rec.Code, path)
+		}
+	})
+
+	t.Run("admin routes need control or settings", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodDelete, "/api/v2/detections/12", reviewToken, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("control token reaches backups", func(t *testing.T) {
+		rec := doTokenRequest(e, http.MethodGet, "/api/v2/backups/birdnet-20250101-030000/download", controlToken, "")
+		assert.NotEqual(t, http.StatusForbidden, rec.Code)
+		assert.NotEqual(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestRequiredScope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/api/v2/detections/recent", auth.ScopeReadDetections},
+		{http.MethodGet, "/api/v2/analytics/species/summary", auth.ScopeReadDetections},
+		{http.MethodGet, "/api/v2/settings/birdnet", auth.ScopeSettings},
+		{http.MethodPut, "/api/v2/settings/birdnet", auth.ScopeSettings},
+		{http.MethodGet, "/api/v2/tokens", auth.ScopeSettings},
+		{http.MethodGet, "/api/v2/users", auth.ScopeSettings},
+		{http.MethodPost, "/api/v2/detections/12/review", auth.ScopeReview},
+		{http.MethodDelete, "/api/v2/detections/12", auth.ScopeReview},
+		{http.MethodPost, "/api/v2/control/restart", auth.ScopeControl},
+		{http.MethodPost, "/api/v2/backups", auth.ScopeControl},
+		{http.MethodGet, "/api/v2/backups", auth.ScopeControl},
+		{http.MethodGet, "/api/v2/backups/birdnet-20250101-030000/download", auth.ScopeControl},
+		{http.MethodPost, "/api/v2/settingsx", auth.ScopeControl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, auth.RequiredScope(tt.method, tt.path))
+		})
+	}
+}
@@ -0,0 +1,896 @@
+// internal/api/v2/uploads.go
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observation"
+)
+
+// Upload analysis job states
+const (
+	UploadStatusQueued    = "queued"
+	UploadStatusRunning   = "running"
+	UploadStatusSaving    = "saving"
+	UploadStatusCompleted = "completed"
+	UploadStatusFailed    = "failed"
+	UploadStatusCancelled = "cancelled"
+)
+
+const (
+	maxUploadSize            = 1 << 30 // Largest accepted audio upload, 1 GiB
+	maxQueuedUploads         = 4       // Uploads waiting for analysis, more are rejected
+	maxUploadJobs            = 50      // Finished jobs kept for their results
+	uploadJobRetention       = time.Hour
+	uploadProgressBufferSize = 32 // Progress events buffered per SSE client
+	// UploadSourceID is the audio source ID of detections saved from uploads
+	UploadSourceID = "upload"
+)
+
+// chunkPredictor analyses one 3 second chunk of audio, implemented by *birdnet.BirdNET
+type chunkPredictor interface {
+	ProcessChunk(chunk []float32, predStart time.Time) ([]datastore.Note, error)
+}
+
+// speciesRangePredictor lists the species probable at a location on a date,
+// implemented by *birdnet.BirdNET
+type speciesRangePredictor interface {
+	GetProbableSpeciesAt(latitude, longitude float64, date time.Time, week float32) ([]birdnet.SpeciesScore, error)
+}
+
+// UploadAnalysisOptions are the analysis settings of an upload
+type UploadAnalysisOptions struct {
+	Threshold      float64   `json:"threshold"`           // Minimum confidence of reported detections
+	Locale         string    `json:"locale,omitempty"`    // Locale of common names, the BirdNET locale when empty
+	RecordedAt     time.Time `json:"recordedAt"`          // Start time of the recording
+	Latitude       *float64  `json:"latitude,omitempty"`  // Recording location, the station location when empty
+	Longitude      *float64  `json:"longitude,omitempty"` // Recording location, the station location when empty
+	SaveDetections bool      `json:"saveDetections"`      // Save accepted detections as notes
+}
+
+// UploadDetection is a species detected in a segment of an upload
+type UploadDetection struct {
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	SpeciesCode    string  `json:"speciesCode,omitempty"`
+	Confidence     float64 `json:"confidence"`
+	NoteID         uint    `json:"noteId,omitempty"` // ID of the saved detection
+}
+
+// UploadSegment lists the detections of one analysed segment of an upload
+type UploadSegment struct {
+	Start      float64           `json:"start"` // Offset from the start of the file in seconds
+	End        float64           `json:"end"`
+	Detections []UploadDetection `json:"detections"`
+}
+
+// UploadProgress is a progress event of an upload analysis
+type UploadProgress struct {
+	Status      string    `json:"status"`
+	Chunk       int       `json:"chunk"`
+	TotalChunks int       `json:"totalChunks"`
+	Detections  int       `json:"detections"`
+	Error       string    `json:"error,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// UploadJobResponse describes an upload analysis job. Segments are only
+// included when a single job is requested.
+type UploadJobResponse struct {
+	ID              string                `json:"id"`
+	Filename        string                `json:"filename"`
+	Duration        float64               `json:"duration"` // Length of the audio in seconds
+	Options         UploadAnalysisOptions `json:"options"`
+	Progress        UploadProgress        `json:"progress"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	FinishedAt      *time.Time            `json:"finishedAt,omitempty"`
+	SavedDetections int                   `json:"savedDetections"`
+	Segments        []UploadSegment       `json:"segments,omitempty"`
+}
+
+// uploadJob is an uploaded file waiting for or going through analysis
+type uploadJob struct {
+	mu          sync.Mutex
+	id          string
+	filename    string
+	path        string // Temporary copy of the upload, removed when the analysis ends
+	info        myaudio.AudioInfo
+	options     UploadAnalysisOptions
+	commonNames map[string]string // Common names of the requested locale by scientific name
+	createdAt   time.Time
+	finishedAt  time.Time
+	progress    UploadProgress
+	segments    []UploadSegment
+	saved       int
+	cancel      context.CancelFunc
+	cancelled   bool
+	subscribers map[chan UploadProgress]struct{}
+}
+
+// publish records a progress event and sends it to the subscribers, slow
+// subscribers miss events instead of blocking the analysis
+func (j *uploadJob) publish(p UploadProgress) {
+	p.Time = time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress = p
+	for ch := range j.subscribers {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// finish records the final state of the job and closes the subscriber channels
+func (j *uploadJob) finish(status string, err error) {
+	j.mu.Lock()
+	p := j.progress
+	j.mu.Unlock()
+
+	p.Status = status
+	if err != nil {
+		p.Error = err.Error()
+	}
+	j.publish(p)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.finishedAt = time.Now()
+	for ch := range j.subscribers {
+		close(ch)
+	}
+	j.subscribers = nil
+}
+
+// done reports whether the analysis of the job has ended
+func (j *uploadJob) done() bool {
+	return !j.finishedTime().IsZero()
+}
+
+// finishedTime returns when the analysis ended, zero while it has not
+func (j *uploadJob) finishedTime() time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.finishedAt
+}
+
+// duration returns the length of the uploaded audio in seconds
+func (j *uploadJob) duration() float64 {
+	return float64(j.info.TotalSamples) / float64(max(j.info.SampleRate, 1))
+}
+
+// subscribe returns the current progress and, while the job has not finished, a
+// channel receiving the following events. The channel is closed when the job ends.
+func (j *uploadJob) subscribe() (UploadProgress, chan UploadProgress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.finishedAt.IsZero() {
+		return j.progress, nil
+	}
+	ch := make(chan UploadProgress, uploadProgressBufferSize)
+	if j.subscribers == nil {
+		j.subscribers = make(map[chan UploadProgress]struct{})
+	}
+	j.subscribers[ch] = struct{}{}
+	return j.progress, ch
+}
+
+// unsubscribe removes a subscriber that stops following the job
+func (j *uploadJob) unsubscribe(ch chan UploadProgress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.subscribers[ch]; ok {
+		delete(j.subscribers, ch)
+		close(ch)
+	}
+}
+
+// response returns the API representation of the job
+func (j *uploadJob) response(withSegments bool) UploadJobResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	resp := UploadJobResponse{
+		ID:              j.id,
+		Filename:        j.filename,
+		Duration:        j.duration(),
+		Options:         j.options,
+		Progress:        j.progress,
+		CreatedAt:       j.createdAt,
+		SavedDetections: j.saved,
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		resp.FinishedAt = &finishedAt
+	}
+	if withSegments {
+		// Detections are cloned too, their note IDs are set while the job is saved
+		resp.Segments = make([]UploadSegment, len(j.segments))
+		for i, segment := range j.segments {
+			segment.Detections = slices.Clone(segment.Detections)
+			resp.Segments[i] = segment
+		}
+	}
+	return resp
+}
+
+// uploadQueue runs upload analyses one at a time in upload order
+type uploadQueue struct {
+	mu        sync.Mutex
+	jobs      map[string]*uploadJob
+	queue     chan *uploadJob
+	startOnce sync.Once
+}
+
+// add registers a job and queues it, it returns false when the queue is full
+func (q *uploadQueue) add(job *uploadJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.queue == nil {
+		q.queue = make(chan *uploadJob, maxQueuedUploads)
+		q.jobs = make(map[string]*uploadJob)
+	}
+	select {
+	case q.queue <- job:
+	default:
+		return false
+	}
+	q.jobs[job.id] = job
+	q.pruneLocked()
+	return true
+}
+
+// pruneLocked drops finished jobs past their retention or beyond the job limit, q.mu must be held
+func (q *uploadQueue) pruneLocked() {
+	type finishedJob struct {
+		id string
+		at time.Time
+	}
+	var finished []finishedJob
+	for id, job := range q.jobs {
+		finishedAt := job.finishedTime()
+		if finishedAt.IsZero() {
+			continue
+		}
+		if time.Since(finishedAt) > uploadJobRetention {
+			delete(q.jobs, id)
+			continue
+		}
+		finished = append(finished, finishedJob{id: id, at: finishedAt})
+	}
+	if excess := len(q.jobs) - maxUploadJobs; excess > 0 {
+		slices.SortFunc(finished, func(a, b finishedJob) int { return a.at.Compare(b.at) })
+		for _, job := range finished[:min(excess, len(finished))] {
+			delete(q.jobs, job.id)
+		}
+	}
+}
+
+// get returns a job by ID
+func (q *uploadQueue) get(id string) (*uploadJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	return job, ok
+}
+
+// list returns all jobs, newest first
+func (q *uploadQueue) list() []*uploadJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]*uploadJob, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b *uploadJob) int { return b.createdAt.Compare(a.createdAt) })
+	return jobs
+}
+
+// remove forgets a finished job
+func (q *uploadQueue) remove(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.jobs, id)
+}
+
+// initUploadRoutes registers the audio upload analysis endpoints
+func (c *Controller) initUploadRoutes() {
+	c.logInfoIfEnabled("Initializing upload analysis routes")
+
+	// Uploads use the shared BirdNET instance and can save detections, so they
+	// are limited to reviewers
+	uploadGroup := c.Group.Group("/analysis/uploads", c.authMiddleware, auth.RequireRole(auth.RoleReviewer))
+	uploadGroup.GET("", c.ListUploadAnalyses)
+	uploadGroup.POST("", c.UploadAudioForAnalysis)
+	uploadGroup.GET("/:id", c.GetUploadAnalysis)
+	uploadGroup.GET("/:id/progress", c.StreamUploadProgress)
+	uploadGroup.DELETE("/:id", c.DeleteUploadAnalysis)
+
+	c.logInfoIfEnabled("Upload analysis routes initialized successfully")
+}
+
+// getUploadPredictor returns the BirdNET instance used to analyse uploads
+func (c *Controller) getUploadPredictor() chunkPredictor {
+	if c.uploadPredictor != nil {
+		return c.uploadPredictor
+	}
+	if c.Processor == nil || c.Processor.Bn == nil {
+		return nil
+	}
+	return c.Processor.Bn
+}
+
+// uploadNotFound responds that an upload analysis job does not exist
+func (c *Controller) uploadNotFound(ctx echo.Context, id string) error {
+	return c.HandleError(ctx, errors.Newf("upload analysis %s not found", id).
+		Category(errors.CategoryNotFound).
+		Component("api-uploads").
+		Build(), "Upload analysis not found", http.StatusNotFound)
+}
+
+// uploadValidationError responds with a 400 error for invalid upload requests
+func (c *Controller) uploadValidationError(ctx echo.Context, message string) error {
+	return c.HandleError(ctx, errors.Newf("%s", message).
+		Category(errors.CategoryValidation).
+		Component("api-uploads").
+		Build(), message, http.StatusBadRequest)
+}
+
+// UploadAudioForAnalysis handles POST /api/v2/analysis/uploads
+// Accepts a WAV or FLAC file in the "file" form field and queues its analysis.
+// Optional form fields: threshold (0-1), locale, recordedAt (RFC 3339 or local
+// "2006-01-02T15:04:05"), latitude, longitude and save ("true" to save accepted
+// detections as notes with the "upload" source).
+func (c *Controller) UploadAudioForAnalysis(ctx echo.Context) error {
+	predictor := c.getUploadPredictor()
+	if predictor == nil {
+		return c.HandleError(ctx, errors.Newf("BirdNET is not running").
+			Category(errors.CategorySystem).
+			Component("api-uploads").
+			Build(), "Audio analysis is not available", http.StatusServiceUnavailable)
+	}
+
+	ctx.Request().Body = http.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxUploadSize)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return c.uploadValidationError(ctx, "An audio file is required in the file field, up to 1 GiB")
+	}
+	filename := filepath.Base(fileHeader.Filename)
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".wav" && ext != ".flac" {
+		return c.uploadValidationError(ctx, "Only WAV and FLAC files can be analysed")
+	}
+
+	options, commonNames, err := c.parseUploadOptions(ctx)
+	if err != nil {
+		return c.uploadValidationError(ctx, err.Error())
+	}
+
+	path, err := saveUploadedFile(fileHeader, ext)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to store the uploaded file", http.StatusInternalServerError)
+	}
+	info, err := myaudio.GetAudioInfo(path)
+	if err != nil || info.TotalSamples == 0 {
+		removeUploadFile(path)
+		return c.uploadValidationError(ctx, "The file is not a readable audio file")
+	}
+
+	job := &uploadJob{
+		id:          newUploadID(),
+		filename:    filename,
+		path:        path,
+		info:        info,
+		options:     options,
+		commonNames: commonNames,
+		createdAt:   time.Now(),
+		progress: UploadProgress{
+			Status:      UploadStatusQueued,
+			TotalChunks: max(myaudio.GetTotalChunks(info.SampleRate, info.TotalSamples, c.Settings.BirdNET.Overlap), 0),
+			Time:        time.Now(),
+		},
+	}
+	if !c.uploads.add(job) {
+		removeUploadFile(path)
+		return c.HandleError(ctx, errors.Newf("upload analysis queue is full").
+			Category(errors.CategoryLimit).
+			Component("api-uploads").
+			Build(), "Too many uploads are waiting for analysis, try again later", http.StatusTooManyRequests)
+	}
+	c.uploads.startOnce.Do(func() {
+		c.wg.Go(func() { c.runUploadQueue(predictor) })
+	})
+
+	c.logInfoIfEnabled("Audio upload queued for analysis",
+		logger.String("job_id", job.id),
+		logger.String("filename", filename),
+		logger.Int64("size", fileHeader.Size),
+		logger.Bool("save_detections", options.SaveDetections),
+		logger.String("username", stringFromCtx(ctx, auth.CtxKeyUsername, "")),
+		logger.String("ip", ctx.RealIP()))
+
+	ctx.Response().Header().Set(echo.HeaderLocation, "/api/v2/analysis/uploads/"+job.id)
+	return ctx.JSON(http.StatusAccepted, job.response(false))
+}
+
+// parseUploadOptions reads the analysis options of an upload from the form
+func (c *Controller) parseUploadOptions(ctx echo.Context) (UploadAnalysisOptions, map[string]string, error) {
+	options := UploadAnalysisOptions{
+		Threshold:  c.Settings.BirdNET.Threshold,
+		RecordedAt: time.Now(),
+	}
+
+	if value := ctx.FormValue("threshold"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return options, nil, fmt.Errorf("threshold must be a number between 0 and 1")
+		}
+		options.Threshold = threshold
+	}
+
+	if value := ctx.FormValue("recordedAt"); value != "" {
+		recordedAt, err := parseUploadTime(value)
+		if err != nil {
+			return options, nil, err
+		}
+		options.RecordedAt = recordedAt
+	}
+
+	latValue, lonValue := ctx.FormValue("latitude"), ctx.FormValue("longitude")
+	if (latValue == "") != (lonValue == "") {
+		return options, nil, fmt.Errorf("latitude and longitude must be given together")
+	}
+	if latValue != "" {
+		lat, latErr := strconv.ParseFloat(latValue, 64)
+		lon, lonErr := strconv.ParseFloat(lonValue, 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return options, nil, fmt.Errorf("latitude must be between -90 and 90 and longitude between -180 and 180")
+		}
+		options.Latitude, options.Longitude = &lat, &lon
+	}
+
+	options.SaveDetections = ctx.FormValue("save") == QueryValueTrue
+
+	var commonNames map[string]string
+	if locale := strings.TrimSpace(ctx.FormValue("locale")); locale != "" && locale != c.Settings.BirdNET.Locale {
+		names, err := loadLocaleCommonNames(locale)
+		if err != nil {
+			return options, nil, err
+		}
+		options.Locale = locale
+		commonNames = names
+	}
+
+	return options, commonNames, nil
+}
+
+// parseUploadTime parses the recording start time, times without a zone are local
+func parseUploadTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("recordedAt must be an RFC 3339 time or a local time such as 2006-01-02T15:04:05")
+}
+
+// loadLocaleCommonNames returns the common names of a BirdNET label locale by scientific name
+func loadLocaleCommonNames(locale string) (map[string]string, error) {
+	result := birdnet.GetLabelFileDataWithResult(birdnet.DefaultModelVersion, locale, nil)
+	if result.Error != nil || result.FallbackOccurred {
+		return nil, fmt.Errorf("locale %q is not available", locale)
+	}
+
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(result.Data))
+	for scanner.Scan() {
+		scientific, common, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "_")
+		if ok {
+			names[scientific] = common
+		}
+	}
+	return names, nil
+}
+
+// saveUploadedFile copies an uploaded file to a temporary file and returns its path
+func saveUploadedFile(fileHeader *multipart.FileHeader, ext string) (string, error) {
+	src, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close() //nolint:errcheck // Read-only upload part
+
+	dst, err := os.CreateTemp("", "birdnet-go-upload-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		removeUploadFile(dst.Name())
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		removeUploadFile(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// removeUploadFile deletes the temporary copy of an upload
+func removeUploadFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		GetLogger().Warn("Failed to remove uploaded audio file",
+			logger.String("path", path),
+			logger.Error(err))
+	}
+}
+
+// newUploadID returns a random job ID
+func newUploadID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// runUploadQueue analyses queued uploads one at a time until shutdown
+func (c *Controller) runUploadQueue(predictor chunkPredictor) {
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	for {
+		select {
+		case <-parent.Done():
+			// Jobs still in the queue are dropped with the controller
+			return
+		case job := <-c.uploads.queue:
+			c.runUploadJob(parent, predictor, job)
+		}
+	}
+}
+
+// runUploadJob analyses one upload and optionally saves its detections
+func (c *Controller) runUploadJob(parent context.Context, predictor chunkPredictor, job *uploadJob) {
+	defer removeUploadFile(job.path)
+
+	jobCtx, cancel := context.WithCancel(parent)
+	defer cancel()
+	job.mu.Lock()
+	if job.cancelled {
+		job.mu.Unlock()
+		job.finish(UploadStatusCancelled, nil)
+		return
+	}
+	job.cancel = cancel
+	job.mu.Unlock()
+
+	err := c.analyzeUpload(jobCtx, predictor, job)
+	if err == nil && job.options.SaveDetections {
+		err = c.saveUploadDetections(jobCtx, job)
+	}
+
+	switch {
+	case jobCtx.Err() != nil:
+		job.finish(UploadStatusCancelled, nil)
+		c.logInfoIfEnabled("Upload analysis cancelled", logger.String("job_id", job.id))
+	case err != nil:
+		job.finish(UploadStatusFailed, err)
+		c.logErrorIfEnabled("Upload analysis failed", logger.String("job_id", job.id), logger.Error(err))
+	default:
+		job.finish(UploadStatusCompleted, nil)
+		c.logInfoIfEnabled("Upload analysis completed",
+			logger.String("job_id", job.id),
+			logger.Int("segments", len(job.response(true).Segments)),
+			logger.Int("saved_detections", job.response(false).SavedDetections))
+	}
+}
+
+// analyzeUpload runs BirdNET over the chunks of an upload and records the
+// segments with detections at or above the threshold
+func (c *Controller) analyzeUpload(ctx context.Context, predictor chunkPredictor, job *uploadJob) error {
+	// Reading the file only needs the input path and chunk overlap of the settings
+	readSettings := *c.Settings
+	readSettings.Input.Path = job.path
+	step := 3 - readSettings.BirdNET.Overlap
+	isIncluded, err := c.uploadRangeFilter(predictor, job)
+	if err != nil {
+		return err
+	}
+
+	progress := job.response(false).Progress
+	progress.Status = UploadStatusRunning
+	job.publish(progress)
+
+	chunk := 0
+	return myaudio.ReadAudioFileBuffered(&readSettings, func(data []float32, _ bool) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+
+		offset := float64(chunk) * step
+		start := job.options.RecordedAt.Add(time.Duration(offset * float64(time.Second)))
+		notes, err := predictor.ProcessChunk(data, start)
+		if err != nil {
+			return err
+		}
+		chunk++
+
+		// The last chunk is padded to 3 seconds, its segment ends with the file
+		segment := UploadSegment{Start: offset, End: min(offset+3, max(job.duration(), offset))}
+		for i := range notes {
+			note := &notes[i]
+			if note.Confidence < job.options.Threshold {
+				continue
+			}
+			if isIncluded != nil && !isIncluded(note.ScientificName) {
+				continue
+			}
+			commonName := note.CommonName
+			if name, ok := job.commonNames[note.ScientificName]; ok {
+				commonName = name
+			}
+			segment.Detections = append(segment.Detections, UploadDetection{
+				ScientificName: note.ScientificName,
+				CommonName:     commonName,
+				SpeciesCode:    note.SpeciesCode,
+				Confidence:     math.Round(note.Confidence*10000) / 10000,
+			})
+		}
+
+		job.mu.Lock()
+		if len(segment.Detections) > 0 {
+			job.segments = append(job.segments, segment)
+		}
+		progress.Detections += len(segment.Detections)
+		job.mu.Unlock()
+
+		progress.Chunk = chunk
+		progress.TotalChunks = max(progress.TotalChunks, chunk)
+		job.publish(progress)
+		return nil
+	})
+}
+
+// uploadRangeFilter returns the range filter check for the detections of an
+// upload, nil when they are not filtered. Uploads from the station use the
+// station species list, uploads with a location get a list computed for that
+// location and the recording date.
+func (c *Controller) uploadRangeFilter(predictor chunkPredictor, job *uploadJob) (func(string) bool, error) {
+	if job.options.Latitude == nil {
+		if len(c.Settings.BirdNET.RangeFilter.Species) == 0 {
+			return nil, nil
+		}
+		return c.Settings.IsSpeciesIncluded, nil
+	}
+
+	ranger, ok := predictor.(speciesRangePredictor)
+	if !ok {
+		return nil, nil
+	}
+
+	scores, err := ranger.GetProbableSpeciesAt(*job.options.Latitude, *job.options.Longitude, job.options.RecordedAt, 0)
+	if err != nil {
+		return nil, errors.New(err).
+			Category(errors.CategoryProcessing).
+			Component("api-uploads").
+			Context("operation", "upload_range_filter").
+			Build()
+	}
+
+	included := make(map[string]bool, len(scores))
+	for _, score := range scores {
+		scientificName, _, _ := observation.ParseSpeciesString(score.Label)
+		included[scientificName] = true
+	}
+	return func(scientificName string) bool { return included[scientificName] }, nil
+}
+
+// saveUploadDetections saves the detections of an analysed upload as notes
+func (c *Controller) saveUploadDetections(ctx context.Context, job *uploadJob) error {
+	progress := job.response(false).Progress
+	progress.Status = UploadStatusSaving
+	job.publish(progress)
+
+	latitude, longitude := c.Settings.BirdNET.Latitude, c.Settings.BirdNET.Longitude
+	if job.options.Latitude != nil {
+		latitude, longitude = *job.options.Latitude, *job.options.Longitude
+	}
+	source := datastore.AudioSource{
+		ID:          UploadSourceID,
+		SafeString:  UploadSourceID,
+		DisplayName: "Upload: " + job.filename,
+	}
+
+	// Copy the detections out so that the job stays readable while the notes are saved
+	type pendingDetection struct {
+		segment, index int
+		begin, end     time.Time
+		detection      UploadDetection
+	}
+	var pending []pendingDetection
+	job.mu.Lock()
+	for i := range job.segments {
+		segment := &job.segments[i]
+		begin := job.options.RecordedAt.Add(time.Duration(segment.Start * float64(time.Second)))
+		end := job.options.RecordedAt.Add(time.Duration(segment.End * float64(time.Second)))
+		for k := range segment.Detections {
+			pending = append(pending, pendingDetection{
+				segment:   i,
+				index:     k,
+				begin:     begin,
+				end:       end,
+				detection: segment.Detections[k],
+			})
+		}
+	}
+	job.mu.Unlock()
+
+	for _, p := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		note := datastore.Note{
+			SourceNode:     c.Settings.Main.Name,
+			Date:           p.begin.Format(time.DateOnly),
+			Time:           p.begin.Format(time.TimeOnly),
+			Source:         source,
+			BeginTime:      p.begin,
+			EndTime:        p.end,
+			SpeciesCode:    p.detection.SpeciesCode,
+			ScientificName: p.detection.ScientificName,
+			CommonName:     p.detection.CommonName,
+			Confidence:     p.detection.Confidence,
+			Latitude:       latitude,
+			Longitude:      longitude,
+			Threshold:      job.options.Threshold,
+			Sensitivity:    c.Settings.BirdNET.Sensitivity,
+		}
+		if err := c.DS.Save(&note, nil); err != nil {
+			return err
+		}
+
+		job.mu.Lock()
+		job.segments[p.segment].Detections[p.index].NoteID = note.ID
+		job.saved++
+		job.mu.Unlock()
+	}
+	return nil
+}
+
+// ListUploadAnalyses handles GET /api/v2/analysis/uploads
+// Lists the queued, running and recently finished upload analyses, newest first.
+func (c *Controller) ListUploadAnalyses(ctx echo.Context) error {
+	jobs := c.uploads.list()
+	response := make([]UploadJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		response = append(response, job.response(false))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetUploadAnalysis handles GET /api/v2/analysis/uploads/:id
+// Returns the state of an upload analysis with the segments analysed so far.
+func (c *Controller) GetUploadAnalysis(ctx echo.Context) error {
+	job, ok := c.uploads.get(ctx.Param("id"))
+	if !ok {
+		return c.uploadNotFound(ctx, ctx.Param("id"))
+	}
+	return ctx.JSON(http.StatusOK, job.response(true))
+}
+
+// StreamUploadProgress handles GET /api/v2/analysis/uploads/:id/progress
+// Streams the progress of an upload analysis as SSE "progress" events. The
+// current progress is sent on connect, and a final "done" event with the job
+// and its segments is sent when the analysis has ended.
+func (c *Controller) StreamUploadProgress(ctx echo.Context) error {
+	job, ok := c.uploads.get(ctx.Param("id"))
+	if !ok {
+		return c.uploadNotFound(ctx, ctx.Param("id"))
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx.Request().Context(), maxSSEStreamDuration)
+	defer cancel()
+	ctx.SetRequest(ctx.Request().WithContext(timeoutCtx))
+
+	setSSEHeaders(ctx)
+	clientID := generateCorrelationID()
+
+	c.logSSEConnection(clientID, ctx.RealIP(), ctx.Request().UserAgent(), "upload-progress", true)
+	defer c.logSSEConnection(clientID, ctx.RealIP(), "", "upload-progress", false)
+
+	current, ch := job.subscribe()
+	if ch != nil {
+		defer job.unsubscribe(ch)
+	}
+
+	if err := c.sendConnectionMessage(ctx, clientID, "Connected to upload analysis progress", "upload_progress"); err != nil {
+		return err
+	}
+	if err := c.sendSSEMessage(ctx, "progress", current); err != nil {
+		return err
+	}
+	if ch == nil {
+		return c.sendSSEMessage(ctx, "done", job.response(true))
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case p, ok := <-ch:
+			if !ok {
+				return c.sendSSEMessage(ctx, "done", job.response(true))
+			}
+			if err := c.sendSSEMessage(ctx, "progress", p); err != nil {
+				return err
+			}
+		case <-heartbeat.C:
+			if err := c.sendSSEHeartbeat(ctx, clientID, "upload_progress"); err != nil {
+				return err
+			}
+		case <-ctx.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// DeleteUploadAnalysis handles DELETE /api/v2/analysis/uploads/:id
+// Cancels a queued or running analysis, or removes a finished one. Detections
+// already saved by the analysis are kept.
+func (c *Controller) DeleteUploadAnalysis(ctx echo.Context) error {
+	id := ctx.Param("id")
+	job, ok := c.uploads.get(id)
+	if !ok {
+		return c.uploadNotFound(ctx, id)
+	}
+
+	if job.done() {
+		c.uploads.remove(id)
+		return ctx.NoContent(http.StatusNoContent)
+	}
+
+	job.mu.Lock()
+	job.cancelled = true
+	if job.cancel != nil {
+		job.cancel()
+	}
+	job.mu.Unlock()
+
+	c.logInfoIfEnabled("Upload analysis cancellation requested",
+		logger.String("job_id", id),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusAccepted, map[string]string{
+		"message": "Upload analysis is being cancelled",
+	})
+}
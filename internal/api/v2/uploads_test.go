@@ -0,0 +1,336 @@
+// uploads_test.go: Tests for the audio upload analysis endpoints
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// fakePredictor returns canned predictions for each analysed chunk
+type fakePredictor struct {
+	mu     sync.Mutex
+	chunks int
+	starts []time.Time
+	notes  [][]datastore.Note // Predictions by chunk index
+}
+
+func (p *fakePredictor) ProcessChunk(_ []float32, predStart time.Time) ([]datastore.Note, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	index := p.chunks
+	p.chunks++
+	p.starts = append(p.starts, predStart)
+	if index < len(p.notes) {
+		return p.notes[index], nil
+	}
+	return nil, nil
+}
+
+// writeTestWAV writes a silent mono WAV file of the given length
+func writeTestWAV(t *testing.T, seconds float64) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.wav")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	enc := wav.NewEncoder(f, conf.SampleRate, 16, 1, 1)
+	buf := &audio.IntBuffer{
+		Data:   make([]int, int(seconds*conf.SampleRate)),
+		Format: &audio.Format{SampleRate: conf.SampleRate, NumChannels: 1},
+	}
+	require.NoError(t, enc.Write(buf))
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+// setupUploadTest creates a controller with a fake predictor and a SQLite datastore
+func setupUploadTest(t *testing.T) (*Controller, *fakePredictor) {
+	t.Helper()
+
+	controller, _ := newSQLiteTestController(t, &datastore.Note{}, &datastore.Results{})
+	controller.Settings.Main.Name = "Test Station"
+	controller.Settings.BirdNET.Threshold = 0.7
+	controller.Settings.BirdNET.Latitude = 60.17
+	controller.Settings.BirdNET.Longitude = 24.94
+
+	predictor := &fakePredictor{notes: [][]datastore.Note{
+		{
+			{ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Confidence: 0.91},
+			{ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.3},
+		},
+		nil,
+		{{ScientificName: "Corvus corax", CommonName: "Common Raven", Confidence: 0.8}},
+	}}
+	controller.uploadPredictor = predictor
+	return controller, predictor
+}
+
+// doUploadRequest posts an audio file with the given form fields
+func doUploadRequest(t *testing.T, controller *Controller, filename string, data []byte, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	for k, v := range fields {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/analysis/uploads", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.UploadAudioForAnalysis(controller.Echo.NewContext(req, rec)))
+	return rec
+}
+
+// waitForUpload polls an upload analysis until it has finished
+func waitForUpload(t *testing.T, controller *Controller, id string) UploadJobResponse {
+	t.Helper()
+	var job UploadJobResponse
+	require.Eventually(t, func() bool {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		rec := httptest.NewRecorder()
+		ctx := controller.Echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+		require.NoError(t, controller.GetUploadAnalysis(ctx))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		return job.FinishedAt != nil
+	}, 10*time.Second, 20*time.Millisecond)
+	return job
+}
+
+func TestUploadAudioForAnalysis(t *testing.T) {
+	controller, predictor := setupUploadTest(t)
+
+	rec := doUploadRequest(t, controller, "garden.wav", writeTestWAV(t, 9), map[string]string{
+		"threshold":  "0.5",
+		"recordedAt": "2024-05-01T05:00:00Z",
+		"save":       "true",
+	})
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var queued UploadJobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queued))
+	assert.Equal(t, "garden.wav", queued.Filename)
+	assert.InDelta(t, 9.0, queued.Duration, 0.001)
+	assert.InDelta(t, 0.5, queued.Options.Threshold, 0.0001)
+
+	job := waitForUpload(t, controller, queued.ID)
+	require.Equal(t, UploadStatusCompleted, job.Progress.Status, job.Progress.Error)
+	assert.Equal(t, 3, job.Progress.Chunk)
+	assert.Equal(t, 2, job.Progress.Detections, "the Great Tit is below the threshold")
+	require.Len(t, job.Segments, 2)
+	assert.InDelta(t, 0.0, job.Segments[0].Start, 0.001)
+	assert.Equal(t, "Turdus merula", job.Segments[0].Detections[0].ScientificName)
+	assert.InDelta(t, 6.0, job.Segments[1].Start, 0.001)
+	assert.InDelta(t, 9.0, job.Segments[1].End, 0.001)
+	assert.Equal(t, 2, job.SavedDetections)
+
+	predictor.mu.Lock()
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 0, 6, 0, time.UTC), predictor.starts[2].UTC())
+	predictor.mu.Unlock()
+
+	var notes []datastore.Note
+	require.NoError(t, controller.DS.(*datastore.SQLiteStore).DB.Order("id").Find(&notes).Error)
+	require.Len(t, notes, 2)
+	assert.Equal(t, UploadSourceID, notes[0].Source.ID)
+	assert.Equal(t, "Upload: garden.wav", notes[0].Source.DisplayName)
+	assert.Equal(t, job.Segments[0].Detections[0].NoteID, notes[0].ID)
+	assert.InDelta(t, 60.17, notes[1].Latitude, 0.0001, "uploads without a location use the station location")
+
+	t.Run("location override and no saving", func(t *testing.T) {
+		rec := doUploadRequest(t, controller, "field.wav", writeTestWAV(t, 3), map[string]string{
+			"latitude":  "51.5",
+			"longitude": "-0.12",
+		})
+		require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+		var queued UploadJobResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queued))
+		require.NotNil(t, queued.Options.Latitude)
+		assert.InDelta(t, 51.5, *queued.Options.Latitude, 0.0001)
+		assert.InDelta(t, 0.7, queued.Options.Threshold, 0.0001, "the BirdNET threshold is the default")
+
+		job := waitForUpload(t, controller, queued.ID)
+		assert.Equal(t, UploadStatusCompleted, job.Progress.Status)
+		assert.Zero(t, job.SavedDetections)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		wavData := writeTestWAV(t, 3)
+		tests := []struct {
+			name     string
+			filename string
+			data     []byte
+			fields   map[string]string
+		}{
+			{"unsupported extension", "notes.txt", []byte("hello"), nil},
+			{"unreadable audio", "broken.wav", []byte("not a wav file"), nil},
+			{"threshold out of range", "a.wav", wavData, map[string]string{"threshold": "1.5"}},
+			{"bad time", "a.wav", wavData, map[string]string{"recordedAt": "yesterday"}},
+			{"latitude without longitude", "a.wav", wavData, map[string]string{"latitude": "60"}},
+			{"unknown locale", "a.wav", wavData, map[string]string{"locale": "xx-nope"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := doUploadRequest(t, controller, tt.filename, tt.data, tt.fields)
+				assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("unavailable without BirdNET", func(t *testing.T) {
+		controller.uploadPredictor = nil
+		defer func() { controller.uploadPredictor = predictor }()
+		rec := doUploadRequest(t, controller, "a.wav", writeTestWAV(t, 3), nil)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+// blockingSaveStore blocks each note save until released
+type blockingSaveStore struct {
+	datastore.Interface
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSaveStore) Save(note *datastore.Note, results []datastore.Results) error {
+	s.saving <- struct{}{}
+	<-s.release
+	return s.Interface.Save(note, results)
+}
+
+func TestSaveUploadDetectionsKeepsJobReadable(t *testing.T) {
+	controller, _ := setupUploadTest(t)
+	store := &blockingSaveStore{
+		Interface: controller.DS,
+		saving:    make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+	controller.DS = store
+
+	job := &uploadJob{
+		id:      "job",
+		options: UploadAnalysisOptions{RecordedAt: time.Date(2024, 5, 1, 5, 0, 0, 0, time.UTC), Threshold: 0.5},
+		segments: []UploadSegment{
+			{Start: 0, End: 3, Detections: []UploadDetection{{ScientificName: "Turdus merula", Confidence: 0.9}}},
+			{Start: 3, End: 6, Detections: []UploadDetection{{ScientificName: "Corvus corax", Confidence: 0.8}}},
+		},
+	}
+	saved := make(chan error, 1)
+	go func() { saved <- controller.saveUploadDetections(t.Context(), job) }()
+
+	// The job can be read and followed while a note is being saved
+	<-store.saving
+	resp := job.response(true)
+	assert.Zero(t, resp.SavedDetections)
+	_, ch := job.subscribe()
+	job.unsubscribe(ch)
+
+	store.release <- struct{}{}
+	<-store.saving
+	assert.Equal(t, 1, job.response(false).SavedDetections)
+	store.release <- struct{}{}
+	require.NoError(t, <-saved)
+
+	resp = job.response(true)
+	assert.Equal(t, 2, resp.SavedDetections)
+	assert.NotZero(t, resp.Segments[0].Detections[0].NoteID)
+	assert.NotZero(t, resp.Segments[1].Detections[0].NoteID)
+}
+
+// rangePredictor is a fakePredictor with a range filter, it records the location
+// and the date it was asked for
+type rangePredictor struct {
+	fakePredictor
+	labels    []string
+	latitude  float64
+	longitude float64
+	date      time.Time
+}
+
+func (p *rangePredictor) GetProbableSpeciesAt(latitude, longitude float64, date time.Time, _ float32) ([]birdnet.SpeciesScore, error) {
+	p.latitude, p.longitude = latitude, longitude
+	p.date = date
+	scores := make([]birdnet.SpeciesScore, 0, len(p.labels))
+	for _, label := range p.labels {
+		scores = append(scores, birdnet.SpeciesScore{Score: 0.5, Label: label})
+	}
+	return scores, nil
+}
+
+func TestUploadRangeFilter(t *testing.T) {
+	controller, _ := setupUploadTest(t)
+	predictor := &rangePredictor{labels: []string{"Corvus corax_Common Raven"}}
+	recordedAt := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+
+	t.Run("station without a species list", func(t *testing.T) {
+		isIncluded, err := controller.uploadRangeFilter(predictor, &uploadJob{options: UploadAnalysisOptions{RecordedAt: recordedAt}})
+		require.NoError(t, err)
+		assert.Nil(t, isIncluded)
+	})
+
+	t.Run("location override", func(t *testing.T) {
+		lat, lon := 51.5, -0.12
+		job := &uploadJob{options: UploadAnalysisOptions{RecordedAt: recordedAt, Latitude: &lat, Longitude: &lon}}
+		isIncluded, err := controller.uploadRangeFilter(predictor, job)
+		require.NoError(t, err)
+		require.NotNil(t, isIncluded)
+		assert.True(t, isIncluded("Corvus corax"))
+		assert.False(t, isIncluded("Turdus merula"))
+
+		assert.InDelta(t, 51.5, predictor.latitude, 0.0001, "the range filter uses the upload location")
+		assert.InDelta(t, -0.12, predictor.longitude, 0.0001)
+		assert.Equal(t, recordedAt, predictor.date, "the range filter uses the recording date")
+		assert.InDelta(t, 60.17, controller.Settings.BirdNET.Latitude, 0.0001, "the station location is left untouched")
+		assert.InDelta(t, 24.94, controller.Settings.BirdNET.Longitude, 0.0001)
+	})
+
+	t.Run("location override without a range filter", func(t *testing.T) {
+		lat, lon := 51.5, -0.12
+		job := &uploadJob{options: UploadAnalysisOptions{RecordedAt: recordedAt, Latitude: &lat, Longitude: &lon}}
+		isIncluded, err := controller.uploadRangeFilter(&predictor.fakePredictor, job)
+		require.NoError(t, err)
+		assert.Nil(t, isIncluded)
+	})
+}
+
+func TestParseUploadTime(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseUploadTime("2024-05-01T05:00:00+03:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC), got.UTC())
+
+	got, err = parseUploadTime("2024-05-01 05:30:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 5, 30, 0, 0, time.Local), got)
+
+	got, err = parseUploadTime("2024-05-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), got)
+
+	_, err = parseUploadTime("05/01/2024")
+	assert.Error(t, err)
+}
@@ -0,0 +1,336 @@
+// internal/api/v2/users.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// CreateUserRequest is the request body for creating a user
+type CreateUserRequest struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	Role        string `json:"role"`
+	Password    string `json:"password"` // Empty creates an account that signs in only with OAuth
+}
+
+// UpdateUserRequest is the request body for changing a user, omitted fields are kept
+type UpdateUserRequest struct {
+	DisplayName *string `json:"displayName"`
+	Role        *string `json:"role"`
+	Password    *string `json:"password"` // Empty removes password sign in
+	Disabled    *bool   `json:"disabled"`
+}
+
+// AddIdentityRequest is the request body for mapping an OAuth account to a user
+type AddIdentityRequest struct {
+	Provider string `json:"provider"` // Provider ID, e.g. "google", "github" or "microsoft"
+	Subject  string `json:"subject"`  // User ID or email reported by the provider
+}
+
+// UserIdentityResponse describes an OAuth identity of a user
+type UserIdentityResponse struct {
+	ID        uint      `json:"id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UserResponse describes a user, the password hash is never returned
+type UserResponse struct {
+	ID          uint                   `json:"id"`
+	Username    string                 `json:"username"`
+	DisplayName string                 `json:"displayName,omitempty"`
+	Role        string                 `json:"role"`
+	Disabled    bool                   `json:"disabled"`
+	HasPassword bool                   `json:"hasPassword"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+	LastLoginAt *time.Time             `json:"lastLoginAt,omitempty"`
+	Identities  []UserIdentityResponse `json:"identities"`
+}
+
+// initUserRoutes registers the user management endpoints
+func (c *Controller) initUserRoutes() {
+	c.logInfoIfEnabled("Initializing user routes")
+
+	userGroup := c.Group.Group("/users", c.authMiddleware, auth.RequireRole(auth.RoleAdmin))
+	userGroup.GET("", c.ListUsers)
+	userGroup.POST("", c.CreateUser)
+	userGroup.GET("/roles", c.GetUserRoles)
+	userGroup.PATCH("/:id", c.UpdateUser)
+	userGroup.DELETE("/:id", c.DeleteUser)
+	userGroup.POST("/:id/identities", c.AddUserIdentity)
+	userGroup.DELETE("/:id/identities/:identityId", c.RemoveUserIdentity)
+
+	c.logInfoIfEnabled("User routes initialized successfully")
+}
+
+// usersUnavailable responds that user accounts are not supported by the datastore
+func (c *Controller) usersUnavailable(ctx echo.Context) error {
+	return c.HandleError(ctx, errors.Newf("user manager not available").
+		Category(errors.CategorySystem).
+		Component("api-users").
+		Build(), "User accounts are not available", http.StatusServiceUnavailable)
+}
+
+// handleUserError maps user management errors to HTTP responses
+func (c *Controller) handleUserError(ctx echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidRole), errors.Is(err, auth.ErrInvalidUsername),
+		errors.Is(err, auth.ErrInvalidPassword), errors.Is(err, auth.ErrInvalidProvider):
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, auth.ErrUsernameTaken), errors.Is(err, auth.ErrIdentityTaken):
+		return c.HandleError(ctx, err, err.Error(), http.StatusConflict)
+	case errors.Is(err, datastore.ErrUserNotFound):
+		return c.HandleError(ctx, err, "User not found", http.StatusNotFound)
+	case errors.Is(err, datastore.ErrUserIdentityNotFound):
+		return c.HandleError(ctx, err, "User identity not found", http.StatusNotFound)
+	default:
+		return c.HandleError(ctx, err, message, http.StatusInternalServerError)
+	}
+}
+
+// parseUserID parses a numeric ID path parameter
+func parseUserID(ctx echo.Context, param string) (uint, error) {
+	id, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// newUserResponse converts a stored user to its API representation
+func newUserResponse(user *datastore.User) UserResponse {
+	identities := make([]UserIdentityResponse, 0, len(user.Identities))
+	for i := range user.Identities {
+		identity := &user.Identities[i]
+		identities = append(identities, UserIdentityResponse{
+			ID:        identity.ID,
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+	return UserResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Role:        user.Role,
+		Disabled:    user.Disabled,
+		HasPassword: user.PasswordHash != "",
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		LastLoginAt: user.LastLoginAt,
+		Identities:  identities,
+	}
+}
+
+// ListUsers handles GET /api/v2/users
+// Lists all users with their roles and OAuth identities.
+func (c *Controller) ListUsers(ctx echo.Context) error {
+	if c.userManager == nil {
+		return c.usersUnavailable(ctx)
+	}
+
+	users, err := c.userManager.List()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list users", http.StatusInternalServerError)
+	}
+
+	response := make([]UserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, newUserResponse(&users[i]))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetUserRoles handles GET /api/v2/users/roles
+// Lists the roles that can be given to users, from the least to the most privileged.
+func (c *Controller) GetUserRoles(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, auth.Roles)
+}
+
+// CreateUser handles POST /api/v2/users
+// Creates a user that signs in with a password, an OAuth identity or both.
+func (c *Controller) CreateUser(ctx echo.Context) error {
+	if c.userManager == nil {
+		return c.usersUnavailable(ctx)
+	}
+
+	var req CreateUserRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	user, err := c.userManager.Create(req.Username, req.DisplayName, role, req.Password)
+	if err != nil {
+		return c.handleUserError(ctx, err, "Failed to create user")
+	}
+
+	c.logInfoIfEnabled("User created",
+		logger.String("username", user.Username),
+		logger.String("role", user.Role),
+		logger.String("created_by", stringFromCtx(ctx, auth.CtxKeyUsername, "")),
+		logger.String("ip", ctx.RealIP()),
+	)
+	return ctx.JSON(http.StatusCreated, newUserResponse(user))
+}
+
+// UpdateUser handles PATCH /api/v2/users/:id
+// Changes the display name, role, password or disabled state of a user. Admins
+// cannot demote or disable their own account to avoid locking themselves out.
+func (c *Controller) UpdateUser(ctx echo.Context) error {
+	if c.userManager == nil {
+		return c.usersUnavailable(ctx)
+	}
+
+	id, err := parseUserID(ctx, "id")
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid user ID", http.StatusBadRequest)
+	}
+	var req UpdateUserRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	update := auth.UserUpdate{
+		DisplayName: req.DisplayName,
+		Password:    req.Password,
+		Disabled:    req.Disabled,
+	}
+	if req.Role != nil {
+		role, err := auth.ParseRole(*req.Role)
+		if err != nil {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		update.Role = &role
+	}
+
+	if c.isCurrentUser(ctx, id) &&
+		((update.Role != nil && *update.Role != auth.RoleAdmin) || (update.Disabled != nil && *update.Disabled)) {
+		return c.HandleError(ctx, errors.Newf("admin tried to demote or disable own account").
+			Category(errors.CategoryValidation).
+			Component("api-users").
+			Build(), "You cannot demote or disable your own account", http.StatusBadRequest)
+	}
+
+	user, err := c.userManager.Update(id, update)
+	if err != nil {
+		return c.handleUserError(ctx, err, "Failed to update user")
+	}
+
+	c.logInfoIfEnabled("User updated",
+		logger.String("username", user.Username),
+		logger.String("role", user.Role),
+		logger.Bool("disabled", user.Disabled),
+		logger.String("ip", ctx.RealIP()),
+	)
+	return ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// DeleteUser handles DELETE /api/v2/users/:id
+// Deletes a user and its OAuth identities. Reviews and comments keep the username.
+func (c *Controller) DeleteUser(ctx echo.Context) error {
+	if c.userManager == nil {
+		return c.usersUnavailable(ctx)
+	}
+
+	id, err := parseUserID(ctx, "id")
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid user ID", http.StatusBadRequest)
+	}
+	if c.isCurrentUser(ctx, id) {
+		return c.HandleError(ctx, errors.Newf("admin tried to delete own account").
+			Category(errors.CategoryValidation).
+			Component("api-users").
+			Build(), "You cannot delete your own account", http.StatusBadRequest)
+	}
+
+	if err := c.userManager.Delete(id); err != nil {
+		return c.handleUserError(ctx, err, "Failed to delete user")
+	}
+
+	c.logInfoIfEnabled("User deleted",
+		logger.Int64("user_id", int64(id)),
+		logger.String("ip", ctx.RealIP()),
+	)
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// AddUserIdentity handles POST /api/v2/users/:id/identities
+// Maps an OAuth provider account to a user, the account then signs in as the user.
+func (c *Controller) AddUserIdentity(ctx echo.Context) error {
+	if c.userManager == nil {
+		return c.usersUnavailable(ctx)
+	}
+
+	id, err := parseUserID(ctx, "id")
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid user ID", http.StatusBadRequest)
+	}
+	var req AddIdentityRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	identity, err := c.userManager.AddIdentity(id, req.Provider, req.Subject)
+	if err != nil {
+		return c.handleUserError(ctx, err, "Failed to add user identity")
+	}
+
+	c.logInfoIfEnabled("User identity added",
+		logger.Int64("user_id", int64(id)),
+		logger.String("provider", identity.Provider),
+		logger.String("ip", ctx.RealIP()),
+	)
+	return ctx.JSON(http.StatusCreated, UserIdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		CreatedAt: identity.CreatedAt,
+	})
+}
+
+// RemoveUserIdentity handles DELETE /api/v2/users/:id/identities/:identityId
+// Removes an OAuth identity from a user.
+func (c *Controller) RemoveUserIdentity(ctx echo.Context) error {
+	if c.userManager == nil {
+		return c.usersUnavailable(ctx)
+	}
+
+	id, err := parseUserID(ctx, "id")
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid user ID", http.StatusBadRequest)
+	}
+	identityID, err := parseUserID(ctx, "identityId")
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid identity ID", http.StatusBadRequest)
+	}
+
+	if err := c.userManager.RemoveIdentity(id, identityID); err != nil {
+		return c.handleUserError(ctx, err, "Failed to remove user identity")
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// isCurrentUser reports whether the user with the given ID made the request
+func (c *Controller) isCurrentUser(ctx echo.Context, id uint) bool {
+	username := stringFromCtx(ctx, auth.CtxKeyUsername, "")
+	if username == "" {
+		return false
+	}
+	user, err := c.userManager.Get(id)
+	return err == nil && user.Username == username
+}
@@ -0,0 +1,460 @@
+// users_test.go: Tests for user accounts, roles and user management endpoints
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	"github.com/markbates/goth/gothic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupUserTest creates a controller with password authentication and user accounts
+func setupUserTest(t *testing.T) (*echo.Echo, *auth.UserManager) {
+	t.Helper()
+	return setupUserTestWithStore(t, newTestUserStore(t))
+}
+
+// newTestUserStore returns a user store backed by an in-memory database
+func newTestUserStore(t *testing.T) auth.UserStore {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&datastore.User{}, &datastore.UserIdentity{}))
+	return (&datastore.DataStore{DB: db}).NewUserStore()
+}
+
+// setupUserTestWithStore creates a controller with password authentication and
+// user accounts kept in store
+func setupUserTestWithStore(t *testing.T, store auth.UserStore) (*echo.Echo, *auth.UserManager) {
+	t.Helper()
+
+	settings := &conf.Settings{
+		Realtime: conf.RealtimeSettings{
+			Audio: conf.AudioSettings{Export: conf.ExportSettings{Path: t.TempDir()}},
+		},
+		Security: conf.Security{
+			SessionSecret: "test-session-secret-32-chars-long",
+			BasicAuth: conf.BasicAuth{
+				Enabled:        true,
+				ClientID:       "birdnet-client",
+				Password:       "testpassword123",
+				AuthCodeExp:    5 * time.Minute,
+				AccessTokenExp: 24 * time.Hour,
+			},
+		},
+	}
+
+	users := auth.NewUserManager(store, settings)
+
+	mockImageProvider := &MockImageProvider{}
+	mockImageProvider.On("Fetch", mock.Anything).Return(imageprovider.BirdImage{}, nil).Maybe()
+	birdImageCache := &imageprovider.BirdImageCache{}
+	birdImageCache.SetImageProvider(mockImageProvider)
+	mockMetrics, _ := observability.NewMetrics()
+	controlChan := make(chan string, 10)
+
+	authService := auth.NewSecurityAdapter(createTestOAuth2Server(settings))
+	authService.Users = users
+	authMw := auth.NewMiddleware(authService)
+	gothic.Store = sessions.NewCookieStore([]byte(settings.Security.SessionSecret))
+
+	e := echo.New()
+	controller, err := NewWithOptions(e, mocks.NewMockInterface(t), settings, birdImageCache,
+		suncalc.NewSunCalc(60.1699, 24.9384), controlChan, mockMetrics, true,
+		WithAuthMiddleware(authMw.Authenticate), WithAuthService(authService), WithUserManager(users))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		close(controlChan)
+	})
+
+	return e, users
+}
+
+// loginSession logs in through the v2 login and callback endpoints and returns the session cookies
+func loginSession(t *testing.T, e *echo.Echo, username, password string) []*http.Cookie {
+	t.Helper()
+
+	body := `{"username": "` + username + `", "password": "` + password + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/auth/login", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return nil
+	}
+
+	var resp AuthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	callback, err := url.Parse(resp.RedirectURL)
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodGet, callback.String(), http.NoBody)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code, "callback should establish the session")
+
+	// The callback expires the old session cookie before setting the new one,
+	// keep only the live cookies like a browser would
+	var cookies []*http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+// doSessionRequest performs a request with the given session cookies
+func doSessionRequest(e *echo.Echo, method, path string, cookies []*http.Cookie, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUserAccountLogin(t *testing.T) {
+	e, users := setupUserTest(t)
+
+	alice, err := users.Create("Alice", "Alice Example", auth.RoleReviewer, "alice-password")
+	require.NoError(t, err)
+
+	t.Run("wrong password is rejected", func(t *testing.T) {
+		assert.Nil(t, loginSession(t, e, "alice", "wrong-password"))
+	})
+
+	t.Run("account session has the account role", func(t *testing.T) {
+		cookies := loginSession(t, e, "alice", "alice-password")
+		require.NotEmpty(t, cookies)
+
+		rec := doSessionRequest(e, http.MethodGet, "/api/v2/auth/status", cookies, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var status AuthStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		assert.Equal(t, "alice", status.Username)
+		assert.Equal(t, string(auth.RoleReviewer), status.Role)
+
+		rec = doSessionRequest(e, http.MethodGet, "/api/v2/settings", cookies, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code, "reviewers cannot read settings")
+		rec = doSessionRequest(e, http.MethodGet, "/api/v2/users", cookies, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code, "reviewers cannot manage users")
+
+		disabled := true
+		_, err := users.Update(alice.ID, auth.UserUpdate{Disabled: &disabled})
+		require.NoError(t, err)
+		rec = doSessionRequest(e, http.MethodGet, "/api/v2/auth/status", cookies, "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "sessions of disabled users end")
+	})
+
+	t.Run("admin password keeps the admin role", func(t *testing.T) {
+		cookies := loginSession(t, e, "birdnet-client", "testpassword123")
+		require.NotEmpty(t, cookies)
+
+		rec := doSessionRequest(e, http.MethodGet, "/api/v2/auth/status", cookies, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var status AuthStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		assert.Equal(t, string(auth.RoleAdmin), status.Role)
+
+		rec = doSessionRequest(e, http.MethodGet, "/api/v2/users", cookies, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestUserAccountCannotTakeAdminUsername(t *testing.T) {
+	e, users := setupUserTest(t)
+
+	_, err := users.Create("Birdnet-Client", "", auth.RoleViewer, "testpassword123")
+	require.ErrorIs(t, err, auth.ErrUsernameTaken, "the admin username is reserved case-insensitively")
+
+	cookies := loginSession(t, e, "birdnet-client", "testpassword123")
+	require.NotEmpty(t, cookies, "the config admin can still log in")
+	rec := doSessionRequest(e, http.MethodGet, "/api/v2/auth/status", cookies, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status AuthStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, string(auth.RoleAdmin), status.Role)
+
+	rec = doSessionRequest(e, http.MethodPost, "/api/v2/users", cookies,
+		`{"username": "BIRDNET-CLIENT", "role": "viewer", "password": "viewer-password"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestStoreInSessionKeepsAllValues(t *testing.T) {
+	gothic.Store = sessions.NewCookieStore([]byte("test-session-secret-32-chars-long"))
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/auth/google/callback", http.NoBody), rec)
+	values := map[string]string{"userId": "bob@example.com", "google": "12345", auth.SessionKeyAccount: "bob"}
+	require.NoError(t, auth.StoreInSession(c, values))
+
+	// A new cookie session keeps every value written in the single save
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	for _, cookie := range rec.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	for key, want := range values {
+		got, err := gothic.GetFromSession(key, req)
+		require.NoError(t, err, key)
+		assert.Equal(t, want, got, key)
+	}
+}
+
+func TestNotificationChangesRequireRoles(t *testing.T) {
+	e, users := setupUserTest(t)
+
+	_, err := users.Create("victor", "", auth.RoleViewer, "victor-password")
+	require.NoError(t, err)
+	_, err = users.Create("rita", "", auth.RoleReviewer, "rita-password")
+	require.NoError(t, err)
+	viewer := loginSession(t, e, "victor", "victor-password")
+	require.NotEmpty(t, viewer)
+	reviewer := loginSession(t, e, "rita", "rita-password")
+	require.NotEmpty(t, reviewer)
+
+	tests := []struct {
+		method, path string
+		cookies      []*http.Cookie
+	}{
+		{http.MethodPut, "/api/v2/notifications/abc/read", viewer},
+		{http.MethodPut, "/api/v2/notifications/abc/acknowledge", viewer},
+		{http.MethodDelete, "/api/v2/notifications/abc", viewer},
+		{http.MethodDelete, "/api/v2/notifications/abc", reviewer},
+	}
+	for _, tt := range tests {
+		rec := doSessionRequest(e, tt.method, tt.path, tt.cookies, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code, tt.method+" "+tt.path)
+	}
+
+	rec := doSessionRequest(e, http.MethodPut, "/api/v2/notifications/abc/read", reviewer, "")
+	assert.NotEqual(t, http.StatusForbidden, rec.Code, "reviewers mark notifications as read")
+}
+
+// countingUserStore counts the username lookups of a user store
+type countingUserStore struct {
+	auth.UserStore
+	lookups atomic.Int32
+}
+
+func (s *countingUserStore) GetByUsername(username string) (*datastore.User, error) {
+	s.lookups.Add(1)
+	return s.UserStore.GetByUsername(username)
+}
+
+func TestUserAccountSessionLooksUpUserOnce(t *testing.T) {
+	store := &countingUserStore{UserStore: newTestUserStore(t)}
+	e, users := setupUserTestWithStore(t, store)
+
+	_, err := users.Create("alice", "", auth.RoleReviewer, "alice-password")
+	require.NoError(t, err)
+	cookies := loginSession(t, e, "alice", "alice-password")
+	require.NotEmpty(t, cookies)
+
+	store.lookups.Store(0)
+	rec := doSessionRequest(e, http.MethodGet, "/api/v2/auth/status", cookies, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status AuthStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, string(auth.RoleReviewer), status.Role)
+	assert.Equal(t, int32(1), store.lookups.Load(), "the session user is looked up once per request")
+}
+
+func TestUserManagementEndpoints(t *testing.T) {
+	e, users := setupUserTest(t)
+
+	_, err := users.Create("root", "", auth.RoleAdmin, "root-password")
+	require.NoError(t, err)
+	cookies := loginSession(t, e, "root", "root-password")
+	require.NotEmpty(t, cookies)
+
+	var created UserResponse
+	t.Run("create user", func(t *testing.T) {
+		rec := doSessionRequest(e, http.MethodPost, "/api/v2/users", cookies,
+			`{"username": "Bob", "displayName": "Bob", "role": "viewer", "password": "bob-password"}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.Equal(t, "bob", created.Username)
+		assert.Equal(t, string(auth.RoleViewer), created.Role)
+		assert.True(t, created.HasPassword)
+		assert.NotContains(t, rec.Body.String(), "bob-password")
+	})
+
+	t.Run("invalid users are rejected", func(t *testing.T) {
+		tests := []struct {
+			body string
+			want int
+		}{
+			{`{"username": "bob", "role": "viewer"}`, http.StatusConflict},
+			{`{"username": "carol", "role": "owner"}`, http.StatusBadRequest},
+			{`{"username": "car ol", "role": "viewer"}`, http.StatusBadRequest},
+			{`{"username": "carol", "role": "viewer", "password": "short"}`, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			rec := doSessionRequest(e, http.MethodPost, "/api/v2/users", cookies, tt.body)
+			assert.Equal(t, tt.want, rec.Code, tt.body)
+		}
+	})
+
+	userPath := "/api/v2/users/" + strconv.FormatUint(uint64(created.ID), 10)
+
+	t.Run("update user", func(t *testing.T) {
+		rec := doSessionRequest(e, http.MethodPatch, userPath, cookies, `{"role": "reviewer", "password": ""}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var updated UserResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+		assert.Equal(t, string(auth.RoleReviewer), updated.Role)
+		assert.False(t, updated.HasPassword)
+		assert.Equal(t, "Bob", updated.DisplayName, "omitted fields are kept")
+	})
+
+	t.Run("admins cannot lock themselves out", func(t *testing.T) {
+		root, err := users.Lookup("root")
+		require.NoError(t, err)
+		rootPath := "/api/v2/users/" + strconv.FormatUint(uint64(root.ID), 10)
+
+		rec := doSessionRequest(e, http.MethodPatch, rootPath, cookies, `{"role": "viewer"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		rec = doSessionRequest(e, http.MethodDelete, rootPath, cookies, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("map OAuth identities", func(t *testing.T) {
+		rec := doSessionRequest(e, http.MethodPost, userPath+"/identities", cookies,
+			`{"provider": "microsoftonline", "subject": "bob@example.com"}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var identity UserIdentityResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &identity))
+		assert.Equal(t, "microsoft", identity.Provider, "goth provider names are stored as config IDs")
+
+		rec = doSessionRequest(e, http.MethodPost, userPath+"/identities", cookies,
+			`{"provider": "microsoft", "subject": "bob@example.com"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		rec = doSessionRequest(e, http.MethodPost, userPath+"/identities", cookies,
+			`{"provider": "myspace", "subject": "bob"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		user, err := users.LookupIdentity("microsoftonline", "12345", "bob@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, user.ID)
+
+		path := userPath + "/identities/" + strconv.FormatUint(uint64(identity.ID), 10)
+		rec = doSessionRequest(e, http.MethodDelete, path, cookies, "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		_, err = users.LookupIdentity("microsoft", "bob@example.com")
+		require.ErrorIs(t, err, datastore.ErrUserNotFound)
+	})
+
+	t.Run("list and delete users", func(t *testing.T) {
+		rec := doSessionRequest(e, http.MethodGet, "/api/v2/users", cookies, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var list []UserResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		assert.Len(t, list, 2)
+
+		rec = doSessionRequest(e, http.MethodDelete, userPath, cookies, "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		rec = doSessionRequest(e, http.MethodDelete, userPath, cookies, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		role     any
+		required auth.Role
+		want     int
+	}{
+		{"viewer reads", auth.RoleViewer, auth.RoleViewer, http.StatusOK},
+		{"viewer reviews", auth.RoleViewer, auth.RoleReviewer, http.StatusForbidden},
+		{"reviewer reviews", auth.RoleReviewer, auth.RoleReviewer, http.StatusOK},
+		{"reviewer changes settings", auth.RoleReviewer, auth.RoleAdmin, http.StatusForbidden},
+		{"admin changes settings", auth.RoleAdmin, auth.RoleAdmin, http.StatusOK},
+		{"unknown role", auth.Role("owner"), auth.RoleViewer, http.StatusForbidden},
+		{"no role in the context", nil, auth.RoleViewer, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v2/settings", http.NoBody), rec)
+			if tt.role != nil {
+				c.Set(auth.CtxKeyRole, tt.role)
+			}
+
+			handler := auth.RequireRole(tt.required)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			require.NoError(t, handler(c))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestReviewerCannotIgnoreSpecies(t *testing.T) {
+	_, mockDS, controller := setupTestEnvironment(t)
+	controller.Settings.Realtime.Species.Exclude = []string{}
+	mockDS.On("Get", "7").Return(datastore.Note{ID: 7, CommonName: "Common Chiffchaff"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/detections/7/review",
+		strings.NewReader(`{"verified": "false_positive", "ignoreSpecies": "Common Chiffchaff"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := controller.Echo.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("7")
+	ctx.Set(auth.CtxKeyRole, auth.RoleReviewer)
+	ctx.Set(auth.CtxKeyUsername, "alice")
+
+	require.NoError(t, controller.ReviewDetection(ctx))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, controller.Settings.Realtime.Species.Exclude, "the exclude list is unchanged")
+	mockDS.AssertNotCalled(t, "SaveNoteReview", mock.Anything)
+}
+
+func TestReviewAttribution(t *testing.T) {
+	_, mockDS, controller := setupTestEnvironment(t)
+
+	mockDS.On("SaveNoteReview", mock.MatchedBy(func(review *datastore.NoteReview) bool {
+		return review.NoteID == 7 && review.Verified == "correct" && review.ReviewedBy == "alice"
+	})).Return(nil).Once()
+	mockDS.On("SaveNoteComment", mock.MatchedBy(func(comment *datastore.NoteComment) bool {
+		return comment.NoteID == 7 && comment.CreatedBy == "alice"
+	})).Return(nil).Once()
+
+	require.NoError(t, controller.AddReview(7, true, "alice"))
+	require.NoError(t, controller.AddComment(7, "Clear song", "alice"))
+	mockDS.AssertExpectations(t)
+}
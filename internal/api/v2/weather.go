@@ -83,6 +83,10 @@ func (c *Controller) initWeatherRoutes() {
 
 	// Sun times endpoint using SunCalc
 	weatherGroup.GET("/sun/:date", c.GetSunTimes)
+
+	// Readings pushed by a local weather station, authenticated by the token in the path
+	weatherGroup.GET("/station/:token", c.ReceiveStationReading)
+	weatherGroup.POST("/station/:token", c.ReceiveStationReading)
 }
 
 // buildDailyWeatherResponse creates a DailyWeatherResponse from a DailyEvents struct
@@ -0,0 +1,84 @@
+// internal/api/v2/weather_station.go
+package api
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/weather"
+)
+
+// maxStationReadingSize limits the size of a pushed weather station reading
+const maxStationReadingSize = 64 * 1024
+
+// ReceiveStationReading handles GET and POST /api/v2/weather/station/:token
+//
+// Weather stations push their readings here with the Wunderground upload protocol,
+// which Ecowitt (customized server) and Ambient Weather stations also use, as query
+// or form parameters. WeeWX records are posted as JSON. The reading is saved by the
+// local station weather provider on its next poll.
+func (c *Controller) ReceiveStationReading(ctx echo.Context) error {
+	if c.Settings == nil || c.Settings.Realtime.Weather.Provider != WeatherProviderLocalStation {
+		return c.stationError(ctx, nil, "Local weather station is not the configured weather provider", http.StatusNotFound)
+	}
+
+	// Compare in constant time and never accept readings without a configured token
+	token := c.Settings.Realtime.Weather.LocalStation.Token
+	if token == "" || subtle.ConstantTimeCompare([]byte(ctx.Param("token")), []byte(token)) != 1 {
+		return c.stationError(ctx, nil, "Invalid weather station token", http.StatusUnauthorized)
+	}
+
+	req := ctx.Request()
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, maxStationReadingSize)
+
+	var reading weather.StationReading
+	var err error
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		var body []byte
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return c.stationError(ctx, err, "Failed to read weather station reading", http.StatusBadRequest)
+		}
+		reading, err = weather.ParseWeeWXRecord(body)
+	} else {
+		if err = req.ParseForm(); err != nil {
+			return c.stationError(ctx, err, "Failed to parse weather station reading", http.StatusBadRequest)
+		}
+		reading, err = weather.ParseStationForm(req.Form)
+	}
+	if err != nil {
+		return c.stationError(ctx, err, "Invalid weather station reading", http.StatusBadRequest)
+	}
+
+	weather.SubmitStationReading(reading)
+
+	GetLogger().Debug("Weather station reading received",
+		logger.String("ip", ctx.RealIP()),
+		logger.Time("time", reading.Time))
+
+	// Stations using the Wunderground protocol expect the plain text response of it
+	return ctx.String(http.StatusOK, "success")
+}
+
+// stationError responds with an error to a weather station push. Unlike HandleError
+// it does not log the request path, which contains the station secret.
+func (c *Controller) stationError(ctx echo.Context, err error, message string, code int) error {
+	errorResp := NewErrorResponse(err, message, code)
+
+	errorStr := message
+	if err != nil {
+		errorStr = err.Error()
+	}
+	c.logErrorIfEnabled("Weather station push rejected",
+		logger.String("correlation_id", errorResp.CorrelationID),
+		logger.String("message", message),
+		logger.String("error", errorStr),
+		logger.Int("code", code),
+		logger.String("method", ctx.Request().Method),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(code, errorResp)
+}
@@ -0,0 +1,154 @@
+// weather_station_test.go: Tests for the local weather station push endpoint
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/weather"
+)
+
+const testStationToken = "0123456789abcdef"
+
+// setupWeatherStationTest creates a controller with the local station weather provider
+func setupWeatherStationTest(t *testing.T) (*echo.Echo, *Controller) {
+	t.Helper()
+
+	e := echo.New()
+	settings := &conf.Settings{}
+	settings.BirdNET.Latitude = 60.17
+	settings.BirdNET.Longitude = 24.94
+	settings.Realtime.Weather.Provider = WeatherProviderLocalStation
+	settings.Realtime.Weather.LocalStation.Token = testStationToken
+	settings.Realtime.Weather.LocalStation.MaxAge = 15
+
+	controller := &Controller{
+		Group:    e.Group("/api/v2"),
+		Settings: settings,
+	}
+	return e, controller
+}
+
+// pushStationReading sends a reading to the push endpoint and returns the response
+func pushStationReading(t *testing.T, e *echo.Echo, controller *Controller, token, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/weather/station/"+token, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v2/weather/station/:token")
+	c.SetParamNames("token")
+	c.SetParamValues(token)
+
+	require.NoError(t, controller.ReceiveStationReading(c))
+	return rec
+}
+
+// TestReceiveStationReadingEcowitt tests a reading posted with the Ecowitt protocol
+func TestReceiveStationReadingEcowitt(t *testing.T) {
+	e, controller := setupWeatherStationTest(t)
+
+	form := url.Values{
+		"PASSKEY":      {"ABCDEF"},
+		"stationtype":  {"GW1100A_V2.1.4"},
+		"dateutc":      {"2024-06-01 12:00:00"},
+		"tempf":        {"68.0"},
+		"humidity":     {"55"},
+		"baromrelin":   {"29.921"},
+		"windspeedmph": {"10.0"},
+		"windgustmph":  {"15.0"},
+		"winddir":      {"270"},
+		"rainratein":   {"0.000"},
+	}
+	rec := pushStationReading(t, e, controller, testStationToken, echo.MIMEApplicationForm, form.Encode())
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "success", rec.Body.String())
+
+	provider := weather.NewLocalStationProvider()
+	data, err := provider.FetchWeather(controller.Settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 20.0, data.Temperature.Current, 0.01)
+	assert.Equal(t, 55, data.Humidity)
+	assert.Equal(t, 1013, data.Pressure)
+	assert.InDelta(t, 4.47, data.Wind.Speed, 0.01)
+	assert.Equal(t, 270, data.Wind.Deg)
+	assert.InDelta(t, 60.17, data.Location.Latitude, 0.001)
+	assert.NotEmpty(t, data.Icon)
+
+	_, err = provider.FetchWeather(controller.Settings)
+	require.ErrorIs(t, err, weather.ErrWeatherDataNotModified, "the same reading is not saved twice")
+}
+
+// TestReceiveStationReadingWeeWX tests a WeeWX record posted as JSON
+func TestReceiveStationReadingWeeWX(t *testing.T) {
+	e, controller := setupWeatherStationTest(t)
+
+	record := `{"dateTime": 1717243200, "usUnits": 17, "outTemp": 12.5, "outHumidity": "81", "barometer": 1009.6, "windSpeed": 3.2, "windDir": null, "rainRate": 1.2}`
+	rec := pushStationReading(t, e, controller, testStationToken, echo.MIMEApplicationJSON, record)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	data, err := weather.NewLocalStationProvider().FetchWeather(controller.Settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 12.5, data.Temperature.Current, 0.01)
+	assert.Equal(t, 81, data.Humidity)
+	assert.Equal(t, 1010, data.Pressure)
+	assert.InDelta(t, 3.2, data.Wind.Speed, 0.01)
+	assert.Equal(t, int64(1717243200), data.Time.Unix())
+	assert.Equal(t, string(weather.IconRain), data.Icon, "rain is inferred from the rain rate")
+}
+
+// TestReceiveStationReadingRejected tests readings which are not accepted
+func TestReceiveStationReadingRejected(t *testing.T) {
+	e, controller := setupWeatherStationTest(t)
+
+	rec := pushStationReading(t, e, controller, "wrong-token-0000", echo.MIMEApplicationForm, "tempf=68")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = pushStationReading(t, e, controller, testStationToken, echo.MIMEApplicationForm, "humidity=50")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "readings without temperature are rejected")
+
+	rec = pushStationReading(t, e, controller, testStationToken, echo.MIMEApplicationJSON, `{"outTemp": 12.5}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "WeeWX records without unit system are rejected")
+
+	controller.Settings.Realtime.Weather.Provider = WeatherProviderYrno
+	rec = pushStationReading(t, e, controller, testStationToken, echo.MIMEApplicationForm, "tempf=68")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestReceiveStationReadingNotLogged tests that the station secret and Wunderground
+// passwords are kept out of the API logs
+func TestReceiveStationReadingNotLogged(t *testing.T) {
+	e, controller := setupWeatherStationTest(t)
+	var buf bytes.Buffer
+	controller.apiLogger = logger.NewSlogLogger(&buf, logger.LogLevelDebug, time.UTC)
+	handler := controller.LoggingMiddleware()(controller.ReceiveStationReading)
+
+	for _, token := range []string{testStationToken, "wrong-station-token"} {
+		req := httptest.NewRequest(http.MethodGet,
+			"/api/v2/weather/station/"+token+"?ID=station&PASSWORD=wu-password&dateutc=now&tempf=50", http.NoBody)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v2/weather/station/:token")
+		c.SetParamNames("token")
+		c.SetParamValues(token)
+		require.NoError(t, handler(c))
+	}
+
+	logged := buf.String()
+	assert.Contains(t, logged, "/api/v2/weather/station/[REDACTED]")
+	assert.NotContains(t, logged, testStationToken)
+	assert.NotContains(t, logged, "wrong-station-token")
+	assert.NotContains(t, logged, "wu-password")
+}
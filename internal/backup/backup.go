@@ -42,6 +42,22 @@ type Source interface {
 	Validate() error
 }
 
+// IncrementalSource is implemented by sources that only back up data added since
+// their previous successful backup. The manager keeps their progress in the
+// StateManager and only records it once the backup is stored in every target.
+type IncrementalSource interface {
+	Source
+	// BackupSince backs up the data added after since and returns the state to
+	// record once the backup has been stored. It returns ErrNoChanges when
+	// there is nothing new to back up. To start a new chain with a full backup
+	// it returns a state with an empty BaseID, the manager then sets it to the
+	// ID of the new backup.
+	BackupSince(ctx context.Context, since SourceState) (io.ReadCloser, SourceState, error)
+}
+
+// ErrNoChanges is returned by incremental sources when there is no new data to back up
+var ErrNoChanges = errors.NewStd("no new data since the last backup")
+
 // Target represents a destination where backups are stored
 type Target interface {
 	// Name returns the name of the target
@@ -56,6 +72,13 @@ type Target interface {
 	Validate() error
 }
 
+// Retriever is implemented by targets that can fetch a stored backup archive.
+// It is kept separate from Target so restore support can be added per target.
+type Retriever interface {
+	// Retrieve downloads the archive of the backup with the given ID to destPath
+	Retrieve(ctx context.Context, id, destPath string) error
+}
+
 // Metadata contains information about a backup
 type Metadata struct {
 	Version      int       `json:"version"`                 // Version of the metadata format
@@ -72,6 +95,8 @@ type Metadata struct {
 	Compressed   bool      `json:"compressed,omitempty"`    // Whether the backup is compressed
 	Encrypted    bool      `json:"encrypted,omitempty"`     // Whether the backup is encrypted
 	OriginalSize int64     `json:"original_size,omitempty"` // Original size before compression/encryption
+	Incremental  bool      `json:"incremental,omitempty"`   // Whether the backup only contains data added since the previous one
+	BaseID       string    `json:"base_id,omitempty"`       // ID of the full backup an incremental backup builds on, its own ID for the full backup
 }
 
 // BackupInfo represents information about a stored backup
@@ -150,18 +175,70 @@ func sanitizeConfig(config *conf.Settings) *conf.Settings {
 	}
 
 	// Remove sensitive information
-	sanitized.Security.BasicAuth.Password = ""
-	sanitized.Security.BasicAuth.ClientSecret = ""
-	sanitized.Security.GoogleAuth.ClientSecret = ""
-	sanitized.Security.GithubAuth.ClientSecret = ""
-	sanitized.Security.SessionSecret = ""
-	sanitized.Output.MySQL.Password = ""
-	sanitized.Realtime.MQTT.Password = ""
-	sanitized.Realtime.Weather.OpenWeather.APIKey = ""
+	for _, field := range sensitiveFields(&sanitized) {
+		*field = ""
+	}
+
+	// Remove credentials from backup target settings, archives may be stored off-site
+	for i := range sanitized.Backup.Targets {
+		for _, key := range sensitiveTargetSettings {
+			delete(sanitized.Backup.Targets[i].Settings, key)
+		}
+	}
 
 	return &sanitized
 }
 
+// sensitiveFields returns pointers to the configuration fields holding credentials
+func sensitiveFields(config *conf.Settings) []*string {
+	return []*string{
+		&config.Security.BasicAuth.Password,
+		&config.Security.BasicAuth.ClientSecret,
+		&config.Security.GoogleAuth.ClientSecret,
+		&config.Security.GithubAuth.ClientSecret,
+		&config.Security.SessionSecret,
+		&config.Output.MySQL.Password,
+		&config.Realtime.MQTT.Password,
+		&config.Realtime.MQTT.Commands.Token,
+		&config.Realtime.Weather.OpenWeather.APIKey,
+		&config.Realtime.Weather.LocalStation.Token,
+	}
+}
+
+// restoreSensitiveFields fills the credentials removed by sanitizeConfig from the
+// running configuration. Backup target credentials are taken from the target at
+// the same position when it is of the same type.
+func restoreSensitiveFields(restored, running *conf.Settings) {
+	runningFields := sensitiveFields(running)
+	for i, field := range sensitiveFields(restored) {
+		if *field == "" {
+			*field = *runningFields[i]
+		}
+	}
+
+	for i := range restored.Backup.Targets {
+		if i >= len(running.Backup.Targets) || restored.Backup.Targets[i].Type != running.Backup.Targets[i].Type {
+			continue
+		}
+		for _, key := range sensitiveTargetSettings {
+			value, ok := running.Backup.Targets[i].Settings[key]
+			if !ok {
+				continue
+			}
+			if _, exists := restored.Backup.Targets[i].Settings[key]; exists {
+				continue
+			}
+			if restored.Backup.Targets[i].Settings == nil {
+				restored.Backup.Targets[i].Settings = make(map[string]any)
+			}
+			restored.Backup.Targets[i].Settings[key] = value
+		}
+	}
+}
+
+// sensitiveTargetSettings lists backup target setting keys that hold credentials
+var sensitiveTargetSettings = []string{"password", "secretaccesskey", "sessiontoken", "ssecustomerkey"}
+
 // Manager handles the backup operations
 type Manager struct {
 	config       *conf.BackupConfig
@@ -259,7 +336,8 @@ func (m *Manager) Start() error {
 	return nil
 }
 
-// RunBackup performs an immediate backup of all sources
+// RunBackup performs an immediate backup of all sources. The progress of the
+// backup is reported to the ProgressFunc set with WithProgress, if any.
 func (m *Manager) RunBackup(ctx context.Context) error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -288,12 +366,16 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 	var allTempDirs []string
 	var errs []error
 
+	progress := Progress{Stage: ProgressStarted, SourcesTotal: len(m.sources)}
+	reportProgress(ctx, &progress)
+
 	// Process each source
 	for sourceName, source := range m.sources {
 		select {
 		case <-ctx.Done():
 			// Clean up temp dirs before returning
 			m.cleanupTempDirectories(allTempDirs)
+			reportProgress(ctx, &Progress{Stage: ProgressFailed, SourcesDone: progress.SourcesDone, SourcesTotal: progress.SourcesTotal, Error: ctx.Err().Error()})
 			return errors.New(ctx.Err()).
 				Component("backup").
 				Category(errors.CategorySystem).
@@ -304,13 +386,17 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 		}
 		startSourceTime := time.Now()
 		m.logger.Info("Processing backup source", logger.String("source_name", sourceName))
+		reportProgress(ctx, &Progress{Stage: ProgressSourceStarted, Source: sourceName, SourcesDone: progress.SourcesDone, SourcesTotal: progress.SourcesTotal})
 		tempDirs, err := m.processBackupSource(ctx, sourceName, source, now, isDaily, isWeekly)
 		allTempDirs = append(allTempDirs, tempDirs...)
+		progress.SourcesDone++
 		if err != nil {
 			m.logger.Error("Failed to process backup source", logger.String("source_name", sourceName), logger.Error(err))
+			reportProgress(ctx, &Progress{Stage: ProgressSourceFailed, Source: sourceName, SourcesDone: progress.SourcesDone, SourcesTotal: progress.SourcesTotal, Error: err.Error()})
 			errs = append(errs, fmt.Errorf("source %s: %w", sourceName, err)) // Wrap error with source name
 			continue                                                          // Continue with the next source
 		}
+		reportProgress(ctx, &Progress{Stage: ProgressSourceCompleted, Source: sourceName, SourcesDone: progress.SourcesDone, SourcesTotal: progress.SourcesTotal})
 		m.logger.Info("Successfully processed backup source",
 			logger.String("source_name", sourceName),
 			logger.Int64("duration_ms", time.Since(startSourceTime).Milliseconds()),
@@ -328,11 +414,13 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 	if len(errs) > 0 {
 		combinedErr := combineErrors(errs)
 		m.logger.Error("Backup process completed with errors", logger.Int("error_count", len(errs)), logger.Error(combinedErr))
+		reportProgress(ctx, &Progress{Stage: ProgressFailed, SourcesDone: progress.SourcesDone, SourcesTotal: progress.SourcesTotal, Error: combinedErr.Error()})
 		// Optionally update overall state manager status here if needed
 		return combinedErr
 	}
 
 	m.logger.Info("Backup process completed successfully")
+	reportProgress(ctx, &Progress{Stage: ProgressCompleted, SourcesDone: progress.SourcesDone, SourcesTotal: progress.SourcesTotal})
 	// Optionally update overall state manager status here if needed
 	return nil
 }
@@ -341,9 +429,23 @@ func (m *Manager) RunBackup(ctx context.Context) error {
 func (m *Manager) processBackupSource(ctx context.Context, sourceName string, source Source, timestamp time.Time, isDaily, isWeekly bool) ([]string, error) {
 	var tempDirs []string // Track temp dirs created in this function
 
-	// 1. Perform the actual backup from the source
+	// 1. Perform the actual backup from the source, incremental sources continue
+	// from the state recorded by their last successful backup
 	m.logger.Debug("Starting source backup", logger.String("source_name", sourceName))
-	backupReader, err := source.Backup(ctx)
+	incremental, isIncremental := source.(IncrementalSource)
+	var backupReader io.ReadCloser
+	var nextState SourceState
+	var err error
+	if isIncremental {
+		backupReader, nextState, err = incremental.BackupSince(ctx, m.stateManager.GetSourceState(sourceName))
+	} else {
+		backupReader, err = source.Backup(ctx)
+	}
+	if errors.Is(err, ErrNoChanges) {
+		m.logger.Info("No new data to back up, skipping source", logger.String("source_name", sourceName))
+		reportProgress(ctx, &Progress{Stage: ProgressSourceSkipped, Source: sourceName})
+		return tempDirs, nil
+	}
 	if err != nil {
 		return tempDirs, fmt.Errorf("failed to initiate backup from source: %w", err)
 	}
@@ -369,17 +471,24 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 
 	// 3. Prepare metadata
 	metadata := &Metadata{
-		Version:    1, // Current metadata version
-		ID:         fmt.Sprintf("%s-%s", sourceName, timestamp.Format("20060102-150405")),
-		Timestamp:  timestamp,
-		Type:       sourceName, // Assuming source name is the type for now
-		Source:     sourceName,
-		IsDaily:    isDaily,
-		IsWeekly:   isWeekly, // Add weekly flag
-		AppVersion: m.appVersion,
-		Encrypted:  m.config.Encryption,
+		Version:     1, // Current metadata version
+		ID:          fmt.Sprintf("%s-%s", sourceName, timestamp.Format("20060102-150405")),
+		Timestamp:   timestamp,
+		Type:        sourceName, // Assuming source name is the type for now
+		Source:      sourceName,
+		IsDaily:     isDaily,
+		IsWeekly:    isWeekly, // Add weekly flag
+		AppVersion:  m.appVersion,
+		Encrypted:   m.config.Encryption,
+		Incremental: isIncremental,
 		// Size and checksum will be calculated later
 	}
+	if isIncremental {
+		if nextState.BaseID == "" {
+			nextState.BaseID = metadata.ID // This backup starts a new chain
+		}
+		metadata.BaseID = nextState.BaseID
+	}
 
 	// Hash the config (consider doing this once per RunBackup if config doesn't change)
 	configHash, err := m.hashConfig()
@@ -438,19 +547,30 @@ func (m *Manager) processBackupSource(ctx context.Context, sourceName string, so
 	metadata.Size = fileInfo.Size()
 	m.logger.Debug("Updated metadata with final size", logger.String("source_name", sourceName), logger.Int64("size", metadata.Size))
 
-	// Calculate checksum if needed (optional, can be time-consuming)
-	// checksum, err := calculateChecksum(finalArchivePath)
-	// if err == nil {
-	//     metadata.Checksum = checksum
-	// } else {
-	//     m.logger.Warn("Failed to calculate checksum", "path", finalArchivePath, "error", err)
-	// }
+	// Calculate checksum of the final file so restores can detect damaged archives
+	checksum, err := calculateChecksum(finalArchivePath)
+	if err != nil {
+		m.logger.Warn("Failed to calculate checksum", logger.String("path", finalArchivePath), logger.Error(err))
+	} else {
+		metadata.Checksum = checksum
+	}
+	reportProgress(ctx, &Progress{Stage: ProgressArchiveCreated, Source: sourceName, BackupID: metadata.ID, Size: metadata.Size})
 
 	// 8. Store the final archive in all registered targets
 	if err := m.storeBackupInTargets(ctx, finalArchivePath, metadata); err != nil {
 		return tempDirs, fmt.Errorf("failed to store backup in targets: %w", err)
 	}
 
+	// 9. Record the progress of incremental sources, a failed store above means
+	// the same data is picked up again by the next run
+	if isIncremental {
+		nextState.LastBackupID = metadata.ID
+		nextState.LastSuccessful = time.Now()
+		if err := m.stateManager.UpdateSourceState(sourceName, nextState); err != nil {
+			return tempDirs, fmt.Errorf("failed to record incremental backup state: %w", err)
+		}
+	}
+
 	m.logger.Debug("Finished processing source", logger.String("source_name", sourceName))
 	return tempDirs, nil // Return tempDirs for cleanup by the caller
 }
@@ -473,6 +593,31 @@ func (m *Manager) hashConfig() (string, error) {
 	return hex.EncodeToString(hash[:]), nil
 }
 
+// calculateChecksum returns the hex encoded SHA256 checksum of a file
+func calculateChecksum(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304 - path is an internal temp path from backup manager
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_file_for_checksum").
+			Context("path", path).
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "calculate_checksum").
+			Context("path", path).
+			Build()
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // addConfigToArchive adds the sanitized configuration file to the tar archive
 func (m *Manager) addConfigToArchive(tw *tar.Writer, metadata *Metadata) error {
 	m.logger.Debug("Adding sanitized config to archive", logger.String("backup_id", metadata.ID))
@@ -549,6 +694,7 @@ func (m *Manager) storeBackupInTargets(ctx context.Context, archivePath string,
 			if err := target.Store(storeCtx, archivePath, metadata); err != nil {
 				wrappedErr := fmt.Errorf("target %s: %w", targetName, err)
 				m.logger.Error("Failed to store backup in target", logger.String("backup_id", metadata.ID), logger.String("target_name", targetName), logger.Error(err))
+				reportProgress(ctx, &Progress{Stage: ProgressTargetFailed, Source: metadata.Source, Target: targetName, BackupID: metadata.ID, Error: err.Error()})
 				errChan <- wrappedErr
 				// Update state for this specific target failure
 				if m.stateManager != nil {
@@ -561,6 +707,7 @@ func (m *Manager) storeBackupInTargets(ctx context.Context, archivePath string,
 					logger.String("backup_id", metadata.ID),
 					logger.String("target_name", targetName),
 					logger.Int64("duration_ms", time.Since(startTargetTime).Milliseconds()))
+				reportProgress(ctx, &Progress{Stage: ProgressTargetStored, Source: metadata.Source, Target: targetName, BackupID: metadata.ID, Size: metadata.Size})
 				// Update state for this specific target success
 				if m.stateManager != nil {
 					if err := m.stateManager.UpdateTargetState(targetName, metadata, "success"); err != nil {
@@ -770,20 +917,65 @@ func (m *Manager) addMetadataToArchive(ctx context.Context, tw *tar.Writer, meta
 }
 
 // addBackupDataToArchive streams data from the source reader into the tar archive.
+// TAR headers must carry the entry size, so the stream is first spooled to a
+// temporary file and then copied into the archive.
 func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, reader io.Reader, metadata *Metadata) error {
 	start := time.Now()
 	// Determine the filename within the archive based on source type or name
 	// Example: Use source name with a common extension
 	backupFilename := fmt.Sprintf("backup.%s", strings.ToLower(metadata.Source)) // e.g., backup.sqlite
 
-	// We don't know the size beforehand for streaming backup.
-	// Write the data directly. TAR format supports this.
+	spoolFile, err := os.CreateTemp("", "birdnet-go-backup-data-*")
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_backup_data_spool").
+			Build()
+	}
+	defer func() {
+		if err := spoolFile.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			m.logger.Warn("Failed to close backup data spool file", logger.String("path", spoolFile.Name()), logger.Error(err))
+		}
+		if err := os.Remove(spoolFile.Name()); err != nil && !os.IsNotExist(err) {
+			m.logger.Warn("Failed to remove backup data spool file", logger.String("path", spoolFile.Name()), logger.Error(err))
+		}
+	}()
+
+	// Copy data from source reader to the spool file.
+	// source.Backup should handle context internally.
+	copiedBytes, err := io.Copy(spoolFile, reader)
+	if err != nil {
+		// Check for context cancellation specifically if possible
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategorySystem).
+				Context("operation", "stream_backup_data").
+				Context("error_type", "cancelled").
+				Build()
+		}
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stream_backup_data_to_spool").
+			Context("bytes_copied", copiedBytes).
+			Build()
+	}
+	if _, err := spoolFile.Seek(0, io.SeekStart); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "rewind_backup_data_spool").
+			Build()
+	}
+
 	// Create TAR header for the backup data
 	hdr := &tar.Header{
 		Name:    backupFilename,
+		Size:    copiedBytes,
 		Mode:    int64(PermArchiveFile), // Standard file permissions
 		ModTime: metadata.Timestamp,
-		// Size is unknown for streaming, tar writer handles this.
 	}
 
 	// Write header
@@ -795,25 +987,19 @@ func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, re
 			Build()
 	}
 
-	// Copy data from source reader to tar writer
-	// Wrap the reader with a context checker if possible/needed,
-	// although source.Backup should handle context internally.
-	copiedBytes, err := io.Copy(tw, reader)
-	if err != nil {
-		// Check for context cancellation specifically if possible
+	if err := copyWithContext(ctx, tw, spoolFile); err != nil {
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			return errors.New(err).
 				Component("backup").
 				Category(errors.CategorySystem).
-				Context("operation", "stream_backup_data").
+				Context("operation", "write_backup_data_to_tar").
 				Context("error_type", "cancelled").
 				Build()
 		}
 		return errors.New(err).
 			Component("backup").
 			Category(errors.CategoryFileIO).
-			Context("operation", "stream_backup_data_to_tar").
-			Context("bytes_copied", copiedBytes).
+			Context("operation", "write_backup_data_to_tar").
 			Build()
 	}
 
@@ -824,15 +1010,43 @@ func (m *Manager) addBackupDataToArchive(ctx context.Context, tw *tar.Writer, re
 	return nil
 }
 
+// copyWithContext copies src to dst in chunks, stopping early if ctx is done
+func copyWithContext(ctx context.Context, dst io.Writer, src io.Reader) error {
+	buf := make([]byte, 256*KB)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
 // encryptArchive encrypts the source file and writes it to the destination file.
 // Renamed from encryptAndWriteArchive for clarity.
 func (m *Manager) encryptArchive(ctx context.Context, sourcePath, destPath string) error {
 	start := time.Now()
 
-	// Read the entire source file (archive) into memory.
-	// Consider streaming encryption for very large files if memory becomes an issue.
-	// Read source file (internal temp archive path from backup manager)
-	plaintext, err := os.ReadFile(sourcePath) //nolint:gosec // G304 - sourcePath is an internal temp path from backup manager
+	m.logger.Debug("Encrypting archive", logger.String("source", sourcePath), logger.String("destination", destPath))
+
+	// Get encryption key
+	key, err := m.GetEncryptionKey() // Assumes GetEncryptionKey is implemented in encryption.go
+	if err != nil {
+		return fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	// Open source file (internal temp archive path from backup manager)
+	src, err := os.Open(sourcePath) //nolint:gosec // G304 - sourcePath is an internal temp path from backup manager
 	if err != nil {
 		return errors.New(err).
 			Component("backup").
@@ -841,24 +1055,24 @@ func (m *Manager) encryptArchive(ctx context.Context, sourcePath, destPath strin
 			Context("source_path", sourcePath).
 			Build()
 	}
+	defer func() { _ = src.Close() }()
 
-	m.logger.Debug("Encrypting archive", logger.String("source", sourcePath), logger.String("destination", destPath))
-
-	// Get encryption key
-	key, err := m.GetEncryptionKey() // Assumes GetEncryptionKey is implemented in encryption.go
+	dst, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, PermSecureFile) //nolint:gosec // G304 - destPath is an internal temp path from backup manager
 	if err != nil {
-		return fmt.Errorf("failed to get encryption key: %w", err)
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_encrypted_archive").
+			Context("dest_path", destPath).
+			Build()
 	}
 
-	// Encrypt data
-	ciphertext, err := encryptData(plaintext, key) // Assumes encryptData is implemented in encryption.go
-	if err != nil {
+	// Encrypt the archive in chunks, large clip backups are never held in memory
+	if err := encryptStream(ctx, dst, src, key); err != nil {
+		_ = dst.Close()
 		return fmt.Errorf("failed during data encryption: %w", err)
 	}
-
-	// Write encrypted data to destination file
-	err = os.WriteFile(destPath, ciphertext, PermSecureFile) // Secure permissions
-	if err != nil {
+	if err := dst.Close(); err != nil {
 		return errors.New(err).
 			Component("backup").
 			Category(errors.CategoryFileIO).
@@ -1030,6 +1244,9 @@ func (m *Manager) enforceRetentionPolicy(ctx context.Context, target Target, bac
 	}
 
 	sourceType := backups[0].Source // Assume all backups in the list are of the same source type
+	if backups[0].Incremental {
+		return m.pruneSupersededChains(ctx, target, backups)
+	}
 	m.logger.Info("Enforcing retention policy",
 		logger.String("target_name", target.Name()),
 		logger.String("source_type", sourceType),
@@ -1045,8 +1262,6 @@ func (m *Manager) enforceRetentionPolicy(ctx context.Context, target Target, bac
 	}
 
 	now := time.Now()
-	deleteCount := 0
-	var deleteErrors []error
 	backupsToDelete := make(BackupSet) // Use BackupSet to track unique backups for deletion
 
 	// Iterate through backups (sorted newest first) to determine which to delete
@@ -1099,6 +1314,45 @@ func (m *Manager) enforceRetentionPolicy(ctx context.Context, target Target, bac
 
 	}
 
+	return m.deleteBackupSet(ctx, target, sourceType, backupsToDelete)
+}
+
+// pruneSupersededChains deletes incremental backups that belong to an older chain
+// once the current chain of their source has caught up. Every backup of a chain
+// holds data no other backup of it has, so the retention rules do not apply to
+// them, but the current chain holds everything the older ones do that is still
+// in the source. Nothing is deleted while the current chain is catching up.
+func (m *Manager) pruneSupersededChains(ctx context.Context, target Target, backups []BackupInfo) error {
+	sourceType := backups[0].Source
+	state := m.stateManager.GetSourceState(sourceType)
+	if state.BaseID == "" || !state.CaughtUp {
+		m.logger.Debug("Keeping incremental backups until the current chain has caught up",
+			logger.String("target_name", target.Name()),
+			logger.String("source_type", sourceType),
+			logger.String("base_id", state.BaseID))
+		return nil
+	}
+
+	backupsToDelete := make(BackupSet)
+	for i := range backups {
+		// Backups made before chains existed have no base ID and are superseded as well
+		if backups[i].BaseID != state.BaseID {
+			m.logger.Debug("Marking backup for deletion (superseded chain)",
+				logger.String("backup_id", backups[i].ID),
+				logger.String("base_id", backups[i].BaseID),
+				logger.String("current_base_id", state.BaseID))
+			backupsToDelete.Add(&backups[i])
+		}
+	}
+
+	return m.deleteBackupSet(ctx, target, sourceType, backupsToDelete)
+}
+
+// deleteBackupSet deletes the backups marked by the retention policy from a target
+func (m *Manager) deleteBackupSet(ctx context.Context, target Target, sourceType string, backupsToDelete BackupSet) error {
+	deleteCount := 0
+	var deleteErrors []error
+
 	// Perform deletions for unique IDs marked
 	for id := range backupsToDelete {
 		backup := backupsToDelete[id]
@@ -1319,6 +1573,23 @@ func (m *Manager) DeleteBackup(ctx context.Context, id string) error {
 	return m.deleteBackupWithTimeout(ctx, &backupToDelete, target)
 }
 
+// RetrieveBackup downloads the archive of a backup into destDir and returns its path.
+// When target is empty the backup is fetched from the first target that holds it.
+// The archive is returned as stored, encrypted archives are not decrypted.
+func (m *Manager) RetrieveBackup(ctx context.Context, id, target, destDir string) (string, *BackupInfo, error) {
+	if id == "" {
+		return "", nil, NewError(ErrValidation, "backup ID cannot be empty", nil)
+	}
+	if err := validateBackupID(id); err != nil {
+		return "", nil, err
+	}
+
+	retrieveCtx, cancel := context.WithTimeout(ctx, m.getOperationTimeout())
+	defer cancel()
+
+	return m.fetchArchive(retrieveCtx, &RestoreOptions{BackupID: id, Target: target}, destDir)
+}
+
 // getBackupTimeout returns the configured timeout for the entire backup process.
 func (m *Manager) getBackupTimeout() time.Duration {
 	if m.config.OperationTimeouts.Backup > 0 {
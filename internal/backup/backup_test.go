@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestSanitizeConfig tests that secrets are removed from the config stored in backups
+// while the original config is left untouched
+func TestSanitizeConfig(t *testing.T) {
+	t.Parallel()
+	config := &conf.Settings{}
+	config.Security.BasicAuth.Password = "basic-password"
+	config.Security.BasicAuth.ClientSecret = "basic-client-secret"
+	config.Security.GoogleAuth.ClientSecret = "google-secret"
+	config.Security.GithubAuth.ClientSecret = "github-secret"
+	config.Security.SessionSecret = "session-secret"
+	config.Output.MySQL.Password = "mysql-password"
+	config.Realtime.MQTT.Username = "birdnet"
+	config.Realtime.MQTT.Password = "mqtt-password"
+	config.Realtime.MQTT.Commands.Token = "mqtt-command-token"
+	config.Realtime.Weather.OpenWeather.APIKey = "openweather-key"
+	config.Realtime.Weather.LocalStation.Token = "station-push-token"
+	config.Backup.Targets = []conf.BackupTarget{{
+		Type: "s3",
+		Settings: map[string]any{
+			"bucket":          "birdnet",
+			"secretaccesskey": "s3-secret",
+			"sessiontoken":    "s3-session",
+		},
+	}}
+
+	sanitized := sanitizeConfig(config)
+
+	assert.Empty(t, sanitized.Security.BasicAuth.Password)
+	assert.Empty(t, sanitized.Security.BasicAuth.ClientSecret)
+	assert.Empty(t, sanitized.Security.GoogleAuth.ClientSecret)
+	assert.Empty(t, sanitized.Security.GithubAuth.ClientSecret)
+	assert.Empty(t, sanitized.Security.SessionSecret)
+	assert.Empty(t, sanitized.Output.MySQL.Password)
+	assert.Empty(t, sanitized.Realtime.MQTT.Password)
+	assert.Empty(t, sanitized.Realtime.MQTT.Commands.Token, "the MQTT command token authorizes remote control")
+	assert.Empty(t, sanitized.Realtime.Weather.OpenWeather.APIKey)
+	assert.Empty(t, sanitized.Realtime.Weather.LocalStation.Token, "the station token authorizes weather pushes")
+	assert.Equal(t, map[string]any{"bucket": "birdnet"}, sanitized.Backup.Targets[0].Settings)
+
+	// Settings that are not secret are kept
+	assert.Equal(t, "birdnet", sanitized.Realtime.MQTT.Username)
+
+	// The running config keeps its secrets
+	assert.Equal(t, "mqtt-command-token", config.Realtime.MQTT.Commands.Token)
+	assert.Equal(t, "station-push-token", config.Realtime.Weather.LocalStation.Token)
+	assert.Equal(t, "s3-secret", config.Backup.Targets[0].Settings["secretaccesskey"])
+}
+
+// TestRestoreSensitiveFields tests that credentials removed from an archived config
+// are taken from the running config, without overriding values set in the archive
+func TestRestoreSensitiveFields(t *testing.T) {
+	t.Parallel()
+	running := &conf.Settings{}
+	running.Security.SessionSecret = "session-secret"
+	running.Realtime.MQTT.Password = "mqtt-password"
+	running.Realtime.Weather.LocalStation.Token = "station-push-token"
+	running.Backup.Targets = []conf.BackupTarget{
+		{Type: "s3", Settings: map[string]any{"bucket": "birdnet", "secretaccesskey": "s3-secret"}},
+		{Type: "sftp", Settings: map[string]any{"password": "sftp-password"}},
+	}
+
+	restored := sanitizeConfig(running)
+	restored.Realtime.MQTT.Password = "archived-password"
+	restored.Backup.Targets[1].Type = "ftp"
+	restoreSensitiveFields(restored, running)
+
+	assert.Equal(t, "session-secret", restored.Security.SessionSecret)
+	assert.Equal(t, "station-push-token", restored.Realtime.Weather.LocalStation.Token)
+	assert.Equal(t, "archived-password", restored.Realtime.MQTT.Password, "values in the archive are kept")
+	assert.Equal(t, map[string]any{"bucket": "birdnet", "secretaccesskey": "s3-secret"}, restored.Backup.Targets[0].Settings)
+	assert.NotContains(t, restored.Backup.Targets[1].Settings, "password", "credentials of a different target type are not copied")
+}
@@ -0,0 +1,238 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Clip backup constants
+const (
+	// ClipsManifestName is the first entry of the TAR stream written by the clips source
+	ClipsManifestName = "manifest.json"
+
+	// ClipsManifestVersion is the current version of the clips manifest format
+	ClipsManifestVersion = 1
+
+	// ClipKindAudio marks an exported audio clip in the clips manifest
+	ClipKindAudio = "clip"
+
+	// ClipKindSpectrogram marks a spectrogram image in the clips manifest
+	ClipKindSpectrogram = "spectrogram"
+
+	// maxClipsManifestSize limits how much of the clips manifest is read into memory
+	maxClipsManifestSize = 256 * MB
+)
+
+// ClipsManifest describes the files in an incremental clip backup
+type ClipsManifest struct {
+	Version    int                 `json:"version"`             // Version of the manifest format
+	ExportPath string              `json:"export_path"`         // Audio export path the files were read from
+	Since      time.Time           `json:"since,omitzero"`      // Files modified after this time are included, zero for a full backup
+	Until      time.Time           `json:"until"`               // Files modified up to this time are included
+	Files      []ClipsManifestFile `json:"files"`               // Files in the order they appear in the archive
+	TotalSize  int64               `json:"total_size"`          // Combined size of all files
+	NoteCount  int                 `json:"note_count"`          // Number of detections referenced by the files
+	Truncated  bool                `json:"truncated,omitempty"` // Whether files were left for the next run to stay below the size limit
+}
+
+// ClipsManifestFile maps a backed up file to the detection that references it
+type ClipsManifestFile struct {
+	Path           string    `json:"path"`                      // Slash separated path relative to the export path
+	Kind           string    `json:"kind"`                      // ClipKindAudio or ClipKindSpectrogram
+	Size           int64     `json:"size"`                      // File size in bytes
+	ModTime        time.Time `json:"mod_time"`                  // Modification time of the file
+	NoteID         uint      `json:"note_id,omitempty"`         // Detection referencing the clip, zero if none was found
+	ScientificName string    `json:"scientific_name,omitempty"` // Species of the detection
+	CommonName     string    `json:"common_name,omitempty"`     // Common name of the species
+	Date           string    `json:"date,omitempty"`            // Date of the detection
+	Time           string    `json:"time,omitempty"`            // Time of the detection
+	Confidence     float64   `json:"confidence,omitempty"`      // Confidence of the detection
+	Verified       string    `json:"verified,omitempty"`        // Review status at backup time ("correct", "false_positive")
+	Locked         bool      `json:"locked,omitempty"`          // Whether the detection was locked at backup time
+}
+
+// isClipsArchive reports whether the backup data at path is a TAR stream
+// written by the clips source
+func isClipsArchive(dataPath string) (bool, error) {
+	isTar, err := isTarArchive(dataPath)
+	if err != nil || !isTar {
+		return false, err
+	}
+
+	f, err := os.Open(dataPath) //nolint:gosec // G304 - dataPath is inside the restore temp directory
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_backup_data").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	hdr, err := tar.NewReader(f).Next()
+	if err != nil {
+		return false, nil
+	}
+	return hdr.Name == ClipsManifestName, nil
+}
+
+// clipsRestoreDir returns where the clips of a backup should be restored
+func (m *Manager) clipsRestoreDir(outputDir string) (string, error) {
+	if outputDir != "" {
+		return outputDir, nil
+	}
+
+	exportPath := m.fullConfig.Realtime.Audio.Export.Path
+	if exportPath == "" {
+		return "", NewError(ErrConfig, "audio export path is not configured, use an output directory to restore clips", nil)
+	}
+	absPath, err := filepath.Abs(exportPath)
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "resolve_clips_path").
+			Context("path", exportPath).
+			Build()
+	}
+	return absPath, nil
+}
+
+// restoreClips extracts the clips of an incremental clip backup. Existing files
+// are kept, so restoring the backups of a chain in any order is safe.
+func (m *Manager) restoreClips(ctx context.Context, contents *archiveContents, opts *RestoreOptions, result *RestoreResult) error {
+	destDir, err := m.clipsRestoreDir(opts.OutputDir)
+	if err != nil {
+		return err
+	}
+	result.ClipsPath = destDir
+
+	f, err := os.Open(contents.dataPath) //nolint:gosec // G304 - dataPath is inside the restore temp directory
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_backup_data").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	tr := tar.NewReader(f)
+	manifest, manifestData, err := readClipsManifest(tr)
+	if err != nil {
+		return err
+	}
+	modTimes := make(map[string]time.Time, len(manifest.Files))
+	for i := range manifest.Files {
+		modTimes[manifest.Files[i].Path] = manifest.Files[i].ModTime
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return NewError(ErrCorruption, "failed to read clips from backup", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Clip paths are relative to the export path and must stay inside it
+		if !filepath.IsLocal(filepath.FromSlash(hdr.Name)) || path.Clean(hdr.Name) != hdr.Name {
+			return NewError(ErrSecurity, fmt.Sprintf("unexpected clip path in backup archive: %s", hdr.Name), nil)
+		}
+
+		destPath := filepath.Join(destDir, filepath.FromSlash(hdr.Name))
+		if _, err := os.Lstat(destPath); err == nil {
+			result.ClipsSkipped++
+			continue
+		}
+
+		if opts.DryRun {
+			result.ClipsRestored++
+			continue
+		}
+		if err := writeFileAtomic(destPath, &contextReader{ctx: ctx, r: tr}, PermBackupFile); err != nil {
+			return err
+		}
+		// Keep the original modification time so the clip is not backed up again
+		if modTime, ok := modTimes[hdr.Name]; ok {
+			if err := os.Chtimes(destPath, modTime, modTime); err != nil {
+				m.logger.Debug("Failed to restore clip modification time", logger.String("path", destPath), logger.Error(err))
+			}
+		}
+		result.ClipsRestored++
+	}
+
+	if !opts.DryRun {
+		manifestPath := filepath.Join(destDir, contents.metadata.ID+".manifest.json")
+		if err := writeFileAtomic(manifestPath, bytes.NewReader(manifestData), PermBackupFile); err != nil {
+			return err
+		}
+		result.ClipsManifest = manifestPath
+	}
+
+	if manifest.Since.IsZero() {
+		result.Warnings = append(result.Warnings, "this is a full clip backup, later clip backups contain the clips added since")
+	} else {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("clip backups are incremental, this backup only contains clips added between %s and %s",
+				manifest.Since.Local().Format(time.DateTime), manifest.Until.Local().Format(time.DateTime)))
+	}
+
+	m.logger.Info("Clips restored",
+		logger.String("clips_path", destDir),
+		logger.Int("restored", result.ClipsRestored),
+		logger.Int("skipped", result.ClipsSkipped),
+		logger.Bool("dry_run", opts.DryRun))
+	return nil
+}
+
+// readClipsManifest reads the manifest entry at the start of a clips TAR stream
+func readClipsManifest(tr *tar.Reader) (*ClipsManifest, []byte, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, nil, NewError(ErrCorruption, "failed to read clips manifest", err)
+	}
+	if hdr.Name != ClipsManifestName {
+		return nil, nil, NewError(ErrCorruption, fmt.Sprintf("clips backup starts with '%s' instead of the manifest", hdr.Name), nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(tr, maxClipsManifestSize))
+	if err != nil {
+		return nil, nil, NewError(ErrCorruption, "failed to read clips manifest", err)
+	}
+	var manifest ClipsManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, NewError(ErrCorruption, "invalid clips manifest", err)
+	}
+	if manifest.Version > ClipsManifestVersion {
+		return nil, nil, NewError(ErrValidation, fmt.Sprintf("clips manifest version %d is newer than supported version %d, upgrade BirdNET-Go first", manifest.Version, ClipsManifestVersion), nil)
+	}
+	return &manifest, data, nil
+}
+
+// contextReader stops reading once its context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
@@ -0,0 +1,227 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// clipsTarSource is an incremental source streaming a prepared clips TAR stream
+type clipsTarSource struct {
+	manifest ClipsManifest
+	files    map[string]string // Path in the archive to content
+}
+
+func (s *clipsTarSource) Name() string    { return "clips" }
+func (s *clipsTarSource) Validate() error { return nil }
+
+func (s *clipsTarSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	reader, _, err := s.BackupSince(ctx, SourceState{})
+	return reader, err
+}
+
+func (s *clipsTarSource) BackupSince(_ context.Context, since SourceState) (io.ReadCloser, SourceState, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	manifestData, err := json.Marshal(s.manifest)
+	if err != nil {
+		return nil, since, err
+	}
+	entries := []struct{ name, content string }{{ClipsManifestName, string(manifestData)}}
+	for i := range s.manifest.Files {
+		entries = append(entries, struct{ name, content string }{s.manifest.Files[i].Path, s.files[s.manifest.Files[i].Path]})
+	}
+	for _, entry := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0o600, Size: int64(len(entry.content))}); err != nil {
+			return nil, since, err
+		}
+		if _, err := tw.Write([]byte(entry.content)); err != nil {
+			return nil, since, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, since, err
+	}
+	return io.NopCloser(&buf), SourceState{Watermark: s.manifest.Until, TotalFiles: len(s.manifest.Files)}, nil
+}
+
+// newTestClipsSource returns a clips source holding the given files, modified at modTime
+func newTestClipsSource(since, modTime time.Time, files map[string]string) *clipsTarSource {
+	source := &clipsTarSource{
+		manifest: ClipsManifest{Version: ClipsManifestVersion, Since: since, Until: modTime.Add(time.Minute)},
+		files:    files,
+	}
+	for path, content := range files {
+		source.manifest.Files = append(source.manifest.Files, ClipsManifestFile{
+			Path: path, Kind: ClipKindAudio, Size: int64(len(content)), ModTime: modTime,
+		})
+	}
+	return source
+}
+
+// runTestClipsBackup backs up the clips of source and returns the stored backup
+func runTestClipsBackup(t *testing.T, m *Manager, target *dirTarget, source *clipsTarSource) Metadata {
+	t.Helper()
+	require.NoError(t, m.RegisterSource(source))
+	require.NoError(t, m.RunBackup(t.Context()))
+
+	backups, err := target.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	require.True(t, backups[0].Incremental)
+	return backups[0].Metadata
+}
+
+// TestRestoreClipsIntoExportPath tests restoring a clip backup into the configured
+// export path without replacing clips that are already there
+func TestRestoreClipsIntoExportPath(t *testing.T) {
+	m, target, settings := newTestManager(t, false)
+	settings.Realtime.Audio.Export.Path = t.TempDir()
+	modTime := time.Date(2025, 5, 1, 4, 0, 0, 0, time.UTC)
+	since := modTime.Add(-24 * time.Hour)
+	metadata := runTestClipsBackup(t, m, target, newTestClipsSource(since, modTime, map[string]string{
+		"2025/05/eurbla_95p.wav": "blackbird",
+		"2025/05/comcha_80p.wav": "chaffinch",
+	}))
+
+	// The state of the source is recorded with the stored backup
+	state := m.stateManager.GetSourceState("clips")
+	assert.Equal(t, metadata.ID, state.LastBackupID)
+	assert.Equal(t, 2, state.TotalFiles)
+
+	existing := filepath.Join(settings.Realtime.Audio.Export.Path, "2025", "05", "comcha_80p.wav")
+	require.NoError(t, os.MkdirAll(filepath.Dir(existing), 0o750))
+	require.NoError(t, os.WriteFile(existing, []byte("kept"), 0o600))
+
+	// A dry run counts the clips without writing them
+	result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, settings.Realtime.Audio.Export.Path, result.ClipsPath)
+	assert.Equal(t, 1, result.ClipsRestored)
+	assert.Equal(t, 1, result.ClipsSkipped)
+	assert.Empty(t, result.ClipsManifest)
+	assert.NoFileExists(t, filepath.Join(settings.Realtime.Audio.Export.Path, "2025", "05", "eurbla_95p.wav"))
+
+	result, err = m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ClipsRestored)
+	assert.Equal(t, 1, result.ClipsSkipped)
+	assert.Empty(t, result.ConfigPath, "the configuration is not restored from incremental backups")
+	assert.Contains(t, result.Warnings, fmt.Sprintf("clip backups are incremental, this backup only contains clips added between %s and %s",
+		since.Local().Format(time.DateTime), modTime.Add(time.Minute).Local().Format(time.DateTime)))
+
+	restored := filepath.Join(settings.Realtime.Audio.Export.Path, "2025", "05", "eurbla_95p.wav")
+	data, err := os.ReadFile(restored)
+	require.NoError(t, err)
+	assert.Equal(t, "blackbird", string(data))
+	info, err := os.Stat(restored)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(modTime), "restored clips keep their modification time")
+
+	data, err = os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "kept", string(data))
+
+	assert.Equal(t, filepath.Join(settings.Realtime.Audio.Export.Path, metadata.ID+".manifest.json"), result.ClipsManifest)
+	assert.FileExists(t, result.ClipsManifest)
+}
+
+// TestRestoreClipsWithoutExportPath tests that clips need an export path or an output directory
+func TestRestoreClipsWithoutExportPath(t *testing.T) {
+	m, target, _ := newTestManager(t, false)
+	modTime := time.Date(2025, 5, 1, 4, 0, 0, 0, time.UTC)
+	metadata := runTestClipsBackup(t, m, target, newTestClipsSource(time.Time{}, modTime, map[string]string{"a.wav": "a"}))
+
+	_, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, DryRun: true})
+	require.Error(t, err)
+	assert.True(t, IsErrorCode(err, ErrConfig), "got %v", err)
+
+	result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ClipsRestored)
+	assert.Contains(t, result.Warnings, "this is a full clip backup, later clip backups contain the clips added since")
+}
+
+// TestRestoreClipsRejectsUnsafePaths tests that clip paths cannot leave the restore directory
+func TestRestoreClipsRejectsUnsafePaths(t *testing.T) {
+	for _, path := range []string{"../outside.wav", "/etc/outside.wav", "2025/../../outside.wav", "2025//clip.wav"} {
+		t.Run(path, func(t *testing.T) {
+			m, target, _ := newTestManager(t, false)
+			modTime := time.Date(2025, 5, 1, 4, 0, 0, 0, time.UTC)
+			metadata := runTestClipsBackup(t, m, target, newTestClipsSource(time.Time{}, modTime, map[string]string{path: "x"}))
+
+			outputDir := filepath.Join(t.TempDir(), "clips", "restore")
+			_, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: outputDir})
+			require.Error(t, err)
+			assert.True(t, IsErrorCode(err, ErrSecurity), "got %v", err)
+			assert.NoFileExists(t, filepath.Join(filepath.Dir(outputDir), "outside.wav"))
+		})
+	}
+}
+
+// TestRestoreClipsNewerManifest tests that manifests of a newer format are not restored
+func TestRestoreClipsNewerManifest(t *testing.T) {
+	m, target, _ := newTestManager(t, false)
+	source := newTestClipsSource(time.Time{}, time.Date(2025, 5, 1, 4, 0, 0, 0, time.UTC), map[string]string{"a.wav": "a"})
+	source.manifest.Version = ClipsManifestVersion + 1
+	metadata := runTestClipsBackup(t, m, target, source)
+
+	_, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: t.TempDir()})
+	require.Error(t, err)
+	assert.True(t, IsErrorCode(err, ErrValidation), "got %v", err)
+}
+
+// TestRetentionPrunesSupersededClipChains tests that incremental backups are kept
+// until a newer chain has caught up, and that older chains are deleted afterwards
+// regardless of the retention rules of database backups
+func TestRetentionPrunesSupersededClipChains(t *testing.T) {
+	m, target, _ := newTestManager(t, false)
+	retention := conf.BackupRetention{MaxAge: "1d", MaxBackups: 1}
+
+	// Newest first: the current chain, an older chain and a backup made before chains
+	baseIDs := []string{"clips-20250105-030000", "clips-20250105-030000", "clips-20250103-030000", "clips-20250103-030000", ""}
+	var backups []BackupInfo
+	for i, baseID := range baseIDs {
+		metadata := Metadata{
+			ID:          fmt.Sprintf("clips-2025010%d-030000", 6-i),
+			Timestamp:   time.Date(2025, 1, 6-i, 3, 0, 0, 0, time.UTC),
+			Source:      "clips",
+			Incremental: true,
+			BaseID:      baseID,
+		}
+		archive := filepath.Join(t.TempDir(), "archive")
+		require.NoError(t, os.WriteFile(archive, []byte("clips"), 0o600))
+		require.NoError(t, target.Store(t.Context(), archive, &metadata))
+		backups = append(backups, BackupInfo{Metadata: metadata, Target: target.Name()})
+	}
+
+	storedIDs := func() []string {
+		stored, err := target.List(t.Context())
+		require.NoError(t, err)
+		var ids []string
+		for i := range stored {
+			ids = append(ids, stored[i].ID)
+		}
+		return ids
+	}
+
+	// The new chain is still catching up, every backup is kept
+	require.NoError(t, m.stateManager.UpdateSourceState("clips", SourceState{BaseID: "clips-20250105-030000"}))
+	require.NoError(t, m.enforceRetentionPolicy(t.Context(), target, backups, retention))
+	assert.Len(t, storedIDs(), 5)
+
+	require.NoError(t, m.stateManager.UpdateSourceState("clips", SourceState{BaseID: "clips-20250105-030000", CaughtUp: true}))
+	require.NoError(t, m.enforceRetentionPolicy(t.Context(), target, backups, retention))
+	assert.ElementsMatch(t, []string{"clips-20250106-030000", "clips-20250105-030000"}, storedIDs())
+}
@@ -42,6 +42,15 @@ const (
 	MetadataVersion = 1
 )
 
+// MySQL dump constants
+const (
+	// MySQLDumpHeader is the first line of dumps written by the MySQL backup source
+	MySQLDumpHeader = "-- BirdNET-Go MySQL dump"
+
+	// MySQLDumpFooter is the last line of a complete MySQL dump
+	MySQLDumpFooter = "-- Dump completed"
+)
+
 // Byte size constants for readable calculations
 const (
 	KB = 1024
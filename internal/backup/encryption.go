@@ -1,10 +1,16 @@
 package backup
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -103,6 +109,36 @@ func (m *Manager) getEncryptionKey() ([]byte, error) {
 		return key, nil
 	}
 
+	return decodeEncryptionKey(keyBytes)
+}
+
+// readEncryptionKey reads the existing encryption key without generating a new one.
+// Unlike getEncryptionKey it does not require encryption to be enabled, so archives
+// can be decrypted on a fresh install once the key file has been imported.
+func (m *Manager) readEncryptionKey() ([]byte, error) {
+	keyPath, err := m.getEncryptionKeyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, err := os.ReadFile(keyPath) //nolint:gosec // G304 - keyPath is an internal config path from backup manager
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewError(ErrEncryption, fmt.Sprintf("backup is encrypted but no encryption key found at %s, import the key first", keyPath), err)
+		}
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encryption_key").
+			Context("key_path", keyPath).
+			Build()
+	}
+
+	return decodeEncryptionKey(keyBytes)
+}
+
+// decodeEncryptionKey decodes a hex encoded key file and validates its length
+func decodeEncryptionKey(keyBytes []byte) ([]byte, error) {
 	// Decode existing key from hex
 	keyStr := strings.TrimSpace(string(keyBytes))
 	key, err := hex.DecodeString(keyStr)
@@ -204,6 +240,178 @@ func decryptData(encryptedData, key []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
+// Archives are encrypted as a stream of chunks, each sealed with AES-256-GCM, so
+// they are never held in memory. The stream starts with encryptionStreamMagic and
+// a random nonce prefix. The nonce of every chunk is the prefix, the chunk number
+// and a flag marking the last chunk, so reordered, dropped or truncated chunks
+// fail to decrypt. Archives written before the stream format are a single sealed
+// block and are told apart by the missing magic.
+const (
+	encryptionStreamMagic     = "BNGOENC2"
+	encryptionChunkSize       = 64 * 1024
+	encryptionNoncePrefixSize = 7
+)
+
+// newGCM creates the AES-256-GCM cipher for a key
+func newGCM(key []byte, operation string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", "create_cipher_for_"+operation).
+			Build()
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", "create_gcm_for_"+operation).
+			Build()
+	}
+	return gcm, nil
+}
+
+// chunkNonce returns the nonce of a chunk of the encryption stream
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, encryptionNoncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// readChunk reads the next chunk of size bytes from r and reports whether it is
+// the last one. A short chunk is always the last one.
+func readChunk(r *bufio.Reader, buf []byte) (n int, last bool, err error) {
+	n, err = io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, true, nil
+	}
+	if err != nil {
+		return n, false, err
+	}
+	if _, err := r.Peek(1); errors.Is(err, io.EOF) {
+		return n, true, nil
+	} else if err != nil {
+		return n, false, err
+	}
+	return n, false, nil
+}
+
+// isEncryptionStream reports whether an encrypted archive uses the stream format
+func isEncryptionStream(path string) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec // G304 - path is an archive selected for restore
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_encrypted_archive").
+			Context("path", path).
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	magic := make([]byte, len(encryptionStreamMagic))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return false, nil // Too short to be a stream
+	}
+	return bytes.Equal(magic, []byte(encryptionStreamMagic)), nil
+}
+
+// encryptStream encrypts src into dst with AES-256-GCM, chunk by chunk
+func encryptStream(ctx context.Context, dst io.Writer, src io.Reader, key []byte) error {
+	gcm, err := newGCM(key, "encryption")
+	if err != nil {
+		return err
+	}
+
+	prefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", "generate_nonce").
+			Build()
+	}
+	if _, err := io.WriteString(dst, encryptionStreamMagic); err != nil {
+		return err
+	}
+	if _, err := dst.Write(prefix); err != nil {
+		return err
+	}
+
+	r := bufio.NewReaderSize(src, encryptionChunkSize)
+	plaintext := make([]byte, encryptionChunkSize)
+	ciphertext := make([]byte, 0, encryptionChunkSize+gcm.Overhead())
+	for counter := uint32(0); ; counter++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, last, err := readChunk(r, plaintext)
+		if err != nil {
+			return err
+		}
+		ciphertext = gcm.Seal(ciphertext[:0], chunkNonce(prefix, counter, last), plaintext[:n], nil)
+		if _, err := dst.Write(ciphertext); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// decryptStream decrypts an archive written by encryptStream from src into dst
+func decryptStream(ctx context.Context, dst io.Writer, src io.Reader, key []byte) error {
+	gcm, err := newGCM(key, "decryption")
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, len(encryptionStreamMagic)+encryptionNoncePrefixSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return errors.Newf("encrypted archive too short: %v", err).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_encrypted_data_size").
+			Build()
+	}
+	prefix := header[len(encryptionStreamMagic):]
+
+	r := bufio.NewReaderSize(src, encryptionChunkSize+gcm.Overhead())
+	ciphertext := make([]byte, encryptionChunkSize+gcm.Overhead())
+	plaintext := make([]byte, 0, encryptionChunkSize)
+	for counter := uint32(0); ; counter++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, last, err := readChunk(r, ciphertext)
+		if err != nil {
+			return err
+		}
+		plaintext, err = gcm.Open(plaintext[:0], chunkNonce(prefix, counter, last), ciphertext[:n], nil)
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategorySystem).
+				Context("operation", "decrypt_data").
+				Context("chunk", counter).
+				Build()
+		}
+		if _, err := dst.Write(plaintext); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
 // GenerateEncryptionKey generates a new encryption key and saves it to the default location
 func (m *Manager) GenerateEncryptionKey() (string, error) {
 	m.logger.Info("Generating new encryption key...")
@@ -295,7 +503,8 @@ func (m *Manager) GetEncryptionKey() ([]byte, error) {
 	return m.getEncryptionKey()
 }
 
-// DecryptData decrypts the provided data using the configured encryption key
+// DecryptData decrypts the provided data using the configured encryption key. Both
+// the stream format of current archives and the single block of older ones are read.
 func (m *Manager) DecryptData(encryptedData []byte) ([]byte, error) {
 	if !m.config.Encryption {
 		return nil, errors.Newf("encryption is not enabled").
@@ -311,7 +520,14 @@ func (m *Manager) DecryptData(encryptedData []byte) ([]byte, error) {
 	}
 
 	m.logger.Debug("Retrieved encryption key")
-	return decryptData(encryptedData, key)
+	if !bytes.HasPrefix(encryptedData, []byte(encryptionStreamMagic)) {
+		return decryptData(encryptedData, key)
+	}
+	var plaintext bytes.Buffer
+	if err := decryptStream(context.Background(), &plaintext, bytes.NewReader(encryptedData), key); err != nil {
+		return nil, err
+	}
+	return plaintext.Bytes(), nil
 }
 
 // GetEncryptionKeyPath returns the path to the encryption key file
@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEncryptionKey returns a random AES-256 key
+func testEncryptionKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+// TestEncryptStreamRoundTrip tests encrypting and decrypting archives around the chunk size
+func TestEncryptStreamRoundTrip(t *testing.T) {
+	t.Parallel()
+	key := testEncryptionKey(t)
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3 * encryptionChunkSize} {
+		t.Run(fmt.Sprintf("size=%d", size), func(t *testing.T) {
+			t.Parallel()
+			plaintext := make([]byte, size)
+			_, err := rand.Read(plaintext)
+			require.NoError(t, err)
+
+			var encrypted bytes.Buffer
+			require.NoError(t, encryptStream(t.Context(), &encrypted, bytes.NewReader(plaintext), key))
+			assert.True(t, bytes.HasPrefix(encrypted.Bytes(), []byte(encryptionStreamMagic)))
+
+			var decrypted bytes.Buffer
+			require.NoError(t, decryptStream(t.Context(), &decrypted, bytes.NewReader(encrypted.Bytes()), key))
+			assert.True(t, bytes.Equal(plaintext, decrypted.Bytes()), "decrypted data differs")
+		})
+	}
+}
+
+// TestDecryptStreamRejectsTampering tests that modified, truncated and extended
+// streams and the wrong key fail to decrypt
+func TestDecryptStreamRejectsTampering(t *testing.T) {
+	t.Parallel()
+	key := testEncryptionKey(t)
+	plaintext := bytes.Repeat([]byte("x"), 2*encryptionChunkSize)
+
+	var buf bytes.Buffer
+	require.NoError(t, encryptStream(t.Context(), &buf, bytes.NewReader(plaintext), key))
+	encrypted := buf.Bytes()
+	headerSize := len(encryptionStreamMagic) + encryptionNoncePrefixSize
+	firstChunkEnd := headerSize + encryptionChunkSize + 16
+
+	modified := bytes.Clone(encrypted)
+	modified[headerSize+10] ^= 1
+
+	tests := map[string]struct {
+		data []byte
+		key  []byte
+	}{
+		"modified chunk":            {modified, key},
+		"truncated at chunk border": {encrypted[:firstChunkEnd], key},
+		"truncated within chunk":    {encrypted[:firstChunkEnd+100], key},
+		"appended data":             {append(bytes.Clone(encrypted), 0), key},
+		"wrong key":                 {encrypted, testEncryptionKey(t)},
+		"missing header":            {encrypted[:headerSize-1], key},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := decryptStream(t.Context(), &bytes.Buffer{}, bytes.NewReader(tt.data), tt.key)
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestDecryptArchiveBlockFormat tests that archives encrypted as a single block
+// before the stream format are still restored
+func TestDecryptArchiveBlockFormat(t *testing.T) {
+	m, _, _ := newTestManager(t, true)
+	key, err := m.GetEncryptionKey()
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "metadata.json", Mode: 0o600, Size: 2}))
+	_, err = tw.Write([]byte("{}"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	ciphertext, err := encryptData(archive.Bytes(), key)
+	require.NoError(t, err)
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.enc")
+	require.NoError(t, os.WriteFile(archivePath, ciphertext, 0o600))
+
+	tarPath, err := m.decryptArchiveIfNeeded(t.Context(), archivePath, t.TempDir())
+	require.NoError(t, err)
+	data, err := os.ReadFile(tarPath)
+	require.NoError(t, err)
+	assert.Equal(t, archive.Bytes(), data)
+}
+
+// TestManagerDecryptData tests that DecryptData reads both the stream format of
+// current archives and the single block of older ones
+func TestManagerDecryptData(t *testing.T) {
+	m, _, _ := newTestManager(t, true)
+	key, err := m.GetEncryptionKey()
+	require.NoError(t, err)
+	plaintext := bytes.Repeat([]byte("backup"), encryptionChunkSize/3)
+
+	var stream bytes.Buffer
+	require.NoError(t, encryptStream(t.Context(), &stream, bytes.NewReader(plaintext), key))
+	block, err := encryptData(plaintext, key)
+	require.NoError(t, err)
+
+	for name, encrypted := range map[string][]byte{"stream": stream.Bytes(), "block": block} {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := m.DecryptData(encrypted)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(plaintext, decrypted), "decrypted data differs")
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package backup
+
+import (
+	"context"
+	"time"
+)
+
+// Backup progress stages
+const (
+	ProgressStarted         = "started"          // Backup run started
+	ProgressSourceStarted   = "source_started"   // Backing up a source
+	ProgressArchiveCreated  = "archive_created"  // Archive of a source created
+	ProgressTargetStored    = "target_stored"    // Archive stored in a target
+	ProgressTargetFailed    = "target_failed"    // Storing the archive in a target failed
+	ProgressSourceCompleted = "source_completed" // Source backed up and stored
+	ProgressSourceSkipped   = "source_skipped"   // Incremental source had no new data
+	ProgressSourceFailed    = "source_failed"    // Backing up a source failed
+	ProgressCompleted       = "completed"        // Backup run finished without errors
+	ProgressFailed          = "failed"           // Backup run finished with errors
+)
+
+// Progress describes a step of a running backup
+type Progress struct {
+	Stage        string    `json:"stage"`
+	Source       string    `json:"source,omitempty"`
+	Target       string    `json:"target,omitempty"`
+	BackupID     string    `json:"backup_id,omitempty"`
+	Size         int64     `json:"size,omitempty"`
+	SourcesDone  int       `json:"sources_done"`
+	SourcesTotal int       `json:"sources_total"`
+	Error        string    `json:"error,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// ProgressFunc receives the progress of a running backup. It is called from the
+// goroutines storing the archive in the targets, so it must be safe for concurrent use.
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+// WithProgress returns a context that reports the progress of RunBackup to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress sends p to the progress function of ctx, if any
+func reportProgress(ctx context.Context, p *Progress) {
+	fn, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	if !ok || fn == nil {
+		return
+	}
+	p.Time = time.Now()
+	fn(*p)
+}
@@ -0,0 +1,975 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver for restored database integrity checks
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Restore related constants
+const (
+	// archiveMetadataName is the name of the metadata entry inside backup archives
+	archiveMetadataName = "metadata.json"
+	// archiveConfigName is the name of the configuration entry inside backup archives
+	archiveConfigName = "config.yml"
+	// archiveDataPrefix is the name prefix of the backup data entry inside backup archives
+	archiveDataPrefix = "backup."
+
+	// maxArchiveMetadataSize limits how much of metadata.json is read into memory
+	maxArchiveMetadataSize = 1 * MB
+	// maxArchiveConfigSize limits how much of config.yml is read into memory
+	maxArchiveConfigSize = 10 * MB
+
+	// restoredConfigName is the file name the restored configuration is written to
+	restoredConfigName = "config.yaml"
+	// preRestoreSuffix is appended to files that are replaced by a restore
+	preRestoreSuffix = ".pre-restore-"
+
+	// mysqlDumpTailSize is how much of the end of a MySQL dump is searched for the footer
+	mysqlDumpTailSize = 4 * KB
+)
+
+// sqliteHeader is the magic string at the start of every SQLite database file
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// RestoreOptions controls how a backup is restored
+type RestoreOptions struct {
+	BackupID     string // ID of the backup to restore, e.g. "birdnet-20250102-030000"
+	Target       string // Name of the target to fetch from; empty tries all registered targets
+	ArchivePath  string // Restore from a local archive file instead of fetching it from a target
+	OutputDir    string // Restore into this directory instead of the configured locations
+	DryRun       bool   // Fetch and verify the archive without writing anything
+	SkipDatabase bool   // Do not restore the database
+	SkipConfig   bool   // Do not restore the configuration file
+}
+
+// RestoreResult describes the outcome of a restore operation
+type RestoreResult struct {
+	Metadata         Metadata `json:"metadata"`                    // Metadata read from the archive
+	Target           string   `json:"target,omitempty"`            // Target the archive was fetched from
+	DryRun           bool     `json:"dry_run"`                     // Whether this was a dry run
+	ChecksumVerified bool     `json:"checksum_verified"`           // Whether the archive checksum was verified
+	DatabasePath     string   `json:"database_path,omitempty"`     // Where the database was (or would be) restored
+	ConfigPath       string   `json:"config_path,omitempty"`       // Where the configuration was (or would be) restored
+	PreviousDatabase string   `json:"previous_database,omitempty"` // Where the replaced database was moved
+	PreviousConfig   string   `json:"previous_config,omitempty"`   // Where the replaced configuration was moved
+	ClipsPath        string   `json:"clips_path,omitempty"`        // Directory clips were (or would be) restored into
+	ClipsManifest    string   `json:"clips_manifest,omitempty"`    // Where the clips manifest was written
+	ClipsRestored    int      `json:"clips_restored,omitempty"`    // Number of clips and spectrograms restored
+	ClipsSkipped     int      `json:"clips_skipped,omitempty"`     // Number of files skipped because they already exist
+	Warnings         []string `json:"warnings,omitempty"`          // Non-fatal issues found during restore
+}
+
+// archiveContents holds the entries extracted from a backup archive
+type archiveContents struct {
+	metadata *Metadata
+	config   []byte
+	dataPath string // Path of the extracted backup data file
+	dataName string // Name of the backup data entry in the archive
+}
+
+// Restore fetches a backup archive, verifies it and restores its contents.
+// Existing files are moved aside with a ".pre-restore-<timestamp>" suffix rather
+// than overwritten, so a failed or unwanted restore can be undone by hand.
+func (m *Manager) Restore(ctx context.Context, opts *RestoreOptions) (*RestoreResult, error) {
+	if opts.BackupID == "" && opts.ArchivePath == "" {
+		return nil, NewError(ErrValidation, "either a backup ID or an archive path is required", nil)
+	}
+	if opts.BackupID != "" {
+		if err := validateBackupID(opts.BackupID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SkipDatabase && opts.SkipConfig {
+		return nil, NewError(ErrValidation, "nothing to restore, both database and config are skipped", nil)
+	}
+
+	// A restore moves the same amount of data as a backup, use the same timeout
+	ctx, cancel := context.WithTimeout(ctx, m.getBackupTimeout())
+	defer cancel()
+
+	start := time.Now()
+	m.logger.Info("Starting restore",
+		logger.String("backup_id", opts.BackupID),
+		logger.String("target", opts.Target),
+		logger.String("archive_path", opts.ArchivePath),
+		logger.Bool("dry_run", opts.DryRun))
+
+	tempDir, err := os.MkdirTemp("", "birdnet-go-restore-*")
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_restore_temp_directory").
+			Build()
+	}
+	defer m.cleanupTempDirectories([]string{tempDir})
+
+	result := &RestoreResult{DryRun: opts.DryRun}
+
+	// 1. Get the archive, either from a local file or from a target
+	archivePath := opts.ArchivePath
+	var info *BackupInfo
+	if archivePath == "" {
+		archivePath, info, err = m.fetchArchive(ctx, opts, tempDir)
+		if err != nil {
+			return nil, err
+		}
+		result.Target = info.Target
+	}
+
+	// 2. Verify the checksum recorded when the backup was stored
+	if info != nil && info.Checksum != "" {
+		checksum, err := calculateChecksum(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(checksum, info.Checksum) {
+			return nil, NewError(ErrCorruption, fmt.Sprintf("archive checksum mismatch: expected %s, got %s", info.Checksum, checksum), nil)
+		}
+		result.ChecksumVerified = true
+	} else {
+		result.Warnings = append(result.Warnings, "no checksum recorded for this archive, skipping checksum verification")
+	}
+
+	// 3. Decrypt the archive if needed
+	tarPath, err := m.decryptArchiveIfNeeded(ctx, archivePath, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Extract and verify the archive contents
+	contents, err := m.readArchive(ctx, tarPath, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verifyArchiveContents(contents, opts); err != nil {
+		return nil, err
+	}
+	result.Metadata = *contents.metadata
+
+	// 5. Restore the database and configuration
+	if !opts.SkipDatabase {
+		if err := m.restoreDatabase(ctx, contents, opts, result); err != nil {
+			return nil, err
+		}
+	}
+	// Incremental backups carry the configuration only for reference, restoring
+	// it from an old increment would silently roll back newer settings
+	if !opts.SkipConfig && contents.metadata.Incremental {
+		result.Warnings = append(result.Warnings, "configuration is not restored from incremental backups, restore it from a database backup")
+	} else if !opts.SkipConfig {
+		if err := m.restoreConfig(contents, opts, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.DryRun && opts.OutputDir == "" && (result.DatabasePath != "" || result.ConfigPath != "") {
+		result.Warnings = append(result.Warnings, "restart BirdNET-Go to load the restored data")
+	}
+
+	m.logger.Info("Restore completed",
+		logger.String("backup_id", result.Metadata.ID),
+		logger.Bool("dry_run", opts.DryRun),
+		logger.String("database_path", result.DatabasePath),
+		logger.String("config_path", result.ConfigPath),
+		logger.String("clips_path", result.ClipsPath),
+		logger.Int("warning_count", len(result.Warnings)),
+		logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+	return result, nil
+}
+
+// validateBackupID ensures a backup ID cannot be used to escape a target's directory
+func validateBackupID(id string) error {
+	if strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return NewError(ErrSecurity, fmt.Sprintf("invalid backup ID: %s", id), nil)
+	}
+	return nil
+}
+
+// fetchArchive downloads the requested backup from the first target that has it
+func (m *Manager) fetchArchive(ctx context.Context, opts *RestoreOptions, tempDir string) (string, *BackupInfo, error) {
+	m.mu.RLock()
+	candidates := make([]Target, 0, len(m.targets))
+	for name, t := range m.targets {
+		if opts.Target == "" || opts.Target == name {
+			candidates = append(candidates, t)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(candidates) == 0 {
+		if opts.Target != "" {
+			return "", nil, NewError(ErrNotFound, fmt.Sprintf("target '%s' is not registered", opts.Target), nil)
+		}
+		return "", nil, NewError(ErrNotFound, "no backup targets registered", nil)
+	}
+
+	// Look up the stored metadata for checksum verification. Not every target
+	// reports backup IDs in its listing, so a missing entry is not an error.
+	var known *BackupInfo
+	backups, err := m.ListBackups(ctx)
+	if err != nil {
+		m.logger.Warn("Failed to list backups, continuing without stored metadata", logger.Error(err))
+	}
+	for i := range backups {
+		if backups[i].ID == opts.BackupID && (opts.Target == "" || backups[i].Target == opts.Target) {
+			known = &backups[i]
+			break
+		}
+	}
+
+	// Try the target known to hold the backup first, then the rest in name order
+	sort.Slice(candidates, func(i, j int) bool {
+		if known != nil && candidates[i].Name() != candidates[j].Name() {
+			if candidates[i].Name() == known.Target {
+				return true
+			}
+			if candidates[j].Name() == known.Target {
+				return false
+			}
+		}
+		return candidates[i].Name() < candidates[j].Name()
+	})
+
+	destPath := filepath.Join(tempDir, opts.BackupID+".archive")
+	var errs []error
+	for _, target := range candidates {
+		retriever, ok := target.(Retriever)
+		if !ok {
+			errs = append(errs, fmt.Errorf("target %s: retrieving backups is not supported", target.Name()))
+			continue
+		}
+
+		m.logger.Info("Retrieving backup from target", logger.String("backup_id", opts.BackupID), logger.String("target_name", target.Name()))
+		if err := retriever.Retrieve(ctx, opts.BackupID, destPath); err != nil {
+			m.logger.Debug("Failed to retrieve backup from target", logger.String("backup_id", opts.BackupID), logger.String("target_name", target.Name()), logger.Error(err))
+			errs = append(errs, fmt.Errorf("target %s: %w", target.Name(), err))
+			continue
+		}
+
+		info := &BackupInfo{Target: target.Name()}
+		if known != nil && known.Target == target.Name() {
+			info = known
+		}
+		return destPath, info, nil
+	}
+
+	return "", nil, NewError(ErrNotFound, fmt.Sprintf("backup '%s' could not be retrieved from any target", opts.BackupID), combineErrors(errs))
+}
+
+// isTarArchive reports whether the file at path starts with a TAR header
+func isTarArchive(path string) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec // G304 - path is an archive selected for restore
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_archive").
+			Context("path", path).
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, 512)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, nil // Too short to be a TAR archive
+	}
+	// USTAR, PAX and GNU headers all carry the "ustar" magic at offset 257
+	return bytes.HasPrefix(header[257:], []byte("ustar")), nil
+}
+
+// decryptArchiveIfNeeded returns the path of a plain TAR archive, decrypting the
+// archive into tempDir when it is encrypted
+func (m *Manager) decryptArchiveIfNeeded(ctx context.Context, archivePath, tempDir string) (string, error) {
+	isTar, err := isTarArchive(archivePath)
+	if err != nil {
+		return "", err
+	}
+	if isTar {
+		return archivePath, nil
+	}
+
+	m.logger.Debug("Archive is not a plain TAR file, decrypting", logger.String("archive_path", archivePath))
+	key, err := m.readEncryptionKey()
+	if err != nil {
+		return "", err
+	}
+
+	tarPath := filepath.Join(tempDir, "decrypted.tar")
+	stream, err := isEncryptionStream(archivePath)
+	if err != nil {
+		return "", err
+	}
+	if stream {
+		err = decryptArchiveStream(ctx, archivePath, tarPath, key)
+	} else {
+		err = decryptArchiveBlock(archivePath, tarPath, key)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	isTar, err = isTarArchive(tarPath)
+	if err != nil {
+		return "", err
+	}
+	if !isTar {
+		return "", NewError(ErrCorruption, "decrypted archive is not a valid TAR file", nil)
+	}
+	return tarPath, nil
+}
+
+// decryptArchiveStream decrypts an archive in the stream format into tarPath
+func decryptArchiveStream(ctx context.Context, archivePath, tarPath string, key []byte) error {
+	src, err := os.Open(archivePath) //nolint:gosec // G304 - archivePath is an archive selected for restore
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encrypted_archive").
+			Context("archive_path", archivePath).
+			Build()
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(tarPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, PermSecureFile) //nolint:gosec // G304 - tarPath is in the restore temp directory
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_decrypted_archive").
+			Build()
+	}
+	if err := decryptStream(ctx, dst, src, key); err != nil {
+		_ = dst.Close()
+		return NewError(ErrEncryption, "failed to decrypt archive, check that the correct encryption key is installed", err)
+	}
+	if err := dst.Close(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_decrypted_archive").
+			Build()
+	}
+	return nil
+}
+
+// decryptArchiveBlock decrypts an archive written before the stream format, which
+// is a single sealed block and has to be read into memory
+func decryptArchiveBlock(archivePath, tarPath string, key []byte) error {
+	ciphertext, err := os.ReadFile(archivePath) //nolint:gosec // G304 - archivePath is an archive selected for restore
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_encrypted_archive").
+			Context("archive_path", archivePath).
+			Build()
+	}
+
+	plaintext, err := decryptData(ciphertext, key)
+	if err != nil {
+		return NewError(ErrEncryption, "failed to decrypt archive, check that the correct encryption key is installed", err)
+	}
+
+	if err := os.WriteFile(tarPath, plaintext, PermSecureFile); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_decrypted_archive").
+			Build()
+	}
+	return nil
+}
+
+// readArchive extracts metadata, configuration and backup data from a TAR archive
+func (m *Manager) readArchive(ctx context.Context, tarPath, tempDir string) (*archiveContents, error) {
+	f, err := os.Open(tarPath) //nolint:gosec // G304 - tarPath is an archive selected for restore
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_archive").
+			Context("path", tarPath).
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	contents := &archiveContents{}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, NewError(ErrCorruption, "failed to read backup archive", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Entries are always written flat, reject anything that tries to use a path
+		if filepath.Base(hdr.Name) != hdr.Name || hdr.Name == ".." {
+			return nil, NewError(ErrSecurity, fmt.Sprintf("unexpected entry in backup archive: %s", hdr.Name), nil)
+		}
+
+		switch {
+		case hdr.Name == archiveMetadataName:
+			data, err := io.ReadAll(io.LimitReader(tr, maxArchiveMetadataSize))
+			if err != nil {
+				return nil, NewError(ErrCorruption, "failed to read archive metadata", err)
+			}
+			var metadata Metadata
+			if err := json.Unmarshal(data, &metadata); err != nil {
+				return nil, NewError(ErrCorruption, "invalid archive metadata", err)
+			}
+			contents.metadata = &metadata
+
+		case hdr.Name == archiveConfigName:
+			data, err := io.ReadAll(io.LimitReader(tr, maxArchiveConfigSize))
+			if err != nil {
+				return nil, NewError(ErrCorruption, "failed to read archived configuration", err)
+			}
+			contents.config = data
+
+		case strings.HasPrefix(hdr.Name, archiveDataPrefix):
+			dataPath := filepath.Join(tempDir, hdr.Name)
+			if err := extractToFile(ctx, tr, dataPath); err != nil {
+				return nil, err
+			}
+			contents.dataPath = dataPath
+			contents.dataName = hdr.Name
+
+		default:
+			m.logger.Warn("Ignoring unknown entry in backup archive", logger.String("entry", hdr.Name))
+		}
+	}
+
+	if contents.metadata == nil {
+		return nil, NewError(ErrCorruption, "backup archive does not contain metadata.json", nil)
+	}
+	return contents, nil
+}
+
+// extractToFile writes the current TAR entry to path
+func extractToFile(ctx context.Context, r io.Reader, path string) error {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, PermSecureFile) //nolint:gosec // G304 - path is inside the restore temp directory
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_extracted_file").
+			Build()
+	}
+	if err := copyWithContext(ctx, out, r); err != nil {
+		_ = out.Close()
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "extract_archive_entry").
+			Build()
+	}
+	if err := out.Close(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "close_extracted_file").
+			Build()
+	}
+	return nil
+}
+
+// verifyArchiveContents checks the archive metadata against the restore request
+func (m *Manager) verifyArchiveContents(contents *archiveContents, opts *RestoreOptions) error {
+	metadata := contents.metadata
+
+	if metadata.Version > MetadataVersion {
+		return NewError(ErrValidation, fmt.Sprintf("backup metadata version %d is newer than supported version %d, upgrade BirdNET-Go first", metadata.Version, MetadataVersion), nil)
+	}
+	if opts.BackupID != "" && metadata.ID != opts.BackupID {
+		return NewError(ErrValidation, fmt.Sprintf("archive contains backup '%s', expected '%s'", metadata.ID, opts.BackupID), nil)
+	}
+	// The source name is used for restored file names, it must not point elsewhere
+	if !filepath.IsLocal(metadata.Source) || strings.ContainsAny(metadata.Source, `/\`) {
+		return NewError(ErrSecurity, fmt.Sprintf("invalid backup source name: %q", metadata.Source), nil)
+	}
+
+	// The config hash is computed over the exact bytes stored as config.yml
+	if contents.config != nil && metadata.ConfigHash != "" {
+		hash := sha256.Sum256(contents.config)
+		if hex.EncodeToString(hash[:]) != metadata.ConfigHash {
+			return NewError(ErrCorruption, "archived configuration does not match its recorded hash", nil)
+		}
+	}
+
+	if !opts.SkipDatabase && contents.dataPath == "" {
+		return NewError(ErrCorruption, "backup archive does not contain backup data", nil)
+	}
+	if !opts.SkipConfig && contents.config == nil {
+		return NewError(ErrCorruption, "backup archive does not contain config.yml", nil)
+	}
+	return nil
+}
+
+// restoreDatabase verifies the extracted backup data and moves it into place
+func (m *Manager) restoreDatabase(ctx context.Context, contents *archiveContents, opts *RestoreOptions, result *RestoreResult) error {
+	isSQLite, err := hasFilePrefix(contents.dataPath, sqliteHeader)
+	if err != nil {
+		return err
+	}
+	if !isSQLite {
+		isMySQLDump, err := hasFilePrefix(contents.dataPath, []byte(MySQLDumpHeader))
+		if err != nil {
+			return err
+		}
+		if isMySQLDump {
+			return m.restoreMySQLDump(contents, opts, result)
+		}
+		isClips, err := isClipsArchive(contents.dataPath)
+		if err != nil {
+			return err
+		}
+		if isClips {
+			return m.restoreClips(ctx, contents, opts, result)
+		}
+		return NewError(ErrValidation, fmt.Sprintf("unsupported backup data '%s', only SQLite databases, MySQL dumps and clip backups can be restored", contents.dataName), nil)
+	}
+
+	if err := verifySQLiteIntegrity(ctx, contents.dataPath); err != nil {
+		return err
+	}
+
+	destPath, err := m.databaseRestorePath(contents.metadata, opts.OutputDir)
+	if err != nil {
+		return err
+	}
+	result.DatabasePath = destPath
+
+	if opts.DryRun {
+		m.logger.Info("Dry run, database not restored", logger.String("database_path", destPath))
+		return nil
+	}
+
+	// Copy the database next to the destination first, the live database is only
+	// moved aside once the copy is complete
+	staged, err := copyToTempFile(contents.dataPath, destPath, PermBackupFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(staged) }() // No-op once renamed into place
+
+	// A leftover write-ahead log would be replayed onto the restored database, so
+	// it is moved aside with the database. Everything moved is put back on failure.
+	timestamp := time.Now().Format("20060102-150405")
+	moved := make(map[string]string)
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		aside, err := moveAside(destPath+suffix, timestamp)
+		if err != nil {
+			m.moveBack(moved)
+			return err
+		}
+		if aside != "" {
+			moved[destPath+suffix] = aside
+		}
+	}
+	previous := moved[destPath]
+
+	if err := os.Rename(staged, destPath); err != nil {
+		m.moveBack(moved)
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "rename_restore_file").
+			Context("path", destPath).
+			Build()
+	}
+	result.PreviousDatabase = previous
+
+	m.logger.Info("Database restored",
+		logger.String("database_path", destPath),
+		logger.String("previous_database", previous))
+	return nil
+}
+
+// restoreMySQLDump writes a MySQL dump into the output directory. Dumps are not
+// imported automatically, the user loads them with the mysql client.
+func (m *Manager) restoreMySQLDump(contents *archiveContents, opts *RestoreOptions, result *RestoreResult) error {
+	complete, err := hasFileSuffix(contents.dataPath, []byte(MySQLDumpFooter), mysqlDumpTailSize)
+	if err != nil {
+		return err
+	}
+	if !complete {
+		return NewError(ErrCorruption, fmt.Sprintf("MySQL dump '%s' is incomplete", contents.dataName), nil)
+	}
+
+	if opts.OutputDir == "" {
+		return NewError(ErrValidation, "MySQL backups can only be restored to an output directory, import the dump with the mysql client afterwards", nil)
+	}
+
+	destPath := filepath.Join(opts.OutputDir, contents.metadata.Source+".sql")
+	result.DatabasePath = destPath
+
+	if opts.DryRun {
+		m.logger.Info("Dry run, MySQL dump not restored", logger.String("database_path", destPath))
+		return nil
+	}
+
+	previous, err := moveAside(destPath, time.Now().Format("20060102-150405"))
+	if err != nil {
+		return err
+	}
+	result.PreviousDatabase = previous
+
+	if err := copyFileAtomic(contents.dataPath, destPath, PermSecureFile); err != nil {
+		return err
+	}
+
+	result.Warnings = append(result.Warnings,
+		fmt.Sprintf("import the MySQL dump with: mysql -u <user> -p <database> < %s", destPath))
+	m.logger.Info("MySQL dump restored", logger.String("database_path", destPath))
+	return nil
+}
+
+// databaseRestorePath returns where the database of a backup should be restored
+func (m *Manager) databaseRestorePath(metadata *Metadata, outputDir string) (string, error) {
+	dbPath := m.fullConfig.Output.SQLite.Path
+
+	if outputDir != "" {
+		name := filepath.Base(dbPath)
+		if dbPath == "" {
+			name = metadata.Source + ".db"
+		}
+		return filepath.Join(outputDir, name), nil
+	}
+
+	if dbPath == "" {
+		return "", NewError(ErrConfig, "sqlite path is not configured, use an output directory to restore the database", nil)
+	}
+	absPath, err := filepath.Abs(dbPath)
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "resolve_database_path").
+			Context("path", dbPath).
+			Build()
+	}
+	return absPath, nil
+}
+
+// restoreConfig writes the archived configuration into place
+func (m *Manager) restoreConfig(contents *archiveContents, opts *RestoreOptions, result *RestoreResult) error {
+	// Make sure we never install a configuration the application cannot parse
+	var restored conf.Settings
+	if err := yaml.Unmarshal(contents.config, &restored); err != nil {
+		return NewError(ErrCorruption, "archived configuration is not valid YAML", err)
+	}
+
+	// Archived configurations are sanitized, take the credentials from the running
+	// configuration so a restore does not silently drop them
+	if m.fullConfig != nil {
+		restoreSensitiveFields(&restored, m.fullConfig)
+	}
+	config, err := yaml.Marshal(&restored)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "marshal_restored_config").
+			Build()
+	}
+
+	destPath, err := configRestorePath(opts.OutputDir)
+	if err != nil {
+		return err
+	}
+	result.ConfigPath = destPath
+	result.Warnings = append(result.Warnings, "passwords, client secrets and API keys are not stored in archived configurations, the restored configuration uses those of the running configuration")
+
+	if opts.DryRun {
+		m.logger.Info("Dry run, configuration not restored", logger.String("config_path", destPath))
+		return nil
+	}
+
+	previous, err := moveAside(destPath, time.Now().Format("20060102-150405"))
+	if err != nil {
+		return err
+	}
+	result.PreviousConfig = previous
+
+	if err := writeFileAtomic(destPath, bytes.NewReader(config), PermSecureFile); err != nil {
+		return err
+	}
+
+	m.logger.Info("Configuration restored",
+		logger.String("config_path", destPath),
+		logger.String("previous_config", previous))
+	return nil
+}
+
+// configRestorePath returns where the archived configuration should be restored
+func configRestorePath(outputDir string) (string, error) {
+	if outputDir != "" {
+		return filepath.Join(outputDir, restoredConfigName), nil
+	}
+
+	if configPath, err := conf.FindConfigFile(); err == nil {
+		return configPath, nil
+	}
+
+	configPaths, err := conf.GetDefaultConfigPaths()
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "get_config_restore_path").
+			Build()
+	}
+	if len(configPaths) == 0 {
+		return "", NewError(ErrConfig, "no config paths available", nil)
+	}
+	return filepath.Join(configPaths[0], restoredConfigName), nil
+}
+
+// hasFilePrefix reports whether the file at path starts with prefix
+func hasFilePrefix(path string, prefix []byte) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec // G304 - path is inside the restore temp directory
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_backup_data").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, len(prefix))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, nil
+	}
+	return bytes.Equal(header, prefix), nil
+}
+
+// hasFileSuffix reports whether suffix occurs within the last tailSize bytes of the file at path
+func hasFileSuffix(path string, suffix []byte, tailSize int64) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec // G304 - path is inside the restore temp directory
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_backup_data").
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_backup_data").
+			Build()
+	}
+
+	offset := max(info.Size()-tailSize, 0)
+	tail := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return false, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_backup_data").
+			Build()
+	}
+	return bytes.Contains(tail, suffix), nil
+}
+
+// verifySQLiteIntegrity runs an integrity check on the database at path
+func verifySQLiteIntegrity(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return NewError(ErrDatabase, "failed to open restored database", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return NewError(ErrCorruption, "failed to check restored database integrity", err)
+	}
+	if result != "ok" {
+		return NewError(ErrCorruption, fmt.Sprintf("restored database failed integrity check: %s", result), nil)
+	}
+	return nil
+}
+
+// moveAside renames an existing file to "<path>.pre-restore-<timestamp>" and
+// returns the new path, or an empty string if there was nothing to move
+func moveAside(path, timestamp string) (string, error) {
+	if _, err := os.Lstat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_restore_destination").
+			Context("path", path).
+			Build()
+	}
+
+	newPath := path + preRestoreSuffix + timestamp
+	if err := os.Rename(path, newPath); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "move_existing_file").
+			Context("path", path).
+			Build()
+	}
+	return newPath, nil
+}
+
+// moveBack renames files moved aside by moveAside back to their original paths,
+// undoing a restore that failed halfway
+func (m *Manager) moveBack(moved map[string]string) {
+	for original, aside := range moved {
+		if err := os.Rename(aside, original); err != nil {
+			m.logger.Error("Failed to move file back after a failed restore, move it back by hand",
+				logger.String("path", original),
+				logger.String("moved_to", aside),
+				logger.Error(err))
+		}
+	}
+}
+
+// copyFileAtomic copies src to dest through a temporary file in dest's directory
+func copyFileAtomic(src, dest string, perm os.FileMode) error {
+	in, err := os.Open(src) //nolint:gosec // G304 - src is inside the restore temp directory
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_restore_source").
+			Build()
+	}
+	defer func() { _ = in.Close() }()
+
+	return writeFileAtomic(dest, in, perm)
+}
+
+// copyToTempFile copies src to a temporary file in dest's directory and returns its
+// path, the caller renames it into place
+func copyToTempFile(src, dest string, perm os.FileMode) (string, error) {
+	in, err := os.Open(src) //nolint:gosec // G304 - src is inside the restore temp directory
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_restore_source").
+			Build()
+	}
+	defer func() { _ = in.Close() }()
+
+	return writeTempFile(dest, in, perm)
+}
+
+// writeFileAtomic writes r to dest through a temporary file that is renamed into place
+func writeFileAtomic(dest string, r io.Reader, perm os.FileMode) error {
+	tmpPath, err := writeTempFile(dest, r, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "rename_restore_file").
+			Context("path", dest).
+			Build()
+	}
+	return nil
+}
+
+// writeTempFile writes r to a synced temporary file in dest's directory and returns its path
+func writeTempFile(dest string, r io.Reader, perm os.FileMode) (string, error) {
+	dir := filepath.Dir(dest)
+	if err := os.MkdirAll(dir, PermBackupDir); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_restore_directory").
+			Context("dir_path", dir).
+			Build()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(dest)+".restore-*")
+	if err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_restore_temp_file").
+			Context("dir_path", dir).
+			Build()
+	}
+	success := false
+	defer func() {
+		if !success {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_restore_file").
+			Context("path", dest).
+			Build()
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "set_restore_file_permissions").
+			Context("path", dest).
+			Build()
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "sync_restore_file").
+			Context("path", dest).
+			Build()
+	}
+	if err := tmp.Close(); err != nil {
+		return "", errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "close_restore_file").
+			Context("path", dest).
+			Build()
+	}
+
+	success = true
+	return tmp.Name(), nil
+}
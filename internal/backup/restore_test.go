@@ -0,0 +1,364 @@
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// testSecret is a credential that must never end up in a backup archive
+const testSecret = "test-secret-password"
+
+// dirTarget is a backup target keeping archives in a local directory
+type dirTarget struct {
+	name    string
+	dir     string
+	mu      sync.Mutex
+	backups map[string]Metadata
+}
+
+func newDirTarget(t *testing.T, name string) *dirTarget {
+	t.Helper()
+	return &dirTarget{name: name, dir: t.TempDir(), backups: make(map[string]Metadata)}
+}
+
+func (d *dirTarget) Name() string    { return d.name }
+func (d *dirTarget) Validate() error { return nil }
+
+func (d *dirTarget) Store(_ context.Context, sourcePath string, metadata *Metadata) error {
+	if err := copyFileAtomic(sourcePath, d.archivePath(metadata.ID), PermBackupFile); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.backups[metadata.ID] = *metadata
+	return nil
+}
+
+func (d *dirTarget) List(_ context.Context) ([]BackupInfo, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	backups := make([]BackupInfo, 0, len(d.backups))
+	for _, metadata := range d.backups {
+		backups = append(backups, BackupInfo{Metadata: metadata})
+	}
+	return backups, nil
+}
+
+func (d *dirTarget) Delete(_ context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.backups, id)
+	return os.Remove(d.archivePath(id))
+}
+
+func (d *dirTarget) Retrieve(_ context.Context, id, destPath string) error {
+	d.mu.Lock()
+	_, ok := d.backups[id]
+	d.mu.Unlock()
+	if !ok {
+		return NewError(ErrNotFound, fmt.Sprintf("backup %s not found", id), nil)
+	}
+	return copyFileAtomic(d.archivePath(id), destPath, PermBackupFile)
+}
+
+func (d *dirTarget) archivePath(id string) string {
+	return filepath.Join(d.dir, id+".archive")
+}
+
+// fileSource is a backup source streaming a copy of a file
+type fileSource struct {
+	name string
+	path string
+}
+
+func (s *fileSource) Name() string    { return s.name }
+func (s *fileSource) Validate() error { return nil }
+
+func (s *fileSource) Backup(_ context.Context) (io.ReadCloser, error) {
+	return os.Open(s.path)
+}
+
+// newTestManager creates a backup manager storing into a directory target. The
+// home directory is redirected so the encryption key and the live configuration
+// are kept in a temporary directory.
+func newTestManager(t *testing.T, encrypted bool) (*Manager, *dirTarget, *conf.Settings) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	settings := &conf.Settings{}
+	settings.Output.SQLite.Path = filepath.Join(t.TempDir(), "birdnet.db")
+	settings.Security.BasicAuth.Password = testSecret
+	settings.Backup.Enabled = true
+	settings.Backup.Encryption = encrypted
+
+	m, err := NewManager(settings, nil, newTestStateManager(t), "test")
+	require.NoError(t, err)
+	target := newDirTarget(t, "local")
+	require.NoError(t, m.RegisterTarget(target))
+	return m, target, settings
+}
+
+// createTestDatabase creates a SQLite database holding one note
+func createTestDatabase(t *testing.T, path, species string) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, db.Close()) }()
+	_, err = db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, common_name TEXT)")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO notes (common_name) VALUES (?)", species)
+	require.NoError(t, err)
+}
+
+// readTestDatabase returns the species of the note in a database created by createTestDatabase
+func readTestDatabase(t *testing.T, path string) string {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, db.Close()) }()
+	var species string
+	require.NoError(t, db.QueryRow("SELECT common_name FROM notes").Scan(&species))
+	return species
+}
+
+// runTestBackup backs up a database holding one note and returns the stored backup
+func runTestBackup(t *testing.T, m *Manager, target *dirTarget, species string) Metadata {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "source.db")
+	createTestDatabase(t, dbPath, species)
+	require.NoError(t, m.RegisterSource(&fileSource{name: "sqlite", path: dbPath}))
+	require.NoError(t, m.RunBackup(t.Context()))
+
+	backups, err := target.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	return backups[0].Metadata
+}
+
+// TestRestoreRoundTrip tests restoring plain and encrypted backups into an output directory
+func TestRestoreRoundTrip(t *testing.T) {
+	for _, encrypted := range []bool{false, true} {
+		t.Run(fmt.Sprintf("encrypted=%v", encrypted), func(t *testing.T) {
+			m, target, _ := newTestManager(t, encrypted)
+			metadata := runTestBackup(t, m, target, "Eurasian Blackbird")
+			assert.Equal(t, encrypted, metadata.Encrypted)
+			assert.NotEmpty(t, metadata.Checksum)
+
+			outputDir := filepath.Join(t.TempDir(), "restore")
+			result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: outputDir})
+			require.NoError(t, err)
+
+			assert.Equal(t, "local", result.Target)
+			assert.True(t, result.ChecksumVerified)
+			assert.Equal(t, metadata.ID, result.Metadata.ID)
+			assert.Equal(t, filepath.Join(outputDir, "birdnet.db"), result.DatabasePath)
+			assert.Equal(t, "Eurasian Blackbird", readTestDatabase(t, result.DatabasePath))
+
+			// The archived configuration is sanitized, the restored one takes the
+			// credentials from the running configuration
+			assert.Equal(t, filepath.Join(outputDir, restoredConfigName), result.ConfigPath)
+			config, err := os.ReadFile(result.ConfigPath)
+			require.NoError(t, err)
+			assert.Contains(t, string(config), testSecret)
+			assert.Contains(t, result.Warnings, "passwords, client secrets and API keys are not stored in archived configurations, the restored configuration uses those of the running configuration")
+			assert.NotContains(t, result.Warnings, "restart BirdNET-Go to load the restored data")
+		})
+	}
+}
+
+// TestRestoreDryRun tests that a dry run verifies the archive without writing anything
+func TestRestoreDryRun(t *testing.T) {
+	m, target, _ := newTestManager(t, false)
+	metadata := runTestBackup(t, m, target, "Common Chaffinch")
+
+	outputDir := filepath.Join(t.TempDir(), "restore")
+	result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: outputDir, DryRun: true})
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.True(t, result.ChecksumVerified)
+	assert.Equal(t, filepath.Join(outputDir, "birdnet.db"), result.DatabasePath)
+	assert.NoDirExists(t, outputDir, "a dry run writes nothing")
+}
+
+// TestRestoreConfiguredPath tests restoring into the configured database path, moving
+// the existing database and its write-ahead log aside
+func TestRestoreConfiguredPath(t *testing.T) {
+	m, target, settings := newTestManager(t, false)
+	metadata := runTestBackup(t, m, target, "Great Tit")
+
+	livePath := settings.Output.SQLite.Path
+	createTestDatabase(t, livePath, "Blue Tit")
+	require.NoError(t, os.WriteFile(livePath+"-wal", []byte("stale wal"), PermBackupFile))
+
+	result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, SkipConfig: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, livePath, result.DatabasePath)
+	assert.Equal(t, "Great Tit", readTestDatabase(t, livePath))
+	require.NotEmpty(t, result.PreviousDatabase)
+	assert.Equal(t, "Blue Tit", readTestDatabase(t, result.PreviousDatabase))
+	assert.NoFileExists(t, livePath+"-wal", "a leftover write-ahead log is moved aside")
+	assert.Contains(t, result.Warnings, "restart BirdNET-Go to load the restored data")
+}
+
+// TestRestoreConfiguredPathRollback tests that the live database and its write-ahead
+// log are moved back when the restore fails after moving them aside
+func TestRestoreConfiguredPathRollback(t *testing.T) {
+	m, target, settings := newTestManager(t, false)
+	metadata := runTestBackup(t, m, target, "Great Tit")
+
+	livePath := settings.Output.SQLite.Path
+	createTestDatabase(t, livePath, "Blue Tit")
+	require.NoError(t, os.WriteFile(livePath+"-wal", []byte("wal"), PermBackupFile))
+
+	// A non-empty directory in the way of the moved -shm file makes the restore fail
+	// after the database and the -wal file have been moved aside
+	require.NoError(t, os.WriteFile(livePath+"-shm", []byte("shm"), PermBackupFile))
+	now := time.Now()
+	for i := range 3 {
+		blocker := livePath + "-shm" + preRestoreSuffix + now.Add(time.Duration(i)*time.Second).Format("20060102-150405")
+		require.NoError(t, os.MkdirAll(filepath.Join(blocker, "blocker"), PermBackupDir))
+	}
+
+	result, err := m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, SkipConfig: true})
+	require.Error(t, err)
+	assert.Nil(t, result)
+
+	// Checked before opening the database, which removes a stray write-ahead log
+	data, err := os.ReadFile(livePath + "-wal")
+	require.NoError(t, err)
+	assert.Equal(t, "wal", string(data))
+	assert.FileExists(t, livePath+"-shm")
+	assert.Equal(t, "Blue Tit", readTestDatabase(t, livePath), "the live database is moved back")
+
+	entries, err := os.ReadDir(filepath.Dir(livePath))
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".restore-", "the staged copy is removed")
+		if !entry.IsDir() {
+			assert.NotContains(t, entry.Name(), preRestoreSuffix)
+		}
+	}
+}
+
+// TestRestoreChecksumMismatch tests that a damaged archive is rejected before anything is restored
+func TestRestoreChecksumMismatch(t *testing.T) {
+	m, target, _ := newTestManager(t, false)
+	metadata := runTestBackup(t, m, target, "European Robin")
+
+	f, err := os.OpenFile(target.archivePath(metadata.ID), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("damaged")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	outputDir := filepath.Join(t.TempDir(), "restore")
+	_, err = m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: outputDir})
+	require.Error(t, err)
+	assert.True(t, IsErrorCode(err, ErrCorruption), "got %v", err)
+	assert.NoDirExists(t, outputDir)
+}
+
+// TestRestoreEncryptedWithoutKey tests that encrypted archives need the encryption key
+func TestRestoreEncryptedWithoutKey(t *testing.T) {
+	m, target, _ := newTestManager(t, true)
+	metadata := runTestBackup(t, m, target, "Song Thrush")
+
+	keyPath, err := m.getEncryptionKeyPath()
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(keyPath))
+
+	_, err = m.Restore(t.Context(), &RestoreOptions{BackupID: metadata.ID, OutputDir: t.TempDir(), DryRun: true})
+	require.Error(t, err)
+	assert.True(t, IsErrorCode(err, ErrEncryption), "got %v", err)
+}
+
+// TestRestoreValidation tests the checks made before fetching an archive
+func TestRestoreValidation(t *testing.T) {
+	m, _, _ := newTestManager(t, false)
+
+	for name, opts := range map[string]*RestoreOptions{
+		"no backup":      {},
+		"path traversal": {BackupID: "../birdnet-20250102-030000"},
+		"nothing":        {BackupID: "sqlite-20250102-030000", SkipDatabase: true, SkipConfig: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.Restore(t.Context(), opts)
+			require.Error(t, err)
+		})
+	}
+
+	_, err := m.Restore(t.Context(), &RestoreOptions{BackupID: "sqlite-20250102-030000", DryRun: true})
+	assert.True(t, IsErrorCode(err, ErrNotFound), "got %v", err)
+}
+
+// writeTestArchive writes a plain backup archive holding the given metadata, a
+// configuration and a SQLite database
+func writeTestArchive(t *testing.T, metadata *Metadata) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "source.db")
+	createTestDatabase(t, dbPath, "Eurasian Wren")
+	database, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+	metadataJSON, err := json.Marshal(metadata)
+	require.NoError(t, err)
+
+	archivePath := filepath.Join(t.TempDir(), metadata.ID+".tar")
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, f.Close()) }()
+	tw := tar.NewWriter(f)
+	for name, content := range map[string][]byte{
+		archiveMetadataName:          metadataJSON,
+		archiveConfigName:            []byte("main:\n  name: test\n"),
+		archiveDataPrefix + "sqlite": database,
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return archivePath
+}
+
+// TestRestoreSourceTraversal tests that an archive cannot use its source name to
+// write outside the restore directory
+func TestRestoreSourceTraversal(t *testing.T) {
+	m, _, settings := newTestManager(t, false)
+	settings.Output.SQLite.Path = ""
+
+	for _, source := range []string{"../../etc/x", "sub/dir", `..\x`, "/etc/x", ""} {
+		t.Run(source, func(t *testing.T) {
+			archivePath := writeTestArchive(t, &Metadata{Version: MetadataVersion, ID: "birdnet-20250102-030000", Source: source})
+
+			outputDir := filepath.Join(t.TempDir(), "out", "restore")
+			_, err := m.Restore(t.Context(), &RestoreOptions{ArchivePath: archivePath, OutputDir: outputDir, SkipConfig: true})
+			require.Error(t, err)
+			assert.True(t, IsErrorCode(err, ErrSecurity), "got %v", err)
+			assert.NoDirExists(t, filepath.Dir(outputDir), "nothing is written")
+		})
+	}
+
+	t.Run("valid source", func(t *testing.T) {
+		archivePath := writeTestArchive(t, &Metadata{Version: MetadataVersion, ID: "birdnet-20250102-030000", Source: "sqlite"})
+		outputDir := t.TempDir()
+		result, err := m.Restore(t.Context(), &RestoreOptions{ArchivePath: archivePath, OutputDir: outputDir, SkipConfig: true})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(outputDir, "sqlite.db"), result.DatabasePath)
+		assert.Equal(t, "Eurasian Wren", readTestDatabase(t, result.DatabasePath))
+	})
+}
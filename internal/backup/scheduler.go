@@ -496,7 +496,7 @@ func (s *Scheduler) TriggerBackup(ctx context.Context) error {
 	// Try to acquire the lock to prevent concurrent backups
 	if !s.runningBackup.TryLock() {
 		s.logger.Warn("Cannot trigger manual backup - another backup is already running")
-		return fmt.Errorf("another backup is already in progress")
+		return NewError(ErrLocked, "another backup is already in progress", nil)
 	}
 	defer s.runningBackup.Unlock()
 
@@ -0,0 +1,499 @@
+package sources
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Clip backup constants
+const (
+	clipsSourceName = "clips"
+	// clipsSettleTime keeps files that may still be written out of the current run.
+	// Exports are renamed into place keeping the modification time of the temporary
+	// file, so the watermark must stay behind anything still being exported.
+	clipsSettleTime              = time.Minute
+	clipsDefaultMaxRunSizeMB     = 1024
+	clipsDefaultFullIntervalDays = 30
+	spectrogramExt               = ".png"
+)
+
+// clipFileTypes are the audio file extensions written by the audio export
+var clipFileTypes = []string{".wav", ".flac", ".aac", ".opus", ".mp3", ".m4a"}
+
+// spectrogramSuffixPattern matches the size and raw markers of spectrogram file
+// names, e.g. "clip.sm.raw" or "clip_400px-legend" once ".png" is removed
+var spectrogramSuffixPattern = regexp.MustCompile(`(\.(sm|md|lg|xl))?(\.raw)?$|_\d+px(-legend)?$`)
+
+// ClipNoteStore looks up the detections that reference exported clips.
+// It is implemented by the datastore.
+type ClipNoteStore interface {
+	GetClipNotes(clipNames []string) ([]datastore.ClipNote, error)
+}
+
+// clipFile is a file selected for backup
+type clipFile struct {
+	relPath string // Slash separated path relative to the export path
+	path    string
+	size    int64
+	modTime time.Time
+	kind    string
+}
+
+// ClipsSource implements the backup.IncrementalSource interface for the audio
+// clips and spectrograms in the audio export directory. Each backup contains the
+// files modified since the previous successful one, preceded by a manifest that
+// maps them to their detections. Every full interval a new chain starts with a
+// backup of all files, after which the older chains can be deleted.
+type ClipsSource struct {
+	config *conf.Settings
+	store  ClipNoteStore
+	log    logger.Logger
+}
+
+// NewClipsSource creates a new clip backup source. The store is optional,
+// without it the manifest does not reference detections.
+func NewClipsSource(config *conf.Settings, store ClipNoteStore, log logger.Logger) *ClipsSource {
+	if log == nil {
+		log = logger.Global().Module("backup")
+	}
+	return &ClipsSource{
+		config: config,
+		store:  store,
+		log:    log.Module("clips"),
+	}
+}
+
+// Name returns the name of this source
+func (s *ClipsSource) Name() string {
+	return clipsSourceName
+}
+
+// Validate checks that an audio export path is configured
+func (s *ClipsSource) Validate() error {
+	if s.config.Realtime.Audio.Export.Path == "" {
+		return errors.Newf("audio export path not configured").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_config").
+			Build()
+	}
+	return nil
+}
+
+// Backup backs up every clip in the export directory
+func (s *ClipsSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	reader, _, err := s.BackupSince(ctx, backup.SourceState{})
+	return reader, err
+}
+
+// BackupSince backs up the clips and spectrograms modified after since.Watermark,
+// or all of them when a new chain is due. The returned reader streams a TAR
+// archive starting with the manifest.
+func (s *ClipsSource) BackupSince(ctx context.Context, since backup.SourceState) (io.ReadCloser, backup.SourceState, error) {
+	if err := s.Validate(); err != nil {
+		return nil, since, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	exportPath, err := filepath.Abs(s.config.Realtime.Audio.Export.Path)
+	if err != nil {
+		return nil, since, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "resolve_export_path").
+			Build()
+	}
+
+	now := time.Now()
+	fullBackup := s.startsFullBackup(since, now)
+	if fullBackup {
+		since = backup.SourceState{}
+	}
+
+	until := now.Add(-clipsSettleTime)
+	if !until.After(since.Watermark) {
+		return nil, since, backup.ErrNoChanges
+	}
+
+	files, err := s.scanFiles(ctx, exportPath, since.Watermark, until)
+	if err != nil {
+		return nil, since, err
+	}
+
+	files, truncated := limitRunSize(files, s.maxRunSize())
+	if len(files) == 0 {
+		return nil, since, backup.ErrNoChanges
+	}
+
+	manifest := s.buildManifest(exportPath, files, since.Watermark, until)
+	manifest.Truncated = truncated
+
+	next := backup.SourceState{
+		Watermark:  until,
+		TotalFiles: since.TotalFiles + len(files),
+		TotalSize:  since.TotalSize + manifest.TotalSize,
+		// The manager sets the base ID of a new chain to the ID of its first backup
+		BaseID:      since.BaseID,
+		BaseStarted: since.BaseStarted,
+		CaughtUp:    since.CaughtUp || !truncated,
+	}
+	if fullBackup {
+		next.BaseStarted = now
+	}
+	if truncated {
+		// Files are sorted by modification time, continue after the last one included
+		next.Watermark = files[len(files)-1].modTime
+		manifest.Until = next.Watermark
+		s.log.Info("Clip backup size limit reached, remaining clips are included in the next run",
+			logger.Int("max_run_size_mb", s.maxRunSize()/backup.MB))
+	}
+
+	s.log.Info("Starting clip backup",
+		logger.Int("file_count", len(files)),
+		logger.Int64("total_size", manifest.TotalSize),
+		logger.Int("note_count", manifest.NoteCount),
+		logger.Time("since", since.Watermark),
+		logger.Bool("full_backup", fullBackup))
+
+	pr, pw := io.Pipe()
+	go func() {
+		start := time.Now()
+		if err := s.writeArchive(ctx, pw, manifest, files); err != nil {
+			s.log.Error("Clip backup failed in goroutine", logger.Error(err), logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+			if closeErr := pw.CloseWithError(err); closeErr != nil {
+				s.log.Warn("Error closing pipe writer with error", logger.Error(closeErr))
+			}
+			return
+		}
+		if err := pw.Close(); err != nil {
+			s.log.Warn("Error closing pipe writer in goroutine", logger.Error(err))
+		}
+		s.log.Info("Clip backup completed successfully", logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+	}()
+
+	return pr, next, nil
+}
+
+// maxRunSize returns the configured size limit of a single run in bytes
+func (s *ClipsSource) maxRunSize() int {
+	sizeMB := s.config.Backup.Clips.MaxRunSizeMB
+	if sizeMB <= 0 {
+		sizeMB = clipsDefaultMaxRunSizeMB
+	}
+	return sizeMB * backup.MB
+}
+
+// fullInterval returns the configured time between full backups
+func (s *ClipsSource) fullInterval() time.Duration {
+	days := s.config.Backup.Clips.FullIntervalDays
+	if days <= 0 {
+		days = clipsDefaultFullIntervalDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// startsFullBackup reports whether a run starts a new chain with a full backup.
+// This is the case when there is no chain yet, including state recorded before
+// chains existed, and when the current chain has caught up and is older than
+// the full interval. A chain that has not caught up is continued first, so a
+// full backup larger than the run size limit is completed before the next one.
+func (s *ClipsSource) startsFullBackup(since backup.SourceState, now time.Time) bool {
+	if since.BaseID == "" {
+		return true
+	}
+	return since.CaughtUp && now.Sub(since.BaseStarted) >= s.fullInterval()
+}
+
+// scanFiles returns the clips and spectrograms below root modified in (since, until],
+// sorted by modification time
+func (s *ClipsSource) scanFiles(ctx context.Context, root string, since, until time.Time) ([]clipFile, error) {
+	var files []clipFile
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll // Nothing has been exported yet
+			}
+			s.log.Warn("Skipping unreadable path", logger.String("path", path), logger.Error(err))
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		// Hidden directories hold no exports, e.g. the clips held for undoing a bulk delete
+		if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+
+		kind := clipKind(d.Name())
+		if kind == "" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Removed while scanning
+		}
+		if !info.ModTime().After(since) || info.ModTime().After(until) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, clipFile{
+			relPath: filepath.ToSlash(relPath),
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+			kind:    kind,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "scan_clips").
+			Context("path", root).
+			Build()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.Before(files[j].modTime)
+		}
+		return files[i].relPath < files[j].relPath
+	})
+	return files, nil
+}
+
+// clipKind returns the manifest kind of a file name, or an empty string for
+// files that are not backed up
+func clipKind(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	switch {
+	case ext == spectrogramExt:
+		return backup.ClipKindSpectrogram
+	case slices.Contains(clipFileTypes, ext):
+		return backup.ClipKindAudio
+	default:
+		return ""
+	}
+}
+
+// limitRunSize keeps the oldest files up to maxSize bytes. Files sharing the
+// modification time of the last file kept are always kept with it, so the
+// watermark never splits them.
+func limitRunSize(files []clipFile, maxSize int) ([]clipFile, bool) {
+	var total int64
+	for i := range files {
+		if i > 0 && total+files[i].size > int64(maxSize) && files[i].modTime.After(files[i-1].modTime) {
+			return files[:i], true
+		}
+		total += files[i].size
+	}
+	return files, false
+}
+
+// buildManifest describes the selected files and links them to their detections
+func (s *ClipsSource) buildManifest(exportPath string, files []clipFile, since, until time.Time) *backup.ClipsManifest {
+	manifest := &backup.ClipsManifest{
+		Version:    backup.ClipsManifestVersion,
+		ExportPath: exportPath,
+		Since:      since,
+		Until:      until,
+		Files:      make([]backup.ClipsManifestFile, 0, len(files)),
+	}
+
+	notes := s.lookupNotes(files)
+	noteIDs := make(map[uint]struct{})
+	for i := range files {
+		entry := backup.ClipsManifestFile{
+			Path:    files[i].relPath,
+			Kind:    files[i].kind,
+			Size:    files[i].size,
+			ModTime: files[i].modTime,
+		}
+		if note, ok := notes[clipBase(files[i].relPath, files[i].kind)]; ok {
+			entry.NoteID = note.NoteID
+			entry.ScientificName = note.ScientificName
+			entry.CommonName = note.CommonName
+			entry.Date = note.Date
+			entry.Time = note.Time
+			entry.Confidence = note.Confidence
+			entry.Verified = note.Verified
+			entry.Locked = note.Locked
+			noteIDs[note.NoteID] = struct{}{}
+		}
+		manifest.Files = append(manifest.Files, entry)
+		manifest.TotalSize += files[i].size
+	}
+	manifest.NoteCount = len(noteIDs)
+	return manifest
+}
+
+// lookupNotes returns the detections referencing the selected files, keyed by
+// clip path without extension. Spectrograms whose clip is not part of this run
+// are resolved with every possible audio extension.
+func (s *ClipsSource) lookupNotes(files []clipFile) map[string]datastore.ClipNote {
+	notes := make(map[string]datastore.ClipNote)
+	if s.store == nil {
+		return notes
+	}
+
+	var clipNames []string
+	audioBases := make(map[string]bool)
+	for i := range files {
+		if files[i].kind == backup.ClipKindAudio {
+			clipNames = append(clipNames, files[i].relPath)
+			audioBases[clipBase(files[i].relPath, files[i].kind)] = true
+		}
+	}
+	for i := range files {
+		base := clipBase(files[i].relPath, files[i].kind)
+		if files[i].kind != backup.ClipKindSpectrogram || audioBases[base] {
+			continue
+		}
+		audioBases[base] = true
+		for _, ext := range clipFileTypes {
+			clipNames = append(clipNames, base+ext)
+		}
+	}
+
+	clipNotes, err := s.store.GetClipNotes(clipNames)
+	if err != nil {
+		// The clips are still worth backing up without detection details
+		s.log.Warn("Failed to look up detections for clips, manifest will not reference them", logger.Error(err))
+		return notes
+	}
+	for i := range clipNotes {
+		notes[clipBase(clipNotes[i].ClipName, backup.ClipKindAudio)] = clipNotes[i]
+	}
+	return notes
+}
+
+// clipBase returns the clip path without extension that a file belongs to
+func clipBase(relPath, kind string) string {
+	base := strings.TrimSuffix(relPath, filepath.Ext(relPath))
+	if kind == backup.ClipKindSpectrogram {
+		base = spectrogramSuffixPattern.ReplaceAllString(base, "")
+	}
+	return base
+}
+
+// writeArchive writes the manifest followed by the selected files as a TAR stream
+func (s *ClipsSource) writeArchive(ctx context.Context, w io.Writer, manifest *backup.ClipsManifest, files []clipFile) error {
+	tw := tar.NewWriter(w)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategorySystem).
+			Context("operation", "marshal_clips_manifest").
+			Build()
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    backup.ClipsManifestName,
+		Mode:    int64(backup.PermBackupFile),
+		Size:    int64(len(manifestData)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return s.wrapWriteError(err, "write_manifest_header")
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return s.wrapWriteError(err, "write_manifest")
+	}
+
+	for i := range files {
+		if err := ctx.Err(); err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategorySystem).
+				Context("operation", "write_clips").
+				Context("error_type", "cancelled").
+				Build()
+		}
+		if err := s.addFile(tw, &files[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return s.wrapWriteError(err, "close_clips_archive")
+	}
+	return nil
+}
+
+// addFile copies a single file into the TAR stream. Files removed since the
+// scan, e.g. by the disk cleanup, are skipped.
+func (s *ClipsSource) addFile(tw *tar.Writer, file *clipFile) error {
+	f, err := os.Open(file.path) //nolint:gosec // G304 - path was found by walking the export directory
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			s.log.Warn("Clip removed before it could be backed up", logger.String("path", file.relPath))
+			return nil
+		}
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "open_clip").
+			Context("path", file.relPath).
+			Build()
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_clip").
+			Context("path", file.relPath).
+			Build()
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    file.relPath,
+		Mode:    int64(backup.PermBackupFile),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return s.wrapWriteError(err, "write_clip_header")
+	}
+	if _, err := io.CopyN(tw, f, info.Size()); err != nil {
+		return s.wrapWriteError(err, "write_clip")
+	}
+	return nil
+}
+
+// wrapWriteError wraps an error writing the clips TAR stream
+func (s *ClipsSource) wrapWriteError(err error, operation string) error {
+	return errors.New(err).
+		Component("backup").
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Build()
+}
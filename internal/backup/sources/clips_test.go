@@ -0,0 +1,421 @@
+package sources
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/backup/targets"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// fakeClipNoteStore returns the configured detections for the clip names it is asked for
+type fakeClipNoteStore struct {
+	notes     []datastore.ClipNote
+	requested []string
+}
+
+func (f *fakeClipNoteStore) GetClipNotes(clipNames []string) ([]datastore.ClipNote, error) {
+	f.requested = append(f.requested, clipNames...)
+	var found []datastore.ClipNote
+	for _, note := range f.notes {
+		for _, name := range clipNames {
+			if note.ClipName == name {
+				found = append(found, note)
+			}
+		}
+	}
+	return found, nil
+}
+
+// writeTestClip writes a file below the export path with the given modification time
+func writeTestClip(t *testing.T, exportPath, relPath string, size int, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(exportPath, filepath.FromSlash(relPath))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// readClipsArchive reads the manifest and the names of the files in a clips TAR stream
+func readClipsArchive(t *testing.T, r io.Reader) (*backup.ClipsManifest, []string) {
+	t.Helper()
+	tr := tar.NewReader(r)
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	require.Equal(t, backup.ClipsManifestName, hdr.Name, "the manifest comes first")
+	var manifest backup.ClipsManifest
+	require.NoError(t, json.NewDecoder(tr).Decode(&manifest))
+
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	return &manifest, names
+}
+
+// newTestClipsSource creates a clips source for a temporary export path
+func newTestClipsSource(t *testing.T, store ClipNoteStore) (*ClipsSource, string) {
+	t.Helper()
+	settings := &conf.Settings{}
+	settings.Realtime.Audio.Export.Path = t.TempDir()
+	return NewClipsSource(settings, store, nil), settings.Realtime.Audio.Export.Path
+}
+
+// TestClipKind tests which files of the export path are backed up
+func TestClipKind(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"clip.wav":        backup.ClipKindAudio,
+		"clip.FLAC":       backup.ClipKindAudio,
+		"clip.m4a":        backup.ClipKindAudio,
+		"clip.png":        backup.ClipKindSpectrogram,
+		"clip_400px.png":  backup.ClipKindSpectrogram,
+		"clip.txt":        "",
+		"clip.wav.temp":   "",
+		"manifest.json":   "",
+		"no-extension":    "",
+		"clip.sm.raw.jpg": "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, clipKind(name), name)
+	}
+}
+
+// TestClipBase tests resolving files to the clip they belong to
+func TestClipBase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		relPath string
+		kind    string
+		want    string
+	}{
+		{"2025/05/eurbla_95p.wav", backup.ClipKindAudio, "2025/05/eurbla_95p"},
+		{"2025/05/eurbla_95p.png", backup.ClipKindSpectrogram, "2025/05/eurbla_95p"},
+		{"2025/05/eurbla_95p.sm.png", backup.ClipKindSpectrogram, "2025/05/eurbla_95p"},
+		{"2025/05/eurbla_95p.lg.raw.png", backup.ClipKindSpectrogram, "2025/05/eurbla_95p"},
+		{"2025/05/eurbla_95p_400px.png", backup.ClipKindSpectrogram, "2025/05/eurbla_95p"},
+		{"2025/05/eurbla_95p_800px-legend.png", backup.ClipKindSpectrogram, "2025/05/eurbla_95p"},
+		// Size markers only apply to spectrograms
+		{"2025/05/eurbla_95p.sm.wav", backup.ClipKindAudio, "2025/05/eurbla_95p.sm"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, clipBase(tt.relPath, tt.kind), tt.relPath)
+	}
+}
+
+// TestLimitRunSize tests splitting a run at the size limit without splitting a modification time
+func TestLimitRunSize(t *testing.T) {
+	t.Parallel()
+	base := time.Date(2025, 5, 1, 4, 0, 0, 0, time.UTC)
+	file := func(size int64, seconds int) clipFile {
+		return clipFile{size: size, modTime: base.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	tests := []struct {
+		name          string
+		files         []clipFile
+		maxSize       int
+		wantCount     int
+		wantTruncated bool
+	}{
+		{"empty", nil, 100, 0, false},
+		{"below limit", []clipFile{file(40, 1), file(40, 2)}, 100, 2, false},
+		{"at limit", []clipFile{file(50, 1), file(50, 2)}, 100, 2, false},
+		{"above limit", []clipFile{file(60, 1), file(60, 2), file(60, 3)}, 100, 1, true},
+		{"first file above limit", []clipFile{file(200, 1), file(10, 2)}, 100, 1, true},
+		{"same modification time", []clipFile{file(60, 1), file(60, 1), file(60, 2)}, 100, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			files, truncated := limitRunSize(tt.files, tt.maxSize)
+			assert.Len(t, files, tt.wantCount)
+			assert.Equal(t, tt.wantTruncated, truncated)
+		})
+	}
+}
+
+// TestClipsSourceBackupSince tests selecting the files modified since the watermark and
+// mapping them to their detections in the manifest
+func TestClipsSourceBackupSince(t *testing.T) {
+	store := &fakeClipNoteStore{notes: []datastore.ClipNote{
+		{NoteID: 1, ClipName: "2025/05/eurbla_95p.wav", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Verified: "correct", Locked: true},
+		{NoteID: 2, ClipName: "2025/04/comcha_80p.flac", ScientificName: "Fringilla coelebs", CommonName: "Common Chaffinch"},
+	}}
+	source, exportPath := newTestClipsSource(t, store)
+
+	now := time.Now()
+	since := backup.SourceState{
+		Watermark:   now.Add(-3 * time.Hour),
+		TotalFiles:  5,
+		TotalSize:   1000,
+		BaseID:      "clips-20250401-030000",
+		BaseStarted: now.Add(-24 * time.Hour),
+		CaughtUp:    true,
+	}
+	writeTestClip(t, exportPath, "2025/04/comcha_80p.flac", 10, now.Add(-4*time.Hour)) // Backed up before
+	writeTestClip(t, exportPath, "2025/05/eurbla_95p.wav", 100, now.Add(-2*time.Hour))
+	writeTestClip(t, exportPath, "2025/05/eurbla_95p.png", 20, now.Add(-2*time.Hour+time.Second))
+	writeTestClip(t, exportPath, "2025/04/comcha_80p_400px.png", 30, now.Add(-time.Hour)) // Clip is in an earlier backup
+	writeTestClip(t, exportPath, "2025/05/unknown_70p.mp3", 40, now.Add(-30*time.Minute))
+	writeTestClip(t, exportPath, "2025/05/fresh_90p.wav", 50, now)                             // May still be written
+	writeTestClip(t, exportPath, "2025/05/.hidden.wav", 60, now.Add(-time.Hour))               // Hidden
+	writeTestClip(t, exportPath, "2025/05/notes.txt", 70, now.Add(-time.Hour))                 // Not a clip
+	writeTestClip(t, exportPath, "2025/05/clip.wav.temp", 80, now.Add(-time.Hour))             // Export in progress
+	writeTestClip(t, exportPath, ".bulk-undo/7/2025/05/held_90p.wav", 90, now.Add(-time.Hour)) // Held for an undo
+
+	reader, next, err := source.BackupSince(t.Context(), since)
+	require.NoError(t, err)
+	manifest, names := readClipsArchive(t, reader)
+	require.NoError(t, reader.Close())
+
+	// Files are archived oldest first
+	assert.Equal(t, []string{
+		"2025/05/eurbla_95p.wav",
+		"2025/05/eurbla_95p.png",
+		"2025/04/comcha_80p_400px.png",
+		"2025/05/unknown_70p.mp3",
+	}, names)
+
+	assert.Equal(t, backup.ClipsManifestVersion, manifest.Version)
+	assert.True(t, manifest.Since.Equal(since.Watermark))
+	assert.True(t, manifest.Until.Equal(next.Watermark))
+	assert.False(t, manifest.Truncated)
+	assert.Equal(t, int64(190), manifest.TotalSize)
+	assert.Equal(t, 2, manifest.NoteCount)
+	require.Len(t, manifest.Files, 4)
+
+	clip, spectrogram, earlier, unknown := manifest.Files[0], manifest.Files[1], manifest.Files[2], manifest.Files[3]
+	assert.Equal(t, backup.ClipKindAudio, clip.Kind)
+	assert.Equal(t, uint(1), clip.NoteID)
+	assert.Equal(t, "Eurasian Blackbird", clip.CommonName)
+	assert.Equal(t, "correct", clip.Verified)
+	assert.True(t, clip.Locked)
+	assert.Equal(t, backup.ClipKindSpectrogram, spectrogram.Kind)
+	assert.Equal(t, uint(1), spectrogram.NoteID, "spectrograms reference the detection of their clip")
+	assert.Equal(t, uint(2), earlier.NoteID, "spectrograms of clips in earlier backups are resolved too")
+	assert.Equal(t, "Common Chaffinch", earlier.CommonName)
+	assert.Zero(t, unknown.NoteID)
+	assert.Empty(t, unknown.CommonName)
+
+	// The clip of a spectrogram outside the run is looked up with every audio extension
+	for _, ext := range clipFileTypes {
+		assert.Contains(t, store.requested, "2025/04/comcha_80p"+ext)
+	}
+
+	// The watermark keeps files that may still be written for the next run
+	assert.True(t, next.Watermark.Before(now.Add(-clipsSettleTime+time.Second)))
+	assert.True(t, next.Watermark.After(now.Add(-clipsSettleTime-time.Minute)))
+	assert.Equal(t, 9, next.TotalFiles)
+	assert.Equal(t, int64(1190), next.TotalSize)
+	assert.Equal(t, since.BaseID, next.BaseID, "the run continues the current chain")
+	assert.True(t, next.BaseStarted.Equal(since.BaseStarted))
+	assert.True(t, next.CaughtUp)
+
+	// Nothing changed since the new watermark
+	_, state, err := source.BackupSince(t.Context(), next)
+	require.ErrorIs(t, err, backup.ErrNoChanges)
+	assert.Equal(t, next, state)
+}
+
+// TestClipsSourceRunSizeLimit tests that files above the run size limit are left for the next run
+func TestClipsSourceRunSizeLimit(t *testing.T) {
+	source, exportPath := newTestClipsSource(t, nil)
+	source.config.Backup.Clips.MaxRunSizeMB = 1
+
+	now := time.Now()
+	first := now.Add(-3 * time.Hour)
+	writeTestClip(t, exportPath, "a.wav", 600*1024, first)
+	writeTestClip(t, exportPath, "b.wav", 600*1024, now.Add(-2*time.Hour))
+
+	reader, next, err := source.BackupSince(t.Context(), backup.SourceState{})
+	require.NoError(t, err)
+	manifest, names := readClipsArchive(t, reader)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, []string{"a.wav"}, names)
+	assert.True(t, manifest.Truncated)
+	assert.True(t, manifest.Since.IsZero())
+	assert.True(t, next.Watermark.Equal(first), "the next run continues after the last file included")
+	assert.True(t, manifest.Until.Equal(first))
+	assert.False(t, next.CaughtUp)
+
+	// The manager records the ID of the first backup of the chain
+	next.BaseID = "clips-20250501-030000"
+	reader, next, err = source.BackupSince(t.Context(), next)
+	require.NoError(t, err)
+	manifest, names = readClipsArchive(t, reader)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, []string{"b.wav"}, names)
+	assert.False(t, manifest.Truncated)
+	assert.Equal(t, 2, next.TotalFiles)
+	assert.Equal(t, "clips-20250501-030000", next.BaseID)
+	assert.True(t, next.CaughtUp)
+}
+
+// TestClipsSourceFullBackup tests when a run starts a new chain with a full backup
+func TestClipsSourceFullBackup(t *testing.T) {
+	source, exportPath := newTestClipsSource(t, nil)
+	source.config.Backup.Clips.FullIntervalDays = 7
+
+	now := time.Now()
+	writeTestClip(t, exportPath, "a.wav", 10, now.Add(-3*time.Hour))
+	writeTestClip(t, exportPath, "b.wav", 20, now.Add(-time.Hour))
+	chain := backup.SourceState{
+		Watermark:   now.Add(-2 * time.Hour),
+		TotalFiles:  1,
+		TotalSize:   10,
+		BaseID:      "clips-20250501-030000",
+		BaseStarted: now.Add(-8 * 24 * time.Hour),
+		CaughtUp:    true,
+	}
+
+	tests := []struct {
+		name  string
+		state func() backup.SourceState
+		full  bool
+	}{
+		{"no chain yet", func() backup.SourceState { return backup.SourceState{} }, true},
+		{"state recorded before chains", func() backup.SourceState {
+			return backup.SourceState{Watermark: chain.Watermark, TotalFiles: 1}
+		}, true},
+		{"chain older than the interval", func() backup.SourceState { return chain }, true},
+		{"chain within the interval", func() backup.SourceState {
+			state := chain
+			state.BaseStarted = now.Add(-6 * 24 * time.Hour)
+			return state
+		}, false},
+		{"chain still catching up", func() backup.SourceState {
+			state := chain
+			state.CaughtUp = false
+			return state
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since := tt.state()
+			reader, next, err := source.BackupSince(t.Context(), since)
+			require.NoError(t, err)
+			manifest, names := readClipsArchive(t, reader)
+			require.NoError(t, reader.Close())
+			assert.True(t, next.CaughtUp)
+
+			if tt.full {
+				assert.Equal(t, []string{"a.wav", "b.wav"}, names)
+				assert.True(t, manifest.Since.IsZero())
+				assert.Empty(t, next.BaseID, "the manager sets the ID of the new chain")
+				assert.True(t, next.BaseStarted.After(now.Add(-time.Second)))
+				assert.Equal(t, 2, next.TotalFiles, "totals start over with the chain")
+				return
+			}
+			assert.Equal(t, []string{"b.wav"}, names)
+			assert.True(t, manifest.Since.Equal(since.Watermark))
+			assert.Equal(t, since.BaseID, next.BaseID)
+			assert.True(t, next.BaseStarted.Equal(since.BaseStarted))
+			assert.Equal(t, 2, next.TotalFiles)
+		})
+	}
+}
+
+// TestClipsSourceMissingExportPath tests that an export path without clips has nothing to back up
+func TestClipsSourceMissingExportPath(t *testing.T) {
+	source, exportPath := newTestClipsSource(t, nil)
+	source.config.Realtime.Audio.Export.Path = filepath.Join(exportPath, "missing")
+
+	_, _, err := source.BackupSince(t.Context(), backup.SourceState{})
+	require.ErrorIs(t, err, backup.ErrNoChanges)
+
+	source.config.Realtime.Audio.Export.Path = ""
+	_, _, err = source.BackupSince(t.Context(), backup.SourceState{})
+	require.Error(t, err)
+}
+
+// TestClipsSourceBackupAndRestore tests incremental clip backups through the backup
+// manager, which continues from the state recorded in the state manager, and
+// restoring them into an output directory
+func TestClipsSourceBackupAndRestore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := &fakeClipNoteStore{notes: []datastore.ClipNote{
+		{NoteID: 7, ClipName: "2025/05/eurbla_95p.wav", CommonName: "Eurasian Blackbird"},
+	}}
+	source, exportPath := newTestClipsSource(t, store)
+	source.config.Backup.Enabled = true
+
+	stateManager, err := backup.NewStateManager(nil)
+	require.NoError(t, err)
+	manager, err := backup.NewManager(source.config, nil, stateManager, "test")
+	require.NoError(t, err)
+	target, err := targets.NewLocalTarget(targets.LocalTargetConfig{Path: t.TempDir()}, nil)
+	require.NoError(t, err)
+	require.NoError(t, manager.RegisterTarget(target))
+	require.NoError(t, manager.RegisterSource(source))
+
+	clipTime := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	writeTestClip(t, exportPath, "2025/05/eurbla_95p.wav", 100, clipTime)
+	writeTestClip(t, exportPath, "2025/05/eurbla_95p.png", 20, clipTime)
+
+	require.NoError(t, manager.RunBackup(t.Context()))
+	backups, err := manager.ListBackups(t.Context())
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.True(t, backups[0].Incremental)
+	assert.Equal(t, backups[0].ID, backups[0].BaseID, "the first backup starts a chain")
+
+	// The state manager records the stored backup and the watermark it reached
+	state := stateManager.GetSourceState(clipsSourceName)
+	assert.Equal(t, backups[0].ID, state.LastBackupID)
+	assert.Equal(t, backups[0].ID, state.BaseID)
+	assert.True(t, state.CaughtUp)
+	assert.Equal(t, 2, state.TotalFiles)
+	assert.True(t, state.Watermark.After(clipTime))
+
+	// The next run starts from the recorded watermark and finds nothing new
+	require.NoError(t, manager.RunBackup(t.Context()))
+	backups, err = manager.ListBackups(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+	assert.Equal(t, state.LastBackupID, stateManager.GetSourceState(clipsSourceName).LastBackupID)
+
+	outputDir := filepath.Join(t.TempDir(), "clips")
+	result, err := manager.Restore(t.Context(), &backup.RestoreOptions{BackupID: backups[0].ID, OutputDir: outputDir})
+	require.NoError(t, err)
+	assert.Equal(t, outputDir, result.ClipsPath)
+	assert.Equal(t, 2, result.ClipsRestored)
+	assert.Zero(t, result.ClipsSkipped)
+	assert.Contains(t, result.Warnings, "configuration is not restored from incremental backups, restore it from a database backup")
+
+	info, err := os.Stat(filepath.Join(outputDir, "2025", "05", "eurbla_95p.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), info.Size())
+	assert.True(t, info.ModTime().Equal(clipTime), "restored clips keep their modification time")
+
+	// The manifest written next to the clips maps them to their detections
+	data, err := os.ReadFile(result.ClipsManifest)
+	require.NoError(t, err)
+	var manifest backup.ClipsManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest.Files, 2)
+	for _, file := range manifest.Files {
+		assert.Equal(t, uint(7), file.NoteID, file.Path)
+	}
+
+	// Restoring again keeps the existing files
+	result, err = manager.Restore(t.Context(), &backup.RestoreOptions{BackupID: backups[0].ID, OutputDir: outputDir})
+	require.NoError(t, err)
+	assert.Zero(t, result.ClipsRestored)
+	assert.Equal(t, 2, result.ClipsSkipped)
+}
@@ -0,0 +1,41 @@
+package sources
+
+import (
+	"fmt"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// RegisterConfiguredSources registers a backup source for each enabled
+// datastore, and the clip source when clip backups are enabled. The store is
+// used to link clips to detections and may be nil. Sources that fail
+// validation are logged and skipped.
+func RegisterConfiguredSources(manager *backup.Manager, settings *conf.Settings, store ClipNoteStore, lg logger.Logger) []error {
+	if lg == nil {
+		lg = logger.Global().Module("backup")
+	}
+
+	var candidates []backup.Source
+	if settings.Output.SQLite.Enabled {
+		candidates = append(candidates, NewSQLiteSource(settings, lg))
+	}
+	if settings.Output.MySQL.Enabled {
+		candidates = append(candidates, NewMySQLSource(settings, lg))
+	}
+	if settings.Backup.Clips.Enabled {
+		candidates = append(candidates, NewClipsSource(settings, store, lg))
+	}
+
+	var errs []error
+	for _, source := range candidates {
+		if err := manager.RegisterSource(source); err != nil {
+			lg.Error("Failed to register backup source", logger.String("source_name", source.Name()), logger.Error(err))
+			errs = append(errs, fmt.Errorf("source %s: %w", source.Name(), err))
+			continue
+		}
+		lg.Info("Registered backup source", logger.String("source_name", source.Name()))
+	}
+	return errs
+}
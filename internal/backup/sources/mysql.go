@@ -0,0 +1,598 @@
+package sources
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// MySQL dump constants
+const (
+	mysqlDefaultPort      = "3306"
+	mysqlDumpBufferSize   = 256 * 1024  // Write buffer for the dump stream
+	mysqlMaxInsertSize    = 1024 * 1024 // Start a new INSERT statement above this size
+	mysqlConnectTimeout   = 10 * time.Second
+	mysqlValidateTimeout  = 30 * time.Second
+	mysqlTransactionalEng = "InnoDB"
+)
+
+// mysqlSourceNamePattern matches characters that are not allowed in source names
+var mysqlSourceNamePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// mysqlBaseTable describes a table included in the dump
+type mysqlBaseTable struct {
+	name   string
+	engine string
+}
+
+// MySQLSource implements the backup.Source interface for MySQL databases.
+// It writes a logical SQL dump of all tables in the configured database,
+// read from a single consistent snapshot.
+type MySQLSource struct {
+	config *conf.Settings
+	log    logger.Logger
+}
+
+// NewMySQLSource creates a new MySQL backup source
+func NewMySQLSource(config *conf.Settings, log logger.Logger) *MySQLSource {
+	if log == nil {
+		log = logger.Global().Module("backup")
+	}
+	return &MySQLSource{
+		config: config,
+		log:    log.Module("mysql"),
+	}
+}
+
+// Name returns the name of this source
+func (s *MySQLSource) Name() string {
+	// Use the database name as the source name, like the SQLite file name
+	name := mysqlSourceNamePattern.ReplaceAllString(s.config.Output.MySQL.Database, "_")
+	if name == "" {
+		return "mysql"
+	}
+	return name
+}
+
+// validateConfig checks if MySQL backup is enabled and properly configured
+func (s *MySQLSource) validateConfig() error {
+	cfg := &s.config.Output.MySQL
+	if !cfg.Enabled {
+		return errors.Newf("mysql is not enabled").
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_config").
+			Build()
+	}
+
+	var missing []string
+	if cfg.Host == "" {
+		missing = append(missing, "host")
+	}
+	if cfg.Username == "" {
+		missing = append(missing, "username")
+	}
+	if cfg.Database == "" {
+		missing = append(missing, "database")
+	}
+	if len(missing) > 0 {
+		return errors.Newf("mysql %s not configured", strings.Join(missing, ", ")).
+			Component("backup").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "validate_config").
+			Build()
+	}
+
+	return nil
+}
+
+// dsn builds the connection string for the configured database
+func (s *MySQLSource) dsn() string {
+	cfg := &s.config.Output.MySQL
+
+	port := cfg.Port
+	if port == "" {
+		port = mysqlDefaultPort
+	}
+
+	driverConfig := mysql.NewConfig()
+	driverConfig.User = cfg.Username
+	driverConfig.Passwd = cfg.Password
+	driverConfig.Net = "tcp"
+	driverConfig.Addr = net.JoinHostPort(cfg.Host, port)
+	driverConfig.DBName = cfg.Database
+	driverConfig.Timeout = mysqlConnectTimeout
+	driverConfig.Params = map[string]string{"charset": "utf8mb4"}
+	return driverConfig.FormatDSN()
+}
+
+// openDatabase opens and verifies a connection to the configured database
+func (s *MySQLSource) openDatabase(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("mysql", s.dsn())
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "open_database").
+			Context("host", s.config.Output.MySQL.Host).
+			Build()
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		// Best effort close on error path
+		_ = db.Close()
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "verify_database_connection").
+			Context("host", s.config.Output.MySQL.Host).
+			Build()
+	}
+
+	return db, nil
+}
+
+// Backup performs a streaming logical backup of the MySQL database
+func (s *MySQLSource) Backup(ctx context.Context) (io.ReadCloser, error) {
+	start := time.Now()
+	s.log.Info("Starting MySQL streaming backup operation")
+
+	if err := s.validateConfig(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	db, err := s.openDatabase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	s.log.Info("Connected to MySQL database",
+		logger.String("host", s.config.Output.MySQL.Host),
+		logger.String("database", s.config.Output.MySQL.Database))
+
+	// Create pipe for streaming
+	pr, pw := io.Pipe()
+
+	// Start the dump in a goroutine
+	go func() {
+		defer func() {
+			if err := db.Close(); err != nil {
+				s.log.Debug("Failed to close database connection", logger.Error(err))
+			}
+		}()
+
+		if err := s.dumpDatabase(ctx, db, pw); err != nil {
+			s.log.Error("MySQL backup failed in goroutine", logger.Error(err), logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+			if closeErr := pw.CloseWithError(err); closeErr != nil {
+				s.log.Warn("Error closing pipe writer with error", logger.Error(closeErr))
+			}
+			return
+		}
+
+		if err := pw.Close(); err != nil {
+			s.log.Warn("Error closing pipe writer in goroutine", logger.Error(err))
+		}
+		s.log.Info("MySQL backup completed successfully", logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+	}()
+
+	return pr, nil
+}
+
+// dumpDatabase writes a SQL dump of all base tables to w. All tables are read
+// inside one transaction with a consistent snapshot, so InnoDB tables reflect
+// a single point in time even while detections are being written.
+func (s *MySQLSource) dumpDatabase(ctx context.Context, db *sql.DB, w io.Writer) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "get_connection").
+			Build()
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			s.log.Debug("Failed to close connection", logger.Error(err))
+		}
+	}()
+
+	for _, stmt := range []string{
+		"SET SESSION time_zone = '+00:00'", // TIMESTAMP values are dumped and restored in UTC
+		"SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ",
+		"START TRANSACTION WITH CONSISTENT SNAPSHOT",
+	} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryDatabase).
+				Context("operation", "start_snapshot").
+				Build()
+		}
+	}
+	// The transaction only reads, rolling back releases the snapshot
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "ROLLBACK"); err != nil {
+			s.log.Debug("Failed to end snapshot transaction", logger.Error(err))
+		}
+	}()
+
+	tables, err := s.listTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(w, mysqlDumpBufferSize)
+	if err := s.writeHeader(bw); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if !strings.EqualFold(table.engine, mysqlTransactionalEng) {
+			s.log.Warn("Table does not use a transactional engine, its dump may not be consistent with other tables",
+				logger.String("table", table.name),
+				logger.String("engine", table.engine))
+		}
+
+		rows, err := s.dumpTable(ctx, conn, bw, table.name)
+		if err != nil {
+			return err
+		}
+		s.log.Debug("Dumped table", logger.String("table", table.name), logger.Int64("rows", rows))
+	}
+
+	if err := s.writeFooter(bw); err != nil {
+		return err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "flush_dump").
+			Build()
+	}
+
+	s.log.Info("MySQL dump written", logger.Int("table_count", len(tables)))
+	return nil
+}
+
+// listTables returns all base tables of the current database
+func (s *MySQLSource) listTables(ctx context.Context, conn *sql.Conn) ([]mysqlBaseTable, error) {
+	rows, err := conn.QueryContext(ctx,
+		`SELECT TABLE_NAME, COALESCE(ENGINE, '') FROM information_schema.TABLES
+		 WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'
+		 ORDER BY TABLE_NAME`)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "list_tables").
+			Build()
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.log.Debug("Failed to close table list", logger.Error(err))
+		}
+	}()
+
+	var tables []mysqlBaseTable
+	for rows.Next() {
+		var table mysqlBaseTable
+		if err := rows.Scan(&table.name, &table.engine); err != nil {
+			return nil, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryDatabase).
+				Context("operation", "list_tables").
+				Build()
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "list_tables").
+			Build()
+	}
+
+	if len(tables) == 0 {
+		return nil, errors.Newf("database %s contains no tables", s.config.Output.MySQL.Database).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "list_tables").
+			Build()
+	}
+
+	return tables, nil
+}
+
+// writeHeader writes the dump preamble
+func (s *MySQLSource) writeHeader(w *bufio.Writer) error {
+	header := fmt.Sprintf(`%s
+-- Database: %s
+-- Created: %s
+
+SET NAMES utf8mb4;
+SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0;
+SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0;
+SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO';
+SET @OLD_TIME_ZONE=@@TIME_ZONE, TIME_ZONE='+00:00';
+
+`, backup.MySQLDumpHeader, s.config.Output.MySQL.Database, time.Now().UTC().Format(time.RFC3339))
+	return writeDump(w, header)
+}
+
+// writeFooter restores the session settings and marks the dump as complete
+func (s *MySQLSource) writeFooter(w *bufio.Writer) error {
+	footer := fmt.Sprintf(`SET TIME_ZONE=@OLD_TIME_ZONE;
+SET SQL_MODE=@OLD_SQL_MODE;
+SET UNIQUE_CHECKS=@OLD_UNIQUE_CHECKS;
+SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS;
+
+%s %s
+`, backup.MySQLDumpFooter, time.Now().UTC().Format(time.RFC3339))
+	return writeDump(w, footer)
+}
+
+// dumpTable writes the schema and all rows of a table, returning the number of rows written
+func (s *MySQLSource) dumpTable(ctx context.Context, conn *sql.Conn, w *bufio.Writer, table string) (int64, error) {
+	quoted := quoteIdentifier(table)
+
+	var name, createStmt string
+	if err := conn.QueryRowContext(ctx, "SHOW CREATE TABLE "+quoted).Scan(&name, &createStmt); err != nil {
+		return 0, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "show_create_table").
+			Context("table", table).
+			Build()
+	}
+
+	schema := fmt.Sprintf("--\n-- Table %s\n--\n\nDROP TABLE IF EXISTS %s;\n%s;\n\n", quoted, quoted, createStmt)
+	if err := writeDump(w, schema); err != nil {
+		return 0, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT * FROM "+quoted)
+	if err != nil {
+		return 0, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "select_rows").
+			Context("table", table).
+			Build()
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.log.Debug("Failed to close rows", logger.String("table", table), logger.Error(err))
+		}
+	}()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "get_column_types").
+			Context("table", table).
+			Build()
+	}
+
+	columnNames := make([]string, len(columnTypes))
+	encoders := make([]func(*bytes.Buffer, []byte), len(columnTypes))
+	for i, ct := range columnTypes {
+		columnNames[i] = quoteIdentifier(ct.Name())
+		encoders[i] = mysqlValueEncoder(ct.DatabaseTypeName())
+	}
+	insertPrefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES\n", quoted, strings.Join(columnNames, ","))
+
+	values := make([]sql.RawBytes, len(columnTypes))
+	scanArgs := make([]any, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	var stmt bytes.Buffer
+	var rowCount int64
+	flush := func() error {
+		if stmt.Len() == 0 {
+			return nil
+		}
+		stmt.WriteString(";\n")
+		_, err := w.Write(stmt.Bytes())
+		stmt.Reset()
+		if err != nil {
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "write_rows").
+				Context("table", table).
+				Build()
+		}
+		return nil
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return rowCount, errors.New(err).
+				Component("backup").
+				Category(errors.CategoryDatabase).
+				Context("operation", "scan_row").
+				Context("table", table).
+				Build()
+		}
+
+		if stmt.Len() == 0 {
+			stmt.WriteString(insertPrefix)
+		} else {
+			stmt.WriteString(",\n")
+		}
+		stmt.WriteByte('(')
+		for i, value := range values {
+			if i > 0 {
+				stmt.WriteByte(',')
+			}
+			if value == nil {
+				stmt.WriteString("NULL")
+				continue
+			}
+			encoders[i](&stmt, value)
+		}
+		stmt.WriteByte(')')
+		rowCount++
+
+		if stmt.Len() >= mysqlMaxInsertSize {
+			if err := flush(); err != nil {
+				return rowCount, err
+			}
+			// Check for cancellation between statements
+			if err := ctx.Err(); err != nil {
+				return rowCount, errors.New(err).
+					Component("backup").
+					Category(errors.CategorySystem).
+					Context("operation", "dump_table").
+					Context("error_type", "cancelled").
+					Build()
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return rowCount, errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "read_rows").
+			Context("table", table).
+			Build()
+	}
+
+	if err := flush(); err != nil {
+		return rowCount, err
+	}
+	return rowCount, writeDump(w, "\n")
+}
+
+// Validate checks if the source configuration is valid and the database is reachable
+func (s *MySQLSource) Validate() error {
+	if err := s.validateConfig(); err != nil {
+		return err
+	}
+	s.log.Debug("Validating MySQL source configuration",
+		logger.String("host", s.config.Output.MySQL.Host),
+		logger.String("database", s.config.Output.MySQL.Database))
+
+	ctx, cancel := context.WithTimeout(context.Background(), mysqlValidateTimeout)
+	defer cancel()
+
+	db, err := s.openDatabase(ctx)
+	if err != nil {
+		s.log.Error("MySQL source validation failed", logger.Error(err))
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			s.log.Debug("Failed to close database connection", logger.Error(err))
+		}
+	}()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryDatabase).
+			Context("operation", "get_connection").
+			Build()
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			s.log.Debug("Failed to close connection", logger.Error(err))
+		}
+	}()
+
+	if _, err := s.listTables(ctx, conn); err != nil {
+		s.log.Error("MySQL source validation failed", logger.Error(err))
+		return err
+	}
+
+	s.log.Info("MySQL source validation successful", logger.String("database", s.config.Output.MySQL.Database))
+	return nil
+}
+
+// writeDump writes a string to the dump stream
+func writeDump(w *bufio.Writer, s string) error {
+	if _, err := w.WriteString(s); err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_dump").
+			Build()
+	}
+	return nil
+}
+
+// quoteIdentifier quotes a MySQL identifier with backticks
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// mysqlValueEncoder returns the SQL literal encoder for a column type.
+// Numbers are written as is, binary data as hex and everything else as an escaped string.
+func mysqlValueEncoder(databaseType string) func(*bytes.Buffer, []byte) {
+	switch strings.TrimPrefix(databaseType, "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "DECIMAL", "FLOAT", "DOUBLE", "YEAR":
+		return func(b *bytes.Buffer, v []byte) { b.Write(v) }
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY":
+		return writeHexLiteral
+	default:
+		return writeStringLiteral
+	}
+}
+
+// writeHexLiteral writes binary data as a hex literal
+func writeHexLiteral(b *bytes.Buffer, v []byte) {
+	if len(v) == 0 {
+		b.WriteString("''")
+		return
+	}
+	b.WriteString("0x")
+	b.WriteString(hex.EncodeToString(v))
+}
+
+// writeStringLiteral writes a quoted string literal, escaping like mysqldump.
+// Newlines are always escaped so every statement ends on its own line.
+func writeStringLiteral(b *bytes.Buffer, v []byte) {
+	b.WriteByte('\'')
+	for _, c := range v {
+		switch c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '"':
+			b.WriteString(`\"`)
+		case 0x1a:
+			b.WriteString(`\Z`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+}
@@ -0,0 +1,115 @@
+//go:build integration
+
+package sources
+
+// The tests in this file dump a database from an in-process MySQL server. They
+// are behind the integration build tag so the server and its dependencies are
+// only built when asked for:
+//
+//	go test -tags integration ./internal/backup/sources/
+
+import (
+	"database/sql"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// testMySQLDatabase is the database served by the in-process MySQL server
+const testMySQLDatabase = "birdnet"
+
+// startTestMySQLServer starts an in-process MySQL server with an empty database
+// and returns settings for connecting to it
+func startTestMySQLServer(t *testing.T) *conf.Settings {
+	t.Helper()
+	provider := memory.NewDBProvider(memory.NewDatabase(testMySQLDatabase))
+	engine := sqle.NewDefault(provider)
+	srv, err := server.NewServer(server.Config{Protocol: "tcp", Address: "127.0.0.1:0"}, engine, gmssql.NewContext, memory.NewSessionBuilder(provider), nil)
+	require.NoError(t, err)
+	go func() { _ = srv.Start() }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	settings := &conf.Settings{}
+	settings.Output.MySQL.Enabled = true
+	settings.Output.MySQL.Host = host
+	settings.Output.MySQL.Port = port
+	settings.Output.MySQL.Username = "root"
+	settings.Output.MySQL.Database = testMySQLDatabase
+	return settings
+}
+
+// openTestMySQL opens a connection to the database of the test server
+func openTestMySQL(t *testing.T, source *MySQLSource) *sql.DB {
+	t.Helper()
+	db, err := source.openDatabase(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// TestMySQLSourceBackup tests dumping a database from an in-process MySQL server
+func TestMySQLSourceBackup(t *testing.T) {
+	source := NewMySQLSource(startTestMySQLServer(t), nil)
+	db := openTestMySQL(t, source)
+
+	for _, stmt := range []string{
+		"CREATE TABLE notes (id INT PRIMARY KEY, common_name VARCHAR(100), confidence DOUBLE, begin_time DATETIME, comment TEXT, spectrogram BLOB)",
+		"CREATE TABLE `odd``name` (id INT PRIMARY KEY)",
+	} {
+		_, err := db.ExecContext(t.Context(), stmt)
+		require.NoError(t, err)
+	}
+	begin := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err := db.ExecContext(t.Context(), "INSERT INTO notes VALUES (?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?)",
+		1, "Cetti's Warbler", 0.85, begin, "line1\nline2 \\ \"quoted\" \x00", []byte{0x00, 0xff},
+		2, "Eurasian Blackbird", 0.5, nil, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, source.Validate())
+
+	reader, err := source.Backup(t.Context())
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	dump := string(data)
+
+	assert.True(t, strings.HasPrefix(dump, backup.MySQLDumpHeader+"\n-- Database: birdnet\n"), "dump starts with the header")
+	lines := strings.Split(strings.TrimSuffix(dump, "\n"), "\n")
+	assert.True(t, strings.HasPrefix(lines[len(lines)-1], backup.MySQLDumpFooter+" "), "dump ends with the footer")
+
+	// Tables are dumped in name order, each dropped and recreated
+	notesAt := strings.Index(dump, "DROP TABLE IF EXISTS `notes`;\nCREATE TABLE `notes`")
+	oddAt := strings.Index(dump, "DROP TABLE IF EXISTS `odd``name`;\nCREATE TABLE `odd``name`")
+	require.NotEqual(t, -1, notesAt)
+	require.NotEqual(t, -1, oddAt)
+	assert.Less(t, notesAt, oddAt)
+
+	assert.Contains(t, dump, "INSERT INTO `notes` (`id`,`common_name`,`confidence`,`begin_time`,`comment`,`spectrogram`) VALUES\n"+
+		`(1,'Cetti\'s Warbler',0.85,'2025-01-02 03:04:05','line1\nline2 \\ \"quoted\" \0',0x00ff),`+"\n"+
+		"(2,'Eurasian Blackbird',0.5,NULL,NULL,NULL);\n")
+	assert.NotContains(t, dump, "INSERT INTO `odd``name`", "empty tables have no INSERT statement")
+}
+
+// TestMySQLSourceNoTables tests that an empty database is not backed up
+func TestMySQLSourceNoTables(t *testing.T) {
+	source := NewMySQLSource(startTestMySQLServer(t), nil)
+
+	err := source.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains no tables")
+}
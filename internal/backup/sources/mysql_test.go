@@ -0,0 +1,98 @@
+package sources
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQuoteIdentifier tests quoting table and column names
+func TestQuoteIdentifier(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "notes", "`notes`"},
+		{"space", "note reviews", "`note reviews`"},
+		{"backtick", "odd`name", "`odd``name`"},
+		{"only backticks", "``", "``````"},
+		{"quotes", `it's "x"`, "`it's \"x\"`"},
+		{"empty", "", "``"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, quoteIdentifier(tt.in))
+		})
+	}
+}
+
+// TestWriteStringLiteral tests escaping string values like mysqldump
+func TestWriteStringLiteral(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   []byte
+		want string
+	}{
+		{"empty", []byte{}, `''`},
+		{"plain", []byte("Eurasian Blackbird"), `'Eurasian Blackbird'`},
+		{"nul", []byte("a\x00b"), `'a\0b'`},
+		{"single quote", []byte("Cetti's Warbler"), `'Cetti\'s Warbler'`},
+		{"double quote", []byte(`say "hi"`), `'say \"hi\"'`},
+		{"backslash", []byte(`C:\clips\a.wav`), `'C:\\clips\\a.wav'`},
+		{"newline and carriage return", []byte("line1\r\nline2"), `'line1\r\nline2'`},
+		{"ctrl-z", []byte("a\x1ab"), `'a\Zb'`},
+		{"utf-8", []byte("Rödhake"), `'Rödhake'`},
+		{"injection", []byte(`'); DROP TABLE notes; --`), `'\'); DROP TABLE notes; --'`},
+		{"time", []byte("2025-01-02 03:04:05.123456"), `'2025-01-02 03:04:05.123456'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			writeStringLiteral(&b, tt.in)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+// TestMySQLValueEncoder tests selecting the literal encoding for column types
+func TestMySQLValueEncoder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		databaseType string
+		in           []byte
+		want         string
+	}{
+		{"int", "INT", []byte("-42"), "-42"},
+		{"unsigned bigint", "UNSIGNED BIGINT", []byte("18446744073709551615"), "18446744073709551615"},
+		{"decimal", "DECIMAL", []byte("0.8512"), "0.8512"},
+		{"double", "DOUBLE", []byte("1e-05"), "1e-05"},
+		{"year", "YEAR", []byte("2025"), "2025"},
+		{"blob", "BLOB", []byte{0x00, 0xff, '\'', '\\'}, "0x00ff275c"},
+		{"empty varbinary", "VARBINARY", []byte{}, "''"},
+		{"binary", "BINARY", []byte("ab"), "0x6162"},
+		{"bit", "BIT", []byte{0x01}, "0x01"},
+		{"varchar", "VARCHAR", []byte("it's"), `'it\'s'`},
+		{"text with nul", "TEXT", []byte("a\x00b"), `'a\0b'`},
+		{"datetime", "DATETIME", []byte("2025-01-02 03:04:05"), `'2025-01-02 03:04:05'`},
+		{"timestamp", "TIMESTAMP", []byte("2025-01-02 03:04:05"), `'2025-01-02 03:04:05'`},
+		{"date", "DATE", []byte("2025-01-02"), `'2025-01-02'`},
+		{"time", "TIME", []byte("-12:30:00"), `'-12:30:00'`},
+		{"json", "JSON", []byte(`{"a":"b"}`), `'{\"a\":\"b\"}'`},
+		{"unknown type", "", []byte("x"), `'x'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			mysqlValueEncoder(tt.databaseType)(&b, tt.in)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
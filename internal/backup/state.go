@@ -18,7 +18,8 @@ type BackupState struct {
 	Schedules  map[string]ScheduleState `json:"schedules"` // Key is "daily" or "weekly-{weekday}"
 	Targets    map[string]TargetState   `json:"targets"`   // Key is target name
 	MissedRuns []MissedBackup           `json:"missed_runs"`
-	Stats      map[string]BackupStats   `json:"stats"` // Key is target name
+	Stats      map[string]BackupStats   `json:"stats"`   // Key is target name
+	Sources    map[string]SourceState   `json:"sources"` // Key is source name, only incremental sources are tracked
 }
 
 // ScheduleState represents the state of a backup schedule
@@ -41,6 +42,21 @@ type TargetState struct {
 	ValidationStatus string    `json:"validation_status"`
 }
 
+// SourceState represents the progress of an incremental backup source. The
+// backups of a source form chains: each chain starts with a full backup and
+// continues with the data added since. Once a newer chain has caught up, the
+// older ones are no longer needed and are removed by the retention policy.
+type SourceState struct {
+	LastBackupID   string    `json:"last_backup_id"`        // ID of the last backup stored in every target
+	LastSuccessful time.Time `json:"last_successful"`       // When the last backup was stored in every target
+	Watermark      time.Time `json:"watermark"`             // Data modified up to this time has been backed up
+	TotalFiles     int       `json:"total_files"`           // Number of files backed up by the current chain
+	TotalSize      int64     `json:"total_size"`            // Size of the files backed up by the current chain
+	BaseID         string    `json:"base_id,omitempty"`     // ID of the full backup the current chain of backups started with
+	BaseStarted    time.Time `json:"base_started,omitzero"` // When the full backup of the current chain was started
+	CaughtUp       bool      `json:"caught_up,omitempty"`   // Whether the current chain holds everything up to the watermark
+}
+
 // MissedBackup represents a missed backup event
 type MissedBackup struct {
 	ScheduledTime time.Time `json:"scheduled_time"`
@@ -77,6 +93,7 @@ func NewStateManager(_ logger.Logger) (*StateManager, error) {
 			Schedules:  make(map[string]ScheduleState),
 			Targets:    make(map[string]TargetState),
 			Stats:      make(map[string]BackupStats),
+			Sources:    make(map[string]SourceState),
 			MissedRuns: make([]MissedBackup, 0),
 		},
 		logger: GetLogger().Module("statemanager"),
@@ -110,14 +127,12 @@ func (sm *StateManager) loadState() error {
 	return json.Unmarshal(data, sm.state)
 }
 
-// saveState saves the current backup state to disk
+// saveState saves the current backup state to disk.
+// The caller must hold sm.mu.
 func (sm *StateManager) saveState() error {
 	start := time.Now()
 
-	// Copy state under lock
-	sm.mu.Lock()
 	stateSnapshot := *sm.state
-	sm.mu.Unlock()
 
 	// Update last update time (on the snapshot)
 	stateSnapshot.LastUpdate = time.Now()
@@ -244,6 +259,28 @@ func (sm *StateManager) UpdateTargetState(targetName string, metadata *Metadata,
 	return nil
 }
 
+// UpdateSourceState records the progress of an incremental backup source
+func (sm *StateManager) UpdateSourceState(sourceName string, state SourceState) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.logger.Debug("Updating source state",
+		logger.String("source_name", sourceName),
+		logger.String("backup_id", state.LastBackupID),
+		logger.Time("watermark", state.Watermark))
+
+	// State files written by older versions have no sources section
+	if sm.state.Sources == nil {
+		sm.state.Sources = make(map[string]SourceState)
+	}
+	sm.state.Sources[sourceName] = state
+
+	if err := sm.saveState(); err != nil {
+		sm.logger.Error("Failed to save state after updating source state", logger.String("source_name", sourceName), logger.Error(err))
+		return err
+	}
+	return nil
+}
+
 // UpdateStats updates the backup statistics
 func (sm *StateManager) UpdateStats(stats map[string]BackupStats) error {
 	sm.mu.Lock()
@@ -280,6 +317,14 @@ func (sm *StateManager) GetTargetState(targetName string) TargetState {
 	return sm.state.Targets[targetName]
 }
 
+// GetSourceState returns the state of a specific incremental source
+func (sm *StateManager) GetSourceState(sourceName string) SourceState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.state.Sources[sourceName]
+}
+
 // GetMissedBackups returns all missed backups
 func (sm *StateManager) GetMissedBackups() []MissedBackup {
 	sm.mu.RLock()
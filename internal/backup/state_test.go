@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStateManager creates a state manager persisting to a temporary directory
+func newTestStateManager(t *testing.T) *StateManager {
+	t.Helper()
+	return &StateManager{
+		statePath: filepath.Join(t.TempDir(), "backup-state.json"),
+		state: &BackupState{
+			Schedules:  make(map[string]ScheduleState),
+			Targets:    make(map[string]TargetState),
+			Stats:      make(map[string]BackupStats),
+			Sources:    make(map[string]SourceState),
+			MissedRuns: make([]MissedBackup, 0),
+		},
+		logger: GetLogger().Module("statemanager"),
+	}
+}
+
+// TestStateManagerUpdatesPersist tests that updates, which save the state while
+// holding the state lock, complete and are written to disk
+func TestStateManagerUpdatesPersist(t *testing.T) {
+	t.Parallel()
+	sm := newTestStateManager(t)
+	watermark := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	done := make(chan error, 1)
+	go func() {
+		if err := sm.UpdateTargetState("local", &Metadata{ID: "birdnet-20250102-030000", Size: 42}, "success"); err != nil {
+			done <- err
+			return
+		}
+		done <- sm.UpdateSourceState("clips", SourceState{LastBackupID: "clips-20250102-030000", Watermark: watermark})
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "state update did not complete, saveState must not lock the held mutex again")
+	}
+
+	reloaded := newTestStateManager(t)
+	reloaded.statePath = sm.statePath
+	require.NoError(t, reloaded.loadState())
+	assert.Equal(t, "birdnet-20250102-030000", reloaded.GetTargetState("local").LastBackupID)
+	assert.Equal(t, int64(42), reloaded.GetTargetState("local").LastBackupSize)
+	assert.True(t, reloaded.GetSourceState("clips").Watermark.Equal(watermark))
+}
@@ -3,6 +3,7 @@ package targets
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -493,3 +494,48 @@ func WriteTempFile(content []byte, prefix string) (*TempFileResult, error) {
 		Cleanup: cleanup,
 	}, nil
 }
+
+// ArchiveFileNames returns the file names a backup with the given ID may be stored
+// under, in lookup order. The manager stores archives as <id>.tar, or <id>.tar.enc
+// when encryption is enabled; the bare ID is tried last so exact file names work too.
+func ArchiveFileNames(id string) ([]string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, backup.NewError(backup.ErrSecurity, "invalid backup ID: "+id, nil)
+	}
+	return []string{id + ".tar.enc", id + ".tar", id}, nil
+}
+
+// DownloadToFile copies r to destPath, creating or truncating the file.
+// The partially written file is removed if the copy fails.
+func DownloadToFile(ctx context.Context, r io.Reader, destPath string) error {
+	out, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, PermFile) //nolint:gosec // G304 - destPath is a restore temp path from backup manager
+	if err != nil {
+		return backup.NewError(backup.ErrIO, "failed to create download file", err)
+	}
+
+	copyDone := make(chan error, 1)
+	go func() {
+		buf := make([]byte, CopyBufferSize)
+		_, err := io.CopyBuffer(out, r, buf)
+		copyDone <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = out.Close()
+		_ = os.Remove(destPath)
+		return backup.NewError(backup.ErrCanceled, "download canceled", ctx.Err())
+	case err := <-copyDone:
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(destPath)
+			return backup.NewError(backup.ErrIO, "failed to download backup", err)
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		_ = os.Remove(destPath)
+		return backup.NewError(backup.ErrIO, "failed to close download file", err)
+	}
+	return nil
+}
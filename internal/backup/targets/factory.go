@@ -0,0 +1,75 @@
+package targets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// NewTargetFromConfig creates a backup target from its configuration entry
+func NewTargetFromConfig(cfg *conf.BackupTarget, lg logger.Logger) (backup.Target, error) {
+	settings := cfg.Settings
+	if settings == nil {
+		settings = map[string]any{}
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case "local":
+		p := NewSettingsParser(settings)
+		config := LocalTargetConfig{
+			Path:  p.RequireString("path", "local"),
+			Debug: p.OptionalBool("debug", false),
+		}
+		if err := p.Error(); err != nil {
+			return nil, err
+		}
+		return NewLocalTarget(config, lg)
+	case "ftp":
+		return NewFTPTargetFromMap(settings)
+	case "sftp":
+		return NewSFTPTarget(settings, lg)
+	case "s3":
+		return NewS3Target(settings, lg)
+	case "rsync":
+		return NewRsyncTarget(settings, lg)
+	case "gdrive", "googledrive":
+		return NewGDriveTargetFromMap(settings)
+	default:
+		return nil, backup.NewError(backup.ErrConfig, fmt.Sprintf("unsupported backup target type: %s", cfg.Type), nil)
+	}
+}
+
+// RegisterConfiguredTargets creates all enabled targets from the backup
+// configuration and registers them with the manager. Targets that fail to
+// initialize are logged and skipped so one broken target does not disable the rest.
+func RegisterConfiguredTargets(manager *backup.Manager, config *conf.BackupConfig, lg logger.Logger) []error {
+	if lg == nil {
+		lg = logger.Global().Module("backup")
+	}
+
+	var errs []error
+	for i := range config.Targets {
+		targetConfig := &config.Targets[i]
+		if !targetConfig.Enabled {
+			continue
+		}
+
+		target, err := NewTargetFromConfig(targetConfig, lg)
+		if err != nil {
+			lg.Error("Failed to create backup target", logger.String("type", targetConfig.Type), logger.Error(err))
+			errs = append(errs, fmt.Errorf("target %s: %w", targetConfig.Type, err))
+			continue
+		}
+
+		if err := manager.RegisterTarget(target); err != nil {
+			lg.Error("Failed to register backup target", logger.String("target_name", target.Name()), logger.Error(err))
+			errs = append(errs, fmt.Errorf("target %s: %w", target.Name(), err))
+			continue
+		}
+		lg.Info("Registered backup target", logger.String("target_name", target.Name()))
+	}
+	return errs
+}
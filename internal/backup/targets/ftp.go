@@ -466,6 +466,44 @@ func (t *FTPTarget) Delete(ctx context.Context, target string) error {
 	})
 }
 
+// Retrieve downloads the archive of a stored backup to destPath
+func (t *FTPTarget) Retrieve(ctx context.Context, backupID, destPath string) error {
+	if t.config.Debug {
+		t.log.Info(fmt.Sprintf("🔄 FTP: Retrieving backup %s from %s", backupID, t.config.Host))
+	}
+
+	names, err := ArchiveFileNames(backupID)
+	if err != nil {
+		return err
+	}
+
+	return t.withRetry(ctx, func(conn *ftp.ServerConn) error {
+		var lastErr error
+		for _, name := range names {
+			resp, err := conn.Retr(path.Join(t.config.BasePath, name))
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			err = DownloadToFile(ctx, resp, destPath)
+			if closeErr := resp.Close(); closeErr != nil && err == nil {
+				err = backup.NewError(backup.ErrIO, "ftp: failed to complete download", closeErr)
+			}
+			if err != nil {
+				return err
+			}
+
+			if t.config.Debug {
+				t.log.Info(fmt.Sprintf("✅ FTP: Successfully retrieved backup %s", name))
+			}
+			return nil
+		}
+
+		return backup.NewError(backup.ErrNotFound, fmt.Sprintf("ftp: backup %s not found", backupID), lastErr)
+	})
+}
+
 // Validate performs comprehensive validation of the FTP target
 func (t *FTPTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
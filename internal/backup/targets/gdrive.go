@@ -739,6 +739,75 @@ func (t *GDriveTarget) Delete(ctx context.Context, id string) error {
 	})
 }
 
+// Retrieve downloads the archive of a stored backup to destPath.
+// The ID is matched against archive file names first and then tried as a
+// Drive file ID, since List reports Drive file IDs for this target.
+func (t *GDriveTarget) Retrieve(ctx context.Context, id, destPath string) error {
+	if t.config.Debug {
+		t.log.Info(fmt.Sprintf("🔄 GDrive: Retrieving backup %s", id))
+	}
+
+	names, err := ArchiveFileNames(id)
+	if err != nil {
+		return err
+	}
+
+	// Refresh token if needed
+	if err := t.refreshTokenIfNeeded(ctx); err != nil {
+		return err
+	}
+
+	// Acquire rate limit token
+	if err := t.rateLimiter.acquire(ctx); err != nil {
+		return backup.NewError(backup.ErrCanceled, "gdrive: operation canceled while waiting for rate limit", err)
+	}
+
+	return t.withRetry(ctx, func() error {
+		folderId, err := t.ensureFolder(ctx, t.config.BasePath)
+		if err != nil {
+			return err
+		}
+
+		fileId := ""
+		for _, name := range names {
+			query := fmt.Sprintf("name='%s' and '%s' in parents and trashed=false", name, folderId)
+			files, err := t.service.Files.List().Q(query).Fields("files(id)").Context(ctx).Do()
+			if err != nil {
+				return backup.NewError(backup.ErrIO, "gdrive: failed to find backup file", err)
+			}
+			if len(files.Files) > 0 {
+				fileId = files.Files[0].Id
+				break
+			}
+		}
+		if fileId == "" {
+			fileId = id
+		}
+
+		resp, err := t.service.Files.Get(fileId).Context(ctx).Download()
+		if err != nil {
+			if isAPIErr, apiErr := t.isAPIError(err); isAPIErr {
+				return apiErr
+			}
+			return backup.NewError(backup.ErrIO, "gdrive: failed to download backup file", err)
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.log.Info(fmt.Sprintf("gdrive: failed to close download body: %v", err))
+			}
+		}()
+
+		if err := DownloadToFile(ctx, resp.Body, destPath); err != nil {
+			return err
+		}
+
+		if t.config.Debug {
+			t.log.Info(fmt.Sprintf("✅ GDrive: Successfully retrieved backup %s", id))
+		}
+		return nil
+	})
+}
+
 // Validate performs comprehensive validation of the Google Drive target
 func (t *GDriveTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
@@ -49,6 +49,13 @@ type LocalTargetConfig struct {
 	Debug bool
 }
 
+// resolve returns the full path of a file in the backup directory.
+// SecureFS resolves relative paths against the working directory, so names
+// must be joined with the target path before they are handed to it.
+func (t *LocalTarget) resolve(name string) string {
+	return filepath.Join(t.path, name)
+}
+
 // atomicWriteSecure writes data to a temporary file and renames it atomically using securefs.
 // The relativePath should be relative to the LocalTarget's backup directory.
 func (t *LocalTarget) atomicWriteSecure(relativePath string, perm os.FileMode, write func(*os.File) error) error {
@@ -56,7 +63,7 @@ func (t *LocalTarget) atomicWriteSecure(relativePath string, perm os.FileMode, w
 	tempName := fmt.Sprintf(".tmp-%d-%s", time.Now().UnixNano(), filepath.Base(relativePath))
 
 	// Create temp file using securefs (sandboxed to backup directory)
-	tempFile, err := t.sfs.OpenFile(tempName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	tempFile, err := t.sfs.OpenFile(t.resolve(tempName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
 	if err != nil {
 		return fmt.Errorf("failed to create temporary file: %w", err)
 	}
@@ -68,7 +75,7 @@ func (t *LocalTarget) atomicWriteSecure(relativePath string, perm os.FileMode, w
 			if err := tempFile.Close(); err != nil {
 				t.log.Debug("local: failed to close temp file", logger.Error(err))
 			}
-			if err := t.sfs.Remove(tempName); err != nil {
+			if err := t.sfs.Remove(t.resolve(tempName)); err != nil {
 				t.log.Debug("local: failed to remove temp file", logger.Error(err))
 			}
 		}
@@ -95,7 +102,7 @@ func (t *LocalTarget) atomicWriteSecure(relativePath string, perm os.FileMode, w
 	}
 
 	// Perform atomic rename within the securefs sandbox (Go 1.25+)
-	if err := t.sfs.Rename(tempName, relativePath); err != nil {
+	if err := t.sfs.Rename(t.resolve(tempName), t.resolve(relativePath)); err != nil {
 		return fmt.Errorf("failed to rename temporary file: %w", err)
 	}
 
@@ -502,7 +509,7 @@ func (t *LocalTarget) List(ctx context.Context) ([]backup.BackupInfo, error) {
 	}
 
 	// Use securefs for directory listing (sandboxed to backup path)
-	entries, err := t.sfs.ReadDir(".")
+	entries, err := t.sfs.ReadDir(t.resolve("."))
 	if err != nil {
 		return nil, errors.New(err).
 			Component("backup").
@@ -522,7 +529,7 @@ func (t *LocalTarget) List(ctx context.Context) ([]backup.BackupInfo, error) {
 		backupName := strings.TrimSuffix(entry.Name(), ".meta")
 
 		// Check if the corresponding backup file exists (using securefs)
-		if _, err := t.sfs.Stat(backupName); err != nil {
+		if _, err := t.sfs.Stat(t.resolve(backupName)); err != nil {
 			if t.debug {
 				t.log.Info(fmt.Sprintf("⚠️ Skipping orphaned metadata file %s: backup file not found", entry.Name()))
 			}
@@ -530,7 +537,7 @@ func (t *LocalTarget) List(ctx context.Context) ([]backup.BackupInfo, error) {
 		}
 
 		// Read metadata file using securefs (sandboxed access)
-		metadataFile, err := t.sfs.Open(entry.Name())
+		metadataFile, err := t.sfs.Open(t.resolve(entry.Name()))
 		if err != nil {
 			t.log.Info(fmt.Sprintf("⚠️ Skipping backup %s: %v", backupName, err))
 			continue
@@ -574,7 +581,7 @@ func (t *LocalTarget) Delete(ctx context.Context, backupID string) error {
 	metadataName := backupID + ".meta"
 
 	// Delete backup file
-	if err := t.sfs.Remove(backupID); err != nil && !os.IsNotExist(err) {
+	if err := t.sfs.Remove(t.resolve(backupID)); err != nil && !os.IsNotExist(err) {
 		return errors.New(err).
 			Component("backup").
 			Category(errors.CategoryFileIO).
@@ -584,7 +591,7 @@ func (t *LocalTarget) Delete(ctx context.Context, backupID string) error {
 	}
 
 	// Delete metadata file
-	if err := t.sfs.Remove(metadataName); err != nil && !os.IsNotExist(err) {
+	if err := t.sfs.Remove(t.resolve(metadataName)); err != nil && !os.IsNotExist(err) {
 		return errors.New(err).
 			Component("backup").
 			Category(errors.CategoryFileIO).
@@ -600,6 +607,49 @@ func (t *LocalTarget) Delete(ctx context.Context, backupID string) error {
 	return nil
 }
 
+// Retrieve copies the archive of a stored backup to destPath
+func (t *LocalTarget) Retrieve(ctx context.Context, backupID, destPath string) error {
+	if t.debug {
+		t.log.Info(fmt.Sprintf("🔄 Retrieving backup %s from local target", backupID))
+	}
+
+	names, err := ArchiveFileNames(backupID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		// Open the archive using securefs (sandboxed to backup path)
+		srcFile, err := t.sfs.Open(t.resolve(name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errors.New(err).
+				Component("backup").
+				Category(errors.CategoryFileIO).
+				Context("operation", "open_backup_file").
+				Context("backup_id", backupID).
+				Build()
+		}
+
+		err = DownloadToFile(ctx, srcFile, destPath)
+		if closeErr := srcFile.Close(); closeErr != nil {
+			t.log.Debug("local: failed to close backup file", logger.String("file", name), logger.Error(closeErr))
+		}
+		if err != nil {
+			return err
+		}
+
+		if t.debug {
+			t.log.Info(fmt.Sprintf("✅ Successfully retrieved backup %s", name))
+		}
+		return nil
+	}
+
+	return backup.NewError(backup.ErrNotFound, fmt.Sprintf("local: backup %s not found", backupID), nil)
+}
+
 // Validate checks if the target configuration is valid
 func (t *LocalTarget) Validate() error {
 	// Check if path is absolute
@@ -643,7 +693,7 @@ func (t *LocalTarget) Validate() error {
 
 	// Check if path is writable using securefs (sandboxed)
 	testFileName := ".write_test"
-	f, err := t.sfs.OpenFile(testFileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, PermFile)
+	f, err := t.sfs.OpenFile(t.resolve(testFileName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, PermFile)
 	if err != nil {
 		return errors.New(err).
 			Component("backup").
@@ -655,7 +705,7 @@ func (t *LocalTarget) Validate() error {
 	if err := f.Close(); err != nil {
 		t.log.Debug("local: failed to close test file", logger.Error(err))
 	}
-	if err := t.sfs.Remove(testFileName); err != nil {
+	if err := t.sfs.Remove(t.resolve(testFileName)); err != nil {
 		t.log.Debug("local: failed to remove test file", logger.Error(err))
 	}
 
@@ -711,6 +711,76 @@ func (t *RsyncTarget) Delete(ctx context.Context, target string) error {
 	return nil
 }
 
+// Retrieve downloads the archive of a stored backup to destPath using rsync
+func (t *RsyncTarget) Retrieve(ctx context.Context, backupID, destPath string) error {
+	if t.config.Debug {
+		t.log.Info("Rsync: Retrieving backup",
+			logger.String("backup_id", backupID),
+			logger.String("host", t.config.Host))
+	}
+
+	names, err := ArchiveFileNames(backupID)
+	if err != nil {
+		return err
+	}
+
+	cleanBasePath, err := t.sanitizePath(t.config.BasePath)
+	if err != nil {
+		return err
+	}
+
+	sshArgs := []string{
+		"-p", fmt.Sprintf("%d", t.config.Port),
+	}
+	if t.config.KeyFile != "" {
+		sshArgs = append(sshArgs, "-i", t.config.KeyFile)
+	}
+	sshArgs = append(sshArgs, fmt.Sprintf("%s@%s", t.config.Username, t.config.Host))
+
+	for _, name := range names {
+		cleanName, err := t.sanitizePath(name)
+		if err != nil {
+			return err
+		}
+		remotePath := path.Join(cleanBasePath, cleanName)
+
+		// Check if the archive exists under this name
+		checkCmd := exec.CommandContext(ctx, t.sshPath, append(sshArgs, fmt.Sprintf("test -f '%s' && echo exists", remotePath))...) // #nosec G204 -- sshPath validated during initialization, remotePath constructed from sanitized paths
+		if output, err := checkCmd.CombinedOutput(); err != nil || !strings.Contains(string(output), "exists") {
+			continue
+		}
+
+		err = t.withRetry(ctx, func() error {
+			args := []string{
+				"-a",             // Archive mode
+				"--protect-args", // Protect special characters
+				"--timeout=300",  // Connection timeout
+				"-e", t.buildSSHCmd(),
+				fmt.Sprintf("%s@%s:%s", t.config.Username, t.config.Host, remotePath),
+				destPath,
+			}
+
+			// #nosec G204 - rsyncPath is validated during initialization, args are constructed safely
+			cmd := exec.CommandContext(ctx, t.rsyncPath, args...)
+			if err := t.executeCommand(ctx, cmd); err != nil {
+				return backup.NewError(backup.ErrIO, "rsync: download failed", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if t.config.Debug {
+			t.log.Info("Rsync: Successfully retrieved backup",
+				logger.String("file", name))
+		}
+		return nil
+	}
+
+	return backup.NewError(backup.ErrNotFound, fmt.Sprintf("rsync: backup %s not found", backupID), nil)
+}
+
 // Validate checks if the target configuration is valid
 func (t *RsyncTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), backup.DefaultValidateTimeout)
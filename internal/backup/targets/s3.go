@@ -0,0 +1,712 @@
+package targets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/tphakala/birdnet-go/internal/backup"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// S3-specific constants (shared constants imported from common.go)
+const (
+	s3MetadataFileExt   = ".meta"
+	s3DefaultEndpoint   = "s3.amazonaws.com"
+	s3DefaultRegion     = "us-east-1"
+	s3DefaultPartSize   = 16 * 1024 * 1024 // 16MB parts, archives above this use multipart upload
+	s3MinPartSize       = 5 * 1024 * 1024  // S3 rejects multipart parts smaller than 5MB
+	s3MaxMetadataSize   = 1024 * 1024      // Upper bound for metadata sidecar objects
+	s3ValidationObject  = "write_test"
+	s3ContentTypeBinary = "application/octet-stream"
+	s3ContentTypeJSON   = "application/json"
+)
+
+// Server-side encryption modes supported by the S3 target
+const (
+	S3SSENone = ""        // No server-side encryption requested
+	S3SSES3   = "AES256"  // Keys managed by the storage provider (SSE-S3)
+	S3SSEKMS  = "aws:kms" // Keys managed by AWS KMS (SSE-KMS)
+	S3SSEC    = "SSE-C"   // Customer provided key (SSE-C)
+)
+
+// S3TargetConfig holds configuration for the S3 target
+type S3TargetConfig struct {
+	Endpoint        string        // Host[:port] or URL of the S3 API, empty for AWS
+	Region          string        // Bucket region
+	Bucket          string        // Bucket name
+	AccessKeyID     string        // Access key, empty to read credentials from the environment
+	SecretAccessKey string        // Secret key
+	SessionToken    string        // Optional session token for temporary credentials
+	Prefix          string        // Object key prefix, without leading or trailing slash
+	UseSSL          bool          // Use HTTPS when the endpoint has no scheme
+	ForcePathStyle  bool          // Use path-style addressing (required by most MinIO setups)
+	SSE             string        // Server-side encryption mode
+	SSEKMSKeyID     string        // KMS key ID for SSE-KMS
+	SSECustomerKey  string        // Base64 encoded 32 byte key for SSE-C
+	StorageClass    string        // Optional storage class (e.g. STANDARD_IA)
+	PartSize        uint64        // Multipart upload part size in bytes
+	Timeout         time.Duration // Timeout for metadata operations and validation
+	Debug           bool
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	Transport       http.RoundTripper // Optional HTTP transport, e.g. trusting a private CA
+}
+
+// S3Target implements the backup.Target interface for S3-compatible object storage
+// such as AWS S3, MinIO, Backblaze B2 and Wasabi
+type S3Target struct {
+	config S3TargetConfig
+	client *minio.Client
+	sse    encrypt.ServerSide
+	log    logger.Logger
+}
+
+// NewS3Target creates a new S3 target with the given configuration
+func NewS3Target(settings map[string]any, lg logger.Logger) (*S3Target, error) {
+	p := NewSettingsParser(settings)
+
+	config := S3TargetConfig{
+		// Required settings
+		Bucket: p.RequireString("bucket", "s3"),
+
+		// Optional settings with defaults
+		Endpoint:        p.OptionalString("endpoint", ""),
+		Region:          p.OptionalString("region", s3DefaultRegion),
+		AccessKeyID:     p.OptionalString("accesskeyid", ""),
+		SecretAccessKey: p.OptionalString("secretaccesskey", ""),
+		SessionToken:    p.OptionalString("sessiontoken", ""),
+		Prefix:          p.OptionalPath("prefix", false),
+		UseSSL:          p.OptionalBool("usessl", true),
+		ForcePathStyle:  p.OptionalBool("forcepathstyle", false),
+		SSE:             p.OptionalString("sse", S3SSENone),
+		SSEKMSKeyID:     p.OptionalString("ssekmskeyid", ""),
+		SSECustomerKey:  p.OptionalString("ssecustomerkey", ""),
+		StorageClass:    p.OptionalString("storageclass", ""),
+		PartSize:        uint64(max(p.OptionalInt("partsize", 0), 0)), //nolint:gosec // G115 - clamped to non-negative
+		Timeout:         p.OptionalDuration("timeout", DefaultTimeout, "s3"),
+		Debug:           p.OptionalBool("debug", false),
+
+		// Fixed defaults
+		MaxRetries:   DefaultMaxRetries,
+		RetryBackoff: DefaultRetryBackoff,
+	}
+
+	if err := p.Error(); err != nil {
+		return nil, err
+	}
+
+	return NewS3TargetFromConfig(&config, lg)
+}
+
+// NewS3TargetFromConfig creates a new S3 target from a typed configuration
+func NewS3TargetFromConfig(config *S3TargetConfig, lg logger.Logger) (*S3Target, error) {
+	if config.Bucket == "" {
+		return nil, backup.NewError(backup.ErrConfig, "s3: bucket is required", nil)
+	}
+	if (config.AccessKeyID == "") != (config.SecretAccessKey == "") {
+		return nil, backup.NewError(backup.ErrConfig, "s3: accesskeyid and secretaccesskey must be set together", nil)
+	}
+
+	// Set defaults for optional fields
+	if config.Region == "" {
+		config.Region = s3DefaultRegion
+	}
+	if config.PartSize == 0 {
+		config.PartSize = s3DefaultPartSize
+	}
+	if config.PartSize < s3MinPartSize {
+		return nil, backup.NewError(backup.ErrConfig, fmt.Sprintf("s3: partsize must be at least %d bytes", s3MinPartSize), nil)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+
+	config.Prefix = strings.Trim(config.Prefix, "/")
+	if config.Prefix != "" {
+		prefix, err := ValidatePathWithOpts(config.Prefix, PathValidationOpts{
+			AllowHidden:    false,
+			AllowAbsolute:  false,
+			ConvertToSlash: true,
+			ReturnCleaned:  true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		config.Prefix = prefix
+	}
+
+	endpoint, secure, err := parseS3Endpoint(config.Endpoint, config.UseSSL)
+	if err != nil {
+		return nil, err
+	}
+
+	sse, err := newS3ServerSideEncryption(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if lg == nil {
+		lg = logger.Global().Module("backup")
+	}
+
+	lookup := minio.BucketLookupAuto
+	if config.ForcePathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:        s3Credentials(config),
+		Secure:       secure,
+		Region:       config.Region,
+		BucketLookup: lookup,
+		Transport:    config.Transport,
+	})
+	if err != nil {
+		return nil, backup.NewError(backup.ErrConfig, "s3: failed to create client", err)
+	}
+
+	return &S3Target{
+		config: *config,
+		client: client,
+		sse:    sse,
+		log:    lg.Module("s3"),
+	}, nil
+}
+
+// parseS3Endpoint splits an endpoint setting into the host[:port] expected by the
+// client and the TLS flag. A scheme in the endpoint takes precedence over useSSL.
+func parseS3Endpoint(endpoint string, useSSL bool) (host string, secure bool, err error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return s3DefaultEndpoint, true, nil
+	}
+
+	if !strings.Contains(endpoint, "://") {
+		return strings.TrimRight(endpoint, "/"), useSSL, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, backup.NewError(backup.ErrConfig, "s3: invalid endpoint", err)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return "", false, backup.NewError(backup.ErrConfig, "s3: endpoint must not contain a path, use prefix instead", nil)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return u.Host, true, nil
+	case "http":
+		return u.Host, false, nil
+	default:
+		return "", false, backup.NewError(backup.ErrConfig, "s3: unsupported endpoint scheme: "+u.Scheme, nil)
+	}
+}
+
+// s3Credentials returns static credentials when configured, otherwise the
+// standard AWS and MinIO environment variables and credential files are used
+func s3Credentials(config *S3TargetConfig) *credentials.Credentials {
+	if config.AccessKeyID != "" {
+		return credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, config.SessionToken)
+	}
+	return credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+		&credentials.FileAWSCredentials{},
+	})
+}
+
+// newS3ServerSideEncryption builds the server-side encryption option for the configured mode
+func newS3ServerSideEncryption(config *S3TargetConfig) (encrypt.ServerSide, error) {
+	switch strings.ToUpper(config.SSE) {
+	case S3SSENone, "NONE":
+		return nil, nil
+	case S3SSES3, "SSE-S3":
+		return encrypt.NewSSE(), nil
+	case strings.ToUpper(S3SSEKMS), "SSE-KMS":
+		if config.SSEKMSKeyID == "" {
+			return nil, backup.NewError(backup.ErrConfig, "s3: ssekmskeyid is required for SSE-KMS", nil)
+		}
+		sse, err := encrypt.NewSSEKMS(config.SSEKMSKeyID, nil)
+		if err != nil {
+			return nil, backup.NewError(backup.ErrConfig, "s3: invalid SSE-KMS configuration", err)
+		}
+		return sse, nil
+	case S3SSEC:
+		key, err := base64.StdEncoding.DecodeString(config.SSECustomerKey)
+		if err != nil {
+			return nil, backup.NewError(backup.ErrConfig, "s3: ssecustomerkey must be base64 encoded", err)
+		}
+		sse, err := encrypt.NewSSEC(key)
+		if err != nil {
+			return nil, backup.NewError(backup.ErrConfig, "s3: ssecustomerkey must be 32 bytes", err)
+		}
+		return sse, nil
+	default:
+		return nil, backup.NewError(backup.ErrConfig, "s3: unsupported sse mode: "+config.SSE, nil)
+	}
+}
+
+// Name returns the name of this target
+func (t *S3Target) Name() string {
+	return "s3"
+}
+
+// objectKey returns the object key for a file name below the configured prefix
+func (t *S3Target) objectKey(name string) string {
+	if t.config.Prefix == "" {
+		return name
+	}
+	return path.Join(t.config.Prefix, name)
+}
+
+// listPrefix returns the prefix used when listing backup objects
+func (t *S3Target) listPrefix() string {
+	if t.config.Prefix == "" {
+		return ""
+	}
+	return t.config.Prefix + "/"
+}
+
+// readSSE returns the encryption option needed to read objects. Only SSE-C
+// requires the key on reads, SSE-S3 and SSE-KMS are decrypted transparently.
+func (t *S3Target) readSSE() encrypt.ServerSide {
+	if t.sse != nil && t.sse.Type() == encrypt.SSEC {
+		return t.sse
+	}
+	return nil
+}
+
+// isTransientError checks if an error is likely temporary
+func (t *S3Target) isTransientError(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= http.StatusInternalServerError,
+		resp.Code == "SlowDown", resp.Code == "RequestTimeout":
+		return true
+	case resp.StatusCode != 0:
+		return false
+	}
+	return IsTransientError(err)
+}
+
+// isS3NotFound checks if an error reports a missing object
+func isS3NotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.Code == "NoSuchKey" || (resp.StatusCode == http.StatusNotFound && resp.Code != "NoSuchBucket")
+}
+
+// withRetry executes an operation with retry logic. Waiting between attempts
+// ends early when the context is cancelled.
+func (t *S3Target) withRetry(ctx context.Context, operation string, op func() error) error {
+	var lastErr error
+	for attempt := range t.config.MaxRetries {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return t.cancelledError(ctx, operation)
+			case <-time.After(t.config.RetryBackoff * time.Duration(attempt)):
+			}
+		}
+		if ctx.Err() != nil {
+			return t.cancelledError(ctx, operation)
+		}
+
+		err := op()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !t.isTransientError(err) {
+			return t.wrapError(err, operation)
+		}
+
+		if t.config.Debug {
+			t.log.Debug("S3: Retrying operation after error",
+				logger.String("operation", operation),
+				logger.Error(err),
+				logger.Int("attempt", attempt+1),
+				logger.Int("max_retries", t.config.MaxRetries))
+		}
+	}
+
+	return errors.New(lastErr).
+		Component("backup").
+		Category(errors.CategoryNetwork).
+		Context("operation", operation).
+		Context("max_retries", t.config.MaxRetries).
+		Build()
+}
+
+// cancelledError reports an operation stopped by a cancelled context
+func (t *S3Target) cancelledError(ctx context.Context, operation string) error {
+	return errors.New(ctx.Err()).
+		Component("backup").
+		Category(errors.CategorySystem).
+		Context("operation", operation).
+		Context("error_type", "cancelled").
+		Build()
+}
+
+// wrapError converts S3 errors into backup errors
+func (t *S3Target) wrapError(err error, operation string) error {
+	var backupErr *backup.Error
+	if errors.As(err, &backupErr) {
+		return err
+	}
+
+	resp := minio.ToErrorResponse(err)
+	switch {
+	case resp.Code == "NoSuchBucket":
+		return backup.NewError(backup.ErrConfig, fmt.Sprintf("s3: bucket %s does not exist", t.config.Bucket), err)
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden,
+		resp.Code == "InvalidAccessKeyId", resp.Code == "SignatureDoesNotMatch":
+		return backup.NewError(backup.ErrConfig, "s3: access denied, check credentials and bucket policy", err)
+	}
+
+	return errors.New(err).
+		Component("backup").
+		Category(errors.CategoryNetwork).
+		Context("operation", operation).
+		Context("bucket", t.config.Bucket).
+		Build()
+}
+
+// Store implements the backup.Target interface. Archives larger than the part
+// size are uploaded with multipart upload. The metadata object is written after
+// the archive so that List never reports an incomplete backup.
+func (t *S3Target) Store(ctx context.Context, sourcePath string, metadata *backup.Metadata) error {
+	if t.config.Debug {
+		t.log.Debug("S3: Storing backup",
+			logger.String("source_path", sourcePath),
+			logger.String("bucket", t.config.Bucket))
+	}
+
+	// Validate source file
+	srcInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "stat_source_file").
+			Context("source_path", sourcePath).
+			Build()
+	}
+	if srcInfo.Size() > MaxBackupSizeBytes {
+		return errors.Newf("backup file too large: %d bytes (max %d bytes)", srcInfo.Size(), MaxBackupSizeBytes).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_file_size").
+			Context("file_size", srcInfo.Size()).
+			Context("max_size", MaxBackupSizeBytes).
+			Build()
+	}
+
+	// Marshal metadata
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryFileIO).
+			Context("operation", "marshal_metadata").
+			Build()
+	}
+
+	backupKey := t.objectKey(filepath.Base(sourcePath))
+	putOpts := minio.PutObjectOptions{
+		ContentType:          s3ContentTypeBinary,
+		ServerSideEncryption: t.sse,
+		StorageClass:         t.config.StorageClass,
+		PartSize:             t.config.PartSize,
+		UserMetadata:         map[string]string{"backup-id": metadata.ID},
+	}
+
+	start := time.Now()
+	err = t.withRetry(ctx, "upload_backup", func() error {
+		_, err := t.client.FPutObject(ctx, t.config.Bucket, backupKey, sourcePath, putOpts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	metaOpts := putOpts
+	metaOpts.ContentType = s3ContentTypeJSON
+	err = t.withRetry(ctx, "upload_metadata", func() error {
+		_, err := t.client.PutObject(ctx, t.config.Bucket, backupKey+s3MetadataFileExt,
+			strings.NewReader(string(metadataBytes)), int64(len(metadataBytes)), metaOpts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if t.config.Debug {
+		t.log.Debug("S3: Successfully stored backup",
+			logger.String("key", backupKey),
+			logger.Int64("size", srcInfo.Size()),
+			logger.Int64("duration_ms", time.Since(start).Milliseconds()))
+	}
+
+	return nil
+}
+
+// List implements the backup.Target interface
+func (t *S3Target) List(ctx context.Context) ([]backup.BackupInfo, error) {
+	if t.config.Debug {
+		t.log.Debug("S3: Listing backups",
+			logger.String("bucket", t.config.Bucket),
+			logger.String("prefix", t.config.Prefix))
+	}
+
+	// Collect all object keys directly below the prefix
+	var keys map[string]struct{}
+	err := t.withRetry(ctx, "list_backups", func() error {
+		keys = make(map[string]struct{})
+		for object := range t.client.ListObjects(ctx, t.config.Bucket, minio.ListObjectsOptions{
+			Prefix:    t.listPrefix(),
+			Recursive: false,
+		}) {
+			if object.Err != nil {
+				return object.Err
+			}
+			keys[object.Key] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]backup.BackupInfo, 0, len(keys)/2)
+	for key := range keys {
+		if !strings.HasSuffix(key, s3MetadataFileExt) {
+			continue
+		}
+
+		// Skip metadata objects whose archive is missing
+		backupKey := strings.TrimSuffix(key, s3MetadataFileExt)
+		if _, ok := keys[backupKey]; !ok {
+			if t.config.Debug {
+				t.log.Debug("S3: Skipping orphaned metadata object", logger.String("key", key))
+			}
+			continue
+		}
+
+		metadata, err := t.readMetadata(ctx, key)
+		if err != nil {
+			t.log.Warn("S3: Skipping backup with unreadable metadata",
+				logger.String("key", key),
+				logger.Error(err))
+			continue
+		}
+
+		backups = append(backups, backup.BackupInfo{
+			Metadata: *metadata,
+			Target:   t.Name(),
+		})
+	}
+
+	// Sort backups by timestamp (newest first)
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ID > backups[j].ID
+	})
+
+	return backups, nil
+}
+
+// readMetadata downloads and decodes a metadata object
+func (t *S3Target) readMetadata(ctx context.Context, key string) (*backup.Metadata, error) {
+	var metadata backup.Metadata
+	err := t.withRetry(ctx, "read_metadata", func() error {
+		object, err := t.client.GetObject(ctx, t.config.Bucket, key, minio.GetObjectOptions{
+			ServerSideEncryption: t.readSSE(),
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := object.Close(); err != nil && t.config.Debug {
+				t.log.Debug("S3: Failed to close metadata object", logger.String("key", key), logger.Error(err))
+			}
+		}()
+
+		data, err := io.ReadAll(io.LimitReader(object, s3MaxMetadataSize))
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return backup.NewError(backup.ErrCorruption, "s3: invalid metadata in "+key, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// findBackupKey returns the object key of the archive for a backup ID
+func (t *S3Target) findBackupKey(ctx context.Context, backupID string) (string, error) {
+	names, err := ArchiveFileNames(backupID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range names {
+		key := t.objectKey(name)
+		found := false
+		err := t.withRetry(ctx, "stat_backup", func() error {
+			_, err := t.client.StatObject(ctx, t.config.Bucket, key, minio.StatObjectOptions{
+				ServerSideEncryption: t.readSSE(),
+			})
+			switch {
+			case err == nil:
+				found = true
+			case isS3NotFound(err):
+				// Try the next file name
+			default:
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return key, nil
+		}
+	}
+
+	return "", backup.NewError(backup.ErrNotFound, fmt.Sprintf("s3: backup %s not found", backupID), nil)
+}
+
+// Delete implements the backup.Target interface
+func (t *S3Target) Delete(ctx context.Context, backupID string) error {
+	if t.config.Debug {
+		t.log.Debug("S3: Deleting backup",
+			logger.String("backup_id", backupID),
+			logger.String("bucket", t.config.Bucket))
+	}
+
+	backupKey, err := t.findBackupKey(ctx, backupID)
+	if err != nil {
+		return err
+	}
+
+	// Remove the metadata first so a partially deleted backup is no longer listed
+	for _, key := range []string{backupKey + s3MetadataFileExt, backupKey} {
+		err := t.withRetry(ctx, "delete_backup", func() error {
+			return t.client.RemoveObject(ctx, t.config.Bucket, key, minio.RemoveObjectOptions{})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.config.Debug {
+		t.log.Debug("S3: Successfully deleted backup", logger.String("key", backupKey))
+	}
+
+	return nil
+}
+
+// Retrieve downloads the archive of a stored backup to destPath
+func (t *S3Target) Retrieve(ctx context.Context, backupID, destPath string) error {
+	if t.config.Debug {
+		t.log.Debug("S3: Retrieving backup",
+			logger.String("backup_id", backupID),
+			logger.String("bucket", t.config.Bucket))
+	}
+
+	backupKey, err := t.findBackupKey(ctx, backupID)
+	if err != nil {
+		return err
+	}
+
+	return t.withRetry(ctx, "retrieve_backup", func() error {
+		object, err := t.client.GetObject(ctx, t.config.Bucket, backupKey, minio.GetObjectOptions{
+			ServerSideEncryption: t.readSSE(),
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := object.Close(); err != nil && t.config.Debug {
+				t.log.Debug("S3: Failed to close backup object", logger.String("key", backupKey), logger.Error(err))
+			}
+		}()
+
+		return DownloadToFile(ctx, object, destPath)
+	})
+}
+
+// Validate checks that the bucket is reachable and writable
+func (t *S3Target) Validate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
+	defer cancel()
+
+	var exists bool
+	err := t.withRetry(ctx, "validate_bucket", func() error {
+		var err error
+		exists, err = t.client.BucketExists(ctx, t.config.Bucket)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return backup.NewError(backup.ErrConfig, fmt.Sprintf("s3: bucket %s does not exist", t.config.Bucket), nil)
+	}
+
+	// Write and remove a small test object to verify permissions and encryption settings
+	testKey := t.objectKey(s3ValidationObject)
+	testData := "test"
+	err = t.withRetry(ctx, "validate_write", func() error {
+		_, err := t.client.PutObject(ctx, t.config.Bucket, testKey, strings.NewReader(testData), int64(len(testData)),
+			minio.PutObjectOptions{
+				ContentType:          "text/plain",
+				ServerSideEncryption: t.sse,
+				StorageClass:         t.config.StorageClass,
+			})
+		return err
+	})
+	if err != nil {
+		return errors.New(err).
+			Component("backup").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_write_test_object").
+			Build()
+	}
+
+	if err := t.client.RemoveObject(ctx, t.config.Bucket, testKey, minio.RemoveObjectOptions{}); err != nil {
+		t.log.Warn("S3: Failed to delete test object",
+			logger.String("key", testKey),
+			logger.Error(err))
+	}
+
+	return nil
+}
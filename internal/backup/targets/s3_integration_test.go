@@ -0,0 +1,249 @@
+//go:build integration
+
+package targets
+
+// The tests in this file run the S3 target against an in-memory S3 server. They
+// are behind the integration build tag so the server and its dependencies are
+// only built when asked for:
+//
+//	go test -tags integration ./internal/backup/targets/
+
+import (
+	"bytes"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+)
+
+// s3Request is a request received by the fake S3 server
+type s3Request struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+}
+
+// s3RequestRecorder records the requests sent to the fake S3 server
+type s3RequestRecorder struct {
+	mu       sync.Mutex
+	requests []s3Request
+}
+
+func (r *s3RequestRecorder) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.requests = append(r.requests, s3Request{
+			method: req.Method,
+			path:   req.URL.Path,
+			query:  req.URL.RawQuery,
+			header: req.Header.Clone(),
+		})
+		r.mu.Unlock()
+		next.ServeHTTP(w, req)
+	})
+}
+
+// find returns the recorded requests matching a method and a query parameter
+func (r *s3RequestRecorder) find(method, queryParam string) []s3Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []s3Request
+	for _, req := range r.requests {
+		if req.method == method && (queryParam == "" || strings.Contains(req.query, queryParam)) {
+			found = append(found, req)
+		}
+	}
+	return found
+}
+
+// newTestS3Target creates an S3 target backed by an in-memory S3 server
+func newTestS3Target(t *testing.T, configure func(*S3TargetConfig)) (*S3Target, *s3RequestRecorder) {
+	t.Helper()
+
+	s3Backend := s3mem.New()
+	require.NoError(t, s3Backend.CreateBucket(testS3Bucket))
+	recorder := &s3RequestRecorder{}
+	faker := gofakes3.New(s3Backend, gofakes3.WithLogger(gofakes3.DiscardLog()))
+	// TLS keeps the client from signing uploads with aws-chunked encoding, which
+	// the fake server does not decode for multipart parts
+	server := httptest.NewTLSServer(recorder.wrap(faker.Server()))
+	t.Cleanup(server.Close)
+
+	config := &S3TargetConfig{
+		Endpoint:        server.URL,
+		Bucket:          testS3Bucket,
+		AccessKeyID:     "test-access-key",
+		SecretAccessKey: "test-secret-key",
+		Prefix:          "birdnet",
+		ForcePathStyle:  true,
+		PartSize:        s3MinPartSize,
+		RetryBackoff:    time.Millisecond,
+		Transport:       server.Client().Transport,
+	}
+	if configure != nil {
+		configure(config)
+	}
+	target, err := NewS3TargetFromConfig(config, nil)
+	require.NoError(t, err)
+	return target, recorder
+}
+
+// writeTestArchive writes an archive of random data
+func writeTestArchive(t *testing.T, name string, size int) string {
+	t.Helper()
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// storeTestBackup stores an archive of the given size and returns its metadata
+func storeTestBackup(t *testing.T, target *S3Target, id string, size int) (*backup.Metadata, string) {
+	t.Helper()
+	archivePath := writeTestArchive(t, id+".tar", size)
+	metadata := &backup.Metadata{
+		Version:   1,
+		ID:        id,
+		Timestamp: time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+		Size:      int64(size),
+		Source:    "sqlite",
+	}
+	require.NoError(t, target.Store(t.Context(), archivePath, metadata))
+	return metadata, archivePath
+}
+
+// TestS3TargetStoreListRetrieveDelete tests the life cycle of a backup stored with multipart upload
+func TestS3TargetStoreListRetrieveDelete(t *testing.T) {
+	target, recorder := newTestS3Target(t, nil)
+	ctx := t.Context()
+
+	// Archives above the part size are uploaded in parts
+	metadata, archivePath := storeTestBackup(t, target, "sqlite-20250102-030000", s3MinPartSize+1024)
+	assert.Len(t, recorder.find(http.MethodPost, "uploads"), 1, "multipart upload is started")
+	assert.Len(t, recorder.find(http.MethodPut, "partNumber="), 2, "archive is uploaded in two parts")
+
+	// Path-style addressing puts the bucket in the path and the prefix in the key
+	for _, req := range recorder.find(http.MethodPut, "") {
+		assert.True(t, strings.HasPrefix(req.path, "/"+testS3Bucket+"/birdnet/"), req.path)
+	}
+
+	backups, err := target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, metadata.ID, backups[0].ID)
+	assert.Equal(t, metadata.Size, backups[0].Size)
+	assert.Equal(t, "s3", backups[0].Target)
+
+	retrievedPath := filepath.Join(t.TempDir(), "retrieved.tar")
+	require.NoError(t, target.Retrieve(ctx, metadata.ID, retrievedPath))
+	want, err := os.ReadFile(archivePath)
+	require.NoError(t, err)
+	got, err := os.ReadFile(retrievedPath)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(want, got), "retrieved archive differs from the stored one")
+
+	require.NoError(t, target.Delete(ctx, metadata.ID))
+	backups, err = target.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	err = target.Delete(ctx, metadata.ID)
+	assert.True(t, backup.IsErrorCode(err, backup.ErrNotFound), "got %v", err)
+	err = target.Retrieve(ctx, metadata.ID, retrievedPath)
+	assert.True(t, backup.IsErrorCode(err, backup.ErrNotFound), "got %v", err)
+}
+
+// TestS3TargetListMetadataSidecars tests that only archives with a readable metadata sidecar are listed
+func TestS3TargetListMetadataSidecars(t *testing.T) {
+	target, _ := newTestS3Target(t, nil)
+	ctx := t.Context()
+
+	storeTestBackup(t, target, "sqlite-20250101-030000", 1024)
+	storeTestBackup(t, target, "sqlite-20250102-030000", 1024)
+
+	put := func(key, content string) {
+		_, err := target.client.PutObject(ctx, testS3Bucket, key, strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{})
+		require.NoError(t, err)
+	}
+	// A sidecar without its archive, an archive without a sidecar, a damaged sidecar
+	// and an object outside the prefix
+	put("birdnet/sqlite-20250103-030000.tar.meta", `{"id": "sqlite-20250103-030000"}`)
+	put("birdnet/sqlite-20250104-030000.tar", "archive")
+	put("birdnet/sqlite-20250105-030000.tar", "archive")
+	put("birdnet/sqlite-20250105-030000.tar.meta", "not json")
+	put("other/sqlite-20250106-030000.tar", "archive")
+	put("other/sqlite-20250106-030000.tar.meta", `{"id": "sqlite-20250106-030000"}`)
+
+	backups, err := target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "sqlite-20250102-030000", backups[0].ID, "newest first")
+	assert.Equal(t, "sqlite-20250101-030000", backups[1].ID)
+}
+
+// TestS3TargetValidate tests the bucket and write checks
+func TestS3TargetValidate(t *testing.T) {
+	target, recorder := newTestS3Target(t, nil)
+	require.NoError(t, target.Validate())
+	assert.Len(t, recorder.find(http.MethodDelete, ""), 1, "the test object is removed")
+
+	missing, _ := newTestS3Target(t, func(c *S3TargetConfig) { c.Bucket = "missing" })
+	err := missing.Validate()
+	assert.True(t, backup.IsErrorCode(err, backup.ErrConfig), "got %v", err)
+}
+
+// TestS3TargetServerSideEncryption tests that uploads request the configured encryption
+func TestS3TargetServerSideEncryption(t *testing.T) {
+	tests := []struct {
+		name     string
+		sse      string
+		kmsKeyID string
+		key      string
+		headers  map[string]string
+	}{
+		{"none", S3SSENone, "", "", map[string]string{"X-Amz-Server-Side-Encryption": ""}},
+		{"sse-s3", S3SSES3, "", "", map[string]string{"X-Amz-Server-Side-Encryption": "AES256"}},
+		{"sse-c", S3SSEC, "", testS3CustomerKey, map[string]string{
+			"X-Amz-Server-Side-Encryption":                    "",
+			"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+		}},
+		{"sse-kms", S3SSEKMS, "backup-key", "", map[string]string{
+			"X-Amz-Server-Side-Encryption":                "aws:kms",
+			"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "backup-key",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, recorder := newTestS3Target(t, func(c *S3TargetConfig) {
+				c.SSE = tt.sse
+				c.SSEKMSKeyID = tt.kmsKeyID
+				c.SSECustomerKey = tt.key
+			})
+			storeTestBackup(t, target, "sqlite-20250102-030000", 1024)
+
+			puts := recorder.find(http.MethodPut, "")
+			require.Len(t, puts, 2, "archive and metadata sidecar")
+			for _, req := range puts {
+				for header, value := range tt.headers {
+					assert.Equal(t, value, req.header.Get(header), "%s of %s", header, req.path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package targets
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/backup"
+)
+
+const (
+	testS3Bucket      = "backups"
+	testS3CustomerKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=" // 32 bytes, base64 encoded
+)
+
+// TestS3ServerSideEncryptionConfig tests the validation of the encryption settings
+func TestS3ServerSideEncryptionConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  S3TargetConfig
+		wantErr bool
+	}{
+		{"none", S3TargetConfig{SSE: "none"}, false},
+		{"sse-s3", S3TargetConfig{SSE: "sse-s3"}, false},
+		{"sse-kms without key", S3TargetConfig{SSE: S3SSEKMS}, true},
+		{"sse-c", S3TargetConfig{SSE: S3SSEC, SSECustomerKey: testS3CustomerKey}, false},
+		{"sse-c short key", S3TargetConfig{SSE: S3SSEC, SSECustomerKey: "c2hvcnQ="}, true},
+		{"sse-c not base64", S3TargetConfig{SSE: S3SSEC, SSECustomerKey: "not base64!"}, true},
+		{"unknown", S3TargetConfig{SSE: "rot13"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := newS3ServerSideEncryption(&tt.config)
+			if tt.wantErr {
+				assert.True(t, backup.IsErrorCode(err, backup.ErrConfig), "got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestS3TargetWithRetry tests retrying transient errors and stopping on cancellation
+func TestS3TargetWithRetry(t *testing.T) {
+	t.Parallel()
+
+	unavailable := minio.ErrorResponse{StatusCode: http.StatusServiceUnavailable, Code: "ServiceUnavailable"}
+	newTarget := func(backoff time.Duration) *S3Target {
+		return &S3Target{
+			config: S3TargetConfig{Bucket: testS3Bucket, MaxRetries: 3, RetryBackoff: backoff},
+			log:    GetLogger(),
+		}
+	}
+
+	t.Run("transient error", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		err := newTarget(time.Millisecond).withRetry(t.Context(), "test", func() error {
+			attempts++
+			if attempts < 3 {
+				return unavailable
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		t.Parallel()
+		attempts := 0
+		err := newTarget(time.Millisecond).withRetry(t.Context(), "test", func() error {
+			attempts++
+			return minio.ErrorResponse{StatusCode: http.StatusForbidden, Code: "AccessDenied"}
+		})
+		assert.True(t, backup.IsErrorCode(err, backup.ErrConfig), "got %v", err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("cancelled during backoff", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(t.Context())
+		attempts := 0
+		start := time.Now()
+		err := newTarget(time.Hour).withRetry(ctx, "test", func() error {
+			attempts++
+			cancel()
+			return unavailable
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+		assert.Less(t, time.Since(start), time.Minute, "backoff does not outlive the context")
+	})
+}
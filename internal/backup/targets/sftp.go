@@ -670,6 +670,58 @@ func (t *SFTPTarget) Delete(ctx context.Context, target string) error {
 	})
 }
 
+// Retrieve downloads the archive of a stored backup to destPath
+func (t *SFTPTarget) Retrieve(ctx context.Context, backupID, destPath string) error {
+	if t.config.Debug {
+		t.log.Debug("SFTP: Retrieving backup",
+			logger.String("backup_id", backupID),
+			logger.String("host", t.config.Host))
+	}
+
+	names, err := ArchiveFileNames(backupID)
+	if err != nil {
+		return err
+	}
+
+	return t.withRetry(ctx, func(client *sftp.Client) error {
+		for _, name := range names {
+			remotePath := path.Join(t.config.BasePath, name)
+			if err := t.validatePath(remotePath); err != nil {
+				return err
+			}
+
+			file, err := client.Open(remotePath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return errors.New(err).
+					Component("backup").
+					Category(errors.CategoryNetwork).
+					Context("operation", "open_remote_backup").
+					Context("backup_id", backupID).
+					Build()
+			}
+
+			err = DownloadToFile(ctx, file, destPath)
+			if closeErr := file.Close(); closeErr != nil && t.config.Debug {
+				t.log.Debug("SFTP: Failed to close remote file", logger.Error(closeErr))
+			}
+			if err != nil {
+				return err
+			}
+
+			if t.config.Debug {
+				t.log.Debug("SFTP: Successfully retrieved backup",
+					logger.String("file", name))
+			}
+			return nil
+		}
+
+		return backup.NewError(backup.ErrNotFound, fmt.Sprintf("sftp: backup %s not found", backupID), nil)
+	})
+}
+
 // Validate checks if the target configuration is valid
 func (t *SFTPTarget) Validate() error {
 	ctx, cancel := context.WithTimeout(context.Background(), t.config.Timeout)
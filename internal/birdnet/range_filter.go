@@ -85,6 +85,12 @@ func BuildRangeFilter(bn *BirdNET) error {
 // GetProbableSpecies filters and sorts bird species based on their scores.
 // It also updates the scores for species that have custom actions defined in the speciesConfigCSV.
 func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesScore, error) {
+	return bn.GetProbableSpeciesAt(bn.Settings.BirdNET.Latitude, bn.Settings.BirdNET.Longitude, date, week)
+}
+
+// GetProbableSpeciesAt is GetProbableSpecies for the given location instead of the
+// configured one, the settings are left untouched.
+func (bn *BirdNET) GetProbableSpeciesAt(latitude, longitude float64, date time.Time, week float32) ([]SpeciesScore, error) {
 	bn.Debug("Applying range filter")
 
 	// Skip filtering if range interpreter is not initialized
@@ -94,13 +100,13 @@ func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesSc
 	}
 
 	// Skip filtering if location is not set
-	if bn.Settings.BirdNET.Latitude == 0 && bn.Settings.BirdNET.Longitude == 0 {
+	if latitude == 0 && longitude == 0 {
 		bn.Debug("Latitude and longitude not set, not using location based prediction filter")
 		return zeroScoresForAllLabels(bn.Settings.BirdNET.Labels), nil
 	}
 
 	// Apply prediction filter based on the context
-	filters, err := bn.predictFilter(date, week)
+	filters, err := bn.predictFilter(latitude, longitude, date, week)
 	if err != nil {
 		return nil, errors.New(err).
 			Category(errors.CategoryValidation).
@@ -200,7 +206,7 @@ func matchesSpecies(label, speciesName string) bool {
 }
 
 // predictFilter applies a TensorFlow Lite model to predict species based on the context.
-func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error) {
+func (bn *BirdNET) predictFilter(latitude, longitude float64, date time.Time, week float32) ([]Filter, error) {
 	start := time.Now()
 
 	input := bn.RangeInterpreter.GetInputTensor(0)
@@ -218,7 +224,7 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	}
 
 	// Prepare the input data
-	data := []float32{float32(bn.Settings.BirdNET.Latitude), float32(bn.Settings.BirdNET.Longitude), week}
+	data := []float32{float32(latitude), float32(longitude), week}
 
 	// Retrieve the input tensor's underlying data slice
 	float32s := input.Float32s()
@@ -242,8 +248,8 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 			Category(errors.CategoryModelInit).
 			Context("model_type", "range_filter").
 			Context("status_code", status).
-			Context("latitude", bn.Settings.BirdNET.Latitude).
-			Context("longitude", bn.Settings.BirdNET.Longitude).
+			Context("latitude", latitude).
+			Context("longitude", longitude).
 			Context("week", week).
 			Timing("range-filter-invoke", time.Since(start)).
 			Build()
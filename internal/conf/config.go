@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
@@ -81,10 +82,18 @@ type RetentionSettings struct {
 // AudioSettings contains settings for audio processing and export.
 // SoundLevelSettings contains settings for sound level monitoring
 type SoundLevelSettings struct {
-	Enabled              bool `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                                            // true to enable sound level monitoring
-	Interval             int  `yaml:"interval" mapstructure:"interval" json:"interval"`                                         // measurement interval in seconds (default: 10)
-	Debug                bool `yaml:"debug" mapstructure:"debug" json:"debug"`                                                  // true to enable debug logging for sound level monitoring
-	DebugRealtimeLogging bool `yaml:"debug_realtime_logging" mapstructure:"debug_realtime_logging" json:"debugRealtimeLogging"` // true to log debug messages for every realtime update, false to log only at configured interval
+	Enabled              bool                      `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                                            // true to enable sound level monitoring
+	Interval             int                       `yaml:"interval" mapstructure:"interval" json:"interval"`                                         // measurement interval in seconds (default: 10)
+	Debug                bool                      `yaml:"debug" mapstructure:"debug" json:"debug"`                                                  // true to enable debug logging for sound level monitoring
+	DebugRealtimeLogging bool                      `yaml:"debug_realtime_logging" mapstructure:"debug_realtime_logging" json:"debugRealtimeLogging"` // true to log debug messages for every realtime update, false to log only at configured interval
+	Storage              SoundLevelStorageSettings `yaml:"storage" mapstructure:"storage" json:"storage"`                                            // saving of sound levels in the database
+}
+
+// SoundLevelStorageSettings contains settings for saving sound levels in the database
+type SoundLevelStorageSettings struct {
+	Enabled   bool `yaml:"enabled" mapstructure:"enabled" json:"enabled"`       // true to save sound levels in the database
+	Interval  int  `yaml:"interval" mapstructure:"interval" json:"interval"`    // seconds of measurements combined into one saved sound level (default: 60)
+	Retention int  `yaml:"retention" mapstructure:"retention" json:"retention"` // days saved sound levels are kept, 0 to keep them forever (default: 30)
 }
 
 type AudioSettings struct {
@@ -210,6 +219,18 @@ type DynamicThresholdSettings struct {
 	ValidHours int     `json:"validHours"` // number of hours to consider for dynamic threshold
 }
 
+// ReviewSuppressionSettings contains settings for suppressing detections that reviews
+// repeatedly marked as false positives for the same source and time of day.
+type ReviewSuppressionSettings struct {
+	Enabled              bool    `json:"enabled"`              // true to suppress detections learned from false positive reviews
+	Action               string  `json:"action"`               // "raise" to raise the threshold above past false positives, "hold" to hold back all matching detections
+	MinReviews           int     `json:"minReviews"`           // reviews of a species, source and time of day needed to learn from them
+	MinFalsePositiveRate float64 `json:"minFalsePositiveRate"` // share of false positive reviews that triggers suppression
+	HourBucket           int     `json:"hourBucket"`           // hours of the day learned together, 1-24
+	LookbackDays         int     `json:"lookbackDays"`         // days of reviews to learn from, 0 for all reviews
+	RefreshMinutes       int     `json:"refreshMinutes"`       // minutes between relearning from reviews
+}
+
 // RetrySettings contains common settings for retry mechanisms
 type RetrySettings struct {
 	Enabled           bool    `json:"enabled"`           // true to enable retry mechanism
@@ -239,19 +260,64 @@ type EBirdSettings struct {
 
 // WeatherSettings contains all weather-related settings
 type WeatherSettings struct {
-	Provider     string               `json:"provider"`     // "none", "yrno", "openweather", or "wunderground"
+	Provider     string               `json:"provider"`     // "none", "yrno", "openweather", "wunderground", "openmeteo", "localstation" or "mqtt"
 	PollInterval int                  `json:"pollInterval"` // weather data polling interval in minutes
 	Debug        bool                 `json:"debug"`        // true to enable debug mode
 	OpenWeather  OpenWeatherSettings  `json:"openWeather"`  // OpenWeather integration settings
 	Wunderground WundergroundSettings `json:"wunderground"` // WeatherUnderground integration settings
+	OpenMeteo    OpenMeteoSettings    `json:"openMeteo"`    // Open-Meteo integration settings
+	LocalStation LocalStationSettings `json:"localStation"` // weather station pushing readings to BirdNET-Go
+	MQTT         WeatherMQTTSettings  `json:"mqtt"`         // weather readings subscribed from MQTT topics
+}
+
+// OpenMeteoSettings contains settings for the Open-Meteo integration, which needs no API key
+type OpenMeteoSettings struct {
+	Endpoint        string `json:"endpoint"`        // Open-Meteo forecast API endpoint for current weather
+	ArchiveEndpoint string `json:"archiveEndpoint"` // Open-Meteo historical weather API endpoint
+	Backfill        bool   `json:"backfill"`        // true to fill in missing weather of past detection dates
+}
+
+// LocalStationSettings contains settings for a local weather station pushing readings
+// in Ecowitt, Ambient Weather, Wunderground or WeeWX format
+type LocalStationSettings struct {
+	Token  string `json:"token"`  // secret part of the push URL /api/v2/weather/station/<token>
+	MaxAge int    `json:"maxAge"` // minutes after which the last pushed reading is stale
+}
+
+// WeatherMQTTSettings contains settings for reading weather values from MQTT topics.
+// The broker connection settings of realtime.mqtt are used.
+type WeatherMQTTSettings struct {
+	Units  string            `json:"units"`  // "metric" (°C, m/s, hPa, mm/h) or "imperial" (°F, mph, inHg, in/h)
+	MaxAge int               `json:"maxAge"` // minutes after which a topic value is stale
+	Topics WeatherMQTTTopics `json:"topics"` // topics publishing one numeric value each
+}
+
+// WeatherMQTTTopics contains the topics of the weather values, empty topics are not read
+type WeatherMQTTTopics struct {
+	Temperature    string `json:"temperature"`    // required
+	Humidity       string `json:"humidity"`       // relative humidity in percent
+	Pressure       string `json:"pressure"`       // sea level pressure
+	WindSpeed      string `json:"windSpeed"`      // average wind speed
+	WindGust       string `json:"windGust"`       // wind gust speed
+	WindDirection  string `json:"windDirection"`  // wind direction in degrees
+	RainRate       string `json:"rainRate"`       // precipitation rate
+	SolarRadiation string `json:"solarRadiation"` // solar radiation in W/m²
 }
 
 // ---------------- Notification push configuration -----------------
 
 // NotificationConfig is the root for notification-specific settings.
 type NotificationConfig struct {
-	Push      PushSettings          `json:"push" yaml:"push"`
-	Templates NotificationTemplates `json:"templates" yaml:"templates"`
+	Push      PushSettings              `json:"push" yaml:"push"`
+	Templates NotificationTemplates     `json:"templates" yaml:"templates"`
+	Store     NotificationStoreSettings `json:"store" yaml:"store"`
+}
+
+// NotificationStoreSettings controls how notification history is kept.
+type NotificationStoreSettings struct {
+	Persist       bool `json:"persist" yaml:"persist"`             // Keep notifications in the database across restarts
+	RetentionDays int  `json:"retentionDays" yaml:"retentiondays"` // Days to keep notifications, 0 keeps them until they expire
+	MaxEntries    int  `json:"maxEntries" yaml:"maxentries"`       // Maximum number of stored notifications, 0 for no limit
 }
 
 // NotificationTemplates contains customizable notification message templates.
@@ -403,25 +469,60 @@ type RTSPHealthSettings struct {
 	MonitoringInterval   int `json:"monitoringInterval"`   // health check interval in seconds (default: 30)
 }
 
+// RTSPStreamSpeciesSettings contains the species filters of a single RTSP stream.
+type RTSPStreamSpeciesSettings struct {
+	Include []string `json:"include"` // when set, only these species are reported for the stream
+	Exclude []string `json:"exclude"` // species never reported for the stream
+}
+
+// RTSPStreamSettings contains the configuration of a single named RTSP stream.
+type RTSPStreamSettings struct {
+	ID        string                    `json:"id"`        // stable source ID, stored with the detections of the stream
+	Name      string                    `json:"name"`      // display name, defaults to the ID
+	URL       string                    `json:"url"`       // RTSP stream URL
+	Enabled   *bool                     `json:"enabled"`   // false to keep the stream configured but not captured, enabled when unset
+	Transport string                    `json:"transport"` // RTSP transport protocol, defaults to the global transport
+	Gain      float64                   `json:"gain"`      // gain in dB applied to the stream audio
+	Equalizer EqualizerSettings         `json:"equalizer"` // equalizer applied to the stream audio
+	Species   RTSPStreamSpeciesSettings `json:"species"`   // per-stream species include and exclude lists
+}
+
 // RTSPSettings contains settings for RTSP streaming.
 type RTSPSettings struct {
-	Transport        string             `json:"transport"`        // RTSP Transport Protocol
-	URLs             []string           `json:"urls"`             // RTSP stream URL
-	Health           RTSPHealthSettings `json:"health"`           // health monitoring settings
-	FFmpegParameters []string           `json:"ffmpegParameters"` // optional custom FFmpeg parameters
+	Transport        string               `json:"transport"`        // RTSP Transport Protocol
+	URLs             []string             `json:"urls"`             // RTSP stream URL, legacy unnamed streams
+	Streams          []RTSPStreamSettings `json:"streams"`          // named RTSP streams with per-stream settings
+	Health           RTSPHealthSettings   `json:"health"`           // health monitoring settings
+	FFmpegParameters []string             `json:"ffmpegParameters"` // optional custom FFmpeg parameters
 }
 
 // MQTTSettings contains settings for MQTT integration.
 type MQTTSettings struct {
-	Enabled       bool            `json:"enabled"`       // true to enable MQTT
-	Debug         bool            `json:"debug"`         // true to enable MQTT debug
-	Broker        string          `json:"broker"`        // MQTT broker URL
-	Topic         string          `json:"topic"`         // MQTT topic
-	Username      string          `json:"username"`      // MQTT username
-	Password      string          `json:"password"`      // MQTT password
-	Retain        bool            `json:"retain"`        // true to retain messages
-	RetrySettings RetrySettings   `json:"retrySettings"` // settings for retry mechanism
-	TLS           MQTTTLSSettings `json:"tls"`           // TLS/SSL configuration
+	Enabled       bool                  `json:"enabled"`       // true to enable MQTT
+	Debug         bool                  `json:"debug"`         // true to enable MQTT debug
+	Broker        string                `json:"broker"`        // MQTT broker URL
+	Topic         string                `json:"topic"`         // MQTT topic
+	Username      string                `json:"username"`      // MQTT username
+	Password      string                `json:"password"`      // MQTT password
+	Retain        bool                  `json:"retain"`        // true to retain messages
+	RetrySettings RetrySettings         `json:"retrySettings"` // settings for retry mechanism
+	TLS           MQTTTLSSettings       `json:"tls"`           // TLS/SSL configuration
+	HomeAssistant HomeAssistantSettings `json:"homeAssistant"` // Home Assistant MQTT discovery
+	Commands      MQTTCommandSettings   `json:"commands"`      // remote control through a subscribed command topic
+}
+
+// MQTTCommandSettings contains settings for remote control commands received over MQTT
+type MQTTCommandSettings struct {
+	Enabled bool   `json:"enabled"` // true to subscribe to the command topic
+	Topic   string `json:"topic"`   // command topic, empty for <topic>/command
+	Token   string `json:"token"`   // shared secret every command must include
+}
+
+// HomeAssistantSettings contains settings for Home Assistant MQTT discovery
+type HomeAssistantSettings struct {
+	Enabled         bool   `json:"enabled"`         // true to publish Home Assistant discovery configs
+	DiscoveryPrefix string `json:"discoveryPrefix"` // discovery prefix configured in Home Assistant
+	StateInterval   int    `json:"stateInterval"`   // seconds between detection count, stream health and system state updates
 }
 
 // MQTTTLSSettings contains TLS/SSL configuration for secure MQTT connections
@@ -495,6 +596,7 @@ type RealtimeSettings struct {
 	Dashboard           Dashboard                   `json:"dashboard"`           // Dashboard settings
 	DynamicThreshold    DynamicThresholdSettings    `json:"dynamicThreshold"`    // Dynamic threshold settings
 	FalsePositiveFilter FalsePositiveFilterSettings `json:"falsePositiveFilter"` // False positive filtering aggressivity settings
+	ReviewSuppression   ReviewSuppressionSettings   `json:"reviewSuppression"`   // False positive suppression learned from reviews
 	Log                 struct {
 		Enabled bool   `json:"enabled"` // true to enable OBS chat log
 		Path    string `json:"path"`    // path to OBS chat log
@@ -516,10 +618,14 @@ type RealtimeSettings struct {
 
 // SpeciesAction represents a single action configuration
 type SpeciesAction struct {
-	Type            string   `yaml:"type" json:"type"`                       // Type of action (ExecuteCommand, etc)
-	Command         string   `yaml:"command" json:"command"`                 // Path to the command to execute
-	Parameters      []string `yaml:"parameters" json:"parameters"`           // Action parameters
-	ExecuteDefaults bool     `yaml:"executeDefaults" json:"executeDefaults"` // Whether to also execute default actions
+	Type            string   `yaml:"type" json:"type"`                                           // Type of action (ExecuteCommand, SendNotification)
+	Command         string   `yaml:"command" json:"command"`                                     // Path to the command to execute
+	Parameters      []string `yaml:"parameters" json:"parameters"`                               // Action parameters
+	ExecuteDefaults bool     `yaml:"executeDefaults" json:"executeDefaults"`                     // Whether to also execute default actions
+	Title           string   `yaml:"title,omitempty" json:"title,omitempty"`                     // Notification title template (SendNotification)
+	Message         string   `yaml:"message,omitempty" json:"message,omitempty"`                 // Notification message template (SendNotification)
+	Providers       []string `yaml:"providers,omitempty" json:"providers,omitempty"`             // Push providers to notify, empty for all (SendNotification)
+	CooldownMinutes int      `yaml:"cooldownMinutes,omitempty" json:"cooldownMinutes,omitempty"` // Minimum minutes between notifications, 0 uses the species interval (SendNotification)
 }
 
 // SpeciesConfig represents configuration for a specific species
@@ -1055,8 +1161,15 @@ type S3BackupSettings struct {
 	Bucket          string `yaml:"bucket"`          // S3 bucket name
 	AccessKeyID     string `yaml:"accesskeyid"`     // AWS access key ID
 	SecretAccessKey string `yaml:"secretaccesskey"` // AWS secret access key
+	SessionToken    string `yaml:"sessiontoken"`    // Session token for temporary credentials (optional)
 	Prefix          string `yaml:"prefix"`          // Object key prefix
 	UseSSL          bool   `yaml:"usessl"`          // Use SSL/TLS (default: true)
+	ForcePathStyle  bool   `yaml:"forcepathstyle"`  // Use path-style addressing, required by most MinIO setups
+	SSE             string `yaml:"sse"`             // Server-side encryption: "", "AES256", "aws:kms" or "SSE-C"
+	SSEKMSKeyID     string `yaml:"ssekmskeyid"`     // KMS key ID when SSE is "aws:kms"
+	SSECustomerKey  string `yaml:"ssecustomerkey"`  // Base64 encoded 32 byte key when SSE is "SSE-C"
+	StorageClass    string `yaml:"storageclass"`    // Storage class for uploaded objects (optional)
+	PartSize        int    `yaml:"partsize"`        // Multipart upload part size in bytes (default: 16MB, minimum 5MB)
 }
 
 // Validate validates S3 backup settings
@@ -1121,6 +1234,13 @@ type BackupScheduleConfig struct {
 	IsWeekly bool   `yaml:"isweekly" json:"isWeekly"` // If true, this schedule is weekly (runs on the specified Weekday at Hour:Minute). If false, it's a daily schedule (runs every day at Hour:Minute). (Valid: true or false)
 }
 
+// BackupClipsConfig defines the incremental backup of exported audio clips
+type BackupClipsConfig struct {
+	Enabled          bool `yaml:"enabled" json:"enabled"`                     // If true, clips and spectrograms added to the audio export path since the last successful run are backed up.
+	MaxRunSizeMB     int  `yaml:"max_run_size_mb" json:"maxRunSizeMb"`        // Upper bound for the clips included in a single run, remaining clips are picked up by the next run. Default: 1024.
+	FullIntervalDays int  `yaml:"full_interval_days" json:"fullIntervalDays"` // Days between full clip backups, older clip backups are deleted once a newer full backup is complete. Default: 30.
+}
+
 // BackupConfig contains backup-related configuration
 type BackupConfig struct {
 	Enabled        bool                   `yaml:"enabled" json:"enabled"`                // Global flag to enable or disable the entire backup system. If false, no backups (manual or scheduled) will occur.
@@ -1131,6 +1251,7 @@ type BackupConfig struct {
 	Retention      BackupRetention        `yaml:"retention" json:"retention"`            // Defines policies for how long and how many backups are kept.
 	Targets        []BackupTarget         `yaml:"targets" json:"targets"`                // A list of configured backup targets (destinations) where backup archives will be stored.
 	Schedules      []BackupScheduleConfig `yaml:"schedules" json:"schedules"`            // A list of schedules (e.g., daily, weekly) that define when automatic backups should run.
+	Clips          BackupClipsConfig      `yaml:"clips" json:"clips"`                    // Incremental backup of exported audio clips and their spectrograms.
 
 	// OperationTimeouts defines timeouts for various backup operations
 	OperationTimeouts struct {
@@ -1172,7 +1293,7 @@ type Settings struct {
 		File struct {
 			Enabled bool   `yaml:"-" json:"-"` // true to enable file output
 			Path    string `yaml:"-" json:"-"` // directory to output results
-			Type    string `yaml:"-" json:"-"` // table, csv
+			Type    string `yaml:"-" json:"-"` // table, csv, raven, audacity, birdnet-csv, json
 		} `json:"file"`
 
 		SQLite struct {
@@ -1832,6 +1953,9 @@ const (
 	WeatherYrNo         WeatherProvider = "yrno"
 	WeatherOpenWeather  WeatherProvider = "openweather"
 	WeatherWunderground WeatherProvider = "wunderground"
+	WeatherOpenMeteo    WeatherProvider = "openmeteo"
+	WeatherLocalStation WeatherProvider = "localstation"
+	WeatherMQTT         WeatherProvider = "mqtt"
 )
 
 // Prefer explicit settings return to avoid confusion at call sites.
@@ -1842,6 +1966,12 @@ func (s *Settings) GetWeatherProvider() (provider WeatherProvider, settings any)
 		return WeatherOpenWeather, s.Realtime.Weather.OpenWeather
 	case string(WeatherWunderground):
 		return WeatherWunderground, s.Realtime.Weather.Wunderground
+	case string(WeatherOpenMeteo):
+		return WeatherOpenMeteo, s.Realtime.Weather.OpenMeteo
+	case string(WeatherLocalStation):
+		return WeatherLocalStation, s.Realtime.Weather.LocalStation
+	case string(WeatherMQTT):
+		return WeatherMQTT, s.Realtime.Weather.MQTT
 	case string(WeatherYrNo), string(WeatherNone):
 		return WeatherProvider(p), nil
 	default:
@@ -1853,6 +1983,62 @@ func (s *Settings) GetWeatherProvider() (provider WeatherProvider, settings any)
 	}
 }
 
+// ValidateOpenMeteo validates Open-Meteo settings when the provider is "openmeteo"
+func (o *OpenMeteoSettings) ValidateOpenMeteo() error {
+	endpoints := []struct{ name, value string }{
+		{"endpoint", o.Endpoint},
+		{"archiveEndpoint", o.ArchiveEndpoint},
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint.value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("openMeteo.%s must be an http or https URL, got: %s", endpoint.name, endpoint.value)
+		}
+	}
+	return nil
+}
+
+// ValidateLocalStation validates local weather station settings when the provider is "localstation"
+func (l *LocalStationSettings) ValidateLocalStation() error {
+	if len(l.Token) < MinWeatherStationTokenLength {
+		return fmt.Errorf("localStation.token must be at least %d characters when provider is localstation", MinWeatherStationTokenLength)
+	}
+	if l.MaxAge < 1 {
+		return fmt.Errorf("localStation.maxAge must be at least 1 minute, got: %d", l.MaxAge)
+	}
+	return nil
+}
+
+// ValidateWeatherMQTT validates MQTT weather settings when the provider is "mqtt"
+func (m *WeatherMQTTSettings) ValidateWeatherMQTT() error {
+	if m.Topics.Temperature == "" {
+		return fmt.Errorf("mqtt.topics.temperature is required when provider is mqtt")
+	}
+	if m.Units != "metric" && m.Units != "imperial" {
+		return fmt.Errorf("mqtt.units must be one of [metric, imperial], got: %s", m.Units)
+	}
+	if m.MaxAge < 1 {
+		return fmt.Errorf("mqtt.maxAge must be at least 1 minute, got: %d", m.MaxAge)
+	}
+	for _, topic := range m.Topics.List() {
+		if strings.ContainsAny(topic, "#+") {
+			return fmt.Errorf("mqtt weather topic %q must not contain wildcards", topic)
+		}
+	}
+	return nil
+}
+
+// List returns the configured topics
+func (t *WeatherMQTTTopics) List() []string {
+	var topics []string
+	for _, topic := range []string{t.Temperature, t.Humidity, t.Pressure, t.WindSpeed, t.WindGust, t.WindDirection, t.RainRate, t.SolarRadiation} {
+		if topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
 // ValidateWunderground validates Wunderground settings when the provider is "wunderground"
 func (w *WundergroundSettings) ValidateWunderground() error {
 	// Validate required fields when provider is "wunderground"
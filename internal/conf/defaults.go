@@ -89,6 +89,9 @@ func setDefaultConfig() {
 	// Sound level monitoring configuration
 	viper.SetDefault("realtime.audio.soundlevel.enabled", false)
 	viper.SetDefault("realtime.audio.soundlevel.interval", 10)
+	viper.SetDefault("realtime.audio.soundlevel.storage.enabled", false)
+	viper.SetDefault("realtime.audio.soundlevel.storage.interval", 60)
+	viper.SetDefault("realtime.audio.soundlevel.storage.retention", 30)
 
 	// Audio capture configuration
 	viper.SetDefault("realtime.audio.export.debug", false)
@@ -156,6 +159,15 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.dynamicthreshold.min", 0.20)
 	viper.SetDefault("realtime.dynamicthreshold.validhours", 24)
 
+	// False positive suppression learned from reviews
+	viper.SetDefault("realtime.reviewsuppression.enabled", false)
+	viper.SetDefault("realtime.reviewsuppression.action", "raise")
+	viper.SetDefault("realtime.reviewsuppression.minreviews", 5)
+	viper.SetDefault("realtime.reviewsuppression.minfalsepositiverate", 0.8)
+	viper.SetDefault("realtime.reviewsuppression.hourbucket", 3)
+	viper.SetDefault("realtime.reviewsuppression.lookbackdays", 365)
+	viper.SetDefault("realtime.reviewsuppression.refreshminutes", 60)
+
 	// False positive filter configuration
 	// Level 0 = Off (no filtering, backward compatible default)
 	// Level 1 = Lenient, Level 2 = Moderate, Level 3 = Balanced (original behavior)
@@ -212,8 +224,30 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.weather.wunderground.endpoint", "https://api.weather.com/v2/pws/observations/current")
 	viper.SetDefault("realtime.weather.wunderground.units", "m") // m=metric, e=imperial, h=UK hybrid
 
+	// Open-Meteo specific configuration
+	viper.SetDefault("realtime.weather.openmeteo.endpoint", "https://api.open-meteo.com/v1/forecast")
+	viper.SetDefault("realtime.weather.openmeteo.archiveendpoint", "https://archive-api.open-meteo.com/v1/archive")
+	viper.SetDefault("realtime.weather.openmeteo.backfill", true)
+
+	// Local weather station push configuration
+	viper.SetDefault("realtime.weather.localstation.token", "")
+	viper.SetDefault("realtime.weather.localstation.maxage", 15)
+
+	// MQTT weather configuration
+	viper.SetDefault("realtime.weather.mqtt.units", "metric")
+	viper.SetDefault("realtime.weather.mqtt.maxage", 15)
+	viper.SetDefault("realtime.weather.mqtt.topics.temperature", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.humidity", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.pressure", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.windspeed", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.windgust", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.winddirection", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.rainrate", "")
+	viper.SetDefault("realtime.weather.mqtt.topics.solarradiation", "")
+
 	// RTSP configuration
 	viper.SetDefault("realtime.rtsp.urls", []string{})
+	viper.SetDefault("realtime.rtsp.streams", []map[string]any{})
 	viper.SetDefault("realtime.rtsp.transport", "tcp")
 	viper.SetDefault("realtime.rtsp.health.healthydatathreshold", 60)
 	viper.SetDefault("realtime.rtsp.health.monitoringinterval", 30)
@@ -232,6 +266,12 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.mqtt.retrysettings.initialdelay", 30)
 	viper.SetDefault("realtime.mqtt.retrysettings.maxdelay", 3600)
 	viper.SetDefault("realtime.mqtt.retrysettings.backoffmultiplier", 2.0)
+	viper.SetDefault("realtime.mqtt.homeassistant.enabled", false)
+	viper.SetDefault("realtime.mqtt.homeassistant.discoveryprefix", "homeassistant")
+	viper.SetDefault("realtime.mqtt.homeassistant.stateinterval", 60)
+	viper.SetDefault("realtime.mqtt.commands.enabled", false)
+	viper.SetDefault("realtime.mqtt.commands.topic", "")
+	viper.SetDefault("realtime.mqtt.commands.token", "")
 
 	// Privacy filter configuration
 	viper.SetDefault("realtime.privacyfilter.enabled", true)
@@ -385,6 +425,11 @@ func setDefaultConfig() {
 
 	viper.SetDefault("notification.push.providers", []map[string]any{})
 
+	// Notification history storage
+	viper.SetDefault("notification.store.persist", true)
+	viper.SetDefault("notification.store.retentiondays", 30)
+	viper.SetDefault("notification.store.maxentries", 1000)
+
 	// Notification templates
 	viper.SetDefault("notification.templates.newspecies.title", "New Species: {{.CommonName}}")
 	viper.SetDefault("notification.templates.newspecies.message", "{{.ImageURL}}\n\nFirst detection of {{.CommonName}} ({{.ScientificName}}) with {{.ConfidencePercent}}% confidence at {{.DetectionTime}}. \n{{.DetectionURL}}")
@@ -609,6 +609,32 @@ func TestValidateMQTTSettings_Valid(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with Home Assistant discovery",
+			settings: MQTTSettings{
+				Enabled: true,
+				Broker:  "tcp://localhost:1883",
+				Topic:   "birdnet",
+				HomeAssistant: HomeAssistantSettings{
+					Enabled:         true,
+					DiscoveryPrefix: "homeassistant",
+					StateInterval:   60,
+				},
+			},
+		},
+		{
+			name: "with command subscription",
+			settings: MQTTSettings{
+				Enabled: true,
+				Broker:  "tcp://localhost:1883",
+				Topic:   "birdnet",
+				Commands: MQTTCommandSettings{
+					Enabled: true,
+					Topic:   "birdnet/station1/command",
+					Token:   "0123456789abcdef",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -687,6 +713,61 @@ func TestValidateMQTTSettings_Invalid(t *testing.T) {
 			},
 			expectError: "max delay must be greater than or equal to initial delay",
 		},
+		{
+			name: "Home Assistant discovery prefix with wildcard",
+			settings: MQTTSettings{
+				Enabled: true,
+				Broker:  "tcp://localhost:1883",
+				Topic:   "test",
+				HomeAssistant: HomeAssistantSettings{
+					Enabled:         true,
+					DiscoveryPrefix: "homeassistant/#",
+					StateInterval:   60,
+				},
+			},
+			expectError: "discovery prefix must be a topic without wildcards",
+		},
+		{
+			name: "Home Assistant state interval too short",
+			settings: MQTTSettings{
+				Enabled: true,
+				Broker:  "tcp://localhost:1883",
+				Topic:   "test",
+				HomeAssistant: HomeAssistantSettings{
+					Enabled:         true,
+					DiscoveryPrefix: "homeassistant",
+					StateInterval:   1,
+				},
+			},
+			expectError: "state interval must be at least 10 seconds",
+		},
+		{
+			name: "command subscription without token",
+			settings: MQTTSettings{
+				Enabled: true,
+				Broker:  "tcp://localhost:1883",
+				Topic:   "test",
+				Commands: MQTTCommandSettings{
+					Enabled: true,
+					Token:   "short",
+				},
+			},
+			expectError: "command token must be at least 16 characters",
+		},
+		{
+			name: "command topic with wildcard",
+			settings: MQTTSettings{
+				Enabled: true,
+				Broker:  "tcp://localhost:1883",
+				Topic:   "test",
+				Commands: MQTTCommandSettings{
+					Enabled: true,
+					Topic:   "test/+/command",
+					Token:   "0123456789abcdef",
+				},
+			},
+			expectError: "command topic must be a topic without wildcards",
+		},
 	}
 
 	for _, tt := range tests {
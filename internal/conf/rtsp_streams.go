@@ -0,0 +1,98 @@
+package conf
+
+import (
+	"slices"
+	"strings"
+)
+
+// IsEnabled returns true unless the stream has been explicitly disabled
+func (s *RTSPStreamSettings) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// DisplayName returns the name of the stream, or its ID when no name is set
+func (s *RTSPStreamSettings) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.ID
+}
+
+// IsSpeciesAllowed reports whether detections of a species are reported for the stream.
+// The species is a BirdNET label such as "Turdus merula_Eurasian Blackbird", list entries
+// match the full label, the scientific name or the common name, case-insensitively.
+func (s *RTSPStreamSettings) IsSpeciesAllowed(species string) bool {
+	if len(s.Species.Include) == 0 && len(s.Species.Exclude) == 0 {
+		return true
+	}
+
+	label := strings.ToLower(species)
+	scientific, common, _ := strings.Cut(label, "_")
+	matches := func(entry string) bool {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		return entry == label || entry == scientific || entry == common
+	}
+
+	if slices.ContainsFunc(s.Species.Exclude, matches) {
+		return false
+	}
+	return len(s.Species.Include) == 0 || slices.ContainsFunc(s.Species.Include, matches)
+}
+
+// ActiveStreams returns the streams to capture: the enabled named streams followed
+// by the legacy URLs which are not also configured as named streams. The transport
+// of every returned stream is resolved, legacy URLs use the global transport.
+func (r *RTSPSettings) ActiveStreams() []RTSPStreamSettings {
+	streams := make([]RTSPStreamSettings, 0, len(r.Streams)+len(r.URLs))
+	for i := range r.Streams {
+		stream := r.Streams[i]
+		if !stream.IsEnabled() || stream.URL == "" {
+			continue
+		}
+		if stream.Transport == "" {
+			stream.Transport = r.Transport
+		}
+		streams = append(streams, stream)
+	}
+
+	for _, url := range r.URLs {
+		if url == "" || slices.ContainsFunc(r.Streams, func(s RTSPStreamSettings) bool { return s.URL == url }) {
+			continue
+		}
+		streams = append(streams, RTSPStreamSettings{URL: url, Transport: r.Transport})
+	}
+	return streams
+}
+
+// StreamURLs returns the URLs of the streams to capture
+func (r *RTSPSettings) StreamURLs() []string {
+	streams := r.ActiveStreams()
+	urls := make([]string, len(streams))
+	for i := range streams {
+		urls[i] = streams[i].URL
+	}
+	return urls
+}
+
+// StreamByURL returns the active stream with the given URL
+func (r *RTSPSettings) StreamByURL(url string) (RTSPStreamSettings, bool) {
+	for _, stream := range r.ActiveStreams() {
+		if stream.URL == url {
+			return stream, true
+		}
+	}
+	return RTSPStreamSettings{}, false
+}
+
+// StreamByID returns the named stream with the given ID, enabled or not
+func (r *RTSPSettings) StreamByID(id string) (*RTSPStreamSettings, bool) {
+	if id == "" {
+		return nil, false
+	}
+	for i := range r.Streams {
+		if r.Streams[i].ID == id {
+			return &r.Streams[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,115 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTSPActiveStreams(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+	settings := RTSPSettings{
+		Transport: "tcp",
+		URLs:      []string{"rtsp://cam1.local/stream", "rtsp://legacy.local/stream", ""},
+		Streams: []RTSPStreamSettings{
+			{ID: "garden", Name: "Garden", URL: "rtsp://cam1.local/stream", Transport: "udp"},
+			{ID: "porch", URL: "rtsp://porch.local/stream"},
+			{ID: "attic", URL: "rtsp://attic.local/stream", Enabled: &disabled},
+		},
+	}
+
+	streams := settings.ActiveStreams()
+	require.Len(t, streams, 3)
+	assert.Equal(t, "garden", streams[0].ID)
+	assert.Equal(t, "udp", streams[0].Transport, "the stream transport overrides the global transport")
+	assert.Equal(t, "tcp", streams[1].Transport, "streams without a transport use the global transport")
+	assert.Empty(t, streams[2].ID, "legacy URLs are unnamed streams")
+	assert.Equal(t, "rtsp://legacy.local/stream", streams[2].URL)
+	assert.Equal(t, "tcp", streams[2].Transport)
+
+	assert.Equal(t, []string{"rtsp://cam1.local/stream", "rtsp://porch.local/stream", "rtsp://legacy.local/stream"}, settings.StreamURLs(),
+		"URLs configured as named streams are not duplicated and disabled streams are skipped")
+
+	stream, ok := settings.StreamByURL("rtsp://porch.local/stream")
+	require.True(t, ok)
+	assert.Equal(t, "porch", stream.DisplayName(), "the ID is displayed when no name is set")
+	_, ok = settings.StreamByURL("rtsp://attic.local/stream")
+	assert.False(t, ok, "disabled streams are not active")
+
+	named, ok := settings.StreamByID("attic")
+	require.True(t, ok, "disabled streams can be looked up by ID")
+	assert.False(t, named.IsEnabled())
+	_, ok = settings.StreamByID("")
+	assert.False(t, ok)
+}
+
+func TestRTSPStreamSpeciesAllowed(t *testing.T) {
+	t.Parallel()
+
+	stream := RTSPStreamSettings{ID: "garden"}
+	assert.True(t, stream.IsSpeciesAllowed("Turdus merula_Eurasian Blackbird"), "all species are allowed without lists")
+
+	stream.Species.Exclude = []string{"passer domesticus"}
+	assert.False(t, stream.IsSpeciesAllowed("Passer domesticus_House Sparrow"), "excluded by scientific name")
+	assert.True(t, stream.IsSpeciesAllowed("Turdus merula_Eurasian Blackbird"))
+
+	stream.Species.Include = []string{"Eurasian Blackbird", "House Sparrow"}
+	assert.True(t, stream.IsSpeciesAllowed("Turdus merula_Eurasian Blackbird"), "included by common name")
+	assert.False(t, stream.IsSpeciesAllowed("Passer domesticus_House Sparrow"), "exclusion wins over inclusion")
+	assert.False(t, stream.IsSpeciesAllowed("Erithacus rubecula_European Robin"), "species not on the include list")
+}
+
+func TestRTSPStreamsFromConfig(t *testing.T) {
+	t.Parallel()
+
+	config := `
+realtime:
+  rtsp:
+    transport: tcp
+    urls:
+      - rtsp://legacy.local/stream
+    streams:
+      - id: garden
+        name: Garden Camera
+        url: rtsp://garden.local/stream
+        transport: udp
+        gain: 6
+        equalizer:
+          enabled: true
+          filters:
+            - type: HighPass
+              frequency: 150
+              q: 0.707
+              passes: 1
+        species:
+          exclude:
+            - House Sparrow
+      - id: attic
+        url: rtsp://attic.local/stream
+        enabled: false
+`
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(config)))
+	var settings Settings
+	require.NoError(t, v.Unmarshal(&settings))
+
+	rtsp := &settings.Realtime.RTSP
+	require.Len(t, rtsp.Streams, 2)
+	garden := rtsp.Streams[0]
+	assert.Equal(t, "Garden Camera", garden.DisplayName())
+	assert.True(t, garden.IsEnabled(), "streams are enabled unless disabled")
+	assert.InDelta(t, 6.0, garden.Gain, 1e-9)
+	require.Len(t, garden.Equalizer.Filters, 1)
+	assert.Equal(t, "HighPass", garden.Equalizer.Filters[0].Type)
+	assert.Equal(t, []string{"House Sparrow"}, garden.Species.Exclude)
+	assert.False(t, rtsp.Streams[1].IsEnabled())
+
+	assert.Equal(t, []string{"rtsp://garden.local/stream", "rtsp://legacy.local/stream"}, rtsp.StreamURLs())
+	require.NoError(t, validateRTSPSettings(rtsp))
+}
@@ -0,0 +1,66 @@
+package conf
+
+import (
+	"slices"
+	"sync"
+)
+
+// Names of the species lists of realtime.species
+const (
+	SpeciesListInclude = "include"
+	SpeciesListExclude = "exclude"
+)
+
+// speciesFilterMutex serializes changes of the include and exclude lists of
+// realtime.species, so concurrent changes from the API and MQTT commands do not
+// lose updates
+var speciesFilterMutex sync.Mutex
+
+// speciesFilterList returns the include or exclude list of realtime.species
+func speciesFilterList(settings *Settings, list string) *[]string {
+	if list == SpeciesListExclude {
+		return &settings.Realtime.Species.Exclude
+	}
+	return &settings.Realtime.Species.Include
+}
+
+// ListedSpecies returns a copy of the include or exclude list of realtime.species
+func ListedSpecies(list string) []string {
+	speciesFilterMutex.Lock()
+	defer speciesFilterMutex.Unlock()
+	return slices.Clone(*speciesFilterList(GetSettings(), list))
+}
+
+// ToggleListedSpecies adds a species to the include or exclude list of
+// realtime.species, or removes it when already listed, and saves the settings.
+// Returns true when the species was added.
+func ToggleListedSpecies(list, species string) (bool, error) {
+	speciesFilterMutex.Lock()
+	defer speciesFilterMutex.Unlock()
+
+	// Replace the list instead of modifying it, readers may hold the old slice
+	listed := speciesFilterList(GetSettings(), list)
+	added := !slices.Contains(*listed, species)
+	if added {
+		*listed = append(slices.Clone(*listed), species)
+	} else {
+		*listed = slices.DeleteFunc(slices.Clone(*listed), func(s string) bool { return s == species })
+	}
+
+	return added, SaveSettings()
+}
+
+// AddListedSpecies adds a species to the include or exclude list of realtime.species
+// and saves the settings. Returns false when the species was already listed.
+func AddListedSpecies(list, species string) (bool, error) {
+	speciesFilterMutex.Lock()
+	defer speciesFilterMutex.Unlock()
+
+	listed := speciesFilterList(GetSettings(), list)
+	if slices.Contains(*listed, species) {
+		return false, nil
+	}
+	*listed = append(slices.Clone(*listed), species)
+
+	return true, SaveSettings()
+}
@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTempConfigFile points SaveSettings at a config file in a temporary home
+// directory and installs test settings
+func useTempConfigFile(t *testing.T) *Settings {
+	t.Helper()
+	if runtime.GOOS == osWindows {
+		t.Skip("config paths below the home directory differ on Windows")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "birdnet-go")
+	require.NoError(t, os.MkdirAll(configDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), nil, 0o600))
+
+	original := GetSettings()
+	settings := GetTestSettings()
+	SetTestSettings(settings)
+	t.Cleanup(func() { SetTestSettings(original) })
+	return settings
+}
+
+func TestToggleListedSpecies(t *testing.T) {
+	settings := useTempConfigFile(t)
+	settings.Realtime.Species.Exclude = []string{"House Sparrow"}
+
+	added, err := ToggleListedSpecies(SpeciesListExclude, "American Crow")
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []string{"House Sparrow", "American Crow"}, ListedSpecies(SpeciesListExclude))
+
+	added, err = ToggleListedSpecies(SpeciesListExclude, "House Sparrow")
+	require.NoError(t, err)
+	assert.False(t, added)
+	assert.Equal(t, []string{"American Crow"}, ListedSpecies(SpeciesListExclude))
+
+	added, err = AddListedSpecies(SpeciesListExclude, "American Crow")
+	require.NoError(t, err)
+	assert.False(t, added, "listed species are not added twice")
+
+	added, err = ToggleListedSpecies(SpeciesListInclude, "Barn Owl")
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []string{"Barn Owl"}, ListedSpecies(SpeciesListInclude))
+	assert.Equal(t, []string{"American Crow"}, ListedSpecies(SpeciesListExclude), "lists are separate")
+}
+
+func TestListedSpeciesConcurrentChanges(t *testing.T) {
+	useTempConfigFile(t)
+
+	// Toggles of the API and MQTT commands and additions of reviews at once
+	const perCaller = 10
+	var wg sync.WaitGroup
+	for caller := range 3 {
+		wg.Go(func() {
+			for i := range perCaller {
+				species := fmt.Sprintf("Species %d-%d", caller, i)
+				var err error
+				if caller == 2 {
+					_, err = AddListedSpecies(SpeciesListExclude, species)
+				} else {
+					_, err = ToggleListedSpecies(SpeciesListExclude, species)
+				}
+				assert.NoError(t, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.Len(t, ListedSpecies(SpeciesListExclude), 3*perCaller, "no change is lost")
+}
@@ -19,6 +19,18 @@ import (
 // MinSoundLevelInterval is the minimum sound level interval in seconds to prevent excessive CPU usage
 const MinSoundLevelInterval = 5
 
+// MaxSoundLevelStorageInterval is the longest interval of saved sound levels, hourly indicators need at least one per hour
+const MaxSoundLevelStorageInterval = 3600
+
+// MinHomeAssistantStateInterval is the minimum interval in seconds between Home Assistant state updates
+const MinHomeAssistantStateInterval = 10
+
+// MinMQTTCommandTokenLength is the minimum length of the shared secret authenticating MQTT commands
+const MinMQTTCommandTokenLength = 16
+
+// MinWeatherStationTokenLength is the minimum length of the secret in the local weather station push URL
+const MinWeatherStationTokenLength = 16
+
 // DefaultCleanupCheckInterval is the default disk cleanup check interval in minutes
 const DefaultCleanupCheckInterval = 15
 
@@ -26,6 +38,8 @@ const DefaultCleanupCheckInterval = 15
 var (
 	// birdweatherIDPattern validates Birdweather ID format (24 alphanumeric characters)
 	birdweatherIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]{24}$`)
+	// rtspStreamIDPattern validates RTSP stream IDs
+	rtspStreamIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 )
 
 // Audio gain limits in dB
@@ -297,6 +311,31 @@ func ValidateMQTTSettings(settings *MQTTSettings) ValidationResult {
 		}
 	}
 
+	// Validate Home Assistant discovery settings if enabled
+	if settings.HomeAssistant.Enabled {
+		prefix := settings.HomeAssistant.DiscoveryPrefix
+		if prefix == "" || strings.ContainsAny(prefix, "#+") {
+			result.Valid = false
+			result.Errors = append(result.Errors, "Home Assistant discovery prefix must be a topic without wildcards")
+		}
+		if settings.HomeAssistant.StateInterval < MinHomeAssistantStateInterval {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("Home Assistant state interval must be at least %d seconds", MinHomeAssistantStateInterval))
+		}
+	}
+
+	// Validate command subscription settings if enabled
+	if settings.Commands.Enabled {
+		if strings.ContainsAny(settings.Commands.Topic, "#+") {
+			result.Valid = false
+			result.Errors = append(result.Errors, "MQTT command topic must be a topic without wildcards")
+		}
+		if len(settings.Commands.Token) < MinMQTTCommandTokenLength {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("MQTT command token must be at least %d characters", MinMQTTCommandTokenLength))
+		}
+	}
+
 	result.Normalized = settings
 	return result
 }
@@ -544,10 +583,86 @@ func validateRealtimeSettings(settings *RealtimeSettings) error {
 		return err
 	}
 
+	// Validate review suppression settings
+	if err := validateReviewSuppressionSettings(&settings.ReviewSuppression); err != nil {
+		return err
+	}
+
+	// Validate RTSP stream settings
+	if err := validateRTSPSettings(&settings.RTSP); err != nil {
+		return err
+	}
+
 	// Add more realtime settings validation as needed
 	return nil
 }
 
+// validateReviewSuppressionSettings validates the false positive suppression learned from reviews
+func validateReviewSuppressionSettings(settings *ReviewSuppressionSettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	var problem string
+	switch {
+	case settings.Action != "raise" && settings.Action != "hold":
+		problem = fmt.Sprintf("review suppression action must be raise or hold, got %q", settings.Action)
+	case settings.MinReviews < 1:
+		problem = "review suppression minimum reviews must be at least 1"
+	case settings.MinFalsePositiveRate <= 0 || settings.MinFalsePositiveRate > 1:
+		problem = "review suppression minimum false positive rate must be greater than 0 and at most 1"
+	case settings.HourBucket < 1 || settings.HourBucket > 24:
+		problem = "review suppression hour bucket must be between 1 and 24"
+	case settings.LookbackDays < 0:
+		problem = "review suppression lookback days must be non-negative"
+	case settings.RefreshMinutes < 1:
+		problem = "review suppression refresh minutes must be at least 1"
+	default:
+		return nil
+	}
+	return errors.New(fmt.Errorf("%s", problem)).
+		Category(errors.CategoryValidation).
+		Context("validation_type", "review-suppression").
+		Build()
+}
+
+// validateRTSPSettings validates the named RTSP streams. IDs are stored with the
+// detections of a stream, so they must be unique and safe to use in URLs.
+func validateRTSPSettings(settings *RTSPSettings) error {
+	ids := make(map[string]bool, len(settings.Streams))
+	urls := make(map[string]bool, len(settings.Streams))
+
+	var problem string
+	for i := range settings.Streams {
+		stream := &settings.Streams[i]
+		switch {
+		case stream.ID == "":
+			problem = fmt.Sprintf("RTSP stream %d must have an ID", i+1)
+		case !rtspStreamIDPattern.MatchString(stream.ID):
+			problem = fmt.Sprintf("RTSP stream ID %q may only contain letters, digits, '-' and '_'", stream.ID)
+		case ids[stream.ID]:
+			problem = fmt.Sprintf("RTSP stream ID %q is used more than once", stream.ID)
+		case stream.URL == "":
+			problem = fmt.Sprintf("RTSP stream %q must have a URL", stream.ID)
+		case urls[stream.URL]:
+			problem = fmt.Sprintf("RTSP stream %q uses the URL of another stream", stream.ID)
+		case stream.Transport != "" && stream.Transport != "tcp" && stream.Transport != "udp":
+			problem = fmt.Sprintf("RTSP stream %q transport must be tcp or udp, got %q", stream.ID, stream.Transport)
+		case stream.Gain < MinAudioGain || stream.Gain > MaxAudioGain:
+			problem = fmt.Sprintf("RTSP stream %q gain must be between %.0f and +%.0f dB, got %.1f", stream.ID, MinAudioGain, MaxAudioGain, stream.Gain)
+		}
+		if problem != "" {
+			return errors.New(fmt.Errorf("%s", problem)).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "rtsp-streams").
+				Build()
+		}
+		ids[stream.ID] = true
+		urls[stream.URL] = true
+	}
+	return nil
+}
+
 // validateMQTTSettings validates the MQTT-specific settings.
 // This function uses ValidateMQTTSettings internally and handles error formatting
 // to maintain backward compatibility.
@@ -581,6 +696,28 @@ func validateSoundLevelSettings(settings *SoundLevelSettings) error {
 				Context("minimum_interval", MinSoundLevelInterval).
 				Build()
 		}
+
+		// Saved sound levels combine whole measurements and must line up with the hours
+		storage := &settings.Storage
+		if storage.Enabled {
+			if storage.Interval < settings.Interval || storage.Interval > MaxSoundLevelStorageInterval ||
+				MaxSoundLevelStorageInterval%storage.Interval != 0 {
+				return errors.New(fmt.Errorf("sound level storage interval must divide an hour evenly and be from the measurement interval of %d to %d seconds, got %d",
+					settings.Interval, MaxSoundLevelStorageInterval, storage.Interval)).
+					Category(errors.CategoryValidation).
+					Context("validation_type", "sound-level-storage-interval").
+					Context("interval", storage.Interval).
+					Context("minimum_interval", settings.Interval).
+					Build()
+			}
+			if storage.Retention < 0 {
+				return errors.New(fmt.Errorf("sound level retention must be 0 or more days, got %d", storage.Retention)).
+					Category(errors.CategoryValidation).
+					Context("validation_type", "sound-level-retention").
+					Context("retention", storage.Retention).
+					Build()
+			}
+		}
 	}
 	return nil
 }
@@ -847,6 +984,36 @@ func validateWeatherSettings(settings *WeatherSettings) error {
 		}
 	}
 
+	// Validate Open-Meteo settings if it's the selected provider
+	if settings.Provider == "openmeteo" {
+		if err := settings.OpenMeteo.ValidateOpenMeteo(); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "openmeteo-settings").
+				Build()
+		}
+	}
+
+	// Validate local weather station settings if it's the selected provider
+	if settings.Provider == "localstation" {
+		if err := settings.LocalStation.ValidateLocalStation(); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "localstation-settings").
+				Build()
+		}
+	}
+
+	// Validate MQTT weather settings if it's the selected provider
+	if settings.Provider == "mqtt" {
+		if err := settings.MQTT.ValidateWeatherMQTT(); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "weather-mqtt-settings").
+				Build()
+		}
+	}
+
 	return nil
 }
 
@@ -1009,6 +1176,12 @@ func validateSpeciesConfigSettings(settings *SpeciesSettings) error {
 
 // validateNotificationSettings validates notification push configuration
 func validateNotificationSettings(n *NotificationConfig) error {
+	if n.Store.RetentionDays < 0 || n.Store.MaxEntries < 0 {
+		return errors.New(fmt.Errorf("notification.store retention days and max entries must be >= 0")).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-store-retention").
+			Build()
+	}
 	if !n.Push.Enabled {
 		return nil
 	}
@@ -74,6 +74,45 @@ func TestValidateSoundLevelSettings(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "enabled with storage - should pass",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 10,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 60, Retention: 30},
+			},
+			wantErr: false,
+		},
+		{
+			name: "storage interval shorter than measurement interval - should fail",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 30,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 10},
+			},
+			wantErr: true,
+			errType: "sound-level-storage-interval",
+		},
+		{
+			name: "storage interval not dividing an hour - should fail",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 10,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 70},
+			},
+			wantErr: true,
+			errType: "sound-level-storage-interval",
+		},
+		{
+			name: "negative storage retention - should fail",
+			settings: SoundLevelSettings{
+				Enabled:  true,
+				Interval: 10,
+				Storage:  SoundLevelStorageSettings{Enabled: true, Interval: 60, Retention: -1},
+			},
+			wantErr: true,
+			errType: "sound-level-retention",
+		},
 		{
 			name: "disabled with zero interval - should pass",
 			settings: SoundLevelSettings{
@@ -108,7 +147,7 @@ func TestValidateSoundLevelSettings(t *testing.T) {
 				assert.Equal(t, errors.CategoryValidation, enhanced.Category)
 
 				// Verify interval context for interval errors
-				if tt.errType == "sound-level-interval" {
+				if tt.errType == "sound-level-interval" || tt.errType == "sound-level-storage-interval" {
 					assert.Contains(t, enhanced.Context, "interval")
 					assert.Contains(t, enhanced.Context, "minimum_interval")
 				}
@@ -185,3 +224,137 @@ func BenchmarkValidateSoundLevelSettingsWithError(b *testing.B) {
 		_ = validateSoundLevelSettings(settings)
 	}
 }
+
+func TestValidateReviewSuppressionSettings(t *testing.T) {
+	valid := ReviewSuppressionSettings{
+		Enabled:              true,
+		Action:               "raise",
+		MinReviews:           5,
+		MinFalsePositiveRate: 0.8,
+		HourBucket:           3,
+		LookbackDays:         365,
+		RefreshMinutes:       60,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(s *ReviewSuppressionSettings)
+		wantErr bool
+	}{
+		{"valid raise", func(s *ReviewSuppressionSettings) {}, false},
+		{"valid hold", func(s *ReviewSuppressionSettings) { s.Action = "hold" }, false},
+		{"disabled ignores invalid values", func(s *ReviewSuppressionSettings) { s.Enabled = false; s.Action = "" }, false},
+		{"unknown action", func(s *ReviewSuppressionSettings) { s.Action = "drop" }, true},
+		{"zero minimum reviews", func(s *ReviewSuppressionSettings) { s.MinReviews = 0 }, true},
+		{"zero false positive rate", func(s *ReviewSuppressionSettings) { s.MinFalsePositiveRate = 0 }, true},
+		{"false positive rate above 1", func(s *ReviewSuppressionSettings) { s.MinFalsePositiveRate = 1.5 }, true},
+		{"hour bucket above 24", func(s *ReviewSuppressionSettings) { s.HourBucket = 25 }, true},
+		{"negative lookback", func(s *ReviewSuppressionSettings) { s.LookbackDays = -1 }, true},
+		{"zero refresh", func(s *ReviewSuppressionSettings) { s.RefreshMinutes = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid
+			tt.modify(&settings)
+			err := validateReviewSuppressionSettings(&settings)
+			if tt.wantErr {
+				assertValidationError(t, err, "review-suppression")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRTSPSettings(t *testing.T) {
+	valid := func() RTSPSettings {
+		return RTSPSettings{
+			Transport: "tcp",
+			Streams: []RTSPStreamSettings{
+				{ID: "garden", Name: "Garden", URL: "rtsp://garden.local/stream", Transport: "udp", Gain: 6},
+				{ID: "porch_2", URL: "rtsp://porch.local/stream"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(s *RTSPSettings)
+		wantErr bool
+	}{
+		{"valid streams", func(s *RTSPSettings) {}, false},
+		{"legacy URLs only", func(s *RTSPSettings) { s.Streams = nil; s.URLs = []string{"rtsp://cam.local/stream"} }, false},
+		{"missing ID", func(s *RTSPSettings) { s.Streams[1].ID = "" }, true},
+		{"invalid ID", func(s *RTSPSettings) { s.Streams[1].ID = "porch camera" }, true},
+		{"duplicate ID", func(s *RTSPSettings) { s.Streams[1].ID = "garden" }, true},
+		{"missing URL", func(s *RTSPSettings) { s.Streams[1].URL = "" }, true},
+		{"duplicate URL", func(s *RTSPSettings) { s.Streams[1].URL = s.Streams[0].URL }, true},
+		{"unknown transport", func(s *RTSPSettings) { s.Streams[0].Transport = "http" }, true},
+		{"gain out of range", func(s *RTSPSettings) { s.Streams[0].Gain = 50 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid()
+			tt.modify(&settings)
+			err := validateRTSPSettings(&settings)
+			if tt.wantErr {
+				assertValidationError(t, err, "rtsp-streams")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWeatherProviderSettings(t *testing.T) {
+	valid := func() WeatherSettings {
+		return WeatherSettings{
+			PollInterval: 15,
+			OpenMeteo: OpenMeteoSettings{
+				Endpoint:        "https://api.open-meteo.com/v1/forecast",
+				ArchiveEndpoint: "https://archive-api.open-meteo.com/v1/archive",
+			},
+			LocalStation: LocalStationSettings{Token: "0123456789abcdef", MaxAge: 15},
+			MQTT: WeatherMQTTSettings{
+				Units:  "metric",
+				MaxAge: 15,
+				Topics: WeatherMQTTTopics{Temperature: "weather/temperature", Humidity: "weather/humidity"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		provider       string
+		modify         func(s *WeatherSettings)
+		validationType string
+	}{
+		{"local station", "localstation", func(s *WeatherSettings) {}, ""},
+		{"short station token", "localstation", func(s *WeatherSettings) { s.LocalStation.Token = "short" }, "localstation-settings"},
+		{"station max age", "localstation", func(s *WeatherSettings) { s.LocalStation.MaxAge = 0 }, "localstation-settings"},
+		{"mqtt", "mqtt", func(s *WeatherSettings) {}, ""},
+		{"mqtt imperial", "mqtt", func(s *WeatherSettings) { s.MQTT.Units = "imperial" }, ""},
+		{"mqtt without temperature", "mqtt", func(s *WeatherSettings) { s.MQTT.Topics.Temperature = "" }, "weather-mqtt-settings"},
+		{"mqtt unknown units", "mqtt", func(s *WeatherSettings) { s.MQTT.Units = "kelvin" }, "weather-mqtt-settings"},
+		{"mqtt wildcard topic", "mqtt", func(s *WeatherSettings) { s.MQTT.Topics.Humidity = "weather/+" }, "weather-mqtt-settings"},
+		{"open-meteo", "openmeteo", func(s *WeatherSettings) {}, ""},
+		{"open-meteo invalid endpoint", "openmeteo", func(s *WeatherSettings) { s.OpenMeteo.ArchiveEndpoint = "archive-api.open-meteo.com" }, "openmeteo-settings"},
+		{"unused settings are not validated", "yrno", func(s *WeatherSettings) { s.LocalStation.Token = ""; s.MQTT.Units = "" }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid()
+			settings.Provider = tt.provider
+			tt.modify(&settings)
+			err := validateWeatherSettings(&settings)
+			if tt.validationType != "" {
+				assertValidationError(t, err, tt.validationType)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
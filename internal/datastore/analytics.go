@@ -2,8 +2,11 @@
 package datastore
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,6 +22,18 @@ func isDebugLoggingEnabled() bool {
 	return settings != nil && settings.Debug
 }
 
+// sourceCondition returns the SQL condition and arguments limiting a query to the
+// detections of one audio source. The source matches either the source ID or the
+// display name stored with each detection. It returns an empty condition when
+// source is empty.
+func sourceCondition(source string) (condition string, args []any) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return "", nil
+	}
+	return "(source_id = ? OR source_name = ?)", []any{source, source}
+}
+
 // SpeciesSummaryData contains overall statistics for a bird species
 type SpeciesSummaryData struct {
 	ScientificName string
@@ -64,7 +79,7 @@ type NewSpeciesData struct {
 // NOTE: Uses a read-only transaction with repeatable read isolation to prevent race conditions
 // when concurrent writes are occurring. This ensures consistent timestamps even when new species
 // are being inserted. See issue #1239 for details on the SQLite WAL mode race condition.
-func (ds *DataStore) GetSpeciesSummaryData(ctx context.Context, startDate, endDate string) ([]SpeciesSummaryData, error) {
+func (ds *DataStore) GetSpeciesSummaryData(ctx context.Context, startDate, endDate, source string) ([]SpeciesSummaryData, error) {
 	// Pre-allocate with reasonable capacity for typical species count
 	summaries := make([]SpeciesSummaryData, 0, 100)
 
@@ -109,21 +124,27 @@ func (ds *DataStore) GetSpeciesSummaryData(ctx context.Context, startDate, endDa
 		FROM notes
 	`, dateTimeFormat, dateTimeFormat)
 
-	// Add WHERE clause if date filters are provided
-	var whereClause string
+	// Add WHERE clause if date or source filters are provided
+	var conditions []string
 	var args []any
 
-	switch {
-	case startDate != "" && endDate != "":
-		whereClause = "WHERE date >= ? AND date <= ?"
-		args = append(args, startDate, endDate)
-	case startDate != "":
-		whereClause = "WHERE date >= ?"
+	if startDate != "" {
+		conditions = append(conditions, "date >= ?")
 		args = append(args, startDate)
-	case endDate != "":
-		whereClause = "WHERE date <= ?"
+	}
+	if endDate != "" {
+		conditions = append(conditions, "date <= ?")
 		args = append(args, endDate)
 	}
+	if condition, sourceArgs := sourceCondition(source); condition != "" {
+		conditions = append(conditions, condition)
+		args = append(args, sourceArgs...)
+	}
+
+	var whereClause string
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
 
 	// Complete the query
 	queryStr += whereClause + `
@@ -258,7 +279,7 @@ func (ds *DataStore) GetSpeciesSummaryData(ctx context.Context, startDate, endDa
 }
 
 // GetHourlyAnalyticsData retrieves detection counts grouped by hour
-func (ds *DataStore) GetHourlyAnalyticsData(ctx context.Context, date, species string) ([]HourlyAnalyticsData, error) {
+func (ds *DataStore) GetHourlyAnalyticsData(ctx context.Context, date, species, source string) ([]HourlyAnalyticsData, error) {
 	var analytics []HourlyAnalyticsData
 	hourFormat := ds.GetHourFormat()
 
@@ -277,6 +298,10 @@ func (ds *DataStore) GetHourlyAnalyticsData(ctx context.Context, date, species s
 		query = query.Where("scientific_name = ? OR common_name = ?", species, species)
 	}
 
+	if condition, args := sourceCondition(source); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
 	// Execute query
 	if err := query.Scan(&analytics).Error; err != nil {
 		return nil, errors.New(err).
@@ -292,7 +317,7 @@ func (ds *DataStore) GetHourlyAnalyticsData(ctx context.Context, date, species s
 }
 
 // GetDailyAnalyticsData retrieves detection counts grouped by day
-func (ds *DataStore) GetDailyAnalyticsData(ctx context.Context, startDate, endDate, species string) ([]DailyAnalyticsData, error) {
+func (ds *DataStore) GetDailyAnalyticsData(ctx context.Context, startDate, endDate, species, source string) ([]DailyAnalyticsData, error) {
 	var analytics []DailyAnalyticsData
 
 	// Base query
@@ -316,6 +341,11 @@ func (ds *DataStore) GetDailyAnalyticsData(ctx context.Context, startDate, endDa
 		query = query.Where("scientific_name = ? OR common_name = ?", species, species)
 	}
 
+	// Apply audio source filter
+	if condition, args := sourceCondition(source); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
 	// Execute query
 	if err := query.Scan(&analytics).Error; err != nil {
 		return nil, errors.New(err).
@@ -332,7 +362,7 @@ func (ds *DataStore) GetDailyAnalyticsData(ctx context.Context, startDate, endDa
 }
 
 // GetDetectionTrends calculates the trend in detections over time
-func (ds *DataStore) GetDetectionTrends(ctx context.Context, period string, limit int) ([]DailyAnalyticsData, error) {
+func (ds *DataStore) GetDetectionTrends(ctx context.Context, period, source string, limit int) ([]DailyAnalyticsData, error) {
 	var trends []DailyAnalyticsData
 
 	var interval string
@@ -347,6 +377,15 @@ func (ds *DataStore) GetDetectionTrends(ctx context.Context, period string, limi
 		interval = "30 days" // Default to month
 	}
 
+	// Limit to one audio source if requested
+	sourceClause, sourceArgs := sourceCondition(source)
+	if sourceClause != "" {
+		sourceClause = "AND " + sourceClause
+	}
+	args := make([]any, 0, len(sourceArgs)+1)
+	args = append(args, sourceArgs...)
+	args = append(args, limit)
+
 	// Calculate start date based on the period
 	var startDate string
 	switch strings.ToLower(ds.Dialector().Name()) {
@@ -355,13 +394,13 @@ func (ds *DataStore) GetDetectionTrends(ctx context.Context, period string, limi
 		query := fmt.Sprintf(`
 			SELECT date, COUNT(*) as count
 			FROM notes
-			WHERE date >= %s
+			WHERE date >= %s %s
 			GROUP BY date
 			ORDER BY date DESC
 			LIMIT ?
-		`, startDate)
+		`, startDate, sourceClause)
 
-		if err := ds.DB.WithContext(ctx).Raw(query, limit).Scan(&trends).Error; err != nil {
+		if err := ds.DB.WithContext(ctx).Raw(query, args...).Scan(&trends).Error; err != nil {
 			return nil, errors.New(err).
 				Component("datastore").
 				Category(errors.CategoryDatabase).
@@ -375,13 +414,13 @@ func (ds *DataStore) GetDetectionTrends(ctx context.Context, period string, limi
 		query := fmt.Sprintf(`
 			SELECT date, COUNT(*) as count
 			FROM notes
-			WHERE date >= %s
+			WHERE date >= %s %s
 			GROUP BY date
 			ORDER BY date DESC
 			LIMIT ?
-		`, startDate)
+		`, startDate, sourceClause)
 
-		if err := ds.DB.WithContext(ctx).Raw(query, limit).Scan(&trends).Error; err != nil {
+		if err := ds.DB.WithContext(ctx).Raw(query, args...).Scan(&trends).Error; err != nil {
 			return nil, errors.New(err).
 				Component("datastore").
 				Category(errors.CategoryDatabase).
@@ -409,7 +448,7 @@ func (ds *DataStore) GetDetectionTrends(ctx context.Context, period string, limi
 
 // GetHourlyDistribution retrieves hourly detection distribution across a date range
 // Groups detections by hour of day (0-23) regardless of the specific date
-func (ds *DataStore) GetHourlyDistribution(ctx context.Context, startDate, endDate, species string) ([]HourlyDistributionData, error) {
+func (ds *DataStore) GetHourlyDistribution(ctx context.Context, startDate, endDate, species, source string) ([]HourlyDistributionData, error) {
 	var parsedStartDate, parsedEndDate time.Time
 	var err error
 
@@ -477,6 +516,11 @@ func (ds *DataStore) GetHourlyDistribution(ctx context.Context, startDate, endDa
 			species, species)
 	}
 
+	// Apply audio source filter if provided
+	if condition, args := sourceCondition(source); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
 	// Group by hour
 	query = query.Group(hourExpr)
 
@@ -503,7 +547,7 @@ func (ds *DataStore) GetHourlyDistribution(ctx context.Context, startDate, endDa
 // This is suitable for seasonal and yearly tracking where we need to know when each species
 // was first detected within that specific period, regardless of prior detections.
 // It returns all species detected in the period with their first detection date in that period.
-func (ds *DataStore) GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate, endDate string, limit, offset int) ([]NewSpeciesData, error) {
+func (ds *DataStore) GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate, endDate, source string, limit, offset int) ([]NewSpeciesData, error) {
 	// Validate input
 	if startDate != "" && endDate != "" && startDate > endDate {
 		return nil, errors.Newf("start date cannot be after end date").
@@ -539,12 +583,23 @@ func (ds *DataStore) GetSpeciesFirstDetectionInPeriod(ctx context.Context, start
 	WHERE date BETWEEN ? AND ?
 		AND date != ''
 		AND date IS NOT NULL
+		%s
 	GROUP BY scientific_name
 	ORDER BY first_detection_date ASC
 	LIMIT ? OFFSET ?
 	`
 
-	if err := ds.DB.WithContext(ctx).Raw(query, startDate, endDate, limit, offset).Scan(&results).Error; err != nil {
+	// Limit to one audio source if requested
+	args := []any{startDate, endDate}
+	sourceClause, sourceArgs := sourceCondition(source)
+	if sourceClause != "" {
+		sourceClause = "AND " + sourceClause
+		args = append(args, sourceArgs...)
+	}
+	args = append(args, limit, offset)
+	query = fmt.Sprintf(query, sourceClause)
+
+	if err := ds.DB.WithContext(ctx).Raw(query, args...).Scan(&results).Error; err != nil {
 		return nil, errors.New(err).
 			Component("datastore").
 			Category(errors.CategoryDatabase).
@@ -572,7 +627,7 @@ func (ds *DataStore) GetSpeciesFirstDetectionInPeriod(ctx context.Context, start
 // This is suitable for lifetime tracking only - NOT for seasonal or yearly tracking.
 // It supports pagination with limit and offset parameters.
 // NOTE: For optimal performance with large datasets, add a composite index on (scientific_name, date)
-func (ds *DataStore) GetNewSpeciesDetections(ctx context.Context, startDate, endDate string, limit, offset int) ([]NewSpeciesData, error) {
+func (ds *DataStore) GetNewSpeciesDetections(ctx context.Context, startDate, endDate, source string, limit, offset int) ([]NewSpeciesData, error) {
 	// Temporary struct to scan raw results, ensuring date can be checked for null/empty
 	type RawNewSpeciesResult struct {
 		ScientificName     string
@@ -608,6 +663,7 @@ func (ds *DataStore) GetNewSpeciesDetections(ctx context.Context, startDate, end
 	        scientific_name, 
 	        MIN(CASE WHEN date != '' AND date IS NOT NULL THEN date ELSE NULL END) as first_detection_date
 	    FROM notes
+	    %s
 	    GROUP BY scientific_name
 	    HAVING first_detection_date IS NOT NULL AND first_detection_date != '' 
 	), 
@@ -617,7 +673,7 @@ func (ds *DataStore) GetNewSpeciesDetections(ctx context.Context, startDate, end
 	        COUNT(*) as count_in_period,
 			MAX(common_name) as common_name -- Reverted from ANY_VALUE for testing
 	    FROM notes
-	    WHERE date BETWEEN ? AND ?
+	    WHERE date BETWEEN ? AND ? %s
 	    GROUP BY scientific_name
 	)
 	SELECT 
@@ -632,8 +688,23 @@ func (ds *DataStore) GetNewSpeciesDetections(ctx context.Context, startDate, end
 	LIMIT ? OFFSET ?;
 	`
 
+	// With a source filter, species are new when the source hears them for the first time
+	var args []any
+	firstSeenClause, periodClause := "", ""
+	if condition, sourceArgs := sourceCondition(source); condition != "" {
+		firstSeenClause = "WHERE " + condition
+		periodClause = "AND " + condition
+		args = append(args, sourceArgs...)
+		args = append(args, startDate, endDate)
+		args = append(args, sourceArgs...)
+	} else {
+		args = append(args, startDate, endDate)
+	}
+	args = append(args, startDate, endDate, limit, offset)
+	query = fmt.Sprintf(query, firstSeenClause, periodClause)
+
 	// Execute the raw SQL query into the temporary struct
-	if err := ds.DB.WithContext(ctx).Raw(query, startDate, endDate, startDate, endDate, limit, offset).Scan(&rawResults).Error; err != nil {
+	if err := ds.DB.WithContext(ctx).Raw(query, args...).Scan(&rawResults).Error; err != nil {
 		return nil, errors.New(err).
 			Component("datastore").
 			Category(errors.CategoryDatabase).
@@ -666,3 +737,116 @@ func (ds *DataStore) GetNewSpeciesDetections(ctx context.Context, startDate, end
 
 	return finalResults, nil
 }
+
+// ReviewBucket counts the review verdicts of detections within a confidence range
+type ReviewBucket struct {
+	MinConfidence float64 // Inclusive lower bound
+	MaxConfidence float64 // Exclusive upper bound, 1 is included in the last bucket
+	Correct       int
+	FalsePositive int
+}
+
+// SpeciesReviewStats counts the review verdicts of the detections of one species
+type SpeciesReviewStats struct {
+	ScientificName string
+	CommonName     string
+	Correct        int
+	FalsePositive  int
+	Buckets        []ReviewBucket // Buckets holding reviews, lowest confidence first
+}
+
+// GetSpeciesReviewStats counts the "correct" and "false_positive" reviews of each
+// species by confidence buckets of the given size. Optional date range filtering
+// with startDate and endDate in YYYY-MM-DD format. Species are ordered by the
+// number of reviews, most reviewed first.
+func (ds *DataStore) GetSpeciesReviewStats(ctx context.Context, startDate, endDate, source string, bucketSize float64) ([]SpeciesReviewStats, error) {
+	if bucketSize <= 0 || bucketSize > 1 {
+		return nil, validationError("bucket size must be greater than 0 and at most 1", "bucket_size", bucketSize)
+	}
+
+	// Reviews are added by hand, so there are few enough to aggregate in Go
+	// instead of bucketing with SQL that differs between SQLite and MySQL
+	query := ds.DB.WithContext(ctx).Table("notes").
+		Select("notes.scientific_name, notes.common_name, notes.confidence, note_reviews.verified").
+		Joins("JOIN note_reviews ON note_reviews.note_id = notes.id").
+		Where("note_reviews.verified IN ?", []string{"correct", "false_positive"}).
+		Order("notes.id DESC")
+	if startDate != "" {
+		query = query.Where("notes.date >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("notes.date <= ?", endDate)
+	}
+	if condition, args := sourceCondition(source); condition != "" {
+		query = query.Where(condition, args...)
+	}
+
+	var rows []struct {
+		ScientificName string
+		CommonName     string
+		Confidence     float64
+		Verified       string
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "get_species_review_stats").
+			Context("start_date", startDate).
+			Context("end_date", endDate).
+			Build()
+	}
+
+	bucketCount := int(math.Ceil(1/bucketSize - 1e-9))
+	stats := make([]SpeciesReviewStats, 0)
+	index := make(map[string]int)
+	buckets := make(map[string]map[int]*ReviewBucket)
+	for i := range rows {
+		row := &rows[i]
+		pos, ok := index[row.ScientificName]
+		if !ok {
+			// Rows are newest first, the species keeps its latest common name
+			pos = len(stats)
+			index[row.ScientificName] = pos
+			stats = append(stats, SpeciesReviewStats{ScientificName: row.ScientificName, CommonName: row.CommonName})
+			buckets[row.ScientificName] = make(map[int]*ReviewBucket)
+		}
+
+		// The epsilon keeps confidences on a bucket boundary, e.g. 0.7, out of the bucket below
+		b := min(max(int(row.Confidence/bucketSize+1e-9), 0), bucketCount-1)
+		bucket, ok := buckets[row.ScientificName][b]
+		if !ok {
+			bucket = &ReviewBucket{
+				MinConfidence: float64(b) * bucketSize,
+				MaxConfidence: min(float64(b+1)*bucketSize, 1),
+			}
+			buckets[row.ScientificName][b] = bucket
+		}
+		if row.Verified == "correct" {
+			bucket.Correct++
+			stats[pos].Correct++
+		} else {
+			bucket.FalsePositive++
+			stats[pos].FalsePositive++
+		}
+	}
+
+	for i := range stats {
+		speciesBuckets := buckets[stats[i].ScientificName]
+		stats[i].Buckets = make([]ReviewBucket, 0, len(speciesBuckets))
+		for _, bucket := range speciesBuckets {
+			stats[i].Buckets = append(stats[i].Buckets, *bucket)
+		}
+		slices.SortFunc(stats[i].Buckets, func(a, b ReviewBucket) int {
+			return cmp.Compare(a.MinConfidence, b.MinConfidence)
+		})
+	}
+	slices.SortStableFunc(stats, func(a, b SpeciesReviewStats) int {
+		if c := cmp.Compare(b.Correct+b.FalsePositive, a.Correct+a.FalsePositive); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ScientificName, b.ScientificName)
+	})
+
+	return stats, nil
+}
@@ -97,7 +97,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		seedTestData(t, ds)
 
 		// Test without date filters
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "", "")
 		require.NoError(t, err)
 		assert.Len(t, summaries, 3) // 3 unique species
 
@@ -135,7 +135,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		seedTestData(t, ds)
 
 		// Test with start date filter
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "2024-01-16", "")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "2024-01-16", "", "")
 		require.NoError(t, err)
 		assert.Len(t, summaries, 2) // Only Blue Jay and Northern Cardinal
 
@@ -150,7 +150,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		seedTestData(t, ds)
 
 		// Test with end date filter
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "2024-01-16")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "2024-01-16", "")
 		require.NoError(t, err)
 		assert.Len(t, summaries, 2) // American Robin and Blue Jay
 
@@ -165,7 +165,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		seedTestData(t, ds)
 
 		// Test with date range
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "2024-01-16", "2024-01-16")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "2024-01-16", "2024-01-16", "")
 		require.NoError(t, err)
 		assert.Len(t, summaries, 1) // Only Blue Jay
 
@@ -179,7 +179,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		ds := setupTestDB(t)
 
 		// Test with empty database
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "", "")
 		require.NoError(t, err)
 		assert.Empty(t, summaries)
 	})
@@ -223,7 +223,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 
 		// This query should not fail even with different species_code values
 		// because we're using MAX(species_code) aggregate function
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "", "")
 		require.NoError(t, err, "Query should not fail with SQL aggregate error")
 		assert.Len(t, summaries, 1)
 
@@ -261,7 +261,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		require.NoError(t, err)
 
 		// Query should not fail even with NULL species_code
-		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "")
+		summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "", "")
 		require.NoError(t, err, "Query should handle NULL species_code without error")
 		require.Len(t, summaries, 2)
 
@@ -279,7 +279,7 @@ func TestGetSpeciesSummaryData(t *testing.T) {
 		`, "2024-01-21", "10:00:00", "Nullus commonus", "nulcom", 0.66)
 		require.NoError(t, result2.Error)
 
-		summaries2, err := ds.GetSpeciesSummaryData(context.Background(), "", "")
+		summaries2, err := ds.GetSpeciesSummaryData(context.Background(), "", "", "")
 		require.NoError(t, err)
 		nsCommon := findSpeciesByScientificName(summaries2, "Nullus commonus")
 		require.NotNil(t, nsCommon)
@@ -305,7 +305,7 @@ func TestGetSpeciesSummaryDataTimeFormat(t *testing.T) {
 	err := ds.DB.Create(&note).Error
 	require.NoError(t, err)
 
-	summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "")
+	summaries, err := ds.GetSpeciesSummaryData(context.Background(), "", "", "")
 	require.NoError(t, err)
 	require.Len(t, summaries, 1)
 
@@ -360,7 +360,7 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		}
 
 		// Test 1: Get new species in July 2024
-		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", 10, 0)
+		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", "", 10, 0)
 		require.NoError(t, err)
 		assert.Len(t, result, 2, "Expected 2 new species in July 2024")
 
@@ -388,7 +388,7 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		ds := setupTestDB(t)
 
 		// Test invalid date range
-		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-31", "2024-07-01", 10, 0)
+		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-31", "2024-07-01", "", 10, 0)
 		require.Error(t, err, "Expected error for invalid date range")
 		assert.Contains(t, err.Error(), "start date cannot be after end date")
 		assert.Nil(t, result)
@@ -412,12 +412,12 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		}
 
 		// Test with limit
-		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", 2, 0)
+		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", "", 2, 0)
 		require.NoError(t, err)
 		assert.Len(t, result, 2, "Expected 2 results with limit=2")
 
 		// Test with offset
-		result2, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", 2, 2)
+		result2, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", "", 2, 2)
 		require.NoError(t, err)
 		assert.Len(t, result2, 2, "Expected 2 results with limit=2, offset=2")
 
@@ -440,7 +440,7 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		require.NoError(t, err)
 
 		// Should only return species with valid dates
-		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", 10, 0)
+		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-01", "2024-07-31", "", 10, 0)
 		require.NoError(t, err)
 		assert.Len(t, result, 1, "Expected only species with valid dates")
 		assert.Equal(t, "Species valid", result[0].ScientificName)
@@ -463,7 +463,7 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		}
 
 		// Query for new species in 2024
-		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-01-01", "2024-12-31", 10, 0)
+		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-01-01", "2024-12-31", "", 10, 0)
 		require.NoError(t, err)
 
 		// Should not find Motacilla alba as new in 2024
@@ -492,12 +492,12 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		}
 
 		// Test spring period
-		springResult, err := ds.GetNewSpeciesDetections(context.Background(), "2024-03-20", "2024-06-20", 10, 0)
+		springResult, err := ds.GetNewSpeciesDetections(context.Background(), "2024-03-20", "2024-06-20", "", 10, 0)
 		require.NoError(t, err)
 		assert.Len(t, springResult, 2, "Expected 2 new species in spring")
 
 		// Test summer period
-		summerResult, err := ds.GetNewSpeciesDetections(context.Background(), "2024-06-21", "2024-09-21", 10, 0)
+		summerResult, err := ds.GetNewSpeciesDetections(context.Background(), "2024-06-21", "2024-09-21", "", 10, 0)
 		require.NoError(t, err)
 		assert.Len(t, summerResult, 2, "Expected 2 new species in summer")
 
@@ -513,7 +513,7 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 		require.NoError(t, err)
 
 		// Barn Swallow should not be "new" in summer
-		summerResult2, err := ds.GetNewSpeciesDetections(context.Background(), "2024-06-21", "2024-09-21", 10, 0)
+		summerResult2, err := ds.GetNewSpeciesDetections(context.Background(), "2024-06-21", "2024-09-21", "", 10, 0)
 		require.NoError(t, err)
 
 		for _, species := range summerResult2 {
@@ -539,7 +539,7 @@ func TestGetNewSpeciesDetections(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-20", "2024-07-20", 10, 0)
+		result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-07-20", "2024-07-20", "", 10, 0)
 		require.NoError(t, err)
 		assert.Len(t, result, 1, "Expected 1 new species")
 		assert.Equal(t, "2024-07-20", result[0].FirstSeenDate)
@@ -585,7 +585,7 @@ func TestGetHourlyDistribution(t *testing.T) {
 		}
 
 		// Test hourly distribution
-		distribution, err := ds.GetHourlyDistribution(context.Background(), "2024-07-15", "2024-07-15", "")
+		distribution, err := ds.GetHourlyDistribution(context.Background(), "2024-07-15", "2024-07-15", "", "")
 		require.NoError(t, err)
 
 		// Create map for verification
@@ -620,7 +620,7 @@ func TestGetHourlyDistribution(t *testing.T) {
 		}
 
 		// Test with species filter
-		distribution, err := ds.GetHourlyDistribution(context.Background(), "2024-07-15", "2024-07-15", "Species A")
+		distribution, err := ds.GetHourlyDistribution(context.Background(), "2024-07-15", "2024-07-15", "Species A", "")
 		require.NoError(t, err)
 
 		totalCount := 0
@@ -678,7 +678,7 @@ func TestDatabasePerformance(t *testing.T) {
 
 	// Measure query performance
 	start := time.Now()
-	result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-01-01", "2024-01-31", 50, 0)
+	result, err := ds.GetNewSpeciesDetections(context.Background(), "2024-01-01", "2024-01-31", "", 50, 0)
 	duration := time.Since(start)
 
 	require.NoError(t, err)
@@ -688,9 +688,65 @@ func TestDatabasePerformance(t *testing.T) {
 	// Test pagination performance
 	start = time.Now()
 	for offset := 0; offset < 30; offset += 10 {
-		_, err := ds.GetNewSpeciesDetections(context.Background(), "2024-01-01", "2024-01-31", 10, offset)
+		_, err := ds.GetNewSpeciesDetections(context.Background(), "2024-01-01", "2024-01-31", "", 10, offset)
 		require.NoError(t, err)
 	}
 	duration = time.Since(start)
 	assert.Less(t, duration.Milliseconds(), int64(paginationThresholdMs), "Paginated queries should complete within %dms", paginationThresholdMs)
 }
+
+func TestGetSpeciesReviewStats(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}))
+
+	reviews := []struct {
+		date       string
+		scientific string
+		confidence float64
+		verified   string
+	}{
+		{"2024-01-15", "Turdus merula", 0.72, "false_positive"},
+		{"2024-01-15", "Turdus merula", 0.74, "correct"},
+		{"2024-01-16", "Turdus merula", 0.91, "correct"},
+		{"2024-01-16", "Turdus merula", 1.0, "correct"},
+		{"2024-01-16", "Parus major", 0.8, "false_positive"},
+		{"2024-01-17", "Parus major", 0.85, ""},
+		{"2024-01-17", "Parus major", 0.85, "correct"},
+	}
+	for i, r := range reviews {
+		note := Note{Date: r.date, Time: "08:00:00", ScientificName: r.scientific, CommonName: r.scientific, Confidence: r.confidence}
+		require.NoError(t, ds.DB.Create(&note).Error)
+		if r.verified != "" {
+			require.NoError(t, ds.DB.Create(&NoteReview{NoteID: note.ID, Verified: r.verified}).Error, i)
+		}
+	}
+	// Unreviewed detections are not counted
+	require.NoError(t, ds.DB.Create(&Note{Date: "2024-01-17", ScientificName: "Pica pica", Confidence: 0.9}).Error)
+
+	stats, err := ds.GetSpeciesReviewStats(context.Background(), "", "", "", 0.1)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	blackbird := stats[0]
+	assert.Equal(t, "Turdus merula", blackbird.ScientificName)
+	assert.Equal(t, 3, blackbird.Correct)
+	assert.Equal(t, 1, blackbird.FalsePositive)
+	require.Len(t, blackbird.Buckets, 2)
+	assert.InDelta(t, 0.7, blackbird.Buckets[0].MinConfidence, 1e-9)
+	assert.Equal(t, 1, blackbird.Buckets[0].Correct)
+	assert.Equal(t, 1, blackbird.Buckets[0].FalsePositive)
+	assert.InDelta(t, 0.9, blackbird.Buckets[1].MinConfidence, 1e-9)
+	assert.InDelta(t, 1.0, blackbird.Buckets[1].MaxConfidence, 1e-9)
+	assert.Equal(t, 2, blackbird.Buckets[1].Correct, "confidence 1 falls in the last bucket")
+
+	stats, err = ds.GetSpeciesReviewStats(context.Background(), "2024-01-16", "2024-01-16", "", 0.1)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, 2, stats[0].Correct)
+	assert.Equal(t, "Parus major", stats[1].ScientificName)
+	assert.Equal(t, 1, stats[1].FalsePositive)
+
+	_, err = ds.GetSpeciesReviewStats(context.Background(), "", "", "", 0)
+	assert.Error(t, err)
+}
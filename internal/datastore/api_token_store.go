@@ -0,0 +1,103 @@
+// api_token_store.go: Database backed store for scoped API tokens
+package datastore
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// APITokenStore stores API tokens in the main database
+type APITokenStore struct {
+	db *gorm.DB
+}
+
+// NewAPITokenStore creates an API token store using the database of the datastore
+func (ds *DataStore) NewAPITokenStore() *APITokenStore {
+	return &APITokenStore{db: ds.DB}
+}
+
+// ScopeList returns the scopes granted to the token
+func (t *APIToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return nil
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+// Create stores a new API token
+func (s *APITokenStore) Create(token *APIToken) error {
+	if token == nil {
+		return validationError("api token cannot be nil", "token", nil)
+	}
+	if token.TokenHash == "" {
+		return validationError("api token hash cannot be empty", "token_hash", nil)
+	}
+	if err := s.db.Create(token).Error; err != nil {
+		return dbError(err, "create_api_token", errors.PriorityMedium,
+			"token_name", token.Name,
+			"table", "api_tokens",
+			"action", "create_api_token")
+	}
+	return nil
+}
+
+// List returns all API tokens, including revoked ones, newest first
+func (s *APITokenStore) List() ([]APIToken, error) {
+	var tokens []APIToken
+	if err := s.db.Order("created_at DESC, id DESC").Find(&tokens).Error; err != nil {
+		return nil, dbError(err, "list_api_tokens", errors.PriorityLow,
+			"action", "list_api_tokens")
+	}
+	return tokens, nil
+}
+
+// GetByHash returns the API token with the given token hash
+func (s *APITokenStore) GetByHash(hash string) (*APIToken, error) {
+	var token APIToken
+	if err := s.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPITokenNotFound
+		}
+		return nil, dbError(err, "get_api_token", errors.PriorityLow,
+			"action", "validate_api_token")
+	}
+	return &token, nil
+}
+
+// Revoke marks an API token as revoked. Revoked tokens are kept so their use
+// history remains visible, revoking a revoked token keeps the first revocation time.
+func (s *APITokenStore) Revoke(id uint, at time.Time) error {
+	result := s.db.Model(&APIToken{}).Where("id = ?", id).Where("revoked_at IS NULL").Update("revoked_at", at)
+	if result.Error != nil {
+		return dbError(result.Error, "revoke_api_token", errors.PriorityMedium,
+			"token_id", strconv.FormatUint(uint64(id), 10),
+			"action", "revoke_api_token")
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := s.db.Model(&APIToken{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return dbError(err, "revoke_api_token", errors.PriorityMedium,
+				"token_id", strconv.FormatUint(uint64(id), 10),
+				"action", "revoke_api_token")
+		}
+		if count == 0 {
+			return ErrAPITokenNotFound
+		}
+	}
+	return nil
+}
+
+// MarkUsed records when and from where an API token was last used
+func (s *APITokenStore) MarkUsed(id uint, at time.Time, ip string) error {
+	if err := s.db.Model(&APIToken{}).Where("id = ?", id).
+		Updates(map[string]any{"last_used_at": at, "last_used_ip": ip}).Error; err != nil {
+		return dbError(err, "mark_api_token_used", errors.PriorityLow,
+			"token_id", strconv.FormatUint(uint64(id), 10),
+			"action", "track_api_token_use")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// api_token_store_test.go: Unit tests for the API token store
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupAPITokenStoreTestDB creates an in-memory SQLite database for testing
+func setupAPITokenStoreTestDB(t *testing.T) *APITokenStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "Failed to create test database")
+	require.NoError(t, db.AutoMigrate(&APIToken{}), "Failed to migrate schema")
+	return (&DataStore{DB: db}).NewAPITokenStore()
+}
+
+func TestAPITokenStoreLifecycle(t *testing.T) {
+	t.Parallel()
+	store := setupAPITokenStoreTestDB(t)
+
+	token := &APIToken{Name: "home-assistant", TokenHash: "abc123", Prefix: "bng_abcd", Scopes: "read-detections,review"}
+	require.NoError(t, store.Create(token))
+	require.NotZero(t, token.ID)
+
+	got, err := store.GetByHash("abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "home-assistant", got.Name)
+	assert.Equal(t, []string{"read-detections", "review"}, got.ScopeList())
+	assert.Nil(t, got.LastUsedAt)
+
+	usedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, store.MarkUsed(token.ID, usedAt, "192.168.1.10"))
+	got, err = store.GetByHash("abc123")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.True(t, got.LastUsedAt.Equal(usedAt))
+	assert.Equal(t, "192.168.1.10", got.LastUsedIP)
+
+	revokedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, store.Revoke(token.ID, revokedAt))
+	require.NoError(t, store.Revoke(token.ID, revokedAt.Add(time.Hour)), "revoking twice is not an error")
+	got, err = store.GetByHash("abc123")
+	require.NoError(t, err)
+	require.NotNil(t, got.RevokedAt)
+	assert.True(t, got.RevokedAt.Equal(revokedAt), "the first revocation time is kept")
+
+	tokens, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+}
+
+func TestAPITokenStoreNotFound(t *testing.T) {
+	t.Parallel()
+	store := setupAPITokenStoreTestDB(t)
+
+	_, err := store.GetByHash("missing")
+	require.ErrorIs(t, err, ErrAPITokenNotFound)
+	require.ErrorIs(t, store.Revoke(42, time.Now()), ErrAPITokenNotFound)
+	require.Error(t, store.Create(&APIToken{Name: "no hash"}))
+}
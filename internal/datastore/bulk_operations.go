@@ -0,0 +1,443 @@
+// bulk_operations.go: Transactional changes to many notes with a short undo window
+package datastore
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// Bulk operation actions
+const (
+	BulkActionReview   = "review"   // Set the review verdict
+	BulkActionLock     = "lock"     // Lock the notes
+	BulkActionUnlock   = "unlock"   // Unlock the notes
+	BulkActionDelete   = "delete"   // Delete the notes with their results, reviews, comments and locks
+	BulkActionReassign = "reassign" // Change the species of the notes
+)
+
+// bulkSnapshotBatchSize is the number of snapshot rows inserted per statement
+const bulkSnapshotBatchSize = 100
+
+// BulkNoteChange describes a change applied to many notes by ApplyBulkNoteChange
+type BulkNoteChange struct {
+	Action         string        // One of the BulkAction constants
+	Verified       string        // Verdict for BulkActionReview, "correct" or "false_positive"
+	ScientificName string        // New species for BulkActionReassign
+	CommonName     string        // New species for BulkActionReassign
+	SpeciesCode    string        // New species for BulkActionReassign, may be empty
+	SpeciesSource  string        // Where the new species was chosen from, a CorrectionSource constant, defaults to label
+	PerformedBy    string        // User making the change, recorded on reviews and the operation
+	UndoWindow     time.Duration // How long the change can be undone
+}
+
+// BulkNoteResult is the outcome of a bulk operation or of undoing one
+type BulkNoteResult struct {
+	Operation *BulkOperation
+	Notes     []Note // Changed notes as they were before the change, or restored notes after an undo
+	Skipped   []uint // Notes left unchanged because they are locked or already in the requested state
+	Missing   []uint // IDs that did not match a note
+}
+
+// validate checks that the change has the fields its action needs
+func (c *BulkNoteChange) validate() error {
+	switch c.Action {
+	case BulkActionReview:
+		if c.Verified != "correct" && c.Verified != "false_positive" {
+			return validationError("review verdict must be correct or false_positive", "verified", c.Verified)
+		}
+	case BulkActionReassign:
+		if c.ScientificName == "" || c.CommonName == "" {
+			return validationError("new species requires scientific and common name", "species", c.ScientificName)
+		}
+		switch c.SpeciesSource {
+		case "":
+			c.SpeciesSource = CorrectionSourceLabel
+		case CorrectionSourceLabel, CorrectionSourceManual:
+		default:
+			return validationError("unknown species source", "species_source", c.SpeciesSource)
+		}
+	case BulkActionLock, BulkActionUnlock, BulkActionDelete:
+	default:
+		return validationError("unknown bulk action", "action", c.Action)
+	}
+	if c.UndoWindow <= 0 {
+		return validationError("undo window must be positive", "undo_window", c.UndoWindow)
+	}
+	return nil
+}
+
+// details returns the description stored with the operation
+func (c *BulkNoteChange) details() string {
+	switch c.Action {
+	case BulkActionReview:
+		return c.Verified
+	case BulkActionReassign:
+		return c.ScientificName + "_" + c.CommonName
+	default:
+		return ""
+	}
+}
+
+// applies reports whether the change would modify the note
+func (c *BulkNoteChange) applies(note *Note) bool {
+	switch c.Action {
+	case BulkActionLock:
+		return note.Lock == nil
+	case BulkActionUnlock:
+		return note.Lock != nil
+	case BulkActionReview:
+		return note.Lock == nil && (note.Review == nil || note.Review.Verified != c.Verified)
+	case BulkActionReassign:
+		return note.Lock == nil && note.ScientificName != c.ScientificName
+	default:
+		return note.Lock == nil
+	}
+}
+
+// ApplyBulkNoteChange applies a change to the notes with the given IDs in one
+// transaction. Locked notes are not reviewed, reassigned or deleted. The state of
+// the changed notes is stored so that UndoBulkOperation can restore it until the
+// undo window has passed. When no note is changed no operation is recorded and
+// the Operation of the result is nil.
+func (ds *DataStore) ApplyBulkNoteChange(noteIDs []uint, change *BulkNoteChange) (*BulkNoteResult, error) {
+	if change == nil {
+		return nil, validationError("bulk change cannot be nil", "change", nil)
+	}
+	if err := change.validate(); err != nil {
+		return nil, err
+	}
+
+	result := &BulkNoteResult{}
+	err := ds.Transaction(func(tx *gorm.DB) error {
+		var notes []Note
+		if err := tx.Preload("Results").Preload("Review").Preload("Comments").Preload("Lock").
+			Where("id IN ?", noteIDs).Order("id ASC").Find(&notes).Error; err != nil {
+			return err
+		}
+
+		found := make(map[uint]bool, len(notes))
+		changed := make([]uint, 0, len(notes))
+		for i := range notes {
+			found[notes[i].ID] = true
+			if change.applies(&notes[i]) {
+				result.Notes = append(result.Notes, notes[i])
+				changed = append(changed, notes[i].ID)
+			} else {
+				result.Skipped = append(result.Skipped, notes[i].ID)
+			}
+		}
+		for _, id := range noteIDs {
+			if !found[id] {
+				result.Missing = append(result.Missing, id)
+				found[id] = true
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		op := &BulkOperation{
+			Action:      change.Action,
+			Details:     change.details(),
+			NoteCount:   len(changed),
+			PerformedBy: change.PerformedBy,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(change.UndoWindow),
+		}
+		if err := tx.Create(op).Error; err != nil {
+			return err
+		}
+		if err := saveBulkSnapshots(tx, op.ID, result.Notes); err != nil {
+			return err
+		}
+		result.Operation = op
+
+		if err := applyBulkChange(tx, change, changed, now); err != nil {
+			return err
+		}
+		if change.Action == BulkActionReassign {
+			return saveBulkCorrections(tx, op, change, result.Notes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "apply_bulk_note_change", errors.PriorityMedium,
+			"action", change.Action,
+			"note_count", strconv.Itoa(len(noteIDs)),
+			"table", "notes")
+	}
+	return result, nil
+}
+
+// saveBulkSnapshots stores the state of the notes before a bulk operation
+func saveBulkSnapshots(tx *gorm.DB, operationID uint, notes []Note) error {
+	snapshots := make([]BulkOperationNote, 0, len(notes))
+	for i := range notes {
+		data, err := json.Marshal(&notes[i])
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, BulkOperationNote{
+			OperationID: operationID,
+			NoteID:      notes[i].ID,
+			Snapshot:    string(data),
+		})
+	}
+	return tx.CreateInBatches(snapshots, bulkSnapshotBatchSize).Error
+}
+
+// saveBulkCorrections records the species corrections made by a bulk reassign
+// in the audit trail, linked to the operation so that an undo removes them
+func saveBulkCorrections(tx *gorm.DB, op *BulkOperation, change *BulkNoteChange, notes []Note) error {
+	corrections := make([]SpeciesCorrection, 0, len(notes))
+	for i := range notes {
+		correction := newSpeciesCorrection(&notes[i], change.ScientificName, change.CommonName, change.SpeciesCode,
+			change.SpeciesSource, change.PerformedBy, op.CreatedAt)
+		correction.BulkOperationID = &op.ID
+		corrections = append(corrections, correction)
+	}
+	return tx.CreateInBatches(corrections, bulkSnapshotBatchSize).Error
+}
+
+// applyBulkChange performs the change on the notes with the given IDs
+func applyBulkChange(tx *gorm.DB, change *BulkNoteChange, ids []uint, now time.Time) error {
+	switch change.Action {
+	case BulkActionReview:
+		if err := tx.Where("note_id IN ?", ids).Delete(&NoteReview{}).Error; err != nil {
+			return err
+		}
+		reviews := make([]NoteReview, 0, len(ids))
+		for _, id := range ids {
+			reviews = append(reviews, NoteReview{NoteID: id, Verified: change.Verified, ReviewedBy: change.PerformedBy})
+		}
+		return tx.CreateInBatches(reviews, bulkSnapshotBatchSize).Error
+
+	case BulkActionLock:
+		locks := make([]NoteLock, 0, len(ids))
+		for _, id := range ids {
+			locks = append(locks, NoteLock{NoteID: id, LockedAt: now})
+		}
+		return tx.CreateInBatches(locks, bulkSnapshotBatchSize).Error
+
+	case BulkActionUnlock:
+		return tx.Where("note_id IN ?", ids).Delete(&NoteLock{}).Error
+
+	case BulkActionReassign:
+		// Reviews were about the previous species
+		if err := tx.Where("note_id IN ?", ids).Delete(&NoteReview{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Note{}).Where("id IN ?", ids).Updates(map[string]any{
+			"scientific_name": change.ScientificName,
+			"common_name":     change.CommonName,
+			"species_code":    change.SpeciesCode,
+		}).Error
+
+	case BulkActionDelete:
+		return deleteNotesInTransaction(tx, ids)
+	}
+	return nil
+}
+
+// deleteNotesInTransaction deletes notes and the rows referencing them
+func deleteNotesInTransaction(tx *gorm.DB, ids []uint) error {
+	for _, model := range []any{&Results{}, &NoteReview{}, &NoteComment{}, &NoteLock{}} {
+		if err := tx.Where("note_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("id IN ?", ids).Delete(&Note{}).Error
+}
+
+// UndoBulkOperation restores the notes changed by a bulk operation to their state
+// before the operation. Changes made to the notes after the operation are
+// overwritten, notes deleted since a non-delete operation stay deleted. Undoing a
+// reassign also removes its species corrections from the audit trail. It returns
+// ErrBulkOperationNotUndoable when the operation was already undone or has expired.
+func (ds *DataStore) UndoBulkOperation(id uint) (*BulkNoteResult, error) {
+	result := &BulkNoteResult{}
+	err := ds.Transaction(func(tx *gorm.DB) error {
+		var op BulkOperation
+		if err := tx.First(&op, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBulkOperationNotFound
+			}
+			return err
+		}
+		now := time.Now()
+		if op.UndoneAt != nil || now.After(op.ExpiresAt) {
+			return ErrBulkOperationNotUndoable
+		}
+
+		var snapshots []BulkOperationNote
+		if err := tx.Where("operation_id = ?", op.ID).Order("note_id ASC").Find(&snapshots).Error; err != nil {
+			return err
+		}
+		for i := range snapshots {
+			var note Note
+			if err := json.Unmarshal([]byte(snapshots[i].Snapshot), &note); err != nil {
+				return err
+			}
+			restored, err := restoreNoteSnapshot(tx, op.Action, &note)
+			if err != nil {
+				return err
+			}
+			if restored {
+				result.Notes = append(result.Notes, note)
+			} else {
+				result.Missing = append(result.Missing, note.ID)
+			}
+		}
+
+		if op.Action == BulkActionReassign {
+			if err := tx.Where("bulk_operation_id = ?", op.ID).Delete(&SpeciesCorrection{}).Error; err != nil {
+				return err
+			}
+		}
+
+		op.UndoneAt = &now
+		if err := tx.Model(&op).Update("undone_at", now).Error; err != nil {
+			return err
+		}
+		result.Operation = &op
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrBulkOperationNotFound) || errors.Is(err, ErrBulkOperationNotUndoable) {
+			return nil, err
+		}
+		return nil, dbError(err, "undo_bulk_operation", errors.PriorityMedium,
+			"operation_id", strconv.FormatUint(uint64(id), 10),
+			"table", "bulk_operations")
+	}
+	return result, nil
+}
+
+// restoreNoteSnapshot restores the part of a note changed by the action. It
+// returns false when the note no longer exists and the action did not delete it.
+func restoreNoteSnapshot(tx *gorm.DB, action string, note *Note) (bool, error) {
+	var count int64
+	if err := tx.Model(&Note{}).Where("id = ?", note.ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	if action == BulkActionDelete {
+		// Recreate the note with its results, review, comments and lock. The IDs
+		// are kept unless a new detection has taken the ID of the note meanwhile.
+		if count > 0 {
+			clearNoteIDs(note)
+		}
+		return true, tx.Create(note).Error
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	switch action {
+	case BulkActionReview:
+		if err := tx.Where("note_id = ?", note.ID).Delete(&NoteReview{}).Error; err != nil {
+			return false, err
+		}
+		if note.Review != nil {
+			return true, tx.Create(note.Review).Error
+		}
+	case BulkActionLock, BulkActionUnlock:
+		if err := tx.Where("note_id = ?", note.ID).Delete(&NoteLock{}).Error; err != nil {
+			return false, err
+		}
+		if note.Lock != nil {
+			return true, tx.Create(note.Lock).Error
+		}
+	case BulkActionReassign:
+		if err := tx.Model(&Note{}).Where("id = ?", note.ID).Updates(map[string]any{
+			"scientific_name": note.ScientificName,
+			"common_name":     note.CommonName,
+			"species_code":    note.SpeciesCode,
+		}).Error; err != nil {
+			return false, err
+		}
+		// Restore the review of the original species cleared by the reassign
+		if err := tx.Where("note_id = ?", note.ID).Delete(&NoteReview{}).Error; err != nil {
+			return false, err
+		}
+		if note.Review != nil {
+			return true, tx.Create(note.Review).Error
+		}
+	}
+	return true, nil
+}
+
+// clearNoteIDs resets the primary keys of a note and its associations so that
+// they are assigned new IDs when created
+func clearNoteIDs(note *Note) {
+	note.ID = 0
+	for i := range note.Results {
+		note.Results[i].ID, note.Results[i].NoteID = 0, 0
+	}
+	for i := range note.Comments {
+		note.Comments[i].ID, note.Comments[i].NoteID = 0, 0
+	}
+	if note.Review != nil {
+		note.Review.ID, note.Review.NoteID = 0, 0
+	}
+	if note.Lock != nil {
+		note.Lock.ID, note.Lock.NoteID = 0, 0
+	}
+}
+
+// GetBulkOperation returns a bulk operation by ID, or ErrBulkOperationNotFound
+func (ds *DataStore) GetBulkOperation(id uint) (*BulkOperation, error) {
+	var op BulkOperation
+	if err := ds.DB.First(&op, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBulkOperationNotFound
+		}
+		return nil, dbError(err, "get_bulk_operation", errors.PriorityLow,
+			"operation_id", strconv.FormatUint(uint64(id), 10),
+			"table", "bulk_operations")
+	}
+	return &op, nil
+}
+
+// GetBulkOperations returns the bulk operations that can still be undone, newest first
+func (ds *DataStore) GetBulkOperations() ([]BulkOperation, error) {
+	var ops []BulkOperation
+	if err := ds.DB.Where("expires_at > ? AND undone_at IS NULL", time.Now()).
+		Order("created_at DESC, id DESC").Find(&ops).Error; err != nil {
+		return nil, dbError(err, "get_bulk_operations", errors.PriorityLow,
+			"table", "bulk_operations")
+	}
+	return ops, nil
+}
+
+// PurgeBulkOperations deletes the undo records of operations that expired before
+// the given time or were undone, and returns the deleted operations
+func (ds *DataStore) PurgeBulkOperations(before time.Time) ([]BulkOperation, error) {
+	var ops []BulkOperation
+	err := ds.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ? OR undone_at IS NOT NULL", before).Find(&ops).Error; err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			return nil
+		}
+		ids := make([]uint, 0, len(ops))
+		for i := range ops {
+			ids = append(ids, ops[i].ID)
+		}
+		if err := tx.Where("operation_id IN ?", ids).Delete(&BulkOperationNote{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&BulkOperation{}).Error
+	})
+	if err != nil {
+		return nil, dbError(err, "purge_bulk_operations", errors.PriorityLow,
+			"table", "bulk_operations")
+	}
+	return ops, nil
+}
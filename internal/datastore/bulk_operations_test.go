@@ -0,0 +1,209 @@
+// bulk_operations_test.go: Tests for bulk note changes and undo
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBulkTestDB creates a datastore with three notes, the last one locked
+func setupBulkTestDB(t *testing.T) *DataStore {
+	t.Helper()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&Results{}, &NoteReview{}, &NoteLock{}, &NoteComment{},
+		&BulkOperation{}, &BulkOperationNote{}, &SpeciesCorrection{}))
+	seedSourceNotes(t, ds)
+	require.NoError(t, ds.DB.Create(&Results{NoteID: 1, Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}).Error)
+	require.NoError(t, ds.DB.Create(&NoteReview{NoteID: 1, Verified: "correct", ReviewedBy: "alice"}).Error)
+	require.NoError(t, ds.DB.Create(&NoteComment{NoteID: 1, Entry: "singing"}).Error)
+	require.NoError(t, ds.DB.Create(&NoteLock{NoteID: 3, LockedAt: time.Now()}).Error)
+	return ds
+}
+
+func TestApplyBulkNoteChangeReviewAndUndo(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+
+	result, err := ds.ApplyBulkNoteChange([]uint{1, 2, 3, 99}, &BulkNoteChange{
+		Action:      BulkActionReview,
+		Verified:    "false_positive",
+		PerformedBy: "bob",
+		UndoWindow:  time.Minute,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Operation)
+	assert.Equal(t, 2, result.Operation.NoteCount)
+	assert.Equal(t, []uint{3}, result.Skipped, "locked notes are not reviewed")
+	assert.Equal(t, []uint{99}, result.Missing)
+
+	var reviews []NoteReview
+	require.NoError(t, ds.DB.Order("note_id").Find(&reviews).Error)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, "false_positive", reviews[0].Verified)
+	assert.Equal(t, "bob", reviews[1].ReviewedBy)
+
+	ops, err := ds.GetBulkOperations()
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+
+	undo, err := ds.UndoBulkOperation(result.Operation.ID)
+	require.NoError(t, err)
+	assert.Len(t, undo.Notes, 2)
+	require.NoError(t, ds.DB.Order("note_id").Find(&reviews).Error)
+	require.Len(t, reviews, 1, "note 2 had no review before")
+	assert.Equal(t, uint(1), reviews[0].NoteID)
+	assert.Equal(t, "correct", reviews[0].Verified)
+	assert.Equal(t, "alice", reviews[0].ReviewedBy)
+
+	_, err = ds.UndoBulkOperation(result.Operation.ID)
+	require.ErrorIs(t, err, ErrBulkOperationNotUndoable)
+	_, err = ds.UndoBulkOperation(12345)
+	require.ErrorIs(t, err, ErrBulkOperationNotFound)
+
+	op, err := ds.GetBulkOperation(result.Operation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, BulkActionReview, op.Action)
+	assert.NotNil(t, op.UndoneAt, "undone operations are still returned")
+	_, err = ds.GetBulkOperation(12345)
+	require.ErrorIs(t, err, ErrBulkOperationNotFound)
+}
+
+func TestApplyBulkNoteChangeDeleteAndUndo(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+
+	result, err := ds.ApplyBulkNoteChange([]uint{1, 3}, &BulkNoteChange{Action: BulkActionDelete, UndoWindow: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, result.Notes, 1)
+	assert.Equal(t, []uint{3}, result.Skipped)
+	assert.Len(t, result.Notes[0].Results, 1, "the snapshot includes the results")
+
+	var count int64
+	require.NoError(t, ds.DB.Model(&Note{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+	require.NoError(t, ds.DB.Model(&Results{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	undo, err := ds.UndoBulkOperation(result.Operation.ID)
+	require.NoError(t, err)
+	require.Len(t, undo.Notes, 1)
+
+	var note Note
+	require.NoError(t, ds.DB.Preload("Results").Preload("Review").Preload("Comments").First(&note, 1).Error)
+	assert.Equal(t, "Turdus merula", note.ScientificName)
+	assert.Len(t, note.Results, 1)
+	require.NotNil(t, note.Review)
+	assert.Equal(t, "correct", note.Review.Verified)
+	require.Len(t, note.Comments, 1)
+	assert.Equal(t, "singing", note.Comments[0].Entry)
+}
+
+func TestApplyBulkNoteChangeReassignAndLock(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+
+	result, err := ds.ApplyBulkNoteChange([]uint{1, 2}, &BulkNoteChange{
+		Action:         BulkActionReassign,
+		ScientificName: "Turdus philomelos",
+		CommonName:     "Song Thrush",
+		SpeciesCode:    "sonthr1",
+		UndoWindow:     time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Turdus philomelos_Song Thrush", result.Operation.Details)
+
+	var note Note
+	require.NoError(t, ds.DB.First(&note, 2).Error)
+	assert.Equal(t, "Song Thrush", note.CommonName)
+	assert.Equal(t, "sonthr1", note.SpeciesCode)
+
+	var reviews int64
+	require.NoError(t, ds.DB.Model(&NoteReview{}).Count(&reviews).Error)
+	assert.Zero(t, reviews, "reviews of the previous species are cleared")
+
+	corrections, total, err := ds.GetSpeciesCorrections(&SpeciesCorrectionFilter{Species: "Turdus philomelos"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.NotNil(t, corrections[0].BulkOperationID)
+	assert.Equal(t, result.Operation.ID, *corrections[0].BulkOperationID)
+	assert.Equal(t, CorrectionSourceLabel, corrections[0].Source)
+
+	lock, err := ds.ApplyBulkNoteChange([]uint{1, 2, 3}, &BulkNoteChange{Action: BulkActionLock, UndoWindow: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, 2, lock.Operation.NoteCount)
+	assert.Equal(t, []uint{3}, lock.Skipped, "already locked")
+
+	_, err = ds.UndoBulkOperation(result.Operation.ID)
+	require.NoError(t, err)
+	require.NoError(t, ds.DB.First(&note, 2).Error)
+	assert.Equal(t, "Eurasian Blackbird", note.CommonName)
+	var review NoteReview
+	require.NoError(t, ds.DB.Where("note_id = ?", 1).First(&review).Error, "undo restores the review")
+	assert.Equal(t, "correct", review.Verified)
+	_, total, err = ds.GetSpeciesCorrections(nil)
+	require.NoError(t, err)
+	assert.Zero(t, total, "undo removes the corrections of the reassign")
+
+	_, err = ds.UndoBulkOperation(lock.Operation.ID)
+	require.NoError(t, err)
+	var locks int64
+	require.NoError(t, ds.DB.Model(&NoteLock{}).Count(&locks).Error)
+	assert.Equal(t, int64(1), locks)
+
+	t.Run("no changes records no operation", func(t *testing.T) {
+		result, err := ds.ApplyBulkNoteChange([]uint{1, 2}, &BulkNoteChange{Action: BulkActionUnlock, UndoWindow: time.Minute})
+		require.NoError(t, err)
+		assert.Nil(t, result.Operation)
+		assert.Equal(t, []uint{1, 2}, result.Skipped)
+	})
+
+	t.Run("invalid changes", func(t *testing.T) {
+		_, err := ds.ApplyBulkNoteChange([]uint{1}, &BulkNoteChange{Action: "rename", UndoWindow: time.Minute})
+		require.Error(t, err)
+		_, err = ds.ApplyBulkNoteChange([]uint{1}, &BulkNoteChange{Action: BulkActionReview, Verified: "maybe", UndoWindow: time.Minute})
+		require.Error(t, err)
+		_, err = ds.ApplyBulkNoteChange([]uint{1}, &BulkNoteChange{Action: BulkActionReassign, UndoWindow: time.Minute})
+		require.Error(t, err)
+	})
+}
+
+func TestPurgeBulkOperations(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+
+	result, err := ds.ApplyBulkNoteChange([]uint{1}, &BulkNoteChange{Action: BulkActionLock, UndoWindow: time.Minute})
+	require.NoError(t, err)
+
+	purged, err := ds.PurgeBulkOperations(time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, purged, "operations within the undo window are kept")
+
+	purged, err = ds.PurgeBulkOperations(time.Now().Add(2 * time.Minute))
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, result.Operation.ID, purged[0].ID)
+
+	var snapshots int64
+	require.NoError(t, ds.DB.Model(&BulkOperationNote{}).Count(&snapshots).Error)
+	assert.Zero(t, snapshots)
+	_, err = ds.UndoBulkOperation(result.Operation.ID)
+	require.ErrorIs(t, err, ErrBulkOperationNotFound)
+}
+
+func TestSearchNoteIDsAdvanced(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}, &NoteLock{}))
+	seedSourceNotes(t, ds)
+
+	ids, err := ds.SearchNoteIDsAdvanced(&AdvancedSearchFilters{Species: []string{"Turdus merula"}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids)
+
+	ids, err = ds.SearchNoteIDsAdvanced(&AdvancedSearchFilters{}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids)
+}
@@ -62,6 +62,22 @@ var (
 	ErrImageCacheNotFound = errors.Newf("image cache not found").Component("datastore").Category(errors.CategoryNotFound).Build()
 	// ErrNotificationHistoryNotFound indicates no notification history record exists for the given species and type.
 	ErrNotificationHistoryNotFound = errors.Newf("notification history not found").Component("datastore").Category(errors.CategoryNotFound).Build()
+	// ErrAPITokenNotFound indicates no API token exists for the given hash or ID.
+	ErrAPITokenNotFound = errors.Newf("api token not found").Component("datastore").Category(errors.CategoryNotFound).Build()
+	// ErrUserNotFound indicates no user exists for the given ID, username or identity.
+	ErrUserNotFound = errors.Newf("user not found").Component("datastore").Category(errors.CategoryNotFound).Build()
+	// ErrUserIdentityNotFound indicates the user has no identity with the given ID.
+	ErrUserIdentityNotFound = errors.Newf("user identity not found").Component("datastore").Category(errors.CategoryNotFound).Build()
+	// ErrBulkOperationNotFound indicates no bulk operation exists for the given ID.
+	ErrBulkOperationNotFound = errors.Newf("bulk operation not found").Component("datastore").Category(errors.CategoryNotFound).Build()
+	// ErrBulkOperationNotUndoable indicates the bulk operation was already undone or its undo window has passed.
+	ErrBulkOperationNotUndoable = errors.Newf("bulk operation can no longer be undone").Component("datastore").Category(errors.CategoryConflict).Build()
+	// ErrNoteNotFound indicates no note exists for the given ID.
+	ErrNoteNotFound = errors.Newf("note not found").Component("datastore").Category(errors.CategoryNotFound).Build()
+	// ErrNoteLocked indicates the note is locked and cannot be changed.
+	ErrNoteLocked = errors.Newf("note is locked").Component("datastore").Category(errors.CategoryConflict).Build()
+	// ErrSpeciesUnchanged indicates a species correction names the species the note already has.
+	ErrSpeciesUnchanged = errors.Newf("note already has this species").Component("datastore").Category(errors.CategoryValidation).Build()
 	// ErrDBNotConnected indicates the database is not connected, but partial stats may be available.
 	ErrDBNotConnected = errors.Newf("database not connected").Component("datastore").Category(errors.CategorySystem).Build()
 )
@@ -116,14 +132,14 @@ type Interface interface {
 	GetAllImageCaches(providerName string) ([]ImageCache, error)
 	GetLockedNotesClipPaths() ([]string, error)
 	CountHourlyDetections(date, hour string, duration int) (int64, error)
-	// Analytics methods
-	GetSpeciesSummaryData(ctx context.Context, startDate, endDate string) ([]SpeciesSummaryData, error)
-	GetHourlyAnalyticsData(ctx context.Context, date string, species string) ([]HourlyAnalyticsData, error)
-	GetDailyAnalyticsData(ctx context.Context, startDate, endDate string, species string) ([]DailyAnalyticsData, error)
-	GetDetectionTrends(ctx context.Context, period string, limit int) ([]DailyAnalyticsData, error)
-	GetHourlyDistribution(ctx context.Context, startDate, endDate string, species string) ([]HourlyDistributionData, error)
-	GetNewSpeciesDetections(ctx context.Context, startDate, endDate string, limit, offset int) ([]NewSpeciesData, error)
-	GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate, endDate string, limit, offset int) ([]NewSpeciesData, error)
+	// Analytics methods, a non-empty source limits them to one audio source
+	GetSpeciesSummaryData(ctx context.Context, startDate, endDate, source string) ([]SpeciesSummaryData, error)
+	GetHourlyAnalyticsData(ctx context.Context, date, species, source string) ([]HourlyAnalyticsData, error)
+	GetDailyAnalyticsData(ctx context.Context, startDate, endDate, species, source string) ([]DailyAnalyticsData, error)
+	GetDetectionTrends(ctx context.Context, period, source string, limit int) ([]DailyAnalyticsData, error)
+	GetHourlyDistribution(ctx context.Context, startDate, endDate, species, source string) ([]HourlyDistributionData, error)
+	GetNewSpeciesDetections(ctx context.Context, startDate, endDate, source string, limit, offset int) ([]NewSpeciesData, error)
+	GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate, endDate, source string, limit, offset int) ([]NewSpeciesData, error)
 	// Search functionality
 	SearchDetections(filters *SearchFilters) ([]DetectionRecord, int, error)
 	// Dynamic Threshold methods
@@ -1022,9 +1038,14 @@ func (ds *DataStore) GetNoteReview(noteID string) (*NoteReview, error) {
 
 // SaveNoteReview saves or updates a note review
 func (ds *DataStore) SaveNoteReview(review *NoteReview) error {
-	// Use upsert operation to either create or update the review
+	// Use upsert operation to either create or update the review. The fields are
+	// assigned as a map so that an empty reviewer replaces the previous one.
 	result := ds.DB.Where("note_id = ?", review.NoteID).
-		Assign(*review).
+		Assign(map[string]any{
+			"verified":    review.Verified,
+			"reviewed_by": review.ReviewedBy,
+			"updated_at":  time.Now(),
+		}).
 		FirstOrCreate(review)
 
 	if result.Error != nil {
@@ -1568,6 +1589,72 @@ func (ds *DataStore) GetLockedNotesClipPaths() ([]string, error) {
 	return clipPaths, nil
 }
 
+// ClipNote links an exported clip to the detection that references it
+type ClipNote struct {
+	NoteID         uint
+	ClipName       string
+	ScientificName string
+	CommonName     string
+	Date           string
+	Time           string
+	Confidence     float64
+	Verified       string // Review status, empty when the detection is not reviewed
+	Locked         bool
+}
+
+// clipNotesBatchSize keeps IN clauses below the SQLite bound parameter limit
+const clipNotesBatchSize = 500
+
+// GetClipNotes returns the detections referencing the given clip names, together
+// with their review and lock status. Clip names without a detection are omitted.
+func (ds *DataStore) GetClipNotes(clipNames []string) ([]ClipNote, error) {
+	var clipNotes []ClipNote
+
+	for start := 0; start < len(clipNames); start += clipNotesBatchSize {
+		end := min(start+clipNotesBatchSize, len(clipNames))
+
+		var batch []ClipNote
+		err := ds.DB.Model(&Note{}).
+			Select("notes.id AS note_id, notes.clip_name, notes.scientific_name, notes.common_name, "+
+				"notes.date, notes.time, notes.confidence, note_reviews.verified, "+
+				"note_locks.id IS NOT NULL AS locked").
+			Joins("LEFT JOIN note_reviews ON notes.id = note_reviews.note_id").
+			Joins("LEFT JOIN note_locks ON notes.id = note_locks.note_id").
+			Where("notes.clip_name IN ?", clipNames[start:end]).
+			Scan(&batch).
+			Error
+		if err != nil {
+			return nil, errors.New(err).
+				Component("datastore").
+				Category(errors.CategoryDatabase).
+				Context("operation", "get_clip_notes").
+				Context("clip_count", end-start).
+				Build()
+		}
+		clipNotes = append(clipNotes, batch...)
+	}
+
+	return clipNotes, nil
+}
+
+// NoteExists reports whether a detection of the species is already stored for the
+// given date and time. Importers use it to skip detections that were saved before.
+func (ds *DataStore) NoteExists(date, timeStr, scientificName string) (bool, error) {
+	var count int64
+	err := ds.DB.Model(&Note{}).
+		Where("date = ? AND time = ? AND scientific_name = ?", date, timeStr, scientificName).
+		Limit(1).
+		Count(&count).
+		Error
+	if err != nil {
+		return false, dbError(err, "check_note_exists", errors.PriorityLow,
+			"date", date,
+			"scientific_name", scientificName,
+			"action", "deduplicate_detection")
+	}
+	return count > 0, nil
+}
+
 // CountHourlyDetections counts the number of detections for a specific date and hour.
 func (ds *DataStore) CountHourlyDetections(date, hour string, duration int) (int64, error) {
 	var count int64
@@ -1608,6 +1695,7 @@ type SearchFilters struct {
 	LockedOnly     bool
 	UnlockedOnly   bool
 	Device         string
+	Source         string // Audio source ID or display name
 	TimeOfDay      string // "any", "day", "night", "sunrise", "sunset"
 	Page           int
 	PerPage        int
@@ -1735,6 +1823,10 @@ func applyCommonFilters(query *gorm.DB, filters *SearchFilters, ds *DataStore) *
 		query = query.Where("notes.source_node LIKE ?", "%"+filters.Device+"%")
 	}
 
+	if filters.Source != "" {
+		query = query.Where("(notes.source_id = ? OR notes.source_name = ?)", filters.Source, filters.Source)
+	}
+
 	return query
 }
 
@@ -1934,7 +2026,7 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 
 	// Select necessary fields, including potentially null fields from joins
 	query = query.Select("notes.id, notes.date, notes.time, notes.scientific_name, notes.common_name, notes.confidence, " +
-		"notes.latitude, notes.longitude, notes.clip_name, notes.source_node, " +
+		"notes.latitude, notes.longitude, notes.clip_name, notes.source_node, notes.source_id, notes.source_name, " +
 		"note_reviews.verified AS review_verified, " + // Select review status
 		"note_locks.id IS NOT NULL AS is_locked") // Select lock status as boolean
 
@@ -1995,6 +2087,8 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 		Longitude      float64
 		ClipName       string
 		SourceNode     string
+		SourceID       *string // Use pointer to handle NULL for rows not yet migrated
+		SourceName     *string
 		ReviewVerified *string // Use pointer to handle NULL for review status
 		IsLocked       bool    // Boolean result from IS NOT NULL
 	}
@@ -2089,7 +2183,7 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 			Locked:         scanned.IsLocked, // Use derived status
 			HasAudio:       scanned.ClipName != "",
 			Device:         scanned.SourceNode,
-			Source:         "",        // Source field was runtime-only, not stored in database
+			Source:         scannedSourceName(scanned.SourceID, scanned.SourceName),
 			TimeOfDay:      timeOfDay, // Include calculated time of day
 		}
 
@@ -2099,6 +2193,17 @@ func (ds *DataStore) SearchDetections(filters *SearchFilters) ([]DetectionRecord
 	return results, int(total), nil
 }
 
+// scannedSourceName returns the display name of a scanned audio source, falling back to the source ID
+func scannedSourceName(id, name *string) string {
+	if name != nil && *name != "" {
+		return *name
+	}
+	if id != nil {
+		return *id
+	}
+	return ""
+}
+
 // getSunEventsForDate retrieves sun times for a given date
 func (ds *DataStore) getSunEventsForDate(dateStr string, timestamp time.Time) (suncalc.SunEventTimes, error) {
 	// Check if the sun times are already cached
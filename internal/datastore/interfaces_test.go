@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,3 +29,45 @@ func createDatabase(t *testing.T, settings *conf.Settings) Interface {
 
 	return dataStore
 }
+
+// TestGetClipNotes tests that clips are resolved to their detections with review and lock status
+func TestGetClipNotes(t *testing.T) {
+	t.Parallel()
+
+	ds := createDatabase(t, &conf.Settings{})
+	store, ok := ds.(*SQLiteStore)
+	require.True(t, ok, "expected SQLite store")
+
+	notes := []Note{
+		{Date: "2025-05-01", Time: "06:00:00", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Confidence: 0.9, ClipName: "2025/05/turdus_merula_90p.wav"},
+		{Date: "2025-05-01", Time: "06:05:00", ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.8, ClipName: "2025/05/parus_major_80p.wav"},
+		{Date: "2025-05-01", Time: "06:10:00", ScientificName: "Pica pica", CommonName: "Eurasian Magpie", Confidence: 0.7},
+	}
+	require.NoError(t, store.DB.Create(&notes).Error)
+	require.NoError(t, store.DB.Create(&NoteReview{NoteID: notes[0].ID, Verified: "correct"}).Error)
+	require.NoError(t, store.DB.Create(&NoteLock{NoteID: notes[1].ID, LockedAt: time.Now()}).Error)
+
+	clipNotes, err := store.GetClipNotes([]string{
+		"2025/05/turdus_merula_90p.wav",
+		"2025/05/parus_major_80p.wav",
+		"2025/05/unknown.wav",
+	})
+	require.NoError(t, err)
+	require.Len(t, clipNotes, 2)
+
+	byClip := make(map[string]ClipNote, len(clipNotes))
+	for _, cn := range clipNotes {
+		byClip[cn.ClipName] = cn
+	}
+
+	blackbird := byClip["2025/05/turdus_merula_90p.wav"]
+	assert.Equal(t, notes[0].ID, blackbird.NoteID)
+	assert.Equal(t, "Turdus merula", blackbird.ScientificName)
+	assert.Equal(t, "correct", blackbird.Verified)
+	assert.False(t, blackbird.Locked)
+
+	tit := byClip["2025/05/parus_major_80p.wav"]
+	assert.Equal(t, notes[1].ID, tit.NoteID)
+	assert.Empty(t, tit.Verified)
+	assert.True(t, tit.Locked)
+}
@@ -291,6 +291,11 @@ func performAutoMigration(db *gorm.DB, debug bool, dbType, connectionInfo string
 		return err
 	}
 
+	// Fill the audio source columns of detections saved before sources were stored
+	if err := runDataMigration(db, dbType, noteSourcesMigration, backfillNoteSources); err != nil {
+		return err
+	}
+
 	// Log successful migration completion
 	migrationLogger.Debug("Database migration completed successfully",
 		logger.String("db_type", dbType),
@@ -482,8 +487,18 @@ func migrateTables(db *gorm.DB, dbType string, log logger.Logger) (int, error) {
 		{&NoteLock{}, "note_locks"},
 		{&ImageCache{}, "image_caches"},
 		{&DynamicThreshold{}, "dynamic_thresholds"},
-		{&ThresholdEvent{}, "threshold_events"},            // BG-59: Threshold change history
-		{&NotificationHistory{}, "notification_histories"}, // BG-17: Notification suppression persistence
+		{&ThresholdEvent{}, "threshold_events"},                  // BG-59: Threshold change history
+		{&NotificationHistory{}, "notification_histories"},       // BG-17: Notification suppression persistence
+		{&StoredNotification{}, "stored_notifications"},          // Notification history shown in the web UI
+		{&APIToken{}, "api_tokens"},                              // Scoped API tokens for machine access
+		{&User{}, "users"},                                       // Web UI and API accounts with roles
+		{&UserIdentity{}, "user_identities"},                     // OAuth identities of users
+		{&BulkOperation{}, "bulk_operations"},                    // Undo records of bulk detection changes
+		{&BulkOperationNote{}, "bulk_operation_notes"},           // Note snapshots of bulk operations
+		{&SpeciesCorrection{}, "species_corrections"},            // Audit trail of species corrections
+		{&ReviewSuppressionReset{}, "review_suppression_resets"}, // Resets of the false positive suppression learned from reviews
+		{&SoundLevel{}, "sound_levels"},                          // Downsampled sound levels for noise indicators
+		{&DataMigration{}, "data_migrations"},                    // Markers of applied one-off data migrations
 	}
 
 	GetLogger().Debug("Starting table migrations",
@@ -644,6 +659,74 @@ func createOptimizedIndexes(db *gorm.DB, dbType string, log logger.Logger) error
 	return nil
 }
 
+// noteSourcesMigration is the data migration marker of backfillNoteSources
+const noteSourcesMigration = "backfill_note_sources"
+
+// runDataMigration runs the one-off data migration migrate unless the database has
+// a marker for it, and adds the marker once the migration has succeeded
+func runDataMigration(db *gorm.DB, dbType, name string, migrate func(db *gorm.DB, dbType string) error) error {
+	var count int64
+	if err := db.Model(&DataMigration{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "check_data_migration").
+			Context("db_type", dbType).
+			Context("migration", name).
+			Build()
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := migrate(db, dbType); err != nil {
+		return err
+	}
+
+	if err := db.Create(&DataMigration{Name: name, AppliedAt: time.Now()}).Error; err != nil {
+		return errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "mark_data_migration").
+			Context("db_type", dbType).
+			Context("migration", name).
+			Build()
+	}
+
+	GetLogger().Debug("Data migration applied",
+		logger.String("migration", name),
+		logger.String("db_type", dbType))
+	return nil
+}
+
+// backfillNoteSources replaces NULL audio source columns of existing notes with empty
+// strings. It recovers nothing: detections saved before sources were stored keep an
+// empty source, so they are counted by unfiltered queries and never match a source filter.
+func backfillNoteSources(db *gorm.DB, dbType string) error {
+	result := db.Model(&Note{}).
+		Where("source_id IS NULL OR source_name IS NULL").
+		Updates(map[string]any{
+			"source_id":   gorm.Expr("COALESCE(source_id, '')"),
+			"source_name": gorm.Expr("COALESCE(source_name, '')"),
+		})
+	if result.Error != nil {
+		return errors.New(result.Error).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "backfill_note_sources").
+			Context("db_type", dbType).
+			Context("table_name", "notes").
+			Build()
+	}
+
+	if result.RowsAffected > 0 {
+		GetLogger().Info("Marked existing detections with unknown audio source",
+			logger.Int64("count", result.RowsAffected),
+			logger.String("db_type", dbType))
+	}
+	return nil
+}
+
 // logTableMigration logs the result of a table migration
 func logTableMigration(log logger.Logger, tableName, action string, addedColumns []string, duration time.Duration) {
 	logFields := []logger.Field{
@@ -0,0 +1,275 @@
+// migrate_database.go: Copy all tables between two databases, e.g. from SQLite to MySQL
+package datastore
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultMigrationBatchSize is the number of rows copied per batch when not configured
+const DefaultMigrationBatchSize = 1000
+
+// noteChildFilter skips rows referencing notes that no longer exist. SQLite databases
+// created before foreign keys were enforced can contain such rows, and they would
+// violate the foreign key constraints of the destination.
+const noteChildFilter = "note_id IN (SELECT id FROM notes)"
+
+// MigrationOptions controls a database to database migration
+type MigrationOptions struct {
+	BatchSize int  // Rows per batch, DefaultMigrationBatchSize when zero
+	Resume    bool // Continue an interrupted migration into a non-empty destination
+	// Progress is called after each copied batch with the running totals of a table
+	Progress func(table string, copied, total int64)
+}
+
+// TableMigrationResult holds the outcome of copying a single table
+type TableMigrationResult struct {
+	Table       string
+	SourceRows  int64 // Rows eligible for copying in the source
+	SkippedRows int64 // Orphaned rows in the source that were not copied
+	TargetRows  int64 // Rows in the destination after the copy
+	Copied      int64 // Rows copied by this run
+}
+
+// Verified reports whether the destination holds every eligible source row
+func (r *TableMigrationResult) Verified() bool {
+	return r.TargetRows == r.SourceRows
+}
+
+// MigrationResult holds the outcome of a database migration
+type MigrationResult struct {
+	Tables []TableMigrationResult
+}
+
+// Verified reports whether the row counts of all tables match
+func (r *MigrationResult) Verified() bool {
+	for i := range r.Tables {
+		if !r.Tables[i].Verified() {
+			return false
+		}
+	}
+	return true
+}
+
+// migrationTable describes how a table is copied
+type migrationTable struct {
+	name   string
+	model  any
+	filter string // Optional condition selecting the rows to copy
+	copy   func(ctx context.Context, src, dst *gorm.DB, t *migrationTable, opts *MigrationOptions, total int64) (int64, error)
+}
+
+// migrationTables lists the tables to copy, parents before the tables referencing them
+func migrationTables() []migrationTable {
+	return []migrationTable{
+		{name: "notes", model: &Note{}, copy: copyTable[Note]},
+		{name: "results", model: &Results{}, filter: noteChildFilter, copy: copyTable[Results]},
+		{name: "note_reviews", model: &NoteReview{}, filter: noteChildFilter, copy: copyTable[NoteReview]},
+		{name: "note_comments", model: &NoteComment{}, filter: noteChildFilter, copy: copyTable[NoteComment]},
+		{name: "note_locks", model: &NoteLock{}, filter: noteChildFilter, copy: copyTable[NoteLock]},
+		{name: "daily_events", model: &DailyEvents{}, copy: copyTable[DailyEvents]},
+		{name: "hourly_weather", model: &HourlyWeather{}, copy: copyTable[HourlyWeather]},
+		{name: "image_caches", model: &ImageCache{}, copy: copyTable[ImageCache]},
+		{name: "dynamic_thresholds", model: &DynamicThreshold{}, copy: copyTable[DynamicThreshold]},
+		{name: "threshold_events", model: &ThresholdEvent{}, copy: copyTable[ThresholdEvent]},
+		{name: "notification_histories", model: &NotificationHistory{}, copy: copyTable[NotificationHistory]},
+		{name: "stored_notifications", model: &StoredNotification{}, copy: copyTable[StoredNotification]},
+		{name: "api_tokens", model: &APIToken{}, copy: copyTable[APIToken]},
+		{name: "users", model: &User{}, copy: copyTable[User]},
+		{name: "user_identities", model: &UserIdentity{}, copy: copyTable[UserIdentity]},
+		{name: "bulk_operations", model: &BulkOperation{}, copy: copyTable[BulkOperation]},
+		{name: "bulk_operation_notes", model: &BulkOperationNote{}, copy: copyTable[BulkOperationNote]},
+		{name: "species_corrections", model: &SpeciesCorrection{}, copy: copyTable[SpeciesCorrection]},
+		{name: "review_suppression_resets", model: &ReviewSuppressionReset{}, copy: copyTable[ReviewSuppressionReset]},
+		{name: "sound_levels", model: &SoundLevel{}, copy: copyTable[SoundLevel]},
+	}
+}
+
+// MigrateDatabase copies every table from src to dst and verifies the row counts.
+// Both databases must already have the current schema, which is the case for databases
+// opened through SQLiteStore.Open or MySQLStore.Open. Rows keep their primary keys, so
+// relations between tables are preserved. Rows that already exist in the destination
+// are left untouched, which makes an interrupted migration safe to resume.
+func MigrateDatabase(ctx context.Context, src, dst *gorm.DB, opts MigrationOptions) (*MigrationResult, error) {
+	if src == nil || dst == nil {
+		return nil, validationError("source and destination databases are required", "database", nil)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultMigrationBatchSize
+	}
+
+	tables := migrationTables()
+
+	if !opts.Resume {
+		for i := range tables {
+			var count int64
+			if err := dst.WithContext(ctx).Model(tables[i].model).Count(&count).Error; err != nil {
+				return nil, dbError(err, "check_migration_target", errors.PriorityHigh,
+					"table", tables[i].name,
+					"action", "migrate_database")
+			}
+			if count > 0 {
+				return nil, errors.Newf("destination table %s already contains %d rows, use resume to continue an earlier migration", tables[i].name, count).
+					Component("datastore").
+					Category(errors.CategoryValidation).
+					Context("operation", "check_migration_target").
+					Context("table", tables[i].name).
+					Build()
+			}
+		}
+	}
+
+	result := &MigrationResult{Tables: make([]TableMigrationResult, 0, len(tables))}
+	for i := range tables {
+		tableResult, err := migrateTableData(ctx, src, dst, &tables[i], &opts)
+		if err != nil {
+			return result, err
+		}
+		result.Tables = append(result.Tables, *tableResult)
+	}
+
+	GetLogger().Info("Database migration finished",
+		logger.Int("tables", len(result.Tables)),
+		logger.Bool("verified", result.Verified()))
+	return result, nil
+}
+
+// migrateTableData copies a single table and counts the rows on both sides
+func migrateTableData(ctx context.Context, src, dst *gorm.DB, t *migrationTable, opts *MigrationOptions) (*TableMigrationResult, error) {
+	res := &TableMigrationResult{Table: t.name}
+
+	var allRows int64
+	if err := src.WithContext(ctx).Model(t.model).Count(&allRows).Error; err != nil {
+		return nil, dbError(err, "count_source_rows", errors.PriorityHigh,
+			"table", t.name,
+			"action", "migrate_database")
+	}
+	res.SourceRows = allRows
+	if t.filter != "" {
+		if err := src.WithContext(ctx).Model(t.model).Where(t.filter).Count(&res.SourceRows).Error; err != nil {
+			return nil, dbError(err, "count_source_rows", errors.PriorityHigh,
+				"table", t.name,
+				"action", "migrate_database")
+		}
+	}
+	res.SkippedRows = allRows - res.SourceRows
+	if res.SkippedRows > 0 {
+		GetLogger().Warn("Skipping orphaned rows during database migration",
+			logger.String("table", t.name),
+			logger.Int64("count", res.SkippedRows))
+	}
+
+	copied, err := t.copy(ctx, src, dst, t, opts, res.SourceRows)
+	if err != nil {
+		return nil, err
+	}
+	res.Copied = copied
+
+	if err := dst.WithContext(ctx).Model(t.model).Count(&res.TargetRows).Error; err != nil {
+		return nil, dbError(err, "count_target_rows", errors.PriorityHigh,
+			"table", t.name,
+			"action", "verify_migration")
+	}
+
+	GetLogger().Info("Migrated database table",
+		logger.String("table", t.name),
+		logger.Int64("copied", res.Copied),
+		logger.Int64("source_rows", res.SourceRows),
+		logger.Int64("target_rows", res.TargetRows))
+	return res, nil
+}
+
+// copyTable copies the rows of a table in primary key order. For integer keys the copy
+// starts after the highest key already in the destination, other tables are copied
+// again with existing rows skipped.
+func copyTable[T any](ctx context.Context, src, dst *gorm.DB, t *migrationTable, opts *MigrationOptions, total int64) (int64, error) {
+	stmt := &gorm.Statement{DB: src}
+	if err := stmt.Parse(new(T)); err != nil {
+		return 0, dbError(err, "parse_table_schema", errors.PriorityHigh,
+			"table", t.name,
+			"action", "migrate_database")
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return 0, errors.Newf("table %s has no primary key", t.name).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Context("operation", "migrate_table").
+			Context("table", t.name).
+			Build()
+	}
+	integerKey := pk.DataType == schema.Uint || pk.DataType == schema.Int
+
+	var lastKey any
+	var done int64
+	if integerKey {
+		var maxKey *uint64
+		if err := dst.WithContext(ctx).Model(new(T)).Select("MAX(" + pk.DBName + ")").Scan(&maxKey).Error; err != nil {
+			return 0, dbError(err, "find_resume_position", errors.PriorityHigh,
+				"table", t.name,
+				"action", "migrate_database")
+		}
+		if maxKey != nil {
+			lastKey = *maxKey
+			// Rows up to the resume position were copied by an earlier run
+			query := src.WithContext(ctx).Model(new(T)).Where(pk.DBName+" <= ?", lastKey)
+			if t.filter != "" {
+				query = query.Where(t.filter)
+			}
+			if err := query.Count(&done).Error; err != nil {
+				return 0, dbError(err, "count_copied_rows", errors.PriorityLow,
+					"table", t.name,
+					"action", "migrate_database")
+			}
+		}
+	}
+
+	var copied int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return copied, err
+		}
+
+		query := src.WithContext(ctx).Order(pk.DBName).Limit(opts.BatchSize)
+		if t.filter != "" {
+			query = query.Where(t.filter)
+		}
+		if lastKey != nil {
+			query = query.Where(pk.DBName+" > ?", lastKey)
+		}
+
+		var batch []T
+		if err := query.Find(&batch).Error; err != nil {
+			return copied, dbError(err, "read_migration_batch", errors.PriorityHigh,
+				"table", t.name,
+				"action", "migrate_database")
+		}
+		if len(batch) == 0 {
+			return copied, nil
+		}
+
+		result := dst.WithContext(ctx).
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&batch)
+		if result.Error != nil {
+			return copied, dbError(result.Error, "write_migration_batch", errors.PriorityHigh,
+				"table", t.name,
+				"batch_size", len(batch),
+				"action", "migrate_database")
+		}
+		copied += result.RowsAffected
+		done += int64(len(batch))
+
+		lastKey, _ = pk.ValueOf(ctx, reflect.ValueOf(&batch[len(batch)-1]).Elem())
+		if opts.Progress != nil {
+			opts.Progress(t.name, done, total)
+		}
+	}
+}
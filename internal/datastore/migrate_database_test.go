@@ -0,0 +1,120 @@
+// migrate_database_test.go: Tests for copying data between databases
+package datastore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// openMigrationTestDB creates a file backed SQLite database with the full schema
+func openMigrationTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name)), &gorm.Config{})
+	require.NoError(t, err)
+	for _, table := range migrationTables() {
+		require.NoError(t, db.AutoMigrate(table.model))
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// seedMigrationSource adds notes with relations, weather data and a notification
+func seedMigrationSource(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	for i := 1; i <= 5; i++ {
+		note := Note{
+			Date:           "2024-05-01",
+			Time:           "06:00:00",
+			ScientificName: "Turdus merula",
+			CommonName:     "Eurasian Blackbird",
+			Confidence:     0.8,
+			Source:         AudioSource{ID: "rtsp_garden", DisplayName: "Garden"},
+			Results:        []Results{{Species: "Turdus merula", Confidence: 0.8}},
+		}
+		require.NoError(t, db.Create(&note).Error)
+	}
+	require.NoError(t, db.Create(&NoteReview{NoteID: 1, Verified: "correct"}).Error)
+	require.NoError(t, db.Create(&NoteComment{NoteID: 2, Entry: "Clear song"}).Error)
+	require.NoError(t, db.Create(&NoteLock{NoteID: 3, LockedAt: time.Now()}).Error)
+
+	// Orphaned result left behind by a note deleted without foreign key enforcement
+	require.NoError(t, db.Create(&Results{NoteID: 999, Species: "Parus major"}).Error)
+
+	daily := DailyEvents{Date: "2024-05-01", Country: "FI", CityName: "Helsinki"}
+	require.NoError(t, db.Create(&daily).Error)
+	require.NoError(t, db.Create(&HourlyWeather{DailyEventsID: daily.ID, Time: time.Now(), Temperature: 12.5}).Error)
+
+	require.NoError(t, db.Create(&StoredNotification{
+		ID: "0b8f6f4e-8f3a-4c1e-9d3c-1f2a3b4c5d6e", Type: "detection", Priority: "high",
+		Status: "unread", Title: "Eurasian Blackbird detected", Timestamp: time.Now(),
+	}).Error)
+}
+
+func TestMigrateDatabase(t *testing.T) {
+	t.Parallel()
+	src := openMigrationTestDB(t, "source.db")
+	dst := openMigrationTestDB(t, "target.db")
+	seedMigrationSource(t, src)
+
+	var progressCalls int
+	result, err := MigrateDatabase(context.Background(), src, dst, MigrationOptions{
+		BatchSize: 2,
+		Progress:  func(table string, copied, total int64) { progressCalls++ },
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Verified())
+	assert.Positive(t, progressCalls)
+
+	byTable := make(map[string]TableMigrationResult)
+	for _, r := range result.Tables {
+		byTable[r.Table] = r
+	}
+	assert.Equal(t, int64(5), byTable["notes"].TargetRows)
+	assert.Equal(t, int64(5), byTable["results"].TargetRows)
+	assert.Equal(t, int64(1), byTable["results"].SkippedRows)
+	assert.Equal(t, int64(1), byTable["hourly_weather"].TargetRows)
+	assert.Equal(t, int64(1), byTable["stored_notifications"].TargetRows)
+
+	// Relations and stored columns survive the copy
+	var note Note
+	require.NoError(t, dst.Preload("Review").Preload("Results").First(&note, 1).Error)
+	require.NotNil(t, note.Review)
+	assert.Equal(t, "correct", note.Review.Verified)
+	assert.Len(t, note.Results, 1)
+	assert.Equal(t, "Garden", note.Source.DisplayName)
+}
+
+func TestMigrateDatabaseResume(t *testing.T) {
+	t.Parallel()
+	src := openMigrationTestDB(t, "source.db")
+	dst := openMigrationTestDB(t, "target.db")
+	seedMigrationSource(t, src)
+
+	// Simulate an interrupted run that copied the first notes
+	var firstNotes []Note
+	require.NoError(t, src.Order("id").Limit(2).Find(&firstNotes).Error)
+	require.NoError(t, dst.Omit("Results").Create(&firstNotes).Error)
+
+	_, err := MigrateDatabase(context.Background(), src, dst, MigrationOptions{})
+	require.Error(t, err, "a non-empty destination requires resume")
+
+	result, err := MigrateDatabase(context.Background(), src, dst, MigrationOptions{Resume: true})
+	require.NoError(t, err)
+	assert.True(t, result.Verified())
+	assert.Equal(t, "notes", result.Tables[0].Table)
+	assert.Equal(t, int64(3), result.Tables[0].Copied)
+	assert.Equal(t, int64(5), result.Tables[0].TargetRows)
+}
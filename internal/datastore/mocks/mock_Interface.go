@@ -1101,9 +1101,9 @@ func (_c *MockInterface_GetAllNotes_Call) RunAndReturn(run func() ([]datastore.N
 	return _c
 }
 
-// GetDailyAnalyticsData provides a mock function with given fields: ctx, startDate, endDate, species
-func (_m *MockInterface) GetDailyAnalyticsData(ctx context.Context, startDate string, endDate string, species string) ([]datastore.DailyAnalyticsData, error) {
-	ret := _m.Called(ctx, startDate, endDate, species)
+// GetDailyAnalyticsData provides a mock function with given fields: ctx, startDate, endDate, species, source
+func (_m *MockInterface) GetDailyAnalyticsData(ctx context.Context, startDate string, endDate string, species string, source string) ([]datastore.DailyAnalyticsData, error) {
+	ret := _m.Called(ctx, startDate, endDate, species, source)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetDailyAnalyticsData")
@@ -1111,19 +1111,19 @@ func (_m *MockInterface) GetDailyAnalyticsData(ctx context.Context, startDate st
 
 	var r0 []datastore.DailyAnalyticsData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]datastore.DailyAnalyticsData, error)); ok {
-		return rf(ctx, startDate, endDate, species)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]datastore.DailyAnalyticsData, error)); ok {
+		return rf(ctx, startDate, endDate, species, source)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []datastore.DailyAnalyticsData); ok {
-		r0 = rf(ctx, startDate, endDate, species)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []datastore.DailyAnalyticsData); ok {
+		r0 = rf(ctx, startDate, endDate, species, source)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.DailyAnalyticsData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, startDate, endDate, species)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, startDate, endDate, species, source)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1141,13 +1141,14 @@ type MockInterface_GetDailyAnalyticsData_Call struct {
 //   - startDate string
 //   - endDate string
 //   - species string
-func (_e *MockInterface_Expecter) GetDailyAnalyticsData(ctx interface{}, startDate interface{}, endDate interface{}, species interface{}) *MockInterface_GetDailyAnalyticsData_Call {
-	return &MockInterface_GetDailyAnalyticsData_Call{Call: _e.mock.On("GetDailyAnalyticsData", ctx, startDate, endDate, species)}
+//   - source string
+func (_e *MockInterface_Expecter) GetDailyAnalyticsData(ctx interface{}, startDate interface{}, endDate interface{}, species interface{}, source interface{}) *MockInterface_GetDailyAnalyticsData_Call {
+	return &MockInterface_GetDailyAnalyticsData_Call{Call: _e.mock.On("GetDailyAnalyticsData", ctx, startDate, endDate, species, source)}
 }
 
-func (_c *MockInterface_GetDailyAnalyticsData_Call) Run(run func(ctx context.Context, startDate string, endDate string, species string)) *MockInterface_GetDailyAnalyticsData_Call {
+func (_c *MockInterface_GetDailyAnalyticsData_Call) Run(run func(ctx context.Context, startDate string, endDate string, species string, source string)) *MockInterface_GetDailyAnalyticsData_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
 	})
 	return _c
 }
@@ -1157,7 +1158,7 @@ func (_c *MockInterface_GetDailyAnalyticsData_Call) Return(_a0 []datastore.Daily
 	return _c
 }
 
-func (_c *MockInterface_GetDailyAnalyticsData_Call) RunAndReturn(run func(context.Context, string, string, string) ([]datastore.DailyAnalyticsData, error)) *MockInterface_GetDailyAnalyticsData_Call {
+func (_c *MockInterface_GetDailyAnalyticsData_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]datastore.DailyAnalyticsData, error)) *MockInterface_GetDailyAnalyticsData_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1275,9 +1276,9 @@ func (_c *MockInterface_GetDatabaseStats_Call) RunAndReturn(run func() (*datasto
 	return _c
 }
 
-// GetDetectionTrends provides a mock function with given fields: ctx, period, limit
-func (_m *MockInterface) GetDetectionTrends(ctx context.Context, period string, limit int) ([]datastore.DailyAnalyticsData, error) {
-	ret := _m.Called(ctx, period, limit)
+// GetDetectionTrends provides a mock function with given fields: ctx, period, source, limit
+func (_m *MockInterface) GetDetectionTrends(ctx context.Context, period string, source string, limit int) ([]datastore.DailyAnalyticsData, error) {
+	ret := _m.Called(ctx, period, source, limit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetDetectionTrends")
@@ -1285,19 +1286,19 @@ func (_m *MockInterface) GetDetectionTrends(ctx context.Context, period string,
 
 	var r0 []datastore.DailyAnalyticsData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]datastore.DailyAnalyticsData, error)); ok {
-		return rf(ctx, period, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]datastore.DailyAnalyticsData, error)); ok {
+		return rf(ctx, period, source, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) []datastore.DailyAnalyticsData); ok {
-		r0 = rf(ctx, period, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []datastore.DailyAnalyticsData); ok {
+		r0 = rf(ctx, period, source, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.DailyAnalyticsData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
-		r1 = rf(ctx, period, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, period, source, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1313,14 +1314,15 @@ type MockInterface_GetDetectionTrends_Call struct {
 // GetDetectionTrends is a helper method to define mock.On call
 //   - ctx context.Context
 //   - period string
+//   - source string
 //   - limit int
-func (_e *MockInterface_Expecter) GetDetectionTrends(ctx interface{}, period interface{}, limit interface{}) *MockInterface_GetDetectionTrends_Call {
-	return &MockInterface_GetDetectionTrends_Call{Call: _e.mock.On("GetDetectionTrends", ctx, period, limit)}
+func (_e *MockInterface_Expecter) GetDetectionTrends(ctx interface{}, period interface{}, source interface{}, limit interface{}) *MockInterface_GetDetectionTrends_Call {
+	return &MockInterface_GetDetectionTrends_Call{Call: _e.mock.On("GetDetectionTrends", ctx, period, source, limit)}
 }
 
-func (_c *MockInterface_GetDetectionTrends_Call) Run(run func(ctx context.Context, period string, limit int)) *MockInterface_GetDetectionTrends_Call {
+func (_c *MockInterface_GetDetectionTrends_Call) Run(run func(ctx context.Context, period string, source string, limit int)) *MockInterface_GetDetectionTrends_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(int))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
 	})
 	return _c
 }
@@ -1330,7 +1332,7 @@ func (_c *MockInterface_GetDetectionTrends_Call) Return(_a0 []datastore.DailyAna
 	return _c
 }
 
-func (_c *MockInterface_GetDetectionTrends_Call) RunAndReturn(run func(context.Context, string, int) ([]datastore.DailyAnalyticsData, error)) *MockInterface_GetDetectionTrends_Call {
+func (_c *MockInterface_GetDetectionTrends_Call) RunAndReturn(run func(context.Context, string, string, int) ([]datastore.DailyAnalyticsData, error)) *MockInterface_GetDetectionTrends_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1471,9 +1473,9 @@ func (_c *MockInterface_GetDynamicThresholdStats_Call) RunAndReturn(run func() (
 	return _c
 }
 
-// GetHourlyAnalyticsData provides a mock function with given fields: ctx, date, species
-func (_m *MockInterface) GetHourlyAnalyticsData(ctx context.Context, date string, species string) ([]datastore.HourlyAnalyticsData, error) {
-	ret := _m.Called(ctx, date, species)
+// GetHourlyAnalyticsData provides a mock function with given fields: ctx, date, species, source
+func (_m *MockInterface) GetHourlyAnalyticsData(ctx context.Context, date string, species string, source string) ([]datastore.HourlyAnalyticsData, error) {
+	ret := _m.Called(ctx, date, species, source)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetHourlyAnalyticsData")
@@ -1481,19 +1483,19 @@ func (_m *MockInterface) GetHourlyAnalyticsData(ctx context.Context, date string
 
 	var r0 []datastore.HourlyAnalyticsData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]datastore.HourlyAnalyticsData, error)); ok {
-		return rf(ctx, date, species)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]datastore.HourlyAnalyticsData, error)); ok {
+		return rf(ctx, date, species, source)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) []datastore.HourlyAnalyticsData); ok {
-		r0 = rf(ctx, date, species)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []datastore.HourlyAnalyticsData); ok {
+		r0 = rf(ctx, date, species, source)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.HourlyAnalyticsData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, date, species)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, date, species, source)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1510,13 +1512,14 @@ type MockInterface_GetHourlyAnalyticsData_Call struct {
 //   - ctx context.Context
 //   - date string
 //   - species string
-func (_e *MockInterface_Expecter) GetHourlyAnalyticsData(ctx interface{}, date interface{}, species interface{}) *MockInterface_GetHourlyAnalyticsData_Call {
-	return &MockInterface_GetHourlyAnalyticsData_Call{Call: _e.mock.On("GetHourlyAnalyticsData", ctx, date, species)}
+//   - source string
+func (_e *MockInterface_Expecter) GetHourlyAnalyticsData(ctx interface{}, date interface{}, species interface{}, source interface{}) *MockInterface_GetHourlyAnalyticsData_Call {
+	return &MockInterface_GetHourlyAnalyticsData_Call{Call: _e.mock.On("GetHourlyAnalyticsData", ctx, date, species, source)}
 }
 
-func (_c *MockInterface_GetHourlyAnalyticsData_Call) Run(run func(ctx context.Context, date string, species string)) *MockInterface_GetHourlyAnalyticsData_Call {
+func (_c *MockInterface_GetHourlyAnalyticsData_Call) Run(run func(ctx context.Context, date string, species string, source string)) *MockInterface_GetHourlyAnalyticsData_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -1526,7 +1529,7 @@ func (_c *MockInterface_GetHourlyAnalyticsData_Call) Return(_a0 []datastore.Hour
 	return _c
 }
 
-func (_c *MockInterface_GetHourlyAnalyticsData_Call) RunAndReturn(run func(context.Context, string, string) ([]datastore.HourlyAnalyticsData, error)) *MockInterface_GetHourlyAnalyticsData_Call {
+func (_c *MockInterface_GetHourlyAnalyticsData_Call) RunAndReturn(run func(context.Context, string, string, string) ([]datastore.HourlyAnalyticsData, error)) *MockInterface_GetHourlyAnalyticsData_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1593,9 +1596,9 @@ func (_c *MockInterface_GetHourlyDetections_Call) RunAndReturn(run func(string,
 	return _c
 }
 
-// GetHourlyDistribution provides a mock function with given fields: ctx, startDate, endDate, species
-func (_m *MockInterface) GetHourlyDistribution(ctx context.Context, startDate string, endDate string, species string) ([]datastore.HourlyDistributionData, error) {
-	ret := _m.Called(ctx, startDate, endDate, species)
+// GetHourlyDistribution provides a mock function with given fields: ctx, startDate, endDate, species, source
+func (_m *MockInterface) GetHourlyDistribution(ctx context.Context, startDate string, endDate string, species string, source string) ([]datastore.HourlyDistributionData, error) {
+	ret := _m.Called(ctx, startDate, endDate, species, source)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetHourlyDistribution")
@@ -1603,19 +1606,19 @@ func (_m *MockInterface) GetHourlyDistribution(ctx context.Context, startDate st
 
 	var r0 []datastore.HourlyDistributionData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]datastore.HourlyDistributionData, error)); ok {
-		return rf(ctx, startDate, endDate, species)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) ([]datastore.HourlyDistributionData, error)); ok {
+		return rf(ctx, startDate, endDate, species, source)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []datastore.HourlyDistributionData); ok {
-		r0 = rf(ctx, startDate, endDate, species)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []datastore.HourlyDistributionData); ok {
+		r0 = rf(ctx, startDate, endDate, species, source)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.HourlyDistributionData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, startDate, endDate, species)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, startDate, endDate, species, source)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1633,13 +1636,14 @@ type MockInterface_GetHourlyDistribution_Call struct {
 //   - startDate string
 //   - endDate string
 //   - species string
-func (_e *MockInterface_Expecter) GetHourlyDistribution(ctx interface{}, startDate interface{}, endDate interface{}, species interface{}) *MockInterface_GetHourlyDistribution_Call {
-	return &MockInterface_GetHourlyDistribution_Call{Call: _e.mock.On("GetHourlyDistribution", ctx, startDate, endDate, species)}
+//   - source string
+func (_e *MockInterface_Expecter) GetHourlyDistribution(ctx interface{}, startDate interface{}, endDate interface{}, species interface{}, source interface{}) *MockInterface_GetHourlyDistribution_Call {
+	return &MockInterface_GetHourlyDistribution_Call{Call: _e.mock.On("GetHourlyDistribution", ctx, startDate, endDate, species, source)}
 }
 
-func (_c *MockInterface_GetHourlyDistribution_Call) Run(run func(ctx context.Context, startDate string, endDate string, species string)) *MockInterface_GetHourlyDistribution_Call {
+func (_c *MockInterface_GetHourlyDistribution_Call) Run(run func(ctx context.Context, startDate string, endDate string, species string, source string)) *MockInterface_GetHourlyDistribution_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
 	})
 	return _c
 }
@@ -1649,7 +1653,7 @@ func (_c *MockInterface_GetHourlyDistribution_Call) Return(_a0 []datastore.Hourl
 	return _c
 }
 
-func (_c *MockInterface_GetHourlyDistribution_Call) RunAndReturn(run func(context.Context, string, string, string) ([]datastore.HourlyDistributionData, error)) *MockInterface_GetHourlyDistribution_Call {
+func (_c *MockInterface_GetHourlyDistribution_Call) RunAndReturn(run func(context.Context, string, string, string, string) ([]datastore.HourlyDistributionData, error)) *MockInterface_GetHourlyDistribution_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2004,9 +2008,9 @@ func (_c *MockInterface_GetLockedNotesClipPaths_Call) RunAndReturn(run func() ([
 	return _c
 }
 
-// GetNewSpeciesDetections provides a mock function with given fields: ctx, startDate, endDate, limit, offset
-func (_m *MockInterface) GetNewSpeciesDetections(ctx context.Context, startDate string, endDate string, limit int, offset int) ([]datastore.NewSpeciesData, error) {
-	ret := _m.Called(ctx, startDate, endDate, limit, offset)
+// GetNewSpeciesDetections provides a mock function with given fields: ctx, startDate, endDate, source, limit, offset
+func (_m *MockInterface) GetNewSpeciesDetections(ctx context.Context, startDate string, endDate string, source string, limit int, offset int) ([]datastore.NewSpeciesData, error) {
+	ret := _m.Called(ctx, startDate, endDate, source, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNewSpeciesDetections")
@@ -2014,19 +2018,19 @@ func (_m *MockInterface) GetNewSpeciesDetections(ctx context.Context, startDate
 
 	var r0 []datastore.NewSpeciesData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) ([]datastore.NewSpeciesData, error)); ok {
-		return rf(ctx, startDate, endDate, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) ([]datastore.NewSpeciesData, error)); ok {
+		return rf(ctx, startDate, endDate, source, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) []datastore.NewSpeciesData); ok {
-		r0 = rf(ctx, startDate, endDate, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) []datastore.NewSpeciesData); ok {
+		r0 = rf(ctx, startDate, endDate, source, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.NewSpeciesData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
-		r1 = rf(ctx, startDate, endDate, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int, int) error); ok {
+		r1 = rf(ctx, startDate, endDate, source, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2043,15 +2047,16 @@ type MockInterface_GetNewSpeciesDetections_Call struct {
 //   - ctx context.Context
 //   - startDate string
 //   - endDate string
+//   - source string
 //   - limit int
 //   - offset int
-func (_e *MockInterface_Expecter) GetNewSpeciesDetections(ctx interface{}, startDate interface{}, endDate interface{}, limit interface{}, offset interface{}) *MockInterface_GetNewSpeciesDetections_Call {
-	return &MockInterface_GetNewSpeciesDetections_Call{Call: _e.mock.On("GetNewSpeciesDetections", ctx, startDate, endDate, limit, offset)}
+func (_e *MockInterface_Expecter) GetNewSpeciesDetections(ctx interface{}, startDate interface{}, endDate interface{}, source interface{}, limit interface{}, offset interface{}) *MockInterface_GetNewSpeciesDetections_Call {
+	return &MockInterface_GetNewSpeciesDetections_Call{Call: _e.mock.On("GetNewSpeciesDetections", ctx, startDate, endDate, source, limit, offset)}
 }
 
-func (_c *MockInterface_GetNewSpeciesDetections_Call) Run(run func(ctx context.Context, startDate string, endDate string, limit int, offset int)) *MockInterface_GetNewSpeciesDetections_Call {
+func (_c *MockInterface_GetNewSpeciesDetections_Call) Run(run func(ctx context.Context, startDate string, endDate string, source string, limit int, offset int)) *MockInterface_GetNewSpeciesDetections_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int), args[4].(int))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(int), args[5].(int))
 	})
 	return _c
 }
@@ -2061,7 +2066,7 @@ func (_c *MockInterface_GetNewSpeciesDetections_Call) Return(_a0 []datastore.New
 	return _c
 }
 
-func (_c *MockInterface_GetNewSpeciesDetections_Call) RunAndReturn(run func(context.Context, string, string, int, int) ([]datastore.NewSpeciesData, error)) *MockInterface_GetNewSpeciesDetections_Call {
+func (_c *MockInterface_GetNewSpeciesDetections_Call) RunAndReturn(run func(context.Context, string, string, string, int, int) ([]datastore.NewSpeciesData, error)) *MockInterface_GetNewSpeciesDetections_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2413,9 +2418,9 @@ func (_c *MockInterface_GetRecentThresholdEvents_Call) RunAndReturn(run func(int
 	return _c
 }
 
-// GetSpeciesFirstDetectionInPeriod provides a mock function with given fields: ctx, startDate, endDate, limit, offset
-func (_m *MockInterface) GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate string, endDate string, limit int, offset int) ([]datastore.NewSpeciesData, error) {
-	ret := _m.Called(ctx, startDate, endDate, limit, offset)
+// GetSpeciesFirstDetectionInPeriod provides a mock function with given fields: ctx, startDate, endDate, source, limit, offset
+func (_m *MockInterface) GetSpeciesFirstDetectionInPeriod(ctx context.Context, startDate string, endDate string, source string, limit int, offset int) ([]datastore.NewSpeciesData, error) {
+	ret := _m.Called(ctx, startDate, endDate, source, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSpeciesFirstDetectionInPeriod")
@@ -2423,19 +2428,19 @@ func (_m *MockInterface) GetSpeciesFirstDetectionInPeriod(ctx context.Context, s
 
 	var r0 []datastore.NewSpeciesData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) ([]datastore.NewSpeciesData, error)); ok {
-		return rf(ctx, startDate, endDate, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) ([]datastore.NewSpeciesData, error)); ok {
+		return rf(ctx, startDate, endDate, source, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, int) []datastore.NewSpeciesData); ok {
-		r0 = rf(ctx, startDate, endDate, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int, int) []datastore.NewSpeciesData); ok {
+		r0 = rf(ctx, startDate, endDate, source, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.NewSpeciesData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int, int) error); ok {
-		r1 = rf(ctx, startDate, endDate, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int, int) error); ok {
+		r1 = rf(ctx, startDate, endDate, source, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2452,15 +2457,16 @@ type MockInterface_GetSpeciesFirstDetectionInPeriod_Call struct {
 //   - ctx context.Context
 //   - startDate string
 //   - endDate string
+//   - source string
 //   - limit int
 //   - offset int
-func (_e *MockInterface_Expecter) GetSpeciesFirstDetectionInPeriod(ctx interface{}, startDate interface{}, endDate interface{}, limit interface{}, offset interface{}) *MockInterface_GetSpeciesFirstDetectionInPeriod_Call {
-	return &MockInterface_GetSpeciesFirstDetectionInPeriod_Call{Call: _e.mock.On("GetSpeciesFirstDetectionInPeriod", ctx, startDate, endDate, limit, offset)}
+func (_e *MockInterface_Expecter) GetSpeciesFirstDetectionInPeriod(ctx interface{}, startDate interface{}, endDate interface{}, source interface{}, limit interface{}, offset interface{}) *MockInterface_GetSpeciesFirstDetectionInPeriod_Call {
+	return &MockInterface_GetSpeciesFirstDetectionInPeriod_Call{Call: _e.mock.On("GetSpeciesFirstDetectionInPeriod", ctx, startDate, endDate, source, limit, offset)}
 }
 
-func (_c *MockInterface_GetSpeciesFirstDetectionInPeriod_Call) Run(run func(ctx context.Context, startDate string, endDate string, limit int, offset int)) *MockInterface_GetSpeciesFirstDetectionInPeriod_Call {
+func (_c *MockInterface_GetSpeciesFirstDetectionInPeriod_Call) Run(run func(ctx context.Context, startDate string, endDate string, source string, limit int, offset int)) *MockInterface_GetSpeciesFirstDetectionInPeriod_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int), args[4].(int))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(int), args[5].(int))
 	})
 	return _c
 }
@@ -2470,14 +2476,14 @@ func (_c *MockInterface_GetSpeciesFirstDetectionInPeriod_Call) Return(_a0 []data
 	return _c
 }
 
-func (_c *MockInterface_GetSpeciesFirstDetectionInPeriod_Call) RunAndReturn(run func(context.Context, string, string, int, int) ([]datastore.NewSpeciesData, error)) *MockInterface_GetSpeciesFirstDetectionInPeriod_Call {
+func (_c *MockInterface_GetSpeciesFirstDetectionInPeriod_Call) RunAndReturn(run func(context.Context, string, string, string, int, int) ([]datastore.NewSpeciesData, error)) *MockInterface_GetSpeciesFirstDetectionInPeriod_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSpeciesSummaryData provides a mock function with given fields: ctx, startDate, endDate
-func (_m *MockInterface) GetSpeciesSummaryData(ctx context.Context, startDate string, endDate string) ([]datastore.SpeciesSummaryData, error) {
-	ret := _m.Called(ctx, startDate, endDate)
+// GetSpeciesSummaryData provides a mock function with given fields: ctx, startDate, endDate, source
+func (_m *MockInterface) GetSpeciesSummaryData(ctx context.Context, startDate string, endDate string, source string) ([]datastore.SpeciesSummaryData, error) {
+	ret := _m.Called(ctx, startDate, endDate, source)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSpeciesSummaryData")
@@ -2485,19 +2491,19 @@ func (_m *MockInterface) GetSpeciesSummaryData(ctx context.Context, startDate st
 
 	var r0 []datastore.SpeciesSummaryData
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]datastore.SpeciesSummaryData, error)); ok {
-		return rf(ctx, startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]datastore.SpeciesSummaryData, error)); ok {
+		return rf(ctx, startDate, endDate, source)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) []datastore.SpeciesSummaryData); ok {
-		r0 = rf(ctx, startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []datastore.SpeciesSummaryData); ok {
+		r0 = rf(ctx, startDate, endDate, source)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]datastore.SpeciesSummaryData)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, startDate, endDate, source)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2514,13 +2520,14 @@ type MockInterface_GetSpeciesSummaryData_Call struct {
 //   - ctx context.Context
 //   - startDate string
 //   - endDate string
-func (_e *MockInterface_Expecter) GetSpeciesSummaryData(ctx interface{}, startDate interface{}, endDate interface{}) *MockInterface_GetSpeciesSummaryData_Call {
-	return &MockInterface_GetSpeciesSummaryData_Call{Call: _e.mock.On("GetSpeciesSummaryData", ctx, startDate, endDate)}
+//   - source string
+func (_e *MockInterface_Expecter) GetSpeciesSummaryData(ctx interface{}, startDate interface{}, endDate interface{}, source interface{}) *MockInterface_GetSpeciesSummaryData_Call {
+	return &MockInterface_GetSpeciesSummaryData_Call{Call: _e.mock.On("GetSpeciesSummaryData", ctx, startDate, endDate, source)}
 }
 
-func (_c *MockInterface_GetSpeciesSummaryData_Call) Run(run func(ctx context.Context, startDate string, endDate string)) *MockInterface_GetSpeciesSummaryData_Call {
+func (_c *MockInterface_GetSpeciesSummaryData_Call) Run(run func(ctx context.Context, startDate string, endDate string, source string)) *MockInterface_GetSpeciesSummaryData_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
@@ -2530,7 +2537,7 @@ func (_c *MockInterface_GetSpeciesSummaryData_Call) Return(_a0 []datastore.Speci
 	return _c
 }
 
-func (_c *MockInterface_GetSpeciesSummaryData_Call) RunAndReturn(run func(context.Context, string, string) ([]datastore.SpeciesSummaryData, error)) *MockInterface_GetSpeciesSummaryData_Call {
+func (_c *MockInterface_GetSpeciesSummaryData_Call) RunAndReturn(run func(context.Context, string, string, string) ([]datastore.SpeciesSummaryData, error)) *MockInterface_GetSpeciesSummaryData_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,10 +5,11 @@ import "time"
 
 // AudioSource represents a structured audio source with ID, safe string, and display name
 // This allows safe separation of concerns: ID for buffer operations, SafeString for logging, DisplayName for UI
+// ID and DisplayName are stored with each note as source_id and source_name, SafeString is runtime only.
 type AudioSource struct {
-	ID          string `json:"id"`          // Source ID for buffer operations (e.g., "rtsp_87b89761")
-	SafeString  string `json:"safeString"`  // Sanitized connection string for logging (credentials removed)
-	DisplayName string `json:"displayName"` // User-friendly name for UI display
+	ID          string `gorm:"column:id;size:100;index:idx_notes_source_id" json:"id"`              // Source ID for buffer operations (e.g., "rtsp_87b89761")
+	SafeString  string `gorm:"-" json:"safeString"`                                                 // Sanitized connection string for logging (credentials removed)
+	DisplayName string `gorm:"column:name;size:200;index:idx_notes_source_name" json:"displayName"` // User-friendly name for UI display
 }
 
 // Note represents a single observation data point
@@ -18,7 +19,7 @@ type Note struct {
 	Date       string `gorm:"index:idx_notes_date;index:idx_notes_date_commonname_confidence;index:idx_notes_sciname_date;index:idx_notes_sciname_date_optimized,priority:2"`
 	Time       string `gorm:"index:idx_notes_time"`
 	//InputFile      string
-	Source      AudioSource `gorm:"-"` // Runtime only, not stored in database
+	Source      AudioSource `gorm:"embedded;embeddedPrefix:source_"` // Audio source that heard the detection, empty for detections saved before sources were stored
 	BeginTime   time.Time
 	EndTime     time.Time
 	SpeciesCode string
@@ -64,11 +65,12 @@ func (r Results) Copy() Results {
 // NoteReview represents the review status of a Note
 // GORM will automatically create table name as 'note_reviews'
 type NoteReview struct {
-	ID        uint      `gorm:"primaryKey"`
-	NoteID    uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
-	Verified  string    `gorm:"type:varchar(20)"`                                                                                  // Values: "correct", "false_positive"
-	CreatedAt time.Time `gorm:"index"`                                                                                             // When the review was created
-	UpdatedAt time.Time // When the review was last updated
+	ID         uint      `gorm:"primaryKey"`
+	NoteID     uint      `gorm:"uniqueIndex;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
+	Verified   string    `gorm:"type:varchar(20)"`                                                                                  // Values: "correct", "false_positive"
+	ReviewedBy string    `gorm:"size:100"`                                                                                          // User who last reviewed the note, empty when unknown
+	CreatedAt  time.Time `gorm:"index"`                                                                                             // When the review was created
+	UpdatedAt  time.Time // When the review was last updated
 }
 
 // NoteComment represents user comments on a detection
@@ -77,6 +79,7 @@ type NoteComment struct {
 	ID        uint      `gorm:"primaryKey"`
 	NoteID    uint      `gorm:"index;not null;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;foreignKey:NoteID;references:ID"` // Foreign key to associate with Note
 	Entry     string    `gorm:"type:text"`                                                                                   // The actual comment text
+	CreatedBy string    `gorm:"size:100"`                                                                                    // User who wrote the comment, empty when unknown
 	CreatedAt time.Time `gorm:"index"`                                                                                       // When the comment was created
 	UpdatedAt time.Time // When the comment was last updated
 }
@@ -204,3 +207,141 @@ type NotificationHistory struct {
 	CreatedAt        time.Time `gorm:"not null"`                                                             // When first created
 	UpdatedAt        time.Time `gorm:"not null"`                                                             // Last update time
 }
+
+// StoredNotification persists a notification shown in the web UI so that notification
+// history, including read and acknowledged state, survives application restarts.
+// Metadata is stored as a JSON object.
+type StoredNotification struct {
+	ID        string     `gorm:"primaryKey;size:36"`     // Notification UUID
+	Type      string     `gorm:"index;not null;size:20"` // error, warning, info, detection, system
+	Priority  string     `gorm:"index;not null;size:20"` // critical, high, medium, low
+	Status    string     `gorm:"index;not null;size:20"` // unread, read, acknowledged
+	Title     string     `gorm:"not null;size:500"`      // Short summary
+	Message   string     `gorm:"type:text"`              // Detailed message
+	Component string     `gorm:"index;size:100"`         // Source component
+	Metadata  string     `gorm:"type:text"`              // JSON encoded metadata
+	Timestamp time.Time  `gorm:"index;not null"`         // When the notification was created
+	ExpiresAt *time.Time `gorm:"index"`                  // When the notification expires, nil for never
+	UpdatedAt time.Time  `gorm:"not null"`               // Last status change
+}
+
+// APIToken is a long-lived, revocable credential for machine access to the API.
+// Only the SHA-256 hash of the token is stored, the token itself is shown once
+// when it is created. Scopes is a comma separated list of granted scopes.
+type APIToken struct {
+	ID         uint       `gorm:"primaryKey"`
+	Name       string     `gorm:"not null;size:100"`            // Label chosen by the user
+	TokenHash  string     `gorm:"uniqueIndex;not null;size:64"` // Hex encoded SHA-256 of the token
+	Prefix     string     `gorm:"size:16"`                      // Start of the token, identifies it in lists
+	Scopes     string     `gorm:"not null;size:255"`            // Comma separated scopes
+	CreatedBy  string     `gorm:"size:100"`                     // User who created the token
+	CreatedAt  time.Time  `gorm:"not null"`                     // When the token was created
+	ExpiresAt  *time.Time `gorm:"index"`                        // When the token expires, nil for never
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`          // When the token was last used
+	LastUsedIP string     `gorm:"size:45"`                      // Client IP of the last use
+	RevokedAt  *time.Time `gorm:"index"`                        // When the token was revoked, nil while active
+}
+
+// User is an account for the web UI and API. Role is one of viewer, reviewer or
+// admin. Users sign in with a password, with an OAuth identity or both.
+type User struct {
+	ID           uint           `gorm:"primaryKey"`
+	Username     string         `gorm:"uniqueIndex;not null;size:100"` // Login name, stored in lower case
+	DisplayName  string         `gorm:"size:100"`                      // Name shown in the web UI
+	Role         string         `gorm:"not null;size:20"`              // viewer, reviewer or admin
+	PasswordHash string         `gorm:"size:100"`                      // bcrypt hash, empty for OAuth only accounts
+	Disabled     bool           `gorm:"not null;default:false"`        // Disabled users cannot sign in
+	CreatedAt    time.Time      `gorm:"not null"`                      // When the user was created
+	UpdatedAt    time.Time      `gorm:"not null"`                      // When the user was last changed
+	LastLoginAt  *time.Time     `gorm:"column:last_login_at"`          // When the user last signed in
+	Identities   []UserIdentity `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// UserIdentity maps an account of an OAuth provider to a User
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index;not null"`                                          // User the identity signs in as
+	Provider  string    `gorm:"not null;size:20;uniqueIndex:idx_user_identity_subject"`  // Provider ID from the config, e.g. "google"
+	Subject   string    `gorm:"not null;size:255;uniqueIndex:idx_user_identity_subject"` // User ID or email reported by the provider
+	CreatedAt time.Time `gorm:"not null"`                                                // When the identity was added
+}
+
+// BulkOperation records a change made to many notes at once. The state of the
+// notes before the change is kept in BulkOperationNote rows until ExpiresAt, so
+// the change can be undone for a short time.
+type BulkOperation struct {
+	ID          uint       `gorm:"primaryKey"`
+	Action      string     `gorm:"not null;size:20"` // review, lock, unlock, delete or reassign
+	Details     string     `gorm:"size:255"`         // Review verdict or new species of the change
+	NoteCount   int        `gorm:"not null"`         // Number of notes changed
+	PerformedBy string     `gorm:"size:100"`         // User who made the change, empty when unknown
+	CreatedAt   time.Time  `gorm:"not null"`         // When the change was made
+	ExpiresAt   time.Time  `gorm:"index;not null"`   // Undo is possible until this time
+	UndoneAt    *time.Time // When the change was undone, nil while in effect
+}
+
+// BulkOperationNote is the state of one note before a bulk operation. NoteID has
+// no foreign key because deleted notes are restored from the snapshot.
+type BulkOperationNote struct {
+	ID          uint   `gorm:"primaryKey"`
+	OperationID uint   `gorm:"index;not null"` // BulkOperation the snapshot belongs to
+	NoteID      uint   `gorm:"not null"`       // Note the snapshot was taken of
+	Snapshot    string `gorm:"type:text"`      // JSON of the note with its results, review, comments and lock
+}
+
+// ReviewSuppressionReset records a reset of the false positive suppression learned
+// from reviews. Reviews made before the reset of a species, or before a reset of
+// all species, are no longer used for learning.
+type ReviewSuppressionReset struct {
+	ID          uint      `gorm:"primaryKey"`
+	SpeciesName string    `gorm:"uniqueIndex;size:200"` // Common name (lowercase), empty for a reset of all species
+	ResetAt     time.Time `gorm:"not null"`
+}
+
+// SpeciesCorrection records a reviewer changing the species of a note, keeping
+// the original prediction so model accuracy can be measured per species. NoteID
+// has no foreign key so the audit trail outlives deleted notes.
+type SpeciesCorrection struct {
+	ID                      uint      `gorm:"primaryKey"`
+	NoteID                  uint      `gorm:"index;not null"` // Note that was corrected
+	OriginalScientificName  string    `gorm:"index;not null"` // Species predicted by the model
+	OriginalCommonName      string    `gorm:"not null"`
+	OriginalSpeciesCode     string    `gorm:"size:20"`
+	OriginalConfidence      float64   `gorm:"not null"`       // Confidence of the prediction
+	CorrectedScientificName string    `gorm:"index;not null"` // Species chosen by the reviewer
+	CorrectedCommonName     string    `gorm:"not null"`
+	CorrectedSpeciesCode    string    `gorm:"size:20"`
+	Source                  string    `gorm:"size:20;not null"` // Where the species was chosen from: label, result or manual
+	ResultConfidence        float64   `gorm:"not null"`         // Confidence of the chosen species in the note results, 0 when not among them
+	BulkOperationID         *uint     `gorm:"index"`            // Bulk reassign that made the correction, nil for single corrections
+	CorrectedBy             string    `gorm:"size:100"`         // User who made the correction, empty when unknown
+	CreatedAt               time.Time `gorm:"index;not null"`
+}
+
+// SoundLevel is the sound level of an audio source over a storage interval, combined
+// from the sound level measurements started within it. Levels are in dB relative to
+// the digital full scale of the audio source.
+type SoundLevel struct {
+	ID         uint      `gorm:"primaryKey"`
+	SourceID   string    `gorm:"index:idx_sound_levels_source_time,priority:1;size:255;not null"` // Audio source of the measurements
+	SourceName string    `gorm:"size:255"`                                                        // Display name of the audio source
+	Time       time.Time `gorm:"index:idx_sound_levels_source_time,priority:2;index;not null"`    // Start of the interval (UTC)
+	Duration   int       `gorm:"not null"`                                                        // Seconds measured within the interval
+	LAeq       float64   `gorm:"column:laeq"`                                                     // A-weighted equivalent level of the interval
+	Bands      string    `gorm:"type:text"`                                                       // JSON of the SoundLevelBand levels by band key
+}
+
+// SoundLevelBand is the level of one 1/3rd octave band over a storage interval
+type SoundLevelBand struct {
+	CenterFreq float64 `json:"f"` // Center frequency in Hz
+	Min        float64 `json:"n"` // Lowest one-second level
+	Max        float64 `json:"x"` // Highest one-second level
+	Mean       float64 `json:"m"` // Energy average of the measurement means
+}
+
+// DataMigration records a one-off data migration that has been applied to the
+// database, so it is not repeated on every startup
+type DataMigration struct {
+	Name      string    `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time `gorm:"not null"`
+}
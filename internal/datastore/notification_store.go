@@ -0,0 +1,290 @@
+// notification_store.go: Database backed store for notifications shown in the web UI
+package datastore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/notification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxStoredNotificationTitle matches the size of the StoredNotification.Title column
+const maxStoredNotificationTitle = 500
+
+// NotificationStore implements notification.NotificationStore on top of the main
+// database. Toast notifications are ephemeral and are never written to the database.
+type NotificationStore struct {
+	db         *gorm.DB
+	retention  time.Duration // Notifications older than this are pruned, 0 disables
+	maxEntries int           // Newest notifications to keep, 0 disables
+}
+
+// Ensure NotificationStore satisfies the notification store interface
+var _ notification.NotificationStore = (*NotificationStore)(nil)
+
+// NewNotificationStore creates a notification store using the database of the datastore.
+// retention and maxEntries control pruning in DeleteExpired, zero disables the limit.
+func (ds *DataStore) NewNotificationStore(retention time.Duration, maxEntries int) *NotificationStore {
+	return &NotificationStore{
+		db:         ds.DB,
+		retention:  retention,
+		maxEntries: maxEntries,
+	}
+}
+
+// Save inserts a notification or replaces an existing notification with the same ID
+func (s *NotificationStore) Save(n *notification.Notification) error {
+	if n == nil {
+		return validationError("notification cannot be nil", "notification", nil)
+	}
+	if isToast, _ := n.Metadata[notification.MetadataKeyIsToast].(bool); isToast {
+		return nil
+	}
+
+	record, err := toStoredNotification(n)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(record).Error; err != nil {
+		return dbError(err, "save_notification", errors.PriorityMedium,
+			"notification_id", n.ID,
+			"table", "stored_notifications",
+			"action", "persist_notification")
+	}
+	return nil
+}
+
+// Get retrieves a notification by ID
+func (s *NotificationStore) Get(id string) (*notification.Notification, error) {
+	var record StoredNotification
+	if err := s.db.Where("id = ?", id).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notification.ErrNotificationNotFound
+		}
+		return nil, dbError(err, "get_notification", errors.PriorityLow,
+			"notification_id", id,
+			"action", "retrieve_notification")
+	}
+	return fromStoredNotification(&record), nil
+}
+
+// List returns notifications matching the filter, newest first
+func (s *NotificationStore) List(filter *notification.FilterOptions) ([]*notification.Notification, error) {
+	query := s.db.Model(&StoredNotification{})
+	if filter != nil {
+		query = applyNotificationFilter(query, filter)
+		if filter.Offset > 0 {
+			query = query.Offset(filter.Offset)
+		}
+		if filter.Limit > 0 {
+			query = query.Limit(filter.Limit)
+		}
+	}
+
+	var records []StoredNotification
+	if err := query.Order("timestamp DESC").Find(&records).Error; err != nil {
+		return nil, dbError(err, "list_notifications", errors.PriorityLow,
+			"action", "list_notifications")
+	}
+
+	results := make([]*notification.Notification, 0, len(records))
+	for i := range records {
+		results = append(results, fromStoredNotification(&records[i]))
+	}
+	return results, nil
+}
+
+// applyNotificationFilter adds the conditions of the filter options to a query.
+// The semantics match the in-memory store: Since and Until are inclusive.
+func applyNotificationFilter(query *gorm.DB, filter *notification.FilterOptions) *gorm.DB {
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	if len(filter.Priorities) > 0 {
+		query = query.Where("priority IN ?", filter.Priorities)
+	}
+	if len(filter.Status) > 0 {
+		query = query.Where("status IN ?", filter.Status)
+	}
+	if filter.Component != "" {
+		query = query.Where("component = ?", filter.Component)
+	}
+	if filter.Since != nil {
+		query = query.Where("timestamp >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("timestamp <= ?", *filter.Until)
+	}
+	return query
+}
+
+// Update replaces an existing notification
+func (s *NotificationStore) Update(n *notification.Notification) error {
+	if n == nil {
+		return validationError("notification cannot be nil", "notification", nil)
+	}
+
+	record, err := toStoredNotification(n)
+	if err != nil {
+		return err
+	}
+	result := s.db.Model(&StoredNotification{}).Where("id = ?", n.ID).
+		Select("*").Omit("id").Updates(record)
+	if result.Error != nil {
+		return dbError(result.Error, "update_notification", errors.PriorityMedium,
+			"notification_id", n.ID,
+			"action", "update_notification_status")
+	}
+	if result.RowsAffected == 0 {
+		return notification.ErrNotificationNotFound
+	}
+	return nil
+}
+
+// Delete removes a notification, deleting a missing notification is not an error
+func (s *NotificationStore) Delete(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&StoredNotification{}).Error; err != nil {
+		return dbError(err, "delete_notification", errors.PriorityLow,
+			"notification_id", id,
+			"action", "delete_notification")
+	}
+	return nil
+}
+
+// DeleteExpired removes expired notifications and prunes notifications that are
+// older than the retention period or exceed the maximum number of entries
+func (s *NotificationStore) DeleteExpired() error {
+	now := time.Now()
+	var deleted int64
+
+	result := s.db.Where("expires_at IS NOT NULL AND expires_at < ?", now).Delete(&StoredNotification{})
+	if result.Error != nil {
+		return dbError(result.Error, "delete_expired_notifications", errors.PriorityLow,
+			"action", "cleanup_expired_notifications")
+	}
+	deleted += result.RowsAffected
+
+	if s.retention > 0 {
+		result = s.db.Where("timestamp < ?", now.Add(-s.retention)).Delete(&StoredNotification{})
+		if result.Error != nil {
+			return dbError(result.Error, "prune_notifications_by_age", errors.PriorityLow,
+				"retention", s.retention.String(),
+				"action", "cleanup_old_notifications")
+		}
+		deleted += result.RowsAffected
+	}
+
+	if s.maxEntries > 0 {
+		pruned, err := s.pruneExcess()
+		if err != nil {
+			return err
+		}
+		deleted += pruned
+	}
+
+	if deleted > 0 {
+		GetLogger().Debug("Pruned stored notifications",
+			logger.Int64("count", deleted),
+			logger.Int("max_entries", s.maxEntries),
+			logger.String("retention", s.retention.String()))
+	}
+	return nil
+}
+
+// pruneExcess deletes the oldest notifications beyond the maximum number of entries
+func (s *NotificationStore) pruneExcess() (int64, error) {
+	var cutoff StoredNotification
+	err := s.db.Select("timestamp").Order("timestamp DESC").Offset(s.maxEntries - 1).Limit(1).Take(&cutoff).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil // Fewer notifications than the limit
+	}
+	if err != nil {
+		return 0, dbError(err, "find_notification_prune_cutoff", errors.PriorityLow,
+			"max_entries", s.maxEntries,
+			"action", "cleanup_excess_notifications")
+	}
+
+	result := s.db.Where("timestamp < ?", cutoff.Timestamp).Delete(&StoredNotification{})
+	if result.Error != nil {
+		return 0, dbError(result.Error, "prune_excess_notifications", errors.PriorityLow,
+			"max_entries", s.maxEntries,
+			"action", "cleanup_excess_notifications")
+	}
+	return result.RowsAffected, nil
+}
+
+// GetUnreadCount returns the number of unread notifications
+func (s *NotificationStore) GetUnreadCount() (int, error) {
+	var count int64
+	if err := s.db.Model(&StoredNotification{}).Where("status = ?", notification.StatusUnread).Count(&count).Error; err != nil {
+		return 0, dbError(err, "count_unread_notifications", errors.PriorityLow,
+			"action", "count_unread_notifications")
+	}
+	return int(count), nil
+}
+
+// toStoredNotification converts a notification to its database representation
+func toStoredNotification(n *notification.Notification) (*StoredNotification, error) {
+	var metadata string
+	if len(n.Metadata) > 0 {
+		data, err := json.Marshal(n.Metadata)
+		if err != nil {
+			return nil, errors.New(err).
+				Component("datastore").
+				Category(errors.CategoryValidation).
+				Context("operation", "encode_notification_metadata").
+				Context("notification_id", n.ID).
+				Build()
+		}
+		metadata = string(data)
+	}
+
+	title := n.Title
+	if runes := []rune(title); len(runes) > maxStoredNotificationTitle {
+		title = string(runes[:maxStoredNotificationTitle])
+	}
+
+	return &StoredNotification{
+		ID:        n.ID,
+		Type:      string(n.Type),
+		Priority:  string(n.Priority),
+		Status:    string(n.Status),
+		Title:     title,
+		Message:   n.Message,
+		Component: n.Component,
+		Metadata:  metadata,
+		Timestamp: n.Timestamp,
+		ExpiresAt: n.ExpiresAt,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// fromStoredNotification converts a database record back to a notification.
+// Metadata values are restored as decoded from JSON, numbers become float64.
+func fromStoredNotification(r *StoredNotification) *notification.Notification {
+	n := &notification.Notification{
+		ID:        r.ID,
+		Type:      notification.Type(r.Type),
+		Priority:  notification.Priority(r.Priority),
+		Status:    notification.Status(r.Status),
+		Title:     r.Title,
+		Message:   r.Message,
+		Component: r.Component,
+		Timestamp: r.Timestamp,
+		ExpiresAt: r.ExpiresAt,
+		Metadata:  make(map[string]any),
+	}
+	if r.Metadata != "" {
+		if err := json.Unmarshal([]byte(r.Metadata), &n.Metadata); err != nil {
+			GetLogger().Warn("Failed to decode stored notification metadata",
+				logger.String("notification_id", r.ID),
+				logger.Error(err))
+			n.Metadata = make(map[string]any)
+		}
+	}
+	return n
+}
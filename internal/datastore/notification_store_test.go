@@ -0,0 +1,154 @@
+// notification_store_test.go: Unit tests for the database backed notification store
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/notification"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupNotificationStoreTestDB creates an in-memory SQLite database for testing
+func setupNotificationStoreTestDB(t *testing.T) *DataStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "Failed to create test database")
+
+	err = db.AutoMigrate(&StoredNotification{})
+	require.NoError(t, err, "Failed to migrate schema")
+
+	return &DataStore{DB: db}
+}
+
+// newTestNotification creates a notification with a fixed timestamp
+func newTestNotification(notifType notification.Type, priority notification.Priority, component string, ts time.Time) *notification.Notification {
+	n := notification.NewNotification(notifType, priority, "title", "message").WithComponent(component)
+	n.Timestamp = ts
+	return n
+}
+
+func TestNotificationStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationStoreTestDB(t).NewNotificationStore(0, 0)
+
+	n := newTestNotification(notification.TypeDetection, notification.PriorityHigh, "detection", time.Now()).
+		WithMetadata("species", "Eurasian Eagle-Owl").
+		WithMetadata(notification.MetadataKeyProviders, []string{"telegram"}).
+		WithExpiry(time.Hour)
+	require.NoError(t, store.Save(n))
+
+	got, err := store.Get(n.ID)
+	require.NoError(t, err)
+	assert.Equal(t, n.Title, got.Title)
+	assert.Equal(t, notification.StatusUnread, got.Status)
+	assert.Equal(t, "Eurasian Eagle-Owl", got.Metadata["species"])
+	assert.Equal(t, []any{"telegram"}, got.Metadata[notification.MetadataKeyProviders])
+	require.NotNil(t, got.ExpiresAt)
+
+	count, err := store.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	got.MarkAsAcknowledged()
+	require.NoError(t, store.Update(got))
+	got, err = store.Get(n.ID)
+	require.NoError(t, err)
+	assert.Equal(t, notification.StatusAcknowledged, got.Status)
+
+	count, err = store.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	require.NoError(t, store.Delete(n.ID))
+	_, err = store.Get(n.ID)
+	require.ErrorIs(t, err, notification.ErrNotificationNotFound)
+	require.ErrorIs(t, store.Update(n), notification.ErrNotificationNotFound)
+	require.NoError(t, store.Delete(n.ID), "deleting a missing notification is not an error")
+}
+
+func TestNotificationStoreSkipsToasts(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationStoreTestDB(t).NewNotificationStore(0, 0)
+
+	toast := notification.NewToast("saved", notification.ToastTypeSuccess).ToNotification()
+	require.NoError(t, store.Save(toast))
+
+	_, err := store.Get(toast.ID)
+	require.ErrorIs(t, err, notification.ErrNotificationNotFound)
+}
+
+func TestNotificationStoreListFilter(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationStoreTestDB(t).NewNotificationStore(0, 0)
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	oldest := newTestNotification(notification.TypeError, notification.PriorityCritical, "database", base)
+	middle := newTestNotification(notification.TypeDetection, notification.PriorityHigh, "detection", base.Add(10*time.Minute))
+	newest := newTestNotification(notification.TypeDetection, notification.PriorityLow, "detection", base.Add(20*time.Minute))
+	newest.MarkAsRead()
+	for _, n := range []*notification.Notification{oldest, middle, newest} {
+		require.NoError(t, store.Save(n))
+	}
+
+	ids := func(list []*notification.Notification) []string {
+		result := make([]string, 0, len(list))
+		for _, n := range list {
+			result = append(result, n.ID)
+		}
+		return result
+	}
+	since := middle.Timestamp
+	until := middle.Timestamp
+
+	tests := []struct {
+		name     string
+		filter   *notification.FilterOptions
+		expected []string
+	}{
+		{"no filter newest first", nil, []string{newest.ID, middle.ID, oldest.ID}},
+		{"type", &notification.FilterOptions{Types: []notification.Type{notification.TypeDetection}}, []string{newest.ID, middle.ID}},
+		{"priority", &notification.FilterOptions{Priorities: []notification.Priority{notification.PriorityCritical}}, []string{oldest.ID}},
+		{"status", &notification.FilterOptions{Status: []notification.Status{notification.StatusUnread}}, []string{middle.ID, oldest.ID}},
+		{"component", &notification.FilterOptions{Component: "database"}, []string{oldest.ID}},
+		{"since inclusive", &notification.FilterOptions{Since: &since}, []string{newest.ID, middle.ID}},
+		{"until inclusive", &notification.FilterOptions{Until: &until}, []string{middle.ID, oldest.ID}},
+		{"pagination", &notification.FilterOptions{Offset: 1, Limit: 1}, []string{middle.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := store.List(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(list))
+		})
+	}
+}
+
+func TestNotificationStoreDeleteExpired(t *testing.T) {
+	t.Parallel()
+	store := setupNotificationStoreTestDB(t).NewNotificationStore(24*time.Hour, 2)
+
+	now := time.Now()
+	expired := newTestNotification(notification.TypeInfo, notification.PriorityLow, "test", now.Add(-time.Minute))
+	past := now.Add(-time.Second)
+	expired.ExpiresAt = &past
+	tooOld := newTestNotification(notification.TypeInfo, notification.PriorityLow, "test", now.Add(-48*time.Hour))
+	excess := newTestNotification(notification.TypeInfo, notification.PriorityLow, "test", now.Add(-3*time.Hour))
+	kept1 := newTestNotification(notification.TypeInfo, notification.PriorityLow, "test", now.Add(-2*time.Hour))
+	kept2 := newTestNotification(notification.TypeInfo, notification.PriorityLow, "test", now.Add(-time.Hour))
+	for _, n := range []*notification.Notification{expired, tooOld, excess, kept1, kept2} {
+		require.NoError(t, store.Save(n))
+	}
+
+	require.NoError(t, store.DeleteExpired())
+
+	list, err := store.List(nil)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, kept2.ID, list[0].ID)
+	assert.Equal(t, kept1.ID, list[1].ID)
+}
@@ -0,0 +1,81 @@
+// review_suppression.go: Review history and resets for learned false positive suppression
+package datastore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewHistoryEntry is a reviewed note as used to learn false positive suppression
+type ReviewHistoryEntry struct {
+	ScientificName string
+	CommonName     string
+	SourceID       string  // Audio source of the note, empty for notes saved before sources were stored
+	SourceName     string  // Display name of the audio source
+	Time           string  // Time of day of the note, HH:MM:SS
+	Confidence     float64 // Confidence of the note
+	Verified       string  // "correct" or "false_positive"
+	ReviewedAt     time.Time
+}
+
+// GetReviewHistory returns the "correct" and "false_positive" reviews last
+// updated after since, all reviews for a zero time, oldest first.
+func (ds *DataStore) GetReviewHistory(ctx context.Context, since time.Time) ([]ReviewHistoryEntry, error) {
+	query := ds.DB.WithContext(ctx).Table("notes").
+		Select("notes.scientific_name, notes.common_name, notes.source_id, notes.source_name, notes.time, notes.confidence, "+
+			"note_reviews.verified, note_reviews.updated_at AS reviewed_at").
+		Joins("JOIN note_reviews ON note_reviews.note_id = notes.id").
+		Where("note_reviews.verified IN ?", []string{"correct", "false_positive"}).
+		Order("note_reviews.updated_at ASC")
+	if !since.IsZero() {
+		query = query.Where("note_reviews.updated_at > ?", since)
+	}
+
+	var entries []ReviewHistoryEntry
+	if err := query.Scan(&entries).Error; err != nil {
+		return nil, dbError(err, "get_review_history", errors.PriorityMedium,
+			"table", "note_reviews",
+			"action", "learn_false_positive_suppression")
+	}
+	return entries, nil
+}
+
+// GetReviewSuppressionResets returns the recorded resets of the learned false positive suppression
+func (ds *DataStore) GetReviewSuppressionResets() ([]ReviewSuppressionReset, error) {
+	var resets []ReviewSuppressionReset
+	if err := ds.DB.Order("species_name").Find(&resets).Error; err != nil {
+		return nil, dbError(err, "get_review_suppression_resets", errors.PriorityLow,
+			"table", "review_suppression_resets")
+	}
+	return resets, nil
+}
+
+// ResetReviewSuppression records a reset of the learned false positive suppression
+// of a species, identified by its common name, or of all species when speciesName
+// is empty. A reset of all species replaces the resets of single species.
+func (ds *DataStore) ResetReviewSuppression(speciesName string, at time.Time) error {
+	speciesName = strings.ToLower(strings.TrimSpace(speciesName))
+
+	err := ds.DB.Transaction(func(tx *gorm.DB) error {
+		if speciesName == "" {
+			if err := tx.Where("1 = 1").Delete(&ReviewSuppressionReset{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "species_name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reset_at"}),
+		}).Create(&ReviewSuppressionReset{SpeciesName: speciesName, ResetAt: at}).Error
+	})
+	if err != nil {
+		return dbError(err, "reset_review_suppression", errors.PriorityMedium,
+			"species", speciesName,
+			"table", "review_suppression_resets")
+	}
+	return nil
+}
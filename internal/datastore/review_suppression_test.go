@@ -0,0 +1,55 @@
+// review_suppression_test.go: Tests for the review history of learned false positive suppression
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReviewHistory(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+	require.NoError(t, ds.DB.Create(&NoteReview{NoteID: 2, Verified: "false_positive"}).Error)
+	require.NoError(t, ds.DB.Create(&NoteReview{NoteID: 3, Verified: "needs_review"}).Error)
+
+	entries, err := ds.GetReviewHistory(t.Context(), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "only correct and false positive reviews are learned from")
+	assert.Equal(t, "correct", entries[0].Verified)
+	assert.Equal(t, "false_positive", entries[1].Verified)
+	assert.Equal(t, "rtsp_garden", entries[1].SourceID)
+	assert.Equal(t, "Garden", entries[1].SourceName)
+	assert.Equal(t, "09:00:00", entries[1].Time)
+	assert.InDelta(t, 0.8, entries[1].Confidence, 0.0001)
+	assert.False(t, entries[1].ReviewedAt.IsZero())
+
+	entries, err = ds.GetReviewHistory(t.Context(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestResetReviewSuppression(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&ReviewSuppressionReset{}))
+
+	first := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, ds.ResetReviewSuppression("Great Tit", first))
+	require.NoError(t, ds.ResetReviewSuppression("great tit", first.Add(time.Minute)))
+	require.NoError(t, ds.ResetReviewSuppression("Eurasian Blackbird", first))
+
+	resets, err := ds.GetReviewSuppressionResets()
+	require.NoError(t, err)
+	require.Len(t, resets, 2)
+	assert.Equal(t, "great tit", resets[1].SpeciesName)
+	assert.True(t, resets[1].ResetAt.Equal(first.Add(time.Minute)), "a repeated reset moves the cutoff")
+
+	require.NoError(t, ds.ResetReviewSuppression("", first.Add(time.Hour)))
+	resets, err = ds.GetReviewSuppressionResets()
+	require.NoError(t, err)
+	require.Len(t, resets, 1, "a reset of all species replaces single species resets")
+	assert.Empty(t, resets[0].SpeciesName)
+}
@@ -3,6 +3,7 @@ package datastore
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,7 +20,8 @@ type AdvancedSearchFilters struct {
 	DateRange     *DateRange
 	Verified      *bool
 	Species       []string
-	Location      []string // Maps to source field
+	Source        []string // Audio source IDs or display names
+	Location      []string // Deprecated alias of Source, kept for older clients
 	Locked        *bool
 	SortAscending bool
 	Limit         int
@@ -57,47 +59,7 @@ func (ds *DataStore) SearchNotesAdvanced(filters *AdvancedSearchFilters) ([]Note
 	// 	metrics.IncrementSearches("advanced")
 	// }
 
-	// Start building the query
-	query := ds.DB.Model(&Note{}).
-		Preload("Review").
-		Preload("Lock").
-		Preload("Comments", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC")
-		})
-
-	// Apply text search if provided
-	if filters.TextQuery != "" {
-		query = query.Where("common_name LIKE ? OR scientific_name LIKE ?",
-			"%"+filters.TextQuery+"%", "%"+filters.TextQuery+"%")
-	}
-
-	// Apply confidence filter
-	query = applyConfidenceFilter(query, filters.Confidence)
-
-	// Apply date range filter
-	query = applyDateRangeFilter(query, filters.DateRange)
-
-	// Apply hour filter
-	query = applyHourFilter(query, filters.Hour)
-
-	// Apply time of day filter
-	query = applyTimeOfDayFilter(query, filters.TimeOfDay)
-
-	// Apply species filter
-	if len(filters.Species) > 0 {
-		query = query.Where("species_code IN ? OR scientific_name IN ?", filters.Species, filters.Species)
-	}
-
-	// Apply location/source filter
-	if len(filters.Location) > 0 {
-		query = query.Where("source IN ?", filters.Location)
-	}
-
-	// Apply verified filter
-	query = applyVerifiedFilter(query, filters.Verified)
-
-	// Apply locked filter
-	query = applyLockedFilter(query, filters.Locked)
+	query := ds.advancedSearchQuery(filters)
 
 	// Count total results before pagination
 	var totalCount int64
@@ -137,7 +99,45 @@ func (ds *DataStore) SearchNotesAdvanced(filters *AdvancedSearchFilters) ([]Note
 			Build()
 	}
 
-	// Populate virtual fields
+	populateReviewFields(notes)
+
+	return notes, totalCount, nil
+}
+
+// ExportNotesAdvanced passes all notes matching the filters to fn in batches of
+// batchSize, ordered by ID. Limit, Offset and SortAscending are ignored. Only one
+// batch is held in memory at a time, errors returned by fn stop the export.
+func (ds *DataStore) ExportNotesAdvanced(filters *AdvancedSearchFilters, batchSize int, fn func(notes []Note) error) error {
+	if batchSize <= 0 {
+		return validationError("batch size must be positive", "batchSize", batchSize)
+	}
+
+	var fnErr error
+	var batch []Note
+	result := ds.advancedSearchQuery(filters).FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+		populateReviewFields(batch)
+		if err := fn(batch); err != nil {
+			fnErr = err
+			return err
+		}
+		return nil
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if result.Error != nil {
+		return errors.Newf("failed to export notes: %w", result.Error).
+			Context("operation", "export_notes_advanced").
+			Context("filters", fmt.Sprintf("%+v", filters)).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Build()
+	}
+	return nil
+}
+
+// populateReviewFields fills the virtual Verified and Locked fields from the preloaded review and lock
+func populateReviewFields(notes []Note) {
 	for i := range notes {
 		note := &notes[i]
 		if note.Review != nil && note.Review.Verified != "" {
@@ -147,8 +147,78 @@ func (ds *DataStore) SearchNotesAdvanced(filters *AdvancedSearchFilters) ([]Note
 			note.Locked = true
 		}
 	}
+}
 
-	return notes, totalCount, nil
+// SearchNoteIDsAdvanced returns the IDs of the notes matching the filters in
+// ascending order, at most limit IDs when limit is positive. Limit, Offset and
+// SortAscending of the filters are ignored.
+func (ds *DataStore) SearchNoteIDsAdvanced(filters *AdvancedSearchFilters, limit int) ([]uint, error) {
+	query := applyAdvancedSearchFilters(ds.DB.Model(&Note{}), filters).Order("id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var ids []uint
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, errors.Newf("failed to search note IDs: %w", err).
+			Context("operation", "search_note_ids_advanced").
+			Context("filters", fmt.Sprintf("%+v", filters)).
+			Component("datastore").
+			Category(errors.CategoryDatabase).
+			Build()
+	}
+	return ids, nil
+}
+
+// advancedSearchQuery builds the filtered note query shared by advanced search and export
+func (ds *DataStore) advancedSearchQuery(filters *AdvancedSearchFilters) *gorm.DB {
+	query := ds.DB.Model(&Note{}).
+		Preload("Review").
+		Preload("Lock").
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		})
+
+	return applyAdvancedSearchFilters(query, filters)
+}
+
+// applyAdvancedSearchFilters adds the conditions of the filters to a note query
+func applyAdvancedSearchFilters(query *gorm.DB, filters *AdvancedSearchFilters) *gorm.DB {
+	// Apply text search if provided
+	if filters.TextQuery != "" {
+		query = query.Where("common_name LIKE ? OR scientific_name LIKE ?",
+			"%"+filters.TextQuery+"%", "%"+filters.TextQuery+"%")
+	}
+
+	// Apply confidence filter
+	query = applyConfidenceFilter(query, filters.Confidence)
+
+	// Apply date range filter
+	query = applyDateRangeFilter(query, filters.DateRange)
+
+	// Apply hour filter
+	query = applyHourFilter(query, filters.Hour)
+
+	// Apply time of day filter
+	query = applyTimeOfDayFilter(query, filters.TimeOfDay)
+
+	// Apply species filter
+	if len(filters.Species) > 0 {
+		query = query.Where("species_code IN ? OR scientific_name IN ?", filters.Species, filters.Species)
+	}
+
+	// Apply audio source filter, matching either the source ID or display name
+	if sources := append(slices.Clone(filters.Source), filters.Location...); len(sources) > 0 {
+		query = query.Where("(source_id IN ? OR source_name IN ?)", sources, sources)
+	}
+
+	// Apply verified filter
+	query = applyVerifiedFilter(query, filters.Verified)
+
+	// Apply locked filter
+	query = applyLockedFilter(query, filters.Locked)
+
+	return query
 }
 
 // ParseDateShortcut converts date shortcuts like "today", "yesterday" to actual dates
@@ -0,0 +1,60 @@
+// search_export_test.go: Tests for exporting notes matching advanced search filters
+package datastore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportNotesAdvanced(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}, &NoteLock{}, &NoteComment{}))
+	seedSourceNotes(t, ds)
+	require.NoError(t, ds.DB.Create(&NoteReview{NoteID: 1, Verified: "correct"}).Error)
+
+	t.Run("batches in ID order", func(t *testing.T) {
+		var batches [][]uint
+		err := ds.ExportNotesAdvanced(&AdvancedSearchFilters{}, 2, func(notes []Note) error {
+			ids := make([]uint, 0, len(notes))
+			for i := range notes {
+				ids = append(ids, notes[i].ID)
+			}
+			batches = append(batches, ids)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, [][]uint{{1, 2}, {3}}, batches)
+	})
+
+	t.Run("filters and review fields", func(t *testing.T) {
+		verified := true
+		var exported []Note
+		err := ds.ExportNotesAdvanced(&AdvancedSearchFilters{Source: []string{"Garden"}, Verified: &verified}, 10,
+			func(notes []Note) error {
+				exported = append(exported, notes...)
+				return nil
+			})
+		require.NoError(t, err)
+		require.Len(t, exported, 1)
+		assert.Equal(t, "correct", exported[0].Verified)
+	})
+
+	t.Run("callback error stops the export", func(t *testing.T) {
+		errStop := errors.New("client went away")
+		calls := 0
+		err := ds.ExportNotesAdvanced(&AdvancedSearchFilters{}, 1, func([]Note) error {
+			calls++
+			return errStop
+		})
+		require.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("invalid batch size", func(t *testing.T) {
+		require.Error(t, ds.ExportNotesAdvanced(&AdvancedSearchFilters{}, 0, func([]Note) error { return nil }))
+	})
+}
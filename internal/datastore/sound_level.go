@@ -0,0 +1,101 @@
+// sound_level.go: Saved sound levels for environmental noise indicators
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// soundLevelBatchSize is the number of sound levels inserted per statement
+const soundLevelBatchSize = 100
+
+// SoundLevelSource is an audio source with saved sound levels
+type SoundLevelSource struct {
+	SourceID   string `json:"sourceId"`
+	SourceName string `json:"sourceName"`
+	Count      int64  `json:"count"` // Number of saved sound levels
+}
+
+// SetBands encodes the band levels into the Bands column
+func (s *SoundLevel) SetBands(bands map[string]SoundLevelBand) error {
+	data, err := json.Marshal(bands)
+	if err != nil {
+		return err
+	}
+	s.Bands = string(data)
+	return nil
+}
+
+// GetBands decodes the band levels of the Bands column, empty for no bands
+func (s *SoundLevel) GetBands() (map[string]SoundLevelBand, error) {
+	bands := make(map[string]SoundLevelBand)
+	if s.Bands == "" {
+		return bands, nil
+	}
+	if err := json.Unmarshal([]byte(s.Bands), &bands); err != nil {
+		return nil, err
+	}
+	return bands, nil
+}
+
+// SaveSoundLevels saves sound levels, with their times in UTC
+func (ds *DataStore) SaveSoundLevels(levels []SoundLevel) error {
+	if len(levels) == 0 {
+		return nil
+	}
+	for i := range levels {
+		levels[i].Time = levels[i].Time.UTC()
+	}
+	if err := ds.DB.CreateInBatches(levels, soundLevelBatchSize).Error; err != nil {
+		return dbError(err, "save_sound_levels", errors.PriorityLow,
+			"table", "sound_levels",
+			"count", len(levels))
+	}
+	return nil
+}
+
+// GetSoundLevels returns the sound levels of an audio source from start up to end,
+// oldest first
+func (ds *DataStore) GetSoundLevels(ctx context.Context, sourceID string, start, end time.Time) ([]SoundLevel, error) {
+	var levels []SoundLevel
+	err := ds.DB.WithContext(ctx).
+		Where("source_id = ? AND time >= ? AND time < ?", sourceID, start.UTC(), end.UTC()).
+		Order("time ASC").
+		Find(&levels).Error
+	if err != nil {
+		return nil, dbError(err, "get_sound_levels", errors.PriorityLow,
+			"table", "sound_levels",
+			"source_id", sourceID)
+	}
+	return levels, nil
+}
+
+// GetSoundLevelSources returns the audio sources with saved sound levels
+func (ds *DataStore) GetSoundLevelSources(ctx context.Context) ([]SoundLevelSource, error) {
+	var sources []SoundLevelSource
+	err := ds.DB.WithContext(ctx).Model(&SoundLevel{}).
+		Select("source_id, MAX(source_name) AS source_name, COUNT(*) AS count").
+		Group("source_id").
+		Order("source_id").
+		Scan(&sources).Error
+	if err != nil {
+		return nil, dbError(err, "get_sound_level_sources", errors.PriorityLow,
+			"table", "sound_levels")
+	}
+	return sources, nil
+}
+
+// DeleteSoundLevelsBefore deletes the sound levels of intervals started before a
+// time and returns the number deleted
+func (ds *DataStore) DeleteSoundLevelsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := ds.DB.WithContext(ctx).Where("time < ?", before.UTC()).Delete(&SoundLevel{})
+	if result.Error != nil {
+		return 0, dbError(result.Error, "delete_sound_levels", errors.PriorityLow,
+			"table", "sound_levels",
+			"action", "sound_level_retention")
+	}
+	return result.RowsAffected, nil
+}
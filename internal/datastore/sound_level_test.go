@@ -0,0 +1,63 @@
+// sound_level_test.go: Tests for saved sound levels
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoundLevels(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&SoundLevel{}))
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	var levels []SoundLevel
+	for i := range 3 {
+		levels = append(levels, SoundLevel{
+			SourceID: "audio_card_1", SourceName: "USB Microphone",
+			Time: start.Add(time.Duration(i) * time.Minute), Duration: 60, LAeq: float64(-40 - i),
+		})
+	}
+	levels = append(levels, SoundLevel{SourceID: "rtsp_1", SourceName: "Garden", Time: start, Duration: 60, LAeq: -50})
+	bands := map[string]SoundLevelBand{"1.0_kHz": {CenterFreq: 1000, Min: -50, Max: -30, Mean: -42}}
+	require.NoError(t, levels[0].SetBands(bands))
+	require.NoError(t, ds.SaveSoundLevels(levels))
+
+	t.Run("get", func(t *testing.T) {
+		got, err := ds.GetSoundLevels(t.Context(), "audio_card_1", start, start.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, got, 2, "the end is exclusive")
+		assert.True(t, got[0].Time.Equal(start))
+		assert.InDelta(t, -41.0, got[1].LAeq, 0.001)
+
+		gotBands, err := got[0].GetBands()
+		require.NoError(t, err)
+		assert.Equal(t, bands, gotBands)
+		gotBands, err = got[1].GetBands()
+		require.NoError(t, err)
+		assert.Empty(t, gotBands)
+	})
+
+	t.Run("sources", func(t *testing.T) {
+		sources, err := ds.GetSoundLevelSources(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []SoundLevelSource{
+			{SourceID: "audio_card_1", SourceName: "USB Microphone", Count: 3},
+			{SourceID: "rtsp_1", SourceName: "Garden", Count: 1},
+		}, sources)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		deleted, err := ds.DeleteSoundLevelsBefore(t.Context(), start.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		got, err := ds.GetSoundLevels(t.Context(), "audio_card_1", start, start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+	})
+}
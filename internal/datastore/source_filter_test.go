@@ -0,0 +1,153 @@
+// source_filter_test.go: Tests for storing and filtering the audio source of detections
+package datastore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedSourceNotes adds detections from two audio sources to the database
+func seedSourceNotes(t *testing.T, ds *DataStore) {
+	t.Helper()
+
+	notes := []Note{
+		{Date: "2024-01-15", Time: "08:00:00", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird",
+			Confidence: 0.9, Source: AudioSource{ID: "rtsp_garden", DisplayName: "Garden"}},
+		{Date: "2024-01-15", Time: "09:00:00", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird",
+			Confidence: 0.8, Source: AudioSource{ID: "rtsp_garden", DisplayName: "Garden"}},
+		{Date: "2024-01-15", Time: "10:00:00", ScientificName: "Parus major", CommonName: "Great Tit",
+			Confidence: 0.85, Source: AudioSource{ID: "sound_card", DisplayName: "Attic"}},
+	}
+	for i := range notes {
+		require.NoError(t, ds.DB.Create(&notes[i]).Error)
+	}
+}
+
+func TestNoteSourceRoundTrip(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	seedSourceNotes(t, ds)
+
+	var note Note
+	require.NoError(t, ds.DB.Where("common_name = ?", "Great Tit").First(&note).Error)
+	assert.Equal(t, "sound_card", note.Source.ID)
+	assert.Equal(t, "Attic", note.Source.DisplayName)
+	assert.Empty(t, note.Source.SafeString, "SafeString is runtime only")
+}
+
+func TestBackfillNoteSources(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+
+	// Simulate a detection saved before the source columns existed
+	require.NoError(t, ds.DB.Exec(
+		"INSERT INTO notes (date, time, scientific_name, common_name, confidence) VALUES (?, ?, ?, ?, ?)",
+		"2024-01-15", "08:00:00", "Turdus merula", "Eurasian Blackbird", 0.9).Error)
+
+	require.NoError(t, backfillNoteSources(ds.DB, "sqlite"))
+
+	var nullCount int64
+	require.NoError(t, ds.DB.Model(&Note{}).
+		Where("source_id IS NULL OR source_name IS NULL").Count(&nullCount).Error)
+	assert.Zero(t, nullCount)
+
+	var note Note
+	require.NoError(t, ds.DB.First(&note).Error)
+	assert.Empty(t, note.Source.ID)
+	assert.Empty(t, note.Source.DisplayName)
+}
+
+func TestBackfillNoteSourcesRunsOnce(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&DataMigration{}))
+
+	calls := 0
+	backfill := func(db *gorm.DB, dbType string) error {
+		calls++
+		return backfillNoteSources(db, dbType)
+	}
+	require.NoError(t, runDataMigration(ds.DB, "sqlite", noteSourcesMigration, backfill))
+	require.NoError(t, runDataMigration(ds.DB, "sqlite", noteSourcesMigration, backfill))
+	assert.Equal(t, 1, calls, "the backfill only runs until its marker is stored")
+
+	var marker DataMigration
+	require.NoError(t, ds.DB.First(&marker, "name = ?", noteSourcesMigration).Error)
+	assert.False(t, marker.AppliedAt.IsZero())
+}
+
+func TestDataMigrationNotMarkedOnError(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&DataMigration{}))
+
+	failing := func(*gorm.DB, string) error { return assert.AnError }
+	require.ErrorIs(t, runDataMigration(ds.DB, "sqlite", noteSourcesMigration, failing), assert.AnError)
+
+	var count int64
+	require.NoError(t, ds.DB.Model(&DataMigration{}).Count(&count).Error)
+	assert.Zero(t, count, "a failed migration is retried on the next startup")
+}
+
+func TestAnalyticsSourceFilter(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	seedSourceNotes(t, ds)
+
+	tests := []struct {
+		name      string
+		source    string
+		wantCount int
+	}{
+		{"no filter", "", 3},
+		{"by source ID", "rtsp_garden", 2},
+		{"by display name", "Attic", 1},
+		{"unknown source", "missing", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			daily, err := ds.GetDailyAnalyticsData(ctx, "2024-01-01", "2024-01-31", "", tt.source)
+			require.NoError(t, err)
+			total := 0
+			for _, d := range daily {
+				total += d.Count
+			}
+			assert.Equal(t, tt.wantCount, total)
+
+			summary, err := ds.GetSpeciesSummaryData(ctx, "2024-01-01", "2024-01-31", tt.source)
+			require.NoError(t, err)
+			total = 0
+			for _, s := range summary {
+				total += s.Count
+			}
+			assert.Equal(t, tt.wantCount, total)
+		})
+	}
+}
+
+func TestSearchNotesAdvancedSourceFilter(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&NoteReview{}, &NoteLock{}, &NoteComment{}))
+	seedSourceNotes(t, ds)
+
+	notes, total, err := ds.SearchNotesAdvanced(&AdvancedSearchFilters{Source: []string{"Garden"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	for i := range notes {
+		assert.Equal(t, "rtsp_garden", notes[i].Source.ID)
+	}
+
+	// The legacy location filter matches sources as well
+	_, total, err = ds.SearchNotesAdvanced(&AdvancedSearchFilters{Location: []string{"sound_card"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
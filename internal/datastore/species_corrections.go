@@ -0,0 +1,219 @@
+// species_corrections.go: Species corrections of notes and their audit trail
+package datastore
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// Sources of a corrected species
+const (
+	CorrectionSourceLabel  = "label"  // Chosen from the model labels
+	CorrectionSourceResult = "result" // Chosen from the alternative results stored with the note
+	CorrectionSourceManual = "manual" // Entered by the reviewer, not known to the model
+)
+
+// SpeciesCorrectionChange describes the new species of a note for CorrectNoteSpecies
+type SpeciesCorrectionChange struct {
+	ScientificName string
+	CommonName     string
+	SpeciesCode    string // May be empty
+	Source         string // One of the CorrectionSource constants
+	CorrectedBy    string // User making the correction
+}
+
+// validate checks that the change names a species and a known source
+func (c *SpeciesCorrectionChange) validate() error {
+	if strings.TrimSpace(c.ScientificName) == "" || strings.TrimSpace(c.CommonName) == "" {
+		return validationError("corrected species requires scientific and common name", "species", c.ScientificName)
+	}
+	switch c.Source {
+	case CorrectionSourceLabel, CorrectionSourceResult, CorrectionSourceManual:
+	default:
+		return validationError("unknown correction source", "source", c.Source)
+	}
+	return nil
+}
+
+// resultConfidence returns the confidence of a species in the results stored
+// with a note, 0 when the species is not among them
+func resultConfidence(note *Note, scientificName string) float64 {
+	var confidence float64
+	for i := range note.Results {
+		// Results name the species as a BirdNET label, "Scientific name_Common name"
+		species := note.Results[i].Species
+		if (species == scientificName || strings.HasPrefix(species, scientificName+"_")) &&
+			float64(note.Results[i].Confidence) > confidence {
+			confidence = float64(note.Results[i].Confidence)
+		}
+	}
+	return confidence
+}
+
+// newSpeciesCorrection creates the audit record of changing the species of a
+// note, the note must be loaded with its results
+func newSpeciesCorrection(note *Note, scientific, common, code, source, by string, now time.Time) SpeciesCorrection {
+	return SpeciesCorrection{
+		NoteID:                  note.ID,
+		OriginalScientificName:  note.ScientificName,
+		OriginalCommonName:      note.CommonName,
+		OriginalSpeciesCode:     note.SpeciesCode,
+		OriginalConfidence:      note.Confidence,
+		CorrectedScientificName: scientific,
+		CorrectedCommonName:     common,
+		CorrectedSpeciesCode:    code,
+		Source:                  source,
+		ResultConfidence:        resultConfidence(note, scientific),
+		CorrectedBy:             by,
+		CreatedAt:               now,
+	}
+}
+
+// CorrectNoteSpecies changes the species of a note and records the original
+// prediction in the audit trail, both in one transaction. The review of the note
+// is cleared, as its verdict was about the original species. It returns
+// ErrNoteNotFound for unknown notes, ErrNoteLocked for locked notes and
+// ErrSpeciesUnchanged when the note already has the species.
+func (ds *DataStore) CorrectNoteSpecies(noteID uint, change *SpeciesCorrectionChange) (*SpeciesCorrection, error) {
+	if change == nil {
+		return nil, validationError("species correction cannot be nil", "change", nil)
+	}
+	if err := change.validate(); err != nil {
+		return nil, err
+	}
+
+	var correction SpeciesCorrection
+	err := ds.Transaction(func(tx *gorm.DB) error {
+		var note Note
+		if err := tx.Preload("Results").Preload("Lock").First(&note, noteID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoteNotFound
+			}
+			return err
+		}
+		if note.Lock != nil {
+			return ErrNoteLocked
+		}
+		if note.ScientificName == change.ScientificName {
+			return ErrSpeciesUnchanged
+		}
+
+		correction = newSpeciesCorrection(&note, change.ScientificName, change.CommonName, change.SpeciesCode,
+			change.Source, change.CorrectedBy, time.Now())
+		if err := tx.Create(&correction).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("note_id = ?", note.ID).Delete(&NoteReview{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Note{}).Where("id = ?", note.ID).Updates(map[string]any{
+			"scientific_name": change.ScientificName,
+			"common_name":     change.CommonName,
+			"species_code":    change.SpeciesCode,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrNoteNotFound) || errors.Is(err, ErrNoteLocked) || errors.Is(err, ErrSpeciesUnchanged) {
+			return nil, err
+		}
+		return nil, dbError(err, "correct_note_species", errors.PriorityMedium,
+			"note_id", strconv.FormatUint(uint64(noteID), 10),
+			"table", "species_corrections")
+	}
+	return &correction, nil
+}
+
+// SpeciesCorrectionFilter selects species corrections. Zero values do not filter.
+type SpeciesCorrectionFilter struct {
+	NoteID    uint
+	Species   string    // Scientific name of the original or the corrected species
+	StartDate time.Time // Corrections made at or after this time
+	EndDate   time.Time // Corrections made before this time
+	Limit     int
+	Offset    int
+}
+
+// apply adds the conditions of the filter to a query
+func (f *SpeciesCorrectionFilter) apply(query *gorm.DB) *gorm.DB {
+	if f == nil {
+		return query
+	}
+	if f.NoteID != 0 {
+		query = query.Where("note_id = ?", f.NoteID)
+	}
+	if f.Species != "" {
+		query = query.Where("(LOWER(original_scientific_name) = LOWER(?) OR LOWER(corrected_scientific_name) = LOWER(?))",
+			f.Species, f.Species)
+	}
+	if !f.StartDate.IsZero() {
+		query = query.Where("created_at >= ?", f.StartDate)
+	}
+	if !f.EndDate.IsZero() {
+		query = query.Where("created_at < ?", f.EndDate)
+	}
+	return query
+}
+
+// GetSpeciesCorrections returns the corrections matching the filter, newest
+// first, and the total number of matching corrections
+func (ds *DataStore) GetSpeciesCorrections(filter *SpeciesCorrectionFilter) ([]SpeciesCorrection, int64, error) {
+	var total int64
+	if err := filter.apply(ds.DB.Model(&SpeciesCorrection{})).Count(&total).Error; err != nil {
+		return nil, 0, dbError(err, "count_species_corrections", errors.PriorityLow,
+			"table", "species_corrections")
+	}
+
+	query := filter.apply(ds.DB.Model(&SpeciesCorrection{})).Order("created_at DESC, id DESC")
+	if filter != nil && filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	var corrections []SpeciesCorrection
+	if err := query.Find(&corrections).Error; err != nil {
+		return nil, 0, dbError(err, "get_species_corrections", errors.PriorityLow,
+			"table", "species_corrections")
+	}
+	return corrections, total, nil
+}
+
+// SpeciesCorrectionCount is the number of corrections from one predicted species to another
+type SpeciesCorrectionCount struct {
+	OriginalScientificName  string
+	OriginalCommonName      string
+	CorrectedScientificName string
+	CorrectedCommonName     string
+	Count                   int64
+	AverageConfidence       float64 // Mean confidence of the corrected predictions
+}
+
+// GetSpeciesCorrectionCounts counts the corrections matching the filter by
+// predicted and corrected species, most frequent first. Limit and Offset are ignored.
+func (ds *DataStore) GetSpeciesCorrectionCounts(filter *SpeciesCorrectionFilter) ([]SpeciesCorrectionCount, error) {
+	var counts []SpeciesCorrectionCount
+	err := filter.apply(ds.DB.Model(&SpeciesCorrection{})).
+		Select("original_scientific_name, MAX(original_common_name) AS original_common_name, " +
+			"corrected_scientific_name, MAX(corrected_common_name) AS corrected_common_name, " +
+			"COUNT(*) AS count, AVG(original_confidence) AS average_confidence").
+		Group("original_scientific_name, corrected_scientific_name").
+		Order("count DESC, original_scientific_name ASC, corrected_scientific_name ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, dbError(err, "get_species_correction_counts", errors.PriorityLow,
+			"table", "species_corrections")
+	}
+	return counts, nil
+}
+
+// GetNoteResults returns the predictions stored with a note, highest confidence first
+func (ds *DataStore) GetNoteResults(noteID uint) ([]Results, error) {
+	var results []Results
+	if err := ds.DB.Where("note_id = ?", noteID).Order("confidence DESC, id ASC").Find(&results).Error; err != nil {
+		return nil, dbError(err, "get_note_results", errors.PriorityLow,
+			"note_id", strconv.FormatUint(uint64(noteID), 10),
+			"table", "results")
+	}
+	return results, nil
+}
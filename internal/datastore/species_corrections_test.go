@@ -0,0 +1,101 @@
+// species_corrections_test.go: Tests for species corrections and their audit trail
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrectNoteSpecies(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+	require.NoError(t, ds.DB.Create(&Results{NoteID: 2, Species: "Turdus philomelos_Song Thrush", Confidence: 0.4}).Error)
+	require.NoError(t, ds.DB.Create(&NoteReview{NoteID: 2, Verified: "false_positive", ReviewedBy: "alice"}).Error)
+
+	correction, err := ds.CorrectNoteSpecies(2, &SpeciesCorrectionChange{
+		ScientificName: "Turdus philomelos",
+		CommonName:     "Song Thrush",
+		SpeciesCode:    "sonthr1",
+		Source:         CorrectionSourceResult,
+		CorrectedBy:    "bob",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Turdus merula", correction.OriginalScientificName)
+	assert.InDelta(t, 0.8, correction.OriginalConfidence, 0.0001)
+	assert.InDelta(t, 0.4, correction.ResultConfidence, 0.0001)
+	assert.Nil(t, correction.BulkOperationID)
+
+	var note Note
+	require.NoError(t, ds.DB.First(&note, 2).Error)
+	assert.Equal(t, "Turdus philomelos", note.ScientificName)
+	assert.Equal(t, "Song Thrush", note.CommonName)
+	assert.Equal(t, "sonthr1", note.SpeciesCode)
+
+	var reviews int64
+	require.NoError(t, ds.DB.Model(&NoteReview{}).Where("note_id = ?", 2).Count(&reviews).Error)
+	assert.Zero(t, reviews, "the review of the original species is cleared")
+	require.NoError(t, ds.DB.Model(&NoteReview{}).Where("note_id = ?", 1).Count(&reviews).Error)
+	assert.Equal(t, int64(1), reviews, "reviews of other notes are kept")
+
+	t.Run("errors", func(t *testing.T) {
+		change := &SpeciesCorrectionChange{ScientificName: "Pica pica", CommonName: "Eurasian Magpie", Source: CorrectionSourceManual}
+		_, err := ds.CorrectNoteSpecies(3, change)
+		require.ErrorIs(t, err, ErrNoteLocked)
+		_, err = ds.CorrectNoteSpecies(99, change)
+		require.ErrorIs(t, err, ErrNoteNotFound)
+		_, err = ds.CorrectNoteSpecies(1, &SpeciesCorrectionChange{ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Source: CorrectionSourceLabel})
+		require.ErrorIs(t, err, ErrSpeciesUnchanged)
+		_, err = ds.CorrectNoteSpecies(1, &SpeciesCorrectionChange{ScientificName: "Pica pica", CommonName: "Eurasian Magpie", Source: "guess"})
+		require.Error(t, err)
+		_, err = ds.CorrectNoteSpecies(1, &SpeciesCorrectionChange{ScientificName: "Pica pica", Source: CorrectionSourceManual})
+		require.Error(t, err)
+
+		_, total, err := ds.GetSpeciesCorrections(nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total, "failed corrections are not recorded")
+	})
+}
+
+func TestGetSpeciesCorrections(t *testing.T) {
+	t.Parallel()
+	ds := setupBulkTestDB(t)
+
+	for _, c := range []struct {
+		id                 uint
+		scientific, common string
+		source             string
+	}{
+		{1, "Turdus philomelos", "Song Thrush", CorrectionSourceLabel},
+		{2, "Turdus philomelos", "Song Thrush", CorrectionSourceLabel},
+		{1, "Turdus iliacus", "Redwing", CorrectionSourceManual},
+	} {
+		_, err := ds.CorrectNoteSpecies(c.id, &SpeciesCorrectionChange{ScientificName: c.scientific, CommonName: c.common, Source: c.source})
+		require.NoError(t, err)
+	}
+
+	corrections, total, err := ds.GetSpeciesCorrections(&SpeciesCorrectionFilter{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, corrections, 2)
+	assert.Equal(t, "Turdus iliacus", corrections[0].CorrectedScientificName, "newest first")
+	assert.Equal(t, "Turdus philomelos", corrections[0].OriginalScientificName, "corrections chain")
+
+	corrections, _, err = ds.GetSpeciesCorrections(&SpeciesCorrectionFilter{NoteID: 2})
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+
+	_, total, err = ds.GetSpeciesCorrections(&SpeciesCorrectionFilter{StartDate: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	counts, err := ds.GetSpeciesCorrectionCounts(&SpeciesCorrectionFilter{Species: "turdus merula"})
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	assert.Equal(t, "Turdus philomelos", counts[0].CorrectedScientificName)
+	assert.Equal(t, "Eurasian Blackbird", counts[0].OriginalCommonName)
+	assert.Equal(t, int64(2), counts[0].Count)
+	assert.InDelta(t, 0.85, counts[0].AverageConfidence, 0.0001)
+}
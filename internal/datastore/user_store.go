@@ -0,0 +1,173 @@
+// user_store.go: Database backed store for web UI and API user accounts
+package datastore
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// UserStore stores users and their OAuth identities in the main database
+type UserStore struct {
+	db *gorm.DB
+}
+
+// NewUserStore creates a user store using the database of the datastore
+func (ds *DataStore) NewUserStore() *UserStore {
+	return &UserStore{db: ds.DB}
+}
+
+// Create stores a new user
+func (s *UserStore) Create(user *User) error {
+	if user == nil {
+		return validationError("user cannot be nil", "user", nil)
+	}
+	if user.Username == "" {
+		return validationError("username cannot be empty", "username", nil)
+	}
+	if err := s.db.Omit("Identities").Create(user).Error; err != nil {
+		return dbError(err, "create_user", errors.PriorityMedium,
+			"username", user.Username,
+			"table", "users",
+			"action", "create_user")
+	}
+	return nil
+}
+
+// List returns all users with their identities, ordered by username
+func (s *UserStore) List() ([]User, error) {
+	var users []User
+	if err := s.db.Preload("Identities").Order("username ASC").Find(&users).Error; err != nil {
+		return nil, dbError(err, "list_users", errors.PriorityLow,
+			"action", "list_users")
+	}
+	return users, nil
+}
+
+// Get returns the user with the given ID and its identities
+func (s *UserStore) Get(id uint) (*User, error) {
+	var user User
+	if err := s.db.Preload("Identities").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, dbError(err, "get_user", errors.PriorityLow,
+			"user_id", strconv.FormatUint(uint64(id), 10),
+			"action", "get_user")
+	}
+	return &user, nil
+}
+
+// GetByUsername returns the user with the given username
+func (s *UserStore) GetByUsername(username string) (*User, error) {
+	var user User
+	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, dbError(err, "get_user_by_username", errors.PriorityLow,
+			"action", "authenticate_user")
+	}
+	return &user, nil
+}
+
+// GetByIdentity returns the user the OAuth identity of provider and subject belongs to
+func (s *UserStore) GetByIdentity(provider, subject string) (*User, error) {
+	var identity UserIdentity
+	if err := s.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, dbError(err, "get_user_by_identity", errors.PriorityLow,
+			"provider", provider,
+			"action", "authenticate_oauth_user")
+	}
+	return s.Get(identity.UserID)
+}
+
+// Update saves the changed fields of a user, identities are changed with
+// AddIdentity and DeleteIdentity
+func (s *UserStore) Update(user *User) error {
+	result := s.db.Model(&User{}).Where("id = ?", user.ID).Updates(map[string]any{
+		"display_name":  user.DisplayName,
+		"role":          user.Role,
+		"password_hash": user.PasswordHash,
+		"disabled":      user.Disabled,
+		"updated_at":    time.Now(),
+	})
+	if result.Error != nil {
+		return dbError(result.Error, "update_user", errors.PriorityMedium,
+			"user_id", strconv.FormatUint(uint64(user.ID), 10),
+			"action", "update_user")
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// Delete removes a user and its identities
+func (s *UserStore) Delete(id uint) error {
+	var deleted int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&UserIdentity{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&User{}, id)
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return dbError(err, "delete_user", errors.PriorityMedium,
+			"user_id", strconv.FormatUint(uint64(id), 10),
+			"action", "delete_user")
+	}
+	if deleted == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// AddIdentity stores an OAuth identity for a user
+func (s *UserStore) AddIdentity(identity *UserIdentity) error {
+	if identity == nil {
+		return validationError("user identity cannot be nil", "identity", nil)
+	}
+	if identity.Provider == "" || identity.Subject == "" {
+		return validationError("user identity needs a provider and a subject", "identity", identity.Provider)
+	}
+	if err := s.db.Create(identity).Error; err != nil {
+		return dbError(err, "add_user_identity", errors.PriorityMedium,
+			"user_id", strconv.FormatUint(uint64(identity.UserID), 10),
+			"provider", identity.Provider,
+			"table", "user_identities",
+			"action", "add_user_identity")
+	}
+	return nil
+}
+
+// DeleteIdentity removes an identity of a user
+func (s *UserStore) DeleteIdentity(userID, identityID uint) error {
+	result := s.db.Where("id = ? AND user_id = ?", identityID, userID).Delete(&UserIdentity{})
+	if result.Error != nil {
+		return dbError(result.Error, "delete_user_identity", errors.PriorityMedium,
+			"user_id", strconv.FormatUint(uint64(userID), 10),
+			"action", "delete_user_identity")
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserIdentityNotFound
+	}
+	return nil
+}
+
+// MarkLogin records when a user last signed in
+func (s *UserStore) MarkLogin(id uint, at time.Time) error {
+	if err := s.db.Model(&User{}).Where("id = ?", id).Update("last_login_at", at).Error; err != nil {
+		return dbError(err, "mark_user_login", errors.PriorityLow,
+			"user_id", strconv.FormatUint(uint64(id), 10),
+			"action", "track_user_login")
+	}
+	return nil
+}
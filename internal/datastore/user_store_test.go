@@ -0,0 +1,104 @@
+// user_store_test.go: Unit tests for the user store
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupUserStoreTestDB creates an in-memory SQLite database for testing
+func setupUserStoreTestDB(t *testing.T) *UserStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "Failed to create test database")
+	require.NoError(t, db.AutoMigrate(&User{}, &UserIdentity{}), "Failed to migrate schema")
+	return (&DataStore{DB: db}).NewUserStore()
+}
+
+func TestUserStoreLifecycle(t *testing.T) {
+	t.Parallel()
+	store := setupUserStoreTestDB(t)
+
+	alice := &User{Username: "alice", DisplayName: "Alice", Role: "reviewer", PasswordHash: "hash"}
+	require.NoError(t, store.Create(alice))
+	require.NotZero(t, alice.ID)
+	require.NoError(t, store.Create(&User{Username: "bob", Role: "viewer"}))
+	require.Error(t, store.Create(&User{Username: "alice", Role: "viewer"}), "usernames are unique")
+
+	require.NoError(t, store.AddIdentity(&UserIdentity{UserID: alice.ID, Provider: "google", Subject: "alice@example.com"}))
+	require.Error(t, store.AddIdentity(&UserIdentity{UserID: alice.ID, Provider: "google", Subject: "alice@example.com"}),
+		"an identity maps to one user")
+
+	users, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0].Username)
+	require.Len(t, users[0].Identities, 1)
+	assert.Empty(t, users[1].Identities)
+
+	got, err := store.GetByIdentity("google", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, got.ID)
+	_, err = store.GetByIdentity("github", "alice@example.com")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	got.Role = "admin"
+	got.Disabled = true
+	require.NoError(t, store.Update(got))
+	loginAt := time.Now().Truncate(time.Second)
+	require.NoError(t, store.MarkLogin(alice.ID, loginAt))
+
+	got, err = store.GetByUsername("alice")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", got.Role)
+	assert.True(t, got.Disabled)
+	assert.Equal(t, "hash", got.PasswordHash)
+	require.NotNil(t, got.LastLoginAt)
+	assert.True(t, loginAt.Equal(*got.LastLoginAt))
+
+	require.NoError(t, store.DeleteIdentity(alice.ID, users[0].Identities[0].ID))
+	require.ErrorIs(t, store.DeleteIdentity(alice.ID, users[0].Identities[0].ID), ErrUserIdentityNotFound)
+
+	require.NoError(t, store.AddIdentity(&UserIdentity{UserID: alice.ID, Provider: "github", Subject: "12345"}))
+	require.NoError(t, store.Delete(alice.ID))
+	_, err = store.GetByIdentity("github", "12345")
+	require.ErrorIs(t, err, ErrUserNotFound, "identities are deleted with the user")
+}
+
+func TestUserStoreNotFound(t *testing.T) {
+	t.Parallel()
+	store := setupUserStoreTestDB(t)
+
+	_, err := store.Get(42)
+	require.ErrorIs(t, err, ErrUserNotFound)
+	_, err = store.GetByUsername("nobody")
+	require.ErrorIs(t, err, ErrUserNotFound)
+	require.ErrorIs(t, store.Update(&User{ID: 42, Role: "viewer"}), ErrUserNotFound)
+	require.ErrorIs(t, store.Delete(42), ErrUserNotFound)
+	require.Error(t, store.Create(&User{}), "username is required")
+}
+
+func TestSaveNoteReviewReplacesReviewer(t *testing.T) {
+	t.Parallel()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Note{}, &NoteReview{}))
+	ds := &DataStore{DB: db}
+	note := Note{Date: "2024-05-01", Time: "06:00:00", ScientificName: "Turdus merula"}
+	require.NoError(t, db.Create(&note).Error)
+
+	require.NoError(t, ds.SaveNoteReview(&NoteReview{NoteID: note.ID, Verified: "correct", ReviewedBy: "alice"}))
+	// A review without a known user, such as one through an OAuth access token
+	require.NoError(t, ds.SaveNoteReview(&NoteReview{NoteID: note.ID, Verified: "false_positive"}))
+
+	var reviews []NoteReview
+	require.NoError(t, db.Find(&reviews).Error)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "false_positive", reviews[0].Verified)
+	assert.Empty(t, reviews[0].ReviewedBy, "the previous reviewer is not kept")
+}
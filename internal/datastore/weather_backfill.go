@@ -0,0 +1,40 @@
+// weather_backfill.go: Queries for filling in missing weather of past detections
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// GetDetectionDates returns the distinct dates with detections, oldest first
+func (ds *DataStore) GetDetectionDates(ctx context.Context) ([]string, error) {
+	var dates []string
+	err := ds.DB.WithContext(ctx).Model(&Note{}).
+		Distinct("date").
+		Order("date ASC").
+		Pluck("date", &dates).Error
+	if err != nil {
+		return nil, dbError(err, "get_detection_dates", errors.PriorityLow,
+			"table", "notes",
+			"action", "weather_backfill")
+	}
+	return dates, nil
+}
+
+// GetHourlyWeatherTimes returns the times of the hourly weather saved from start up
+// to end, oldest first
+func (ds *DataStore) GetHourlyWeatherTimes(ctx context.Context, start, end time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := ds.DB.WithContext(ctx).Model(&HourlyWeather{}).
+		Where("time >= ? AND time < ?", start, end).
+		Order("time ASC").
+		Pluck("time", &times).Error
+	if err != nil {
+		return nil, dbError(err, "get_hourly_weather_times", errors.PriorityLow,
+			"table", "hourly_weathers",
+			"action", "weather_backfill")
+	}
+	return times, nil
+}
@@ -0,0 +1,40 @@
+// weather_backfill_test.go: Tests for the queries of the weather backfill
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDetectionDates(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+
+	for _, date := range []string{"2024-03-02", "2024-03-01", "2024-03-02"} {
+		require.NoError(t, ds.DB.Create(&Note{Date: date, Time: "08:00:00", ScientificName: "Parus major"}).Error)
+	}
+
+	dates, err := ds.GetDetectionDates(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-03-01", "2024-03-02"}, dates)
+}
+
+func TestGetHourlyWeatherTimes(t *testing.T) {
+	t.Parallel()
+	ds := setupTestDB(t)
+	require.NoError(t, ds.DB.AutoMigrate(&HourlyWeather{}))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, hours := range []int{-1, 0, 5, 24} {
+		require.NoError(t, ds.SaveHourlyWeather(&HourlyWeather{Time: start.Add(time.Duration(hours) * time.Hour)}))
+	}
+
+	times, err := ds.GetHourlyWeatherTimes(t.Context(), start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, times, 2)
+	assert.True(t, times[0].Equal(start))
+	assert.True(t, times[1].Equal(start.Add(5*time.Hour)))
+}
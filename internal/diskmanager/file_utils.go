@@ -103,6 +103,7 @@ func LoadPolicy(policyFile string) (*Policy, error) {
 // walkState holds the state for directory walking operations
 type walkState struct {
 	ctx            context.Context
+	baseDir        string
 	allowedExts    []string
 	lockedSet      map[string]struct{}
 	files          []FileInfo
@@ -133,6 +134,10 @@ func createWalkFunc(state *walkState) filepath.WalkFunc {
 
 		if !info.IsDir() {
 			processFile(path, info, state)
+		} else if path != state.baseDir && strings.HasPrefix(info.Name(), ".") {
+			// Hidden directories hold no recordings to clean up, e.g. the clips
+			// held for undoing a bulk delete
+			return filepath.SkipDir
 		}
 
 		// Yield to other goroutines
@@ -251,6 +256,7 @@ func GetAudioFilesContext(ctx context.Context, baseDir string, allowedExts []str
 	// Create walk state to hold all the parameters
 	state := &walkState{
 		ctx:             ctx,
+		baseDir:         baseDir,
 		allowedExts:     allowedExts,
 		lockedSet:       lockedSet,
 		files:           files,
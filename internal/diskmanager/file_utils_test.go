@@ -316,3 +316,21 @@ func TestHandleWalkError(t *testing.T) {
 	require.Error(t, err, "permission error should propagate even for temp files")
 	require.ErrorIs(t, err, permErr, "should return the original error")
 }
+
+// TestGetAudioFilesSkipsHiddenDirectories tests that recordings in hidden directories,
+// such as the clips held for undoing a bulk delete, are not counted or cleaned up
+func TestGetAudioFilesSkipsHiddenDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+
+	visible := filepath.Join(tempDir, "2024", "05", "bubo_bubo_80p_20210102T150405Z.wav")
+	held := filepath.Join(tempDir, ".bulk-undo", "7", "2024", "05", "corvus_corax_90p_20210104T150405Z.wav")
+	for _, path := range []string{visible, held} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("test content"), 0o600))
+	}
+
+	files, err := GetAudioFiles(tempDir, allowedFileTypes, &MockDB{}, false)
+	require.NoError(t, err)
+	require.Len(t, files, 1, "Should skip files in hidden directories")
+	assert.Equal(t, visible, files[0].Path)
+}
@@ -0,0 +1,275 @@
+// analyzer.go: Reader for BirdNET-Analyzer CSV results and Raven selection tables
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// BirdNET-Analyzer source ID stored with imported detections
+const analyzerSourceID = "birdnet-analyzer"
+
+// Recording start times are taken from audio file names such as 20240501_060000.wav
+// (AudioMoth, Song Meter) or 2024-05-01T06-00-00.wav
+var (
+	compactTimestamp = regexp.MustCompile(`(\d{8})[_T-]?(\d{6})`)
+	isoTimestamp     = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[T_ ](\d{2})[-:.]?(\d{2})[-:.]?(\d{2})`)
+)
+
+// AnalyzerReader reads BirdNET-Analyzer output files. Both the CSV result format and
+// Raven selection tables are supported, the format is detected from the header.
+// The results refer to whole recordings rather than clips, so no clips are attached.
+type AnalyzerReader struct {
+	Paths []string // Result files, directories are searched recursively
+	// Start overrides the recording start time. When zero the start time is parsed
+	// from the name of the analyzed audio file or the result file.
+	Start time.Time
+}
+
+// Name implements Reader
+func (r *AnalyzerReader) Name() string {
+	return "BirdNET-Analyzer results"
+}
+
+// Read implements Reader
+func (r *AnalyzerReader) Read(ctx context.Context, fn func(*Record) error) error {
+	files, err := r.resultFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.readFile(file, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resultFiles expands directories into the result files they contain
+func (r *AnalyzerReader) resultFiles() ([]string, error) {
+	var files []string
+	for _, path := range r.Paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fileError(err, "stat_result_path", path)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isResultFile(d.Name()) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fileError(err, "scan_result_directory", path)
+		}
+	}
+	return files, nil
+}
+
+// isResultFile reports whether a file name looks like BirdNET-Analyzer output
+func isResultFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".csv") || strings.HasSuffix(lower, ".selection.table.txt")
+}
+
+// analyzerColumns holds the column indexes of a result file, -1 when missing
+type analyzerColumns struct {
+	begin, end, offset       int
+	scientific, common, code int
+	confidence, audioFile    int
+	view                     int
+}
+
+// parseHeader maps the header of a CSV result file or Raven selection table
+func parseHeader(header []string) (analyzerColumns, bool) {
+	cols := analyzerColumns{-1, -1, -1, -1, -1, -1, -1, -1, -1}
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "start (s)", "begin time (s)":
+			cols.begin = i
+		case "end (s)", "end time (s)":
+			cols.end = i
+		case "file offset (s)":
+			cols.offset = i
+		case "scientific name":
+			cols.scientific = i
+		case "common name":
+			cols.common = i
+		case "species code":
+			cols.code = i
+		case "confidence":
+			cols.confidence = i
+		case "file", "begin path", "begin file":
+			cols.audioFile = i
+		case "view":
+			cols.view = i
+		}
+	}
+	valid := cols.begin >= 0 && cols.confidence >= 0 &&
+		(cols.scientific >= 0 || cols.common >= 0 || cols.code >= 0)
+	return cols, valid
+}
+
+// readFile reads the detections of a single result file
+func (r *AnalyzerReader) readFile(path string, fn func(*Record) error) error {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a result file chosen by the user
+	if err != nil {
+		return fileError(err, "open_result_file", path)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			GetLogger().Warn("Failed to close result file", logger.String("path", path), logger.Error(err))
+		}
+	}()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if strings.HasSuffix(strings.ToLower(path), ".txt") {
+		reader.Comma = '\t'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fileError(err, "read_result_header", path)
+	}
+	if len(header) == 1 && strings.Contains(header[0], "\t") {
+		// Raven selection table saved with a .csv extension
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return fileError(err, "rewind_result_file", path)
+		}
+		reader = csv.NewReader(f)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		reader.Comma = '\t'
+		if header, err = reader.Read(); err != nil {
+			return fileError(err, "read_result_header", path)
+		}
+	}
+
+	cols, ok := parseHeader(header)
+	if !ok {
+		GetLogger().Warn("Skipping file that is not a BirdNET-Analyzer result file",
+			logger.String("path", path))
+		return nil
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fileError(err, "read_result_row", path)
+		}
+		record := r.parseRow(path, row, cols)
+		if record == nil {
+			continue // Raven tables have a spectrogram and waveform row per detection
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// parseRow maps a result row to a record, returning nil for duplicate Raven view rows
+func (r *AnalyzerReader) parseRow(path string, row []string, cols analyzerColumns) *Record {
+	field := func(i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	seconds := func(i int) float64 {
+		v, _ := strconv.ParseFloat(field(i), 64)
+		return v
+	}
+
+	if strings.HasPrefix(strings.ToLower(field(cols.view)), "waveform") {
+		return nil
+	}
+
+	confidence, _ := strconv.ParseFloat(field(cols.confidence), 64)
+	record := &Record{
+		ScientificName: field(cols.scientific),
+		CommonName:     field(cols.common),
+		SpeciesCode:    field(cols.code),
+		Confidence:     confidence,
+		SourceID:       analyzerSourceID,
+	}
+
+	audioFile := field(cols.audioFile)
+	nameForTime := audioFile
+	if nameForTime == "" {
+		nameForTime = filepath.Base(path)
+	}
+	record.SourceName = filepath.Base(nameForTime)
+
+	start := r.Start
+	if start.IsZero() {
+		start = timestampFromName(filepath.Base(nameForTime))
+	}
+	if !start.IsZero() {
+		begin, end := seconds(cols.begin), seconds(cols.end)
+		if cols.offset >= 0 && field(cols.offset) != "" {
+			// Raven tables of several files count Begin Time across all files
+			end = seconds(cols.offset) + end - begin
+			begin = seconds(cols.offset)
+		}
+		record.Begin = start.Add(time.Duration(begin * float64(time.Second)))
+		if end > begin {
+			record.End = start.Add(time.Duration(end * float64(time.Second)))
+		}
+	}
+	return record
+}
+
+// timestampFromName parses the recording start time from a file name, returning
+// the zero time when the name contains no timestamp
+func timestampFromName(name string) time.Time {
+	if m := isoTimestamp.FindStringSubmatch(name); m != nil {
+		if t, err := time.ParseInLocation("2006-01-02150405", m[1]+m[2]+m[3]+m[4], time.Local); err == nil {
+			return t
+		}
+	}
+	if m := compactTimestamp.FindStringSubmatch(name); m != nil {
+		if t, err := time.ParseInLocation("20060102150405", m[1]+m[2], time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// fileError wraps a result file error with context
+func fileError(err error, operation, path string) error {
+	return errors.New(err).
+		Component("importer").
+		Category(errors.CategoryFileParsing).
+		Context("operation", operation).
+		Context("path", path).
+		Build()
+}
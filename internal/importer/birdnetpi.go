@@ -0,0 +1,124 @@
+// birdnetpi.go: Reader for BirdNET-Pi birds.db databases
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// BirdNET-Pi source identifiers stored with imported detections
+const (
+	birdNETPiSourceID   = "birdnet-pi"
+	birdNETPiSourceName = "BirdNET-Pi"
+)
+
+// BirdNETPiReader reads the detections table of a BirdNET-Pi birds.db database
+type BirdNETPiReader struct {
+	DBPath string // Path of birds.db
+	// ClipsDir is the BirdNET-Pi Extracted directory containing By_Date, usually
+	// ~/BirdSongs/Extracted. Clips are not imported when empty.
+	ClipsDir string
+}
+
+// Name implements Reader
+func (r *BirdNETPiReader) Name() string {
+	return "BirdNET-Pi " + filepath.Base(r.DBPath)
+}
+
+// Read implements Reader
+func (r *BirdNETPiReader) Read(ctx context.Context, fn func(*Record) error) error {
+	db, err := gorm.Open(sqlite.Open("file:"+r.DBPath+"?mode=ro"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return r.readError(err, "open_birdnetpi_database")
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer func() {
+			if err := sqlDB.Close(); err != nil {
+				GetLogger().Warn("Failed to close BirdNET-Pi database",
+					logger.String("path", r.DBPath),
+					logger.Error(err))
+			}
+		}()
+	}
+
+	// Date and Time are cast to text, the driver would otherwise convert DATE columns to time.Time
+	rows, err := db.WithContext(ctx).Raw(
+		"SELECT CAST(Date AS TEXT), CAST(Time AS TEXT), Sci_Name, Com_Name, Confidence, Lat, Lon, Cutoff, Sens, File_Name " +
+			"FROM detections ORDER BY Date, Time").Rows()
+	if err != nil {
+		return r.readError(err, "query_birdnetpi_detections")
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			GetLogger().Warn("Failed to close BirdNET-Pi rows", logger.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			date, clock, sciName, comName string
+			confidence, lat, lon          sql.NullFloat64
+			cutoff, sens                  sql.NullFloat64
+			fileName                      sql.NullString
+		)
+		if err := rows.Scan(&date, &clock, &sciName, &comName, &confidence, &lat, &lon, &cutoff, &sens, &fileName); err != nil {
+			return r.readError(err, "scan_birdnetpi_detection")
+		}
+
+		record := &Record{
+			ScientificName: strings.TrimSpace(sciName),
+			CommonName:     strings.TrimSpace(comName),
+			Confidence:     confidence.Float64,
+			Latitude:       lat.Float64,
+			Longitude:      lon.Float64,
+			Threshold:      cutoff.Float64,
+			Sensitivity:    sens.Float64,
+			SourceID:       birdNETPiSourceID,
+			SourceName:     birdNETPiSourceName,
+		}
+		// Rows with an unparsable time are passed on with a zero time and counted as invalid
+		if begin, err := time.ParseInLocation(time.DateTime, date+" "+clock, time.Local); err == nil {
+			record.Begin = begin
+		}
+		if fileName.Valid && fileName.String != "" && r.ClipsDir != "" {
+			record.ClipPath = r.clipPath(date, record.CommonName, fileName.String)
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return r.readError(err, "read_birdnetpi_detections")
+	}
+	return nil
+}
+
+// clipPath returns the location of an extracted clip, BirdNET-Pi stores them as
+// By_Date/<date>/<common name>/<file name> with spaces of the common name replaced
+// by underscores and apostrophes removed
+func (r *BirdNETPiReader) clipPath(date, commonName, fileName string) string {
+	dirName := strings.ReplaceAll(strings.ReplaceAll(commonName, " ", "_"), "'", "")
+	return filepath.Join(r.ClipsDir, "By_Date", date, dirName, filepath.Base(fileName))
+}
+
+// readError wraps a database error with context
+func (r *BirdNETPiReader) readError(err error, operation string) error {
+	return errors.New(err).
+		Component("importer").
+		Category(errors.CategoryDatabase).
+		Context("operation", operation).
+		Context("path", r.DBPath).
+		Build()
+}
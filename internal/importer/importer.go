@@ -0,0 +1,340 @@
+// Package importer imports historic detections from other BirdNET applications
+// into the BirdNET-Go database.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// DefaultSourceNode tags imported detections when no source node is configured
+const DefaultSourceNode = "import"
+
+// ClipMode controls how existing audio clips are attached to imported detections
+type ClipMode string
+
+const (
+	ClipCopy ClipMode = "copy" // Copy clips into the clip export directory
+	ClipLink ClipMode = "link" // Hard link clips, copying when linking is not possible
+	ClipNone ClipMode = "none" // Import detections without clips
+)
+
+// ParseClipMode validates a clip mode name
+func ParseClipMode(mode string) (ClipMode, error) {
+	switch m := ClipMode(strings.ToLower(strings.TrimSpace(mode))); m {
+	case ClipCopy, ClipLink, ClipNone:
+		return m, nil
+	case "":
+		return ClipCopy, nil
+	default:
+		return "", fmt.Errorf("unsupported clip mode %q, use copy, link or none", mode)
+	}
+}
+
+// GetLogger returns the importer package logger
+func GetLogger() logger.Logger {
+	return logger.Global().Module("importer")
+}
+
+// Record is a single detection read from an import source
+type Record struct {
+	Begin          time.Time // Local start time of the detection
+	End            time.Time
+	ScientificName string
+	CommonName     string
+	SpeciesCode    string
+	Confidence     float64
+	Latitude       float64
+	Longitude      float64
+	Threshold      float64
+	Sensitivity    float64
+	ClipPath       string // Existing audio clip of the detection, empty when there is none
+	SourceID       string // Audio source ID stored with the detection
+	SourceName     string // Audio source display name stored with the detection
+}
+
+// Reader reads detections from an import source
+type Reader interface {
+	// Name describes the import source for logs and summaries
+	Name() string
+	// Read calls fn for every detection, stopping at the first error returned by fn
+	Read(ctx context.Context, fn func(*Record) error) error
+}
+
+// Store is the part of the datastore used by the importer
+type Store interface {
+	Save(note *datastore.Note, results []datastore.Results) error
+	NoteExists(date, timeStr, scientificName string) (bool, error)
+}
+
+// SpeciesResolver completes the names and eBird code of a record, e.g. from the
+// BirdNET taxonomy, when the import source does not provide them
+type SpeciesResolver func(r *Record)
+
+// Options controls an import
+type Options struct {
+	SourceNode string          // Source node tag of imported detections, DefaultSourceNode when empty
+	ClipMode   ClipMode        // How clips are attached, ClipCopy when empty
+	ExportPath string          // Clip export directory of BirdNET-Go, required unless clips are skipped
+	DryRun     bool            // Count what would be imported without writing anything
+	Resolve    SpeciesResolver // Optional species name and code lookup
+	// Progress is called periodically with the running totals
+	Progress func(*Result)
+}
+
+// Result summarizes an import
+type Result struct {
+	Read         int // Records read from the source
+	Imported     int // Detections saved
+	Duplicates   int // Detections skipped because they were already stored
+	Invalid      int // Records skipped because they lack a species or time
+	ClipsLinked  int // Clips attached to imported detections
+	ClipsMissing int // Clips referenced by the source that were not found
+}
+
+// progressInterval is the number of records between progress callbacks
+const progressInterval = 500
+
+// Importer saves records from import sources into the datastore
+type Importer struct {
+	store Store
+	opts  Options
+}
+
+// New creates an importer writing to store
+func New(store Store, opts Options) (*Importer, error) {
+	if opts.SourceNode == "" {
+		opts.SourceNode = DefaultSourceNode
+	}
+	if opts.ClipMode == "" {
+		opts.ClipMode = ClipCopy
+	}
+	if opts.ClipMode != ClipNone && opts.ExportPath == "" {
+		return nil, errors.Newf("clip export path is required to import clips").
+			Component("importer").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "create_importer").
+			Context("clip_mode", string(opts.ClipMode)).
+			Build()
+	}
+	return &Importer{store: store, opts: opts}, nil
+}
+
+// Import reads all records of the reader and saves the ones not stored yet
+func (imp *Importer) Import(ctx context.Context, reader Reader) (*Result, error) {
+	result := &Result{}
+	err := reader.Read(ctx, func(r *Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result.Read++
+		if err := imp.importRecord(r, result); err != nil {
+			return err
+		}
+		if imp.opts.Progress != nil && result.Read%progressInterval == 0 {
+			imp.opts.Progress(result)
+		}
+		return nil
+	})
+	if imp.opts.Progress != nil {
+		imp.opts.Progress(result)
+	}
+
+	GetLogger().Info("Import finished",
+		logger.String("source", reader.Name()),
+		logger.Int("read", result.Read),
+		logger.Int("imported", result.Imported),
+		logger.Int("duplicates", result.Duplicates),
+		logger.Int("invalid", result.Invalid),
+		logger.Int("clips_linked", result.ClipsLinked),
+		logger.Int("clips_missing", result.ClipsMissing),
+		logger.Bool("dry_run", imp.opts.DryRun))
+
+	if err != nil {
+		return result, errors.New(err).
+			Component("importer").
+			Category(errors.CategoryProcessing).
+			Context("operation", "import_detections").
+			Context("source", reader.Name()).
+			Context("records_read", result.Read).
+			Build()
+	}
+	return result, nil
+}
+
+// importRecord saves a single record unless it is invalid or already stored
+func (imp *Importer) importRecord(r *Record, result *Result) error {
+	if imp.opts.Resolve != nil {
+		imp.opts.Resolve(r)
+	}
+	if r.ScientificName == "" || r.Begin.IsZero() {
+		result.Invalid++
+		return nil
+	}
+	if r.CommonName == "" {
+		r.CommonName = r.ScientificName
+	}
+
+	date := r.Begin.Format(time.DateOnly)
+	timeStr := r.Begin.Format(time.TimeOnly)
+	exists, err := imp.store.NoteExists(date, timeStr, r.ScientificName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		result.Duplicates++
+		return nil
+	}
+
+	note := imp.newNote(r, date, timeStr)
+	if r.ClipPath != "" && imp.opts.ClipMode != ClipNone {
+		clipName, err := imp.attachClip(r)
+		switch {
+		case err != nil && os.IsNotExist(err):
+			result.ClipsMissing++
+		case err != nil:
+			return err
+		default:
+			note.ClipName = clipName
+			result.ClipsLinked++
+		}
+	}
+
+	if imp.opts.DryRun {
+		result.Imported++
+		return nil
+	}
+
+	results := []datastore.Results{{
+		Species:    r.ScientificName + "_" + r.CommonName,
+		Confidence: float32(note.Confidence),
+	}}
+	if err := imp.store.Save(&note, results); err != nil {
+		return err
+	}
+	result.Imported++
+	return nil
+}
+
+// newNote maps a record to a note
+func (imp *Importer) newNote(r *Record, date, timeStr string) datastore.Note {
+	end := r.End
+	if end.IsZero() {
+		end = r.Begin.Add(3 * time.Second) // BirdNET analyzes 3 second segments
+	}
+	return datastore.Note{
+		SourceNode:     imp.opts.SourceNode,
+		Date:           date,
+		Time:           timeStr,
+		Source:         datastore.AudioSource{ID: r.SourceID, DisplayName: r.SourceName},
+		BeginTime:      r.Begin,
+		EndTime:        end,
+		SpeciesCode:    r.SpeciesCode,
+		ScientificName: r.ScientificName,
+		CommonName:     r.CommonName,
+		Confidence:     math.Round(r.Confidence*100) / 100,
+		Latitude:       r.Latitude,
+		Longitude:      r.Longitude,
+		Threshold:      r.Threshold,
+		Sensitivity:    r.Sensitivity,
+	}
+}
+
+// clipName returns the clip name of an imported clip, following the year/month layout
+// and naming of clips saved by BirdNET-Go
+func clipName(r *Record) string {
+	name := strings.ToLower(strings.ReplaceAll(r.ScientificName, " ", "_"))
+	return filepath.ToSlash(filepath.Join(
+		r.Begin.Format("2006"),
+		r.Begin.Format("01"),
+		fmt.Sprintf("%s_%.0fp_%s%s", name, r.Confidence*100, r.Begin.Format("20060102T150405"),
+			strings.ToLower(filepath.Ext(r.ClipPath))),
+	))
+}
+
+// attachClip makes the clip of a record available in the clip export directory and
+// returns its clip name. Clips already inside the export directory are used in place.
+func (imp *Importer) attachClip(r *Record) (string, error) {
+	info, err := os.Stat(r.ClipPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", os.ErrNotExist
+	}
+
+	if rel, err := filepath.Rel(imp.opts.ExportPath, r.ClipPath); err == nil && !strings.HasPrefix(rel, "..") && !filepath.IsAbs(rel) {
+		return filepath.ToSlash(rel), nil
+	}
+
+	name := clipName(r)
+	if imp.opts.DryRun {
+		return name, nil
+	}
+
+	target := filepath.Join(imp.opts.ExportPath, filepath.FromSlash(name))
+	if _, err := os.Stat(target); err == nil {
+		return name, nil // Attached by an earlier import
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil { //nolint:gosec // G301: clip directories are served by the web UI
+		return "", clipError(err, "create_clip_directory", target)
+	}
+	if imp.opts.ClipMode == ClipLink {
+		if err := os.Link(r.ClipPath, target); err == nil {
+			return name, nil
+		}
+		// Fall back to copying, e.g. when the clip is on another file system
+	}
+	if err := copyFile(r.ClipPath, target); err != nil {
+		return "", clipError(err, "copy_clip", target)
+	}
+	return name, nil
+}
+
+// copyFile copies src to dst, removing a partially written dst on failure
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src) //nolint:gosec // G304: src is a clip path from the import source
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := in.Close(); closeErr != nil {
+			GetLogger().Warn("Failed to close clip", logger.String("path", src), logger.Error(closeErr))
+		}
+	}()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644) //nolint:gosec // G302,G304: dst is inside the clip export directory
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+	}
+	return err
+}
+
+// clipError wraps a clip file error with context
+func clipError(err error, operation, path string) error {
+	return errors.New(err).
+		Component("importer").
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Context("path", path).
+		Build()
+}
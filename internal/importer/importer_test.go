@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeStore records saved notes in memory
+type fakeStore struct {
+	notes []datastore.Note
+}
+
+func (s *fakeStore) Save(note *datastore.Note, results []datastore.Results) error {
+	s.notes = append(s.notes, *note)
+	return nil
+}
+
+func (s *fakeStore) NoteExists(date, timeStr, scientificName string) (bool, error) {
+	for i := range s.notes {
+		n := &s.notes[i]
+		if n.Date == date && n.Time == timeStr && n.ScientificName == scientificName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createBirdNETPiDB writes a birds.db with the BirdNET-Pi schema
+func createBirdNETPiDB(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "birds.db")
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE detections (Date DATE, Time TIME, Sci_Name VARCHAR(100) NOT NULL,
+		Com_Name VARCHAR(100) NOT NULL, Confidence FLOAT, Lat FLOAT, Lon FLOAT, Cutoff FLOAT, Week INT,
+		Sens FLOAT, Overlap FLOAT, File_Name VARCHAR(100) NOT NULL)`).Error)
+	rows := [][]any{
+		{"2023-05-01", "06:00:00", "Turdus merula", "Eurasian Blackbird", 0.91, "Eurasian_Blackbird-91-2023-05-01-birdnet-06:00:00.mp3"},
+		{"2023-05-01", "06:05:00", "Erithacus rubecula", "European Robin", 0.77, "European_Robin-77-2023-05-01-birdnet-06:05:00.mp3"},
+		{"2023-05-02", "07:00:00", "Turdus merula", "Eurasian Blackbird", 0.85, "missing.mp3"},
+	}
+	for _, row := range rows {
+		require.NoError(t, db.Exec(`INSERT INTO detections VALUES (?, ?, ?, ?, ?, 60.1, 24.9, 0.7, 18, 1.25, 0.0, ?)`,
+			row...).Error)
+	}
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	return path
+}
+
+func TestImportBirdNETPi(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	dbPath := createBirdNETPiDB(t, dir)
+
+	extracted := filepath.Join(dir, "Extracted")
+	clipDir := filepath.Join(extracted, "By_Date", "2023-05-01", "Eurasian_Blackbird")
+	require.NoError(t, os.MkdirAll(clipDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(clipDir, "Eurasian_Blackbird-91-2023-05-01-birdnet-06:00:00.mp3"), []byte("clip"), 0o600))
+
+	exportPath := filepath.Join(dir, "clips")
+	store := &fakeStore{}
+	imp, err := New(store, Options{SourceNode: "old-pi", ExportPath: exportPath})
+	require.NoError(t, err)
+
+	reader := &BirdNETPiReader{DBPath: dbPath, ClipsDir: extracted}
+	result, err := imp.Import(context.Background(), reader)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Read)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 1, result.ClipsLinked)
+	assert.Equal(t, 2, result.ClipsMissing)
+
+	first := store.notes[0]
+	assert.Equal(t, "2023-05-01", first.Date)
+	assert.Equal(t, "06:00:00", first.Time)
+	assert.Equal(t, "old-pi", first.SourceNode)
+	assert.Equal(t, "BirdNET-Pi", first.Source.DisplayName)
+	assert.InDelta(t, 0.91, first.Confidence, 0.001)
+	assert.InDelta(t, 60.1, first.Latitude, 0.001)
+	require.NotEmpty(t, first.ClipName)
+	data, err := os.ReadFile(filepath.Join(exportPath, filepath.FromSlash(first.ClipName)))
+	require.NoError(t, err)
+	assert.Equal(t, "clip", string(data))
+
+	// A second import finds every detection already stored
+	result, err = imp.Import(context.Background(), reader)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 3, result.Duplicates)
+	assert.Len(t, store.notes, 3)
+}
+
+func TestImportAnalyzerResults(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "20240501_060000.BirdNET.results.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte(
+		"Start (s),End (s),Scientific name,Common name,Confidence\n"+
+			"3.0,6.0,Turdus merula,Eurasian Blackbird,0.8123\n"+
+			"12.0,15.0,Parus major,Great Tit,0.65\n"), 0o600))
+
+	ravenPath := filepath.Join(dir, "garden.BirdNET.selection.table.txt")
+	require.NoError(t, os.WriteFile(ravenPath, []byte(
+		"Selection\tView\tChannel\tBegin Time (s)\tEnd Time (s)\tLow Freq (Hz)\tHigh Freq (Hz)\tCommon Name\tSpecies Code\tConfidence\tBegin Path\tFile Offset (s)\n"+
+			"1\tSpectrogram 1\t1\t0\t3.0\t0\t15000\tAmerican Robin\tamerob\t0.9\t/rec/2024-05-02T05-30-00.wav\t0\n"+
+			"1\tWaveform 1\t1\t0\t3.0\t0\t15000\tAmerican Robin\tamerob\t0.9\t/rec/2024-05-02T05-30-00.wav\t0\n"), 0o600))
+
+	store := &fakeStore{}
+	imp, err := New(store, Options{
+		ClipMode: ClipNone,
+		Resolve: NewTaxonomyResolver(map[string]string{
+			"amerob":                            "Turdus migratorius_American Robin",
+			"Turdus migratorius_American Robin": "amerob",
+			"eurbla":                            "Turdus merula_Eurasian Blackbird",
+		}),
+	})
+	require.NoError(t, err)
+
+	result, err := imp.Import(context.Background(), &AnalyzerReader{Paths: []string{dir}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Read)
+	assert.Equal(t, 3, result.Imported)
+
+	byName := make(map[string]datastore.Note)
+	for i := range store.notes {
+		byName[store.notes[i].ScientificName] = store.notes[i]
+	}
+
+	blackbird := byName["Turdus merula"]
+	assert.Equal(t, "2024-05-01", blackbird.Date)
+	assert.Equal(t, "06:00:03", blackbird.Time)
+	assert.Equal(t, "eurbla", blackbird.SpeciesCode)
+	assert.InDelta(t, 0.81, blackbird.Confidence, 0.001)
+	assert.Equal(t, DefaultSourceNode, blackbird.SourceNode)
+
+	robin := byName["Turdus migratorius"]
+	assert.Equal(t, "American Robin", robin.CommonName)
+	assert.Equal(t, "2024-05-02", robin.Date)
+	assert.Equal(t, "05:30:00", robin.Time)
+	assert.Equal(t, "2024-05-02T05-30-00.wav", robin.Source.DisplayName)
+}
+
+func TestImportSkipsRecordsWithoutTime(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "unknown.BirdNET.results.csv")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"Start (s),End (s),Scientific name,Common name,Confidence\n"+
+			"3.0,6.0,Turdus merula,Eurasian Blackbird,0.8\n"), 0o600))
+
+	store := &fakeStore{}
+	imp, err := New(store, Options{ClipMode: ClipNone})
+	require.NoError(t, err)
+
+	result, err := imp.Import(context.Background(), &AnalyzerReader{Paths: []string{path}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Invalid)
+	assert.Empty(t, store.notes)
+
+	// The start time can be given explicitly
+	start := time.Date(2024, 6, 1, 4, 0, 0, 0, time.Local)
+	result, err = imp.Import(context.Background(), &AnalyzerReader{Paths: []string{path}, Start: start})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, "04:00:03", store.notes[0].Time)
+}
+
+func TestTimestampFromName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"20240501_060000.WAV", "2024-05-01 06:00:00"},
+		{"SMU01_20240501_060000.wav", "2024-05-01 06:00:00"},
+		{"2024-05-02T05-30-00.flac", "2024-05-02 05:30:00"},
+		{"2024-05-02 05:30:00.mp3", "2024-05-02 05:30:00"},
+		{"garden.wav", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := timestampFromName(tt.name)
+			if tt.want == "" {
+				assert.True(t, got.IsZero())
+				return
+			}
+			assert.Equal(t, tt.want, got.Format(time.DateTime))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// taxonomy.go: Species name and eBird code lookup for imported records
+package importer
+
+import "strings"
+
+// NewTaxonomyResolver creates a resolver from the BirdNET eBird taxonomy map, which maps
+// eBird codes to "ScientificName_CommonName" and back. Raven selection tables only
+// carry the common name and eBird code, so the scientific name is looked up here.
+func NewTaxonomyResolver(taxonomy map[string]string) SpeciesResolver {
+	codeToName := make(map[string][2]string)
+	sciToCode := make(map[string]string)
+	commonToSci := make(map[string]string)
+
+	for key, value := range taxonomy {
+		if strings.Contains(key, "_") {
+			continue // Reverse entries are covered by the code entries
+		}
+		sci, common, ok := strings.Cut(value, "_")
+		if !ok {
+			continue
+		}
+		codeToName[key] = [2]string{sci, common}
+		sciToCode[strings.ToLower(sci)] = key
+		commonToSci[strings.ToLower(common)] = sci
+	}
+
+	return func(r *Record) {
+		if r.ScientificName == "" && r.SpeciesCode != "" {
+			if names, ok := codeToName[r.SpeciesCode]; ok {
+				r.ScientificName = names[0]
+				if r.CommonName == "" {
+					r.CommonName = names[1]
+				}
+			}
+		}
+		if r.ScientificName == "" && r.CommonName != "" {
+			r.ScientificName = commonToSci[strings.ToLower(r.CommonName)]
+		}
+		if r.SpeciesCode == "" && r.ScientificName != "" {
+			r.SpeciesCode = sciToCode[strings.ToLower(r.ScientificName)]
+		}
+	}
+}
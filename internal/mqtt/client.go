@@ -42,26 +42,21 @@ type client struct {
 func NewClient(settings *conf.Settings, observabilityMetrics *observability.Metrics) (Client, error) {
 	log := GetLogger()
 	log.Info("Creating new MQTT client")
-	config := DefaultConfig()
-	config.Broker = settings.Realtime.MQTT.Broker
+	config := connectionConfig(settings)
 	config.ClientID = settings.Main.Name
-	config.Username = settings.Realtime.MQTT.Username
-	config.Password = settings.Realtime.MQTT.Password // Keep password in config, but don't log it
 	config.Topic = settings.Realtime.MQTT.Topic
 	config.Retain = settings.Realtime.MQTT.Retain
 	config.Debug = settings.Realtime.MQTT.Debug
 
-	// Configure TLS settings
-	config.TLS.Enabled = settings.Realtime.MQTT.TLS.Enabled
-	config.TLS.InsecureSkipVerify = settings.Realtime.MQTT.TLS.InsecureSkipVerify
-	config.TLS.CACert = settings.Realtime.MQTT.TLS.CACert
-	config.TLS.ClientCert = settings.Realtime.MQTT.TLS.ClientCert
-	config.TLS.ClientKey = settings.Realtime.MQTT.TLS.ClientKey
+	// Home Assistant marks the entities of this node unavailable through the will message
+	if settings.Realtime.MQTT.HomeAssistant.Enabled {
+		config.AvailabilityTopic = AvailabilityTopic(config.Topic)
+	}
 
-	// Auto-detect TLS from broker URL scheme
-	if strings.HasPrefix(config.Broker, "ssl://") || strings.HasPrefix(config.Broker, "tls://") || strings.HasPrefix(config.Broker, "mqtts://") {
-		config.TLS.Enabled = true
-		log.Info("TLS enabled based on broker URL scheme")
+	// Commands are only accepted with a token, validation rejects enabling them without one
+	if settings.Realtime.MQTT.Commands.Enabled && settings.Realtime.MQTT.Commands.Token != "" {
+		config.CommandTopic = CommandTopic(&settings.Realtime.MQTT)
+		config.CommandToken = settings.Realtime.MQTT.Commands.Token
 	}
 
 	// Note: Debug mode logging is now controlled by the central logger configuration
@@ -79,6 +74,8 @@ func NewClient(settings *conf.Settings, observabilityMetrics *observability.Metr
 		logger.Bool("debug", config.Debug),
 		logger.Bool("tls_enabled", config.TLS.Enabled),
 		logger.Bool("tls_skip_verify", config.TLS.InsecureSkipVerify),
+		logger.String("availability_topic", config.AvailabilityTopic),
+		logger.String("command_topic", config.CommandTopic),
 	)
 
 	return &client{
@@ -89,6 +86,30 @@ func NewClient(settings *conf.Settings, observabilityMetrics *observability.Metr
 	}, nil
 }
 
+// connectionConfig returns the default configuration with the broker, credentials and
+// TLS settings of realtime.mqtt
+func connectionConfig(settings *conf.Settings) Config {
+	config := DefaultConfig()
+	config.Broker = settings.Realtime.MQTT.Broker
+	config.Username = settings.Realtime.MQTT.Username
+	config.Password = settings.Realtime.MQTT.Password // Keep password in config, but don't log it
+
+	// Configure TLS settings
+	config.TLS.Enabled = settings.Realtime.MQTT.TLS.Enabled
+	config.TLS.InsecureSkipVerify = settings.Realtime.MQTT.TLS.InsecureSkipVerify
+	config.TLS.CACert = settings.Realtime.MQTT.TLS.CACert
+	config.TLS.ClientCert = settings.Realtime.MQTT.TLS.ClientCert
+	config.TLS.ClientKey = settings.Realtime.MQTT.TLS.ClientKey
+
+	// Auto-detect TLS from broker URL scheme
+	if strings.HasPrefix(config.Broker, "ssl://") || strings.HasPrefix(config.Broker, "tls://") || strings.HasPrefix(config.Broker, "mqtts://") {
+		config.TLS.Enabled = true
+		GetLogger().Info("TLS enabled based on broker URL scheme")
+	}
+
+	return config
+}
+
 // SetControlChannel sets the control channel for the client
 func (c *client) SetControlChannel(ch chan string) {
 	c.mu.Lock()
@@ -297,6 +318,20 @@ func (c *client) handleConnectionFailure(connectErr error, clientToConnect mqtt.
 
 // Publish sends a message to the specified topic on the MQTT broker.
 func (c *client) Publish(ctx context.Context, topic, payload string) error {
+	c.mu.RLock()
+	retain := c.config.Retain
+	c.mu.RUnlock()
+	return c.publish(ctx, topic, payload, retain)
+}
+
+// PublishRetained sends a message that the broker retains for new subscribers,
+// regardless of the configured retain setting.
+func (c *client) PublishRetained(ctx context.Context, topic, payload string) error {
+	return c.publish(ctx, topic, payload, true)
+}
+
+// publish sends a message to the specified topic with the given retain flag.
+func (c *client) publish(ctx context.Context, topic, payload string, retain bool) error {
 	// Check context before acquiring lock
 	if err := ctx.Err(); err != nil {
 		GetLogger().Warn("Publish context already cancelled",
@@ -323,13 +358,12 @@ func (c *client) Publish(ctx context.Context, topic, payload string) error {
 	}
 	GetLogger().Debug("Client is connected, continuing")
 	clientToPublish := c.internalClient // Get client instance under lock
-	currentRetain := c.config.Retain    // Get config value under lock
 	c.mu.Unlock()                       // Unlock before blocking publish call
 
 	log := GetLogger().With(
 		logger.String("topic", topic),
 		logger.Int("qos", defaultQoS),
-		logger.Bool("retain", currentRetain))
+		logger.Bool("retain", retain))
 	timer := c.metrics.StartPublishTimer()
 	defer timer.ObserveDuration()
 
@@ -337,7 +371,7 @@ func (c *client) Publish(ctx context.Context, topic, payload string) error {
 		logger.Int("payload_size", len(payload)))
 
 	// Perform the publish operation directly
-	token := clientToPublish.Publish(topic, defaultQoS, currentRetain, payload)
+	token := clientToPublish.Publish(topic, defaultQoS, retain, payload)
 
 	// Wait directly on the token with timeout
 	if !token.WaitTimeout(c.config.PublishTimeout) {
@@ -377,7 +411,7 @@ func (c *client) Publish(ctx context.Context, topic, payload string) error {
 			Context("topic", topic).
 			Context("payload_size", len(payload)).
 			Context("qos", defaultQoS).
-			Context("retain", currentRetain).
+			Context("retain", retain).
 			Context("operation", "publish_error").
 			Build()
 		return enhancedErr
@@ -479,6 +513,11 @@ func (c *client) configureClientOptions(log logger.Logger) (*mqtt.ClientOptions,
 	opts.SetWriteTimeout(WriteTimeout)
 	opts.SetConnectTimeout(c.config.ConnectTimeout) // Use config timeout for initial connection attempt
 
+	// The broker publishes the will message when the connection is lost without a disconnect
+	if c.config.AvailabilityTopic != "" {
+		opts.SetWill(c.config.AvailabilityTopic, AvailabilityOffline, defaultQoS, true)
+	}
+
 	// Configure TLS if enabled
 	if c.config.TLS.Enabled {
 		tlsConfig, err := c.createTLSConfig()
@@ -724,6 +763,14 @@ func (c *client) disconnectWithTimeout(timeout time.Duration) {
 		// Check connection status *outside* lock to avoid potential deadlock
 		// if IsConnected internally needs a lock (though it uses RLock)
 		if clientToDisconnect.IsConnected() {
+			// A clean disconnect does not trigger the will message, mark the node offline first
+			if c.config.AvailabilityTopic != "" {
+				token := clientToDisconnect.Publish(c.config.AvailabilityTopic, defaultQoS, true, AvailabilityOffline)
+				if !token.WaitTimeout(timeout) || token.Error() != nil {
+					log.Warn("Failed to publish offline availability before disconnect",
+						logger.String("topic", c.config.AvailabilityTopic))
+				}
+			}
 			disconnectTimeoutMs := uint(timeout.Milliseconds()) // #nosec G115 -- timeout value conversion safe
 			log.Debug("Sending disconnect signal to Paho client",
 				logger.Uint64("timeout_ms", uint64(disconnectTimeoutMs)))
@@ -746,6 +793,14 @@ func (c *client) onConnect(client mqtt.Client) {
 		logger.String("broker", c.config.Broker),
 		logger.String("client_id", c.config.ClientID))
 	c.metrics.UpdateConnectionStatus(true)
+	// Replace the retained will message, the handler must not block so the token is not awaited
+	if c.config.AvailabilityTopic != "" {
+		client.Publish(c.config.AvailabilityTopic, defaultQoS, true, AvailabilityOnline)
+	}
+	// Subscriptions do not survive clean sessions, subscribe again on every connect
+	if c.config.CommandTopic != "" {
+		c.subscribeCommands(client)
+	}
 	// Reset reconnect attempts on successful connection - might be handled by Connect logic resetting lastConnAttempt implicitly
 }
 
@@ -0,0 +1,267 @@
+// commands.go: Remote control commands received on a subscribed MQTT topic
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Commands accepted on the command topic. Commands other than the species toggles
+// are forwarded unchanged as control signals to the control monitor.
+const (
+	CommandReloadBirdNET        = "reload_birdnet"
+	CommandRebuildRangeFilter   = "rebuild_range_filter"
+	CommandReconfigureRTSP      = "reconfigure_rtsp_sources"
+	CommandPauseAnalysis        = "pause_analysis"
+	CommandResumeAnalysis       = "resume_analysis"
+	CommandToggleSpeciesInclude = "toggle_species_include"
+	CommandToggleSpeciesExclude = "toggle_species_exclude"
+)
+
+// Limits of command messages
+const (
+	maxCommandPayloadSize   = 4096
+	maxCommandIDLength      = 64
+	maxCommandSpeciesLength = 200
+	commandSignalTimeout    = 5 * time.Second
+)
+
+// Command is a remote control command received on the command topic
+type Command struct {
+	ID      string `json:"id,omitempty"`      // Optional correlation ID echoed in the acknowledgement
+	Command string `json:"command"`           // Command name, e.g. "pause_analysis"
+	Token   string `json:"token"`             // Shared secret configured in realtime.mqtt.commands.token
+	Species string `json:"species,omitempty"` // Species of the species list toggles
+}
+
+// CommandAck acknowledges a command on the acknowledgement topic
+type CommandAck struct {
+	ID        string    `json:"id,omitempty"`
+	Command   string    `json:"command"`
+	Success   bool      `json:"success"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CommandTopic returns the configured command topic, or <topic>/command when none is set
+func CommandTopic(settings *conf.MQTTSettings) string {
+	if settings.Commands.Topic != "" {
+		return settings.Commands.Topic
+	}
+	return strings.TrimSuffix(settings.Topic, "/") + "/command"
+}
+
+// CommandAckTopic returns the topic acknowledgements of commands are published to
+func CommandAckTopic(commandTopic string) string {
+	return strings.TrimSuffix(commandTopic, "/") + "/ack"
+}
+
+// ParseCommand decodes a command message, authenticates it against the configured
+// token and validates it against the command schema
+func ParseCommand(payload []byte, token string) (*Command, error) {
+	if len(payload) > maxCommandPayloadSize {
+		return nil, newCommandError(fmt.Sprintf("command exceeds %d bytes", maxCommandPayloadSize), "size")
+	}
+
+	var cmd Command
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cmd); err != nil {
+		return nil, newCommandError("command is not a valid JSON command object", "decode")
+	}
+
+	// Compare in constant time and never accept commands without a configured token
+	if token == "" || subtle.ConstantTimeCompare([]byte(cmd.Token), []byte(token)) != 1 {
+		return nil, newCommandError("command is not authorized", "authenticate")
+	}
+
+	if len(cmd.ID) > maxCommandIDLength {
+		return nil, newCommandError(fmt.Sprintf("command id exceeds %d characters", maxCommandIDLength), "validate")
+	}
+
+	switch cmd.Command {
+	case CommandReloadBirdNET, CommandRebuildRangeFilter, CommandReconfigureRTSP,
+		CommandPauseAnalysis, CommandResumeAnalysis:
+		if cmd.Species != "" {
+			return nil, newCommandError(fmt.Sprintf("command %q does not take a species", cmd.Command), "validate")
+		}
+	case CommandToggleSpeciesInclude, CommandToggleSpeciesExclude:
+		cmd.Species = strings.TrimSpace(cmd.Species)
+		if cmd.Species == "" || len(cmd.Species) > maxCommandSpeciesLength {
+			return nil, newCommandError(fmt.Sprintf("command %q requires a species of at most %d characters", cmd.Command, maxCommandSpeciesLength), "validate")
+		}
+	default:
+		return nil, newCommandError(fmt.Sprintf("unknown command %q", cmd.Command), "validate")
+	}
+
+	return &cmd, nil
+}
+
+// newCommandError creates a validation error of a rejected command
+func newCommandError(message, operation string) error {
+	return errors.Newf("%s", message).
+		Component("mqtt").
+		Category(errors.CategoryValidation).
+		Context("operation", "command_"+operation).
+		Build()
+}
+
+// subscribeCommands subscribes to the command topic. It is called from the connect
+// handler, which must not block, so the subscription result is awaited separately.
+func (c *client) subscribeCommands(client mqtt.Client) {
+	topic := c.config.CommandTopic
+	token := client.Subscribe(topic, defaultQoS, c.onCommandMessage)
+	go func() {
+		if !token.WaitTimeout(c.config.PublishTimeout) {
+			GetLogger().Warn("Timed out subscribing to MQTT command topic",
+				logger.String("topic", topic))
+			return
+		}
+		if err := token.Error(); err != nil {
+			GetLogger().Error("Failed to subscribe to MQTT command topic",
+				logger.String("topic", topic),
+				logger.Error(err))
+			return
+		}
+		GetLogger().Info("Subscribed to MQTT command topic",
+			logger.String("topic", topic))
+	}()
+}
+
+// onCommandMessage handles messages on the command topic. The paho message handler
+// must not block, commands are executed on their own goroutine.
+func (c *client) onCommandMessage(_ mqtt.Client, msg mqtt.Message) {
+	// A retained command would be executed again on every connect
+	if msg.Retained() {
+		GetLogger().Warn("Ignoring retained MQTT command",
+			logger.String("topic", msg.Topic()))
+		return
+	}
+
+	payload := slices.Clone(msg.Payload())
+	go c.handleCommand(payload)
+}
+
+// handleCommand executes a command message and publishes its acknowledgement
+func (c *client) handleCommand(payload []byte) {
+	c.mu.RLock()
+	token := c.config.CommandToken
+	ackTopic := CommandAckTopic(c.config.CommandTopic)
+	c.mu.RUnlock()
+
+	ack := CommandAck{Timestamp: time.Now()}
+	cmd, err := ParseCommand(payload, token)
+	if err == nil {
+		ack.ID = cmd.ID
+		ack.Command = cmd.Command
+		ack.Message, err = c.executeCommand(cmd)
+	}
+	if err != nil {
+		GetLogger().Warn("MQTT command rejected",
+			logger.String("command", ack.Command),
+			logger.Error(err))
+		ack.Message = err.Error()
+	} else {
+		ack.Success = true
+		GetLogger().Info("MQTT command accepted",
+			logger.String("command", ack.Command),
+			logger.String("id", ack.ID))
+	}
+
+	ackPayload, err := json.Marshal(ack)
+	if err != nil {
+		GetLogger().Error("Failed to marshal MQTT command acknowledgement", logger.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.PublishTimeout)
+	defer cancel()
+	if err := c.publish(ctx, ackTopic, string(ackPayload), false); err != nil {
+		GetLogger().Warn("Failed to publish MQTT command acknowledgement",
+			logger.String("topic", ackTopic),
+			logger.Error(err))
+	}
+}
+
+// executeCommand runs a validated command and returns the acknowledgement message
+func (c *client) executeCommand(cmd *Command) (string, error) {
+	switch cmd.Command {
+	case CommandToggleSpeciesInclude, CommandToggleSpeciesExclude:
+		listName := conf.SpeciesListInclude
+		if cmd.Command == CommandToggleSpeciesExclude {
+			listName = conf.SpeciesListExclude
+		}
+		added, err := toggleSpeciesList(listName, cmd.Species)
+		if err != nil {
+			return "", err
+		}
+		// Both lists are applied by the range filter
+		if err := c.sendControlSignal(CommandRebuildRangeFilter); err != nil {
+			return "", err
+		}
+		if added {
+			return fmt.Sprintf("%s added to the %s list", cmd.Species, listName), nil
+		}
+		return fmt.Sprintf("%s removed from the %s list", cmd.Species, listName), nil
+	default:
+		if err := c.sendControlSignal(cmd.Command); err != nil {
+			return "", err
+		}
+		return "command queued", nil
+	}
+}
+
+// sendControlSignal forwards a control signal to the control monitor. The read lock
+// is held while sending so SetControlChannel(nil) waits for pending signals before
+// the channel is closed on shutdown.
+func (c *client) sendControlSignal(signal string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.controlChan == nil {
+		return errors.Newf("control channel not available").
+			Component("mqtt").
+			Category(errors.CategorySystem).
+			Context("operation", "send_control_signal").
+			Context("signal", signal).
+			Build()
+	}
+
+	select {
+	case c.controlChan <- signal:
+		return nil
+	case <-time.After(commandSignalTimeout):
+		return errors.Newf("timed out queuing control signal").
+			Component("mqtt").
+			Category(errors.CategoryTimeout).
+			Context("operation", "send_control_signal").
+			Context("signal", signal).
+			Build()
+	}
+}
+
+// toggleSpeciesList adds a species to the include or exclude list, or removes it when
+// already listed, and saves the settings. Returns true when the species was added.
+func toggleSpeciesList(listName, species string) (bool, error) {
+	added, err := conf.ToggleListedSpecies(listName, species)
+	if err != nil {
+		return added, errors.New(err).
+			Component("mqtt").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "save_species_list").
+			Context("list", listName).
+			Build()
+	}
+	return added, nil
+}
@@ -0,0 +1,93 @@
+// commands_test.go: Tests for remote control commands
+package mqtt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const testCommandToken = "0123456789abcdef"
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		payload     string
+		expectError string
+	}{
+		{"pause", `{"id":"a1","command":"pause_analysis","token":"0123456789abcdef"}`, ""},
+		{"reload model", `{"command":"reload_birdnet","token":"0123456789abcdef"}`, ""},
+		{"toggle exclude", `{"command":"toggle_species_exclude","token":"0123456789abcdef","species":"House Sparrow"}`, ""},
+		{"wrong token", `{"command":"pause_analysis","token":"wrong"}`, "not authorized"},
+		{"missing token", `{"command":"pause_analysis"}`, "not authorized"},
+		{"unknown command", `{"command":"restart","token":"0123456789abcdef"}`, "unknown command"},
+		{"unknown field", `{"command":"pause_analysis","token":"0123456789abcdef","force":true}`, "not a valid JSON command"},
+		{"not JSON", `pause_analysis`, "not a valid JSON command"},
+		{"toggle without species", `{"command":"toggle_species_include","token":"0123456789abcdef"}`, "requires a species"},
+		{"species on signal command", `{"command":"pause_analysis","token":"0123456789abcdef","species":"House Sparrow"}`, "does not take a species"},
+		{"long id", `{"id":"` + strings.Repeat("x", 65) + `","command":"pause_analysis","token":"0123456789abcdef"}`, "id exceeds"},
+		{"oversized", `{"command":"pause_analysis","token":"0123456789abcdef","id":"` + strings.Repeat("x", 5000) + `"}`, "exceeds 4096 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd, err := ParseCommand([]byte(tt.payload), testCommandToken)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, cmd.Command)
+		})
+	}
+
+	_, err := ParseCommand([]byte(`{"command":"pause_analysis","token":""}`), "")
+	require.Error(t, err, "commands are rejected when no token is configured")
+}
+
+func TestCommandTopic(t *testing.T) {
+	t.Parallel()
+
+	settings := conf.MQTTSettings{Topic: "birdnet/"}
+	assert.Equal(t, "birdnet/command", CommandTopic(&settings))
+	settings.Commands.Topic = "station/control"
+	assert.Equal(t, "station/control", CommandTopic(&settings))
+	assert.Equal(t, "station/control/ack", CommandAckTopic(settings.Commands.Topic))
+}
+
+func TestHandleCommandForwardsControlSignal(t *testing.T) {
+	t.Parallel()
+
+	controlChan := make(chan string, 1)
+	c := &client{
+		config: Config{
+			CommandTopic:   "birdnet/command",
+			CommandToken:   testCommandToken,
+			PublishTimeout: time.Second,
+		},
+		controlChan: controlChan,
+	}
+
+	c.handleCommand([]byte(`{"command":"resume_analysis","token":"0123456789abcdef"}`))
+	select {
+	case signal := <-controlChan:
+		assert.Equal(t, CommandResumeAnalysis, signal)
+	default:
+		t.Fatal("expected a control signal")
+	}
+
+	c.handleCommand([]byte(`{"command":"resume_analysis","token":"invalid"}`))
+	assert.Empty(t, controlChan, "unauthorized commands are not forwarded")
+
+	c.SetControlChannel(nil)
+	_, err := c.executeCommand(&Command{Command: CommandReloadBirdNET})
+	require.Error(t, err, "commands fail without a control channel")
+}
@@ -0,0 +1,330 @@
+// homeassistant.go: Home Assistant MQTT discovery configs for detections, sound levels and system health
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Payloads of the availability topic
+const (
+	AvailabilityOnline  = "online"
+	AvailabilityOffline = "offline"
+)
+
+// Home Assistant entity components used by BirdNET-Go
+const (
+	ComponentSensor       = "sensor"
+	ComponentBinarySensor = "binary_sensor"
+)
+
+// AvailabilityTopic returns the topic of the online/offline availability of a node
+// publishing to baseTopic. The broker publishes "offline" to it as the will message.
+func AvailabilityTopic(baseTopic string) string {
+	return strings.TrimSuffix(baseTopic, "/") + "/status"
+}
+
+// DiscoveryDevice groups the entities of a BirdNET-Go node into one Home Assistant device
+type DiscoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+	SWVersion    string   `json:"sw_version,omitempty"`
+}
+
+// DiscoveryConfig is the payload of a Home Assistant MQTT discovery config
+type DiscoveryConfig struct {
+	Name                   string          `json:"name"`
+	UniqueID               string          `json:"unique_id"`
+	ObjectID               string          `json:"object_id"`
+	StateTopic             string          `json:"state_topic"`
+	ValueTemplate          string          `json:"value_template,omitempty"`
+	JSONAttributesTopic    string          `json:"json_attributes_topic,omitempty"`
+	JSONAttributesTemplate string          `json:"json_attributes_template,omitempty"`
+	UnitOfMeasurement      string          `json:"unit_of_measurement,omitempty"`
+	DeviceClass            string          `json:"device_class,omitempty"`
+	StateClass             string          `json:"state_class,omitempty"`
+	EntityCategory         string          `json:"entity_category,omitempty"`
+	Icon                   string          `json:"icon,omitempty"`
+	AvailabilityTopic      string          `json:"availability_topic"`
+	PayloadAvailable       string          `json:"payload_available"`
+	PayloadNotAvailable    string          `json:"payload_not_available"`
+	Device                 DiscoveryDevice `json:"device"`
+}
+
+// DiscoveryEntity is a Home Assistant entity with the topic its config is published to
+type DiscoveryEntity struct {
+	Component string
+	Topic     string // Discovery config topic
+	Config    DiscoveryConfig
+}
+
+// DiscoverySource is an audio source that gets its own entities
+type DiscoverySource struct {
+	ID          string
+	DisplayName string
+	IsStream    bool     // true for RTSP streams, which get a stream health entity
+	SoundBands  []string // Octave band keys of sound level sensors, empty without sound level monitoring
+}
+
+// DiscoverySpecies is a species detected today, which gets its own detection count entity
+type DiscoverySpecies struct {
+	Key  string // Key of the species in the detection counts, see SpeciesKey
+	Name string // Common name, or the scientific name when unknown
+}
+
+// HomeAssistantDiscovery builds the Home Assistant discovery configs of a BirdNET-Go node
+// and the topics their state is published to
+type HomeAssistantDiscovery struct {
+	Prefix    string // Discovery prefix configured in Home Assistant
+	NodeID    string // Node name sanitized for topics and unique IDs
+	BaseTopic string // Topic detections are published to, states are published below it
+	Device    DiscoveryDevice
+}
+
+// NewHomeAssistantDiscovery creates the discovery builder for the configured node
+func NewHomeAssistantDiscovery(settings *conf.Settings) *HomeAssistantDiscovery {
+	nodeID := sanitizeDiscoveryID(settings.Main.Name)
+	if nodeID == "" {
+		nodeID = "birdnet_go"
+	}
+	name := "BirdNET-Go"
+	if settings.Main.Name != "" {
+		name += " " + settings.Main.Name
+	}
+	return &HomeAssistantDiscovery{
+		Prefix:    strings.TrimSuffix(settings.Realtime.MQTT.HomeAssistant.DiscoveryPrefix, "/"),
+		NodeID:    nodeID,
+		BaseTopic: strings.TrimSuffix(settings.Realtime.MQTT.Topic, "/"),
+		Device: DiscoveryDevice{
+			Identifiers:  []string{"birdnet-go_" + nodeID},
+			Name:         name,
+			Manufacturer: "BirdNET-Go",
+			Model:        "BirdNET-Go",
+			SWVersion:    settings.Version,
+		},
+	}
+}
+
+// AvailabilityTopic returns the availability topic of the node
+func (d *HomeAssistantDiscovery) AvailabilityTopic() string {
+	return AvailabilityTopic(d.BaseTopic)
+}
+
+// StatsTopic returns the topic of the detection counts of today
+func (d *HomeAssistantDiscovery) StatsTopic() string {
+	return d.BaseTopic + "/stats"
+}
+
+// SystemTopic returns the topic of the CPU, memory and disk state
+func (d *HomeAssistantDiscovery) SystemTopic() string {
+	return d.BaseTopic + "/system"
+}
+
+// SoundLevelTopic returns the topic of the sound levels of a source
+func (d *HomeAssistantDiscovery) SoundLevelTopic(sourceID string) string {
+	return d.BaseTopic + "/soundlevel/" + sanitizeDiscoveryID(sourceID)
+}
+
+// StreamHealthTopic returns the topic of the health of a stream
+func (d *HomeAssistantDiscovery) StreamHealthTopic(sourceID string) string {
+	return d.BaseTopic + "/health/" + sanitizeDiscoveryID(sourceID)
+}
+
+// ConfigTopicFilter returns the topic filter matching the discovery configs of all
+// entities of the node
+func (d *HomeAssistantDiscovery) ConfigTopicFilter() string {
+	return fmt.Sprintf("%s/+/%s/+/config", d.Prefix, d.NodeID)
+}
+
+// NodeEntities returns the entities of the node: the last detected species, the
+// detection and species counts of today, and CPU, memory and disk usage of every
+// monitored disk path
+func (d *HomeAssistantDiscovery) NodeEntities(diskPaths []string) []DiscoveryEntity {
+	entities := []DiscoveryEntity{
+		d.entity(ComponentSensor, "last_species", DiscoveryConfig{
+			Name:                   "Last detected species",
+			StateTopic:             d.BaseTopic,
+			ValueTemplate:          "{{ value_json.CommonName }}",
+			JSONAttributesTopic:    d.BaseTopic,
+			JSONAttributesTemplate: `{{ {"scientific_name": value_json.ScientificName, "confidence": value_json.Confidence, "date": value_json.Date, "time": value_json.Time, "source": value_json.Source.displayName} | tojson }}`,
+			Icon:                   "mdi:bird",
+		}),
+		d.entity(ComponentSensor, "detections_today", DiscoveryConfig{
+			Name:                   "Detections today",
+			StateTopic:             d.StatsTopic(),
+			ValueTemplate:          "{{ value_json.detections }}",
+			JSONAttributesTopic:    d.StatsTopic(),
+			JSONAttributesTemplate: "{{ value_json.species | tojson }}",
+			UnitOfMeasurement:      "detections",
+			StateClass:             "total",
+			Icon:                   "mdi:counter",
+		}),
+		d.entity(ComponentSensor, "species_today", DiscoveryConfig{
+			Name:              "Species today",
+			StateTopic:        d.StatsTopic(),
+			ValueTemplate:     "{{ value_json.species | count }}",
+			UnitOfMeasurement: "species",
+			StateClass:        "measurement",
+			Icon:              "mdi:feather",
+		}),
+		d.entity(ComponentSensor, "cpu_usage", d.systemConfig("CPU usage", "cpu", "mdi:cpu-64-bit")),
+		d.entity(ComponentSensor, "memory_usage", d.systemConfig("Memory usage", "memory", "mdi:memory")),
+	}
+
+	for _, path := range diskPaths {
+		key := DiskKey(path)
+		entities = append(entities, d.entity(ComponentSensor, "disk_usage_"+key,
+			d.systemConfig("Disk usage "+path, "disk."+key, "mdi:harddisk")))
+	}
+	return entities
+}
+
+// SpeciesEntities returns the detection count entities of the species detected today.
+// The counts are read from the stats topic, the entities of species not detected on
+// the next day are removed by the publisher.
+func (d *HomeAssistantDiscovery) SpeciesEntities(species []DiscoverySpecies) []DiscoveryEntity {
+	entities := make([]DiscoveryEntity, 0, len(species))
+	for _, sp := range species {
+		entities = append(entities, d.entity(ComponentSensor, sp.Key+"_today", DiscoveryConfig{
+			Name:              sp.Name + " today",
+			StateTopic:        d.StatsTopic(),
+			ValueTemplate:     fmt.Sprintf("{{ value_json.counts.get(%q, 0) }}", sp.Key),
+			UnitOfMeasurement: "detections",
+			StateClass:        "total",
+			Icon:              "mdi:bird",
+		}))
+	}
+	return entities
+}
+
+// systemConfig returns the config of a usage percentage published to the system topic
+func (d *HomeAssistantDiscovery) systemConfig(name, field, icon string) DiscoveryConfig {
+	return DiscoveryConfig{
+		Name:              name,
+		StateTopic:        d.SystemTopic(),
+		ValueTemplate:     "{{ value_json." + field + " }}",
+		UnitOfMeasurement: "%",
+		StateClass:        "measurement",
+		EntityCategory:    "diagnostic",
+		Icon:              icon,
+	}
+}
+
+// SourceEntities returns the stream health and octave band sound level entities of a source
+func (d *HomeAssistantDiscovery) SourceEntities(source *DiscoverySource) []DiscoveryEntity {
+	sourceKey := sanitizeDiscoveryID(source.ID)
+	name := source.DisplayName
+	if name == "" {
+		name = source.ID
+	}
+
+	var entities []DiscoveryEntity
+	if source.IsStream {
+		healthTopic := d.StreamHealthTopic(source.ID)
+		entities = append(entities, d.entity(ComponentBinarySensor, sourceKey+"_stream", DiscoveryConfig{
+			Name:                name + " stream",
+			StateTopic:          healthTopic,
+			ValueTemplate:       "{{ 'ON' if value_json.healthy else 'OFF' }}",
+			JSONAttributesTopic: healthTopic,
+			DeviceClass:         "connectivity",
+			EntityCategory:      "diagnostic",
+		}))
+	}
+
+	soundTopic := d.SoundLevelTopic(source.ID)
+	for _, band := range source.SoundBands {
+		entities = append(entities, d.entity(ComponentSensor, sourceKey+"_sound_level_"+sanitizeDiscoveryID(band), DiscoveryConfig{
+			Name:              fmt.Sprintf("%s sound level %s", name, strings.ReplaceAll(band, "_", " ")),
+			StateTopic:        soundTopic,
+			ValueTemplate:     fmt.Sprintf("{{ value_json.b[%q].m }}", band),
+			UnitOfMeasurement: "dB",
+			DeviceClass:       "sound_pressure",
+			StateClass:        "measurement",
+		}))
+	}
+	return entities
+}
+
+// entity completes the config of an entity with its IDs, device and availability
+func (d *HomeAssistantDiscovery) entity(component, objectID string, config DiscoveryConfig) DiscoveryEntity {
+	config.ObjectID = d.NodeID + "_" + objectID
+	config.UniqueID = "birdnet-go_" + config.ObjectID
+	config.AvailabilityTopic = d.AvailabilityTopic()
+	config.PayloadAvailable = AvailabilityOnline
+	config.PayloadNotAvailable = AvailabilityOffline
+	config.Device = d.Device
+	return DiscoveryEntity{
+		Component: component,
+		Topic:     fmt.Sprintf("%s/%s/%s/%s/config", d.Prefix, component, d.NodeID, objectID),
+		Config:    config,
+	}
+}
+
+// PublishEntities publishes the retained discovery configs of the entities
+func PublishEntities(ctx context.Context, client Client, entities []DiscoveryEntity) error {
+	for i := range entities {
+		payload, err := json.Marshal(entities[i].Config)
+		if err != nil {
+			return errors.New(err).
+				Component("mqtt").
+				Category(errors.CategoryMQTTPublish).
+				Context("operation", "marshal_discovery_config").
+				Context("topic", entities[i].Topic).
+				Build()
+		}
+		if err := client.PublishRetained(ctx, entities[i].Topic, string(payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveEntities removes entities from Home Assistant by replacing their retained
+// discovery configs with empty payloads
+func RemoveEntities(ctx context.Context, client Client, topics []string) error {
+	for _, topic := range topics {
+		if err := client.PublishRetained(ctx, topic, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiskKey returns the key of a disk path in the system state, e.g. "root" for "/"
+func DiskKey(path string) string {
+	if key := sanitizeDiscoveryID(path); key != "" {
+		return key
+	}
+	return "root"
+}
+
+// SpeciesKey returns the key of a species in the detection counts and in the ID of its
+// entity, the species code when known, e.g. "eurbla", else the scientific name
+func SpeciesKey(speciesCode, scientificName string) string {
+	if key := sanitizeDiscoveryID(speciesCode); key != "" {
+		return key
+	}
+	return sanitizeDiscoveryID(scientificName)
+}
+
+// sanitizeDiscoveryID lowercases an ID and replaces characters not allowed in
+// discovery topics and object IDs with underscores
+func sanitizeDiscoveryID(id string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(id) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
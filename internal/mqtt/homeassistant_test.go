@@ -0,0 +1,142 @@
+// homeassistant_test.go: Tests for the Home Assistant discovery configs
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// retainedRecorder records retained publishes
+type retainedRecorder struct {
+	Client
+	retained map[string]string
+}
+
+func (r *retainedRecorder) PublishRetained(_ context.Context, topic, payload string) error {
+	r.retained[topic] = payload
+	return nil
+}
+
+func newDiscoveryTestSettings() *conf.Settings {
+	settings := &conf.Settings{Version: "1.2.3"}
+	settings.Main.Name = "Garden Node"
+	settings.Realtime.MQTT.Topic = "birdnet/"
+	settings.Realtime.MQTT.HomeAssistant.DiscoveryPrefix = "homeassistant"
+	return settings
+}
+
+func TestHomeAssistantNodeEntities(t *testing.T) {
+	t.Parallel()
+
+	discovery := NewHomeAssistantDiscovery(newDiscoveryTestSettings())
+	assert.Equal(t, "garden_node", discovery.NodeID)
+	assert.Equal(t, "birdnet/status", discovery.AvailabilityTopic())
+
+	entities := discovery.NodeEntities([]string{"/", "/data/clips"})
+	topics := make(map[string]DiscoveryConfig, len(entities))
+	for _, entity := range entities {
+		topics[entity.Topic] = entity.Config
+	}
+
+	last, ok := topics["homeassistant/sensor/garden_node/last_species/config"]
+	require.True(t, ok, "last species sensor is published")
+	assert.Equal(t, "birdnet", last.StateTopic, "the last species is read from detections")
+	assert.Equal(t, "birdnet-go_garden_node_last_species", last.UniqueID)
+	assert.Equal(t, "birdnet/status", last.AvailabilityTopic)
+	assert.Equal(t, AvailabilityOnline, last.PayloadAvailable)
+	assert.Equal(t, AvailabilityOffline, last.PayloadNotAvailable)
+	assert.Equal(t, "BirdNET-Go Garden Node", last.Device.Name)
+	assert.Equal(t, "1.2.3", last.Device.SWVersion)
+
+	detections := topics["homeassistant/sensor/garden_node/detections_today/config"]
+	assert.Equal(t, "birdnet/stats", detections.StateTopic)
+	assert.Equal(t, "birdnet/stats", detections.JSONAttributesTopic, "per-species counts are attributes")
+
+	root, ok := topics["homeassistant/sensor/garden_node/disk_usage_root/config"]
+	require.True(t, ok, "disk usage sensor of the root path")
+	assert.Equal(t, "{{ value_json.disk.root }}", root.ValueTemplate)
+	_, ok = topics["homeassistant/sensor/garden_node/disk_usage_data_clips/config"]
+	assert.True(t, ok, "disk usage sensor of every monitored path")
+	_, ok = topics["homeassistant/sensor/garden_node/cpu_usage/config"]
+	assert.True(t, ok)
+}
+
+func TestHomeAssistantSpeciesEntities(t *testing.T) {
+	t.Parallel()
+
+	discovery := NewHomeAssistantDiscovery(newDiscoveryTestSettings())
+	entities := discovery.SpeciesEntities([]DiscoverySpecies{
+		{Key: SpeciesKey("eurbla", "Turdus merula"), Name: "Eurasian Blackbird"},
+		{Key: SpeciesKey("", "Strix aluco"), Name: "Strix aluco"},
+	})
+	require.Len(t, entities, 2)
+
+	assert.Equal(t, "homeassistant/sensor/garden_node/eurbla_today/config", entities[0].Topic)
+	assert.Equal(t, "garden_node_eurbla_today", entities[0].Config.ObjectID)
+	assert.Equal(t, "Eurasian Blackbird today", entities[0].Config.Name)
+	assert.Equal(t, "birdnet/stats", entities[0].Config.StateTopic)
+	assert.Equal(t, `{{ value_json.counts.get("eurbla", 0) }}`, entities[0].Config.ValueTemplate)
+	assert.Equal(t, "birdnet/status", entities[0].Config.AvailabilityTopic)
+
+	assert.Equal(t, "homeassistant/sensor/garden_node/strix_aluco_today/config", entities[1].Topic,
+		"species without a code are keyed by scientific name")
+	assert.Empty(t, discovery.SpeciesEntities(nil))
+}
+
+func TestHomeAssistantSourceEntities(t *testing.T) {
+	t.Parallel()
+
+	discovery := NewHomeAssistantDiscovery(newDiscoveryTestSettings())
+
+	stream := discovery.SourceEntities(&DiscoverySource{
+		ID:          "garden",
+		DisplayName: "Garden Camera",
+		IsStream:    true,
+		SoundBands:  []string{"25.0_Hz", "1.0_kHz"},
+	})
+	require.Len(t, stream, 3)
+	assert.Equal(t, "homeassistant/binary_sensor/garden_node/garden_stream/config", stream[0].Topic)
+	assert.Equal(t, "connectivity", stream[0].Config.DeviceClass)
+	assert.Equal(t, "birdnet/health/garden", stream[0].Config.StateTopic)
+	assert.Equal(t, "homeassistant/sensor/garden_node/garden_sound_level_25_0_hz/config", stream[1].Topic)
+	assert.Equal(t, "birdnet/soundlevel/garden", stream[1].Config.StateTopic)
+	assert.Equal(t, `{{ value_json.b["25.0_Hz"].m }}`, stream[1].Config.ValueTemplate)
+	assert.Equal(t, "Garden Camera sound level 1.0 kHz", stream[2].Config.Name)
+
+	card := discovery.SourceEntities(&DiscoverySource{ID: "audio_card_default"})
+	assert.Empty(t, card, "sound cards without sound level monitoring have no entities")
+}
+
+func TestHomeAssistantPublishAndRemove(t *testing.T) {
+	t.Parallel()
+
+	discovery := NewHomeAssistantDiscovery(newDiscoveryTestSettings())
+	entities := discovery.SourceEntities(&DiscoverySource{ID: "garden", IsStream: true})
+	recorder := &retainedRecorder{retained: make(map[string]string)}
+
+	require.NoError(t, PublishEntities(t.Context(), recorder, entities))
+	payload, ok := recorder.retained[entities[0].Topic]
+	require.True(t, ok)
+	var config map[string]any
+	require.NoError(t, json.Unmarshal([]byte(payload), &config))
+	assert.Equal(t, "birdnet-go_garden_node_garden_stream", config["unique_id"])
+	assert.NotContains(t, config, "unit_of_measurement", "empty optional fields are omitted")
+
+	require.NoError(t, RemoveEntities(t.Context(), recorder, []string{entities[0].Topic}))
+	assert.Empty(t, recorder.retained[entities[0].Topic], "entities are removed with an empty retained config")
+}
+
+func TestAvailabilityTopicAndDiskKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "birdnet/status", AvailabilityTopic("birdnet"))
+	assert.Equal(t, "birdnet/status", AvailabilityTopic("birdnet/"))
+	assert.Equal(t, "root", DiskKey("/"))
+	assert.Equal(t, "mnt_usb", DiskKey("/mnt/usb"))
+	assert.Equal(t, "c", DiskKey(`C:\`))
+}
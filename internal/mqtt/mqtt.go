@@ -54,6 +54,11 @@ type Client interface {
 	// It returns an error if the publish operation fails.
 	Publish(ctx context.Context, topic string, payload string) error
 
+	// PublishRetained sends a message the broker retains for new subscribers,
+	// regardless of the configured retain setting. Used for discovery configs
+	// and state that must survive restarts of the subscriber.
+	PublishRetained(ctx context.Context, topic string, payload string) error
+
 	// IsConnected returns true if the client is currently connected to the MQTT broker.
 	IsConnected() bool
 
@@ -78,6 +83,9 @@ type Config struct {
 	Password          string
 	Topic             string // Default topic for publishing messages
 	Retain            bool   // true to retain messages at the broker
+	AvailabilityTopic string // Topic of the retained online/offline availability and will message, empty to disable
+	CommandTopic      string // Topic of remote control commands, empty to disable the subscription
+	CommandToken      string // Shared secret commands must include
 	ReconnectCooldown time.Duration
 	ReconnectDelay    time.Duration
 	// Connection timeouts
@@ -0,0 +1,145 @@
+// subscriber.go: Subscriptions to topics of other publishers on the configured broker
+package mqtt
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// MessageHandler handles a message received on a subscribed topic. It is called
+// sequentially from the connection goroutine and must not block.
+type MessageHandler func(topic string, payload []byte)
+
+// Subscription subscribes to a fixed set of topics on the broker of realtime.mqtt.
+// It uses its own connection, so it works whether or not publishing is enabled, and
+// lets the paho client reconnect and resubscribe on connection loss.
+type Subscription struct {
+	config  Config
+	topics  []string
+	handler MessageHandler
+
+	mu     sync.Mutex
+	client mqtt.Client
+}
+
+// NewSubscription creates a subscription to topics with the broker settings of
+// realtime.mqtt. The client ID is the node name with clientIDSuffix appended so the
+// connection does not take over the connection of the publishing client.
+func NewSubscription(settings *conf.Settings, clientIDSuffix string, topics []string, handler MessageHandler) *Subscription {
+	config := connectionConfig(settings)
+	config.ClientID = settings.Main.Name + "-" + clientIDSuffix
+
+	return &Subscription{
+		config:  config,
+		topics:  slices.Clone(topics),
+		handler: handler,
+	}
+}
+
+// Start connects to the broker. Connection attempts continue in the background until
+// Stop is called, so Start only fails on invalid configuration or when ctx is done
+// before the connection attempt was made.
+func (s *Subscription) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		return nil
+	}
+
+	opts := mqtt.NewClientOptions()
+	opts.AddBroker(s.config.Broker)
+	opts.SetClientID(s.config.ClientID)
+	opts.SetUsername(s.config.Username)
+	opts.SetPassword(s.config.Password) // Do not log the password
+	opts.SetCleanSession(true)
+	opts.SetAutoReconnect(true)
+	opts.SetConnectRetry(true)
+	opts.SetKeepAlive(KeepAliveInterval)
+	opts.SetPingTimeout(PingTimeout)
+	opts.SetWriteTimeout(WriteTimeout)
+	opts.SetConnectTimeout(s.config.ConnectTimeout)
+	opts.SetOnConnectHandler(s.onConnect)
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		GetLogger().Warn("MQTT subscription connection lost",
+			logger.String("client_id", s.config.ClientID),
+			logger.Error(err))
+	})
+
+	if s.config.TLS.Enabled {
+		// The TLS helpers of the publishing client only read the connection config
+		tlsConfig, err := (&client{config: s.config}).createTLSConfig()
+		if err != nil {
+			return errors.New(err).
+				Component("mqtt").
+				Category(errors.CategoryConfiguration).
+				Context("broker", s.config.Broker).
+				Context("client_id", s.config.ClientID).
+				Context("operation", "create_tls_config").
+				Build()
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// With connect retry the token completes once connected, do not wait for it
+	s.client = mqtt.NewClient(opts)
+	s.client.Connect()
+
+	GetLogger().Info("MQTT subscription started",
+		logger.String("broker", s.config.Broker),
+		logger.String("client_id", s.config.ClientID),
+		logger.Int("topics", len(s.topics)))
+	return nil
+}
+
+// Stop disconnects from the broker and stops connection attempts
+func (s *Subscription) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return
+	}
+	s.client.Disconnect(durationToMillisUint(s.config.DisconnectTimeout))
+	s.client = nil
+}
+
+// onConnect subscribes to the topics on every connect, the broker does not keep
+// subscriptions of clean sessions. The connect handler must not block, so the
+// subscription result is awaited separately.
+func (s *Subscription) onConnect(client mqtt.Client) {
+	filters := make(map[string]byte, len(s.topics))
+	for _, topic := range s.topics {
+		filters[topic] = defaultQoS
+	}
+
+	token := client.SubscribeMultiple(filters, func(_ mqtt.Client, msg mqtt.Message) {
+		s.handler(msg.Topic(), msg.Payload())
+	})
+	go func() {
+		if !token.WaitTimeout(s.config.PublishTimeout) {
+			GetLogger().Warn("Timed out subscribing to MQTT topics",
+				logger.String("client_id", s.config.ClientID))
+			return
+		}
+		if err := token.Error(); err != nil {
+			GetLogger().Error("Failed to subscribe to MQTT topics",
+				logger.String("client_id", s.config.ClientID),
+				logger.Error(err))
+			return
+		}
+		GetLogger().Info("Subscribed to MQTT topics",
+			logger.String("client_id", s.config.ClientID),
+			logger.Int("topics", len(s.topics)))
+	}()
+}
@@ -0,0 +1,30 @@
+// subscriber_test.go: Tests for subscriptions to topics of other publishers
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestNewSubscriptionUsesBrokerSettings(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Main.Name = "garden"
+	settings.Realtime.MQTT.Broker = "mqtts://broker.local:8883"
+	settings.Realtime.MQTT.Username = "birdnet"
+	settings.Realtime.MQTT.Password = "secret"
+
+	topics := []string{"weather/temperature"}
+	subscription := NewSubscription(settings, "weather", topics, func(string, []byte) {})
+	topics[0] = "modified"
+
+	assert.Equal(t, "garden-weather", subscription.config.ClientID, "the publishing client connection is not taken over")
+	assert.Equal(t, "birdnet", subscription.config.Username)
+	assert.True(t, subscription.config.TLS.Enabled, "TLS is enabled from the broker URL scheme")
+	assert.Equal(t, []string{"weather/temperature"}, subscription.topics)
+
+	subscription.Stop() // Stopping a subscription which was not started is a no-op
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/smallnest/ringbuffer"
@@ -34,6 +35,7 @@ var (
 	analysisMetricsMutex sync.RWMutex            // Mutex for thread-safe access to analysisMetrics
 	analysisMetricsOnce  sync.Once               // Ensures metrics are only set once
 	readBufferPool       *BufferPool             // Global buffer pool for read operations
+	analysisPaused       atomic.Bool             // true while analysis is paused, audio is captured but not analyzed
 )
 
 // init initializes the warningCounter map
@@ -615,6 +617,22 @@ func AnalysisBufferExists(sourceID string) bool {
 	return exists
 }
 
+// PauseAnalysis pauses BirdNET analysis of all sources. Audio capture continues and
+// buffered audio is discarded, so analysis resumes with current audio.
+func PauseAnalysis() {
+	analysisPaused.Store(true)
+}
+
+// ResumeAnalysis resumes BirdNET analysis after PauseAnalysis
+func ResumeAnalysis() {
+	analysisPaused.Store(false)
+}
+
+// IsAnalysisPaused returns true while BirdNET analysis is paused
+func IsAnalysisPaused() bool {
+	return analysisPaused.Load()
+}
+
 // AnalysisBufferMonitor monitors the buffer and processes audio data when enough data is present.
 func AnalysisBufferMonitor(wg *sync.WaitGroup, bn *birdnet.BirdNET, quitChan chan struct{}, sourceID string) {
 	log := GetLogger()
@@ -653,6 +671,14 @@ func AnalysisBufferMonitor(wg *sync.WaitGroup, bn *birdnet.BirdNET, quitChan cha
 				continue
 			}
 
+			// Audio read while analysis is paused is dropped
+			if len(data) == conf.BufferSize && IsAnalysisPaused() {
+				if m := getAnalysisMetrics(); m != nil {
+					m.RecordAnalysisBufferPoll(sourceID, "paused")
+				}
+				continue
+			}
+
 			// if buffer has 3 seconds of data, process it
 			if len(data) == conf.BufferSize {
 				if m := getAnalysisMetrics(); m != nil {
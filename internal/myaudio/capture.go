@@ -283,17 +283,16 @@ func initializeBuffersForSource(sourceID string) error {
 }
 
 func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, unifiedAudioChan chan UnifiedAudioData) {
-	// If no RTSP URLs and no audio device configured, return early
-	if len(settings.Realtime.RTSP.URLs) == 0 && settings.Realtime.Audio.Source == "" {
+	// If no RTSP streams and no audio device configured, return early
+	rtspStreams := settings.Realtime.RTSP.ActiveStreams()
+	if len(rtspStreams) == 0 && settings.Realtime.Audio.Source == "" {
 		return
 	}
 
 	// Initialize RTSP sources - the FFmpegManager will handle buffer initialization
-	if len(settings.Realtime.RTSP.URLs) > 0 {
-		for _, url := range settings.Realtime.RTSP.URLs {
-			// CaptureAudioRTSP delegates to FFmpegManager which handles everything
-			go CaptureAudioRTSP(url, settings.Realtime.RTSP.Transport, wg, quitChan, restartChan, unifiedAudioChan)
-		}
+	for _, stream := range rtspStreams {
+		// CaptureAudioRTSP delegates to FFmpegManager which handles everything
+		go CaptureAudioRTSP(stream.URL, stream.Transport, wg, quitChan, restartChan, unifiedAudioChan)
 	}
 
 	// Handle sound card source if configured
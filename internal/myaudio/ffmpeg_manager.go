@@ -161,6 +161,8 @@ func (m *FFmpegManager) StopStream(url string) error {
 	// Stop the stream and remove from map while holding lock
 	stream.Stop()
 	delete(m.streams, url)
+	// Buffers are keyed by the source ID, not the raw URL
+	sourceID := stream.source.ID
 
 	// Unregister sound level processor while holding lock
 	UnregisterSoundLevelProcessor(url)
@@ -183,14 +185,14 @@ func (m *FFmpegManager) StopStream(url string) error {
 	// This sleep is now safe for synctest since mutex is released
 	time.Sleep(100 * time.Millisecond)
 
-	if err := RemoveAnalysisBuffer(url); err != nil {
+	if err := RemoveAnalysisBuffer(sourceID); err != nil {
 		getManagerLogger().Warn("failed to remove analysis buffer",
 			logger.String("url", privacy.SanitizeRTSPUrl(url)),
 			logger.Error(err),
 			logger.String("operation", "stop_stream_buffer_cleanup"))
 	}
 
-	if err := RemoveCaptureBuffer(url); err != nil {
+	if err := RemoveCaptureBuffer(sourceID); err != nil {
 		getManagerLogger().Warn("failed to remove capture buffer",
 			logger.String("url", privacy.SanitizeRTSPUrl(url)),
 			logger.Error(err),
@@ -268,42 +270,48 @@ func (m *FFmpegManager) HealthCheck() map[string]StreamHealth {
 func (m *FFmpegManager) SyncWithConfig(audioChan chan UnifiedAudioData) error {
 	settings := conf.Setting()
 	configuredURLs := make(map[string]string) // url -> transport
+	configuredStreams := make(map[string]conf.RTSPStreamSettings)
 
 	// Build map of configured URLs with their transport settings
-	for _, url := range settings.Realtime.RTSP.URLs {
-		configuredURLs[url] = settings.Realtime.RTSP.Transport
+	for _, stream := range settings.Realtime.RTSP.ActiveStreams() {
+		configuredURLs[stream.URL] = stream.Transport
+		configuredStreams[stream.URL] = stream
 	}
 
-	// Check for transport changes in existing streams
+	// Check for transport and stream setting changes in existing streams
 	// This must be done before stopping unconfigured streams to detect the change
 	m.streamsMu.RLock()
 	var toRestart []struct {
 		url          string
 		oldTransport string
 		newTransport string
+		reason       string
 	}
 	for url, stream := range m.streams {
 		if configTransport, configured := configuredURLs[url]; configured {
-			// Check if transport setting has changed for this stream
-			if stream.transport != configTransport {
+			// Check if transport, name or audio filter settings have changed for this stream
+			streamConfig := configuredStreams[url]
+			if reason := stream.settingsChange(configTransport, &streamConfig); reason != "" {
 				toRestart = append(toRestart, struct {
 					url          string
 					oldTransport string
 					newTransport string
+					reason       string
 				}{
 					url:          url,
 					oldTransport: stream.transport,
 					newTransport: configTransport,
+					reason:       reason,
 				})
 			}
 		}
 	}
 	m.streamsMu.RUnlock()
 
-	// Restart streams with changed transport settings
+	// Restart streams with changed settings
 	// This is done before stopping unconfigured streams to provide clear log ordering
 	for _, change := range toRestart {
-		getManagerLogger().Info("transport setting changed, restarting stream",
+		getManagerLogger().Info(change.reason+" setting changed, restarting stream",
 			logger.String("url", privacy.SanitizeRTSPUrl(change.url)),
 			logger.String("old_transport", change.oldTransport),
 			logger.String("new_transport", change.newTransport),
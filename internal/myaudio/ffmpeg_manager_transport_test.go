@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
 )
 
 // TestFFmpegManager_TransportFieldAccessible verifies that the transport field
@@ -229,6 +230,71 @@ func TestFFmpegManager_StopStreamRemovesFromMap(t *testing.T) {
 	manager.streamsMu.RUnlock()
 }
 
+// TestFFmpegManager_SyncWithConfigAppliesStreamID verifies that naming a stream whose
+// URL is already registered restarts it under the configured ID, and that the
+// restart is not repeated on the next sync, even while the source is still referenced.
+func TestFFmpegManager_SyncWithConfigAppliesStreamID(t *testing.T) {
+	// Do not use t.Parallel() - this test replaces the global settings
+
+	originalSettings := conf.GetTestSettings()
+	testSettings := *originalSettings
+	testURL := "rtsp://test.local/stream_id_change"
+	testSettings.Realtime.RTSP.Transport = "tcp"
+	testSettings.Realtime.RTSP.URLs = []string{testURL}
+	conf.SetTestSettings(&testSettings)
+	defer conf.SetTestSettings(originalSettings)
+
+	audioChan := make(chan UnifiedAudioData, 100)
+	defer close(audioChan)
+
+	manager := NewFFmpegManager()
+	defer manager.Shutdown()
+
+	// Step 1: The legacy URL starts with a generated source ID
+	require.NoError(t, manager.SyncWithConfig(audioChan))
+	manager.streamsMu.RLock()
+	legacyStream, exists := manager.streams[testURL]
+	manager.streamsMu.RUnlock()
+	require.True(t, exists, "legacy stream should be started")
+	legacyID := legacyStream.source.ID
+	require.NotEqual(t, "stream_id_change_cam", legacyID)
+
+	// Another consumer keeps the source registered while the stream restarts
+	GetRegistry().AcquireSourceReference(legacyID)
+
+	// Step 2: The URL becomes a named stream
+	namedSettings := testSettings
+	namedSettings.Realtime.RTSP.URLs = nil
+	namedSettings.Realtime.RTSP.Streams = []conf.RTSPStreamSettings{
+		{ID: "stream_id_change_cam", Name: "Porch", URL: testURL},
+	}
+	conf.SetTestSettings(&namedSettings)
+
+	require.NoError(t, manager.SyncWithConfig(audioChan))
+	manager.streamsMu.RLock()
+	namedStream, exists := manager.streams[testURL]
+	manager.streamsMu.RUnlock()
+	require.True(t, exists, "named stream should be running")
+	assert.NotSame(t, legacyStream, namedStream, "the stream should be restarted")
+	assert.Equal(t, "stream_id_change_cam", namedStream.source.ID)
+	assert.Equal(t, "Porch", namedStream.source.DisplayName)
+	assert.Equal(t, legacyID, legacyStream.source.ID, "the stopped stream keeps its old ID")
+
+	registered, ok := GetRegistry().GetSourceByConnection(testURL)
+	require.True(t, ok)
+	assert.Equal(t, "stream_id_change_cam", registered.ID)
+	_, ok = GetRegistry().GetSourceByID(legacyID)
+	assert.False(t, ok, "the old ID should no longer be registered")
+
+	// Step 3: Syncing again leaves the stream alone
+	require.NoError(t, manager.SyncWithConfig(audioChan))
+	manager.streamsMu.RLock()
+	current := manager.streams[testURL]
+	manager.streamsMu.RUnlock()
+	assert.Same(t, namedStream, current, "an applied ID should not restart the stream again")
+	_ = GetRegistry().ReleaseSourceReference("stream_id_change_cam")
+}
+
 // Note: Full integration testing of SyncWithConfig with actual configuration changes
 // is not included here because SyncWithConfig directly reads from the global
 // conf.Setting() singleton, which would require complex test setup with temporary
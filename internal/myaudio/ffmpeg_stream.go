@@ -308,9 +308,10 @@ type StreamHealth struct {
 // FFmpegStream manages a single FFmpeg process for audio streaming.
 // It handles process lifecycle, health monitoring, data tracking, and automatic recovery.
 type FFmpegStream struct {
-	source    *AudioSource
-	transport string
-	audioChan chan UnifiedAudioData
+	source      *AudioSource
+	transport   string
+	audioFilter string // FFmpeg audio filter graph from the stream gain and equalizer
+	audioChan   chan UnifiedAudioData
 
 	// Process management
 	cmd      *exec.Cmd
@@ -415,7 +416,11 @@ func generateUniqueFallbackID() string {
 // NewFFmpegStream creates a new FFmpeg stream handler.
 // The url parameter specifies the RTSP stream URL, transport specifies the RTSP transport protocol,
 // and audioChan is the channel where processed audio data will be sent.
+// Streams configured by name are registered with their configured ID and display name,
+// and get the gain and equalizer of their configuration.
 func NewFFmpegStream(url, transport string, audioChan chan UnifiedAudioData) *FFmpegStream {
+	streamConfig, _ := conf.Setting().Realtime.RTSP.StreamByURL(url)
+
 	// Register or get existing source from registry
 	registry := GetRegistry()
 	var source *AudioSource
@@ -436,7 +441,10 @@ func NewFFmpegStream(url, transport string, audioChan chan UnifiedAudioData) *FF
 			IsActive:         true,
 		}
 	} else {
-		source = registry.GetOrCreateSource(url, SourceTypeRTSP)
+		if streamConfig.URL == "" {
+			streamConfig.URL = url
+		}
+		source = registry.GetOrCreateRTSPSource(&streamConfig)
 		if source == nil {
 			getStreamLogger().Error("failed to register RTSP source",
 				logger.String("url", privacy.SanitizeRTSPUrl(url)),
@@ -458,6 +466,7 @@ func NewFFmpegStream(url, transport string, audioChan chan UnifiedAudioData) *FF
 	return &FFmpegStream{
 		source:                         source,
 		transport:                      transport,
+		audioFilter:                    rtspStreamAudioFilter(&streamConfig),
 		audioChan:                      audioChan,
 		restartChan:                    make(chan struct{}, 1),
 		stopChan:                       make(chan struct{}),
@@ -475,6 +484,24 @@ func NewFFmpegStream(url, transport string, audioChan chan UnifiedAudioData) *FF
 	}
 }
 
+// settingsChange returns which setting of the stream differs from its configuration,
+// or an empty string when the stream runs with its configured settings. The source ID,
+// display name and audio filter are fixed when the stream is created.
+func (s *FFmpegStream) settingsChange(transport string, streamConfig *conf.RTSPStreamSettings) string {
+	switch {
+	case s.transport != transport:
+		return "transport"
+	case streamConfig.ID != "" && s.source.ID != streamConfig.ID:
+		return "stream ID"
+	case streamConfig.ID != "" && s.source.DisplayName != streamConfig.DisplayName():
+		return "stream name"
+	case s.audioFilter != rtspStreamAudioFilter(streamConfig):
+		return "audio filter"
+	default:
+		return ""
+	}
+}
+
 // transitionState safely transitions the process state and logs the change.
 // It records the transition in history for debugging and emits structured logs.
 // This method is thread-safe and can be called from any goroutine.
@@ -837,11 +864,20 @@ func (s *FFmpegStream) startProcess() error {
 			Build()
 	}
 
-	// Add input and output parameters
+	// Add input parameters
 	args = append(args,
 		"-i", connStr,
 		"-loglevel", "error",
 		"-vn",
+	)
+
+	// Apply the gain and equalizer of the stream
+	if s.audioFilter != "" {
+		args = append(args, "-af", s.audioFilter)
+	}
+
+	// Add output parameters
+	args = append(args,
 		"-f", format,
 		"-ar", sampleRate,
 		"-ac", numChannels,
@@ -0,0 +1,136 @@
+// noise_indicators.go: Environmental noise indicators computed from sound level measurements
+package myaudio
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// Hours starting the day, evening and night periods of Lden, as defined by the
+// EU Environmental Noise Directive 2002/49/EC
+const (
+	DayStartHour     = 7
+	EveningStartHour = 19
+	NightStartHour   = 23
+
+	// Penalties added to the evening and night levels of Lden
+	eveningPenaltyDB = 5.0
+	nightPenaltyDB   = 10.0
+)
+
+// TimedLevel is an equivalent sound level measured over a duration
+type TimedLevel struct {
+	Time     time.Time // Start of the measurement
+	Level    float64   // Equivalent level in dB
+	Duration float64   // Measured seconds
+}
+
+// DayEveningNight holds the day, evening and night levels of a day and the Lden
+// combining them. Levels of periods without measurements are NaN.
+type DayEveningNight struct {
+	Lday     float64
+	Levening float64
+	Lnight   float64
+	Lden     float64
+}
+
+// AWeighting returns the A-weighting correction in dB at a frequency, as defined by IEC 61672-1
+func AWeighting(freq float64) float64 {
+	f2 := freq * freq
+	ra := (12194 * 12194 * f2 * f2) /
+		((f2 + 20.6*20.6) * math.Sqrt((f2+107.7*107.7)*(f2+737.9*737.9)) * (f2 + 12194*12194))
+	return 20*math.Log10(ra) + 2.0
+}
+
+// AWeightedLevel returns the A-weighted level of 1/3rd octave band levels, the energetic
+// sum of the A-weighted band means. It returns NaN when there are no bands.
+func AWeightedLevel(bands map[string]OctaveBandData) float64 {
+	if len(bands) == 0 {
+		return math.NaN()
+	}
+	var energy float64
+	for _, band := range bands {
+		energy += dbToEnergy(band.Mean + AWeighting(band.CenterFreq))
+	}
+	return energyToDB(energy)
+}
+
+// Leq returns the equivalent continuous level of the measurements, the energy average
+// weighted by their durations. It returns NaN when there are no measurements.
+func Leq(levels []TimedLevel) float64 {
+	var energy, duration float64
+	for _, l := range levels {
+		energy += dbToEnergy(l.Level) * l.Duration
+		duration += l.Duration
+	}
+	if duration <= 0 {
+		return math.NaN()
+	}
+	return energyToDB(energy / duration)
+}
+
+// PercentileLevel returns the level exceeded during n percent of the measured time, e.g.
+// L10 for the peaks of traffic noise or L90 for the background level. It returns NaN
+// when there are no measurements.
+func PercentileLevel(levels []TimedLevel, n float64) float64 {
+	sorted := slices.Clone(levels)
+	slices.SortFunc(sorted, func(a, b TimedLevel) int { return cmp.Compare(b.Level, a.Level) })
+
+	var total float64
+	for _, l := range sorted {
+		total += l.Duration
+	}
+	if total <= 0 {
+		return math.NaN()
+	}
+
+	// Step down from the loudest level until n percent of the time is covered
+	limit := total * n / 100
+	var covered float64
+	for _, l := range sorted {
+		covered += l.Duration
+		if covered >= limit {
+			return l.Level
+		}
+	}
+	return sorted[len(sorted)-1].Level
+}
+
+// ComputeDayEveningNight returns the day (07-19), evening (19-23) and night (23-07)
+// levels of the measurements of one calendar day and their Lden, with the hours taken
+// in the location of the measurement times. Lden is NaN unless all periods have
+// measurements.
+func ComputeDayEveningNight(levels []TimedLevel) DayEveningNight {
+	var day, evening, night []TimedLevel
+	for _, l := range levels {
+		switch hour := l.Time.Hour(); {
+		case hour >= DayStartHour && hour < EveningStartHour:
+			day = append(day, l)
+		case hour >= EveningStartHour && hour < NightStartHour:
+			evening = append(evening, l)
+		default:
+			night = append(night, l)
+		}
+	}
+
+	result := DayEveningNight{Lday: Leq(day), Levening: Leq(evening), Lnight: Leq(night)}
+	dayHours := float64(EveningStartHour - DayStartHour)
+	eveningHours := float64(NightStartHour - EveningStartHour)
+	nightHours := 24 - dayHours - eveningHours
+	result.Lden = energyToDB((dayHours*dbToEnergy(result.Lday) +
+		eveningHours*dbToEnergy(result.Levening+eveningPenaltyDB) +
+		nightHours*dbToEnergy(result.Lnight+nightPenaltyDB)) / 24)
+	return result
+}
+
+// dbToEnergy converts a level in dB to relative energy, NaN stays NaN
+func dbToEnergy(level float64) float64 {
+	return math.Pow(10, level/10)
+}
+
+// energyToDB converts relative energy to a level in dB
+func energyToDB(energy float64) float64 {
+	return 10 * math.Log10(energy)
+}
@@ -0,0 +1,86 @@
+package myaudio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAWeighting tests the A-weighting against the IEC 61672-1 table values
+func TestAWeighting(t *testing.T) {
+	tests := []struct {
+		freq float64
+		want float64
+	}{
+		{31.5, -39.4},
+		{100, -19.1},
+		{1000, 0.0},
+		{4000, 1.0},
+		{10000, -2.5},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, AWeighting(tt.freq), 0.2, "A-weighting at %.1f Hz", tt.freq)
+	}
+}
+
+// TestAWeightedLevel tests summing octave band levels into an A-weighted level
+func TestAWeightedLevel(t *testing.T) {
+	bands := map[string]OctaveBandData{
+		"1.0_kHz": {CenterFreq: 1000, Mean: -40},
+		"1.2_kHz": {CenterFreq: 1250, Mean: -40},
+	}
+	// Two equal bands add 3 dB, the A-weighting of 1.25 kHz is +0.6 dB
+	assert.InDelta(t, -36.7, AWeightedLevel(bands), 0.1)
+	assert.True(t, math.IsNaN(AWeightedLevel(nil)))
+}
+
+// TestLeq tests the duration weighted energy average
+func TestLeq(t *testing.T) {
+	levels := []TimedLevel{
+		{Level: 60, Duration: 60},
+		{Level: 50, Duration: 60},
+	}
+	assert.InDelta(t, 57.4, Leq(levels), 0.1, "the louder level dominates the energy average")
+
+	levels = append(levels, TimedLevel{Level: 50, Duration: 0})
+	assert.InDelta(t, 57.4, Leq(levels), 0.1, "measurements without duration do not count")
+
+	assert.True(t, math.IsNaN(Leq(nil)))
+}
+
+// TestPercentileLevel tests the levels exceeded during a percentage of the time
+func TestPercentileLevel(t *testing.T) {
+	levels := make([]TimedLevel, 0, 10)
+	for i := range 10 {
+		levels = append(levels, TimedLevel{Level: float64(40 + i), Duration: 60})
+	}
+
+	assert.InDelta(t, 49.0, PercentileLevel(levels, 10), 0.001, "L10 is exceeded 10% of the time")
+	assert.InDelta(t, 41.0, PercentileLevel(levels, 90), 0.001, "L90 is exceeded 90% of the time")
+	assert.InDelta(t, 40.0, PercentileLevel(levels, 100), 0.001)
+	assert.True(t, math.IsNaN(PercentileLevel(nil, 10)))
+}
+
+// TestComputeDayEveningNight tests the periods and penalties of Lden
+func TestComputeDayEveningNight(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	levels := make([]TimedLevel, 0, 24)
+	for hour := range 24 {
+		levels = append(levels, TimedLevel{Time: day.Add(time.Duration(hour) * time.Hour), Level: 50, Duration: 3600})
+	}
+
+	result := ComputeDayEveningNight(levels)
+	assert.InDelta(t, 50.0, result.Lday, 0.001)
+	assert.InDelta(t, 50.0, result.Levening, 0.001)
+	assert.InDelta(t, 50.0, result.Lnight, 0.001)
+	// A constant level gets 10*log10((12 + 4*10^0.5 + 8*10)/24) = 6.4 dB of penalties
+	assert.InDelta(t, 56.4, result.Lden, 0.1)
+
+	// Without night measurements Lden is undefined
+	result = ComputeDayEveningNight(levels[DayStartHour:NightStartHour])
+	assert.InDelta(t, 50.0, result.Lday, 0.001)
+	assert.True(t, math.IsNaN(result.Lnight))
+	assert.True(t, math.IsNaN(result.Lden))
+}
@@ -0,0 +1,74 @@
+package myaudio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// rtspStreamAudioFilter returns the FFmpeg audio filter graph applying the gain and
+// equalizer of an RTSP stream, or an empty string when the stream needs no filtering.
+// Sound card capture applies the equalizer in Go, RTSP streams let FFmpeg do it.
+func rtspStreamAudioFilter(stream *conf.RTSPStreamSettings) string {
+	var filters []string
+
+	if stream.Equalizer.Enabled {
+		for _, filter := range stream.Equalizer.Filters {
+			ffmpegFilter := ffmpegEqualizerFilter(filter)
+			if ffmpegFilter == "" {
+				continue
+			}
+			// Every pass adds the attenuation or gain of the filter once more
+			for range filter.Passes {
+				filters = append(filters, ffmpegFilter)
+			}
+		}
+	}
+
+	if stream.Gain != 0 {
+		filters = append(filters, fmt.Sprintf("volume=%sdB", formatFilterValue(stream.Gain)))
+	}
+
+	return strings.Join(filters, ",")
+}
+
+// ffmpegEqualizerFilter converts an equalizer filter to the equivalent FFmpeg audio
+// filter. Filters without passes are disabled and unknown types are skipped.
+func ffmpegEqualizerFilter(filter conf.EqualizerFilter) string {
+	if filter.Passes <= 0 {
+		return ""
+	}
+
+	frequency := formatFilterValue(filter.Frequency)
+	q := formatFilterValue(filter.Q)
+	width := formatFilterValue(filter.Width)
+	gain := formatFilterValue(filter.Gain)
+
+	switch filter.Type {
+	case "LowPass":
+		return "lowpass=f=" + frequency + ":t=q:w=" + q
+	case "HighPass":
+		return "highpass=f=" + frequency + ":t=q:w=" + q
+	case "AllPass":
+		return "allpass=f=" + frequency + ":t=q:w=" + q
+	case "BandPass":
+		return "bandpass=f=" + frequency + ":t=h:w=" + width
+	case "BandReject":
+		return "bandreject=f=" + frequency + ":t=h:w=" + width
+	case "LowShelf":
+		return "lowshelf=f=" + frequency + ":t=q:w=" + q + ":g=" + gain
+	case "HighShelf":
+		return "highshelf=f=" + frequency + ":t=q:w=" + q + ":g=" + gain
+	case "Peaking":
+		return "equalizer=f=" + frequency + ":t=h:w=" + width + ":g=" + gain
+	default:
+		return ""
+	}
+}
+
+// formatFilterValue formats a filter parameter without trailing zeros
+func formatFilterValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,107 @@
+package myaudio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestRTSPStreamAudioFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		stream conf.RTSPStreamSettings
+		want   string
+	}{
+		{
+			name:   "no gain or equalizer",
+			stream: conf.RTSPStreamSettings{ID: "garden"},
+			want:   "",
+		},
+		{
+			name:   "gain only",
+			stream: conf.RTSPStreamSettings{Gain: -3.5},
+			want:   "volume=-3.5dB",
+		},
+		{
+			name: "disabled equalizer is ignored",
+			stream: conf.RTSPStreamSettings{Equalizer: conf.EqualizerSettings{
+				Filters: []conf.EqualizerFilter{{Type: "HighPass", Frequency: 150, Q: 0.707, Passes: 1}},
+			}},
+			want: "",
+		},
+		{
+			name: "equalizer passes and gain",
+			stream: conf.RTSPStreamSettings{Gain: 6, Equalizer: conf.EqualizerSettings{
+				Enabled: true,
+				Filters: []conf.EqualizerFilter{
+					{Type: "HighPass", Frequency: 150, Q: 0.707, Passes: 2},
+					{Type: "BandReject", Frequency: 50, Width: 10, Passes: 1},
+					{Type: "LowPass", Frequency: 15000, Q: 0.707, Passes: 0},
+					{Type: "Unknown", Frequency: 1000, Passes: 1},
+					{Type: "Peaking", Frequency: 3000, Width: 500, Gain: -6, Passes: 1},
+				},
+			}},
+			want: "highpass=f=150:t=q:w=0.707,highpass=f=150:t=q:w=0.707," +
+				"bandreject=f=50:t=h:w=10,equalizer=f=3000:t=h:w=500:g=-6,volume=6dB",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, rtspStreamAudioFilter(&tt.stream))
+		})
+	}
+}
+
+func TestGetOrCreateRTSPSource(t *testing.T) {
+	t.Parallel()
+	registry := newTestRegistry()
+
+	garden := conf.RTSPStreamSettings{ID: "garden", Name: "Garden Camera", URL: "rtsp://garden.local/stream"}
+	source := registry.GetOrCreateRTSPSource(&garden)
+	require.NotNil(t, source)
+	assert.Equal(t, "garden", source.ID, "named streams keep their configured ID")
+	assert.Equal(t, "Garden Camera", source.DisplayName)
+
+	garden.Name = "Back Garden"
+	again := registry.GetOrCreateRTSPSource(&garden)
+	assert.Same(t, source, again)
+	assert.Equal(t, "Back Garden", again.DisplayName, "the display name follows the configuration")
+
+	clash := conf.RTSPStreamSettings{ID: "garden", URL: "rtsp://other.local/stream"}
+	other := registry.GetOrCreateRTSPSource(&clash)
+	require.NotNil(t, other)
+	assert.NotEqual(t, "garden", other.ID, "an ID of another connection is not reused")
+
+	legacy := conf.RTSPStreamSettings{URL: "rtsp://legacy.local/stream"}
+	unnamed := registry.GetOrCreateRTSPSource(&legacy)
+	require.NotNil(t, unnamed)
+	assert.Contains(t, unnamed.ID, "rtsp_", "unnamed streams get a generated ID")
+}
+
+func TestFFmpegStreamSettingsChange(t *testing.T) {
+	t.Parallel()
+
+	stream := &FFmpegStream{
+		source:      &AudioSource{ID: "garden", DisplayName: "Garden"},
+		transport:   "tcp",
+		audioFilter: "volume=6dB",
+	}
+	config := conf.RTSPStreamSettings{ID: "garden", Name: "Garden", Gain: 6}
+	assert.Empty(t, stream.settingsChange("tcp", &config))
+	assert.Equal(t, "transport", stream.settingsChange("udp", &config))
+
+	config.Name = "Back Garden"
+	assert.Equal(t, "stream name", stream.settingsChange("tcp", &config))
+
+	config.Name, config.Gain = "Garden", 3
+	assert.Equal(t, "audio filter", stream.settingsChange("tcp", &config))
+
+	config.ID = "porch"
+	assert.Equal(t, "stream ID", stream.settingsChange("tcp", &config))
+}
@@ -513,6 +513,19 @@ func formatBandKey(centerFreq float64) string {
 	return fmt.Sprintf("%.1f_kHz", centerFreq/1000)
 }
 
+// OctaveBandKeys returns the keys of the octave bands measured at the capture sample
+// rate, in ascending frequency order
+func OctaveBandKeys() []string {
+	keys := make([]string, 0, len(octaveBandCenterFreqs))
+	for _, centerFreq := range octaveBandCenterFreqs {
+		if centerFreq >= float64(conf.SampleRate)/2 {
+			continue
+		}
+		keys = append(keys, formatBandKey(centerFreq))
+	}
+	return keys
+}
+
 // Global sound level processor registry and logger
 var (
 	soundLevelProcessors     = make(map[string]*soundLevelProcessor)
@@ -360,3 +360,19 @@ func TestProcessAudioData_OddByteCount(t *testing.T) {
 	// Result will be nil because we haven't completed an interval
 	assert.Nil(t, result)
 }
+
+func TestOctaveBandKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := OctaveBandKeys()
+	nyquistFreq := float64(conf.SampleRate) / 2.0
+	expected := make([]string, 0, len(octaveBandCenterFreqs))
+	for _, freq := range octaveBandCenterFreqs {
+		if freq < nyquistFreq {
+			expected = append(expected, formatBandKey(freq))
+		}
+	}
+	assert.Equal(t, expected, keys, "one key per measured octave band in ascending order")
+	require.NotEmpty(t, keys)
+	assert.Equal(t, "25.0_Hz", keys[0])
+}
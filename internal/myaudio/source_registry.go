@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/privacy"
@@ -98,6 +99,18 @@ func (r *AudioSourceRegistry) RegisterSource(connectionString string, config Sou
 	// Check if source already exists
 	if existingID, exists := r.connectionMap[connectionString]; exists {
 		source := r.sources[existingID]
+		// Follow a changed ID, e.g. a legacy URL that became a named stream,
+		// unless the requested ID belongs to another connection
+		if config.ID != "" && config.ID != existingID {
+			if _, taken := r.sources[config.ID]; taken {
+				r.logger.Warn("Requested source ID already in use, keeping the existing ID",
+					logger.String("id", config.ID),
+					logger.String("existing_id", existingID),
+					logger.String("safe", source.SafeString))
+			} else {
+				source = r.rekeySource(source, config.ID)
+			}
+		}
 		// Update metadata if provided
 		if config.DisplayName != "" {
 			source.DisplayName = config.DisplayName
@@ -121,7 +134,13 @@ func (r *AudioSourceRegistry) RegisterSource(connectionString string, config Sou
 		ErrorCount:       0,
 	}
 
-	// Auto-generate ID if not provided
+	// Auto-generate ID if not provided, or if the requested ID belongs to another connection
+	if _, taken := r.sources[source.ID]; taken {
+		r.logger.Warn("Requested source ID already in use, generating a new ID",
+			logger.String("id", source.ID),
+			logger.String("safe", source.SafeString))
+		source.ID = ""
+	}
 	if source.ID == "" {
 		source.ID = r.generateID(config.Type)
 	}
@@ -143,6 +162,30 @@ func (r *AudioSourceRegistry) RegisterSource(connectionString string, config Sou
 	return source, nil
 }
 
+// rekeySource moves a registered source to a new ID. A copy is stored so that
+// streams still holding the old source keep cleaning up under the old ID.
+// The caller must hold the write lock.
+func (r *AudioSourceRegistry) rekeySource(source *AudioSource, newID string) *AudioSource {
+	oldID := source.ID
+	rekeyed := *source
+	rekeyed.ID = newID
+
+	delete(r.sources, oldID)
+	r.sources[newID] = &rekeyed
+	r.connectionMap[source.connectionString] = newID
+	if refCount, exists := r.refCounts[oldID]; exists {
+		delete(r.refCounts, oldID)
+		r.refCounts[newID] = refCount
+	}
+
+	r.logger.Info("Re-keyed audio source",
+		logger.String("old_id", oldID),
+		logger.String("id", newID),
+		logger.String("safe", source.SafeString))
+
+	return &rekeyed
+}
+
 // GetSourceByID retrieves a source by its ID
 func (r *AudioSourceRegistry) GetSourceByID(id string) (*AudioSource, bool) {
 	r.mu.RLock()
@@ -186,6 +229,28 @@ func (r *AudioSourceRegistry) GetOrCreateSource(connectionString string, sourceT
 	return source
 }
 
+// GetOrCreateRTSPSource ensures the source of an RTSP stream exists and returns it.
+// Named streams are registered with their configured ID and display name, unnamed
+// streams from the legacy URL list get a generated ID and display name.
+func (r *AudioSourceRegistry) GetOrCreateRTSPSource(stream *conf.RTSPStreamSettings) *AudioSource {
+	if stream.ID == "" {
+		return r.GetOrCreateSource(stream.URL, SourceTypeRTSP)
+	}
+
+	source, err := r.RegisterSource(stream.URL, SourceConfig{
+		ID:          stream.ID,
+		DisplayName: stream.DisplayName(),
+		Type:        SourceTypeRTSP,
+	})
+	if err != nil {
+		r.logger.Error("Failed to register named RTSP source",
+			logger.String("stream_id", stream.ID),
+			logger.Error(err))
+		return nil
+	}
+	return source
+}
+
 // detectSourceTypeFromString determines source type from connection string
 func detectSourceTypeFromString(connectionString string) SourceType {
 	// RTSP URLs (including test URLs for testing)
@@ -77,7 +77,7 @@ func (c *DetectionNotificationConsumer) ProcessDetectionEvent(event events.Detec
 	title, message := c.renderTitleAndMessage(event, templateData)
 	notification := c.buildDetectionNotification(event, title, message, templateData)
 
-	if err := c.service.store.Save(notification); err != nil {
+	if err := c.service.getStore().Save(notification); err != nil {
 		c.logger.Error("failed to save new species notification",
 			logger.String("species", event.GetSpeciesName()),
 			logger.Error(err))
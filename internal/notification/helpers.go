@@ -69,7 +69,7 @@ func NotifyDetection(species string, confidence float64, metadata map[string]any
 		for k, v := range metadata {
 			notification.WithMetadata(k, v)
 		}
-		_ = service.store.Update(notification)
+		_ = service.getStore().Update(notification)
 	}
 }
 
@@ -128,7 +128,7 @@ func NotifyResourceAlert(resource string, current, threshold float64, unit strin
 			WithMetadata("threshold", threshold).
 			WithMetadata("unit", unit).
 			WithExpiry(DefaultResourceAlertExpiry) // Auto-expire resource alerts
-		_ = service.store.Update(notification)
+		_ = service.getStore().Update(notification)
 	}
 }
 
@@ -177,7 +177,7 @@ func NotifyStartup(version string) {
 	notification, _ := service.CreateWithComponent(TypeInfo, PriorityLow, title, message, "system")
 	if notification != nil {
 		notification.WithExpiry(DefaultQuickExpiry) // Auto-expire after 5 minutes
-		_ = service.store.Update(notification)
+		_ = service.getStore().Update(notification)
 	}
 }
 
@@ -38,6 +38,7 @@ const (
 	filterReasonComponentMismatch   = "component_mismatch"   // Notification component not allowed
 	filterReasonConfidenceThreshold = "confidence_threshold" // Confidence metadata didn't meet threshold
 	filterReasonMetadataMismatch    = "metadata_mismatch"    // Other metadata filter failed
+	filterReasonProviderMismatch    = "provider_mismatch"    // Notification is targeted at other providers
 )
 
 // pushDispatcher routes notifications to enabled providers based on filters
@@ -253,6 +254,12 @@ func (d *pushDispatcher) shouldDispatchToProvider(ep *enhancedProvider, notif *N
 	if !ep.prov.IsEnabled() || !ep.prov.SupportsType(notif.Type) {
 		return false
 	}
+	if !targetsProvider(notif, ep.name) {
+		if d.metrics != nil {
+			d.metrics.RecordFilterRejection(ep.name, filterReasonProviderMismatch)
+		}
+		return false
+	}
 	return d.matchesFilter(ep, notif)
 }
 
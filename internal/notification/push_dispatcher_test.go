@@ -1274,3 +1274,37 @@ func TestIsPrivateOrLocalURL(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldDispatchToProvider_ProviderTargeting(t *testing.T) {
+	t.Parallel()
+
+	d := &pushDispatcher{log: GetLogger(), enabled: true}
+	telegram := &enhancedProvider{prov: newFakeProvider("telegram", true), name: "telegram"}
+	webhook := &enhancedProvider{prov: newFakeProvider("webhook", true), name: "webhook"}
+
+	tests := []struct {
+		name      string
+		providers any
+		telegram  bool
+		webhook   bool
+	}{
+		{"no providers metadata", nil, true, true},
+		{"empty list", []string{}, true, true},
+		{"single provider", []string{"telegram"}, true, false},
+		{"case insensitive", []string{" Webhook "}, false, true},
+		{"generic slice from json", []any{"telegram", "webhook"}, true, true},
+		{"unknown provider", []string{"pushover"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			n := NewNotification(TypeDetection, PriorityHigh, "title", "message")
+			if tt.providers != nil {
+				n = n.WithMetadata(MetadataKeyProviders, tt.providers)
+			}
+			assert.Equal(t, tt.telegram, d.shouldDispatchToProvider(telegram, n))
+			assert.Equal(t, tt.webhook, d.shouldDispatchToProvider(webhook, n))
+		})
+	}
+}
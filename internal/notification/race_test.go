@@ -2,12 +2,14 @@
 package notification
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/events"
 )
 
 // Test data constants
@@ -315,3 +317,43 @@ func TestCloneNilNotification(t *testing.T) {
 	clone := nilNotif.Clone()
 	assert.Nil(t, clone, "Clone of nil should return nil")
 }
+
+// TestSetStoreDuringDetectionRace tests replacing the store while the detection
+// consumer saves notifications, as happens when the persistent store is attached
+// at startup while detections are already coming in.
+//
+// Run with: go test -race -run TestSetStoreDuringDetectionRace ./internal/notification/
+func TestSetStoreDuringDetectionRace(t *testing.T) {
+	service, consumer, cleanup := setupTestServiceAndConsumer(t)
+	defer cleanup()
+
+	const numEvents = 50
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		defer close(done)
+		for i := range numEvents {
+			event, err := events.NewDetectionEvent(fmt.Sprintf("Species %d", i), "Genus species", 0.9, "mic", true, 0)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, consumer.ProcessDetectionEvent(event))
+		}
+	})
+	wg.Go(func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				assert.NoError(t, service.SetStore(NewInMemoryStore(numEvents*2)))
+			}
+		}
+	})
+	wg.Wait()
+
+	// Every store swap copies the previous notifications, none are lost
+	notifications, err := service.List(&FilterOptions{Types: []Type{TypeDetection}})
+	require.NoError(t, err)
+	assert.Len(t, notifications, numEvents)
+}
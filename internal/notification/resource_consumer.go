@@ -225,7 +225,7 @@ func (w *ResourceEventWorker) enrichResourceMetadata(notification *Notification,
 	}
 
 	notification.WithExpiry(w.determineResourceExpiry(event))
-	_ = w.service.store.Update(notification)
+	_ = w.service.getStore().Update(notification)
 }
 
 // determineResourceExpiry returns the appropriate expiry duration based on event severity and type.
@@ -20,6 +20,7 @@ type Subscriber struct {
 // Service manages notifications and provides rate limiting
 type Service struct {
 	store         NotificationStore
+	storeMu       sync.RWMutex // Protects store replacement by SetStore
 	subscribers   []*Subscriber
 	subscribersMu sync.RWMutex
 	rateLimiter   *RateLimiter
@@ -102,6 +103,56 @@ func NewService(config *ServiceConfig) *Service {
 	return service
 }
 
+// getStore returns the current notification store
+func (s *Service) getStore() NotificationStore {
+	s.storeMu.RLock()
+	defer s.storeMu.RUnlock()
+	return s.store
+}
+
+// SetStore replaces the notification store, for example with a persistent store once
+// the database is available. The newest unread notifications, at most
+// DefaultMaxNotifications of them, are copied to the new store so nothing raised during
+// startup is lost and detaching from a database does not load its whole history.
+func (s *Service) SetStore(store NotificationStore) error {
+	if store == nil {
+		return errors.Newf("notification store cannot be nil").
+			Component("notification").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
+	unread, err := s.store.List(&FilterOptions{
+		Status: []Status{StatusUnread},
+		Limit:  DefaultMaxNotifications,
+	})
+	if err != nil {
+		return errors.New(err).
+			Component("notification").
+			Category(errors.CategorySystem).
+			Context("operation", "list_unread_notifications").
+			Build()
+	}
+	for _, notif := range unread {
+		if err := store.Save(notif); err != nil {
+			return errors.New(err).
+				Component("notification").
+				Category(errors.CategorySystem).
+				Context("operation", "copy_unread_notification").
+				Context("notification_id", notif.ID).
+				Build()
+		}
+	}
+
+	s.store = store
+	s.logger.Info("notification store replaced",
+		logger.Int("copied_notifications", len(unread)))
+	return nil
+}
+
 // SetTelemetry sets the telemetry integration for the service.
 // This must be called after service creation to enable telemetry reporting.
 func (s *Service) SetTelemetry(telemetry *NotificationTelemetry) {
@@ -143,7 +194,7 @@ func (s *Service) Create(notifType Type, priority Priority, title, message strin
 	}
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return nil, errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -177,7 +228,7 @@ func (s *Service) CreateWithComponent(notifType Type, priority Priority, title,
 		WithComponent(component)
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return nil, errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -193,12 +244,12 @@ func (s *Service) CreateWithComponent(notifType Type, priority Priority, title,
 
 // Get retrieves a notification by ID
 func (s *Service) Get(id string) (*Notification, error) {
-	return s.store.Get(id)
+	return s.getStore().Get(id)
 }
 
 // List returns notifications based on filter options
 func (s *Service) List(filter *FilterOptions) ([]*Notification, error) {
-	return s.store.List(filter)
+	return s.getStore().List(filter)
 }
 
 // MarkAsRead updates a notification's status to read
@@ -210,13 +261,13 @@ func (s *Service) MarkAsRead(id string) error {
 			Build()
 	}
 
-	notification, err := s.store.Get(id)
+	notification, err := s.getStore().Get(id)
 	if err != nil {
 		return err
 	}
 
 	notification.MarkAsRead()
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // MarkAsAcknowledged updates a notification's status to acknowledged
@@ -228,13 +279,13 @@ func (s *Service) MarkAsAcknowledged(id string) error {
 			Build()
 	}
 
-	notification, err := s.store.Get(id)
+	notification, err := s.getStore().Get(id)
 	if err != nil {
 		return err
 	}
 
 	notification.MarkAsAcknowledged()
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // Delete removes a notification
@@ -246,7 +297,7 @@ func (s *Service) Delete(id string) error {
 			Build()
 	}
 
-	return s.store.Delete(id)
+	return s.getStore().Delete(id)
 }
 
 // Subscribe creates a channel to receive real-time notifications.
@@ -325,7 +376,7 @@ func (s *Service) Unsubscribe(ch <-chan *Notification) {
 
 // GetUnreadCount returns the number of unread notifications
 func (s *Service) GetUnreadCount() (int, error) {
-	return s.store.GetUnreadCount()
+	return s.getStore().GetUnreadCount()
 }
 
 // CreateErrorNotification creates a notification from an error
@@ -475,7 +526,7 @@ func (s *Service) cleanupLoop() {
 func (s *Service) performCleanup() {
 	s.logCleanupStart()
 
-	if err := s.store.DeleteExpired(); err != nil {
+	if err := s.getStore().DeleteExpired(); err != nil {
 		s.logger.Error("error cleaning up expired notifications", logger.Error(err))
 	} else if s.config.Debug {
 		s.logger.Debug("notification cleanup completed")
@@ -488,7 +539,7 @@ func (s *Service) logCleanupStart() {
 		return
 	}
 
-	notifications, _ := s.store.List(&FilterOptions{})
+	notifications, _ := s.getStore().List(&FilterOptions{})
 	expiredCount := s.countExpired(notifications)
 
 	if expiredCount > 0 {
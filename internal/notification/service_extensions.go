@@ -42,7 +42,7 @@ func (s *Service) CreateWithMetadata(notification *Notification) error {
 	}
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -261,6 +261,39 @@ func TestService_CreateWithMetadata_WithFailingStore(t *testing.T) {
 	assert.NotEmpty(t, err.Error(), "Error should have a meaningful message")
 }
 
+func TestService_SetStore(t *testing.T) {
+	t.Parallel()
+
+	service := NewService(DefaultServiceConfig())
+	defer service.Stop()
+
+	require.Error(t, service.SetStore(nil), "SetStore() should reject a nil store")
+
+	regular, err := service.Create(TypeInfo, PriorityLow, "Startup", "Raised before the switch")
+	require.NoError(t, err)
+	toast := NewToast("ephemeral", ToastTypeInfo).ToNotification()
+	require.NoError(t, service.CreateWithMetadata(toast))
+
+	newStore := NewInMemoryStore(10)
+	require.NoError(t, service.SetStore(newStore))
+
+	// Unread notifications are copied, toasts are not
+	_, err = newStore.Get(regular.ID)
+	require.NoError(t, err, "notification created before SetStore() should be copied")
+	_, err = newStore.Get(toast.ID)
+	require.ErrorIs(t, err, ErrNotificationNotFound)
+
+	// New notifications go to the new store
+	created, err := service.Create(TypeInfo, PriorityLow, "After", "Raised after the switch")
+	require.NoError(t, err)
+	_, err = newStore.Get(created.ID)
+	require.NoError(t, err)
+
+	count, err := service.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
 // Benchmark for CreateWithMetadata performance
 func BenchmarkService_CreateWithMetadata(b *testing.B) {
 	config := &ServiceConfig{
@@ -292,3 +325,36 @@ func BenchmarkService_CreateWithMetadata(b *testing.B) {
 		}
 	}
 }
+
+func TestService_SetStoreCopiesNewestUnread(t *testing.T) {
+	t.Parallel()
+
+	service := NewService(DefaultServiceConfig())
+	defer service.Stop()
+
+	// Stand in for a database store holding more history than an in-memory store keeps
+	service.store = NewInMemoryStore(DefaultMaxNotifications * 2)
+	base := time.Now().Add(-time.Hour)
+	var newest *Notification
+	for i := range DefaultMaxNotifications + 10 {
+		notif := NewNotification(TypeInfo, PriorityLow, "History", "Stored entry")
+		notif.Timestamp = base.Add(time.Duration(i) * time.Second)
+		require.NoError(t, service.store.Save(notif))
+		newest = notif
+	}
+	read := NewNotification(TypeInfo, PriorityLow, "Read", "Already seen")
+	read.Status = StatusRead
+	read.Timestamp = base.Add(time.Hour)
+	require.NoError(t, service.store.Save(read))
+
+	newStore := NewInMemoryStore(DefaultMaxNotifications)
+	require.NoError(t, service.SetStore(newStore))
+
+	copied, err := newStore.List(nil)
+	require.NoError(t, err)
+	assert.Len(t, copied, DefaultMaxNotifications)
+	_, err = newStore.Get(newest.ID)
+	require.NoError(t, err, "the newest unread notification should be copied")
+	_, err = newStore.Get(read.ID)
+	require.ErrorIs(t, err, ErrNotificationNotFound, "read notifications should not be copied")
+}
@@ -8,6 +8,7 @@ import (
 	"maps"
 	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -66,6 +67,8 @@ const (
 const (
 	// MetadataKeyIsToast identifies toast notifications in metadata
 	MetadataKeyIsToast = "isToast"
+	// MetadataKeyProviders restricts push delivery to the listed provider names
+	MetadataKeyProviders = "providers"
 )
 
 // isToastNotification checks if a notification is a toast notification
@@ -78,6 +81,39 @@ func isToastNotification(notif *Notification) bool {
 	return ok && isToast
 }
 
+// targetsProvider checks if a notification may be pushed to the named provider.
+// Notifications without a providers list in metadata go to every provider.
+func targetsProvider(notif *Notification, providerName string) bool {
+	if notif == nil || notif.Metadata == nil {
+		return true
+	}
+
+	var names []string
+	switch v := notif.Metadata[MetadataKeyProviders].(type) {
+	case []string:
+		names = v
+	case []any:
+		// Metadata restored from JSON holds a generic slice
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	default:
+		return true
+	}
+	if len(names) == 0 {
+		return true
+	}
+
+	for _, name := range names {
+		if strings.EqualFold(strings.TrimSpace(name), providerName) {
+			return true
+		}
+	}
+	return false
+}
+
 // Notification represents a single notification event
 type Notification struct {
 	// ID is the unique identifier for the notification
@@ -0,0 +1,239 @@
+package observation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// Output file types supported by the file and directory commands
+const (
+	OutputTypeTable      = "table"       // BirdNET-Go table, the default
+	OutputTypeCSV        = "csv"         // BirdNET-Go CSV with detection timestamps
+	OutputTypeRaven      = "raven"       // Raven Pro selection table
+	OutputTypeAudacity   = "audacity"    // Audacity label track
+	OutputTypeBirdNETCSV = "birdnet-csv" // BirdNET-Analyzer compatible CSV
+	OutputTypeJSON       = "json"        // JSON document
+)
+
+// OutputTypes lists all supported output file types
+var OutputTypes = []string{
+	OutputTypeTable, OutputTypeCSV, OutputTypeRaven, OutputTypeAudacity, OutputTypeBirdNETCSV, OutputTypeJSON,
+}
+
+// ExportInfo describes the analysis that produced the exported notes
+type ExportInfo struct {
+	InputFile    string    // Analyzed audio file
+	ModelVersion string    // BirdNET model version, e.g. BirdNET_GLOBAL_6K_V2.4
+	Threshold    float64   // Confidence threshold, notes at or below it are not exported
+	Start        time.Time // Recording start, note times are exported as offsets from it
+}
+
+// Raven selection tables need a frequency range, BirdNET analyzes 0-15 kHz
+const (
+	ravenLowFreq  = 0
+	ravenHighFreq = 15000
+)
+
+// IsValidOutputType reports whether outputType is a supported output file type
+func IsValidOutputType(outputType string) bool {
+	return slices.Contains(OutputTypes, outputType)
+}
+
+// OutputFileName returns the name of the output file of an analyzed file. The
+// suffixes of the bioacoustics formats follow the BirdNET-Analyzer naming.
+func OutputFileName(base, outputType string) string {
+	switch outputType {
+	case OutputTypeCSV:
+		return base + ".csv"
+	case OutputTypeRaven:
+		return base + ".BirdNET.selection.table.txt"
+	case OutputTypeAudacity:
+		return base + ".BirdNET.results.txt"
+	case OutputTypeBirdNETCSV:
+		return base + ".BirdNET.results.csv"
+	case OutputTypeJSON:
+		return base + ".BirdNET.results.json"
+	default:
+		return base + ".txt"
+	}
+}
+
+// WriteNotesFile writes the notes in one of the bioacoustics output formats. The
+// output goes to stdout when base is empty, otherwise to OutputFileName(base, outputType).
+func WriteNotesFile(outputType string, notes []datastore.Note, base string, info *ExportInfo) error {
+	if base == "" {
+		return WriteNotes(os.Stdout, outputType, notes, info)
+	}
+
+	filename := OutputFileName(base, outputType)
+	file, err := os.Create(filename) //nolint:gosec // G304: filename is from settings.Output.File.Path
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", filename, err)
+	}
+	if err := WriteNotes(file, outputType, notes, info); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file %s: %w", filename, err)
+	}
+	fmt.Println("Output written to", filename)
+	return nil
+}
+
+// WriteNotes writes the notes to w in one of the bioacoustics output formats
+func WriteNotes(w io.Writer, outputType string, notes []datastore.Note, info *ExportInfo) error {
+	if info == nil {
+		info = &ExportInfo{}
+	}
+
+	exported := make([]datastore.Note, 0, len(notes))
+	for i := range notes {
+		if notes[i].Confidence > info.Threshold {
+			exported = append(exported, notes[i])
+		}
+	}
+
+	switch outputType {
+	case OutputTypeRaven:
+		return writeRaven(w, exported, info)
+	case OutputTypeAudacity:
+		return writeAudacity(w, exported, info)
+	case OutputTypeBirdNETCSV:
+		return writeBirdNETCSV(w, exported, info)
+	case OutputTypeJSON:
+		return writeJSON(w, exported, info)
+	default:
+		return fmt.Errorf("unsupported output type: %s", outputType)
+	}
+}
+
+// offsetSeconds returns the offset of t from the recording start in seconds
+func offsetSeconds(t time.Time, info *ExportInfo) float64 {
+	return t.Sub(info.Start).Seconds()
+}
+
+// formatSeconds formats an offset with millisecond precision, so that segments of
+// chunks analysed with a fractional overlap keep distinct offsets
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
+
+// writeRaven writes a Raven Pro selection table with one spectrogram selection per detection
+func writeRaven(w io.Writer, notes []datastore.Note, info *ExportInfo) error {
+	header := "Selection\tView\tChannel\tBegin Path\tBegin Time (s)\tEnd Time (s)\tFile Offset (s)\t" +
+		"Low Freq (Hz)\tHigh Freq (Hz)\tSpecies Code\tCommon Name\tScientific Name\tConfidence\tModel\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for i := range notes {
+		begin := offsetSeconds(notes[i].BeginTime, info)
+		line := fmt.Sprintf("%d\tSpectrogram 1\t1\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%.4f\t%s\n",
+			i+1, info.InputFile, formatSeconds(begin), formatSeconds(offsetSeconds(notes[i].EndTime, info)),
+			formatSeconds(begin), ravenLowFreq, ravenHighFreq,
+			notes[i].SpeciesCode, notes[i].CommonName, notes[i].ScientificName, notes[i].Confidence, info.ModelVersion)
+		if _, err := io.WriteString(w, line); err != nil {
+			return fmt.Errorf("failed to write note: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeAudacity writes an Audacity label track, labels carry the common name, species
+// code, confidence and model version
+func writeAudacity(w io.Writer, notes []datastore.Note, info *ExportInfo) error {
+	for i := range notes {
+		line := fmt.Sprintf("%s\t%s\t%s (%s), %.4f, %s\n",
+			formatSeconds(offsetSeconds(notes[i].BeginTime, info)),
+			formatSeconds(offsetSeconds(notes[i].EndTime, info)),
+			notes[i].CommonName, notes[i].SpeciesCode, notes[i].Confidence, info.ModelVersion)
+		if _, err := io.WriteString(w, line); err != nil {
+			return fmt.Errorf("failed to write note: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeBirdNETCSV writes the BirdNET-Analyzer CSV format, with the species code and
+// model version appended after the standard columns
+func writeBirdNETCSV(w io.Writer, notes []datastore.Note, info *ExportInfo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Start (s)", "End (s)", "Scientific name", "Common name", "Confidence", "File", "Species code", "Model"}); err != nil {
+		return fmt.Errorf("failed to write header to CSV: %w", err)
+	}
+	for i := range notes {
+		record := []string{
+			formatSeconds(offsetSeconds(notes[i].BeginTime, info)),
+			formatSeconds(offsetSeconds(notes[i].EndTime, info)),
+			notes[i].ScientificName,
+			notes[i].CommonName,
+			strconv.FormatFloat(notes[i].Confidence, 'f', 4, 64),
+			info.InputFile,
+			notes[i].SpeciesCode,
+			info.ModelVersion,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write note to CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write note to CSV: %w", err)
+	}
+	return nil
+}
+
+// jsonDetection is a detection in the JSON output
+type jsonDetection struct {
+	Begin          float64 `json:"begin"`
+	End            float64 `json:"end"`
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	SpeciesCode    string  `json:"speciesCode"`
+	Confidence     float64 `json:"confidence"`
+}
+
+// jsonOutput is the document written by the JSON output
+type jsonOutput struct {
+	File       string          `json:"file"`
+	Model      string          `json:"model"`
+	Threshold  float64         `json:"threshold"`
+	Detections []jsonDetection `json:"detections"`
+}
+
+// writeJSON writes the detections as a JSON document, times are offsets in seconds
+func writeJSON(w io.Writer, notes []datastore.Note, info *ExportInfo) error {
+	out := jsonOutput{
+		File:       filepath.ToSlash(info.InputFile),
+		Model:      info.ModelVersion,
+		Threshold:  info.Threshold,
+		Detections: make([]jsonDetection, 0, len(notes)),
+	}
+	for i := range notes {
+		out.Detections = append(out.Detections, jsonDetection{
+			Begin:          offsetSeconds(notes[i].BeginTime, info),
+			End:            offsetSeconds(notes[i].EndTime, info),
+			ScientificName: notes[i].ScientificName,
+			CommonName:     notes[i].CommonName,
+			SpeciesCode:    notes[i].SpeciesCode,
+			Confidence:     notes[i].Confidence,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package observation
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// update rewrites the golden files, run with: go test ./internal/observation -run TestWriteNotesGolden -update
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// testExportNotes returns notes timed from the zero time, like file analysis produces them
+func testExportNotes() []datastore.Note {
+	start := time.Time{}
+	note := func(begin float64, code, scientific, common string, confidence float64) datastore.Note {
+		beginTime := start.Add(time.Duration(begin * float64(time.Second)))
+		return datastore.Note{
+			BeginTime:      beginTime,
+			EndTime:        beginTime.Add(3 * time.Second),
+			SpeciesCode:    code,
+			ScientificName: scientific,
+			CommonName:     common,
+			Confidence:     confidence,
+		}
+	}
+	return []datastore.Note{
+		note(0, "eurbla", "Turdus merula", "Eurasian Blackbird", 0.9512),
+		note(3, "comchi1", "Fringilla coelebs", "Common Chaffinch", 0.75),
+		// At the threshold, not exported
+		note(4.5, "eurrob1", "Erithacus rubecula", "European Robin", 0.7),
+		note(61.5, "grtwhi1", "Curruca communis", "Greater Whitethroat, \"common\"", 0.8123),
+	}
+}
+
+// TestWriteNotesGolden compares each bioacoustics output format with its golden file
+func TestWriteNotesGolden(t *testing.T) {
+	info := &ExportInfo{
+		InputFile:    "recordings/dawn chorus.wav",
+		ModelVersion: "BirdNET_GLOBAL_6K_V2.4",
+		Threshold:    0.7,
+		Start:        time.Time{},
+	}
+
+	for _, outputType := range []string{OutputTypeRaven, OutputTypeAudacity, OutputTypeBirdNETCSV, OutputTypeJSON} {
+		t.Run(outputType, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteNotes(&buf, outputType, testExportNotes(), info))
+
+			golden := filepath.Join("testdata", OutputFileName("export", outputType))
+			if *update {
+				require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o600))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+// TestWriteNotesOffsets tests that note times are exported as offsets from the recording start
+func TestWriteNotesOffsets(t *testing.T) {
+	start := time.Date(2025, 5, 1, 4, 30, 0, 0, time.UTC)
+	notes := []datastore.Note{{
+		BeginTime:   start.Add(12 * time.Second),
+		EndTime:     start.Add(15 * time.Second),
+		SpeciesCode: "eurbla",
+		CommonName:  "Eurasian Blackbird",
+		Confidence:  0.9,
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteNotes(&buf, OutputTypeAudacity, notes, &ExportInfo{Start: start, ModelVersion: "v2.4"}))
+	assert.Equal(t, "12.000\t15.000\tEurasian Blackbird (eurbla), 0.9000, v2.4\n", buf.String())
+
+	t.Run("fractional overlap", func(t *testing.T) {
+		// With an overlap of 1.75 seconds chunks start 1.25 seconds apart
+		notes := []datastore.Note{
+			{BeginTime: start.Add(1250 * time.Millisecond), EndTime: start.Add(4250 * time.Millisecond), SpeciesCode: "eurbla", CommonName: "Eurasian Blackbird", Confidence: 0.9},
+			{BeginTime: start.Add(2500 * time.Millisecond), EndTime: start.Add(5500 * time.Millisecond), SpeciesCode: "eurbla", CommonName: "Eurasian Blackbird", Confidence: 0.8},
+		}
+		var buf bytes.Buffer
+		require.NoError(t, WriteNotes(&buf, OutputTypeAudacity, notes, &ExportInfo{Start: start, ModelVersion: "v2.4"}))
+		assert.Equal(t, "1.250\t4.250\tEurasian Blackbird (eurbla), 0.9000, v2.4\n"+
+			"2.500\t5.500\tEurasian Blackbird (eurbla), 0.8000, v2.4\n", buf.String())
+	})
+}
+
+// TestWriteNotesUnsupportedType tests that unknown output types are rejected
+func TestWriteNotesUnsupportedType(t *testing.T) {
+	var buf bytes.Buffer
+	require.Error(t, WriteNotes(&buf, OutputTypeTable, testExportNotes(), nil))
+	assert.Empty(t, buf.String())
+}
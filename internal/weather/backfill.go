@@ -0,0 +1,214 @@
+// backfill.go: Filling in missing hourly weather of past detections
+package weather
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// backfillInterval is how often missing weather is looked for
+	backfillInterval = 24 * time.Hour
+	// backfillMinAge skips recent dates, historical weather archives lag a few days behind
+	backfillMinAge = 5 * 24 * time.Hour
+	// backfillMaxRangeDays limits the days of historical weather fetched per request
+	backfillMaxRangeDays = 31
+	// backfillRequestDelay spaces requests to the historical weather API
+	backfillRequestDelay = time.Second
+)
+
+// HistoricalProvider is implemented by providers which can fetch past weather
+type HistoricalProvider interface {
+	// FetchHistoricalWeather returns the hourly weather of the UTC dates from start to end
+	FetchHistoricalWeather(ctx context.Context, settings *conf.Settings, start, end time.Time) ([]WeatherData, error)
+}
+
+// backfillStore is implemented by datastores supporting the weather backfill
+type backfillStore interface {
+	GetDetectionDates(ctx context.Context) ([]string, error)
+	GetHourlyWeatherTimes(ctx context.Context, start, end time.Time) ([]time.Time, error)
+}
+
+// dateRange is a range of consecutive local dates
+type dateRange struct {
+	start, end time.Time // Midnight of the first and last date
+}
+
+// historicalProvider returns the provider when it supports backfilling and backfill is enabled
+func (s *Service) historicalProvider() (HistoricalProvider, bool) {
+	historical, ok := s.provider.(HistoricalProvider)
+	return historical, ok && s.settings.Realtime.Weather.OpenMeteo.Backfill
+}
+
+// runBackfill backfills missing weather now and then once a day until stopChan is closed
+func (s *Service) runBackfill(historical HistoricalProvider, stopChan <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		saved, err := s.Backfill(ctx, historical)
+		if err != nil && ctx.Err() == nil {
+			getLogger().Warn("Weather backfill failed",
+				logger.Int("saved_hours", saved),
+				logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backfillInterval):
+		}
+	}
+}
+
+// Backfill saves the historical weather of the hours of past detection dates which
+// have no weather, e.g. dates of imported detections or of downtime. It returns the
+// number of hours saved.
+func (s *Service) Backfill(ctx context.Context, historical HistoricalProvider) (int, error) {
+	store, ok := s.db.(backfillStore)
+	if !ok {
+		getLogger().Debug("Datastore does not support weather backfill")
+		return 0, nil
+	}
+
+	missing, covered, err := s.missingWeatherDates(ctx, store)
+	if err != nil || len(missing) == 0 {
+		return 0, err
+	}
+
+	ranges := groupDateRanges(missing)
+	getLogger().Info("Backfilling missing weather of past detections",
+		logger.Int("dates", len(missing)),
+		logger.Int("requests", len(ranges)))
+
+	saved := 0
+	for i, r := range ranges {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return saved, ctx.Err()
+			case <-time.After(backfillRequestDelay):
+			}
+		}
+
+		// Local dates may span two UTC dates, fetch the UTC dates of the first and
+		// last instant of the range
+		last := r.end.AddDate(0, 0, 1).Add(-time.Second)
+		data, err := historical.FetchHistoricalWeather(ctx, s.settings, r.start, last)
+		if err != nil {
+			return saved, err
+		}
+
+		for j := range data {
+			hour := data[j].Time.Truncate(time.Hour)
+			if _, ok := missing[hour.In(time.Local).Format(time.DateOnly)]; !ok {
+				continue
+			}
+			if _, ok := covered[hour.Unix()]; ok {
+				continue
+			}
+			if err := s.saveWeatherRecord(&data[j]); err != nil {
+				return saved, err
+			}
+			covered[hour.Unix()] = struct{}{}
+			saved++
+		}
+	}
+
+	getLogger().Info("Weather backfill completed",
+		logger.Int("dates", len(missing)),
+		logger.Int("saved_hours", saved))
+	return saved, nil
+}
+
+// missingWeatherDates returns the local dates with detections which have hours
+// without weather, leaving out dates too recent for historical weather, and the
+// hours with weather around them
+func (s *Service) missingWeatherDates(ctx context.Context, store backfillStore) (map[string]time.Time, map[int64]struct{}, error) {
+	dates, err := store.GetDetectionDates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cutoff := time.Now().Add(-backfillMinAge)
+	candidates := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		day, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+		if err != nil || day.AddDate(0, 0, 1).After(cutoff) {
+			continue
+		}
+		candidates = append(candidates, day)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+
+	covered, err := s.coveredWeatherHours(ctx, store, candidates[0], candidates[len(candidates)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missing := make(map[string]time.Time)
+	for _, day := range candidates {
+		// Step in absolute hours, days have 23 or 25 hours on DST changes
+		next := day.AddDate(0, 0, 1)
+		for hour := day; hour.Before(next); hour = hour.Add(time.Hour) {
+			if _, ok := covered[hour.Truncate(time.Hour).Unix()]; !ok {
+				missing[day.Format(time.DateOnly)] = day
+				break
+			}
+		}
+	}
+	return missing, covered, nil
+}
+
+// coveredWeatherHours returns the starts of the UTC hours with weather around the
+// local dates from first to last as Unix times
+func (s *Service) coveredWeatherHours(ctx context.Context, store backfillStore, first, last time.Time) (map[int64]struct{}, error) {
+	// Weather times may be stored in any time zone, query with a margin of a day
+	times, err := store.GetHourlyWeatherTimes(ctx, first.AddDate(0, 0, -1).UTC(), last.AddDate(0, 0, 2).UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	covered := make(map[int64]struct{}, len(times))
+	for _, t := range times {
+		covered[t.Truncate(time.Hour).Unix()] = struct{}{}
+	}
+	return covered, nil
+}
+
+// groupDateRanges groups dates into ranges of consecutive dates of at most
+// backfillMaxRangeDays days, oldest first
+func groupDateRanges(dates map[string]time.Time) []dateRange {
+	days := make([]time.Time, 0, len(dates))
+	for _, day := range dates {
+		days = append(days, day)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+
+	var ranges []dateRange
+	for _, day := range days {
+		if n := len(ranges); n > 0 {
+			last := &ranges[n-1]
+			consecutive := last.end.AddDate(0, 0, 1).Equal(day)
+			if consecutive && !last.start.AddDate(0, 0, backfillMaxRangeDays-1).Before(day) {
+				last.end = day
+				continue
+			}
+		}
+		ranges = append(ranges, dateRange{start: day, end: day})
+	}
+	return ranges
+}
@@ -0,0 +1,205 @@
+// backfill_test.go: Tests for the weather backfill of past detection dates
+package weather
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata" // Time zones with DST changes regardless of the system zoneinfo
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// fakeBackfillStore is a datastore with detection dates and saved hourly weather
+type fakeBackfillStore struct {
+	datastore.Interface
+	dates []string
+	times []time.Time
+	saved []datastore.HourlyWeather
+}
+
+func (f *fakeBackfillStore) GetDetectionDates(_ context.Context) ([]string, error) {
+	return f.dates, nil
+}
+
+func (f *fakeBackfillStore) GetHourlyWeatherTimes(_ context.Context, _, _ time.Time) ([]time.Time, error) {
+	return f.times, nil
+}
+
+func (f *fakeBackfillStore) SaveDailyEvents(_ *datastore.DailyEvents) error {
+	return nil
+}
+
+func (f *fakeBackfillStore) SaveHourlyWeather(hourlyWeather *datastore.HourlyWeather) error {
+	f.saved = append(f.saved, *hourlyWeather)
+	return nil
+}
+
+// setLocalTimeZone replaces the local time zone for the duration of a test
+func setLocalTimeZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	original := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = original })
+	return loc
+}
+
+// localDates returns the dates at local midnight keyed by date
+func localDates(t *testing.T, loc *time.Location, dates ...string) map[string]time.Time {
+	t.Helper()
+	days := make(map[string]time.Time, len(dates))
+	for _, date := range dates {
+		day, err := time.ParseInLocation(time.DateOnly, date, loc)
+		require.NoError(t, err)
+		days[date] = day
+	}
+	return days
+}
+
+// hoursOf returns the starts of all hours of a local date, 23 or 25 on DST changes
+func hoursOf(t *testing.T, loc *time.Location, date string) []time.Time {
+	t.Helper()
+	day := localDates(t, loc, date)[date]
+	var hours []time.Time
+	for hour := day; hour.Before(day.AddDate(0, 0, 1)); hour = hour.Add(time.Hour) {
+		hours = append(hours, hour.UTC())
+	}
+	return hours
+}
+
+func TestGroupDateRanges(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Helsinki")
+	require.NoError(t, err)
+
+	monthOfDates := func(first string, days int) []string {
+		start, err := time.ParseInLocation(time.DateOnly, first, loc)
+		require.NoError(t, err)
+		dates := make([]string, 0, days)
+		for i := range days {
+			dates = append(dates, start.AddDate(0, 0, i).Format(time.DateOnly))
+		}
+		return dates
+	}
+
+	tests := []struct {
+		name  string
+		dates []string
+		want  [][2]string // First and last date of each range
+	}{
+		{
+			name:  "no dates",
+			dates: nil,
+			want:  nil,
+		},
+		{
+			name:  "single date",
+			dates: []string{"2024-06-01"},
+			want:  [][2]string{{"2024-06-01", "2024-06-01"}},
+		},
+		{
+			name:  "gaps split ranges and ranges are sorted",
+			dates: []string{"2024-06-05", "2024-06-01", "2024-06-02", "2024-06-07"},
+			want:  [][2]string{{"2024-06-01", "2024-06-02"}, {"2024-06-05", "2024-06-05"}, {"2024-06-07", "2024-06-07"}},
+		},
+		{
+			name:  "spring DST change keeps dates consecutive",
+			dates: []string{"2024-03-30", "2024-03-31", "2024-04-01"},
+			want:  [][2]string{{"2024-03-30", "2024-04-01"}},
+		},
+		{
+			name:  "autumn DST change keeps dates consecutive",
+			dates: []string{"2024-10-26", "2024-10-27", "2024-10-28"},
+			want:  [][2]string{{"2024-10-26", "2024-10-28"}},
+		},
+		{
+			name:  "ranges are limited to the maximum days",
+			dates: monthOfDates("2024-03-15", backfillMaxRangeDays+1),
+			want:  [][2]string{{"2024-03-15", "2024-04-14"}, {"2024-04-15", "2024-04-15"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := groupDateRanges(localDates(t, loc, tt.dates...))
+			got := make([][2]string, 0, len(ranges))
+			for _, r := range ranges {
+				got = append(got, [2]string{r.start.Format(time.DateOnly), r.end.Format(time.DateOnly)})
+			}
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMissingWeatherDates(t *testing.T) {
+	loc := setLocalTimeZone(t, "Europe/Helsinki")
+
+	springHours := hoursOf(t, loc, "2024-03-31")
+	autumnHours := hoursOf(t, loc, "2024-10-27")
+	require.Len(t, springHours, 23)
+	require.Len(t, autumnHours, 25)
+
+	tests := []struct {
+		name    string
+		dates   []string
+		covered []time.Time
+		want    []string
+	}{
+		{
+			name:    "all 23 hours of the spring DST date are covered",
+			dates:   []string{"2024-03-31"},
+			covered: springHours,
+			want:    nil,
+		},
+		{
+			name:    "the repeated hour of the autumn DST date is missing",
+			dates:   []string{"2024-10-27"},
+			covered: append(append([]time.Time{}, autumnHours[:4]...), autumnHours[5:]...),
+			want:    []string{"2024-10-27"},
+		},
+		{
+			name:    "all 25 hours of the autumn DST date are covered",
+			dates:   []string{"2024-10-27"},
+			covered: autumnHours,
+			want:    nil,
+		},
+		{
+			name:  "dates without weather are missing",
+			dates: []string{"2024-03-30", "2024-03-31"},
+			want:  []string{"2024-03-30", "2024-03-31"},
+		},
+		{
+			name:  "recent and invalid dates are left out",
+			dates: []string{time.Now().Format(time.DateOnly), "not-a-date"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeBackfillStore{dates: tt.dates, times: tt.covered}
+			s := &Service{}
+
+			missing, _, err := s.missingWeatherDates(t.Context(), store)
+			require.NoError(t, err)
+
+			got := make([]string, 0, len(missing))
+			for date, day := range missing {
+				assert.Equal(t, date, day.Format(time.DateOnly))
+				got = append(got, date)
+			}
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -211,6 +211,39 @@ var OpenWeatherToIcon = map[string]IconCode{
 	"50n": IconFog,
 }
 
+// OpenMeteoToIcon maps WMO weather interpretation codes returned by Open-Meteo to
+// standardized icon codes
+var OpenMeteoToIcon = map[string]IconCode{
+	"0":  IconClearSky,     // clear sky
+	"1":  IconFair,         // mainly clear
+	"2":  IconPartlyCloudy, // partly cloudy
+	"3":  IconCloudy,       // overcast
+	"45": IconFog,          // fog
+	"48": IconFog,          // depositing rime fog
+	"51": IconRain,         // light drizzle
+	"53": IconRain,         // moderate drizzle
+	"55": IconRain,         // dense drizzle
+	"56": IconSleet,        // light freezing drizzle
+	"57": IconSleet,        // dense freezing drizzle
+	"61": IconRain,         // slight rain
+	"63": IconRain,         // moderate rain
+	"65": IconRain,         // heavy rain
+	"66": IconSleet,        // light freezing rain
+	"67": IconSleet,        // heavy freezing rain
+	"71": IconSnow,         // slight snow fall
+	"73": IconSnow,         // moderate snow fall
+	"75": IconSnow,         // heavy snow fall
+	"77": IconSnow,         // snow grains
+	"80": IconRainShowers,  // slight rain showers
+	"81": IconRainShowers,  // moderate rain showers
+	"82": IconRainShowers,  // violent rain showers
+	"85": IconSnow,         // slight snow showers
+	"86": IconSnow,         // heavy snow showers
+	"95": IconThunderstorm, // thunderstorm
+	"96": IconThunderstorm, // thunderstorm with slight hail
+	"99": IconThunderstorm, // thunderstorm with heavy hail
+}
+
 // IconDescription maps standardized icon codes to human-readable descriptions
 var IconDescription = map[IconCode]string{
 	IconClearSky:     "Clear Sky",
@@ -237,6 +270,10 @@ func GetStandardIconCode(code, provider string) IconCode {
 		if iconCode, ok := OpenWeatherToIcon[code]; ok {
 			return iconCode
 		}
+	case "openmeteo":
+		if iconCode, ok := OpenMeteoToIcon[code]; ok {
+			return iconCode
+		}
 	}
 	// Return Unknown if no mapping found
 	getLogger().Warn("No standard icon mapping found for provider code",
@@ -0,0 +1,281 @@
+// provider_localstation.go: Local personal weather station integration for BirdNET-Go
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	localStationProviderName = "localstation"
+
+	// Additional unit conversion factors of station protocols
+	KmhPerMs       = 3.6  // km/h in one m/s
+	CentimeterToMm = 10.0 // Convert centimeters to millimeters
+
+	// WeeWX unit systems of the usUnits field
+	weewxUnitsUS       = 1
+	weewxUnitsMetric   = 16
+	weewxUnitsMetricWX = 17
+
+	// stationDateFormat is the dateutc format of the Wunderground, Ecowitt and Ambient protocols
+	stationDateFormat = "2006-01-02 15:04:05"
+)
+
+// StationReading is a weather station reading in metric units. Values the station
+// does not report are NaN.
+type StationReading struct {
+	Time           time.Time // Observation time
+	Temperature    float64   // °C
+	Humidity       float64   // %
+	Pressure       float64   // Sea level pressure in hPa
+	WindSpeed      float64   // m/s
+	WindGust       float64   // m/s
+	WindDirection  float64   // Degrees
+	RainRate       float64   // mm/h
+	SolarRadiation float64   // W/m²
+}
+
+// newStationReading returns a reading with all values missing
+func newStationReading() StationReading {
+	nan := math.NaN()
+	return StationReading{
+		Temperature:    nan,
+		Humidity:       nan,
+		Pressure:       nan,
+		WindSpeed:      nan,
+		WindGust:       nan,
+		WindDirection:  nan,
+		RainRate:       nan,
+		SolarRadiation: nan,
+	}
+}
+
+// ParseStationForm parses a reading uploaded with the Wunderground protocol, which
+// Ecowitt (customized server) and Ambient Weather stations also use. Values are in
+// imperial units as defined by the protocol.
+func ParseStationForm(values url.Values) (StationReading, error) {
+	reading := newStationReading()
+
+	formValue := func(keys ...string) float64 {
+		for _, key := range keys {
+			if raw := strings.TrimSpace(values.Get(key)); raw != "" {
+				if v, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsNaN(v) && !math.IsInf(v, 0) {
+					return v
+				}
+			}
+		}
+		return math.NaN()
+	}
+
+	reading.Temperature = FahrenheitToCelsius(formValue("tempf"))
+	reading.Humidity = formValue("humidity")
+	reading.Pressure = formValue("baromrelin", "baromin", "baromabsin") * InHgToHPa
+	reading.WindSpeed = formValue("windspeedmph") * MphToMs
+	reading.WindGust = formValue("windgustmph") * MphToMs
+	reading.WindDirection = formValue("winddir")
+	reading.RainRate = formValue("rainratein", "hourlyrainin", "rainin") * InchesToMm
+	reading.SolarRadiation = formValue("solarradiation")
+
+	reading.Time = parseStationTime(values.Get("dateutc"))
+
+	if math.IsNaN(reading.Temperature) {
+		return reading, newWeatherError(fmt.Errorf("station reading has no tempf value"),
+			errors.CategoryValidation, "parse_station_form", localStationProviderName)
+	}
+	return reading, nil
+}
+
+// parseStationTime parses the dateutc field, which is "now", a UTC date time or an
+// epoch in milliseconds. The current time is used when it is missing or invalid.
+func parseStationTime(raw string) time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "now") {
+		return time.Now().UTC()
+	}
+	if t, err := time.Parse(stationDateFormat, raw); err == nil {
+		return t
+	}
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Now().UTC()
+}
+
+// ParseWeeWXRecord parses a WeeWX archive or loop record encoded as JSON, as sent by
+// WeeWX RESTful and MQTT extensions. Values are converted from the unit system of the
+// usUnits field.
+func ParseWeeWXRecord(data []byte) (StationReading, error) {
+	reading := newStationReading()
+
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(data, &record); err != nil {
+		return reading, newWeatherError(fmt.Errorf("WeeWX record is not a JSON object: %w", err),
+			errors.CategoryValidation, "parse_weewx_record", localStationProviderName)
+	}
+
+	value := func(key string) float64 {
+		raw, ok := record[key]
+		if !ok {
+			return math.NaN()
+		}
+		// Numbers may be sent as JSON numbers or strings, null is a missing value
+		text := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsInf(v, 0) {
+			return math.NaN()
+		}
+		return v
+	}
+
+	speedToMs, pressureToHPa, rainToMm := 1.0, 1.0, 1.0
+	units := value("usUnits")
+	switch units {
+	case weewxUnitsUS:
+		speedToMs, pressureToHPa, rainToMm = MphToMs, InHgToHPa, InchesToMm
+		reading.Temperature = FahrenheitToCelsius(value("outTemp"))
+	case weewxUnitsMetric:
+		speedToMs, rainToMm = 1/KmhPerMs, CentimeterToMm
+		reading.Temperature = value("outTemp")
+	case weewxUnitsMetricWX:
+		reading.Temperature = value("outTemp")
+	default:
+		return reading, newWeatherError(fmt.Errorf("unsupported WeeWX usUnits value: %v", units),
+			errors.CategoryValidation, "parse_weewx_record", localStationProviderName)
+	}
+
+	reading.Humidity = value("outHumidity")
+	reading.Pressure = value("barometer") * pressureToHPa
+	reading.WindSpeed = value("windSpeed") * speedToMs
+	reading.WindGust = value("windGust") * speedToMs
+	reading.WindDirection = value("windDir")
+	reading.RainRate = value("rainRate") * rainToMm
+	reading.SolarRadiation = value("radiation")
+
+	reading.Time = time.Now().UTC()
+	if epoch := value("dateTime"); !math.IsNaN(epoch) {
+		reading.Time = time.Unix(int64(epoch), 0).UTC()
+	}
+
+	if math.IsNaN(reading.Temperature) {
+		return reading, newWeatherError(fmt.Errorf("WeeWX record has no outTemp value"),
+			errors.CategoryValidation, "parse_weewx_record", localStationProviderName)
+	}
+	return reading, nil
+}
+
+// toWeatherData converts a station reading into weather data. Stations do not report
+// cloud cover, so the icon is inferred from precipitation, humidity and solar radiation
+// like for Wunderground personal weather stations.
+func (r *StationReading) toWeatherData(settings *conf.Settings) *WeatherData {
+	orZero := func(v float64) float64 {
+		if math.IsNaN(v) {
+			return 0
+		}
+		return v
+	}
+
+	// Without solar radiation, clouds are inferred from humidity as at night
+	iconCode := InferWundergroundIcon(r.Temperature, orZero(r.RainRate), orZero(r.Humidity),
+		orZero(r.SolarRadiation), orZero(r.WindGust))
+
+	return &WeatherData{
+		Time: r.Time,
+		Location: Location{
+			Latitude:  settings.BirdNET.Latitude,
+			Longitude: settings.BirdNET.Longitude,
+		},
+		Temperature: Temperature{
+			Current:   r.Temperature,
+			FeelsLike: r.Temperature,
+			Min:       r.Temperature,
+			Max:       r.Temperature,
+		},
+		Wind: Wind{
+			Speed: orZero(r.WindSpeed),
+			Deg:   int(math.Round(orZero(r.WindDirection))),
+			Gust:  orZero(r.WindGust),
+		},
+		Precipitation: Precipitation{
+			Amount: orZero(r.RainRate),
+		},
+		Pressure:    int(math.Round(orZero(r.Pressure))),
+		Humidity:    int(math.Round(orZero(r.Humidity))),
+		Description: IconDescription[iconCode],
+		Icon:        string(iconCode),
+	}
+}
+
+// stationStore holds the last reading pushed by the local weather station
+type stationStore struct {
+	mu       sync.RWMutex
+	reading  StationReading
+	received time.Time // Zero until the first reading
+}
+
+var localStation stationStore
+
+// SubmitStationReading stores a reading pushed by the local weather station. It is
+// saved by the local station provider on its next poll.
+func SubmitStationReading(reading StationReading) {
+	localStation.mu.Lock()
+	defer localStation.mu.Unlock()
+
+	localStation.reading = reading
+	localStation.received = time.Now()
+
+	getLogger().Debug("Received local weather station reading",
+		logger.Time("time", reading.Time),
+		logger.Float64("temp", reading.Temperature))
+}
+
+// latestStationReading returns the last pushed reading and when it was received
+func latestStationReading() (StationReading, time.Time) {
+	localStation.mu.RLock()
+	defer localStation.mu.RUnlock()
+	return localStation.reading, localStation.received
+}
+
+// NewLocalStationProvider creates a provider for a local weather station pushing its
+// readings to BirdNET-Go
+func NewLocalStationProvider() Provider {
+	return &LocalStationProvider{}
+}
+
+// LocalStationProvider implements the Provider interface for readings pushed by a
+// local weather station
+type LocalStationProvider struct {
+	lastReceived time.Time
+}
+
+// FetchWeather returns the last reading pushed by the station
+func (p *LocalStationProvider) FetchWeather(settings *conf.Settings) (*WeatherData, error) {
+	reading, received := latestStationReading()
+	if received.IsZero() {
+		return nil, newWeatherError(fmt.Errorf("no reading received from the local weather station yet"),
+			errors.CategoryState, "fetch_weather_data", localStationProviderName)
+	}
+
+	maxAge := time.Duration(settings.Realtime.Weather.LocalStation.MaxAge) * time.Minute
+	if age := time.Since(received); age > maxAge {
+		return nil, newWeatherError(fmt.Errorf("last local weather station reading is %s old", age.Round(time.Second)),
+			errors.CategoryState, "fetch_weather_data", localStationProviderName)
+	}
+
+	if received.Equal(p.lastReceived) {
+		return nil, ErrWeatherDataNotModified
+	}
+	p.lastReceived = received
+
+	return reading.toWeatherData(settings), nil
+}
@@ -0,0 +1,97 @@
+// provider_localstation_test.go: Tests for parsing readings pushed by local weather stations
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWeeWXRecord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		record string
+		want   StationReading
+	}{
+		{
+			name: "US units",
+			record: `{"usUnits": 1, "dateTime": 1717236000, "outTemp": 68, "outHumidity": 55,
+				"barometer": 29.92, "windSpeed": 10, "windGust": "20", "windDir": 180,
+				"rainRate": 0.1, "radiation": 450}`,
+			want: StationReading{
+				Temperature: 20, Humidity: 55, Pressure: 1013.21, WindSpeed: 4.4704, WindGust: 8.9408,
+				WindDirection: 180, RainRate: 2.54, SolarRadiation: 450,
+			},
+		},
+		{
+			name: "metric units",
+			record: `{"usUnits": 16, "dateTime": 1717236000, "outTemp": 20, "outHumidity": 55,
+				"barometer": 1013.2, "windSpeed": 36, "windGust": 72, "windDir": 90,
+				"rainRate": 0.5, "radiation": null}`,
+			want: StationReading{
+				Temperature: 20, Humidity: 55, Pressure: 1013.2, WindSpeed: 10, WindGust: 20,
+				WindDirection: 90, RainRate: 5, SolarRadiation: math.NaN(),
+			},
+		},
+		{
+			name: "metricwx units",
+			record: `{"usUnits": "17", "dateTime": 1717236000, "outTemp": 20, "outHumidity": 55,
+				"barometer": 1013.2, "windSpeed": 10, "windGust": 20, "windDir": 270,
+				"rainRate": 5, "radiation": 300}`,
+			want: StationReading{
+				Temperature: 20, Humidity: 55, Pressure: 1013.2, WindSpeed: 10, WindGust: 20,
+				WindDirection: 270, RainRate: 5, SolarRadiation: 300,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reading, err := ParseWeeWXRecord([]byte(tt.record))
+			require.NoError(t, err)
+			assert.Equal(t, time.Unix(1717236000, 0).UTC(), reading.Time)
+
+			got := []float64{reading.Temperature, reading.Humidity, reading.Pressure, reading.WindSpeed,
+				reading.WindGust, reading.WindDirection, reading.RainRate, reading.SolarRadiation}
+			want := []float64{tt.want.Temperature, tt.want.Humidity, tt.want.Pressure, tt.want.WindSpeed,
+				tt.want.WindGust, tt.want.WindDirection, tt.want.RainRate, tt.want.SolarRadiation}
+			for i := range want {
+				if math.IsNaN(want[i]) {
+					assert.True(t, math.IsNaN(got[i]), "value %d should be missing", i)
+					continue
+				}
+				assert.InDelta(t, want[i], got[i], 0.01, "value %d", i)
+			}
+		})
+	}
+}
+
+func TestParseWeeWXRecordErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		record string
+	}{
+		{"not JSON", `outTemp=20`},
+		{"unsupported usUnits", `{"usUnits": 2, "outTemp": 20}`},
+		{"missing usUnits", `{"outTemp": 20}`},
+		{"missing outTemp", `{"usUnits": 17, "outHumidity": 55}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseWeeWXRecord([]byte(tt.record))
+			assert.Error(t, err)
+		})
+	}
+}
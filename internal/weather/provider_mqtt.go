@@ -0,0 +1,198 @@
+// provider_mqtt.go: Weather readings subscribed from MQTT topics for BirdNET-Go
+package weather
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/mqtt"
+)
+
+const (
+	mqttProviderName = "mqtt"
+
+	// mqttClientIDSuffix is appended to the node name for the client ID of the subscription
+	mqttClientIDSuffix = "weather"
+
+	// mqttFirstValueTimeout is how long the first fetch waits for retained or newly
+	// published values after subscribing
+	mqttFirstValueTimeout = 5 * time.Second
+	mqttFirstValuePoll    = 100 * time.Millisecond
+)
+
+// mqttTopicValue is the last numeric value received on a topic
+type mqttTopicValue struct {
+	value    float64
+	received time.Time
+}
+
+// NewMQTTProvider creates a provider reading weather values from MQTT topics
+func NewMQTTProvider() Provider {
+	return &MQTTProvider{values: make(map[string]mqttTopicValue)}
+}
+
+// MQTTProvider implements the Provider interface for weather values published on MQTT
+// topics, e.g. by a weather station gateway or a home automation system. The broker of
+// realtime.mqtt is subscribed to when weather is fetched for the first time.
+type MQTTProvider struct {
+	mu           sync.Mutex
+	subscription *mqtt.Subscription
+	topics       []string // Subscribed topics
+	values       map[string]mqttTopicValue
+	lastReceived time.Time
+}
+
+// FetchWeather returns the latest values received on the configured topics
+func (p *MQTTProvider) FetchWeather(settings *conf.Settings) (*WeatherData, error) {
+	weatherSettings := &settings.Realtime.Weather.MQTT
+	if err := p.subscribe(settings); err != nil {
+		return nil, err
+	}
+
+	temperatureTopic := weatherSettings.Topics.Temperature
+	if !p.waitForValue(temperatureTopic, mqttFirstValueTimeout) {
+		return nil, newWeatherError(fmt.Errorf("no temperature received on MQTT topic %s yet", temperatureTopic),
+			errors.CategoryState, "fetch_weather_data", mqttProviderName)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	maxAge := time.Duration(weatherSettings.MaxAge) * time.Minute
+	if age := time.Since(p.values[temperatureTopic].received); age > maxAge {
+		return nil, newWeatherError(fmt.Errorf("last temperature on MQTT topic %s is %s old", temperatureTopic, age.Round(time.Second)),
+			errors.CategoryState, "fetch_weather_data", mqttProviderName)
+	}
+
+	// Stale values are left out like values the topics do not publish
+	var latest time.Time
+	value := func(topic string) float64 {
+		v, ok := p.values[topic]
+		if topic == "" || !ok || time.Since(v.received) > maxAge {
+			return math.NaN()
+		}
+		if v.received.After(latest) {
+			latest = v.received
+		}
+		return v.value
+	}
+
+	topics := &weatherSettings.Topics
+	reading := StationReading{
+		Temperature:    value(topics.Temperature),
+		Humidity:       value(topics.Humidity),
+		Pressure:       value(topics.Pressure),
+		WindSpeed:      value(topics.WindSpeed),
+		WindGust:       value(topics.WindGust),
+		WindDirection:  value(topics.WindDirection),
+		RainRate:       value(topics.RainRate),
+		SolarRadiation: value(topics.SolarRadiation),
+	}
+
+	if latest.Equal(p.lastReceived) {
+		return nil, ErrWeatherDataNotModified
+	}
+	p.lastReceived = latest
+
+	if weatherSettings.Units == "imperial" {
+		reading.Temperature = FahrenheitToCelsius(reading.Temperature)
+		reading.Pressure *= InHgToHPa
+		reading.WindSpeed *= MphToMs
+		reading.WindGust *= MphToMs
+		reading.RainRate *= InchesToMm
+	}
+	reading.Time = latest.UTC()
+
+	return reading.toWeatherData(settings), nil
+}
+
+// Close stops the subscription
+func (p *MQTTProvider) Close() {
+	p.mu.Lock()
+	subscription := p.subscription
+	p.subscription = nil
+	p.topics = nil
+	p.mu.Unlock()
+
+	if subscription != nil {
+		subscription.Stop()
+	}
+}
+
+// subscribe starts the subscription, or restarts it when the configured topics changed
+func (p *MQTTProvider) subscribe(settings *conf.Settings) error {
+	topics := settings.Realtime.Weather.MQTT.Topics.List()
+
+	p.mu.Lock()
+	if p.subscription != nil && slices.Equal(p.topics, topics) {
+		p.mu.Unlock()
+		return nil
+	}
+	previous := p.subscription
+	p.subscription = mqtt.NewSubscription(settings, mqttClientIDSuffix, topics, p.handleMessage)
+	p.topics = topics
+	clear(p.values)
+	subscription := p.subscription
+	p.mu.Unlock()
+
+	if previous != nil {
+		previous.Stop()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+	if err := subscription.Start(ctx); err != nil {
+		p.mu.Lock()
+		p.subscription = nil
+		p.topics = nil
+		p.mu.Unlock()
+		return newWeatherError(err, errors.CategoryMQTTConnection, "subscribe_weather_topics", mqttProviderName)
+	}
+
+	getLogger().Info("Subscribed to MQTT weather topics",
+		logger.String("broker", settings.Realtime.MQTT.Broker),
+		logger.Int("topics", len(topics)))
+	return nil
+}
+
+// handleMessage stores a numeric value received on a topic. Payloads must be plain
+// numbers, other payloads are ignored.
+func (p *MQTTProvider) handleMessage(topic string, payload []byte) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(payload)), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		getLogger().Debug("Ignoring non-numeric MQTT weather value",
+			logger.String("topic", topic))
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[topic] = mqttTopicValue{value: v, received: time.Now()}
+}
+
+// waitForValue waits until a value was received on a topic, which takes a moment
+// after subscribing
+func (p *MQTTProvider) waitForValue(topic string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		p.mu.Lock()
+		_, ok := p.values[topic]
+		p.mu.Unlock()
+		if ok {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(mqttFirstValuePoll)
+	}
+}
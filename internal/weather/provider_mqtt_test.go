@@ -0,0 +1,141 @@
+// provider_mqtt_test.go: Tests for weather readings subscribed from MQTT topics
+package weather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/mqtt"
+)
+
+// newMQTTTestSettings returns settings of the MQTT weather provider with all topics
+func newMQTTTestSettings(units string) *conf.Settings {
+	settings := &conf.Settings{}
+	settings.Main.Name = "test-node"
+	settings.Realtime.MQTT.Broker = "tcp://127.0.0.1:1"
+	weatherSettings := &settings.Realtime.Weather.MQTT
+	weatherSettings.Units = units
+	weatherSettings.MaxAge = 10
+	weatherSettings.Topics = conf.WeatherMQTTTopics{
+		Temperature: "weather/temperature",
+		Humidity:    "weather/humidity",
+		Pressure:    "weather/pressure",
+		WindSpeed:   "weather/wind_speed",
+		WindGust:    "weather/wind_gust",
+		RainRate:    "weather/rain_rate",
+	}
+	return settings
+}
+
+// newSubscribedMQTTProvider returns a provider which is already subscribed to the
+// configured topics, without connecting to a broker
+func newSubscribedMQTTProvider(settings *conf.Settings) *MQTTProvider {
+	p := NewMQTTProvider().(*MQTTProvider)
+	p.topics = settings.Realtime.Weather.MQTT.Topics.List()
+	p.subscription = mqtt.NewSubscription(settings, mqttClientIDSuffix, p.topics, p.handleMessage)
+	return p
+}
+
+func TestMQTTProviderFetchWeather(t *testing.T) {
+	t.Parallel()
+
+	settings := newMQTTTestSettings("metric")
+	p := newSubscribedMQTTProvider(settings)
+
+	p.handleMessage("weather/temperature", []byte(" 21.5\n"))
+	p.handleMessage("weather/humidity", []byte("60"))
+	p.handleMessage("weather/pressure", []byte("not a number"))
+	p.handleMessage("weather/wind_speed", []byte("NaN"))
+
+	data, err := p.FetchWeather(settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 21.5, data.Temperature.Current, 0.001)
+	assert.Equal(t, 60, data.Humidity)
+	assert.Zero(t, data.Pressure, "non-numeric payloads are ignored")
+	assert.Zero(t, data.Wind.Speed, "NaN payloads are ignored")
+
+	_, err = p.FetchWeather(settings)
+	require.ErrorIs(t, err, ErrWeatherDataNotModified, "no new values since the last fetch")
+
+	// Let the receive time advance so the new value is seen as newer
+	time.Sleep(time.Millisecond)
+	p.handleMessage("weather/temperature", []byte("22"))
+	data, err = p.FetchWeather(settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 22.0, data.Temperature.Current, 0.001)
+}
+
+func TestMQTTProviderStaleValues(t *testing.T) {
+	t.Parallel()
+
+	settings := newMQTTTestSettings("metric")
+	maxAge := time.Duration(settings.Realtime.Weather.MQTT.MaxAge) * time.Minute
+	p := newSubscribedMQTTProvider(settings)
+
+	stale := time.Now().Add(-maxAge - time.Minute)
+	p.values["weather/temperature"] = mqttTopicValue{value: 18, received: time.Now()}
+	p.values["weather/humidity"] = mqttTopicValue{value: 75, received: stale}
+
+	data, err := p.FetchWeather(settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 18.0, data.Temperature.Current, 0.001)
+	assert.Zero(t, data.Humidity, "stale values are left out")
+
+	p.values["weather/temperature"] = mqttTopicValue{value: 19, received: stale}
+	_, err = p.FetchWeather(settings)
+	require.Error(t, err, "a stale temperature is not reported")
+	assert.NotErrorIs(t, err, ErrWeatherDataNotModified)
+}
+
+func TestMQTTProviderImperialUnits(t *testing.T) {
+	t.Parallel()
+
+	settings := newMQTTTestSettings("imperial")
+	p := newSubscribedMQTTProvider(settings)
+
+	p.handleMessage("weather/temperature", []byte("68"))
+	p.handleMessage("weather/pressure", []byte("29.92"))
+	p.handleMessage("weather/wind_speed", []byte("10"))
+	p.handleMessage("weather/wind_gust", []byte("20"))
+	p.handleMessage("weather/rain_rate", []byte("0.1"))
+
+	data, err := p.FetchWeather(settings)
+	require.NoError(t, err)
+	assert.InDelta(t, 20.0, data.Temperature.Current, 0.001, "°F to °C")
+	assert.Equal(t, 1013, data.Pressure, "inHg to hPa")
+	assert.InDelta(t, 4.4704, data.Wind.Speed, 0.001, "mph to m/s")
+	assert.InDelta(t, 8.9408, data.Wind.Gust, 0.001, "mph to m/s")
+	assert.InDelta(t, 2.54, data.Precipitation.Amount, 0.001, "in/h to mm/h")
+}
+
+func TestMQTTProviderResubscribeOnTopicChange(t *testing.T) {
+	t.Parallel()
+
+	settings := newMQTTTestSettings("metric")
+	p := newSubscribedMQTTProvider(settings)
+	// Closing stops the started subscription, which waits for the disconnect
+	// timeout as nothing listens on the broker address
+	defer p.Close()
+
+	p.handleMessage("weather/temperature", []byte("12"))
+	subscription := p.subscription
+
+	require.NoError(t, p.subscribe(settings))
+	assert.Same(t, subscription, p.subscription, "unchanged topics keep the subscription")
+	assert.Contains(t, p.values, "weather/temperature")
+
+	changed := newMQTTTestSettings("metric")
+	changed.Realtime.Weather.MQTT.Topics.Temperature = "garden/temperature"
+	require.NoError(t, p.subscribe(changed))
+	assert.NotSame(t, subscription, p.subscription, "changed topics replace the subscription")
+	assert.Contains(t, p.topics, "garden/temperature")
+	assert.NotContains(t, p.topics, "weather/temperature")
+	assert.Empty(t, p.values, "values of the previous topics are dropped")
+
+	p.Close()
+	assert.Nil(t, p.subscription)
+	assert.Nil(t, p.topics)
+}
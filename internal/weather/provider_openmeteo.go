@@ -0,0 +1,310 @@
+// provider_openmeteo.go: Open-Meteo integration for BirdNET-Go
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	openMeteoProviderName      = "openmeteo"
+	openMeteoForecastURL       = "https://api.open-meteo.com/v1/forecast"
+	openMeteoArchiveURL        = "https://archive-api.open-meteo.com/v1/archive"
+	openMeteoMaxResponseSize   = 4 * 1024 * 1024 // A month of hourly values is about 100 KB
+	openMeteoArchiveDateFormat = "2006-01-02"
+	openMeteoCurrentVariables  = "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation,weather_code,cloud_cover,pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m,visibility"
+	openMeteoHistoricVariables = "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation,weather_code,cloud_cover,pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m"
+)
+
+// openMeteoValues are the weather values of one point in time. Values are nil when
+// the model has no data, e.g. for the last days in the archive.
+type openMeteoValues struct {
+	Time          int64    `json:"time"`
+	Temperature   *float64 `json:"temperature_2m"`
+	Humidity      *float64 `json:"relative_humidity_2m"`
+	FeelsLike     *float64 `json:"apparent_temperature"`
+	Precipitation *float64 `json:"precipitation"`
+	WeatherCode   *float64 `json:"weather_code"`
+	CloudCover    *float64 `json:"cloud_cover"`
+	Pressure      *float64 `json:"pressure_msl"`
+	WindSpeed     *float64 `json:"wind_speed_10m"`
+	WindDirection *float64 `json:"wind_direction_10m"`
+	WindGust      *float64 `json:"wind_gusts_10m"`
+	Visibility    *float64 `json:"visibility"`
+}
+
+// openMeteoHourly holds the hourly values of a response as one array per variable
+type openMeteoHourly struct {
+	Time          []int64    `json:"time"`
+	Temperature   []*float64 `json:"temperature_2m"`
+	Humidity      []*float64 `json:"relative_humidity_2m"`
+	FeelsLike     []*float64 `json:"apparent_temperature"`
+	Precipitation []*float64 `json:"precipitation"`
+	WeatherCode   []*float64 `json:"weather_code"`
+	CloudCover    []*float64 `json:"cloud_cover"`
+	Pressure      []*float64 `json:"pressure_msl"`
+	WindSpeed     []*float64 `json:"wind_speed_10m"`
+	WindDirection []*float64 `json:"wind_direction_10m"`
+	WindGust      []*float64 `json:"wind_gusts_10m"`
+}
+
+// OpenMeteoResponse represents the structure of the Open-Meteo forecast and archive API responses
+type OpenMeteoResponse struct {
+	Latitude  float64          `json:"latitude"`
+	Longitude float64          `json:"longitude"`
+	Current   *openMeteoValues `json:"current"`
+	Hourly    *openMeteoHourly `json:"hourly"`
+	Error     bool             `json:"error"`
+	Reason    string           `json:"reason"`
+}
+
+// at returns the values of the hour at index i
+func (h *openMeteoHourly) at(i int) openMeteoValues {
+	value := func(values []*float64) *float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return nil
+	}
+	return openMeteoValues{
+		Time:          h.Time[i],
+		Temperature:   value(h.Temperature),
+		Humidity:      value(h.Humidity),
+		FeelsLike:     value(h.FeelsLike),
+		Precipitation: value(h.Precipitation),
+		WeatherCode:   value(h.WeatherCode),
+		CloudCover:    value(h.CloudCover),
+		Pressure:      value(h.Pressure),
+		WindSpeed:     value(h.WindSpeed),
+		WindDirection: value(h.WindDirection),
+		WindGust:      value(h.WindGust),
+	}
+}
+
+// NewOpenMeteoProvider creates a new Open-Meteo provider with shared HTTP client
+func NewOpenMeteoProvider(client *http.Client) Provider {
+	if client == nil {
+		client = &http.Client{
+			Timeout: DefaultHTTPClientTimeout,
+		}
+	}
+	return &OpenMeteoProvider{
+		httpClient: client,
+	}
+}
+
+// OpenMeteoProvider implements the Provider and HistoricalProvider interfaces for Open-Meteo
+type OpenMeteoProvider struct {
+	httpClient *http.Client
+	lastTime   int64 // Time of the last current weather returned
+}
+
+// FetchWeather implements the Provider interface for OpenMeteoProvider
+func (p *OpenMeteoProvider) FetchWeather(settings *conf.Settings) (*WeatherData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), MaxRetries*(RequestTimeout+RetryDelay))
+	defer cancel()
+
+	query := openMeteoQuery(settings)
+	query.Set("current", openMeteoCurrentVariables)
+
+	endpoint := settings.Realtime.Weather.OpenMeteo.Endpoint
+	if endpoint == "" {
+		endpoint = openMeteoForecastURL
+	}
+
+	response, err := p.request(ctx, endpoint, query)
+	if err != nil {
+		return nil, err
+	}
+	if response.Current == nil || response.Current.Temperature == nil {
+		return nil, newWeatherError(fmt.Errorf("no current weather returned from API"),
+			errors.CategoryValidation, "validate_weather_response", openMeteoProviderName)
+	}
+
+	// Current weather is updated every 15 minutes
+	if response.Current.Time == p.lastTime {
+		return nil, ErrWeatherDataNotModified
+	}
+	p.lastTime = response.Current.Time
+
+	return mapOpenMeteoValues(response.Current, response.Latitude, response.Longitude), nil
+}
+
+// FetchHistoricalWeather implements the HistoricalProvider interface. It returns the
+// hourly weather of the UTC dates from start to end, hours without data are left out.
+func (p *OpenMeteoProvider) FetchHistoricalWeather(ctx context.Context, settings *conf.Settings, start, end time.Time) ([]WeatherData, error) {
+	query := openMeteoQuery(settings)
+	query.Set("hourly", openMeteoHistoricVariables)
+	query.Set("start_date", start.UTC().Format(openMeteoArchiveDateFormat))
+	query.Set("end_date", end.UTC().Format(openMeteoArchiveDateFormat))
+
+	endpoint := settings.Realtime.Weather.OpenMeteo.ArchiveEndpoint
+	if endpoint == "" {
+		endpoint = openMeteoArchiveURL
+	}
+
+	response, err := p.request(ctx, endpoint, query)
+	if err != nil {
+		return nil, err
+	}
+	if response.Hourly == nil {
+		return nil, newWeatherError(fmt.Errorf("no hourly weather returned from archive API"),
+			errors.CategoryValidation, "validate_weather_response", openMeteoProviderName)
+	}
+
+	data := make([]WeatherData, 0, len(response.Hourly.Time))
+	for i := range response.Hourly.Time {
+		values := response.Hourly.at(i)
+		if values.Temperature == nil {
+			continue
+		}
+		data = append(data, *mapOpenMeteoValues(&values, response.Latitude, response.Longitude))
+	}
+	return data, nil
+}
+
+// openMeteoQuery returns the query parameters common to all requests. Times are
+// requested as Unix timestamps in UTC and wind speed in m/s like the other providers.
+func openMeteoQuery(settings *conf.Settings) url.Values {
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(settings.BirdNET.Latitude, 'f', 3, 64))
+	query.Set("longitude", strconv.FormatFloat(settings.BirdNET.Longitude, 'f', 3, 64))
+	query.Set("wind_speed_unit", "ms")
+	query.Set("timeformat", "unixtime")
+	query.Set("timezone", "GMT")
+	return query
+}
+
+// request executes an API request with retries on network and server errors
+func (p *OpenMeteoProvider) request(ctx context.Context, endpoint string, query url.Values) (*OpenMeteoResponse, error) {
+	apiURL := endpoint + "?" + query.Encode()
+	log := getLogger().With(logger.String("provider", openMeteoProviderName))
+	log.Debug("Fetching weather data", logger.String("url", apiURL))
+
+	var lastErr error
+	for attempt := range MaxRetries {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, newWeatherError(ctx.Err(), errors.CategoryTimeout, "weather_api_request", openMeteoProviderName)
+			case <-time.After(RetryDelay):
+			}
+		}
+
+		response, retry, err := p.doRequest(ctx, apiURL)
+		if err == nil {
+			return response, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+		log.Warn("Open-Meteo request failed",
+			logger.Int("attempt", attempt+1),
+			logger.Int("max_attempts", MaxRetries),
+			logger.Error(err))
+	}
+
+	return nil, newWeatherErrorWithRetries(lastErr, errors.CategoryNetwork, "weather_api_request", openMeteoProviderName)
+}
+
+// doRequest executes one API request and reports whether a failed request may be retried
+func (p *OpenMeteoProvider) doRequest(ctx context.Context, apiURL string) (response *OpenMeteoResponse, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, http.NoBody)
+	if err != nil {
+		return nil, false, newWeatherError(err, errors.CategoryNetwork, "create_http_request", openMeteoProviderName)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, newWeatherError(err, errors.CategoryNetwork, "weather_api_request", openMeteoProviderName)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			getLogger().Debug("Failed to close response body", logger.Error(err))
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, openMeteoMaxResponseSize))
+	if err != nil {
+		return nil, true, newWeatherError(err, errors.CategoryNetwork, "read_response_body", openMeteoProviderName)
+	}
+
+	var parsed OpenMeteoResponse
+	parseErr := json.Unmarshal(body, &parsed)
+
+	if resp.StatusCode != http.StatusOK {
+		reason := fmt.Sprintf("received non-200 response (%d)", resp.StatusCode)
+		if parseErr == nil && parsed.Reason != "" {
+			reason = fmt.Sprintf("%s: %s", reason, parsed.Reason)
+		}
+		// Client errors such as invalid coordinates or dates do not go away on retry
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return nil, retry, newWeatherError(fmt.Errorf("%s", reason), errors.CategoryNetwork, "weather_api_response", openMeteoProviderName)
+	}
+	if parseErr != nil {
+		return nil, false, newWeatherError(parseErr, errors.CategoryValidation, "unmarshal_weather_data", openMeteoProviderName)
+	}
+	return &parsed, false, nil
+}
+
+// mapOpenMeteoValues converts the values of one point in time to WeatherData
+func mapOpenMeteoValues(values *openMeteoValues, latitude, longitude float64) *WeatherData {
+	value := func(v *float64) float64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+
+	temp := value(values.Temperature)
+	feelsLike := temp
+	if values.FeelsLike != nil {
+		feelsLike = *values.FeelsLike
+	}
+
+	iconCode := IconUnknown
+	if values.WeatherCode != nil {
+		iconCode = GetStandardIconCode(strconv.Itoa(int(*values.WeatherCode)), openMeteoProviderName)
+	}
+
+	return &WeatherData{
+		Time: time.Unix(values.Time, 0).UTC(),
+		Location: Location{
+			Latitude:  latitude,
+			Longitude: longitude,
+		},
+		Temperature: Temperature{
+			Current:   temp,
+			FeelsLike: feelsLike,
+			Min:       temp,
+			Max:       temp,
+		},
+		Wind: Wind{
+			Speed: value(values.WindSpeed),
+			Deg:   int(math.Round(value(values.WindDirection))),
+			Gust:  value(values.WindGust),
+		},
+		Precipitation: Precipitation{
+			Amount: value(values.Precipitation),
+		},
+		Clouds:      int(math.Round(value(values.CloudCover))),
+		Visibility:  int(math.Round(value(values.Visibility))),
+		Pressure:    int(math.Round(value(values.Pressure))),
+		Humidity:    int(math.Round(value(values.Humidity))),
+		Description: IconDescription[iconCode],
+		Icon:        string(iconCode),
+	}
+}
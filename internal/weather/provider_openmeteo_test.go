@@ -0,0 +1,174 @@
+// provider_openmeteo_test.go: Tests for the Open-Meteo historical weather and backfill
+package weather
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// archiveServer serves hourly weather for the requested UTC dates and records the
+// requests. Temperature is null for the hours in nullHours.
+type archiveServer struct {
+	mu        sync.Mutex
+	requests  []map[string]string
+	nullHours map[int64]bool
+}
+
+func (a *archiveServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	a.mu.Lock()
+	a.requests = append(a.requests, map[string]string{
+		"start_date": query.Get("start_date"),
+		"end_date":   query.Get("end_date"),
+		"hourly":     query.Get("hourly"),
+		"timezone":   query.Get("timezone"),
+		"timeformat": query.Get("timeformat"),
+	})
+	a.mu.Unlock()
+
+	start, errStart := time.Parse(time.DateOnly, query.Get("start_date"))
+	end, errEnd := time.Parse(time.DateOnly, query.Get("end_date"))
+	if errStart != nil || errEnd != nil || end.Before(start) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":true,"reason":"invalid date range"}`))
+		return
+	}
+
+	hourly := map[string]any{}
+	var times []int64
+	var temperatures, windSpeeds []*float64
+	for hour := start; hour.Before(end.AddDate(0, 0, 1)); hour = hour.Add(time.Hour) {
+		times = append(times, hour.Unix())
+		temperature, windSpeed := 12.5, 3.0
+		if a.nullHours[hour.Unix()] {
+			temperatures = append(temperatures, nil)
+		} else {
+			temperatures = append(temperatures, &temperature)
+		}
+		windSpeeds = append(windSpeeds, &windSpeed)
+	}
+	hourly["time"] = times
+	hourly["temperature_2m"] = temperatures
+	hourly["wind_speed_10m"] = windSpeeds
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"latitude":  60.17,
+		"longitude": 24.94,
+		"hourly":    hourly,
+	})
+}
+
+// newArchiveTestSettings returns settings using the archive endpoint of server
+func newArchiveTestSettings(server *httptest.Server) *conf.Settings {
+	settings := &conf.Settings{}
+	settings.BirdNET.Latitude = 60.17
+	settings.BirdNET.Longitude = 24.94
+	settings.Realtime.Weather.OpenMeteo.ArchiveEndpoint = server.URL
+	settings.Realtime.Weather.OpenMeteo.Backfill = true
+	return settings
+}
+
+func TestOpenMeteoFetchHistoricalWeather(t *testing.T) {
+	t.Parallel()
+
+	nullHour := time.Date(2024, 5, 1, 5, 0, 0, 0, time.UTC)
+	archive := &archiveServer{nullHours: map[int64]bool{nullHour.Unix(): true}}
+	server := httptest.NewServer(archive)
+	defer server.Close()
+
+	provider := NewOpenMeteoProvider(server.Client()).(*OpenMeteoProvider)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 2, 23, 59, 59, 0, time.UTC)
+
+	data, err := provider.FetchHistoricalWeather(t.Context(), newArchiveTestSettings(server), start, end)
+	require.NoError(t, err)
+	assert.Len(t, data, 47, "hours without temperature are left out")
+	assert.Equal(t, start, data[0].Time)
+	assert.InDelta(t, 12.5, data[0].Temperature.Current, 0.001)
+	assert.InDelta(t, 3.0, data[0].Wind.Speed, 0.001)
+	assert.InDelta(t, 60.17, data[0].Location.Latitude, 0.001)
+	for i := range data {
+		assert.NotEqual(t, nullHour, data[i].Time)
+	}
+
+	require.Len(t, archive.requests, 1)
+	request := archive.requests[0]
+	assert.Equal(t, "2024-05-01", request["start_date"])
+	assert.Equal(t, "2024-05-02", request["end_date"])
+	assert.Equal(t, openMeteoHistoricVariables, request["hourly"])
+	assert.Equal(t, "GMT", request["timezone"])
+	assert.Equal(t, "unixtime", request["timeformat"])
+}
+
+func TestOpenMeteoFetchHistoricalWeatherClientError(t *testing.T) {
+	t.Parallel()
+
+	archive := &archiveServer{}
+	server := httptest.NewServer(archive)
+	defer server.Close()
+
+	provider := NewOpenMeteoProvider(server.Client()).(*OpenMeteoProvider)
+	start := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := provider.FetchHistoricalWeather(t.Context(), newArchiveTestSettings(server), start, end)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid date range", "the reason of the API is reported")
+	assert.Len(t, archive.requests, 1, "client errors are not retried")
+}
+
+func TestBackfill(t *testing.T) {
+	setLocalTimeZone(t, "UTC")
+
+	nullHour := time.Date(2024, 5, 1, 5, 0, 0, 0, time.UTC)
+	coveredHour := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	archive := &archiveServer{nullHours: map[int64]bool{nullHour.Unix(): true}}
+	server := httptest.NewServer(archive)
+	defer server.Close()
+
+	store := &fakeBackfillStore{
+		dates: []string{"2024-05-01", "2024-05-02", "2024-05-10", time.Now().Format(time.DateOnly)},
+		times: []time.Time{coveredHour},
+	}
+	provider := NewOpenMeteoProvider(server.Client())
+	s := &Service{provider: provider, db: store, settings: newArchiveTestSettings(server)}
+
+	historical, ok := s.historicalProvider()
+	require.True(t, ok, "Open-Meteo supports backfilling")
+
+	saved, err := s.Backfill(t.Context(), historical)
+	require.NoError(t, err)
+	// Two full days and one day less the null and the covered hour
+	assert.Equal(t, 70, saved)
+	assert.Len(t, store.saved, saved)
+	for i := range store.saved {
+		assert.NotEqual(t, coveredHour, store.saved[i].Time, "covered hours are not saved again")
+		assert.NotEqual(t, nullHour, store.saved[i].Time)
+	}
+
+	require.Len(t, archive.requests, 2, "consecutive dates are fetched in one request")
+	assert.Equal(t, "2024-05-01", archive.requests[0]["start_date"])
+	assert.Equal(t, "2024-05-02", archive.requests[0]["end_date"])
+	assert.Equal(t, "2024-05-10", archive.requests[1]["start_date"])
+	assert.Equal(t, "2024-05-10", archive.requests[1]["end_date"])
+
+	// Once filled in, nothing is missing
+	store.times = make([]time.Time, 0, len(store.saved)+1)
+	for i := range store.saved {
+		store.times = append(store.times, store.saved[i].Time)
+	}
+	store.times = append(store.times, coveredHour, nullHour)
+	saved, err = s.Backfill(t.Context(), historical)
+	require.NoError(t, err)
+	assert.Zero(t, saved)
+	assert.Len(t, archive.requests, 2)
+}
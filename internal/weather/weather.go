@@ -82,6 +82,12 @@ func NewService(settings *conf.Settings, db datastore.Interface, weatherMetrics
 		provider = NewOpenWeatherProvider()
 	case "wunderground":
 		provider = NewWundergroundProvider(nil)
+	case "openmeteo":
+		provider = NewOpenMeteoProvider(nil)
+	case "localstation":
+		provider = NewLocalStationProvider()
+	case "mqtt":
+		provider = NewMQTTProvider()
 	default:
 		return nil, errors.New(fmt.Errorf("invalid weather provider: %s", settings.Realtime.Weather.Provider)).
 			Component("weather").
@@ -108,6 +114,29 @@ func (s *Service) SaveWeatherData(data *WeatherData) error {
 		}
 	}()
 
+	if err := s.saveWeatherRecord(data); err != nil {
+		return err
+	}
+
+	if s.metrics != nil {
+		// Update current weather gauges
+		s.metrics.UpdateWeatherGauges(
+			data.Temperature.Current,
+			float64(data.Humidity),
+			float64(data.Pressure),
+			data.Wind.Speed,
+			float64(data.Visibility),
+		)
+	}
+
+	getLogger().Debug("Successfully saved weather data to database",
+		logger.Time("time", data.Time),
+		logger.String("city", data.Location.City))
+	return nil
+}
+
+// saveWeatherRecord saves the daily events and hourly weather of weather data
+func (s *Service) saveWeatherRecord(data *WeatherData) error {
 	// Create daily events data
 	dailyEvents := &datastore.DailyEvents{
 		Date:     data.Time.Format("2006-01-02"),
@@ -174,19 +203,7 @@ func (s *Service) SaveWeatherData(data *WeatherData) error {
 	}
 	if s.metrics != nil {
 		s.metrics.RecordWeatherDbOperation("save_hourly_weather", "success")
-		// Update current weather gauges
-		s.metrics.UpdateWeatherGauges(
-			data.Temperature.Current,
-			float64(data.Humidity),
-			float64(data.Pressure),
-			data.Wind.Speed,
-			float64(data.Visibility),
-		)
 	}
-
-	getLogger().Debug("Successfully saved weather data to database",
-		logger.Time("time", data.Time),
-		logger.String("city", data.Location.City))
 	return nil
 }
 
@@ -227,6 +244,11 @@ func (s *Service) StartPolling(stopChan <-chan struct{}) {
 		getLogger().Warn("Initial weather fetch failed", logger.Error(err))
 	}
 
+	// Fill in missing weather of past detections in the background
+	if historical, ok := s.historicalProvider(); ok {
+		go s.runBackfill(historical, stopChan)
+	}
+
 	for {
 		select {
 		case <-ticker.C:
@@ -237,6 +259,10 @@ func (s *Service) StartPolling(stopChan <-chan struct{}) {
 			}
 		case <-stopChan:
 			getLogger().Info("Stopping weather polling service")
+			// Release connections of providers which keep them open between polls
+			if closer, ok := s.provider.(interface{ Close() }); ok {
+				closer.Close()
+			}
 			return
 		}
 	}